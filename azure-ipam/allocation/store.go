@@ -0,0 +1,109 @@
+package allocation
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	Dir = "/var/run/azure-ipam/allocations"
+
+	fileExt = ".json"
+)
+
+// Attachment is a container interface which azure-ipam allocated IPs to.
+type Attachment struct {
+	ContainerID string    `json:"containerID"`
+	IfName      string    `json:"ifName"`
+	Args        string    `json:"args"` // the CNI_ARGS of the ADD, which identify the pod to CNS
+	IPs         []string  `json:"ips"`
+	Allocated   time.Time `json:"allocated"`
+}
+
+// Store records the attachments azure-ipam allocated IPs to, one file per attachment under a
+// directory per network, so that GC only releases the IPs of its own network. Every file is
+// written by a single ADD or DEL of its attachment, which the runtime serializes, so the store
+// needs no lock.
+type Store struct {
+	dir string
+}
+
+// NewStore creates a store which keeps the attachments under dir, Dir by default.
+func NewStore(dir string) *Store {
+	if dir == "" {
+		dir = Dir
+	}
+	return &Store{dir: dir}
+}
+
+// Add records the attachment in the network, replacing any previous record of it.
+func (s *Store) Add(network string, a *Attachment) error {
+	networkDir := filepath.Join(s.dir, network)
+	//nolint:gomnd // 0o755 - permission to create directory in octal
+	if err := os.MkdirAll(networkDir, 0o755); err != nil {
+		return errors.Wrapf(err, "failed to create allocation directory %s", networkDir)
+	}
+	b, err := json.Marshal(a)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal attachment")
+	}
+
+	// write to a temp file first so that a crash never leaves a partial record behind
+	file := s.file(network, a.ContainerID, a.IfName)
+	tmp := file + ".tmp"
+	//nolint:gomnd // 0o644 - permission of the record file in octal
+	if err := os.WriteFile(tmp, b, 0o644); err != nil {
+		return errors.Wrapf(err, "failed to write allocation %s", tmp)
+	}
+	if err := os.Rename(tmp, file); err != nil {
+		return errors.Wrapf(err, "failed to rename allocation %s", tmp)
+	}
+	return nil
+}
+
+// Remove deletes the record of the attachment in the network, if any.
+func (s *Store) Remove(network, containerID, ifName string) error {
+	file := s.file(network, containerID, ifName)
+	if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "failed to remove allocation %s", file)
+	}
+	return nil
+}
+
+// List returns the attachments recorded in the network.
+func (s *Store) List(network string) ([]*Attachment, error) {
+	networkDir := filepath.Join(s.dir, network)
+	entries, err := os.ReadDir(networkDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to read allocation directory %s", networkDir)
+	}
+
+	attachments := make([]*Attachment, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), fileExt) {
+			continue
+		}
+		b, err := os.ReadFile(filepath.Join(networkDir, entry.Name()))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read allocation %s", entry.Name())
+		}
+		a := &Attachment{}
+		if err := json.Unmarshal(b, a); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal allocation %s", entry.Name())
+		}
+		attachments = append(attachments, a)
+	}
+	return attachments, nil
+}
+
+func (s *Store) file(network, containerID, ifName string) string {
+	return filepath.Join(s.dir, network, containerID+"_"+ifName+fileExt)
+}
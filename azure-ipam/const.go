@@ -8,6 +8,10 @@ const (
 	pluginName    = "azure-ipam"
 	cnsBaseURL    = "" // fallback to default http://localhost:10090
 	cnsReqTimeout = 15 * time.Second
+	// cnsGRPCAddressEnv names the environment variable which, when set, switches the CNS client to the CNS gRPC API at that address
+	cnsGRPCAddressEnv = "AZURE_IPAM_CNS_GRPC_ADDRESS"
+	// gcGracePeriod is how long GC leaves the IPs of a new attachment alone
+	gcGracePeriod = 5 * time.Minute
 )
 
 // plugin specific error codes
@@ -6,7 +6,10 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"net/netip"
+	"time"
 
+	"github.com/Azure/azure-container-networking/azure-ipam/allocation"
 	"github.com/Azure/azure-container-networking/azure-ipam/internal/buildinfo"
 	"github.com/Azure/azure-container-networking/azure-ipam/ipconfig"
 	"github.com/Azure/azure-container-networking/cns"
@@ -31,7 +34,9 @@ type IPAMPlugin struct {
 	Options   map[string]interface{}
 	logger    *zap.Logger
 	cnsClient cnsClient
-	out       io.Writer // indicate the output channel for the plugin
+	// allocations records the attachments the plugin allocated IPs to, which are the only ones GC may release
+	allocations allocationStore
+	out         io.Writer // indicate the output channel for the plugin
 }
 
 type cnsClient interface {
@@ -39,16 +44,24 @@ type cnsClient interface {
 	RequestIPs(context.Context, cns.IPConfigsRequest) (*cns.IPConfigsResponse, error)
 	ReleaseIPs(context.Context, cns.IPConfigsRequest) error
 	ReleaseIPAddress(context.Context, cns.IPConfigRequest) error
+	RenewIPLeases(context.Context, cns.IPConfigsRequest) error
 }
 
-// NewPlugin constructs a new IPAM plugin instance with given logger and CNS client
-func NewPlugin(logger *zap.Logger, c cnsClient, out io.Writer) (*IPAMPlugin, error) {
+type allocationStore interface {
+	Add(network string, a *allocation.Attachment) error
+	Remove(network, containerID, ifName string) error
+	List(network string) ([]*allocation.Attachment, error)
+}
+
+// NewPlugin constructs a new IPAM plugin instance with given logger, CNS client and allocation store
+func NewPlugin(logger *zap.Logger, c cnsClient, allocations allocationStore, out io.Writer) (*IPAMPlugin, error) {
 	plugin := &IPAMPlugin{
-		Name:      pluginName,
-		Version:   buildinfo.Version,
-		logger:    logger,
-		out:       out,
-		cnsClient: c,
+		Name:        pluginName,
+		Version:     buildinfo.Version,
+		logger:      logger,
+		out:         out,
+		cnsClient:   c,
+		allocations: allocations,
 	}
 	return plugin, nil
 }
@@ -120,6 +133,8 @@ func (p *IPAMPlugin) CmdAdd(args *cniSkel.CmdArgs) error {
 		p.logger.Error("Failed to interpret CNS IPConfigResponse", zap.Error(err), zap.Any("response", resp))
 		return cniTypes.NewError(ErrProcessIPConfigResponse, err.Error(), "failed to interpret CNS IPConfigResponse")
 	}
+	p.recordAllocation(nwCfg.Name, args, *podIPNet)
+
 	cniResult := &types100.Result{}
 	cniResult.IPs = make([]*types100.IPConfig, len(*podIPNet))
 	for i, ipNet := range *podIPNet {
@@ -217,17 +232,125 @@ func (p *IPAMPlugin) CmdDel(args *cniSkel.CmdArgs) error {
 		}
 	}
 
+	// the IPs are released, or will be by the watcher, so GC must not release them again
+	if nwCfg, err := parseNetConf(args.StdinData); err != nil {
+		p.logger.Error("Failed to parse CNI network config from stdin, keeping the allocation record", zap.Error(err))
+	} else if err := p.allocations.Remove(nwCfg.Name, args.ContainerID, args.IfName); err != nil {
+		p.logger.Error("Failed to remove the allocation record", zap.Error(err))
+	}
+
 	p.logger.Info("DEL success")
 
 	return nil
 }
 
-// CmdCheck handles CNI check command - not implemented
+// CmdCheck handles CNI check commands by renewing the leases on the IPs CNS assigned to the pod.
 func (p *IPAMPlugin) CmdCheck(args *cniSkel.CmdArgs) error {
-	p.logger.Info("CHECK called")
+	p.logger.Info("CHECK called", zap.Any("args", args))
+
+	// Create ip config request from args
+	req, err := ipconfig.CreateIPConfigsReq(args)
+	if err != nil {
+		p.logger.Error("Failed to create CNS IP configs request", zap.Error(err))
+		return cniTypes.NewError(ErrCreateIPConfigsRequest, err.Error(), "failed to create CNS IP configs request")
+	}
+	p.logger.Debug("Created CNS IP config request", zap.Any("request", req))
+
+	p.logger.Debug("Making request to CNS")
+	// cnsClient enforces it own timeout
+	if err := p.cnsClient.RenewIPLeases(context.TODO(), req); err != nil {
+		// older CNS versions do not lease IPs, so there is nothing to renew
+		if cnscli.IsUnsupportedAPI(err) {
+			p.logger.Info("CNS does not support IP leases, skipping renewal")
+			return nil
+		}
+		p.logger.Error("Failed to renew IP leases in CNS", zap.Error(err), zap.Any("request", req))
+		return cniTypes.NewError(cniTypes.ErrTryAgainLater, err.Error(), "failed to renew IP leases in CNS")
+	}
+
+	p.logger.Info("CHECK success")
+
 	return nil
 }
 
+// CmdGC handles CNI garbage collection commands. Any attachment which the plugin allocated IPs to in
+// this network, which is not in the runtime supplied list of valid attachments and is older than
+// gcGracePeriod, has its IPs released back to the pool.
+func (p *IPAMPlugin) CmdGC(args *cniSkel.CmdArgs) error {
+	p.logger.Info("GC called", zap.Any("args", args))
+
+	nwCfg, err := parseNetConf(args.StdinData)
+	if err != nil {
+		p.logger.Error("Failed to parse CNI network config from stdin", zap.Error(err), zap.Any("argStdinData", args.StdinData))
+		return cniTypes.NewError(cniTypes.ErrDecodingFailure, err.Error(), "failed to parse CNI network config from stdin")
+	}
+
+	valid := make(map[cniTypes.GCAttachment]struct{}, len(nwCfg.ValidAttachments))
+	validContainerIDs := make(map[string]struct{}, len(nwCfg.ValidAttachments))
+	for _, attachment := range nwCfg.ValidAttachments {
+		valid[attachment] = struct{}{}
+		validContainerIDs[attachment.ContainerID] = struct{}{}
+	}
+
+	attachments, err := p.allocations.List(nwCfg.Name)
+	if err != nil {
+		p.logger.Error("Failed to list the allocation records", zap.Error(err), zap.String("network", nwCfg.Name))
+		return cniTypes.NewError(cniTypes.ErrIOFailure, err.Error(), "failed to list the allocation records")
+	}
+
+	released := 0
+	for _, a := range attachments {
+		if _, ok := valid[cniTypes.GCAttachment{ContainerID: a.ContainerID, IfName: a.IfName}]; ok {
+			continue
+		}
+		// an ADD may still be in flight when the runtime builds its list of valid attachments
+		if time.Since(a.Allocated) < gcGracePeriod {
+			continue
+		}
+		// CNS releases the IPs of the whole container, which still owns another valid attachment
+		if _, ok := validContainerIDs[a.ContainerID]; !ok {
+			req, err := ipconfig.CreateIPConfigsReq(&cniSkel.CmdArgs{ContainerID: a.ContainerID, IfName: a.IfName, Args: a.Args})
+			if err != nil {
+				p.logger.Error("Failed to create CNS IP configs request", zap.Error(err), zap.Any("attachment", a))
+				return cniTypes.NewError(ErrCreateIPConfigsRequest, err.Error(), "failed to create CNS IP configs request")
+			}
+			p.logger.Info("Releasing leaked IP addresses", zap.String("containerID", a.ContainerID), zap.String("ifName", a.IfName), zap.Strings("ips", a.IPs))
+			// cnsClient enforces it own timeout
+			if err := p.cnsClient.ReleaseIPs(context.TODO(), req); err != nil {
+				p.logger.Error("Failed to release leaked IP addresses from CNS", zap.Error(err), zap.Any("request", req))
+				return cniTypes.NewError(cniTypes.ErrTryAgainLater, err.Error(), "failed to release leaked IP addresses from CNS")
+			}
+			released++
+		}
+		if err := p.allocations.Remove(nwCfg.Name, a.ContainerID, a.IfName); err != nil {
+			p.logger.Error("Failed to remove the allocation record", zap.Error(err))
+			return cniTypes.NewError(cniTypes.ErrIOFailure, err.Error(), "failed to remove the allocation record")
+		}
+	}
+
+	p.logger.Info("GC success", zap.Int("released", released))
+
+	return nil
+}
+
+// recordAllocation records the attachment which the IPs were allocated to for GC. A failure only
+// keeps GC from releasing the IPs, so it doesn't fail the ADD.
+func (p *IPAMPlugin) recordAllocation(network string, args *cniSkel.CmdArgs, podIPNets []netip.Prefix) {
+	a := &allocation.Attachment{
+		ContainerID: args.ContainerID,
+		IfName:      args.IfName,
+		Args:        args.Args,
+		IPs:         make([]string, len(podIPNets)),
+		Allocated:   time.Now(),
+	}
+	for i := range podIPNets {
+		a.IPs[i] = podIPNets[i].Addr().String()
+	}
+	if err := p.allocations.Add(network, a); err != nil {
+		p.logger.Error("Failed to record the allocation", zap.Error(err), zap.Any("attachment", a))
+	}
+}
+
 // Parse network config from given byte array
 func parseNetConf(b []byte) (*cniTypes.NetConf, error) {
 	netConf := &cniTypes.NetConf{}
@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"net"
 	"testing"
+	"time"
 
+	"github.com/Azure/azure-container-networking/azure-ipam/allocation"
 	"github.com/Azure/azure-container-networking/azure-ipam/logger"
 	"github.com/Azure/azure-container-networking/cns"
 	"github.com/Azure/azure-container-networking/cns/client"
@@ -219,6 +221,31 @@ func (c *MockCNSClient) ReleaseIPs(ctx context.Context, ipconfig cns.IPConfigsRe
 	}
 }
 
+func (c *MockCNSClient) RenewIPLeases(ctx context.Context, ipconfig cns.IPConfigsRequest) error {
+	switch ipconfig.InfraContainerID {
+	case "failRequestCNSRenewArgs":
+		return errFoo
+	case "happyArgsSingle":
+		e := &client.CNSClientError{}
+		e.Code = types.UnsupportedAPI
+		e.Err = errUnsupportedAPI
+		return e
+	default:
+		return nil
+	}
+}
+
+// gcCNSClient is a mock CNS client which records the releases made during GC
+type gcCNSClient struct {
+	MockCNSClient
+	released []cns.IPConfigsRequest
+}
+
+func (c *gcCNSClient) ReleaseIPs(ctx context.Context, ipconfig cns.IPConfigsRequest) error {
+	c.released = append(c.released, ipconfig)
+	return nil
+}
+
 // cniResultsWriter is a helper struct to write CNI results to a byte array
 type cniResultsWriter struct {
 	result *types100.Result
@@ -358,7 +385,7 @@ func TestCmdAdd(t *testing.T) {
 				return
 			}
 			defer cleanup()
-			ipamPlugin, _ := NewPlugin(testLogger, mockCNSClient, writer)
+			ipamPlugin, _ := NewPlugin(testLogger, mockCNSClient, allocation.NewStore(t.TempDir()), writer)
 			err = ipamPlugin.CmdAdd(tt.args)
 			if tt.wantErr {
 				require.Error(t, err)
@@ -414,7 +441,7 @@ func TestCmdDel(t *testing.T) {
 				return
 			}
 			defer cleanup()
-			ipamPlugin, _ := NewPlugin(testLogger, mockCNSClient, nil)
+			ipamPlugin, _ := NewPlugin(testLogger, mockCNSClient, allocation.NewStore(t.TempDir()), nil)
 			err = ipamPlugin.CmdDel(tt.args)
 			if tt.wantErr {
 				require.Error(t, err)
@@ -426,13 +453,129 @@ func TestCmdDel(t *testing.T) {
 }
 
 func TestCmdCheck(t *testing.T) {
+	tests := []scenario{
+		{
+			name: "Happy renew",
+			args: buildArgs("happyArgs", happyPodArgs, nil),
+		},
+		{
+			name: "Happy renew against CNS without leases",
+			args: buildArgs("happyArgsSingle", happyPodArgs, nil),
+		},
+		{
+			name:    "Fail renew",
+			args:    buildArgs("failRequestCNSRenewArgs", happyPodArgs, nil),
+			wantErr: true,
+		},
+		{
+			name:    "Fail create IP configs request",
+			args:    buildArgs("failCreateIPConfigsReq", "bad", nil),
+			wantErr: true,
+		},
+	}
+
 	mockCNSClient := &MockCNSClient{}
 	testLogger, cleanup, err := logger.New(loggerCfg)
 	if err != nil {
 		return
 	}
 	defer cleanup()
-	ipamPlugin, _ := NewPlugin(testLogger, mockCNSClient, nil)
-	err = ipamPlugin.CmdCheck(nil)
+	ipamPlugin, _ := NewPlugin(testLogger, mockCNSClient, allocation.NewStore(t.TempDir()), nil)
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			err := ipamPlugin.CmdCheck(tt.args)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCmdGC(t *testing.T) {
+	testLogger, cleanup, err := logger.New(loggerCfg)
+	require.NoError(t, err)
+	defer cleanup()
+
+	old := time.Now().Add(-2 * gcGracePeriod)
+	store := allocation.NewStore(t.TempDir())
+	for network, attachments := range map[string][]*allocation.Attachment{
+		"happynetconf": {
+			{ContainerID: "validid", IfName: "eth0", Args: happyPodArgs, Allocated: old},
+			{ContainerID: "validid", IfName: "eth1", Args: happyPodArgs, Allocated: old},
+			{ContainerID: "leakedid", IfName: "eth0", Args: happyPodArgs, IPs: []string{"10.0.1.11", "fd11:1234::1"}, Allocated: old},
+			{ContainerID: "newid", IfName: "eth0", Args: happyPodArgs, Allocated: time.Now()},
+		},
+		"othernetconf": {
+			{ContainerID: "otherid", IfName: "eth0", Args: happyPodArgs, Allocated: old},
+		},
+	} {
+		for _, a := range attachments {
+			require.NoError(t, store.Add(network, a))
+		}
+	}
+
+	netConf := &cniTypes.NetConf{
+		CNIVersion: "1.1.0",
+		Name:       "happynetconf",
+		Type:       "azure-ipam",
+		ValidAttachments: []cniTypes.GCAttachment{
+			{ContainerID: "validid", IfName: "eth0"},
+		},
+	}
+	stdin, err := json.Marshal(netConf)
+	require.NoError(t, err)
+
+	mockCNSClient := &gcCNSClient{}
+	ipamPlugin, err := NewPlugin(testLogger, mockCNSClient, store, nil)
+	require.NoError(t, err)
+
+	err = ipamPlugin.CmdGC(&cniSkel.CmdArgs{StdinData: stdin})
+	require.NoError(t, err)
+
+	// only the old attachment of this network whose container is gone is released
+	require.Len(t, mockCNSClient.released, 1)
+	require.Equal(t, "leakedid", mockCNSClient.released[0].InfraContainerID)
+	require.Equal(t, "eth0", mockCNSClient.released[0].Ifname)
+	require.NotEmpty(t, mockCNSClient.released[0].OrchestratorContext)
+
+	remaining, err := store.List("happynetconf")
+	require.NoError(t, err)
+	ids := []string{}
+	for _, a := range remaining {
+		ids = append(ids, a.ContainerID+"/"+a.IfName)
+	}
+	require.ElementsMatch(t, []string{"validid/eth0", "newid/eth0"}, ids)
+
+	remaining, err = store.List("othernetconf")
+	require.NoError(t, err)
+	require.Len(t, remaining, 1)
+}
+
+func TestAllocationRecordedOnAddAndRemovedOnDel(t *testing.T) {
+	testLogger, cleanup, err := logger.New(loggerCfg)
+	require.NoError(t, err)
+	defer cleanup()
+
+	stdin, err := json.Marshal(&cniTypes.NetConf{CNIVersion: "1.0.0", Name: "happynetconf"})
+	require.NoError(t, err)
+	store := allocation.NewStore(t.TempDir())
+	ipamPlugin, err := NewPlugin(testLogger, &MockCNSClient{}, store, &cniResultsWriter{})
+	require.NoError(t, err)
+
+	require.NoError(t, ipamPlugin.CmdAdd(buildArgs("happyArgsDual", happyPodArgs, stdin)))
+	attachments, err := store.List("happynetconf")
+	require.NoError(t, err)
+	require.Len(t, attachments, 1)
+	require.Equal(t, "happyArgsDual", attachments[0].ContainerID)
+	require.Equal(t, "testifname", attachments[0].IfName)
+	require.Equal(t, []string{"10.0.1.10", "fd11:1234::1"}, attachments[0].IPs)
+
+	require.NoError(t, ipamPlugin.CmdDel(buildArgs("happyArgsDual", happyPodArgs, stdin)))
+	attachments, err = store.List("happynetconf")
 	require.NoError(t, err)
+	require.Empty(t, attachments)
 }
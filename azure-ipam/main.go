@@ -4,6 +4,7 @@ import (
 	"log"
 	"os"
 
+	"github.com/Azure/azure-container-networking/azure-ipam/allocation"
 	"github.com/Azure/azure-container-networking/azure-ipam/internal/buildinfo"
 	"github.com/Azure/azure-container-networking/azure-ipam/logger"
 	cnsclient "github.com/Azure/azure-container-networking/cns/client"
@@ -37,13 +38,18 @@ func executePlugin() error {
 	defer cleanup()
 
 	// Create CNS client
-	client, err := cnsclient.New(cnsBaseURL, cnsReqTimeout)
+	var client *cnsclient.Client
+	if grpcAddress := os.Getenv(cnsGRPCAddressEnv); grpcAddress != "" {
+		client, err = cnsclient.NewWithGRPC(cnsBaseURL, grpcAddress, cnsReqTimeout)
+	} else {
+		client, err = cnsclient.New(cnsBaseURL, cnsReqTimeout)
+	}
 	if err != nil {
 		return errors.Wrapf(err, "failed to initialize CNS client")
 	}
 
 	// Create IPAM plugin
-	plugin, err := NewPlugin(pluginLogger, client, os.Stdout)
+	plugin, err := NewPlugin(pluginLogger, client, allocation.NewStore(allocation.Dir), os.Stdout)
 	if err != nil {
 		pluginLogger.Error("Failed to create IPAM plugin")
 		return errors.Wrapf(err, "failed to create IPAM plugin")
@@ -52,7 +58,7 @@ func executePlugin() error {
 	bv.BuildVersion = buildinfo.Version
 
 	// Execute CNI plugin
-	cniErr := skel.PluginMainWithError(plugin.CmdAdd, plugin.CmdCheck, plugin.CmdDel, version.All, bv.BuildString(pluginName))
+	cniErr := skel.PluginMainFuncsWithError(skel.CNIFuncs{Add: plugin.CmdAdd, Check: plugin.CmdCheck, Del: plugin.CmdDel, GC: plugin.CmdGC}, version.All, bv.BuildString(pluginName))
 	if cniErr != nil {
 		cniErr.Print()
 		return cniErr
@@ -16,6 +16,12 @@ const (
 	CmdGet = "GET"
 	// CmdDel - CNI DEL command.
 	CmdDel = "DEL"
+	// CmdCheck - CNI CHECK command.
+	CmdCheck = "CHECK"
+	// CmdStatus - CNI STATUS command.
+	CmdStatus = "STATUS"
+	// CmdGC - CNI GC command.
+	CmdGC = "GC"
 	// CmdUpdate - CNI UPDATE command.
 	CmdUpdate = "UPDATE"
 	// CmdVersion - CNI VERSION command.
@@ -24,15 +30,24 @@ const (
 	// nonstandard CNI spec command, used to dump CNI state to stdout
 	CmdGetEndpointsState = "GET_ENDPOINT_STATE"
 
+	// EnvEnableWALStore keeps the plugin state in a write-ahead log backed store when set to "true".
+	EnvEnableWALStore = "AZURE_CNI_ENABLE_WAL_STORE"
+
 	// CNI errors.
 	ErrRuntime = 100
+	// ErrDatapathDrift is returned by CHECK when the endpoint datapath no longer matches the recorded state.
+	ErrDatapathDrift = 101
+	// ErrPluginNotAvailable is returned by STATUS when the plugin cannot serve ADD requests.
+	ErrPluginNotAvailable = 50
+	// ErrLimitedConnectivity is returned by STATUS when the host network is degraded.
+	ErrLimitedConnectivity = 51
 
 	// DefaultVersion is the CNI version used when no version is specified in a network config file.
 	defaultVersion = "0.2.0"
 )
 
 // Supported CNI versions.
-var supportedVersions = []string{"0.1.0", "0.2.0", "0.3.0", "0.3.1", "0.4.0", "1.0.0"}
+var supportedVersions = []string{"0.1.0", "0.2.0", "0.3.0", "0.3.1", "0.4.0", "1.0.0", "1.1.0"}
 
 // CNI contract.
 type PluginApi interface {
@@ -40,4 +55,7 @@ type PluginApi interface {
 	Get(args *cniSkel.CmdArgs) error
 	Delete(args *cniSkel.CmdArgs) error
 	Update(args *cniSkel.CmdArgs) error
+	Check(args *cniSkel.CmdArgs) error
+	Status(args *cniSkel.CmdArgs) error
+	GC(args *cniSkel.CmdArgs) error
 }
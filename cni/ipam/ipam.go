@@ -278,6 +278,22 @@ func (plugin *ipamPlugin) Get(args *cniSkel.CmdArgs) error {
 	return nil
 }
 
+// Check handles CNI check commands.
+func (plugin *ipamPlugin) Check(args *cniSkel.CmdArgs) error {
+	return plugin.Get(args)
+}
+
+// Status handles CNI status commands. The local address manager has no external dependencies.
+func (plugin *ipamPlugin) Status(args *cniSkel.CmdArgs) error {
+	return nil
+}
+
+// GC handles CNI gc commands. Addresses of stale endpoints are released by the
+// network plugin deleting them, so there is nothing left to collect here.
+func (plugin *ipamPlugin) GC(args *cniSkel.CmdArgs) error {
+	return nil
+}
+
 // Delete handles CNI delete commands.
 func (plugin *ipamPlugin) Delete(args *cniSkel.CmdArgs) error {
 	var err error
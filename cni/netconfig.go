@@ -29,8 +29,18 @@ type PortMapping struct {
 }
 
 type RuntimeConfig struct {
-	PortMappings []PortMapping    `json:"portMappings,omitempty"`
-	DNS          RuntimeDNSConfig `json:"dns,omitempty"`
+	PortMappings []PortMapping     `json:"portMappings,omitempty"`
+	DNS          RuntimeDNSConfig  `json:"dns,omitempty"`
+	Bandwidth    *RuntimeBandwidth `json:"bandwidth,omitempty"`
+}
+
+// https://www.cni.dev/plugins/current/meta/bandwidth/
+// Rates are in bits per second and bursts in bits.
+type RuntimeBandwidth struct {
+	IngressRate  uint64 `json:"ingressRate,omitempty"`
+	IngressBurst uint64 `json:"ingressBurst,omitempty"`
+	EgressRate   uint64 `json:"egressRate,omitempty"`
+	EgressBurst  uint64 `json:"egressBurst,omitempty"`
 }
 
 // https://github.com/kubernetes/kubernetes/blob/master/pkg/kubelet/dockershim/network/cni/cni.go#L104
@@ -52,33 +62,40 @@ type IPAM struct {
 
 // NetworkConfig represents Azure CNI plugin network configuration.
 type NetworkConfig struct {
-	CNIVersion                    string          `json:"cniVersion,omitempty"`
-	Name                          string          `json:"name,omitempty"`
-	Type                          string          `json:"type,omitempty"`
-	Mode                          string          `json:"mode,omitempty"`
-	Master                        string          `json:"master,omitempty"`
-	AdapterName                   string          `json:"adapterName,omitempty"`
-	Bridge                        string          `json:"bridge,omitempty"`
-	LogLevel                      string          `json:"logLevel,omitempty"`
-	LogTarget                     string          `json:"logTarget,omitempty"`
-	InfraVnetAddressSpace         string          `json:"infraVnetAddressSpace,omitempty"`
-	IPV6Mode                      string          `json:"ipv6Mode,omitempty"`
-	ServiceCidrs                  string          `json:"serviceCidrs,omitempty"`
-	VnetCidrs                     string          `json:"vnetCidrs,omitempty"`
-	PodNamespaceForDualNetwork    []string        `json:"podNamespaceForDualNetwork,omitempty"`
-	IPsToRouteViaHost             []string        `json:"ipsToRouteViaHost,omitempty"`
-	MultiTenancy                  bool            `json:"multiTenancy,omitempty"`
-	EnableSnatOnHost              bool            `json:"enableSnatOnHost,omitempty"`
-	EnableExactMatchForPodName    bool            `json:"enableExactMatchForPodName,omitempty"`
-	DisableHairpinOnHostInterface bool            `json:"disableHairpinOnHostInterface,omitempty"`
-	DisableIPTableLock            bool            `json:"disableIPTableLock,omitempty"`
-	CNSUrl                        string          `json:"cnsurl,omitempty"`
-	ExecutionMode                 string          `json:"executionMode,omitempty"`
-	IPAM                          IPAM            `json:"ipam,omitempty"`
-	DNS                           cniTypes.DNS    `json:"dns,omitempty"`
-	RuntimeConfig                 RuntimeConfig   `json:"runtimeConfig,omitempty"`
-	WindowsSettings               WindowsSettings `json:"windowsSettings,omitempty"`
-	AdditionalArgs                []KVPair        `json:"AdditionalArgs,omitempty"`
+	CNIVersion                    string                  `json:"cniVersion,omitempty"`
+	Name                          string                  `json:"name,omitempty"`
+	Type                          string                  `json:"type,omitempty"`
+	Mode                          string                  `json:"mode,omitempty"`
+	Master                        string                  `json:"master,omitempty"`
+	AdapterName                   string                  `json:"adapterName,omitempty"`
+	Bridge                        string                  `json:"bridge,omitempty"`
+	LogLevel                      string                  `json:"logLevel,omitempty"`
+	LogTarget                     string                  `json:"logTarget,omitempty"`
+	InfraVnetAddressSpace         string                  `json:"infraVnetAddressSpace,omitempty"`
+	IPV6Mode                      string                  `json:"ipv6Mode,omitempty"`
+	ServiceCidrs                  string                  `json:"serviceCidrs,omitempty"`
+	VnetCidrs                     string                  `json:"vnetCidrs,omitempty"`
+	PodNamespaceForDualNetwork    []string                `json:"podNamespaceForDualNetwork,omitempty"`
+	IPsToRouteViaHost             []string                `json:"ipsToRouteViaHost,omitempty"`
+	MultiTenancy                  bool                    `json:"multiTenancy,omitempty"`
+	EnableSnatOnHost              bool                    `json:"enableSnatOnHost,omitempty"`
+	EnableExactMatchForPodName    bool                    `json:"enableExactMatchForPodName,omitempty"`
+	DisableHairpinOnHostInterface bool                    `json:"disableHairpinOnHostInterface,omitempty"`
+	DisableIPTableLock            bool                    `json:"disableIPTableLock,omitempty"`
+	MTU                           int                     `json:"mtu,omitempty"`           // MTU of the bridge and endpoint interfaces
+	AutoDetectMTU                 bool                    `json:"autoDetectMtu,omitempty"` // when set and mtu is not, the MTU of the master interface is used
+	TSO                           *bool                   `json:"tso,omitempty"`           // turns TCP segmentation offload of the endpoint veths on or off
+	GRO                           *bool                   `json:"gro,omitempty"`           // turns generic receive offload of the endpoint veths on or off
+	CNSUrl                        string                  `json:"cnsurl,omitempty"`
+	CNSGRPCAddress                string                  `json:"cnsGrpcAddress,omitempty"` // when set, IPAM calls to CNS use its gRPC API
+	ExecutionMode                 string                  `json:"executionMode,omitempty"`
+	IPAM                          IPAM                    `json:"ipam,omitempty"`
+	DNS                           cniTypes.DNS            `json:"dns,omitempty"`
+	RuntimeConfig                 RuntimeConfig           `json:"runtimeConfig,omitempty"`
+	WindowsSettings               WindowsSettings         `json:"windowsSettings,omitempty"`
+	AdditionalArgs                []KVPair                `json:"AdditionalArgs,omitempty"`
+	ValidAttachments              []cniTypes.GCAttachment `json:"cni.dev/valid-attachments,omitempty"` // only supplied on GC
+	RawPrevResult                 map[string]interface{}  `json:"prevResult,omitempty"`                // result of the previous plugin of a chain
 }
 
 type WindowsSettings struct {
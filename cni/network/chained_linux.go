@@ -0,0 +1,291 @@
+package network
+
+import (
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-container-networking/cni"
+	"github.com/Azure/azure-container-networking/cns"
+	"github.com/Azure/azure-container-networking/iptables"
+	"github.com/Azure/azure-container-networking/netio"
+	"github.com/Azure/azure-container-networking/netlink"
+	"github.com/Azure/azure-container-networking/network"
+	"github.com/Azure/azure-container-networking/platform"
+	cniSkel "github.com/containernetworking/cni/pkg/skel"
+	cniTypes "github.com/containernetworking/cni/pkg/types"
+	cniTypesCurr "github.com/containernetworking/cni/pkg/types/100"
+	cniVersion "github.com/containernetworking/cni/pkg/version"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// chainedEndpointsDir records the chained endpoints, one file per endpoint under a directory per network, so that DEL
+// and GC remove what ADD added even without the result of the previous plugin.
+const chainedEndpointsDir = "/var/run/azure-vnet/chained"
+
+var errNoPrevResult = errors.New("chained mode requires the result of the previous plugin")
+
+// parsePrevResult returns the result of the previous plugin of the chain.
+func parsePrevResult(nwCfg *cni.NetworkConfig) (*cniTypesCurr.Result, error) {
+	if nwCfg.RawPrevResult == nil {
+		return nil, errNoPrevResult
+	}
+	b, err := json.Marshal(nwCfg.RawPrevResult)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal prevResult")
+	}
+	res, err := cniVersion.NewResult(nwCfg.CNIVersion, b)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse prevResult")
+	}
+	prevResult, err := cniTypesCurr.NewResultFromResult(res)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to convert prevResult")
+	}
+	return prevResult, nil
+}
+
+// chainedEndpointInfo returns the Azure part of the endpoint the previous plugin of the chain set up on the container
+// interface of the args.
+func (plugin *NetPlugin) chainedEndpointInfo(args *cniSkel.CmdArgs, nwCfg *cni.NetworkConfig, prevResult *cniTypesCurr.Result) (*network.ChainedEndpointInfo, error) {
+	info := &network.ChainedEndpointInfo{
+		ContainerID:       args.ContainerID,
+		NetNsPath:         args.Netns,
+		IfName:            args.IfName,
+		IPsToRouteViaHost: nwCfg.IPsToRouteViaHost,
+	}
+
+	containerIfIndex := -1
+	for i, iface := range prevResult.Interfaces {
+		if iface.Name == args.IfName && iface.Sandbox != "" {
+			containerIfIndex = i
+		} else if iface.Sandbox == "" && info.HostIfName == "" {
+			info.HostIfName = iface.Name
+		}
+	}
+	if containerIfIndex < 0 {
+		return nil, errors.Errorf("prevResult has no interface %s in a sandbox", args.IfName)
+	}
+
+	var subnets []network.SubnetInfo
+	for _, ipConfig := range prevResult.IPs {
+		// addresses without an interface are taken to be on the container interface.
+		if ipConfig.Interface != nil && *ipConfig.Interface != containerIfIndex {
+			continue
+		}
+		info.IPAddresses = append(info.IPAddresses, ipConfig.Address)
+		family := platform.AfINET
+		if ipConfig.Address.IP.To4() == nil {
+			family = platform.AfINET6
+		}
+		subnets = append(subnets, network.SubnetInfo{
+			Family: family,
+			Prefix: net.IPNet{IP: ipConfig.Address.IP.Mask(ipConfig.Address.Mask), Mask: ipConfig.Address.Mask},
+		})
+		if ipConfig.Gateway != nil {
+			info.Gateways = append(info.Gateways, ipConfig.Gateway)
+		}
+	}
+	if len(info.IPAddresses) == 0 {
+		return nil, errors.Errorf("prevResult has no address on interface %s", args.IfName)
+	}
+
+	if nwCfg.EnableSnatOnHost {
+		var err error
+		if info.EgressSNAT, err = plugin.getEgressSNAT(&cns.EgressSNAT{Mode: cns.EgressSNATNodeIP}, nwCfg, subnets); err != nil {
+			return nil, err
+		}
+	}
+	return info, nil
+}
+
+func newChainedClient() *network.ChainedClient {
+	return network.NewChainedClient(netlink.NewNetlink(), &netio.NetIO{}, network.NewNamespaceClient(), iptables.NewClient())
+}
+
+// addChained adds the Azure host routes, egress SNAT and routes of the IPs to route via the host to the endpoint the
+// previous plugin of the chain set up, records it and passes the result of the previous plugin on.
+func (plugin *NetPlugin) addChained(args *cniSkel.CmdArgs, nwCfg *cni.NetworkConfig) error {
+	prevResult, err := parsePrevResult(nwCfg)
+	if err != nil {
+		return plugin.Errorf(err.Error())
+	}
+	info, err := plugin.chainedEndpointInfo(args, nwCfg, prevResult)
+	if err != nil {
+		return plugin.Errorf(err.Error())
+	}
+
+	logger.Info("Adding chained endpoint",
+		zap.String("containerID", info.ContainerID),
+		zap.String("hostIfName", info.HostIfName),
+		zap.Any("IPs", info.IPAddresses))
+	client := newChainedClient()
+	if err = client.AddEndpoint(info); err != nil {
+		client.DeleteEndpoint(info)
+		return plugin.Errorf(err.Error())
+	}
+	if err = plugin.saveChainedEndpoint(nwCfg.Name, info); err != nil {
+		client.DeleteEndpoint(info)
+		return plugin.Errorf(err.Error())
+	}
+
+	res, err := prevResult.GetAsVersion(nwCfg.CNIVersion)
+	if err != nil {
+		return plugin.Errorf(err.Error())
+	}
+	return errors.Wrap(res.Print(), "failed to print result")
+}
+
+// deleteChained removes what addChained added, as recorded, or as derived from the result of the previous plugin for
+// endpoints added before they were recorded, in which case the host routes are left to be removed with the host
+// interface as it is not known which of them addChained added. Without either there is nothing to remove.
+func (plugin *NetPlugin) deleteChained(args *cniSkel.CmdArgs, nwCfg *cni.NetworkConfig) error {
+	info, err := plugin.loadChainedEndpoint(nwCfg.Name, args.ContainerID, args.IfName)
+	if err != nil {
+		return plugin.Errorf(err.Error())
+	}
+	if info == nil {
+		prevResult, err := parsePrevResult(nwCfg)
+		if err == nil {
+			info, err = plugin.chainedEndpointInfo(args, nwCfg, prevResult)
+		}
+		if err != nil {
+			logger.Info("Nothing to delete for chained endpoint", zap.String("containerID", args.ContainerID), zap.Error(err))
+			return nil
+		}
+	}
+
+	logger.Info("Deleting chained endpoint", zap.String("containerID", info.ContainerID), zap.Any("IPs", info.IPAddresses))
+	newChainedClient().DeleteEndpoint(info)
+	if err := plugin.removeChainedEndpoint(nwCfg.Name, args.ContainerID, args.IfName); err != nil {
+		return plugin.Errorf(err.Error())
+	}
+	return nil
+}
+
+// verifyChained checks that what addChained added to the endpoint the previous plugin of the chain set up still
+// exists.
+func (plugin *NetPlugin) verifyChained(args *cniSkel.CmdArgs, nwCfg *cni.NetworkConfig) ([]network.DatapathDrift, error) {
+	prevResult, err := parsePrevResult(nwCfg)
+	if err != nil {
+		return nil, err
+	}
+	info, err := plugin.chainedEndpointInfo(args, nwCfg, prevResult)
+	if err != nil {
+		return nil, err
+	}
+	return newChainedClient().VerifyEndpoint(info) //nolint:wrapcheck // the caller adds the context
+}
+
+// gcChained removes what addChained added to the recorded endpoints of the network which are not valid attachments and
+// are older than gcGracePeriod.
+func (plugin *NetPlugin) gcChained(nwCfg *cni.NetworkConfig, valid map[cniTypes.GCAttachment]struct{}) ([]string, error) {
+	infos, err := plugin.listChainedEndpoints(nwCfg.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	var collected []string
+	for _, info := range infos {
+		if _, ok := valid[cniTypes.GCAttachment{ContainerID: info.ContainerID, IfName: info.IfName}]; ok {
+			continue
+		}
+		// an ADD may still be in flight when the runtime builds its list of valid attachments
+		if time.Since(info.Created) < gcGracePeriod {
+			continue
+		}
+		logger.Info("Collecting stale chained endpoint", zap.String("containerID", info.ContainerID), zap.String("ifName", info.IfName))
+		newChainedClient().DeleteEndpoint(info)
+		if err := plugin.removeChainedEndpoint(nwCfg.Name, info.ContainerID, info.IfName); err != nil {
+			return collected, err
+		}
+		collected = append(collected, info.ContainerID)
+	}
+	return collected, nil
+}
+
+func (plugin *NetPlugin) chainedEndpointFile(networkName, containerID, ifName string) string {
+	return filepath.Join(plugin.chainedDir, networkName, containerID+"_"+ifName+jsonFileExtension)
+}
+
+// saveChainedEndpoint records the chained endpoint in the network, replacing any previous record of it.
+func (plugin *NetPlugin) saveChainedEndpoint(networkName string, info *network.ChainedEndpointInfo) error {
+	networkDir := filepath.Join(plugin.chainedDir, networkName)
+	//nolint:gomnd // 0o755 - permission to create directory in octal
+	if err := os.MkdirAll(networkDir, 0o755); err != nil {
+		return errors.Wrapf(err, "failed to create chained endpoint directory %s", networkDir)
+	}
+	b, err := json.Marshal(info)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal chained endpoint")
+	}
+
+	// write to a temp file first so that a crash never leaves a partial record behind
+	file := plugin.chainedEndpointFile(networkName, info.ContainerID, info.IfName)
+	tmp := file + ".tmp"
+	//nolint:gomnd // 0o644 - permission of the record file in octal
+	if err := os.WriteFile(tmp, b, 0o644); err != nil {
+		return errors.Wrapf(err, "failed to write chained endpoint %s", tmp)
+	}
+	return errors.Wrapf(os.Rename(tmp, file), "failed to rename chained endpoint %s", tmp)
+}
+
+// loadChainedEndpoint returns the recorded chained endpoint, nil if it is not recorded.
+func (plugin *NetPlugin) loadChainedEndpoint(networkName, containerID, ifName string) (*network.ChainedEndpointInfo, error) {
+	file := plugin.chainedEndpointFile(networkName, containerID, ifName)
+	b, err := os.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to read chained endpoint %s", file)
+	}
+	info := &network.ChainedEndpointInfo{}
+	if err := json.Unmarshal(b, info); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal chained endpoint %s", file)
+	}
+	return info, nil
+}
+
+// removeChainedEndpoint deletes the record of the chained endpoint, if any.
+func (plugin *NetPlugin) removeChainedEndpoint(networkName, containerID, ifName string) error {
+	file := plugin.chainedEndpointFile(networkName, containerID, ifName)
+	if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "failed to remove chained endpoint %s", file)
+	}
+	return nil
+}
+
+// listChainedEndpoints returns the chained endpoints recorded in the network.
+func (plugin *NetPlugin) listChainedEndpoints(networkName string) ([]*network.ChainedEndpointInfo, error) {
+	networkDir := filepath.Join(plugin.chainedDir, networkName)
+	entries, err := os.ReadDir(networkDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to read chained endpoint directory %s", networkDir)
+	}
+
+	infos := make([]*network.ChainedEndpointInfo, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), jsonFileExtension) {
+			continue
+		}
+		file := filepath.Join(networkDir, entry.Name())
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read chained endpoint %s", file)
+		}
+		info := &network.ChainedEndpointInfo{}
+		if err := json.Unmarshal(b, info); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal chained endpoint %s", file)
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
@@ -0,0 +1,141 @@
+//go:build linux
+// +build linux
+
+package network
+
+import (
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-container-networking/cni"
+	"github.com/Azure/azure-container-networking/cns"
+	"github.com/Azure/azure-container-networking/network"
+	cniSkel "github.com/containernetworking/cni/pkg/skel"
+	cniTypes "github.com/containernetworking/cni/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const chainedPrevResult = `{
+	"cniVersion": "1.0.0",
+	"interfaces": [
+		{"name": "lxc1234"},
+		{"name": "eth0", "sandbox": "/var/run/netns/pod"}
+	],
+	"ips": [
+		{"interface": 1, "address": "10.240.0.5/16", "gateway": "10.240.0.1"},
+		{"interface": 1, "address": "fd00::5/64"}
+	]
+}`
+
+func chainedNetworkConfig(t *testing.T) *cni.NetworkConfig {
+	nwCfg := &cni.NetworkConfig{
+		CNIVersion:        "1.0.0",
+		Name:              "azure",
+		Mode:              OpModeChained,
+		VnetCidrs:         "10.0.0.0/8",
+		EnableSnatOnHost:  true,
+		IPsToRouteViaHost: []string{"169.254.169.254"},
+	}
+	require.NoError(t, json.Unmarshal([]byte(chainedPrevResult), &nwCfg.RawPrevResult))
+	return nwCfg
+}
+
+func TestChainedEndpointInfo(t *testing.T) {
+	plugin := GetTestResources()
+	args := &cniSkel.CmdArgs{ContainerID: "abc", Netns: "/var/run/netns/pod", IfName: eth0IfName}
+
+	nwCfg := chainedNetworkConfig(t)
+	prevResult, err := parsePrevResult(nwCfg)
+	require.NoError(t, err)
+	info, err := plugin.chainedEndpointInfo(args, nwCfg, prevResult)
+	require.NoError(t, err)
+
+	assert.Equal(t, "lxc1234", info.HostIfName)
+	assert.Equal(t, []net.IPNet{
+		{IP: net.ParseIP("10.240.0.5"), Mask: net.CIDRMask(16, 32)},
+		{IP: net.ParseIP("fd00::5"), Mask: net.CIDRMask(64, 128)},
+	}, info.IPAddresses)
+	assert.Equal(t, []net.IP{net.ParseIP("10.240.0.1")}, info.Gateways)
+	assert.Equal(t, []string{"169.254.169.254"}, info.IPsToRouteViaHost)
+	require.NotNil(t, info.EgressSNAT)
+	assert.Equal(t, cns.EgressSNATNodeIP, info.EgressSNAT.Mode)
+	// traffic to the pod subnets and the vnet is not source NATed
+	assert.Equal(t, []string{"10.240.0.0/16", "fd00::/64", "10.0.0.0/8"}, info.EgressSNAT.Except)
+
+	// without snat on host the egress traffic is left to the previous plugin
+	nwCfg.EnableSnatOnHost = false
+	info, err = plugin.chainedEndpointInfo(args, nwCfg, prevResult)
+	require.NoError(t, err)
+	assert.Nil(t, info.EgressSNAT)
+
+	// the container interface must be in a sandbox
+	_, err = plugin.chainedEndpointInfo(&cniSkel.CmdArgs{ContainerID: "abc", IfName: "lxc1234"}, nwCfg, prevResult)
+	require.Error(t, err)
+}
+
+func TestChainedWithoutPrevResult(t *testing.T) {
+	plugin := GetTestResources()
+	plugin.chainedDir = t.TempDir()
+	nwCfg := &cni.NetworkConfig{CNIVersion: "1.0.0", Name: "azure", Mode: OpModeChained}
+	args := &cniSkel.CmdArgs{ContainerID: "abc", Netns: "/var/run/netns/pod", IfName: eth0IfName, StdinData: nwCfg.Serialize()}
+
+	_, err := parsePrevResult(nwCfg)
+	require.ErrorIs(t, err, errNoPrevResult)
+	require.Error(t, plugin.addChained(args, nwCfg))
+	// there is nothing to delete without the result of the previous plugin
+	require.NoError(t, plugin.deleteChained(args, nwCfg))
+	// nor to verify
+	require.Error(t, plugin.Check(args))
+
+	// nor without the container interface in it
+	nwCfg = chainedNetworkConfig(t)
+	args = &cniSkel.CmdArgs{ContainerID: "abc", Netns: "/var/run/netns/pod", IfName: "eth1", StdinData: nwCfg.Serialize()}
+	require.NoError(t, plugin.deleteChained(args, nwCfg))
+}
+
+func TestChainedRecordedEndpoints(t *testing.T) {
+	plugin := GetTestResources()
+	plugin.chainedDir = t.TempDir()
+	nwCfg := &cni.NetworkConfig{CNIVersion: "1.0.0", Name: "azure", Mode: OpModeChained}
+
+	// endpoints without a host interface nor egress snat have nothing to remove from the host
+	keep := &network.ChainedEndpointInfo{ContainerID: "keep", IfName: eth0IfName, IPAddresses: []net.IPNet{{IP: net.ParseIP("10.240.0.5"), Mask: net.CIDRMask(16, 32)}}}
+	stale := &network.ChainedEndpointInfo{ContainerID: "stale", IfName: eth0IfName, IPAddresses: []net.IPNet{{IP: net.ParseIP("10.240.0.6"), Mask: net.CIDRMask(16, 32)}}}
+	// endpoints of in-flight ADDs are not valid attachments yet
+	recent := &network.ChainedEndpointInfo{ContainerID: "recent", IfName: eth0IfName, Created: time.Now().Truncate(time.Second)}
+	require.NoError(t, plugin.saveChainedEndpoint(nwCfg.Name, keep))
+	require.NoError(t, plugin.saveChainedEndpoint(nwCfg.Name, stale))
+	require.NoError(t, plugin.saveChainedEndpoint(nwCfg.Name, recent))
+	// other networks are left alone
+	require.NoError(t, plugin.saveChainedEndpoint("other", stale))
+
+	info, err := plugin.loadChainedEndpoint(nwCfg.Name, "keep", eth0IfName)
+	require.NoError(t, err)
+	assert.Equal(t, keep, info)
+	info, err = plugin.loadChainedEndpoint(nwCfg.Name, "missing", eth0IfName)
+	require.NoError(t, err)
+	assert.Nil(t, info)
+
+	nwCfg.ValidAttachments = []cniTypes.GCAttachment{{ContainerID: "keep", IfName: eth0IfName}}
+	require.NoError(t, plugin.GC(&cniSkel.CmdArgs{StdinData: nwCfg.Serialize()}))
+	infos, err := plugin.listChainedEndpoints(nwCfg.Name)
+	require.NoError(t, err)
+	require.Len(t, infos, 2)
+	assert.Equal(t, keep, infos[0])
+	assert.True(t, recent.Created.Equal(infos[1].Created))
+	infos, err = plugin.listChainedEndpoints("other")
+	require.NoError(t, err)
+	assert.Len(t, infos, 1)
+
+	// DEL removes the recorded endpoint without the result of the previous plugin
+	nwCfg.ValidAttachments = nil
+	args := &cniSkel.CmdArgs{ContainerID: "keep", IfName: eth0IfName, StdinData: nwCfg.Serialize()}
+	require.NoError(t, plugin.deleteChained(args, nwCfg))
+	infos, err = plugin.listChainedEndpoints(nwCfg.Name)
+	require.NoError(t, err)
+	require.Len(t, infos, 1)
+	assert.Equal(t, "recent", infos[0].ContainerID)
+}
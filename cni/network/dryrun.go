@@ -0,0 +1,291 @@
+package network
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+
+	"github.com/Azure/azure-container-networking/cni"
+	"github.com/Azure/azure-container-networking/cni/util"
+	"github.com/Azure/azure-container-networking/cns"
+	"github.com/Azure/azure-container-networking/network"
+	cniSkel "github.com/containernetworking/cni/pkg/skel"
+	"github.com/pkg/errors"
+)
+
+// Placeholder addresses of a dry run, from the documentation ranges, standing in for the addresses an ADD would
+// allocate.
+const (
+	dryRunPodIP          = "192.0.2.4"
+	dryRunNCPrimaryIP    = "192.0.2.2"
+	dryRunGateway        = "192.0.2.1"
+	dryRunPrefixLength   = 24
+	dryRunPodIPv6        = "2001:db8::4"
+	dryRunGatewayV6      = "2001:db8::1"
+	dryRunPrefixLengthV6 = 64
+)
+
+// AddPlan is what an ADD would do, as planned by DryRunAdd without touching the host.
+type AddPlan struct {
+	ContainerID  string `json:"containerID"`
+	Netns        string `json:"netns"`
+	IfName       string `json:"ifName"`
+	PodName      string `json:"podName"`
+	PodNamespace string `json:"podNamespace"`
+	Network      string `json:"network"`
+	Mode         string `json:"mode"`
+	// IPAMInvoker is the invoker which would allocate the addresses of the pod.
+	IPAMInvoker string                  `json:"ipamInvoker"`
+	CNSCalls    []PlannedCNSCall        `json:"cnsCalls,omitempty"`
+	Endpoints   []*network.EndpointPlan `json:"endpoints,omitempty"`
+	// PlaceholderAddresses is true when the addresses of the pod are placeholders for those IPAM would allocate.
+	PlaceholderAddresses bool     `json:"placeholderAddresses"`
+	Notes                []string `json:"notes,omitempty"`
+}
+
+// PlannedCNSCall is a call to the CNS API.
+type PlannedCNSCall struct {
+	API     string `json:"api"`
+	Request any    `json:"request"`
+}
+
+// dryRunCNSClient records the CNS calls of a dry run instead of sending them, answering IP requests with placeholder
+// addresses on the subnet of a host interface.
+type dryRunCNSClient struct {
+	calls    []PlannedCNSCall
+	hostIP   cns.HostIPInfo
+	ipamMode util.IpamMode
+}
+
+func (c *dryRunCNSClient) record(api string, request any) {
+	c.calls = append(c.calls, PlannedCNSCall{API: api, Request: request})
+}
+
+func (c *dryRunCNSClient) RequestIPAddress(_ context.Context, ipconfig cns.IPConfigRequest) (*cns.IPConfigResponse, error) {
+	c.record("RequestIPAddress", ipconfig)
+	resp := &cns.IPConfigResponse{PodIpInfo: c.podIPInfo()[0]}
+	return resp, nil
+}
+
+func (c *dryRunCNSClient) ReleaseIPAddress(_ context.Context, ipconfig cns.IPConfigRequest) error {
+	c.record("ReleaseIPAddress", ipconfig)
+	return nil
+}
+
+func (c *dryRunCNSClient) RequestIPs(_ context.Context, ipconfig cns.IPConfigsRequest) (*cns.IPConfigsResponse, error) {
+	c.record("RequestIPs", ipconfig)
+	return &cns.IPConfigsResponse{PodIPInfo: c.podIPInfo()}, nil
+}
+
+func (c *dryRunCNSClient) ReleaseIPs(_ context.Context, ipconfig cns.IPConfigsRequest) error {
+	c.record("ReleaseIPs", ipconfig)
+	return nil
+}
+
+func (c *dryRunCNSClient) GetNetworkContainer(_ context.Context, orchestratorContext []byte) (*cns.GetNetworkContainerResponse, error) {
+	c.record("GetNetworkContainer", json.RawMessage(orchestratorContext))
+	return nil, errors.New("network containers are not planned in a dry run")
+}
+
+func (c *dryRunCNSClient) GetAllNetworkContainers(_ context.Context, orchestratorContext []byte) ([]cns.GetNetworkContainerResponse, error) {
+	c.record("GetAllNetworkContainers", json.RawMessage(orchestratorContext))
+	return nil, errors.New("network containers are not planned in a dry run")
+}
+
+// podIPInfo returns the placeholder IPs of the pod, dual stack in dual stack overlay.
+func (c *dryRunCNSClient) podIPInfo() []cns.PodIpInfo {
+	podIPInfo := []cns.PodIpInfo{
+		{
+			PodIPConfig: cns.IPSubnet{IPAddress: dryRunPodIP, PrefixLength: dryRunPrefixLength},
+			NetworkContainerPrimaryIPConfig: cns.IPConfiguration{
+				IPSubnet:         cns.IPSubnet{IPAddress: dryRunNCPrimaryIP, PrefixLength: dryRunPrefixLength},
+				GatewayIPAddress: dryRunGateway,
+			},
+			HostPrimaryIPInfo: c.hostIP,
+			NICType:           cns.InfraNIC,
+		},
+	}
+	if c.ipamMode == util.DualStackOverlay {
+		podIPInfo = append(podIPInfo, cns.PodIpInfo{
+			PodIPConfig: cns.IPSubnet{IPAddress: dryRunPodIPv6, PrefixLength: dryRunPrefixLengthV6},
+			NetworkContainerPrimaryIPConfig: cns.IPConfiguration{
+				IPSubnet:         cns.IPSubnet{IPAddress: dryRunPodIPv6, PrefixLength: dryRunPrefixLengthV6},
+				GatewayIPAddress: dryRunGatewayV6,
+			},
+			HostPrimaryIPInfo: c.hostIP,
+			NICType:           cns.InfraNIC,
+		})
+	}
+	return podIPInfo
+}
+
+// dryRunHostIPInfo returns the primary IP and subnet of the master interface, or of the first up interface with an IPv4
+// address, so that the master interface is found as in an ADD.
+func (plugin *NetPlugin) dryRunHostIPInfo(nwCfg *cni.NetworkConfig) (cns.HostIPInfo, error) {
+	interfaces, err := plugin.netClient.GetNetworkInterfaces()
+	if err != nil {
+		return cns.HostIPInfo{}, errors.Wrap(err, "failed to get interfaces")
+	}
+	for i := range interfaces {
+		iface := interfaces[i]
+		if nwCfg.Master != "" && iface.Name != nwCfg.Master {
+			continue
+		}
+		if nwCfg.Master == "" && (iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0) {
+			continue
+		}
+		addrs, _ := iface.Addrs()
+		for _, addr := range addrs {
+			ip, ipNet, err := net.ParseCIDR(addr.String())
+			if err != nil || ip.To4() == nil {
+				continue
+			}
+			// the gateway of an azure subnet is its first address.
+			gateway := make(net.IP, net.IPv4len)
+			copy(gateway, ipNet.IP.To4())
+			gateway[3]++
+			return cns.HostIPInfo{PrimaryIP: ip.String(), Subnet: ipNet.String(), Gateway: gateway.String()}, nil
+		}
+	}
+	if nwCfg.Master != "" {
+		return cns.HostIPInfo{}, errors.Errorf("master interface %s has no IPv4 address", nwCfg.Master)
+	}
+	return cns.HostIPInfo{}, errors.New("failed to find an interface with an IPv4 address")
+}
+
+// DryRunAdd plans what Add would do with the args, without touching the host: CNS calls are recorded instead of sent,
+// the addresses of the pod are placeholders and the endpoints are planned by network.PlanEndpoint.
+func (plugin *NetPlugin) DryRunAdd(args *cniSkel.CmdArgs) (*AddPlan, error) {
+	nwCfg, err := cni.ParseNetworkConfig(args.StdinData)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse network configuration")
+	}
+	if err = plugin.validateArgs(args, nwCfg); err != nil {
+		return nil, err
+	}
+
+	k8sPodName, k8sNamespace, err := plugin.getPodInfo(args.Args)
+	if err != nil {
+		return nil, err
+	}
+	if args.ContainerID == "" {
+		return nil, errors.New("container ID not specified in CNI args")
+	}
+	if args.IfName == "" {
+		return nil, errors.New("interface name not specified in CNI args")
+	}
+
+	plan := &AddPlan{
+		ContainerID:  args.ContainerID,
+		Netns:        args.Netns,
+		IfName:       args.IfName,
+		PodName:      k8sPodName,
+		PodNamespace: k8sNamespace,
+		Network:      nwCfg.Name,
+		Mode:         nwCfg.Mode,
+	}
+
+	platformInit(nwCfg)
+	if nwCfg.ExecutionMode == string(util.Baremetal) {
+		plan.IPAMInvoker = "NnsClient"
+		plan.Notes = append(plan.Notes, fmt.Sprintf("the vnet agent configures the networking of pod %s with AddContainerNetworking", k8sPodName))
+		return plan, nil
+	}
+
+	if nwCfg.MultiTenancy {
+		plan.IPAMInvoker = "MultitenancyClient"
+		orchestratorContext, _ := json.Marshal(cns.KubernetesPodInfo{PodName: k8sPodName, PodNamespace: k8sNamespace})
+		plan.CNSCalls = append(plan.CNSCalls, PlannedCNSCall{API: "GetAllNetworkContainers", Request: json.RawMessage(orchestratorContext)})
+		plan.Notes = append(plan.Notes, "the endpoints of the network containers returned by CNS are not planned")
+		return plan, nil
+	}
+
+	hostIP, err := plugin.dryRunHostIPInfo(nwCfg)
+	if err != nil {
+		return nil, err
+	}
+	plan.PlaceholderAddresses = true
+
+	options := make(map[string]any)
+	ipamAddConfig := IPAMAddConfig{nwCfg: nwCfg, args: args, options: options}
+	var ipamAddResult IPAMAddResult
+	switch nwCfg.IPAM.Type {
+	case network.AzureCNS:
+		plan.IPAMInvoker = "CNSIPAMInvoker"
+		cnsClient := &dryRunCNSClient{hostIP: hostIP, ipamMode: util.IpamMode(nwCfg.IPAM.Mode)}
+		invoker := NewCNSInvoker(k8sPodName, k8sNamespace, cnsClient, util.ExecutionMode(nwCfg.ExecutionMode), util.IpamMode(nwCfg.IPAM.Mode))
+		ipamAddResult, err = invoker.Add(ipamAddConfig)
+		plan.CNSCalls = cnsClient.calls
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to plan IPAM")
+		}
+	default:
+		plan.IPAMInvoker = "AzureIPAMInvoker"
+		plan.Notes = append(plan.Notes, fmt.Sprintf("the addresses are allocated by the %s IPAM plugin", nwCfg.IPAM.Type))
+		_, hostSubnet, _ := net.ParseCIDR(hostIP.Subnet)
+		_, podSubnet, _ := net.ParseCIDR(fmt.Sprintf("%s/%d", dryRunPodIP, dryRunPrefixLength))
+		ipamAddResult = IPAMAddResult{interfaceInfo: map[string]network.InterfaceInfo{
+			string(cns.InfraNIC): {
+				IPConfigs: []*network.IPConfig{
+					{Address: net.IPNet{IP: net.ParseIP(dryRunPodIP), Mask: podSubnet.Mask}, Gateway: net.ParseIP(dryRunGateway)},
+				},
+				Routes:           []network.RouteInfo{{Dst: network.Ipv4DefaultRouteDstPrefix, Gw: net.ParseIP(dryRunGateway)}},
+				NICType:          cns.InfraNIC,
+				HostSubnetPrefix: *hostSubnet,
+			},
+		}}
+	}
+
+	policies := cni.GetPoliciesFromNwCfg(nwCfg.AdditionalArgs)
+	infraSeen := false
+	endpointIndex := 0
+	for key := range ipamAddResult.interfaceInfo {
+		ifInfo := ipamAddResult.interfaceInfo[key]
+		networkID, _ := plugin.getNetworkID(args.Netns, &ifInfo, nwCfg)
+
+		epInfo, err := plugin.createEpInfo(&createEpInfoOpt{
+			nwCfg:         nwCfg,
+			ipamAddResult: ipamAddResult,
+			args:          args,
+			policies:      policies,
+			k8sPodName:    k8sPodName,
+			k8sNamespace:  k8sNamespace,
+			natInfo:       getNATInfo(nwCfg, options[network.SNATIPKey], false),
+			networkID:     networkID,
+			ifInfo:        &ifInfo,
+			ipamAddConfig: &ipamAddConfig,
+			ipv6Enabled:   ipamAddResult.ipv6Enabled,
+			infraSeen:     &infraSeen,
+			endpointIndex: endpointIndex,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		epPlan, err := network.PlanEndpoint(epInfo)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to plan endpoint %s", epInfo.EndpointID)
+		}
+		plan.Endpoints = append(plan.Endpoints, epPlan)
+		endpointIndex++
+	}
+	return plan, nil
+}
+
+// HandleDryRun plans the ADD of the CNI args in the environment and prints the plan to stdout as JSON.
+func HandleDryRun(plugin *NetPlugin) error {
+	_, cmdArgs, err := getCmdArgsFromEnv()
+	if err != nil {
+		return err
+	}
+	plan, err := plugin.DryRunAdd(cmdArgs)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return errors.Wrap(enc.Encode(plan), "failed to print plan")
+}
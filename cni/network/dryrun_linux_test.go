@@ -0,0 +1,78 @@
+//go:build linux
+// +build linux
+
+package network
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Azure/azure-container-networking/cni"
+	acnnetwork "github.com/Azure/azure-container-networking/network"
+	cniSkel "github.com/containernetworking/cni/pkg/skel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDryRunAdd(t *testing.T) {
+	args := func(nwCfg cni.NetworkConfig) *cniSkel.CmdArgs {
+		return &cniSkel.CmdArgs{
+			StdinData:   nwCfg.Serialize(),
+			ContainerID: "abcdef12345",
+			Netns:       "/var/run/netns/pod",
+			Args:        fmt.Sprintf("K8S_POD_NAME=%v;K8S_POD_NAMESPACE=%v", "test-pod", "test-pod-ns"),
+			IfName:      eth0IfName,
+		}
+	}
+
+	t.Run("cns invoker", func(t *testing.T) {
+		nwCfg := cni.NetworkConfig{
+			Name:   "azure",
+			Mode:   OpModeTransparent,
+			Master: "lo",
+			IPAM:   cni.IPAM{Type: acnnetwork.AzureCNS, Mode: "v4overlay"},
+		}
+		plan, err := GetTestResources().DryRunAdd(args(nwCfg))
+		require.NoError(t, err)
+		assert.Equal(t, "CNSIPAMInvoker", plan.IPAMInvoker)
+		assert.True(t, plan.PlaceholderAddresses)
+		require.Len(t, plan.CNSCalls, 1)
+		assert.Equal(t, "RequestIPs", plan.CNSCalls[0].API)
+
+		require.Len(t, plan.Endpoints, 1)
+		ep := plan.Endpoints[0]
+		assert.Equal(t, "TransparentEndpointClient", ep.EndpointClient)
+		assert.Equal(t, []acnnetwork.PlannedAddress{{Link: eth0IfName, Netns: "/var/run/netns/pod", Address: dryRunPodIP + "/24"}}, ep.Addresses)
+	})
+
+	t.Run("azure ipam invoker", func(t *testing.T) {
+		nwCfg := cni.NetworkConfig{
+			Name:   "azure",
+			Master: "lo",
+			Bridge: "azure0",
+			IPAM:   cni.IPAM{Type: "azure-vnet-ipam"},
+		}
+		plan, err := GetTestResources().DryRunAdd(args(nwCfg))
+		require.NoError(t, err)
+		assert.Equal(t, "AzureIPAMInvoker", plan.IPAMInvoker)
+		assert.Empty(t, plan.CNSCalls)
+		require.Len(t, plan.Endpoints, 1)
+		assert.Equal(t, "LinuxBridgeEndpointClient", plan.Endpoints[0].EndpointClient)
+	})
+
+	t.Run("multitenancy", func(t *testing.T) {
+		nwCfg := cni.NetworkConfig{Name: "azure", MultiTenancy: true}
+		plan, err := GetTestResources().DryRunAdd(args(nwCfg))
+		require.NoError(t, err)
+		assert.Equal(t, "MultitenancyClient", plan.IPAMInvoker)
+		require.Len(t, plan.CNSCalls, 1)
+		assert.Empty(t, plan.Endpoints)
+	})
+
+	t.Run("missing pod args", func(t *testing.T) {
+		cmdArgs := args(cni.NetworkConfig{Name: "azure"})
+		cmdArgs.Args = ""
+		_, err := GetTestResources().DryRunAdd(cmdArgs)
+		require.Error(t, err)
+	})
+}
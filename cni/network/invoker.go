@@ -4,6 +4,7 @@ import (
 	"net"
 
 	"github.com/Azure/azure-container-networking/cni"
+	"github.com/Azure/azure-container-networking/cns"
 	"github.com/Azure/azure-container-networking/network"
 	cniSkel "github.com/containernetworking/cni/pkg/skel"
 )
@@ -29,6 +30,8 @@ type IPAMAddResult struct {
 	interfaceInfo map[string]network.InterfaceInfo
 	// ncResponse and host subnet prefix were moved into interface info
 	ipv6Enabled bool
+	// egressSNAT is the egress SNAT CNS chose for the pod, nil to leave it as configured for the network.
+	egressSNAT *cns.EgressSNAT
 }
 
 func (ipamAddResult IPAMAddResult) PrettyString() string {
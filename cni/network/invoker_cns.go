@@ -142,7 +142,7 @@ func (invoker *CNSIPAMInvoker) Add(addConfig IPAMAddConfig) (IPAMAddResult, erro
 		}
 	}
 
-	addResult := IPAMAddResult{interfaceInfo: make(map[string]network.InterfaceInfo)}
+	addResult := IPAMAddResult{interfaceInfo: make(map[string]network.InterfaceInfo), egressSNAT: response.EgressSNAT}
 	numInterfacesWithDefaultRoutes := 0
 
 	for i := 0; i < len(response.PodIPInfo); i++ {
@@ -42,12 +42,19 @@ var allowedInput = regexp.MustCompile(`^[a-zA-Z0-9._\-\(\) ]*$`)
 const (
 	dockerNetworkOption = "com.docker.network.generic"
 	OpModeTransparent   = "transparent"
+	// OpModeChained only adds the Azure host routes and SNAT to the interface set up by the previous plugin of a chain.
+	OpModeChained = "chained"
 	// Supported IP version. Currently support only IPv4
 	ipamV6                = "azure-vnet-ipamv6"
 	defaultRequestTimeout = 15 * time.Second
 	ipv4FullMask          = 32
 	ipv6FullMask          = 128
 	ibInterfacePrefix     = "ib"
+	// Bounds of the configured mtu, the IPv4 minimum and the largest a link can have.
+	minMTU = 68
+	maxMTU = 65535
+	// gcGracePeriod is how long GC leaves the endpoints of a new container alone
+	gcGracePeriod = 5 * time.Minute
 )
 
 // CNI Operation Types
@@ -82,6 +89,8 @@ type NetPlugin struct {
 	nnsClient          NnsClient
 	multitenancyClient MultitenancyClient
 	netClient          InterfaceGetter
+	// chainedDir records the endpoints of the chained mode.
+	chainedDir string
 }
 
 type PolicyArgs struct {
@@ -141,6 +150,7 @@ func NewPlugin(name string,
 		nnsClient:          client,
 		multitenancyClient: multitenancyClient,
 		netClient:          &netio.NetIO{},
+		chainedDir:         chainedEndpointsDir,
 	}, nil
 }
 
@@ -420,6 +430,11 @@ func (plugin *NetPlugin) Add(args *cniSkel.CmdArgs) error {
 	iptables.DisableIPTableLock = nwCfg.DisableIPTableLock
 	plugin.setCNIReportDetails(nwCfg, CNI_ADD, "")
 
+	if nwCfg.Mode == OpModeChained {
+		err = plugin.addChained(args, nwCfg)
+		return err
+	}
+
 	defer func() {
 		operationTimeMs := time.Since(startTime).Milliseconds()
 		cniMetric.Metric = aitelemetry.Metric{
@@ -448,6 +463,14 @@ func (plugin *NetPlugin) Add(args *cniSkel.CmdArgs) error {
 		// containerd receives each cniResult as the stdout and create pod
 		addSnatInterface(nwCfg, cniResult) //nolint TODO: check whether Linux supports adding secondary snatinterface
 
+		// report the effective MTU of the container interface
+		for _, epInfo := range epInfos {
+			if epInfo.NICType == cns.InfraNIC && epInfo.MTU != 0 && len(cniResult.Interfaces) > 0 {
+				cniResult.Interfaces[0].Mtu = epInfo.MTU
+				break
+			}
+		}
+
 		// add IB NIC interfaceInfo to cniResult
 		for _, epInfo := range epInfos {
 			if epInfo.NICType == cns.BackendNIC {
@@ -526,7 +549,7 @@ func (plugin *NetPlugin) Add(args *cniSkel.CmdArgs) error {
 		}
 	}
 
-	cnsClient, err := cnscli.New(nwCfg.CNSUrl, defaultRequestTimeout)
+	cnsClient, err := newCNSClient(nwCfg)
 	if err != nil {
 		return fmt.Errorf("failed to create cns client with error: %w", err)
 	}
@@ -644,7 +667,7 @@ func (plugin *NetPlugin) Add(args *cniSkel.CmdArgs) error {
 		//	ipamAddResult.interfaceInfo[ifIndex].IPConfigs, epInfo.Data[network.VlanIDKey], k8sPodName, k8sNamespace, plugin.nm.GetNumberOfEndpoints("", nwCfg.Name)))
 		endpointIndex++
 	}
-	cnsclient, err := cnscli.New(nwCfg.CNSUrl, defaultRequestTimeout)
+	cnsclient, err := newCNSClient(nwCfg)
 	if err != nil {
 		return errors.Wrap(err, "failed to create cns client")
 	}
@@ -693,6 +716,32 @@ func (plugin *NetPlugin) findMasterInterface(opt *createEpInfoOpt) string {
 	}
 }
 
+// getMTU returns the MTU of the bridge and endpoint interfaces from the network config: the configured MTU, else the
+// MTU of the master interface if auto-detection is enabled, else 0 to leave the default MTU.
+func (plugin *NetPlugin) getMTU(nwCfg *cni.NetworkConfig, masterIfName string) (int, error) {
+	if nwCfg.MTU != 0 {
+		if nwCfg.MTU < minMTU || nwCfg.MTU > maxMTU {
+			return 0, errors.Errorf("mtu %d must be between %d and %d", nwCfg.MTU, minMTU, maxMTU)
+		}
+		return nwCfg.MTU, nil
+	}
+	if !nwCfg.AutoDetectMTU {
+		return 0, nil
+	}
+
+	interfaces, err := plugin.netClient.GetNetworkInterfaces()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get interfaces")
+	}
+	for i := range interfaces {
+		if interfaces[i].Name == masterIfName {
+			logger.Info("Detected mtu of master interface", zap.String("masterIfName", masterIfName), zap.Int("MTU", interfaces[i].MTU))
+			return interfaces[i].MTU, nil
+		}
+	}
+	return 0, errors.Errorf("failed to detect mtu, master interface %s not found", masterIfName)
+}
+
 type createEpInfoOpt struct {
 	nwCfg            *cni.NetworkConfig
 	cnsNetworkConfig *cns.GetNetworkContainerResponse
@@ -833,6 +882,32 @@ func (plugin *NetPlugin) createEpInfo(opt *createEpInfoOpt) (*network.EndpointIn
 	}
 	endpointInfo.EndpointPolicies = append(endpointInfo.EndpointPolicies, epPolicies...)
 
+	if opt.ifInfo.NICType == cns.InfraNIC {
+		if endpointInfo.PortMappings, err = getPortMappings(opt.nwCfg); err != nil {
+			logger.Error("failed to get port mappings from runtime configurations", zap.Error(err))
+			return nil, plugin.Errorf(err.Error())
+		}
+		if endpointInfo.Bandwidth, err = getBandwidth(opt.nwCfg); err != nil {
+			logger.Error("failed to get bandwidth from runtime configurations", zap.Error(err))
+			return nil, plugin.Errorf(err.Error())
+		}
+		if endpointInfo.Tuning, err = getTuning(networkPolicies); err != nil {
+			logger.Error("failed to get tuning from network configuration", zap.Error(err))
+			return nil, plugin.Errorf(err.Error())
+		}
+		if endpointInfo.MTU, err = plugin.getMTU(opt.nwCfg, masterIfName); err != nil {
+			logger.Error("failed to get mtu", zap.Error(err))
+			return nil, plugin.Errorf(err.Error())
+		}
+		if opt.nwCfg.TSO != nil || opt.nwCfg.GRO != nil {
+			endpointInfo.Offloads = &network.Offloads{TSO: opt.nwCfg.TSO, GRO: opt.nwCfg.GRO}
+		}
+		if endpointInfo.EgressSNAT, err = plugin.getEgressSNAT(opt.ipamAddResult.egressSNAT, opt.nwCfg, endpointInfo.Subnets); err != nil {
+			logger.Error("failed to get egress snat", zap.Error(err))
+			return nil, plugin.Errorf(err.Error())
+		}
+	}
+
 	if opt.ipamAddResult.ipv6Enabled { // not specific to this particular interface
 		endpointInfo.IPV6Mode = string(util.IpamMode(opt.nwCfg.IPAM.Mode)) // TODO: check IPV6Mode field can be deprecated and can we add IsIPv6Enabled flag for generic working
 	}
@@ -993,6 +1068,212 @@ func (plugin *NetPlugin) Get(args *cniSkel.CmdArgs) error {
 	return nil
 }
 
+// Check handles CNI check commands by verifying that the datapath recorded for the endpoint still exists on the host.
+// Every drifted resource is listed in the details of the returned CNI error.
+func (plugin *NetPlugin) Check(args *cniSkel.CmdArgs) error {
+	var (
+		err       error
+		nwCfg     *cni.NetworkConfig
+		networkID string
+		drifts    []network.DatapathDrift
+	)
+
+	logger.Info("Processing CHECK command",
+		zap.String("container", args.ContainerID),
+		zap.String("netns", args.Netns),
+		zap.String("ifname", args.IfName),
+		zap.String("args", args.Args),
+		zap.String("path", args.Path))
+
+	defer func() {
+		logger.Info("CHECK command completed", zap.Any("drifts", drifts), zap.Error(err))
+	}()
+
+	// Parse network configuration from stdin.
+	if nwCfg, err = cni.ParseNetworkConfig(args.StdinData); err != nil {
+		err = plugin.Errorf("Failed to parse network configuration: %v.", err)
+		return err
+	}
+
+	if argErr := plugin.validateArgs(args, nwCfg); argErr != nil {
+		err = argErr
+		return err
+	}
+
+	iptables.DisableIPTableLock = nwCfg.DisableIPTableLock
+
+	if networkID, err = plugin.getNetworkName(args.Netns, nil, nwCfg); err != nil {
+		logger.Error("Failed to extract network name from network config",
+			zap.Error(err))
+	}
+
+	endpointID := GetEndpointID(args)
+
+	// chained endpoints are only recorded for DEL and GC, the previous plugin of the chain reports what to verify.
+	if nwCfg.Mode == OpModeChained {
+		drifts, err = plugin.verifyChained(args, nwCfg)
+	} else {
+		drifts, err = plugin.nm.VerifyEndpoint(networkID, endpointID)
+	}
+	if err != nil {
+		err = plugin.Errorf("Failed to verify endpoint %s: %v", endpointID, err)
+		return err
+	}
+
+	if len(drifts) > 0 {
+		details, _ := json.Marshal(drifts)
+		err = plugin.Error(cniTypes.NewError(cni.ErrDatapathDrift,
+			fmt.Sprintf("datapath of endpoint %s drifted from recorded state in %d resources", endpointID, len(drifts)), string(details)))
+		return err
+	}
+
+	if nwCfg.IPAM.Type == network.AzureCNS {
+		if err = plugin.renewCNSIPLeases(args, nwCfg); err != nil {
+			err = plugin.Errorf("Failed to renew IP leases for endpoint %s: %v", endpointID, err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// renewCNSIPLeases tells CNS that the pod still holds its IPs so that the lease sweeper does not reclaim them.
+func (plugin *NetPlugin) renewCNSIPLeases(args *cniSkel.CmdArgs, nwCfg *cni.NetworkConfig) error {
+	k8sPodName, k8sNamespace, err := plugin.getPodInfo(args.Args)
+	if err != nil {
+		return err
+	}
+
+	orchestratorContext, err := json.Marshal(cns.KubernetesPodInfo{
+		PodName:      k8sPodName,
+		PodNamespace: k8sNamespace,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal orchestrator context")
+	}
+
+	cnsClient, err := newCNSClient(nwCfg)
+	if err != nil {
+		return errors.Wrap(err, "failed to create cns client")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultRequestTimeout)
+	defer cancel()
+
+	err = cnsClient.RenewIPLeases(ctx, cns.IPConfigsRequest{
+		OrchestratorContext: orchestratorContext,
+		PodInterfaceID:      GetEndpointID(args),
+		InfraContainerID:    args.ContainerID,
+	})
+	// older CNS versions do not lease IPs, so there is nothing to renew
+	if err != nil && !cnscli.IsUnsupportedAPI(err) {
+		return errors.Wrap(err, "failed to renew ip leases")
+	}
+
+	return nil
+}
+
+// Status handles CNI status commands by checking that CNS and the host network are ready to serve ADD requests.
+func (plugin *NetPlugin) Status(args *cniSkel.CmdArgs) error {
+	var (
+		err   error
+		nwCfg *cni.NetworkConfig
+	)
+
+	logger.Info("Processing STATUS command")
+
+	defer func() {
+		logger.Info("STATUS command completed", zap.Error(err))
+	}()
+
+	// Parse network configuration from stdin.
+	if nwCfg, err = cni.ParseNetworkConfig(args.StdinData); err != nil {
+		err = plugin.Errorf("Failed to parse network configuration: %v.", err)
+		return err
+	}
+
+	if nwCfg.IPAM.Type == network.AzureCNS {
+		if cnsErr := plugin.checkCNSStatus(nwCfg); cnsErr != nil {
+			err = plugin.Error(cniTypes.NewError(cni.ErrPluginNotAvailable, "CNS is not ready", cnsErr.Error()))
+			return err
+		}
+	}
+
+	if hostErr := plugin.checkHostNetworkStatus(nwCfg); hostErr != nil {
+		err = plugin.Error(cniTypes.NewError(cni.ErrLimitedConnectivity, "host network is not ready", hostErr.Error()))
+		return err
+	}
+
+	return nil
+}
+
+// newCNSClient returns a CNS client for the network config, which uses the CNS gRPC API when an address for it is configured.
+func newCNSClient(nwCfg *cni.NetworkConfig) (*cnscli.Client, error) {
+	if nwCfg.CNSGRPCAddress != "" {
+		return cnscli.NewWithGRPC(nwCfg.CNSUrl, nwCfg.CNSGRPCAddress, defaultRequestTimeout) //nolint:wrapcheck // callers wrap the error
+	}
+	return cnscli.New(nwCfg.CNSUrl, defaultRequestTimeout) //nolint:wrapcheck // callers wrap the error
+}
+
+// checkCNSStatus verifies that CNS is reachable and serving requests.
+func (plugin *NetPlugin) checkCNSStatus(nwCfg *cni.NetworkConfig) error {
+	cnsClient, err := newCNSClient(nwCfg)
+	if err != nil {
+		return errors.Wrap(err, "failed to create cns client")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultRequestTimeout)
+	defer cancel()
+
+	if _, err := cnsClient.GetHTTPServiceData(ctx); err != nil {
+		return errors.Wrap(err, "failed to query cns")
+	}
+
+	return nil
+}
+
+// checkHostNetworkStatus verifies that the master interface and, if one was created, the bridge are present and up.
+func (plugin *NetPlugin) checkHostNetworkStatus(nwCfg *cni.NetworkConfig) error {
+	var required []string
+	if nwCfg.Master != "" {
+		required = append(required, nwCfg.Master)
+	}
+
+	if networkID, err := plugin.getNetworkName("", nil, nwCfg); err == nil {
+		if nwInfo, err := plugin.nm.GetNetworkInfo(networkID); err == nil && nwInfo.BridgeName != "" {
+			required = append(required, nwInfo.BridgeName)
+		}
+	}
+
+	if len(required) == 0 {
+		return nil
+	}
+
+	interfaces, err := plugin.netClient.GetNetworkInterfaces()
+	if err != nil {
+		return errors.Wrap(err, "failed to get interfaces")
+	}
+
+	for _, name := range required {
+		found := false
+		for i := range interfaces {
+			if interfaces[i].Name != name {
+				continue
+			}
+			if interfaces[i].Flags&net.FlagUp == 0 {
+				return errors.Errorf("interface %s is down", name)
+			}
+			found = true
+			break
+		}
+		if !found {
+			return errors.Errorf("interface %s not found", name)
+		}
+	}
+
+	return nil
+}
+
 // Delete handles CNI delete commands.
 func (plugin *NetPlugin) Delete(args *cniSkel.CmdArgs) error {
 	var (
@@ -1044,6 +1325,11 @@ func (plugin *NetPlugin) Delete(args *cniSkel.CmdArgs) error {
 
 	iptables.DisableIPTableLock = nwCfg.DisableIPTableLock
 
+	if nwCfg.Mode == OpModeChained {
+		err = plugin.deleteChained(args, nwCfg)
+		return err
+	}
+
 	sendMetricFunc := func() {
 		operationTimeMs := time.Since(startTime).Milliseconds()
 		cniMetric.Metric = aitelemetry.Metric{
@@ -1071,6 +1357,8 @@ func (plugin *NetPlugin) Delete(args *cniSkel.CmdArgs) error {
 	if plugin.ipamInvoker == nil {
 		switch nwCfg.IPAM.Type {
 		case network.AzureCNS:
+			// DEL keeps the HTTP client on the default CNS URL, as it always has, so that IPs are released
+			// whether or not CNS serves the gRPC API configured for ADD.
 			cnsClient, cnsErr := cnscli.New("", defaultRequestTimeout)
 			if cnsErr != nil {
 				logger.Error("failed to create cns client", zap.Error(cnsErr))
@@ -1198,6 +1486,127 @@ func (plugin *NetPlugin) Delete(args *cniSkel.CmdArgs) error {
 	return err
 }
 
+// GC handles CNI gc commands by deleting every endpoint in the network whose container is not in the
+// runtime's list of valid attachments and is older than gcGracePeriod, along with its host datapath and IP addresses.
+func (plugin *NetPlugin) GC(args *cniSkel.CmdArgs) error {
+	var (
+		err       error
+		nwCfg     *cni.NetworkConfig
+		networkID string
+		eps       []*network.EndpointInfo
+		collected []string
+	)
+
+	logger.Info("Processing GC command", zap.ByteString("stdinData", args.StdinData))
+
+	defer func() {
+		logger.Info("GC command completed", zap.Strings("collected", collected), zap.Error(err))
+	}()
+
+	// Parse network configuration from stdin.
+	if nwCfg, err = cni.ParseNetworkConfig(args.StdinData); err != nil {
+		err = plugin.Errorf("Failed to parse network configuration: %v.", err)
+		return err
+	}
+
+	valid := make(map[cniTypes.GCAttachment]struct{}, len(nwCfg.ValidAttachments))
+	for _, attachment := range nwCfg.ValidAttachments {
+		valid[attachment] = struct{}{}
+	}
+
+	if nwCfg.Mode == OpModeChained {
+		if collected, err = plugin.gcChained(nwCfg, valid); err != nil {
+			err = plugin.RetriableError(fmt.Errorf("failed to collect chained endpoints: %w", err))
+			return err
+		}
+		return nil
+	}
+
+	if networkID, err = plugin.getNetworkName("", nil, nwCfg); err != nil {
+		logger.Error("Failed to extract network name from network config", zap.Error(err))
+		networkID = nwCfg.Name
+	}
+
+	if eps, err = plugin.listEndpoints(networkID); err != nil {
+		if errors.Is(err, store.ErrStoreEmpty) || network.IsNetworkNotFoundError(err) {
+			err = nil
+			return nil
+		}
+		err = plugin.Errorf("Failed to list endpoints of network %s: %v", networkID, err)
+		return err
+	}
+
+	// A container may own several endpoints, such as delegated NICs besides the infra one, and DEL removes
+	// all of them at once. It is only stale when none of its endpoints is a valid attachment.
+	stale := make(map[string]*network.EndpointInfo)
+	inUse := make(map[string]struct{})
+	for _, epInfo := range eps {
+		if epInfo.ContainerID == "" {
+			continue
+		}
+		if _, ok := valid[cniTypes.GCAttachment{ContainerID: epInfo.ContainerID, IfName: epInfo.IfName}]; ok {
+			inUse[epInfo.ContainerID] = struct{}{}
+			continue
+		}
+		// an ADD may still be in flight when the runtime builds its list of valid attachments
+		if time.Since(epInfo.Created) < gcGracePeriod {
+			inUse[epInfo.ContainerID] = struct{}{}
+			continue
+		}
+		if _, ok := stale[epInfo.ContainerID]; !ok || epInfo.NICType == cns.InfraNIC {
+			stale[epInfo.ContainerID] = epInfo
+		}
+	}
+	for containerID := range inUse {
+		delete(stale, containerID)
+	}
+
+	// The cached IPAM invoker carries the pod identity of the first DEL, so rebuild it per container unless injected.
+	resetInvoker := plugin.ipamInvoker == nil
+	for containerID, epInfo := range stale {
+		logger.Info("Collecting stale endpoint", zap.String("containerID", containerID), zap.String("endpointID", epInfo.EndpointID),
+			zap.String("pod", epInfo.PODName), zap.String("namespace", epInfo.PODNameSpace))
+
+		delArgs := &cniSkel.CmdArgs{
+			ContainerID: containerID,
+			Netns:       epInfo.NetNsPath,
+			IfName:      epInfo.IfName,
+			Args:        fmt.Sprintf("K8S_POD_NAME=%s;K8S_POD_NAMESPACE=%s", epInfo.PODName, epInfo.PODNameSpace),
+			Path:        args.Path,
+			StdinData:   args.StdinData,
+		}
+
+		if resetInvoker {
+			plugin.ipamInvoker = nil
+		}
+
+		if delErr := plugin.Delete(delArgs); delErr != nil {
+			err = plugin.RetriableError(fmt.Errorf("failed to collect endpoints of container %s: %w", containerID, delErr))
+			return err
+		}
+
+		collected = append(collected, containerID)
+	}
+
+	return nil
+}
+
+// listEndpoints returns the endpoints of the network, which CNS keeps in stateless mode.
+func (plugin *NetPlugin) listEndpoints(networkID string) ([]*network.EndpointInfo, error) {
+	if plugin.nm.IsStatelessCNIMode() {
+		return plugin.nm.GetAllEndpointState(networkID) //nolint:wrapcheck // the caller adds the context
+	}
+	eps, err := plugin.nm.GetAllEndpoints(networkID)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // the caller checks for the store and network errors
+	}
+	epInfos := make([]*network.EndpointInfo, 0, len(eps))
+	for _, epInfo := range eps {
+		epInfos = append(epInfos, epInfo)
+	}
+	return epInfos, nil
+}
+
 // Update handles CNI update commands.
 // Update is only supported for multitenancy and to update routes.
 func (plugin *NetPlugin) Update(args *cniSkel.CmdArgs) error {
@@ -1325,7 +1734,7 @@ func (plugin *NetPlugin) Update(args *cniSkel.CmdArgs) error {
 		return plugin.Errorf(err.Error())
 	}
 
-	cnsclient, err := cnscli.New(nwCfg.CNSUrl, defaultRequestTimeout)
+	cnsclient, err := newCNSClient(nwCfg)
 	if err != nil {
 		logger.Error("failed to initialized cns client",
 			zap.String("url", nwCfg.CNSUrl),
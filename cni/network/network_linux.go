@@ -1,14 +1,20 @@
 package network
 
 import (
+	"encoding/json"
+	"math"
 	"net"
+	"net/netip"
 	"strconv"
+	"strings"
 
 	"github.com/Azure/azure-container-networking/cni"
 	"github.com/Azure/azure-container-networking/cns"
+	"github.com/Azure/azure-container-networking/iptables"
 	"github.com/Azure/azure-container-networking/network"
 	"github.com/Azure/azure-container-networking/network/policy"
 	cniTypesCurr "github.com/containernetworking/cni/pkg/types/100"
+	"github.com/pkg/errors"
 )
 
 const (
@@ -108,6 +114,70 @@ func getPoliciesFromRuntimeCfg(_ *cni.NetworkConfig, _ bool) ([]policy.Policy, e
 	return nil, nil
 }
 
+// getPortMappings returns the host ports to forward to the endpoint from the runtime config, which the runtime only
+// passes if azure-vnet declares the portMappings capability in the conflist.
+func getPortMappings(nwCfg *cni.NetworkConfig) ([]network.PortMapping, error) {
+	var mappings []network.PortMapping
+	for _, m := range nwCfg.RuntimeConfig.PortMappings {
+		protocol := strings.ToLower(strings.TrimSpace(m.Protocol))
+		switch protocol {
+		case "":
+			protocol = iptables.TCP
+		case iptables.TCP, iptables.UDP, iptables.SCTP:
+		default:
+			return nil, errors.Errorf("unsupported protocol %q for host port %d", m.Protocol, m.HostPort)
+		}
+		if m.HostPort <= 0 || m.HostPort > 65535 || m.ContainerPort <= 0 || m.ContainerPort > 65535 {
+			return nil, errors.Errorf("invalid port mapping %d:%d", m.HostPort, m.ContainerPort)
+		}
+		if m.HostIp != "" {
+			if _, err := netip.ParseAddr(m.HostIp); err != nil {
+				return nil, errors.Wrapf(err, "invalid host IP for host port %d", m.HostPort)
+			}
+		}
+		mappings = append(mappings, network.PortMapping{
+			HostPort:      m.HostPort,
+			ContainerPort: m.ContainerPort,
+			Protocol:      protocol,
+			HostIP:        m.HostIp,
+		})
+	}
+	return mappings, nil
+}
+
+// getBandwidth returns the shaping of the endpoint traffic from the runtime config, which the runtime only passes if
+// azure-vnet declares the bandwidth capability in the conflist.
+func getBandwidth(nwCfg *cni.NetworkConfig) (*network.Bandwidth, error) {
+	bw := nwCfg.RuntimeConfig.Bandwidth
+	if bw == nil || (bw.IngressRate == 0 && bw.EgressRate == 0) {
+		return nil, nil
+	}
+	for _, limit := range []struct {
+		direction   string
+		rate, burst uint64
+	}{
+		{"ingress", bw.IngressRate, bw.IngressBurst},
+		{"egress", bw.EgressRate, bw.EgressBurst},
+	} {
+		if limit.rate == 0 {
+			continue
+		}
+		// the token bucket holds whole bytes, up to 4GiB
+		if limit.rate < 8 || limit.burst < 8 {
+			return nil, errors.Errorf("%s rate %d and burst %d must be at least 8 bits", limit.direction, limit.rate, limit.burst)
+		}
+		if limit.burst/8 > math.MaxUint32 {
+			return nil, errors.Errorf("%s burst %d must be at most %d bits", limit.direction, limit.burst, uint64(math.MaxUint32)*8)
+		}
+	}
+	return &network.Bandwidth{
+		IngressRate:  bw.IngressRate,
+		IngressBurst: bw.IngressBurst,
+		EgressRate:   bw.EgressRate,
+		EgressBurst:  bw.EgressBurst,
+	}, nil
+}
+
 func addIPV6EndpointPolicy(nwInfo network.NetworkInfo) (policy.Policy, error) {
 	return policy.Policy{}, nil
 }
@@ -130,3 +200,93 @@ func (plugin *NetPlugin) isDualNicFeatureSupported(netNs string) bool {
 func getOverlayGateway(_ *net.IPNet) (net.IP, error) {
 	return net.ParseIP("169.254.1.1"), nil
 }
+
+// getTuning returns the tuning of the container network namespace from the Tuning endpoint policies in the
+// AdditionalArgs. The sysctls of all of them are applied, later policies winning.
+func getTuning(policies []policy.Policy) (*network.Tuning, error) {
+	var tuning *network.Tuning
+	for _, p := range policies {
+		if p.Type != policy.EndpointPolicy {
+			continue
+		}
+		var data policy.KVPairTuning
+		if err := json.Unmarshal(p.Data, &data); err != nil || data.Type != policy.TuningPolicy {
+			continue
+		}
+		if tuning == nil {
+			tuning = &network.Tuning{Sysctls: make(map[string]string)}
+		}
+		for key, value := range data.Sysctls {
+			tuning.Sysctls[key] = value
+		}
+		tuning.Promisc = tuning.Promisc || data.Promisc
+		if data.TxQueueLen != 0 {
+			tuning.TxQueueLen = data.TxQueueLen
+		}
+	}
+	if tuning == nil {
+		return nil, nil
+	}
+	if err := tuning.Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid tuning policy")
+	}
+	return tuning, nil
+}
+
+// getEgressSNAT returns the egress SNAT of the endpoint from the one CNS chose for the pod. Traffic to the endpoint
+// subnets and the vnet CIDRs stays in the vnet and is left as configured for the network. A secondary IP must be an
+// address of the host, so the replies to the SNATed traffic reach it.
+func (plugin *NetPlugin) getEgressSNAT(egressSNAT *cns.EgressSNAT, nwCfg *cni.NetworkConfig, subnets []network.SubnetInfo) (*network.EgressSNAT, error) {
+	if egressSNAT == nil {
+		return nil, nil
+	}
+	if err := egressSNAT.Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid egress snat from cns")
+	}
+
+	if egressSNAT.Mode == cns.EgressSNATSecondaryIP {
+		ip := net.ParseIP(egressSNAT.IPAddress)
+		local, err := plugin.isLocalIP(ip)
+		if err != nil {
+			return nil, err
+		}
+		if !local {
+			return nil, errors.Errorf("egress snat ip %s is not an address of the host", ip)
+		}
+	}
+
+	except := make([]string, 0, len(subnets))
+	for i := range subnets {
+		except = append(except, subnets[i].Prefix.String())
+	}
+	for _, cidr := range strings.Split(nwCfg.VnetCidrs, ",") {
+		if cidr = strings.TrimSpace(cidr); cidr == "" {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return nil, errors.Wrapf(err, "invalid vnet cidr %s", cidr)
+		}
+		except = append(except, cidr)
+	}
+	return &network.EgressSNAT{Mode: egressSNAT.Mode, IPAddress: egressSNAT.IPAddress, Except: except}, nil
+}
+
+// isLocalIP returns whether the IP is an address of one of the host interfaces.
+func (plugin *NetPlugin) isLocalIP(ip net.IP) (bool, error) {
+	interfaces, err := plugin.netClient.GetNetworkInterfaces()
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get interfaces")
+	}
+	for i := range interfaces {
+		addrs, err := interfaces[i].Addrs()
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to get addresses of %s", interfaces[i].Name)
+		}
+		for _, addr := range addrs {
+			if ipNet, ok := addr.(*net.IPNet); ok && ipNet.IP.Equal(ip) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
@@ -4,10 +4,15 @@
 package network
 
 import (
+	"encoding/json"
+	"math"
+	"net"
 	"testing"
 
+	"github.com/Azure/azure-container-networking/cni"
 	"github.com/Azure/azure-container-networking/cns"
 	"github.com/Azure/azure-container-networking/network"
+	"github.com/Azure/azure-container-networking/network/policy"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -160,3 +165,155 @@ func TestAddSnatForDns(t *testing.T) {
 		})
 	}
 }
+
+func TestGetPortMappings(t *testing.T) {
+	tests := []struct {
+		name     string
+		mappings []cni.PortMapping
+		want     []network.PortMapping
+		wantErr  bool
+	}{
+		{
+			name: "default and upper case protocols",
+			mappings: []cni.PortMapping{
+				{HostPort: 8080, ContainerPort: 80},
+				{HostPort: 5353, ContainerPort: 53, Protocol: "UDP", HostIp: "10.0.0.4"},
+			},
+			want: []network.PortMapping{
+				{HostPort: 8080, ContainerPort: 80, Protocol: "tcp"},
+				{HostPort: 5353, ContainerPort: 53, Protocol: "udp", HostIP: "10.0.0.4"},
+			},
+		},
+		{
+			name:     "unsupported protocol",
+			mappings: []cni.PortMapping{{HostPort: 8080, ContainerPort: 80, Protocol: "icmp"}},
+			wantErr:  true,
+		},
+		{
+			name:     "invalid port",
+			mappings: []cni.PortMapping{{HostPort: 70000, ContainerPort: 80}},
+			wantErr:  true,
+		},
+		{
+			name:     "invalid host IP",
+			mappings: []cni.PortMapping{{HostPort: 8080, ContainerPort: 80, HostIp: "node"}},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getPortMappings(&cni.NetworkConfig{RuntimeConfig: cni.RuntimeConfig{PortMappings: tt.mappings}})
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGetBandwidth(t *testing.T) {
+	tests := []struct {
+		name      string
+		bandwidth *cni.RuntimeBandwidth
+		want      *network.Bandwidth
+		wantErr   bool
+	}{
+		{
+			name: "no bandwidth",
+		},
+		{
+			name:      "no rates",
+			bandwidth: &cni.RuntimeBandwidth{IngressBurst: 1000},
+		},
+		{
+			name:      "ingress only",
+			bandwidth: &cni.RuntimeBandwidth{IngressRate: 1000000, IngressBurst: math.MaxUint32},
+			want:      &network.Bandwidth{IngressRate: 1000000, IngressBurst: math.MaxUint32},
+		},
+		{
+			name:      "rate without burst",
+			bandwidth: &cni.RuntimeBandwidth{EgressRate: 1000000},
+			wantErr:   true,
+		},
+		{
+			name:      "burst too large",
+			bandwidth: &cni.RuntimeBandwidth{EgressRate: 1000000, EgressBurst: math.MaxUint64},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getBandwidth(&cni.NetworkConfig{RuntimeConfig: cni.RuntimeConfig{Bandwidth: tt.bandwidth}})
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGetTuning(t *testing.T) {
+	outboundNAT := policy.Policy{Type: policy.EndpointPolicy, Data: json.RawMessage(`{"Type": "OutBoundNAT", "ExceptionList": ["10.0.0.0/8"]}`)}
+
+	tuning, err := getTuning([]policy.Policy{outboundNAT})
+	require.NoError(t, err)
+	assert.Nil(t, tuning)
+
+	tuning, err = getTuning([]policy.Policy{
+		outboundNAT,
+		{Type: policy.EndpointPolicy, Data: json.RawMessage(`{"Type": "Tuning", "Sysctls": {"net.core.somaxconn": "1024", "net.ipv4.tcp_fin_timeout": "30"}, "TxQueueLen": 500}`)},
+		{Type: policy.EndpointPolicy, Data: json.RawMessage(`{"Type": "Tuning", "Sysctls": {"net.core.somaxconn": "4096"}, "Promisc": true}`)},
+		// only endpoint policies tune the container
+		{Type: policy.NetworkPolicy, Data: json.RawMessage(`{"Type": "Tuning", "Sysctls": {"net.ipv4.tcp_syncookies": "0"}}`)},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &network.Tuning{
+		Sysctls:    map[string]string{"net.core.somaxconn": "4096", "net.ipv4.tcp_fin_timeout": "30"},
+		Promisc:    true,
+		TxQueueLen: 500,
+	}, tuning)
+
+	_, err = getTuning([]policy.Policy{
+		{Type: policy.EndpointPolicy, Data: json.RawMessage(`{"Type": "Tuning", "Sysctls": {"net.core.rmem_max": "16777216"}}`)},
+	})
+	require.Error(t, err, "host-scoped sysctls should be rejected")
+}
+
+func TestGetEgressSNAT(t *testing.T) {
+	lo, err := net.InterfaceByName("lo")
+	require.NoError(t, err)
+	plugin := &NetPlugin{netClient: &InterfaceGetterMock{interfaces: []net.Interface{*lo}}}
+	nwCfg := &cni.NetworkConfig{VnetCidrs: "10.0.0.0/8, 172.16.0.0/12"}
+	_, podSubnet, _ := net.ParseCIDR("10.240.0.0/16")
+	subnets := []network.SubnetInfo{{Prefix: *podSubnet}}
+
+	egressSNAT, err := plugin.getEgressSNAT(nil, nwCfg, subnets)
+	require.NoError(t, err)
+	assert.Nil(t, egressSNAT)
+
+	egressSNAT, err = plugin.getEgressSNAT(&cns.EgressSNAT{Mode: cns.EgressSNATNone}, nwCfg, subnets)
+	require.NoError(t, err)
+	assert.Equal(t, &network.EgressSNAT{
+		Mode:   cns.EgressSNATNone,
+		Except: []string{"10.240.0.0/16", "10.0.0.0/8", "172.16.0.0/12"},
+	}, egressSNAT)
+
+	egressSNAT, err = plugin.getEgressSNAT(&cns.EgressSNAT{Mode: cns.EgressSNATSecondaryIP, IPAddress: "127.0.0.1"}, &cni.NetworkConfig{}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, &network.EgressSNAT{Mode: cns.EgressSNATSecondaryIP, IPAddress: "127.0.0.1", Except: []string{}}, egressSNAT)
+
+	// the secondary ip must be an address of the host
+	_, err = plugin.getEgressSNAT(&cns.EgressSNAT{Mode: cns.EgressSNATSecondaryIP, IPAddress: "192.0.2.10"}, nwCfg, subnets)
+	require.ErrorContains(t, err, "not an address of the host")
+
+	_, err = plugin.getEgressSNAT(&cns.EgressSNAT{Mode: "masquerade"}, nwCfg, subnets)
+	require.Error(t, err)
+	_, err = plugin.getEgressSNAT(&cns.EgressSNAT{Mode: cns.EgressSNATNone}, &cni.NetworkConfig{VnetCidrs: "vnet"}, subnets)
+	require.Error(t, err)
+}
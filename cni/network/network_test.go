@@ -1,11 +1,15 @@
 package network
 
 import (
+	"encoding/json"
 	"fmt"
 	"net"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"runtime"
 	"testing"
+	"time"
 
 	"github.com/Azure/azure-container-networking/cni"
 	"github.com/Azure/azure-container-networking/cni/api"
@@ -18,6 +22,7 @@ import (
 	"github.com/Azure/azure-container-networking/nns"
 	"github.com/Azure/azure-container-networking/telemetry"
 	cniSkel "github.com/containernetworking/cni/pkg/skel"
+	cniTypes "github.com/containernetworking/cni/pkg/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -606,6 +611,212 @@ func TestPluginGet(t *testing.T) {
 	}
 }
 
+func TestPluginCheck(t *testing.T) {
+	plugin := GetTestResources()
+
+	// CHECK renews the IP leases of the pod in CNS.
+	var renewReq cns.IPConfigsRequest
+	cnsServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != cns.RenewIPLeases {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewDecoder(r.Body).Decode(&renewReq)
+		_ = json.NewEncoder(w).Encode(cns.Response{ReturnCode: 0})
+	}))
+	defer cnsServer.Close()
+
+	localNwCfg := nwCfg
+	localNwCfg.CNSUrl = cnsServer.URL
+	localArgs := *args
+	localArgs.StdinData = localNwCfg.Serialize()
+	args := &localArgs
+
+	// Check before add fails since there is no endpoint to verify.
+	err := plugin.Check(args)
+	require.Error(t, err)
+
+	require.NoError(t, plugin.Add(args))
+	require.NoError(t, plugin.Check(args))
+	assert.Equal(t, args.ContainerID, renewReq.InfraContainerID)
+	assert.Equal(t, GetEndpointID(args), renewReq.PodInterfaceID)
+
+	mockNetworkManager := plugin.nm.(*acnnetwork.MockNetworkManager)
+	mockNetworkManager.TestDriftMap[GetEndpointID(args)] = []acnnetwork.DatapathDrift{
+		{Resource: acnnetwork.DriftResourceHostInterface, Name: "azv1", Reason: "not found"},
+	}
+
+	err = plugin.Check(args)
+	require.Error(t, err)
+	var cniErr *cniTypes.Error
+	require.ErrorAs(t, err, &cniErr)
+	assert.Equal(t, uint(cni.ErrDatapathDrift), cniErr.Code)
+	assert.Contains(t, cniErr.Details, "azv1")
+}
+
+func TestPluginStatus(t *testing.T) {
+	localNwCfg := nwCfg
+	localNwCfg.IPAM.Type = "azure-vnet-ipam"
+
+	tests := []struct {
+		name       string
+		interfaces []net.Interface
+		wantCode   uint
+	}{
+		{
+			name:       "master interface up",
+			interfaces: []net.Interface{{Name: eth0IfName, Flags: net.FlagUp}},
+		},
+		{
+			name:       "master interface down",
+			interfaces: []net.Interface{{Name: eth0IfName}},
+			wantCode:   cni.ErrLimitedConnectivity,
+		},
+		{
+			name:     "master interface missing",
+			wantCode: cni.ErrLimitedConnectivity,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			plugin := GetTestResources()
+			plugin.netClient = &InterfaceGetterMock{interfaces: tt.interfaces}
+
+			err := plugin.Status(&cniSkel.CmdArgs{StdinData: localNwCfg.Serialize()})
+			if tt.wantCode == 0 {
+				require.NoError(t, err)
+				return
+			}
+			var cniErr *cniTypes.Error
+			require.ErrorAs(t, err, &cniErr)
+			assert.Equal(t, tt.wantCode, cniErr.Code)
+		})
+	}
+}
+
+func TestPluginGC(t *testing.T) {
+	plugin := GetTestResources()
+	invoker := plugin.ipamInvoker.(*MockIpamInvoker)
+
+	keepArgs := *args
+	keepArgs.ContainerID = "keep-container-1"
+	keepArgs.Netns = "keep-netns"
+	staleArgs := *args
+	staleArgs.ContainerID = "stale-container-1"
+	staleArgs.Netns = "stale-netns"
+
+	require.NoError(t, plugin.Add(&keepArgs))
+	require.NoError(t, plugin.Add(&staleArgs))
+	require.Len(t, invoker.ipMap, 2)
+
+	gcCfg := nwCfg
+	gcCfg.CNIVersion = "1.1.0"
+	gcCfg.ValidAttachments = []cniTypes.GCAttachment{{ContainerID: keepArgs.ContainerID, IfName: eth0IfName}}
+
+	require.NoError(t, plugin.GC(&cniSkel.CmdArgs{StdinData: gcCfg.Serialize()}))
+
+	endpoints, _ := plugin.nm.GetAllEndpoints(nwCfg.Name)
+	require.Len(t, endpoints, 1)
+	for _, ep := range endpoints {
+		assert.Equal(t, keepArgs.ContainerID, ep.ContainerID)
+	}
+	assert.Len(t, invoker.ipMap, 1)
+
+	// A second pass with nothing stale is a no-op.
+	require.NoError(t, plugin.GC(&cniSkel.CmdArgs{StdinData: gcCfg.Serialize()}))
+	endpoints, _ = plugin.nm.GetAllEndpoints(nwCfg.Name)
+	require.Len(t, endpoints, 1)
+}
+
+func TestPluginGCMatchesContainerIDAndIfName(t *testing.T) {
+	plugin := GetTestResources()
+	invoker := plugin.ipamInvoker.(*MockIpamInvoker)
+
+	require.NoError(t, plugin.Add(args))
+	require.Len(t, invoker.ipMap, 1)
+
+	// the container is still running, but not on this interface
+	gcCfg := nwCfg
+	gcCfg.CNIVersion = "1.1.0"
+	gcCfg.ValidAttachments = []cniTypes.GCAttachment{{ContainerID: args.ContainerID, IfName: "net1"}}
+
+	require.NoError(t, plugin.GC(&cniSkel.CmdArgs{StdinData: gcCfg.Serialize()}))
+	endpoints, _ := plugin.nm.GetAllEndpoints(nwCfg.Name)
+	require.Empty(t, endpoints)
+	assert.Empty(t, invoker.ipMap)
+}
+
+func TestPluginGCGracePeriod(t *testing.T) {
+	plugin := GetTestResources()
+	invoker := plugin.ipamInvoker.(*MockIpamInvoker)
+
+	newArgs := *args
+	newArgs.ContainerID = "new-container-1"
+	newArgs.Netns = "new-netns"
+	staleArgs := *args
+	staleArgs.ContainerID = "stale-container-1"
+	staleArgs.Netns = "stale-netns"
+
+	require.NoError(t, plugin.Add(&newArgs))
+	require.NoError(t, plugin.Add(&staleArgs))
+	require.Len(t, invoker.ipMap, 2)
+
+	// the ADD of the new container may still be in flight when the runtime lists the valid attachments
+	endpoints, _ := plugin.nm.GetAllEndpoints(nwCfg.Name)
+	for _, ep := range endpoints {
+		if ep.ContainerID == newArgs.ContainerID {
+			ep.Created = time.Now()
+		} else {
+			ep.Created = time.Now().Add(-2 * gcGracePeriod)
+		}
+	}
+
+	gcCfg := nwCfg
+	gcCfg.CNIVersion = "1.1.0"
+
+	require.NoError(t, plugin.GC(&cniSkel.CmdArgs{StdinData: gcCfg.Serialize()}))
+
+	endpoints, _ = plugin.nm.GetAllEndpoints(nwCfg.Name)
+	require.Len(t, endpoints, 1)
+	for _, ep := range endpoints {
+		assert.Equal(t, newArgs.ContainerID, ep.ContainerID)
+	}
+	assert.Len(t, invoker.ipMap, 1)
+}
+
+func TestPluginGCStateless(t *testing.T) {
+	plugin := GetTestResources()
+	invoker := plugin.ipamInvoker.(*MockIpamInvoker)
+
+	keepArgs := *args
+	keepArgs.ContainerID = "keep-container-1"
+	keepArgs.Netns = "keep-netns"
+	staleArgs := *args
+	staleArgs.ContainerID = "stale-container-1"
+	staleArgs.Netns = "stale-netns"
+
+	require.NoError(t, plugin.Add(&keepArgs))
+	require.NoError(t, plugin.Add(&staleArgs))
+	require.Len(t, invoker.ipMap, 2)
+
+	// the endpoints are listed from CNS instead of the state file
+	mockNetworkManager := plugin.nm.(*acnnetwork.MockNetworkManager)
+	mockNetworkManager.StatelessCNIMode = true
+
+	gcCfg := nwCfg
+	gcCfg.CNIVersion = "1.1.0"
+	gcCfg.ValidAttachments = []cniTypes.GCAttachment{{ContainerID: keepArgs.ContainerID, IfName: eth0IfName}}
+
+	require.NoError(t, plugin.GC(&cniSkel.CmdArgs{StdinData: gcCfg.Serialize()}))
+
+	endpoints, _ := plugin.nm.GetAllEndpointState(nwCfg.Name)
+	require.Len(t, endpoints, 1)
+	assert.Equal(t, keepArgs.ContainerID, endpoints[0].ContainerID)
+	assert.Len(t, invoker.ipMap, 1)
+}
+
 /*
 Multitenancy scenarios
 */
@@ -1554,3 +1765,40 @@ func TestValidateArgs(t *testing.T) {
 		})
 	}
 }
+
+func TestGetMTU(t *testing.T) {
+	plugin := &NetPlugin{
+		netClient: &InterfaceGetterMock{
+			interfaces: []net.Interface{{Name: "eth0", MTU: 1500}, {Name: "eth1", MTU: 9000}},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		nwCfg   *cni.NetworkConfig
+		want    int
+		wantErr bool
+	}{
+		{name: "default", nwCfg: &cni.NetworkConfig{}, want: 0},
+		{name: "configured", nwCfg: &cni.NetworkConfig{MTU: 8900}, want: 8900},
+		{name: "configured wins over auto-detect", nwCfg: &cni.NetworkConfig{MTU: 1400, AutoDetectMTU: true}, want: 1400},
+		{name: "auto-detect", nwCfg: &cni.NetworkConfig{AutoDetectMTU: true}, want: 9000},
+		{name: "too small", nwCfg: &cni.NetworkConfig{MTU: 67}, wantErr: true},
+		{name: "too large", nwCfg: &cni.NetworkConfig{MTU: 65536}, wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			mtu, err := plugin.getMTU(tt.nwCfg, "eth1")
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, mtu)
+		})
+	}
+
+	_, err := plugin.getMTU(&cni.NetworkConfig{AutoDetectMTU: true}, "eth2")
+	require.Error(t, err, "auto-detect should fail without the master interface")
+}
@@ -18,6 +18,8 @@ import (
 	"github.com/Azure/azure-container-networking/network/policy"
 	"github.com/Microsoft/hcsshim"
 	hnsv2 "github.com/Microsoft/hcsshim/hcn"
+	cniSkel "github.com/containernetworking/cni/pkg/skel"
+	cniTypes "github.com/containernetworking/cni/pkg/types"
 	cniTypesCurr "github.com/containernetworking/cni/pkg/types/100"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
@@ -31,6 +33,9 @@ var (
 	dualStackCount = 2
 )
 
+// chainedEndpointsDir is empty, as chaining with another primary plugin is only supported on linux.
+const chainedEndpointsDir = ""
+
 func addDefaultRoute(_ string, _ *network.EndpointInfo, _ *network.InterfaceInfo) {
 }
 
@@ -249,6 +254,46 @@ func getPoliciesFromRuntimeCfg(nwCfg *cni.NetworkConfig, isIPv6Enabled bool) ([]
 	return policies, nil
 }
 
+// getPortMappings returns no port mappings as they are programmed by the HNS policies from getPoliciesFromRuntimeCfg.
+func getPortMappings(_ *cni.NetworkConfig) ([]network.PortMapping, error) {
+	return nil, nil
+}
+
+// getBandwidth returns no bandwidth limits as traffic shaping is not supported on windows.
+func getBandwidth(_ *cni.NetworkConfig) (*network.Bandwidth, error) {
+	return nil, nil
+}
+
+// getTuning returns no tuning as tuning policies are not supported on windows, HNS rejects them.
+func getTuning(_ []policy.Policy) (*network.Tuning, error) {
+	return nil, nil
+}
+
+// getEgressSNAT returns no egress snat as the egress traffic of windows endpoints is source NATed by HNS.
+func (plugin *NetPlugin) getEgressSNAT(_ *cns.EgressSNAT, _ *cni.NetworkConfig, _ []network.SubnetInfo) (*network.EgressSNAT, error) {
+	return nil, nil
+}
+
+// addChained fails, as chaining with another primary plugin is only supported on linux.
+func (plugin *NetPlugin) addChained(_ *cniSkel.CmdArgs, _ *cni.NetworkConfig) error {
+	return plugin.Errorf("mode %s is not supported on windows", OpModeChained)
+}
+
+// deleteChained fails, as chaining with another primary plugin is only supported on linux.
+func (plugin *NetPlugin) deleteChained(_ *cniSkel.CmdArgs, _ *cni.NetworkConfig) error {
+	return plugin.Errorf("mode %s is not supported on windows", OpModeChained)
+}
+
+// verifyChained fails, as chaining with another primary plugin is only supported on linux.
+func (plugin *NetPlugin) verifyChained(_ *cniSkel.CmdArgs, _ *cni.NetworkConfig) ([]network.DatapathDrift, error) {
+	return nil, errors.Errorf("mode %s is not supported on windows", OpModeChained)
+}
+
+// gcChained fails, as chaining with another primary plugin is only supported on linux.
+func (plugin *NetPlugin) gcChained(_ *cni.NetworkConfig, _ map[cniTypes.GCAttachment]struct{}) ([]string, error) {
+	return nil, errors.Errorf("mode %s is not supported on windows", OpModeChained)
+}
+
 func getEndpointPolicies(args PolicyArgs) ([]policy.Policy, error) {
 	var policies []policy.Policy
 
@@ -45,6 +45,13 @@ var args = common.ArgumentList{
 		Type:         "bool",
 		DefaultValue: false,
 	},
+	{
+		Name:         common.OptDryRun,
+		Shorthand:    common.OptDryRunAlias,
+		Description:  "Print the JSON plan of the ADD in the CNI environment and stdin without running it",
+		Type:         "bool",
+		DefaultValue: false,
+	},
 }
 
 // Prints version information.
@@ -59,6 +66,7 @@ func rootExecute() error {
 	)
 
 	config.Version = version
+	config.EnableWALStore = os.Getenv(cni.EnvEnableWALStore) == "true"
 
 	reportManager := &telemetry.ReportManager{
 		HostNetAgentURL: hostNetAgentURL,
@@ -86,6 +94,14 @@ func rootExecute() error {
 		return errors.Wrap(err, "Create plugin error")
 	}
 
+	// A dry run only reads the host, so it skips the store lock and telemetry.
+	if common.GetArg(common.OptDryRun).(bool) {
+		if err = network.HandleDryRun(netPlugin); err != nil {
+			network.PrintCNIError(fmt.Sprintf("Failed to plan ADD, err:%v.\n", err))
+		}
+		return errors.Wrap(err, "Dry run error")
+	}
+
 	// Check CNI_COMMAND value
 	cniCmd := os.Getenv(cni.Cmd)
 
@@ -90,7 +90,13 @@ func (plugin *Plugin) Execute(api PluginApi) (err error) {
 	pluginInfo := cniVers.PluginSupports(supportedVersions...)
 
 	// Parse args and call the appropriate cmd handler.
-	cniErr := cniSkel.PluginMainWithError(api.Add, api.Get, api.Delete, pluginInfo, plugin.version)
+	cniErr := cniSkel.PluginMainFuncsWithError(cniSkel.CNIFuncs{
+		Add:    api.Add,
+		Check:  api.Check,
+		Del:    api.Delete,
+		GC:     api.GC,
+		Status: api.Status,
+	}, pluginInfo, plugin.version)
 	if cniErr != nil {
 		cniErr.Print()
 		return cniErr
@@ -191,7 +197,12 @@ func (plugin *Plugin) InitializeKeyValueStore(config *common.PluginConfig) error
 			return errors.Wrap(err, "error creating new filelock")
 		}
 
-		plugin.Store, err = store.NewJsonFileStore(platform.CNIRuntimePath+plugin.Name+".json", lockclient, storeLogger)
+		storeFileName := platform.CNIRuntimePath + plugin.Name + ".json"
+		if config.EnableWALStore {
+			plugin.Store, err = store.NewWALFileStore(storeFileName, lockclient, storeLogger)
+		} else {
+			plugin.Store, err = store.NewJsonFileStore(storeFileName, lockclient, storeLogger)
+		}
 		if err != nil {
 			logger.Error("Failed to create store", zap.Error(err))
 			return err
@@ -209,6 +220,14 @@ func (plugin *Plugin) InitializeKeyValueStore(config *common.PluginConfig) error
 		return errors.Wrap(err, "error Acquiring store lock")
 	}
 
+	if !config.EnableWALStore {
+		// Fold in the updates which the write-ahead log holds from when the WAL store was turned on.
+		if err := store.FoldWALFile(platform.CNIRuntimePath+plugin.Name+".json", storeLogger); err != nil {
+			logger.Error("Failed to fold write-ahead log into the store", zap.Error(err))
+			return errors.Wrap(err, "error folding write-ahead log")
+		}
+	}
+
 	config.Store = plugin.Store
 
 	return nil
@@ -6,6 +6,7 @@ import (
 	"net"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Azure/azure-container-networking/cns/types"
 	"github.com/Azure/azure-container-networking/crd/nodenetworkconfig/api/v1alpha"
@@ -33,9 +34,13 @@ const (
 	RequestIPConfigs                         = "/network/requestipconfigs"
 	ReleaseIPConfig                          = "/network/releaseipconfig"
 	ReleaseIPConfigs                         = "/network/releaseipconfigs"
+	RenewIPLeases                            = "/network/renewipleases"
 	PathDebugIPAddresses                     = "/debug/ipaddresses"
 	PathDebugPodContext                      = "/debug/podcontext"
 	PathDebugRestData                        = "/debug/restdata"
+	PathDebugStickyIPs                       = "/debug/stickyips"
+	PathDebugPoolMonitor                     = "/debug/poolmonitor"
+	PathEvents                               = "/events"
 	NumberOfCPUCores                         = NumberOfCPUCoresPath
 	NMAgentSupportedAPIs                     = NmAgentSupportedApisPath
 	EndpointAPI                              = EndpointPath
@@ -538,6 +543,45 @@ type IPConfigResponse struct {
 type IPConfigsResponse struct {
 	PodIPInfo []PodIpInfo `json:"podIPInfo"`
 	Response  Response    `json:"response"`
+	// EgressSNAT is how the traffic the pod sends out of the vnet is source NATed on the node, nil to leave it as
+	// configured for the network.
+	EgressSNAT *EgressSNAT `json:"egressSNAT,omitempty"`
+}
+
+// Egress SNAT modes.
+const (
+	// EgressSNATNodeIP SNATs the egress traffic of the pod to the node IP of the interface it leaves through.
+	EgressSNATNodeIP = "node-ip"
+	// EgressSNATNone preserves the pod IP on egress traffic.
+	EgressSNATNone = "none"
+	// EgressSNATSecondaryIP SNATs the egress traffic of the pod to a dedicated secondary IP of the node.
+	EgressSNATSecondaryIP = "secondary-ip"
+)
+
+// EgressSNAT chooses how the traffic a pod sends out of the vnet is source NATed on the node.
+type EgressSNAT struct {
+	Mode      string `json:"mode"`
+	IPAddress string `json:"ipAddress,omitempty"` // the secondary IP of the node, for EgressSNATSecondaryIP
+	// Except are the CIDRs of the vnet, whose traffic is left as configured for the network. They are filled in
+	// by the CNI from its network config, CNS leaves them empty.
+	Except []string `json:"except,omitempty"`
+}
+
+// Validate checks that the mode is known and that a secondary IP is passed for, and only for, EgressSNATSecondaryIP.
+func (e *EgressSNAT) Validate() error {
+	switch e.Mode {
+	case EgressSNATNodeIP, EgressSNATNone:
+		if e.IPAddress != "" {
+			return errors.Errorf("egress snat mode %s takes no ip address", e.Mode)
+		}
+	case EgressSNATSecondaryIP:
+		if net.ParseIP(e.IPAddress) == nil {
+			return errors.Errorf("invalid secondary ip %q of egress snat", e.IPAddress)
+		}
+	default:
+		return errors.Errorf("unknown egress snat mode %q", e.Mode)
+	}
+	return nil
 }
 
 // GetIPAddressesRequest is used in CNS IPAM mode to get the states of IPConfigs
@@ -564,6 +608,37 @@ type GetPodContextResponse struct {
 	Response   Response
 }
 
+// StickyIPReservation records the IPs last held by a pod identity (namespace and name) so that a
+// recreated pod with the same identity, such as a StatefulSet replica, is assigned the same IPs.
+type StickyIPReservation struct {
+	PodName      string
+	PodNamespace string
+	IPAddresses  []string
+	// ReleasedAt is when the pod released its IPs, zero while the IPs are assigned to the pod.
+	ReleasedAt time.Time
+	// Conflicts are the most recent reasons the reserved IPs could not be handed back to the pod.
+	Conflicts []StickyIPConflict `json:",omitempty"`
+}
+
+// StickyIPConflict describes a reserved IP that could not be assigned back to its pod.
+type StickyIPConflict struct {
+	IPAddress string
+	Reason    string
+	Time      time.Time
+}
+
+// GetStickyIPReservationsResponse is used in CNS Client debug mode to get the sticky IP reservations
+type GetStickyIPReservationsResponse struct {
+	Reservations []StickyIPReservation
+	Response     Response
+}
+
+// GetPoolMonitorStateResponse is used in CNS Client debug mode to get the state of the IPAM pool monitor
+type GetPoolMonitorStateResponse struct {
+	Snapshot IpamPoolMonitorStateSnapshot
+	Response Response
+}
+
 // IPAddressState Only used in the GetIPConfig API to return IPs that match a filter
 type IPAddressState struct {
 	IPAddress string
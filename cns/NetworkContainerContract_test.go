@@ -238,3 +238,31 @@ func TestPostNetworkContainersRequest_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestEgressSNATValidate(t *testing.T) {
+	tests := []struct {
+		name       string
+		egressSNAT EgressSNAT
+		wantErr    bool
+	}{
+		{name: "node ip", egressSNAT: EgressSNAT{Mode: EgressSNATNodeIP}},
+		{name: "none", egressSNAT: EgressSNAT{Mode: EgressSNATNone}},
+		{name: "secondary ip", egressSNAT: EgressSNAT{Mode: EgressSNATSecondaryIP, IPAddress: "10.240.0.10"}},
+		{name: "secondary ipv6", egressSNAT: EgressSNAT{Mode: EgressSNATSecondaryIP, IPAddress: "fd00::10"}},
+		{name: "secondary ip missing", egressSNAT: EgressSNAT{Mode: EgressSNATSecondaryIP}, wantErr: true},
+		{name: "secondary ip invalid", egressSNAT: EgressSNAT{Mode: EgressSNATSecondaryIP, IPAddress: "10.240.0.10/24"}, wantErr: true},
+		{name: "ip without secondary ip", egressSNAT: EgressSNAT{Mode: EgressSNATNodeIP, IPAddress: "10.240.0.10"}, wantErr: true},
+		{name: "unknown mode", egressSNAT: EgressSNAT{Mode: "masquerade"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.egressSNAT.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
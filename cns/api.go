@@ -75,6 +75,7 @@ type IPConfigurationStatus struct {
 	ID                   string // uuid
 	IPAddress            string
 	LastStateTransition  time.Time
+	LeaseExpiry          time.Time // zero when the IP is not Assigned or leases are disabled
 	NCID                 string
 	PodInfo              PodInfo
 	state                types.IPState
@@ -102,6 +103,18 @@ func (i *IPConfigurationStatus) GetState() types.IPState {
 	return i.state
 }
 
+// GetLeaseState returns the state of the lease on the IP at the passed time, or an
+// empty LeaseState if the IP does not hold a lease.
+func (i *IPConfigurationStatus) GetLeaseState(now time.Time) types.LeaseState {
+	if i.LeaseExpiry.IsZero() {
+		return ""
+	}
+	if now.After(i.LeaseExpiry) {
+		return types.LeaseExpired
+	}
+	return types.LeaseActive
+}
+
 type stateMiddlewareFunc func(*IPConfigurationStatus, types.IPState)
 
 func (i *IPConfigurationStatus) SetState(s types.IPState) {
@@ -132,11 +145,13 @@ func (i IPConfigurationStatus) String() string {
 func (i IPConfigurationStatus) MarshalJSON() ([]byte, error) {
 	type alias IPConfigurationStatus
 	return json.Marshal(&struct { //nolint:wrapcheck // MarshalJSON is not called by us
-		State types.IPState `json:"state"`
+		State      types.IPState    `json:"state"`
+		LeaseState types.LeaseState `json:"leaseState,omitempty"`
 		*alias
 	}{
-		State: i.state,
-		alias: (*alias)(&i),
+		State:      i.state,
+		LeaseState: i.GetLeaseState(time.Now()),
+		alias:      (*alias)(&i),
 	})
 }
 
@@ -164,6 +179,11 @@ func (i *IPConfigurationStatus) UnmarshalJSON(b []byte) error {
 			return errors.Wrap(err, "failed to unmarshal key IPAddress to string")
 		}
 	}
+	if s, ok := m["LeaseExpiry"]; ok {
+		if err := json.Unmarshal(s, &(i.LeaseExpiry)); err != nil {
+			return errors.Wrap(err, "failed to unmarshal key LeaseExpiry to time")
+		}
+	}
 	if s, ok := m["state"]; ok {
 		if err := json.Unmarshal(s, &(i.state)); err != nil {
 			return errors.Wrap(err, "failed to unmarshal key state to IPConfigState")
@@ -8,9 +8,12 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/Azure/azure-container-networking/cns"
+	"github.com/Azure/azure-container-networking/cns/events"
+	pb "github.com/Azure/azure-container-networking/cns/grpc/v1alpha"
 	"github.com/Azure/azure-container-networking/cns/restserver"
 	"github.com/Azure/azure-container-networking/cns/types"
 	"github.com/pkg/errors"
@@ -33,9 +36,13 @@ var clientPaths = []string{
 	cns.RequestIPConfigs,
 	cns.ReleaseIPConfig,
 	cns.ReleaseIPConfigs,
+	cns.RenewIPLeases,
 	cns.PathDebugIPAddresses,
 	cns.PathDebugPodContext,
 	cns.PathDebugRestData,
+	cns.PathDebugStickyIPs,
+	cns.PathDebugPoolMonitor,
+	cns.PathEvents,
 	cns.UnpublishNetworkContainer,
 	cns.PublishNetworkContainer,
 	cns.CreateOrUpdateNetworkContainer,
@@ -56,6 +63,9 @@ type do interface {
 type Client struct {
 	client do
 	routes map[string]url.URL
+	// grpc is set when the client was created with NewWithGRPC.
+	grpc        pb.CNSClient
+	grpcTimeout time.Duration
 }
 
 type ConnectionFailureErr struct {
@@ -68,6 +78,14 @@ func (e *ConnectionFailureErr) Error() string {
 
 // New returns a new CNS client configured with the passed URL and timeout.
 func New(baseURL string, requestTimeout time.Duration) (*Client, error) {
+	return NewWithHTTPClient(baseURL, &http.Client{
+		Timeout: requestTimeout,
+	})
+}
+
+// NewWithHTTPClient returns a new CNS client configured with the passed URL which makes requests with the passed
+// http.Client, such as one with a TLS or unix socket transport.
+func NewWithHTTPClient(baseURL string, httpClient *http.Client) (*Client, error) {
 	if baseURL == "" {
 		baseURL = defaultBaseURL
 	}
@@ -78,9 +96,7 @@ func New(baseURL string, requestTimeout time.Duration) (*Client, error) {
 	}
 
 	return &Client{
-		client: &http.Client{
-			Timeout: requestTimeout,
-		},
+		client: httpClient,
 		routes: routes,
 	}, nil
 }
@@ -107,6 +123,10 @@ func buildRoutes(baseURL string, paths []string) (map[string]url.URL, error) {
 
 // GetAllNetworkContainers Request to get network container configs.
 func (c *Client) GetAllNetworkContainers(ctx context.Context, orchestratorContext []byte) ([]cns.GetNetworkContainerResponse, error) {
+	if c.grpc != nil {
+		return c.getAllNetworkContainersGRPC(ctx, orchestratorContext)
+	}
+
 	payload := cns.GetNetworkContainerRequest{
 		OrchestratorContext: orchestratorContext,
 	}
@@ -167,6 +187,10 @@ func (c *Client) GetAllNetworkContainers(ctx context.Context, orchestratorContex
 
 // GetNetworkContainer Request to get network container config.
 func (c *Client) GetNetworkContainer(ctx context.Context, orchestratorContext []byte) (*cns.GetNetworkContainerResponse, error) {
+	if c.grpc != nil {
+		return c.getNetworkContainerGRPC(ctx, orchestratorContext)
+	}
+
 	payload := cns.GetNetworkContainerRequest{
 		OrchestratorContext: orchestratorContext,
 	}
@@ -398,6 +422,12 @@ func (c *Client) RequestIPs(ctx context.Context, ipconfig cns.IPConfigsRequest)
 		}
 	}()
 
+	if c.grpc != nil {
+		var response *cns.IPConfigsResponse
+		response, err = c.requestIPsGRPC(ctx, ipconfig)
+		return response, err
+	}
+
 	var body bytes.Buffer
 	err = json.NewEncoder(&body).Encode(ipconfig)
 	if err != nil {
@@ -442,6 +472,10 @@ func (c *Client) RequestIPs(ctx context.Context, ipconfig cns.IPConfigsRequest)
 
 // ReleaseIPs calls releaseIPs on which releases the IPs on the pod
 func (c *Client) ReleaseIPs(ctx context.Context, ipconfig cns.IPConfigsRequest) error {
+	if c.grpc != nil {
+		return c.releaseIPsGRPC(ctx, ipconfig)
+	}
+
 	var body bytes.Buffer
 	err := json.NewEncoder(&body).Encode(ipconfig)
 	if err != nil {
@@ -488,6 +522,61 @@ func (c *Client) ReleaseIPs(ctx context.Context, ipconfig cns.IPConfigsRequest)
 	return nil
 }
 
+// RenewIPLeases extends the leases on the IPs assigned to the pod
+func (c *Client) RenewIPLeases(ctx context.Context, ipconfig cns.IPConfigsRequest) error {
+	if c.grpc != nil {
+		return c.renewIPLeasesGRPC(ctx, ipconfig)
+	}
+
+	var body bytes.Buffer
+	err := json.NewEncoder(&body).Encode(ipconfig)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode IPConfigsRequest")
+	}
+
+	u := c.routes[cns.RenewIPLeases]
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), &body)
+	if err != nil {
+		return errors.Wrap(err, "failed to build request")
+	}
+	req.Header.Set(headerContentType, contentTypeJSON)
+	res, err := c.client.Do(req)
+	if err != nil {
+		return &ConnectionFailureErr{
+			cause: err,
+		}
+	}
+	defer res.Body.Close()
+
+	// if we get a 404 error
+	if res.StatusCode == http.StatusNotFound {
+		return &CNSClientError{
+			Code: types.UnsupportedAPI,
+			Err:  errors.Errorf("Unsupported API"),
+		}
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return errors.Errorf("http response %d", res.StatusCode)
+	}
+
+	var resp cns.Response
+
+	err = json.NewDecoder(res.Body).Decode(&resp)
+	if err != nil {
+		return errors.Wrap(err, "failed to decode Response")
+	}
+
+	if resp.ReturnCode != 0 {
+		return &CNSClientError{
+			Code: resp.ReturnCode,
+			Err:  errors.New(resp.Message),
+		}
+	}
+
+	return nil
+}
+
 // GetIPAddressesMatchingStates takes a variadic number of string parameters, to get all IP Addresses matching a number of states
 // usage GetIPAddressesWithStates(ctx, types.Available...)
 func (c *Client) GetIPAddressesMatchingStates(ctx context.Context, stateFilter ...types.IPState) ([]cns.IPConfigurationStatus, error) {
@@ -495,6 +584,10 @@ func (c *Client) GetIPAddressesMatchingStates(ctx context.Context, stateFilter .
 		return nil, nil
 	}
 
+	if c.grpc != nil {
+		return c.getIPAddressesMatchingStatesGRPC(ctx, stateFilter...)
+	}
+
 	payload := cns.GetIPAddressesRequest{
 		IPConfigStateFilter: stateFilter,
 	}
@@ -562,6 +655,98 @@ func (c *Client) GetPodOrchestratorContext(ctx context.Context) (map[string][]st
 	return resp.PodContext, nil
 }
 
+// GetStickyIPReservations returns the IPs CNS holds for sticky pod identities and any conflicts assigning them
+func (c *Client) GetStickyIPReservations(ctx context.Context) ([]cns.StickyIPReservation, error) {
+	u := c.routes[cns.PathDebugStickyIPs]
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build request")
+	}
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "http request failed")
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("http response %d", res.StatusCode)
+	}
+
+	var resp cns.GetStickyIPReservationsResponse
+	if err := json.NewDecoder(res.Body).Decode(&resp); err != nil {
+		return nil, errors.Wrap(err, "failed to decode GetStickyIPReservationsResponse")
+	}
+
+	if resp.Response.ReturnCode != 0 {
+		return nil, errors.New(resp.Response.Message)
+	}
+
+	return resp.Reservations, nil
+}
+
+// GetPoolMonitorState returns the state of the IPAM pool monitor.
+func (c *Client) GetPoolMonitorState(ctx context.Context) (*cns.IpamPoolMonitorStateSnapshot, error) {
+	u := c.routes[cns.PathDebugPoolMonitor]
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build request")
+	}
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "http request failed")
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("http response %d", res.StatusCode)
+	}
+
+	var resp cns.GetPoolMonitorStateResponse
+	if err := json.NewDecoder(res.Body).Decode(&resp); err != nil {
+		return nil, errors.Wrap(err, "failed to decode GetPoolMonitorStateResponse")
+	}
+
+	if resp.Response.ReturnCode != 0 {
+		return nil, errors.New(resp.Response.Message)
+	}
+
+	return &resp.Snapshot, nil
+}
+
+// GetEvents long-polls the CNS event log for events after the cursor, waiting up to wait for one to be published.
+// The returned Page holds the cursor to resume from on the next call. The wait must be shorter than the client's request timeout.
+func (c *Client) GetEvents(ctx context.Context, cursor uint64, wait time.Duration) (*events.Page, error) {
+	u := c.routes[cns.PathEvents]
+	q := u.Query()
+	q.Set("cursor", strconv.FormatUint(cursor, 10))
+	q.Set("wait", wait.String())
+	u.RawQuery = q.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build request")
+	}
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "http request failed")
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("http response %d", res.StatusCode)
+	}
+
+	var resp restserver.GetEventsResponse
+	if err := json.NewDecoder(res.Body).Decode(&resp); err != nil {
+		return nil, errors.Wrap(err, "failed to decode GetEventsResponse")
+	}
+
+	if resp.Response.ReturnCode != 0 {
+		return nil, errors.New(resp.Response.Message)
+	}
+
+	return &resp.Page, nil
+}
+
 // GetHTTPServiceData gets all public in-memory struct details for debugging purpose
 func (c *Client) GetHTTPServiceData(ctx context.Context) (*restserver.GetHTTPServiceDataResponse, error) {
 	u := c.routes[cns.PathDebugRestData]
@@ -892,7 +1077,7 @@ func (c *Client) NMAgentSupportedAPIs(ctx context.Context) (*cns.NmAgentSupporte
 	}
 
 	u := c.routes[cns.NMAgentSupportedAPIs]
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), bytes.NewReader(body))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), bytes.NewReader(body))
 	if err != nil {
 		return nil, errors.Wrap(err, "building http request")
 	}
@@ -1025,6 +1210,10 @@ func (c *Client) GetHomeAz(ctx context.Context) (*cns.GetHomeAzResponse, error)
 
 // GetEndpoint calls the EndpointHandlerAPI in CNS to retrieve the state of a given EndpointID
 func (c *Client) GetEndpoint(ctx context.Context, endpointID string) (*restserver.GetEndpointResponse, error) {
+	if c.grpc != nil {
+		return c.getEndpointGRPC(ctx, endpointID)
+	}
+
 	// build the request
 	u := c.routes[cns.EndpointAPI]
 	uString := u.String() + endpointID
@@ -1057,9 +1246,46 @@ func (c *Client) GetEndpoint(ctx context.Context, endpointID string) (*restserve
 	return &response, nil
 }
 
+// GetEndpoints calls the EndpointHandlerAPI in CNS to retrieve the state of all endpoints, keyed by EndpointID.
+// The gRPC API has no equivalent, so it always uses the HTTP API.
+func (c *Client) GetEndpoints(ctx context.Context) (map[string]*restserver.EndpointInfo, error) {
+	u := c.routes[cns.EndpointAPI]
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), http.NoBody)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build request")
+	}
+	req.Header.Set(headerContentType, contentTypeJSON)
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "http request failed")
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("http response %d", res.StatusCode)
+	}
+
+	var response restserver.GetEndpointsResponse
+	err = json.NewDecoder(res.Body).Decode(&response)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode GetEndpointsResponse")
+	}
+
+	if response.Response.ReturnCode != 0 {
+		return nil, errors.New(response.Response.Message)
+	}
+
+	return response.EndpointInfos, nil
+}
+
 // UpdateEndpoint calls the EndpointHandlerAPI in CNS
 // to update the state of a given EndpointID with either HNSEndpointID or HostVethName
 func (c *Client) UpdateEndpoint(ctx context.Context, endpointID string, ipInfo map[string]*restserver.IPInfo) (*cns.Response, error) {
+	if c.grpc != nil {
+		return c.updateEndpointGRPC(ctx, endpointID, ipInfo)
+	}
+
 	// build the request
 	var body bytes.Buffer
 
@@ -2902,3 +2902,32 @@ func TestGetEndpoint(t *testing.T) {
 		})
 	}
 }
+
+func TestGetEndpoints(t *testing.T) {
+	emptyRoutes, _ := buildRoutes(defaultBaseURL, clientPaths)
+
+	endpointInfos := map[string]*restserver.EndpointInfo{
+		"foo": {PodName: "pod", PodNamespace: "ns", IfnameToIPMap: map[string]*restserver.IPInfo{"eth0": {HostVethName: "azv1"}}},
+	}
+	client := Client{
+		client: &RequestCapture{
+			Next: &mockdo{
+				objToReturn:            restserver.GetEndpointsResponse{EndpointInfos: endpointInfos},
+				httpStatusCodeToReturn: http.StatusOK,
+			},
+		},
+		routes: emptyRoutes,
+	}
+	res, err := client.GetEndpoints(context.TODO())
+	require.NoError(t, err)
+	require.Equal(t, endpointInfos, res)
+
+	client.client = &RequestCapture{
+		Next: &mockdo{
+			objToReturn:            restserver.GetEndpointsResponse{Response: restserver.Response{ReturnCode: types.UnexpectedError, Message: "failed"}},
+			httpStatusCodeToReturn: http.StatusOK,
+		},
+	}
+	_, err = client.GetEndpoints(context.TODO())
+	require.Error(t, err)
+}
@@ -0,0 +1,205 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	"github.com/Azure/azure-container-networking/cns"
+	cnsgrpc "github.com/Azure/azure-container-networking/cns/grpc"
+	pb "github.com/Azure/azure-container-networking/cns/grpc/v1alpha"
+	"github.com/Azure/azure-container-networking/cns/restserver"
+	"github.com/Azure/azure-container-networking/cns/types"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+// NewWithGRPC returns a new CNS client which sends the IPAM, network container and endpoint
+// state calls to the CNS gRPC server at grpcAddress, and all other calls to the HTTP API at baseURL.
+func NewWithGRPC(baseURL, grpcAddress string, requestTimeout time.Duration) (*Client, error) {
+	c, err := New(baseURL, requestTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := grpc.NewClient(grpcAddress, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create gRPC client for %s", grpcAddress)
+	}
+	c.grpc = pb.NewCNSClient(conn)
+	c.grpcTimeout = requestTimeout
+	return c, nil
+}
+
+func (c *Client) grpcContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.grpcTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, c.grpcTimeout)
+}
+
+// grpcError maps gRPC status errors to the errors returned by the HTTP transport.
+func grpcError(err error) error {
+	switch status.Code(err) {
+	case codes.Unimplemented:
+		return &CNSClientError{
+			Code: types.UnsupportedAPI,
+			Err:  errors.Errorf("Unsupported API"),
+		}
+	case codes.Unavailable:
+		return &ConnectionFailureErr{
+			cause: err,
+		}
+	default:
+		return errors.Wrap(err, "grpc request failed")
+	}
+}
+
+func (c *Client) requestIPsGRPC(ctx context.Context, ipconfig cns.IPConfigsRequest) (*cns.IPConfigsResponse, error) { //nolint:gocritic // ignore hugeParam
+	ctx, cancel := c.grpcContext(ctx)
+	defer cancel()
+	res, err := c.grpc.RequestIPConfigs(ctx, cnsgrpc.IPConfigsRequestToProto(ipconfig))
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	response := cnsgrpc.IPConfigsResponseFromProto(res)
+	if response.Response.ReturnCode != 0 {
+		return nil, errors.New(response.Response.Message)
+	}
+	return response, nil
+}
+
+func (c *Client) releaseIPsGRPC(ctx context.Context, ipconfig cns.IPConfigsRequest) error { //nolint:gocritic // ignore hugeParam
+	ctx, cancel := c.grpcContext(ctx)
+	defer cancel()
+	res, err := c.grpc.ReleaseIPConfigs(ctx, cnsgrpc.IPConfigsRequestToProto(ipconfig))
+	if err != nil {
+		return grpcError(err)
+	}
+
+	if res.GetResponse().GetReturnCode() != 0 {
+		return errors.New(res.GetResponse().GetMessage())
+	}
+	return nil
+}
+
+func (c *Client) renewIPLeasesGRPC(ctx context.Context, ipconfig cns.IPConfigsRequest) error { //nolint:gocritic // ignore hugeParam
+	ctx, cancel := c.grpcContext(ctx)
+	defer cancel()
+	res, err := c.grpc.RenewIPLeases(ctx, cnsgrpc.IPConfigsRequestToProto(ipconfig))
+	if err != nil {
+		return grpcError(err)
+	}
+
+	if res.GetReturnCode() != 0 {
+		return &CNSClientError{
+			Code: types.ResponseCode(res.GetReturnCode()),
+			Err:  errors.New(res.GetMessage()),
+		}
+	}
+	return nil
+}
+
+func (c *Client) getIPAddressesMatchingStatesGRPC(ctx context.Context, stateFilter ...types.IPState) ([]cns.IPConfigurationStatus, error) {
+	req := &pb.GetIPAddressesRequest{}
+	for _, state := range stateFilter {
+		req.IpConfigStateFilter = append(req.IpConfigStateFilter, string(state))
+	}
+
+	ctx, cancel := c.grpcContext(ctx)
+	defer cancel()
+	res, err := c.grpc.GetIPAddresses(ctx, req)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	if res.GetResponse().GetReturnCode() != 0 {
+		return nil, errors.New(res.GetResponse().GetMessage())
+	}
+	ipConfigs := make([]cns.IPConfigurationStatus, 0, len(res.GetIpConfigurationStatus()))
+	for _, status := range res.GetIpConfigurationStatus() {
+		ipConfigs = append(ipConfigs, cnsgrpc.IPConfigurationStatusFromProto(status))
+	}
+	return ipConfigs, nil
+}
+
+func (c *Client) getNetworkContainerGRPC(ctx context.Context, orchestratorContext []byte) (*cns.GetNetworkContainerResponse, error) {
+	ctx, cancel := c.grpcContext(ctx)
+	defer cancel()
+	res, err := c.grpc.GetNetworkContainer(ctx, &pb.GetNetworkContainerRequest{OrchestratorContext: orchestratorContext})
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	resp := cnsgrpc.NetworkContainerFromProto(res)
+	if resp.Response.ReturnCode != 0 {
+		return nil, &CNSClientError{
+			Code: resp.Response.ReturnCode,
+			Err:  errors.New(resp.Response.Message),
+		}
+	}
+	return &resp, nil
+}
+
+func (c *Client) getAllNetworkContainersGRPC(ctx context.Context, orchestratorContext []byte) ([]cns.GetNetworkContainerResponse, error) {
+	ctx, cancel := c.grpcContext(ctx)
+	defer cancel()
+	res, err := c.grpc.GetAllNetworkContainers(ctx, &pb.GetNetworkContainerRequest{OrchestratorContext: orchestratorContext})
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	if res.GetResponse().GetReturnCode() != 0 {
+		return nil, &CNSClientError{
+			Code: types.ResponseCode(res.GetResponse().GetReturnCode()),
+			Err:  errors.New(res.GetResponse().GetMessage()),
+		}
+	}
+	ncs := make([]cns.GetNetworkContainerResponse, 0, len(res.GetNetworkContainers()))
+	for _, nc := range res.GetNetworkContainers() {
+		ncs = append(ncs, cnsgrpc.NetworkContainerFromProto(nc))
+	}
+	return ncs, nil
+}
+
+func (c *Client) getEndpointGRPC(ctx context.Context, endpointID string) (*restserver.GetEndpointResponse, error) {
+	ctx, cancel := c.grpcContext(ctx)
+	defer cancel()
+	res, err := c.grpc.GetEndpoint(ctx, &pb.GetEndpointRequest{EndpointID: endpointID})
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	if res.GetResponse().GetReturnCode() != 0 {
+		return nil, errors.New(res.GetResponse().GetMessage())
+	}
+	endpointInfo, err := cnsgrpc.EndpointInfoFromProto(res.GetEndpointInfo())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to convert EndpointInfo")
+	}
+	return &restserver.GetEndpointResponse{
+		Response: restserver.Response{
+			ReturnCode: types.ResponseCode(res.GetResponse().GetReturnCode()),
+			Message:    res.GetResponse().GetMessage(),
+		},
+		EndpointInfo: endpointInfo,
+	}, nil
+}
+
+func (c *Client) updateEndpointGRPC(ctx context.Context, endpointID string, ipInfo map[string]*restserver.IPInfo) (*cns.Response, error) {
+	ctx, cancel := c.grpcContext(ctx)
+	defer cancel()
+	res, err := c.grpc.UpdateEndpoint(ctx, &pb.UpdateEndpointRequest{EndpointID: endpointID, IpInfo: cnsgrpc.IPInfoToProto(ipInfo)})
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	response := cnsgrpc.ResponseFromProto(res)
+	if response.ReturnCode != 0 {
+		return nil, errors.New(response.Message)
+	}
+	return &response, nil
+}
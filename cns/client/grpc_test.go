@@ -0,0 +1,229 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-container-networking/cns"
+	cnsgrpc "github.com/Azure/azure-container-networking/cns/grpc"
+	pb "github.com/Azure/azure-container-networking/cns/grpc/v1alpha"
+	"github.com/Azure/azure-container-networking/cns/types"
+	acncommon "github.com/Azure/azure-container-networking/common"
+	"github.com/Azure/azure-container-networking/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+// startGRPCServer serves the CNS gRPC API backed by the test CNS service and returns its address.
+func startGRPCServer(t *testing.T) string {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := grpc.NewServer()
+	pb.RegisterCNSServer(server, &cnsgrpc.CNS{Logger: zap.NewNop(), State: svc})
+	go server.Serve(lis) //nolint:errcheck // stopped below
+	t.Cleanup(server.Stop)
+	return lis.Addr().String()
+}
+
+// ipamScenarioResult records what a client observed while running the IPAM scenario.
+type ipamScenarioResult struct {
+	PodIPInfo        []cns.PodIpInfo
+	Assigned         []string
+	RenewErr         bool
+	RenewMissingCode types.ResponseCode
+	AssignedAfter    int
+	NCErrCode        types.ResponseCode
+	EndpointErr      bool
+}
+
+func runIPAMScenario(t *testing.T, c *Client) ipamScenarioResult {
+	t.Helper()
+	ctx := context.Background()
+	var res ipamScenarioResult
+
+	podInfo := cns.NewPodInfo("parity-guid", "parity-eth0", testpodname, testpodnamespace)
+	orchestratorContext, err := json.Marshal(podInfo)
+	require.NoError(t, err)
+	req := cns.IPConfigsRequest{
+		PodInterfaceID:      podInfo.InterfaceID(),
+		InfraContainerID:    podInfo.InfraContainerID(),
+		OrchestratorContext: orchestratorContext,
+	}
+
+	resp, err := c.RequestIPs(ctx, req)
+	require.NoError(t, err)
+	res.PodIPInfo = resp.PodIPInfo
+
+	assigned, err := c.GetIPAddressesMatchingStates(ctx, types.Assigned)
+	require.NoError(t, err)
+	for i := range assigned {
+		res.Assigned = append(res.Assigned, assigned[i].IPAddress)
+		assert.Equal(t, podInfo.Name(), assigned[i].PodInfo.Name())
+	}
+
+	res.RenewErr = c.RenewIPLeases(ctx, req) != nil
+
+	require.NoError(t, c.ReleaseIPs(ctx, req))
+	// releasing is idempotent
+	require.NoError(t, c.ReleaseIPs(ctx, req))
+
+	missing := &CNSClientError{}
+	require.ErrorAs(t, c.RenewIPLeases(ctx, req), &missing)
+	res.RenewMissingCode = missing.Code
+
+	assigned, err = c.GetIPAddressesMatchingStates(ctx, types.Assigned)
+	require.NoError(t, err)
+	res.AssignedAfter = len(assigned)
+
+	ncErr := &CNSClientError{}
+	if _, err := c.GetNetworkContainer(ctx, orchestratorContext); err != nil {
+		require.ErrorAs(t, err, &ncErr)
+		res.NCErrCode = ncErr.Code
+	}
+
+	_, err = c.GetEndpoint(ctx, "parity-guid")
+	res.EndpointErr = err != nil
+	return res
+}
+
+func TestGRPCParity(t *testing.T) {
+	addTestStateToRestServer(t, []string{primaryIP})
+	addr := startGRPCServer(t)
+
+	httpClient, err := New("", 2*time.Second)
+	require.NoError(t, err)
+	grpcClient, err := NewWithGRPC("", addr, 2*time.Second)
+	require.NoError(t, err)
+
+	overHTTP := runIPAMScenario(t, httpClient)
+	overGRPC := runIPAMScenario(t, grpcClient)
+
+	assert.Equal(t, []string{primaryIP}, overHTTP.Assigned)
+	assert.Equal(t, types.NotFound, overHTTP.RenewMissingCode)
+	assert.Zero(t, overHTTP.AssignedAfter)
+	assert.Equal(t, overHTTP, overGRPC)
+}
+
+func TestGRPCUnsupportedAPI(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	// a CNS without the IPAM RPCs
+	pb.RegisterCNSServer(server, &pb.UnimplementedCNSServer{})
+	go server.Serve(lis) //nolint:errcheck // stopped below
+	defer server.Stop()
+
+	c, err := NewWithGRPC("", lis.Addr().String(), 2*time.Second)
+	require.NoError(t, err)
+	err = c.RenewIPLeases(context.Background(), cns.IPConfigsRequest{})
+	assert.True(t, IsUnsupportedAPI(err))
+}
+
+func TestGRPCRequestIPsError(t *testing.T) {
+	addTestStateToRestServer(t, []string{primaryIP})
+	addr := startGRPCServer(t)
+
+	c, err := NewWithGRPC("", addr, 2*time.Second)
+	require.NoError(t, err)
+	// a request without an orchestrator context is rejected instead of returning an empty success
+	_, err = c.RequestIPs(context.Background(), cns.IPConfigsRequest{InfraContainerID: "error-guid"})
+	require.Error(t, err)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestGRPCWatchIPPoolState(t *testing.T) {
+	addTestStateToRestServer(t, []string{primaryIP})
+	addr := startGRPCServer(t)
+
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	stream, err := pb.NewCNSClient(conn).WatchIPPoolState(ctx, &pb.GetIPAddressesRequest{IpConfigStateFilter: []string{string(types.Assigned)}})
+	require.NoError(t, err)
+
+	snapshot, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Empty(t, snapshot.GetIpConfigurationStatus())
+
+	podInfo := cns.NewPodInfo("watch-guid", "watch-eth0", testpodname, testpodnamespace)
+	orchestratorContext, err := json.Marshal(podInfo)
+	require.NoError(t, err)
+	req := cns.IPConfigsRequest{
+		PodInterfaceID:      podInfo.InterfaceID(),
+		InfraContainerID:    podInfo.InfraContainerID(),
+		OrchestratorContext: orchestratorContext,
+	}
+	c, err := New("", 2*time.Second)
+	require.NoError(t, err)
+	_, err = c.RequestIPs(ctx, req)
+	require.NoError(t, err)
+
+	snapshot, err = stream.Recv()
+	require.NoError(t, err)
+	require.Len(t, snapshot.GetIpConfigurationStatus(), 1)
+	assert.Equal(t, primaryIP, snapshot.GetIpConfigurationStatus()[0].GetIpAddress())
+	assert.Equal(t, testpodname, snapshot.GetIpConfigurationStatus()[0].GetPodName())
+
+	require.NoError(t, c.ReleaseIPs(ctx, req))
+	snapshot, err = stream.Recv()
+	require.NoError(t, err)
+	assert.Empty(t, snapshot.GetIpConfigurationStatus())
+}
+
+func TestGRPCWatchEndpoints(t *testing.T) {
+	addTestStateToRestServer(t, []string{primaryIP})
+	svc.EndpointStateStore = store.NewMockStore("")
+	svc.Options[acncommon.OptManageEndpointState] = true
+	defer func() {
+		svc.EndpointStateStore = nil
+		delete(svc.Options, acncommon.OptManageEndpointState)
+	}()
+	addr := startGRPCServer(t)
+
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	stream, err := pb.NewCNSClient(conn).WatchEndpoints(ctx, &pb.WatchEndpointsRequest{})
+	require.NoError(t, err)
+
+	podInfo := cns.NewPodInfo("watch-endpoint-guid", "watch-endpoint-eth0", testpodname, testpodnamespace)
+	orchestratorContext, err := json.Marshal(podInfo)
+	require.NoError(t, err)
+	req := cns.IPConfigsRequest{
+		PodInterfaceID:      podInfo.InterfaceID(),
+		InfraContainerID:    podInfo.InfraContainerID(),
+		OrchestratorContext: orchestratorContext,
+	}
+	c, err := New("", 2*time.Second)
+	require.NoError(t, err)
+	_, err = c.RequestIPs(ctx, req)
+	require.NoError(t, err)
+
+	event, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, pb.EndpointEventType_ENDPOINT_EVENT_TYPE_ADDED, event.GetType())
+	assert.Equal(t, podInfo.InfraContainerID(), event.GetEndpointID())
+	assert.Equal(t, testpodname, event.GetEndpointInfo().GetPodName())
+
+	require.NoError(t, c.ReleaseIPs(ctx, req))
+	event, err = stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, pb.EndpointEventType_ENDPOINT_EVENT_TYPE_DELETED, event.GetType())
+	assert.Equal(t, podInfo.InfraContainerID(), event.GetEndpointID())
+}
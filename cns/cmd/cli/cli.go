@@ -1,15 +1,14 @@
+// Package cli implements cnsctl, the CNS debug CLI.
 package cli
 
 import (
 	"context"
 	"fmt"
 	"os"
-	"sort"
 	"strings"
 
-	"github.com/Azure/azure-container-networking/cns"
-	"github.com/Azure/azure-container-networking/cns/client"
 	"github.com/Azure/azure-container-networking/cns/types"
+	"github.com/spf13/cobra"
 )
 
 const (
@@ -20,82 +19,58 @@ const (
 	getPodCmdArg    = "getPodContexts"
 )
 
-func HandleCNSClientCommands(ctx context.Context, cmd string, arg string) error {
-	cnsIPAddress := os.Getenv(envCNSIPAddress)
-	cnsPort := os.Getenv(envCNSPort)
-
-	cnsClient, err := client.New("http://"+cnsIPAddress+":"+cnsPort, client.DefaultTimeout)
-	if err != nil {
-		return err
-	}
-
+// HandleCNSClientCommands runs the legacy azure-cns debug commands, which are now served by the cnsctl commands,
+// against the CNS at the address in the CNSIpAddress and CNSPort environment variables.
+func HandleCNSClientCommands(ctx context.Context, cmd, arg string) error {
+	var args []string
 	switch {
 	case strings.EqualFold(getCmdArg, cmd):
-		return getCmd(ctx, cnsClient, arg)
+		args = []string{"ips"}
+		switch types.IPState(arg) {
+		case types.Available, types.Assigned, types.PendingProgramming, types.PendingRelease:
+			args = append(args, "--state", arg)
+		}
 	case strings.EqualFold(getPodCmdArg, cmd):
-		return getPodCmd(ctx, cnsClient)
+		args = []string{"pods"}
 	case strings.EqualFold(getInMemoryData, cmd):
-		return getInMemory(ctx, cnsClient)
+		args = []string{"dump"}
 	default:
 		return fmt.Errorf("No debug cmd supplied, options are: %v", getCmdArg)
 	}
-}
-
-func getCmd(ctx context.Context, client *client.Client, arg string) error {
-	var states []types.IPState
-
-	switch types.IPState(arg) {
-	case types.Available:
-		states = append(states, types.Available)
-	case types.Assigned:
-		states = append(states, types.Assigned)
-	case types.PendingProgramming:
-		states = append(states, types.PendingProgramming)
-	case types.PendingRelease:
-		states = append(states, types.PendingRelease)
-	default:
-		states = append(states, types.Assigned, types.Available, types.PendingProgramming, types.PendingRelease)
+	if ip, port := os.Getenv(envCNSIPAddress), os.Getenv(envCNSPort); ip != "" || port != "" {
+		args = append(args, "--address", "http://"+ip+":"+port)
 	}
 
-	addr, err := client.GetIPAddressesMatchingStates(ctx, states...)
-	if err != nil {
-		return err
-	}
-
-	printIPAddresses(addr)
-	return nil
+	root := NewRootCmd()
+	root.SetArgs(args)
+	return root.ExecuteContext(ctx) //nolint:wrapcheck // cobra errors are already descriptive
 }
 
-// Sort the addresses based on IP, then write to stdout
-func printIPAddresses(addrSlice []cns.IPConfigurationStatus) {
-	sort.Slice(addrSlice, func(i, j int) bool {
-		return addrSlice[i].IPAddress < addrSlice[j].IPAddress
-	})
-
-	for _, addr := range addrSlice {
-		fmt.Println(addr.String())
+// NewRootCmd returns the cnsctl root command.
+func NewRootCmd() *cobra.Command {
+	opts := &options{}
+	rootCmd := &cobra.Command{
+		Use:           "cnsctl",
+		Short:         "Inspect the state of Azure CNS",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		CompletionOptions: cobra.CompletionOptions{
+			DisableDefaultCmd: true,
+		},
+		PersistentPreRunE: func(*cobra.Command, []string) error {
+			return opts.validate()
+		},
 	}
-}
+	opts.addFlags(rootCmd)
 
-func getPodCmd(ctx context.Context, client *client.Client) error {
-	resp, err := client.GetPodOrchestratorContext(ctx)
-	if err != nil {
-		return err
-	}
-	i := 1
-	for orchContext, podID := range resp {
-		fmt.Printf("%d %s : %s\n", i, orchContext, podID)
-		i++
-	}
-	return nil
-}
+	rootCmd.AddCommand(newIPsCmd(opts))
+	rootCmd.AddCommand(newPodCmd(opts))
+	rootCmd.AddCommand(newPodsCmd(opts))
+	rootCmd.AddCommand(newDumpCmd(opts))
+	rootCmd.AddCommand(newNCCmd(opts))
+	rootCmd.AddCommand(newEndpointCmd(opts))
+	rootCmd.AddCommand(newPoolCmd(opts))
+	rootCmd.AddCommand(newNMAgentCmd(opts))
 
-func getInMemory(ctx context.Context, client *client.Client) error {
-	data, err := client.GetHTTPServiceData(ctx)
-	if err != nil {
-		return err
-	}
-	fmt.Printf("PodIPIDByOrchestratorContext: %v\nPodIPConfigState: %v\n",
-		data.HTTPRestServiceData.PodIPIDByPodInterfaceKey, data.HTTPRestServiceData.PodIPConfigState)
-	return nil
+	return rootCmd
 }
@@ -0,0 +1,183 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Azure/azure-container-networking/cns"
+	"github.com/Azure/azure-container-networking/cns/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeCNS serves the CNS debug APIs used by the commands from fixed state.
+func fakeCNS(t *testing.T, ips []cns.IPConfigurationStatus, ncs []cns.GetNetworkContainerResponse) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc(cns.PathDebugIPAddresses, func(w http.ResponseWriter, r *http.Request) {
+		var req cns.GetIPAddressesRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		resp := cns.GetIPAddressStatusResponse{}
+		for i := range ips {
+			for _, state := range req.IPConfigStateFilter {
+				if ips[i].GetState() == state {
+					resp.IPConfigurationStatus = append(resp.IPConfigurationStatus, ips[i])
+				}
+			}
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(resp))
+	})
+	mux.HandleFunc(cns.NetworkContainersURLPath, func(w http.ResponseWriter, _ *http.Request) {
+		require.NoError(t, json.NewEncoder(w).Encode(cns.GetAllNetworkContainersResponse{NetworkContainers: ncs}))
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func testIPs() []cns.IPConfigurationStatus {
+	ips := []cns.IPConfigurationStatus{
+		{ID: "id-2", NCID: "nc-1", IPAddress: "10.0.0.10"},
+		{ID: "id-1", NCID: "nc-1", IPAddress: "10.0.0.9", PodInfo: cns.NewPodInfo("infra", "eth0", "pod-1", "default")},
+		{ID: "id-3", NCID: "nc-1", IPAddress: "10.0.0.11"},
+	}
+	ips[0].SetState(types.Available)
+	ips[1].SetState(types.Assigned)
+	ips[2].SetState(types.PendingRelease)
+	return ips
+}
+
+func run(t *testing.T, args ...string) (string, error) {
+	cmd := NewRootCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	return out.String(), err
+}
+
+func TestIPs(t *testing.T) {
+	srv := fakeCNS(t, testIPs(), nil)
+
+	out, err := run(t, "ips", "--address", srv.URL)
+	require.NoError(t, err)
+	assert.Equal(t, `IP         STATE           NC    POD
+10.0.0.9   Assigned        nc-1  default/pod-1
+10.0.0.10  Available       nc-1
+10.0.0.11  PendingRelease  nc-1
+`, out)
+
+	out, err = run(t, "ips", "--address", srv.URL, "--state", "pendingrelease,available", "-o", "json")
+	require.NoError(t, err)
+	var rows []ipRow
+	require.NoError(t, json.Unmarshal([]byte(out), &rows))
+	assert.Equal(t, []ipRow{
+		{IP: "10.0.0.10", ID: "id-2", NCID: "nc-1", State: types.Available},
+		{IP: "10.0.0.11", ID: "id-3", NCID: "nc-1", State: types.PendingRelease},
+	}, rows)
+
+	_, err = run(t, "ips", "--address", srv.URL, "--state", "Leased")
+	require.ErrorContains(t, err, "invalid IP state")
+}
+
+func TestPod(t *testing.T) {
+	srv := fakeCNS(t, testIPs(), nil)
+
+	byName, err := run(t, "pod", "default/pod-1", "--address", srv.URL, "-o", "yaml")
+	require.NoError(t, err)
+	assert.Contains(t, byName, "ip: 10.0.0.9\n")
+	assert.Contains(t, byName, "infraContainerID: infra\n")
+
+	byIP, err := run(t, "pod", "10.0.0.9", "--address", srv.URL, "-o", "yaml")
+	require.NoError(t, err)
+	assert.Equal(t, byName, byIP)
+
+	_, err = run(t, "pod", "default/pod-2", "--address", srv.URL)
+	require.ErrorContains(t, err, "no IP or pod default/pod-2 found")
+}
+
+func TestNCDiff(t *testing.T) {
+	before := []cns.GetNetworkContainerResponse{
+		{NetworkContainerID: "nc-1", IPConfiguration: cns.IPConfiguration{GatewayIPAddress: "10.0.0.1"}},
+		{NetworkContainerID: "nc-2"},
+	}
+	after := []cns.GetNetworkContainerResponse{
+		{NetworkContainerID: "nc-1", IPConfiguration: cns.IPConfiguration{GatewayIPAddress: "10.0.0.2"}, Routes: []cns.Route{{}}},
+		{NetworkContainerID: "nc-3"},
+	}
+	dir := t.TempDir()
+	beforePath := filepath.Join(dir, "before.json")
+	b, err := json.Marshal(before)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(beforePath, b, 0o600))
+
+	changes := diffNCs(before, after)
+	require.Len(t, changes, 3)
+	assert.Equal(t, ncChange{NCID: "nc-1", Change: ncChanged, Fields: []string{"IPConfiguration", "Routes"}, Before: &before[0], After: &after[0]}, changes[0])
+	assert.Equal(t, ncRemoved, changes[1].Change)
+	assert.Equal(t, ncAdded, changes[2].Change)
+
+	// with one snapshot, the diff is against the live network containers
+	srv := fakeCNS(t, nil, after)
+	out, err := run(t, "nc", "diff", beforePath, "--address", srv.URL)
+	require.NoError(t, err)
+	assert.Equal(t, `NC    CHANGE   FIELDS
+nc-1  changed  IPConfiguration,Routes
+nc-2  removed
+nc-3  added
+`, out)
+
+	out, err = run(t, "nc", "diff", beforePath, beforePath)
+	require.NoError(t, err)
+	assert.Equal(t, "no changes\n", out)
+}
+
+func TestLegacyCommands(t *testing.T) {
+	srv := fakeCNS(t, testIPs(), nil)
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	t.Setenv(envCNSIPAddress, u.Hostname())
+	t.Setenv(envCNSPort, u.Port())
+
+	require.NoError(t, HandleCNSClientCommands(context.Background(), "get", string(types.Assigned)))
+	require.Error(t, HandleCNSClientCommands(context.Background(), "unknown", ""))
+}
+
+func TestOptionsFromConfig(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "cns_config.json")
+	require.NoError(t, os.WriteFile(configPath, []byte(`{
+		"UseHTTPS": true,
+		"UseMTLS": true,
+		"TLSPort": "10091",
+		"TLSSubjectName": "cns.example.com",
+		"TLSCertificatePath": "/etc/cns/cert.pem"
+	}`), 0o600))
+
+	opts := &options{address: defaultAddress, configPath: configPath, output: outputTable}
+	require.NoError(t, opts.validate())
+	u, err := url.Parse(opts.address)
+	require.NoError(t, err)
+	require.NoError(t, opts.applyConfig(u))
+	assert.Equal(t, "https://localhost:10091", u.String())
+	assert.Equal(t, "cns.example.com", opts.serverName)
+	assert.Equal(t, "/etc/cns/cert.pem", opts.certFile)
+	assert.Equal(t, "/etc/cns/cert.pem", opts.caFile)
+
+	_, err = opts.client()
+	require.ErrorContains(t, err, "failed to read CA file")
+
+	opts = &options{address: defaultAddress, unixSocket: "/var/run/cns.sock", configPath: configPath, output: outputTable}
+	require.ErrorContains(t, opts.validate(), "mutually exclusive")
+	opts = &options{address: defaultAddress, output: "xml"}
+	require.ErrorContains(t, opts.validate(), "invalid output format")
+	opts = &options{address: defaultAddress, caFile: "ca.pem", output: outputTable}
+	_, err = opts.client()
+	require.ErrorContains(t, err, "require an https address")
+}
@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func newEndpointCmd(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "endpoint <container ID>",
+		Short: "Show the state CNS holds for an endpoint",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := opts.client()
+			if err != nil {
+				return err
+			}
+			resp, err := c.GetEndpoint(cmd.Context(), args[0])
+			if err != nil {
+				return errors.Wrapf(err, "failed to get endpoint %s", args[0])
+			}
+			ep := resp.EndpointInfo
+			return opts.printer(cmd.OutOrStdout()).print(ep, func(w io.Writer) {
+				fmt.Fprintf(w, "Pod:\t%s/%s\n\n", ep.PodNamespace, ep.PodName)
+				ifnames := make([]string, 0, len(ep.IfnameToIPMap))
+				for ifname := range ep.IfnameToIPMap {
+					ifnames = append(ifnames, ifname)
+				}
+				sort.Strings(ifnames)
+				fmt.Fprintln(w, "INTERFACE\tTYPE\tIPS\tMAC\tHOST VETH\tHNS ENDPOINT")
+				for _, ifname := range ifnames {
+					info := ep.IfnameToIPMap[ifname]
+					if info == nil {
+						continue
+					}
+					ips := make([]string, 0, len(info.IPv4)+len(info.IPv6))
+					for i := range info.IPv4 {
+						ips = append(ips, info.IPv4[i].String())
+					}
+					for i := range info.IPv6 {
+						ips = append(ips, info.IPv6[i].String())
+					}
+					fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+						ifname, info.NICType, strings.Join(ips, ","), info.MacAddress, info.HostVethName, info.HnsEndpointID)
+				}
+			})
+		},
+	}
+}
@@ -0,0 +1,196 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"net/netip"
+	"sort"
+	"strings"
+
+	"github.com/Azure/azure-container-networking/cns"
+	"github.com/Azure/azure-container-networking/cns/types"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var allIPStates = []types.IPState{types.Available, types.Assigned, types.PendingProgramming, types.PendingRelease}
+
+// ipRow is the output form of an IPConfigurationStatus.
+type ipRow struct {
+	IP    string        `json:"ip"`
+	ID    string        `json:"id"`
+	NCID  string        `json:"ncID"`
+	State types.IPState `json:"state"`
+	// Pod is the namespace/name of the pod the IP is assigned to, if any.
+	Pod              string `json:"pod,omitempty"`
+	InfraContainerID string `json:"infraContainerID,omitempty"`
+	InterfaceID      string `json:"interfaceID,omitempty"`
+}
+
+func toIPRows(ips []cns.IPConfigurationStatus) []ipRow {
+	rows := make([]ipRow, len(ips))
+	for i := range ips {
+		rows[i] = ipRow{IP: ips[i].IPAddress, ID: ips[i].ID, NCID: ips[i].NCID, State: ips[i].GetState()}
+		// IPs which aren't assigned decode with an empty PodInfo
+		if pi := ips[i].PodInfo; pi != nil && pi.Name() != "" {
+			rows[i].Pod = pi.Namespace() + "/" + pi.Name()
+			rows[i].InfraContainerID = pi.InfraContainerID()
+			rows[i].InterfaceID = pi.InterfaceID()
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		a, errA := netip.ParseAddr(rows[i].IP)
+		b, errB := netip.ParseAddr(rows[j].IP)
+		if errA != nil || errB != nil {
+			return rows[i].IP < rows[j].IP
+		}
+		return a.Less(b)
+	})
+	return rows
+}
+
+func writeIPTable(w io.Writer, rows []ipRow) {
+	fmt.Fprintln(w, "IP\tSTATE\tNC\tPOD")
+	for i := range rows {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", rows[i].IP, rows[i].State, rows[i].NCID, rows[i].Pod)
+	}
+}
+
+func parseIPStates(states []string) ([]types.IPState, error) {
+	if len(states) == 0 {
+		return allIPStates, nil
+	}
+	out := make([]types.IPState, 0, len(states))
+	for _, s := range states {
+		var found bool
+		for _, state := range allIPStates {
+			if strings.EqualFold(s, string(state)) {
+				out = append(out, state)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, errors.Errorf("invalid IP state %q, must be one of %v", s, allIPStates)
+		}
+	}
+	return out, nil
+}
+
+func newIPsCmd(opts *options) *cobra.Command {
+	var states []string
+	cmd := &cobra.Command{
+		Use:   "ips",
+		Short: "List the IPs in the pool",
+		Long:  "List the IPs in the pool, optionally only those in the given states.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			filter, err := parseIPStates(states)
+			if err != nil {
+				return err
+			}
+			c, err := opts.client()
+			if err != nil {
+				return err
+			}
+			ips, err := c.GetIPAddressesMatchingStates(cmd.Context(), filter...)
+			if err != nil {
+				return errors.Wrap(err, "failed to get IPs")
+			}
+			rows := toIPRows(ips)
+			return opts.printer(cmd.OutOrStdout()).print(rows, func(w io.Writer) { writeIPTable(w, rows) })
+		},
+	}
+	cmd.Flags().StringSliceVarP(&states, "state", "s", nil, "only list IPs in these states: Available, Assigned, PendingProgramming or PendingRelease")
+	return cmd
+}
+
+func newPodCmd(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "pod <ip | namespace/name>",
+		Short: "Look up the IPs of a pod, or the pod an IP is assigned to",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := opts.client()
+			if err != nil {
+				return err
+			}
+			ips, err := c.GetIPAddressesMatchingStates(cmd.Context(), allIPStates...)
+			if err != nil {
+				return errors.Wrap(err, "failed to get IPs")
+			}
+			var matched []ipRow
+			for _, row := range toIPRows(ips) {
+				if row.IP == args[0] || (row.Pod != "" && row.Pod == args[0]) {
+					matched = append(matched, row)
+				}
+			}
+			if len(matched) == 0 {
+				return errors.Errorf("no IP or pod %s found", args[0])
+			}
+			return opts.printer(cmd.OutOrStdout()).print(matched, func(w io.Writer) {
+				fmt.Fprintln(w, "IP\tSTATE\tNC\tPOD\tINFRA CONTAINER\tINTERFACE")
+				for i := range matched {
+					fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+						matched[i].IP, matched[i].State, matched[i].NCID, matched[i].Pod, matched[i].InfraContainerID, matched[i].InterfaceID)
+				}
+			})
+		},
+	}
+}
+
+func newPodsCmd(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "pods",
+		Short: "List the pods CNS has assigned IPs to",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			c, err := opts.client()
+			if err != nil {
+				return err
+			}
+			pods, err := c.GetPodOrchestratorContext(cmd.Context())
+			if err != nil {
+				return errors.Wrap(err, "failed to get pod contexts")
+			}
+			return opts.printer(cmd.OutOrStdout()).print(pods, func(w io.Writer) {
+				keys := make([]string, 0, len(pods))
+				for k := range pods {
+					keys = append(keys, k)
+				}
+				sort.Strings(keys)
+				fmt.Fprintln(w, "POD\tIP IDS")
+				for _, k := range keys {
+					fmt.Fprintf(w, "%s\t%s\n", k, strings.Join(pods[k], ","))
+				}
+			})
+		},
+	}
+}
+
+func newDumpCmd(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "dump",
+		Short: "Dump the in-memory CNS IPAM state",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			c, err := opts.client()
+			if err != nil {
+				return err
+			}
+			data, err := c.GetHTTPServiceData(cmd.Context())
+			if err != nil {
+				return errors.Wrap(err, "failed to get in-memory state")
+			}
+			state := data.HTTPRestServiceData
+			return opts.printer(cmd.OutOrStdout()).print(state, func(w io.Writer) {
+				ips := make([]cns.IPConfigurationStatus, 0, len(state.PodIPConfigState))
+				for id := range state.PodIPConfigState {
+					ips = append(ips, state.PodIPConfigState[id])
+				}
+				writeIPTable(w, toIPRows(ips))
+				fmt.Fprintf(w, "\n%d pod interfaces\n", len(state.PodIPIDByPodInterfaceKey))
+			})
+		},
+	}
+}
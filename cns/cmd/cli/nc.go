@@ -0,0 +1,174 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/Azure/azure-container-networking/cns"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func newNCCmd(opts *options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "nc",
+		Short: "Inspect the network containers CNS has been given",
+	}
+	cmd.AddCommand(newNCListCmd(opts), newNCDiffCmd(opts))
+	return cmd
+}
+
+func listNCs(ctx context.Context, opts *options) ([]cns.GetNetworkContainerResponse, error) {
+	c, err := opts.client()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.GetAllNCsFromCns(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get network containers")
+	}
+	ncs := resp.NetworkContainers
+	sort.Slice(ncs, func(i, j int) bool { return ncs[i].NetworkContainerID < ncs[j].NetworkContainerID })
+	return ncs, nil
+}
+
+func newNCListCmd(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the network containers",
+		Long: "List the network containers. The JSON output is a snapshot which can be compared with another, " +
+			"or with the current network containers, by nc diff.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ncs, err := listNCs(cmd.Context(), opts)
+			if err != nil {
+				return err
+			}
+			return opts.printer(cmd.OutOrStdout()).print(ncs, func(w io.Writer) {
+				fmt.Fprintln(w, "NC\tSUBNET\tGATEWAY\tROUTES\tPRIMARY INTERFACE")
+				for i := range ncs {
+					ipc := ncs[i].IPConfiguration
+					fmt.Fprintf(w, "%s\t%s/%d\t%s\t%d\t%s\n", ncs[i].NetworkContainerID, ipc.IPSubnet.IPAddress, ipc.IPSubnet.PrefixLength,
+						ipc.GatewayIPAddress, len(ncs[i].Routes), ncs[i].PrimaryInterfaceIdentifier)
+				}
+			})
+		},
+	}
+}
+
+// ncChange is a network container which differs between two snapshots.
+type ncChange struct {
+	NCID   string `json:"ncID"`
+	Change string `json:"change"`
+	// Fields are the fields of a changed network container which differ.
+	Fields []string                         `json:"fields,omitempty"`
+	Before *cns.GetNetworkContainerResponse `json:"before,omitempty"`
+	After  *cns.GetNetworkContainerResponse `json:"after,omitempty"`
+}
+
+const (
+	ncAdded   = "added"
+	ncRemoved = "removed"
+	ncChanged = "changed"
+)
+
+// diffNCs returns the network containers added, removed or changed from before to after, sorted by ID. The Response
+// of each network container is ignored.
+func diffNCs(before, after []cns.GetNetworkContainerResponse) []ncChange {
+	byID := func(ncs []cns.GetNetworkContainerResponse) map[string]*cns.GetNetworkContainerResponse {
+		m := make(map[string]*cns.GetNetworkContainerResponse, len(ncs))
+		for i := range ncs {
+			m[ncs[i].NetworkContainerID] = &ncs[i]
+		}
+		return m
+	}
+	b, a := byID(before), byID(after)
+
+	var changes []ncChange
+	for id, nc := range b {
+		if _, ok := a[id]; !ok {
+			changes = append(changes, ncChange{NCID: id, Change: ncRemoved, Before: nc})
+		}
+	}
+	for id, nc := range a {
+		old, ok := b[id]
+		if !ok {
+			changes = append(changes, ncChange{NCID: id, Change: ncAdded, After: nc})
+			continue
+		}
+		if fields := diffNCFields(old, nc); len(fields) > 0 {
+			changes = append(changes, ncChange{NCID: id, Change: ncChanged, Fields: fields, Before: old, After: nc})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].NCID < changes[j].NCID })
+	return changes
+}
+
+func diffNCFields(before, after *cns.GetNetworkContainerResponse) []string {
+	var fields []string
+	bv, av := reflect.ValueOf(*before), reflect.ValueOf(*after)
+	for i := 0; i < bv.NumField(); i++ {
+		name := bv.Type().Field(i).Name
+		if name == "Response" {
+			continue
+		}
+		if !reflect.DeepEqual(bv.Field(i).Interface(), av.Field(i).Interface()) {
+			fields = append(fields, name)
+		}
+	}
+	return fields
+}
+
+func loadNCSnapshot(path string) ([]cns.GetNetworkContainerResponse, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read snapshot %s", path)
+	}
+	var ncs []cns.GetNetworkContainerResponse
+	if err := json.Unmarshal(b, &ncs); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal snapshot %s, expected the JSON output of nc list", path)
+	}
+	return ncs, nil
+}
+
+func newNCDiffCmd(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "diff <before.json> [after.json]",
+		Short: "Compare network container snapshots",
+		Long: "Compare a snapshot of the network containers, saved with nc list -o json, with another snapshot, " +
+			"or with the current network containers if only one is given.",
+		Args: cobra.RangeArgs(1, 2), //nolint:gomnd // before and after
+		RunE: func(cmd *cobra.Command, args []string) error {
+			before, err := loadNCSnapshot(args[0])
+			if err != nil {
+				return err
+			}
+			var after []cns.GetNetworkContainerResponse
+			if len(args) == 2 { //nolint:gomnd // before and after
+				after, err = loadNCSnapshot(args[1])
+			} else {
+				after, err = listNCs(cmd.Context(), opts)
+			}
+			if err != nil {
+				return err
+			}
+			changes := diffNCs(before, after)
+			return opts.printer(cmd.OutOrStdout()).print(changes, func(w io.Writer) {
+				if len(changes) == 0 {
+					fmt.Fprintln(w, "no changes")
+					return
+				}
+				fmt.Fprintln(w, "NC\tCHANGE\tFIELDS")
+				for i := range changes {
+					fmt.Fprintf(w, "%s\t%s\t%s\n", changes[i].NCID, changes[i].Change, strings.Join(changes[i].Fields, ","))
+				}
+			})
+		},
+	}
+}
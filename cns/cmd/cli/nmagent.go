@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func newNMAgentCmd(opts *options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "nmagent",
+		Short: "Query NMAgent through CNS",
+	}
+	cmd.AddCommand(newNMAgentAPIsCmd(opts), newNMAgentHomeAZCmd(opts))
+	return cmd
+}
+
+func newNMAgentAPIsCmd(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "apis",
+		Short: "List the APIs NMAgent supports",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			c, err := opts.client()
+			if err != nil {
+				return err
+			}
+			resp, err := c.NMAgentSupportedAPIs(cmd.Context())
+			if err != nil {
+				return errors.Wrap(err, "failed to get NMAgent supported APIs")
+			}
+			apis := resp.SupportedApis
+			return opts.printer(cmd.OutOrStdout()).print(apis, func(w io.Writer) {
+				for _, api := range apis {
+					fmt.Fprintln(w, api)
+				}
+			})
+		},
+	}
+}
+
+func newNMAgentHomeAZCmd(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "homeaz",
+		Short: "Show the home availability zone of the node",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			c, err := opts.client()
+			if err != nil {
+				return err
+			}
+			resp, err := c.GetHomeAz(cmd.Context())
+			if err != nil {
+				return errors.Wrap(err, "failed to get home AZ")
+			}
+			homeAZ := resp.HomeAzResponse
+			return opts.printer(cmd.OutOrStdout()).print(homeAZ, func(w io.Writer) {
+				if !homeAZ.IsSupported {
+					fmt.Fprintln(w, "home AZ is not supported by NMAgent on this node")
+					return
+				}
+				fmt.Fprintf(w, "Home AZ:\t%d\n", homeAZ.HomeAz)
+			})
+		},
+	}
+}
@@ -0,0 +1,164 @@
+package cli
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/Azure/azure-container-networking/cns/client"
+	"github.com/Azure/azure-container-networking/cns/configuration"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const (
+	defaultAddress = "http://localhost:10090"
+	// unixSocketBaseURL is the base URL of requests sent over a unix socket, where the host is ignored.
+	unixSocketBaseURL = "http://localhost"
+)
+
+// options are the connection and output options shared by all commands.
+type options struct {
+	address            string
+	unixSocket         string
+	configPath         string
+	caFile             string
+	certFile           string
+	keyFile            string
+	serverName         string
+	insecureSkipVerify bool
+	timeout            time.Duration
+	output             string
+}
+
+func (o *options) addFlags(cmd *cobra.Command) {
+	flags := cmd.PersistentFlags()
+	flags.StringVarP(&o.address, "address", "a", defaultAddress, "CNS URL, http://host:port or https://host:port")
+	flags.StringVar(&o.unixSocket, "unix-socket", "", "connect to CNS over the unix socket at this path instead of the address")
+	flags.StringVarP(&o.configPath, "config", "c", "",
+		"CNS config file to take the TLS and mTLS settings from, as the server does. The address port is replaced with the TLSPort")
+	flags.StringVar(&o.caFile, "tls-ca", "", "PEM file of the CA certificates to verify CNS with, instead of the system roots")
+	flags.StringVar(&o.certFile, "tls-cert", "", "PEM file of the client certificate for mTLS")
+	flags.StringVar(&o.keyFile, "tls-key", "", "PEM file of the client certificate's private key for mTLS, if not in the --tls-cert file")
+	flags.StringVar(&o.serverName, "tls-server-name", "", "name to verify the CNS certificate against, if not the address host")
+	flags.BoolVar(&o.insecureSkipVerify, "insecure-skip-verify", false, "don't verify the CNS certificate")
+	flags.DurationVar(&o.timeout, "timeout", client.DefaultTimeout, "request timeout")
+	flags.StringVarP(&o.output, "output", "o", outputTable, "output format, one of table, json or yaml")
+}
+
+func (o *options) validate() error {
+	switch o.output {
+	case outputTable, outputJSON, outputYAML:
+	default:
+		return errors.Errorf("invalid output format %q, must be one of table, json or yaml", o.output)
+	}
+	if o.unixSocket != "" && o.configPath != "" {
+		return errors.New("--unix-socket and --config are mutually exclusive")
+	}
+	return nil
+}
+
+// applyConfig takes the TLS settings of the CNS config file: when the server uses HTTPS, the client connects to its
+// TLSPort and verifies it with its certificate's subject name, and when it uses mTLS, the client presents the same
+// certificate bundle, which the server trusts.
+func (o *options) applyConfig(u *url.URL) error {
+	b, err := os.ReadFile(o.configPath)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read config file %s", o.configPath)
+	}
+	var config configuration.CNSConfig
+	if err := json.Unmarshal(b, &config); err != nil {
+		return errors.Wrapf(err, "failed to unmarshal config file %s", o.configPath)
+	}
+	if !config.UseHTTPS {
+		return nil
+	}
+	if config.TLSPort == "" {
+		return errors.Errorf("config file %s enables HTTPS without a TLSPort", o.configPath)
+	}
+	u.Scheme = "https"
+	u.Host = net.JoinHostPort(u.Hostname(), config.TLSPort)
+	if o.serverName == "" {
+		o.serverName = config.TLSSubjectName
+	}
+	if config.UseMTLS && o.certFile == "" {
+		if config.TLSCertificatePath == "" {
+			return errors.Errorf("config file %s enables mTLS without a TLSCertificatePath, pass --tls-cert", o.configPath)
+		}
+		o.certFile = config.TLSCertificatePath
+		if o.caFile == "" {
+			o.caFile = config.TLSCertificatePath
+		}
+	}
+	return nil
+}
+
+func (o *options) tlsConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         o.serverName,
+		InsecureSkipVerify: o.insecureSkipVerify, //nolint:gosec // opt-in for debugging
+	}
+	if o.caFile != "" {
+		b, err := os.ReadFile(o.caFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read CA file %s", o.caFile)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(b) {
+			return nil, errors.Errorf("no certificates found in CA file %s", o.caFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if o.certFile != "" {
+		keyFile := o.keyFile
+		if keyFile == "" {
+			keyFile = o.certFile
+		}
+		cert, err := tls.LoadX509KeyPair(o.certFile, keyFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load client certificate")
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
+// client returns a CNS client connected as configured by the options.
+func (o *options) client() (*client.Client, error) {
+	if o.unixSocket != "" {
+		socket := o.unixSocket
+		transport := &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", socket)
+			},
+		}
+		return client.NewWithHTTPClient(unixSocketBaseURL, &http.Client{Transport: transport, Timeout: o.timeout}) //nolint:wrapcheck // client errors are descriptive
+	}
+
+	u, err := url.Parse(o.address)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid address %s", o.address)
+	}
+	if o.configPath != "" {
+		if err := o.applyConfig(u); err != nil {
+			return nil, err
+		}
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if u.Scheme == "https" {
+		if transport.TLSClientConfig, err = o.tlsConfig(); err != nil {
+			return nil, err
+		}
+	} else if o.certFile != "" || o.caFile != "" {
+		return nil, errors.Errorf("TLS options require an https address, got %s", o.address)
+	}
+	return client.NewWithHTTPClient(u.String(), &http.Client{Transport: transport, Timeout: o.timeout}) //nolint:wrapcheck // client errors are descriptive
+}
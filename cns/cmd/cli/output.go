@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+)
+
+// printer writes the result of a command in the output format, using its table function for the table format.
+type printer struct {
+	format string
+	out    io.Writer
+}
+
+func (o *options) printer(out io.Writer) *printer {
+	return &printer{format: o.output, out: out}
+}
+
+// print writes v as JSON or YAML, or calls table to write it as a table.
+func (p *printer) print(v any, table func(w io.Writer)) error {
+	switch p.format {
+	case outputJSON:
+		enc := json.NewEncoder(p.out)
+		enc.SetIndent("", "  ")
+		return errors.Wrap(enc.Encode(v), "failed to write JSON")
+	case outputYAML:
+		b, err := yaml.Marshal(v)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal YAML")
+		}
+		_, err = p.out.Write(b)
+		return errors.Wrap(err, "failed to write YAML")
+	default:
+		var buf bytes.Buffer
+		tw := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0) //nolint:gomnd // padding
+		table(tw)
+		if err := tw.Flush(); err != nil {
+			return errors.Wrap(err, "failed to format table")
+		}
+		// drop the padding of empty trailing columns
+		lines := bytes.SplitAfter(buf.Bytes(), []byte("\n"))
+		for _, line := range lines {
+			trimmed := bytes.TrimRight(line, " \n")
+			if len(line) > 0 && line[len(line)-1] == '\n' {
+				trimmed = append(trimmed, '\n')
+			}
+			if _, err := p.out.Write(trimmed); err != nil {
+				return errors.Wrap(err, "failed to write table")
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func newPoolCmd(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "pool",
+		Short: "Show the state of the IPAM pool monitor",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			c, err := opts.client()
+			if err != nil {
+				return err
+			}
+			snapshot, err := c.GetPoolMonitorState(cmd.Context())
+			if err != nil {
+				return errors.Wrap(err, "failed to get pool monitor state")
+			}
+			return opts.printer(cmd.OutOrStdout()).print(snapshot, func(w io.Writer) {
+				spec := snapshot.CachedNNC.Spec
+				fmt.Fprintf(w, "Minimum free IPs:\t%d\n", snapshot.MinimumFreeIps)
+				fmt.Fprintf(w, "Maximum free IPs:\t%d\n", snapshot.MaximumFreeIps)
+				fmt.Fprintf(w, "IPs not in use being released:\t%d\n", snapshot.UpdatingIpsNotInUseCount)
+				fmt.Fprintf(w, "Requested IP count:\t%d\n", spec.RequestedIPCount)
+				fmt.Fprintf(w, "IPs not in use:\t%d\n", len(spec.IPsNotInUse))
+				scaler := snapshot.CachedNNC.Status.Scaler
+				fmt.Fprintf(w, "Batch size:\t%d\n", scaler.BatchSize)
+				fmt.Fprintf(w, "Max IP count:\t%d\n", scaler.MaxIPCount)
+				fmt.Fprintf(w, "Request threshold percent:\t%d\n", scaler.RequestThresholdPercent)
+				fmt.Fprintf(w, "Release threshold percent:\t%d\n", scaler.ReleaseThresholdPercent)
+				fmt.Fprintf(w, "Network containers:\t%d\n", len(snapshot.CachedNNC.Status.NetworkContainers))
+			})
+		},
+	}
+}
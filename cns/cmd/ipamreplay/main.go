@@ -0,0 +1,75 @@
+// ipamreplay replays a recorded trace of NodeNetworkConfig and ClusterSubnetState updates and IP requests and
+// releases against the CNS IP pool, NodeNetworkConfig reconciler and IPAM pool monitor, and prints the timeline of
+// changes and the final state.
+//
+// It exits 1 if the final state differs from the expectation in the trace, and 2 if the trace can't be replayed.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/Azure/azure-container-networking/cns/cmd/ipamreplay/replay"
+	"github.com/Azure/azure-container-networking/cns/logger"
+	acnlog "github.com/Azure/azure-container-networking/log"
+)
+
+func main() {
+	var (
+		output = flag.String("o", "text", "output format, text or json")
+		logDir = flag.String("log-dir", os.TempDir(), "directory to write the CNS logs to")
+	)
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags] trace.json\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 || (*output != "text" && *output != "json") {
+		flag.Usage()
+		os.Exit(2) //nolint:gomnd // usage error
+	}
+
+	code, err := run(flag.Arg(0), *output, *logDir)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	os.Exit(code)
+}
+
+func run(path, output, logDir string) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 2, err //nolint:gomnd // replay error
+	}
+	defer f.Close()
+	trace, err := replay.Load(f)
+	if err != nil {
+		return 2, err //nolint:gomnd // replay error
+	}
+
+	logger.InitLogger("ipamreplay", acnlog.LevelInfo, acnlog.TargetLogfile, logDir)
+	defer logger.Close()
+
+	report, err := replay.Run(context.Background(), trace)
+	if err != nil {
+		return 2, err //nolint:gomnd // replay error
+	}
+
+	if output == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(report)
+	} else {
+		err = report.WriteText(os.Stdout)
+	}
+	if err != nil {
+		return 2, err //nolint:gomnd // replay error
+	}
+	if len(report.Diff) > 0 {
+		return 1, nil
+	}
+	return 0, nil
+}
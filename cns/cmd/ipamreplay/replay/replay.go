@@ -0,0 +1,285 @@
+package replay
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/Azure/azure-container-networking/cns"
+	"github.com/Azure/azure-container-networking/cns/common"
+	"github.com/Azure/azure-container-networking/cns/events"
+	"github.com/Azure/azure-container-networking/cns/fakes"
+	"github.com/Azure/azure-container-networking/cns/ipampool"
+	"github.com/Azure/azure-container-networking/cns/kubecontroller/nodenetworkconfig"
+	"github.com/Azure/azure-container-networking/cns/restserver"
+	"github.com/Azure/azure-container-networking/cns/types"
+	"github.com/Azure/azure-container-networking/crd/nodenetworkconfig/api/v1alpha"
+	"github.com/Azure/azure-container-networking/nmagent"
+	"github.com/Azure/azure-container-networking/store"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// apiserver is the fake NodeNetworkConfig store which the reconciler reads from and the pool monitor writes the
+// spec to.
+type apiserver struct {
+	mu      sync.Mutex
+	nnc     *v1alpha.NodeNetworkConfig
+	patches []v1alpha.NodeNetworkConfigSpec
+}
+
+func (a *apiserver) set(nnc *v1alpha.NodeNetworkConfig) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	next := nnc.DeepCopy()
+	if a.nnc != nil && next.Spec.RequestedIPCount == 0 && len(next.Spec.IPsNotInUse) == 0 {
+		next.Spec = *a.nnc.Spec.DeepCopy()
+	}
+	a.nnc = next
+}
+
+func (a *apiserver) Get(_ context.Context, key k8stypes.NamespacedName) (*v1alpha.NodeNetworkConfig, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.nnc == nil {
+		return nil, apierrors.NewNotFound(schema.GroupResource{Group: v1alpha.GroupVersion.Group, Resource: "nodenetworkconfigs"}, key.Name)
+	}
+	return a.nnc.DeepCopy(), nil
+}
+
+func (a *apiserver) PatchSpec(_ context.Context, spec *v1alpha.NodeNetworkConfigSpec, _ string) (*v1alpha.NodeNetworkConfig, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.nnc == nil {
+		a.nnc = &v1alpha.NodeNetworkConfig{}
+	}
+	a.nnc.Spec = *spec.DeepCopy()
+	a.patches = append(a.patches, *spec.DeepCopy())
+	return a.nnc.DeepCopy(), nil
+}
+
+// spec returns the current NodeNetworkConfig spec and the specs patched since the last call.
+func (a *apiserver) spec() (v1alpha.NodeNetworkConfigSpec, []v1alpha.NodeNetworkConfigSpec) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	patches := a.patches
+	a.patches = nil
+	if a.nnc == nil {
+		return v1alpha.NodeNetworkConfigSpec{}, patches
+	}
+	return *a.nnc.Spec.DeepCopy(), patches
+}
+
+// nmagentVersions is the NC version list reported by the fake NMAgent.
+type nmagentVersions struct {
+	mu       sync.Mutex
+	versions map[string]int
+}
+
+func (n *nmagentVersions) set(versions map[string]int) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.versions = versions
+}
+
+func (n *nmagentVersions) list(context.Context) (nmagent.NCVersionList, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	var list nmagent.NCVersionList
+	for ncID, version := range n.versions {
+		list.Containers = append(list.Containers, nmagent.NCVersion{NetworkContainerID: ncID, Version: strconv.Itoa(version)})
+	}
+	return list, nil
+}
+
+// monitorListener passes the reconciled NodeNetworkConfigs to the pool monitor synchronously.
+type monitorListener struct {
+	ctx context.Context //nolint:containedctx // the reconciler's listener interface has no context
+	pm  *ipampool.Monitor
+}
+
+func (m *monitorListener) Update(nnc *v1alpha.NodeNetworkConfig) error {
+	return m.pm.Step(m.ctx, nnc, nil)
+}
+
+// Replayer holds the CNS IPAM components which a Trace is replayed against.
+type Replayer struct {
+	service    *restserver.HTTPRestService
+	monitor    *ipampool.Monitor
+	reconciler *nodenetworkconfig.Reconciler
+	apiserver  *apiserver
+	nmagent    *nmagentVersions
+	cursor     uint64
+}
+
+// New creates a Replayer around a new HTTPRestService in CRD mode, with the NodeNetworkConfig reconciler and pool
+// monitor wired to a fake apiserver, and a fake NMAgent.
+func New(ctx context.Context, nodeIP string) (*Replayer, error) {
+	nma := &nmagentVersions{}
+	var config common.ServiceConfig
+	service, err := restserver.NewHTTPRestService(&config, &fakes.WireserverClientFake{}, &fakes.WireserverProxyFake{},
+		&fakes.NMAgentClientFake{GetNCVersionListF: nma.list}, store.NewMockStore(""), &restserver.NoOpConflistGenerator{}, nil,
+		fakes.NewMockIMDSClient())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create CNS service")
+	}
+	service.SetNodeOrchestrator(&cns.SetOrchestratorTypeRequest{OrchestratorType: cns.KubernetesCRD})
+
+	api := &apiserver{}
+	monitor := ipampool.NewMonitor(service, api, nil, &ipampool.Options{Events: service.EventLog()})
+	reconciler := nodenetworkconfig.NewReconciler(service, &monitorListener{ctx: ctx, pm: monitor}, nodeIP).WithGetter(api)
+	return &Replayer{
+		service:    service,
+		monitor:    monitor,
+		reconciler: reconciler,
+		apiserver:  api,
+		nmagent:    nma,
+		cursor:     service.EventLog().Head(),
+	}, nil
+}
+
+// Run replays the Trace and reports the timeline of the changes each step made, the final state, and its
+// differences from the trace's expectation.
+func Run(ctx context.Context, trace *Trace) (*Report, error) {
+	r, err := New(ctx, trace.NodeIP)
+	if err != nil {
+		return nil, err
+	}
+	report := &Report{}
+	for i := range trace.Steps {
+		entry := r.Step(ctx, &trace.Steps[i])
+		entry.Step = i
+		report.Timeline = append(report.Timeline, entry)
+		if entry.Panic {
+			break
+		}
+	}
+	report.Final = r.State()
+	if trace.Expect != nil {
+		report.Diff = trace.Expect.Diff(&report.Final)
+	}
+	return report, nil
+}
+
+// Step applies a single step and returns the changes it made. A panic in CNS is recovered and reported in the
+// entry, after which the Replayer must not be used.
+func (r *Replayer) Step(ctx context.Context, step *Step) (entry Entry) {
+	entry = Entry{At: step.At, Action: step.Action(), Comment: step.Comment}
+	defer func() {
+		if rec := recover(); rec != nil {
+			entry.Panic = true
+			entry.Error = fmt.Sprintf("panic: %v", rec)
+		}
+		entry.Changes = append(entry.Changes, r.changes()...)
+	}()
+	if err := r.apply(ctx, step); err != nil {
+		entry.Error = err.Error()
+	}
+	return entry
+}
+
+func (r *Replayer) apply(ctx context.Context, step *Step) error {
+	switch step.Action() {
+	case "nnc":
+		r.apiserver.set(step.NodeNetworkConfig)
+		key := k8stypes.NamespacedName{Namespace: step.NodeNetworkConfig.Namespace, Name: step.NodeNetworkConfig.Name}
+		_, err := r.reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		return errors.Wrap(err, "reconcile failed")
+	case "css":
+		return errors.Wrap(r.monitor.Step(ctx, nil, step.ClusterSubnetState), "pool monitor failed")
+	case "nmagent":
+		r.nmagent.set(step.NMAgent)
+		r.service.SyncHostNCVersion(ctx, cns.CRD)
+		return nil
+	case "request":
+		resp, err := r.service.RequestIPConfigsHandlerHelper(ctx, *step.Request)
+		return responseError(resp, err)
+	case "release":
+		resp, err := r.service.ReleaseIPConfigHandlerHelper(ctx, *step.Release)
+		return responseError(resp, err)
+	case "tick":
+		return errors.Wrap(r.monitor.Step(ctx, nil, nil), "pool monitor failed")
+	}
+	return errors.Errorf("invalid step %+v", step)
+}
+
+func responseError(resp *cns.IPConfigsResponse, err error) error {
+	if err != nil {
+		if resp != nil {
+			return errors.Wrapf(err, "%s: %s", resp.Response.ReturnCode, resp.Response.Message)
+		}
+		return err
+	}
+	return nil
+}
+
+// changes describes the events published and the specs patched since it was last called.
+func (r *Replayer) changes() []string {
+	page := r.service.EventLog().Since(r.cursor)
+	r.cursor = page.Cursor
+	var changes []string
+	if page.Truncated {
+		changes = append(changes, "some events were dropped")
+	}
+	for i := range page.Events {
+		changes = append(changes, describeEvent(&page.Events[i]))
+	}
+	_, patches := r.apiserver.spec()
+	for i := range patches {
+		changes = append(changes, fmt.Sprintf("nnc spec patched: requestedIPCount %d, ipsNotInUse %d",
+			patches[i].RequestedIPCount, len(patches[i].IPsNotInUse)))
+	}
+	return changes
+}
+
+func describeEvent(e *events.Event) string {
+	switch {
+	case e.IP != nil && e.Type == events.IPRemoved:
+		return fmt.Sprintf("ip %s removed from %s", e.IP.IPAddress, e.IP.PreviousState)
+	case e.IP != nil:
+		s := fmt.Sprintf("ip %s %s -> %s", e.IP.IPAddress, e.IP.PreviousState, e.IP.State)
+		if e.IP.PreviousState == "" {
+			s = fmt.Sprintf("ip %s added as %s", e.IP.IPAddress, e.IP.State)
+		}
+		if e.IP.PodName != "" {
+			s += fmt.Sprintf(" (%s/%s)", e.IP.PodNamespace, e.IP.PodName)
+		}
+		return s
+	case e.NetworkContainer != nil:
+		return fmt.Sprintf("nc %s %s, version %q", e.NetworkContainer.ID,
+			strings.ToLower(strings.TrimPrefix(string(e.Type), "NetworkContainer")), e.NetworkContainer.Version)
+	case e.Pool != nil:
+		s := fmt.Sprintf("pool scaled: requestedIPCount %d -> %d", e.Pool.PreviousRequestedIPCount, e.Pool.RequestedIPCount)
+		if e.Pool.ReleasedIPCount > 0 {
+			s += fmt.Sprintf(", releasing %d", e.Pool.ReleasedIPCount)
+		}
+		return s
+	}
+	return string(e.Type)
+}
+
+// State returns the current IPs in the pool and NodeNetworkConfig spec.
+func (r *Replayer) State() State {
+	spec, _ := r.apiserver.spec()
+	state := State{
+		IPs:    map[string]IPStatus{},
+		Counts: map[types.IPState]int{},
+		Spec:   spec,
+	}
+	for _, ip := range r.service.GetPodIPConfigState() {
+		status := IPStatus{ID: ip.ID, NCID: ip.NCID, State: ip.GetState()}
+		if ip.PodInfo != nil {
+			status.Pod = ip.PodInfo.Namespace() + "/" + ip.PodInfo.Name()
+		}
+		state.IPs[ip.IPAddress] = status
+		state.Counts[status.State]++
+	}
+	sort.Strings(state.Spec.IPsNotInUse)
+	return state
+}
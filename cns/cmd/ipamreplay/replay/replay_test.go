@@ -0,0 +1,114 @@
+package replay
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-container-networking/cns/logger"
+	"github.com/Azure/azure-container-networking/cns/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func loadTrace(t *testing.T, path string) *Trace {
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	trace, err := Load(f)
+	require.NoError(t, err)
+	return trace
+}
+
+func TestRunStuckPendingRelease(t *testing.T) {
+	logger.InitLogger("testlogs", 0, 0, "./")
+	trace := loadTrace(t, "testdata/stuck-pending-release.json")
+
+	report, err := Run(context.Background(), trace)
+	require.NoError(t, err)
+	require.Len(t, report.Timeline, len(trace.Steps))
+	for i := range report.Timeline {
+		assert.Empty(t, report.Timeline[i].Error, "step %d", i)
+	}
+	assert.Empty(t, report.Diff)
+
+	// the IPs are programmed once NMAgent reports the NC version
+	assert.Contains(t, report.Timeline[1].Changes[0], "PendingProgramming -> Available")
+	// the pool scales up for the five pods, then back down once they are gone
+	assert.Equal(t, []string{"pool scaled: requestedIPCount 8 -> 16", "nnc spec patched: requestedIPCount 16, ipsNotInUse 0"}, report.Timeline[7].Changes)
+	scaleDown := report.Timeline[14].Changes
+	assert.Equal(t, "nnc spec patched: requestedIPCount 8, ipsNotInUse 8", scaleDown[len(scaleDown)-1])
+	// and nothing more happens while the released IPs are still in the NNC
+	assert.Empty(t, report.Timeline[15].Changes)
+
+	assert.Equal(t, map[types.IPState]int{types.Available: 8, types.PendingRelease: 8}, report.Final.Counts)
+	assert.Len(t, report.Final.IPs, 16)
+
+	var out strings.Builder
+	require.NoError(t, report.WriteText(&out))
+	assert.Contains(t, out.String(), "PendingRelease:    8")
+}
+
+func TestRunReportsErrorsAndDiff(t *testing.T) {
+	logger.InitLogger("testlogs", 0, 0, "./")
+	trace := loadTrace(t, "testdata/stuck-pending-release.json")
+	// requesting an IP before any NC has been reconciled fails.
+	trace.Steps = trace.Steps[2:3]
+	requested := int64(8)
+	trace.Expect = &Expectation{
+		IPs:              map[string]IPStatus{"10.240.0.5": {State: types.Assigned, Pod: "default/pod-1"}},
+		RequestedIPCount: &requested,
+	}
+
+	report, err := Run(context.Background(), trace)
+	require.NoError(t, err)
+	require.Len(t, report.Timeline, 1)
+	assert.NotEmpty(t, report.Timeline[0].Error)
+	assert.Equal(t, []Difference{
+		{Field: "ips[10.240.0.5]", Want: "Assigned", Got: "missing"},
+		{Field: "spec.requestedIPCount", Want: "8", Got: "0"},
+	}, report.Diff)
+}
+
+func TestLoadValidates(t *testing.T) {
+	tests := []struct {
+		name  string
+		trace string
+		err   string
+	}{
+		{
+			name:  "no input",
+			trace: `{"steps": [{"at": "1s"}]}`,
+			err:   "exactly one",
+		},
+		{
+			name:  "two inputs",
+			trace: `{"steps": [{"at": "1s", "tick": true, "nmagent": {"nc": 1}}]}`,
+			err:   "exactly one",
+		},
+		{
+			name:  "out of order",
+			trace: `{"steps": [{"at": "2s", "tick": true}, {"at": "1s", "tick": true}]}`,
+			err:   "before step 0",
+		},
+		{
+			name:  "bad duration",
+			trace: `{"steps": [{"at": "soon", "tick": true}]}`,
+			err:   "invalid duration",
+		},
+		{
+			name:  "unknown field",
+			trace: `{"steps": [{"at": "1s", "tock": true}]}`,
+			err:   "unknown field",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(strings.NewReader(tt.trace))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+		})
+	}
+}
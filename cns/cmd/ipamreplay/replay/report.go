@@ -0,0 +1,153 @@
+package replay
+
+import (
+	"fmt"
+	"io"
+	"net/netip"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/Azure/azure-container-networking/cns/types"
+	"github.com/Azure/azure-container-networking/crd/nodenetworkconfig/api/v1alpha"
+	"github.com/pkg/errors"
+)
+
+// Report is the outcome of replaying a Trace.
+type Report struct {
+	Timeline []Entry      `json:"timeline"`
+	Final    State        `json:"final"`
+	Diff     []Difference `json:"diff,omitempty"`
+}
+
+// Entry is a replayed step and the changes it made.
+type Entry struct {
+	Step    int      `json:"step"`
+	At      Duration `json:"at"`
+	Action  string   `json:"action"`
+	Comment string   `json:"comment,omitempty"`
+	Error   string   `json:"error,omitempty"`
+	// Panic is set if CNS panicked during the step, which ends the replay.
+	Panic   bool     `json:"panic,omitempty"`
+	Changes []string `json:"changes,omitempty"`
+}
+
+// IPStatus is the state of an IP in the pool.
+type IPStatus struct {
+	ID    string        `json:"id,omitempty"`
+	NCID  string        `json:"ncID,omitempty"`
+	State types.IPState `json:"state"`
+	// Pod is the namespace/name of the pod the IP is assigned to, if any.
+	Pod string `json:"pod,omitempty"`
+}
+
+// State is the IP pool and NodeNetworkConfig spec at the end of a replay.
+type State struct {
+	// IPs are the IPs in the pool, by address.
+	IPs    map[string]IPStatus           `json:"ips"`
+	Counts map[types.IPState]int         `json:"counts"`
+	Spec   v1alpha.NodeNetworkConfigSpec `json:"spec"`
+}
+
+// Difference is a field of the final State which doesn't match the Expectation.
+type Difference struct {
+	Field string `json:"field"`
+	Want  string `json:"want"`
+	Got   string `json:"got"`
+}
+
+// Diff compares the State with the Expectation, returning the differences sorted by field.
+func (e *Expectation) Diff(s *State) []Difference {
+	var diff []Difference
+	for addr, want := range e.IPs {
+		got, ok := s.IPs[addr]
+		if !ok {
+			diff = append(diff, Difference{Field: "ips[" + addr + "]", Want: string(want.State), Got: "missing"})
+			continue
+		}
+		if got.State != want.State {
+			diff = append(diff, Difference{Field: "ips[" + addr + "].state", Want: string(want.State), Got: string(got.State)})
+		}
+		if got.Pod != want.Pod {
+			diff = append(diff, Difference{Field: "ips[" + addr + "].pod", Want: want.Pod, Got: got.Pod})
+		}
+	}
+	for state, want := range e.Counts {
+		if got := s.Counts[state]; got != want {
+			diff = append(diff, Difference{Field: "counts[" + string(state) + "]", Want: strconv.Itoa(want), Got: strconv.Itoa(got)})
+		}
+	}
+	if e.RequestedIPCount != nil && *e.RequestedIPCount != s.Spec.RequestedIPCount {
+		diff = append(diff, Difference{
+			Field: "spec.requestedIPCount",
+			Want:  strconv.FormatInt(*e.RequestedIPCount, 10),
+			Got:   strconv.FormatInt(s.Spec.RequestedIPCount, 10),
+		})
+	}
+	if e.IPsNotInUse != nil && *e.IPsNotInUse != len(s.Spec.IPsNotInUse) {
+		diff = append(diff, Difference{Field: "spec.ipsNotInUse", Want: strconv.Itoa(*e.IPsNotInUse), Got: strconv.Itoa(len(s.Spec.IPsNotInUse))})
+	}
+	sort.Slice(diff, func(i, j int) bool { return diff[i].Field < diff[j].Field })
+	return diff
+}
+
+// WriteText writes the Report as a human readable timeline, followed by the final state and the diff.
+func (r *Report) WriteText(w io.Writer) error {
+	for i := range r.Timeline {
+		e := &r.Timeline[i]
+		line := fmt.Sprintf("#%d +%s %s", e.Step, e.At, e.Action)
+		if e.Comment != "" {
+			line += " # " + e.Comment
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return errors.Wrap(err, "failed to write timeline")
+		}
+		if e.Error != "" {
+			fmt.Fprintf(w, "    error: %s\n", e.Error)
+		}
+		for _, c := range e.Changes {
+			fmt.Fprintf(w, "    %s\n", c)
+		}
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0) //nolint:gomnd // padding
+	fmt.Fprintln(tw, "\nfinal state:")
+	addrs := make([]string, 0, len(r.Final.IPs))
+	for addr := range r.Final.IPs {
+		addrs = append(addrs, addr)
+	}
+	sort.Slice(addrs, func(i, j int) bool {
+		a, errA := netip.ParseAddr(addrs[i])
+		b, errB := netip.ParseAddr(addrs[j])
+		if errA != nil || errB != nil {
+			return addrs[i] < addrs[j]
+		}
+		return a.Less(b)
+	})
+	for _, addr := range addrs {
+		ip := r.Final.IPs[addr]
+		if ip.Pod == "" {
+			fmt.Fprintf(tw, "    %s\t%s\n", addr, ip.State)
+			continue
+		}
+		fmt.Fprintf(tw, "    %s\t%s\t%s\n", addr, ip.State, ip.Pod)
+	}
+	states := make([]string, 0, len(r.Final.Counts))
+	for state := range r.Final.Counts {
+		states = append(states, string(state))
+	}
+	sort.Strings(states)
+	for _, state := range states {
+		fmt.Fprintf(tw, "    %s:\t%d\n", state, r.Final.Counts[types.IPState(state)])
+	}
+	fmt.Fprintf(tw, "    requestedIPCount:\t%d\n", r.Final.Spec.RequestedIPCount)
+	fmt.Fprintf(tw, "    ipsNotInUse:\t%d\n", len(r.Final.Spec.IPsNotInUse))
+
+	if len(r.Diff) > 0 {
+		fmt.Fprintln(tw, "\ndiff:")
+		for _, d := range r.Diff {
+			fmt.Fprintf(tw, "    %s\twant %s\tgot %s\n", d.Field, d.Want, d.Got)
+		}
+	}
+	return errors.Wrap(tw.Flush(), "failed to write report")
+}
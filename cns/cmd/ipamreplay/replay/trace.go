@@ -0,0 +1,147 @@
+// Package replay drives the CNS IPAM state machines, the HTTPRestService IP pool, the NodeNetworkConfig
+// reconciler and the IPAM pool monitor, through a recorded trace of their inputs, without a cluster.
+//
+// The pool hands out and releases whichever free IPs it finds first, so which addresses are picked varies
+// between runs unless the requests in the trace pin them with desiredIPAddresses. The number of IPs in each
+// state, and the requested pool size, are always deterministic.
+package replay
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/Azure/azure-container-networking/cns"
+	"github.com/Azure/azure-container-networking/cns/types"
+	"github.com/Azure/azure-container-networking/crd/clustersubnetstate/api/v1alpha1"
+	"github.com/Azure/azure-container-networking/crd/nodenetworkconfig/api/v1alpha"
+	"github.com/pkg/errors"
+)
+
+// Duration is a time.Duration which is written in traces in time.ParseDuration format, such as "1m30s".
+type Duration time.Duration
+
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	b, err := json.Marshal(d.String())
+	return b, errors.Wrap(err, "failed to marshal duration")
+}
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return errors.Wrap(err, "duration must be a string")
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return errors.Wrapf(err, "invalid duration %q", s)
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// Trace is a recorded sequence of inputs to CNS IPAM.
+type Trace struct {
+	// NodeIP, if set, is the node's IP which the reconciler matches against the NetworkContainers' NodeIP.
+	NodeIP string `json:"nodeIP,omitempty"`
+	// Steps are replayed in order.
+	Steps []Step `json:"steps"`
+	// Expect, if set, is compared with the state at the end of the replay.
+	Expect *Expectation `json:"expect,omitempty"`
+}
+
+// Step is a single input to CNS IPAM. Exactly one of the inputs must be set.
+type Step struct {
+	// At is the offset of the step into the trace. Steps must be in order of At.
+	At Duration `json:"at"`
+	// Comment is shown in the timeline alongside the step.
+	Comment string `json:"comment,omitempty"`
+
+	// NodeNetworkConfig is reconciled as if it had been updated in the apiserver. If its spec is empty, the
+	// spec last written by the pool monitor is kept.
+	NodeNetworkConfig *v1alpha.NodeNetworkConfig `json:"nnc,omitempty"`
+	// ClusterSubnetState is passed to the pool monitor, which then reconciles the pool.
+	ClusterSubnetState *v1alpha1.ClusterSubnetState `json:"css,omitempty"`
+	// NMAgent is the programmed version of each NC, by NC ID, which NMAgent reports to the host NC version sync.
+	NMAgent map[string]int `json:"nmagent,omitempty"`
+	// Request is an IP request from the CNI.
+	Request *cns.IPConfigsRequest `json:"request,omitempty"`
+	// Release is an IP release from the CNI.
+	Release *cns.IPConfigsRequest `json:"release,omitempty"`
+	// Tick reconciles the pool, as the pool monitor does every refresh.
+	Tick bool `json:"tick,omitempty"`
+}
+
+// Action names the input the Step holds.
+func (s *Step) Action() string {
+	var actions []string
+	if s.NodeNetworkConfig != nil {
+		actions = append(actions, "nnc")
+	}
+	if s.ClusterSubnetState != nil {
+		actions = append(actions, "css")
+	}
+	if s.NMAgent != nil {
+		actions = append(actions, "nmagent")
+	}
+	if s.Request != nil {
+		actions = append(actions, "request")
+	}
+	if s.Release != nil {
+		actions = append(actions, "release")
+	}
+	if s.Tick {
+		actions = append(actions, "tick")
+	}
+	if len(actions) != 1 {
+		return ""
+	}
+	return actions[0]
+}
+
+// Expectation is the state expected at the end of a replay. Only the fields which are set are compared.
+type Expectation struct {
+	// IPs are the expected states of IPs, by address. IPs which are not listed are not compared.
+	IPs map[string]IPStatus `json:"ips,omitempty"`
+	// Counts are the expected number of IPs in each state. States which are not listed are not compared.
+	Counts map[types.IPState]int `json:"counts,omitempty"`
+	// RequestedIPCount is the expected requested IP count in the NodeNetworkConfig spec.
+	RequestedIPCount *int64 `json:"requestedIPCount,omitempty"`
+	// IPsNotInUse is the expected number of IPs released in the NodeNetworkConfig spec.
+	IPsNotInUse *int `json:"ipsNotInUse,omitempty"`
+}
+
+// Load reads and validates a JSON trace.
+func Load(r io.Reader) (*Trace, error) {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	var trace Trace
+	if err := dec.Decode(&trace); err != nil {
+		return nil, errors.Wrap(err, "failed to decode trace")
+	}
+	if err := trace.Validate(); err != nil {
+		return nil, err
+	}
+	return &trace, nil
+}
+
+// Validate checks that every step has exactly one input and that the steps are in order.
+func (t *Trace) Validate() error {
+	for i := range t.Steps {
+		if t.Steps[i].Action() == "" {
+			return errors.Errorf("step %d must have exactly one of nnc, css, nmagent, request, release or tick", i)
+		}
+		if i > 0 && t.Steps[i].At < t.Steps[i-1].At {
+			return errors.Errorf("step %d at %s is before step %d at %s", i, t.Steps[i].At, i-1, t.Steps[i-1].At)
+		}
+		for ncID, version := range t.Steps[i].NMAgent {
+			if version < -1 {
+				return errors.Errorf("step %d: invalid version %d for nc %s", i, version, ncID)
+			}
+		}
+	}
+	return nil
+}
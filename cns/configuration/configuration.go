@@ -32,6 +32,12 @@ type CNSConfig struct {
 	EnableStateMigration        bool
 	EnableSubnetScarcity        bool
 	EnableSwiftV2               bool
+	EnableWALStore              bool
+	EgressSNATSettings          EgressSNATSettings
+	EndpointDriftSettings       EndpointDriftSettings
+	IPAMv2ScalingPolicy         string
+	IPLeaseDurationSecs         int
+	IPLeaseSweepIntervalSecs    int
 	InitializeFromCNI           bool
 	KeyVaultSettings            KeyVaultSettings
 	MSISettings                 MSISettings
@@ -40,6 +46,7 @@ type CNSConfig struct {
 	MellanoxMonitorIntervalSecs int
 	MetricsBindAddress          string
 	ProgramSNATIPTables         bool
+	StickyIPHoldTimeSecs        int
 	SyncHostNCTimeoutMs         int
 	SyncHostNCVersionIntervalMs int
 	TLSCertificatePath          string
@@ -108,6 +115,21 @@ type GRPCSettings struct {
 	Port      uint16
 }
 
+// EndpointDriftSettings configures the watcher comparing the endpoints in the CNS endpoint state with the node.
+type EndpointDriftSettings struct {
+	Enable bool
+	// Repair adds back the missing host routes of endpoints.
+	Repair bool
+	// ResyncIntervalSecs is how often all endpoints are checked, in addition to checking them on link and route changes.
+	ResyncIntervalSecs int
+}
+
+type EgressSNATSettings struct {
+	Enable bool
+	// Namespaces is the egress SNAT of the pods in each namespace, unless a pod is annotated with its own.
+	Namespaces map[string]cns.EgressSNAT
+}
+
 func getConfigFilePath(cmdPath string) (string, error) {
 	// If config path is set from cmd line, return that.
 	if strings.TrimSpace(cmdPath) != "" {
@@ -227,6 +249,14 @@ func SetCNSConfigDefaults(config *CNSConfig) {
 	if config.GRPCSettings.Port == 0 {
 		config.GRPCSettings.Port = 8080
 	}
+	if config.EndpointDriftSettings.Enable && config.EndpointDriftSettings.ResyncIntervalSecs == 0 {
+		config.EndpointDriftSettings.ResyncIntervalSecs = 300 //nolint:gomnd // default times
+	}
+	if config.IPLeaseDurationSecs > 0 && config.IPLeaseSweepIntervalSecs == 0 {
+		config.IPLeaseSweepIntervalSecs = 60 //nolint:gomnd // default times
+	}
 	config.GRPCSettings.Enable = false
-	config.WatchPods = config.EnableIPAMv2 || config.EnableSwiftV2
+	// the IP lease sweeper, sticky IPs and egress SNAT need the pod watcher to tell them about the pods on the node
+	config.WatchPods = config.EnableIPAMv2 || config.EnableSwiftV2 || config.IPLeaseDurationSecs > 0 || config.StickyIPHoldTimeSecs > 0 ||
+		config.EgressSNATSettings.Enable
 }
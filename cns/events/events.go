@@ -0,0 +1,88 @@
+// Package events holds the bounded in-memory log of CNS state changes which is served to
+// monitoring clients as a resumable event stream.
+package events
+
+import (
+	"time"
+
+	"github.com/Azure/azure-container-networking/cns/types"
+)
+
+// Type is the kind of state change an Event describes.
+type Type string
+
+const (
+	IPStateChanged          Type = "IPStateChanged"
+	IPRemoved               Type = "IPRemoved"
+	NetworkContainerCreated Type = "NetworkContainerCreated"
+	NetworkContainerUpdated Type = "NetworkContainerUpdated"
+	NetworkContainerDeleted Type = "NetworkContainerDeleted"
+	PoolScaled              Type = "PoolScaled"
+	EndpointDriftDetected   Type = "EndpointDriftDetected"
+	EndpointDriftRepaired   Type = "EndpointDriftRepaired"
+	EndpointStateChanged    Type = "EndpointStateChanged"
+)
+
+// Endpoint resources which can drift from the CNS endpoint state.
+const (
+	EndpointResourceHostInterface = "hostInterface"
+	EndpointResourceHostRoute     = "hostRoute"
+)
+
+// Event is a single CNS state change. Exactly one of IP, NetworkContainer, Pool or Endpoint is set, matching the Type.
+type Event struct {
+	Cursor           uint64                 `json:"cursor"`
+	Time             time.Time              `json:"time"`
+	Type             Type                   `json:"type"`
+	IP               *IPEvent               `json:"ip,omitempty"`
+	NetworkContainer *NetworkContainerEvent `json:"networkContainer,omitempty"`
+	Pool             *PoolEvent             `json:"pool,omitempty"`
+	Endpoint         *EndpointEvent         `json:"endpoint,omitempty"`
+}
+
+// IPEvent describes a change to a secondary IP in the CNS pool.
+type IPEvent struct {
+	ID            string        `json:"id"`
+	NCID          string        `json:"ncID"`
+	IPAddress     string        `json:"ipAddress"`
+	PreviousState types.IPState `json:"previousState,omitempty"`
+	State         types.IPState `json:"state,omitempty"`
+	PodName       string        `json:"podName,omitempty"`
+	PodNamespace  string        `json:"podNamespace,omitempty"`
+}
+
+// NetworkContainerEvent describes a network container being created, updated or deleted.
+type NetworkContainerEvent struct {
+	ID      string `json:"id"`
+	Type    string `json:"type,omitempty"`
+	Version string `json:"version,omitempty"`
+}
+
+// PoolEvent describes a scaling decision made by the IPAM pool monitor.
+type PoolEvent struct {
+	PreviousRequestedIPCount int64 `json:"previousRequestedIPCount"`
+	RequestedIPCount         int64 `json:"requestedIPCount"`
+	BatchSize                int64 `json:"batchSize"`
+	MaxIPCount               int64 `json:"maxIPCount"`
+	Demand                   int64 `json:"demand,omitempty"`
+	ReleasedIPCount          int   `json:"releasedIPCount,omitempty"`
+}
+
+// EndpointEvent describes an endpoint in the CNS endpoint state which was added, updated or removed, or a host
+// interface or host route of an endpoint which is missing from the node, or was added back. Only ContainerID and
+// the pod are set when the endpoint state changed.
+type EndpointEvent struct {
+	ContainerID  string `json:"containerID"`
+	PodName      string `json:"podName,omitempty"`
+	PodNamespace string `json:"podNamespace,omitempty"`
+	IfName       string `json:"ifName,omitempty"`
+	HostIfName   string `json:"hostIfName,omitempty"`
+	Resource     string `json:"resource,omitempty"`
+	Name         string `json:"name,omitempty"`
+	Reason       string `json:"reason,omitempty"`
+}
+
+// Publisher accepts CNS events.
+type Publisher interface {
+	Publish(Event)
+}
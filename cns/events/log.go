@@ -0,0 +1,126 @@
+package events
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultCapacity is the number of events retained by a Log for clients to resume from.
+const DefaultCapacity = 4096
+
+var _ Publisher = (*Log)(nil)
+
+// Page is a batch of events read from a Log.
+type Page struct {
+	// Cursor is the cursor to pass to the next read to resume after the returned events.
+	Cursor uint64 `json:"cursor"`
+	// Truncated is set when events after the requested cursor were dropped from the Log
+	// before they could be read, so the client must resync its view of CNS state.
+	Truncated bool    `json:"truncated"`
+	Events    []Event `json:"events"`
+}
+
+// Log is a bounded, in-memory log of events. Each event is assigned a monotonically increasing
+// cursor which clients use to resume reading after a disconnect. Cursors start from the time the
+// Log was created, so that a cursor handed out by a previous CNS process is always older than
+// anything retained by this one and is reported as truncated instead of being silently resumed.
+type Log struct {
+	mu     sync.Mutex
+	buf    []Event
+	start  int    // index of the oldest retained event in buf
+	size   int    // number of retained events
+	base   uint64 // cursor before the first event
+	head   uint64 // cursor of the newest event
+	notify chan struct{}
+}
+
+// NewLog returns a Log which retains the last capacity events.
+func NewLog(capacity int) *Log {
+	if capacity < 1 {
+		capacity = DefaultCapacity
+	}
+	base := uint64(time.Now().UnixNano())
+	return &Log{
+		buf:    make([]Event, capacity),
+		base:   base,
+		head:   base,
+		notify: make(chan struct{}),
+	}
+}
+
+// Publish appends the event to the Log, evicting the oldest event if the Log is full, and wakes any waiting readers.
+func (l *Log) Publish(e Event) { //nolint:gocritic // events are passed by value
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.head++
+	e.Cursor = l.head
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	if l.size < len(l.buf) {
+		l.buf[(l.start+l.size)%len(l.buf)] = e
+		l.size++
+	} else {
+		l.buf[l.start] = e
+		l.start = (l.start + 1) % len(l.buf)
+	}
+	close(l.notify)
+	l.notify = make(chan struct{})
+}
+
+// Head returns the cursor of the newest event in the Log. Reading from it returns only events published afterwards.
+func (l *Log) Head() uint64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.head
+}
+
+// Since returns the retained events published after cursor.
+func (l *Log) Since(cursor uint64) Page {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	page, _ := l.sinceLocked(cursor)
+	return page
+}
+
+func (l *Log) sinceLocked(cursor uint64) (Page, <-chan struct{}) {
+	page := Page{Cursor: l.head}
+	if cursor > l.head {
+		// the cursor is from the future, there is no way to know what was missed.
+		cursor = 0
+		page.Truncated = true
+	}
+	oldest := l.head - uint64(l.size) + 1
+	if cursor+1 < oldest {
+		// a zero cursor asks for every retained event, and has only missed some if any were evicted.
+		page.Truncated = page.Truncated || cursor != 0 || oldest > l.base+1
+		cursor = oldest - 1
+	}
+	n := int(l.head - cursor)
+	page.Events = make([]Event, 0, n)
+	for i := l.size - n; i < l.size; i++ {
+		page.Events = append(page.Events, l.buf[(l.start+i)%len(l.buf)])
+	}
+	return page, l.notify
+}
+
+// Wait returns the retained events published after cursor, blocking until at least one is available or the
+// context is done. If the context is done before any event is published, the empty Page is returned with the error.
+func (l *Log) Wait(ctx context.Context, cursor uint64) (Page, error) {
+	for {
+		l.mu.Lock()
+		page, notify := l.sinceLocked(cursor)
+		l.mu.Unlock()
+		if len(page.Events) > 0 || page.Truncated {
+			return page, nil
+		}
+		select {
+		case <-ctx.Done():
+			return page, errors.Wrap(ctx.Err(), "context done waiting for events")
+		case <-notify:
+		}
+	}
+}
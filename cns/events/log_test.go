@@ -0,0 +1,90 @@
+package events
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func publishN(l *Log, n int) {
+	for i := 0; i < n; i++ {
+		l.Publish(Event{Type: IPStateChanged, IP: &IPEvent{ID: string(rune('a' + i))}})
+	}
+}
+
+func TestLogSince(t *testing.T) {
+	l := NewLog(4)
+	start := l.Head()
+	publishN(l, 3)
+
+	page := l.Since(start)
+	assert.False(t, page.Truncated)
+	require.Len(t, page.Events, 3)
+	assert.Equal(t, start+1, page.Events[0].Cursor)
+	assert.Equal(t, "c", page.Events[2].IP.ID)
+	assert.Equal(t, page.Events[2].Cursor, page.Cursor)
+	assert.False(t, page.Events[0].Time.IsZero())
+
+	// resuming from the returned cursor yields only new events
+	publishN(l, 1)
+	next := l.Since(page.Cursor)
+	assert.False(t, next.Truncated)
+	require.Len(t, next.Events, 1)
+	assert.Equal(t, page.Cursor+1, next.Events[0].Cursor)
+
+	// a zero cursor replays everything retained
+	all := l.Since(0)
+	assert.False(t, all.Truncated)
+	assert.Len(t, all.Events, 4)
+}
+
+func TestLogTruncated(t *testing.T) {
+	l := NewLog(2)
+	start := l.Head()
+	publishN(l, 5)
+
+	page := l.Since(start)
+	assert.True(t, page.Truncated)
+	require.Len(t, page.Events, 2)
+	assert.Equal(t, "d", page.Events[0].IP.ID)
+	assert.Equal(t, "e", page.Events[1].IP.ID)
+
+	all := l.Since(0)
+	assert.True(t, all.Truncated)
+	assert.Len(t, all.Events, 2)
+
+	// a cursor from a previous process is older than anything in this Log
+	restarted := NewLog(2)
+	publishN(restarted, 1)
+	assert.True(t, restarted.Since(start).Truncated)
+
+	// a cursor from the future can't be resumed either
+	future := l.Since(l.Head() + 10)
+	assert.True(t, future.Truncated)
+	assert.Len(t, future.Events, 2)
+}
+
+func TestLogWait(t *testing.T) {
+	l := NewLog(4)
+	cursor := l.Head()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	page, err := l.Wait(ctx, cursor)
+	require.Error(t, err)
+	assert.Empty(t, page.Events)
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		publishN(l, 1)
+	}()
+	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	page, err = l.Wait(ctx, cursor)
+	require.NoError(t, err)
+	require.Len(t, page.Events, 1)
+	assert.Equal(t, cursor+1, page.Cursor)
+}
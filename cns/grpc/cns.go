@@ -2,10 +2,18 @@ package grpc
 
 import (
 	"context"
+	"sort"
 
+	"github.com/Azure/azure-container-networking/cns"
+	"github.com/Azure/azure-container-networking/cns/events"
+	"github.com/Azure/azure-container-networking/cns/filter"
 	pb "github.com/Azure/azure-container-networking/cns/grpc/v1alpha"
 	"github.com/Azure/azure-container-networking/cns/restserver"
+	"github.com/Azure/azure-container-networking/cns/types"
 	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 )
 
 // CNSService defines the CNS gRPC service.
@@ -26,3 +34,320 @@ func (s *CNS) GetNodeInfo(_ context.Context, req *pb.NodeInfoRequest) (*pb.NodeI
 	// todo: Implement the logic
 	return &pb.NodeInfoResponse{}, nil
 }
+
+func (s *CNS) state() (*restserver.HTTPRestService, error) {
+	if s.State == nil {
+		return nil, status.Error(codes.Unavailable, "CNS state is not initialized")
+	}
+	return s.State, nil
+}
+
+func (s *CNS) RequestIPConfigs(ctx context.Context, req *pb.IPConfigsRequest) (*pb.IPConfigsResponse, error) {
+	state, err := s.state()
+	if err != nil {
+		return nil, err
+	}
+	ipconfigsRequest := IPConfigsRequestFromProto(req)
+	resp, err := state.RequestIPConfigsHandlerHelper(ctx, ipconfigsRequest)
+	if err != nil {
+		s.Logger.Error("RequestIPConfigs failed", zap.String("podInterfaceID", req.GetPodInterfaceID()), zap.Error(err))
+		return nil, ipConfigsError(resp, err)
+	}
+	return IPConfigsResponseToProto(resp), nil
+}
+
+func (s *CNS) ReleaseIPConfigs(ctx context.Context, req *pb.IPConfigsRequest) (*pb.IPConfigsResponse, error) {
+	state, err := s.state()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := state.ReleaseIPConfigHandlerHelper(ctx, IPConfigsRequestFromProto(req))
+	if err != nil {
+		s.Logger.Error("ReleaseIPConfigs failed", zap.String("podInterfaceID", req.GetPodInterfaceID()), zap.Error(err))
+		return nil, ipConfigsError(resp, err)
+	}
+	return IPConfigsResponseToProto(resp), nil
+}
+
+// ipConfigsError maps a failed IPConfigs helper call to a gRPC status error. The code is picked
+// from the CNS return code of the response, and the message is the one CNS would have returned
+// over HTTP.
+func ipConfigsError(resp *cns.IPConfigsResponse, err error) error {
+	if resp == nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	msg := resp.Response.Message
+	if msg == "" {
+		msg = err.Error()
+	}
+	switch resp.Response.ReturnCode { //nolint:exhaustive // all other codes are internal errors
+	case types.InvalidRequest, types.InvalidParameter, types.EmptyOrchestratorContext,
+		types.UnsupportedOrchestratorContext, types.UnsupportedOrchestratorType:
+		return status.Error(codes.InvalidArgument, msg)
+	case types.NotFound, types.UnknownContainerID:
+		return status.Error(codes.NotFound, msg)
+	case types.FailedToAllocateIPConfig, types.FailedToAllocateBackendConfig, types.AddressUnavailable:
+		return status.Error(codes.ResourceExhausted, msg)
+	case types.NilEndpointStateStore:
+		return status.Error(codes.FailedPrecondition, msg)
+	case types.UnsupportedAPI:
+		return status.Error(codes.Unimplemented, msg)
+	default:
+		return status.Error(codes.Internal, msg)
+	}
+}
+
+func (s *CNS) RenewIPLeases(ctx context.Context, req *pb.IPConfigsRequest) (*pb.Response, error) {
+	state, err := s.state()
+	if err != nil {
+		return nil, err
+	}
+	return ResponseToProto(state.RenewIPLeasesHandlerHelper(ctx, IPConfigsRequestFromProto(req))), nil
+}
+
+func (s *CNS) GetIPAddresses(_ context.Context, req *pb.GetIPAddressesRequest) (*pb.GetIPAddressesResponse, error) {
+	state, err := s.state()
+	if err != nil {
+		return nil, err
+	}
+	return getIPAddresses(state, req), nil
+}
+
+// getIPAddresses returns the IPs matching the requested states ordered by IP ID, so that
+// consecutive snapshots of an unchanged pool are equal.
+func getIPAddresses(state *restserver.HTTPRestService, req *pb.GetIPAddressesRequest) *pb.GetIPAddressesResponse {
+	states := make([]types.IPState, len(req.GetIpConfigStateFilter()))
+	for i, s := range req.GetIpConfigStateFilter() {
+		states[i] = types.IPState(s)
+	}
+	ipConfigs := filter.MatchAnyIPConfigState(state.GetPodIPConfigState(), filter.PredicatesForStates(states...)...)
+	sort.Slice(ipConfigs, func(i, j int) bool { return ipConfigs[i].ID < ipConfigs[j].ID })
+
+	resp := &pb.GetIPAddressesResponse{Response: ResponseToProto(cns.Response{ReturnCode: types.Success})}
+	for i := range ipConfigs {
+		resp.IpConfigurationStatus = append(resp.IpConfigurationStatus, IPConfigurationStatusToProto(ipConfigs[i]))
+	}
+	return resp
+}
+
+func (s *CNS) GetNetworkContainer(_ context.Context, req *pb.GetNetworkContainerRequest) (*pb.NetworkContainer, error) {
+	state, err := s.state()
+	if err != nil {
+		return nil, err
+	}
+	resp := state.GetNetworkContainerHandlerHelper(cns.GetNetworkContainerRequest{
+		NetworkContainerid:  req.GetNetworkContainerID(),
+		OrchestratorContext: req.GetOrchestratorContext(),
+	})
+	return NetworkContainerToProto(&resp), nil
+}
+
+func (s *CNS) GetAllNetworkContainers(_ context.Context, req *pb.GetNetworkContainerRequest) (*pb.GetAllNetworkContainersResponse, error) {
+	state, err := s.state()
+	if err != nil {
+		return nil, err
+	}
+	resp := state.GetAllNetworkContainersHandlerHelper(cns.GetNetworkContainerRequest{
+		NetworkContainerid:  req.GetNetworkContainerID(),
+		OrchestratorContext: req.GetOrchestratorContext(),
+	})
+	out := &pb.GetAllNetworkContainersResponse{Response: ResponseToProto(resp.Response)}
+	for i := range resp.NetworkContainers {
+		out.NetworkContainers = append(out.NetworkContainers, NetworkContainerToProto(&resp.NetworkContainers[i]))
+	}
+	return out, nil
+}
+
+func (s *CNS) GetEndpoint(_ context.Context, req *pb.GetEndpointRequest) (*pb.GetEndpointResponse, error) {
+	state, err := s.state()
+	if err != nil {
+		return nil, err
+	}
+	resp := state.GetEndpointHandlerHelper(req.GetEndpointID())
+	return &pb.GetEndpointResponse{
+		Response: &pb.Response{
+			ReturnCode: int32(resp.Response.ReturnCode),
+			Message:    resp.Response.Message,
+		},
+		EndpointInfo: EndpointInfoToProto(&resp.EndpointInfo),
+	}, nil
+}
+
+func (s *CNS) UpdateEndpoint(_ context.Context, req *pb.UpdateEndpointRequest) (*pb.Response, error) {
+	state, err := s.state()
+	if err != nil {
+		return nil, err
+	}
+	ipInfo, err := IPInfoFromProto(req.GetIpInfo())
+	if err != nil {
+		return ResponseToProto(cns.Response{ReturnCode: types.InvalidRequest, Message: err.Error()}), nil
+	}
+	return ResponseToProto(state.UpdateEndpointHandlerHelper(req.GetEndpointID(), ipInfo)), nil
+}
+
+// WatchIPPoolState sends the IPs matching the requested states when the stream opens and then
+// every time they change, until the client cancels the stream. Changes are picked up from the
+// IP events in the CNS event log.
+func (s *CNS) WatchIPPoolState(req *pb.GetIPAddressesRequest, stream pb.CNS_WatchIPPoolStateServer) error {
+	state, err := s.state()
+	if err != nil {
+		return err
+	}
+	log, err := eventLog(state)
+	if err != nil {
+		return err
+	}
+
+	// take the cursor before the first snapshot so that no change made after it is missed
+	cursor := log.Head()
+	var last *pb.GetIPAddressesResponse
+	for {
+		snapshot := getIPAddresses(state, req)
+		if last == nil || !proto.Equal(last, snapshot) {
+			if err := stream.Send(snapshot); err != nil {
+				return err //nolint:wrapcheck // status errors must be returned as is
+			}
+			last = snapshot
+		}
+		for {
+			page, err := log.Wait(stream.Context(), cursor)
+			if err != nil {
+				return nil
+			}
+			cursor = page.Cursor
+			if page.Truncated || hasEvent(page.Events, events.IPStateChanged, events.IPRemoved) {
+				break
+			}
+		}
+	}
+}
+
+// WatchEndpoints sends every endpoint as added when the stream opens and then an event for every
+// endpoint which is added, updated or deleted, until the client cancels the stream. Changes are
+// picked up from the endpoint state events in the CNS event log, and only the endpoints named by
+// them are compared, unless events were dropped before they could be read.
+func (s *CNS) WatchEndpoints(_ *pb.WatchEndpointsRequest, stream pb.CNS_WatchEndpointsServer) error {
+	state, err := s.state()
+	if err != nil {
+		return err
+	}
+	log, err := eventLog(state)
+	if err != nil {
+		return err
+	}
+
+	cursor := log.Head()
+	last := map[string]*pb.EndpointInfo{}
+	var changed map[string]struct{} // nil compares every endpoint
+	for {
+		current := endpointState(state, changed)
+		previous := last
+		if changed != nil {
+			previous = make(map[string]*pb.EndpointInfo, len(changed))
+			for id := range changed {
+				if info, ok := last[id]; ok {
+					previous[id] = info
+				}
+			}
+		}
+		for _, event := range diffEndpoints(previous, current) {
+			if err := stream.Send(event); err != nil {
+				return err //nolint:wrapcheck // status errors must be returned as is
+			}
+			if event.GetType() == pb.EndpointEventType_ENDPOINT_EVENT_TYPE_DELETED {
+				delete(last, event.GetEndpointID())
+			} else {
+				last[event.GetEndpointID()] = event.GetEndpointInfo()
+			}
+		}
+
+		for {
+			page, err := log.Wait(stream.Context(), cursor)
+			if err != nil {
+				return nil
+			}
+			cursor = page.Cursor
+			if page.Truncated {
+				changed = nil
+				break
+			}
+			if changed = changedEndpoints(page.Events); len(changed) > 0 {
+				break
+			}
+		}
+	}
+}
+
+func eventLog(state *restserver.HTTPRestService) (*events.Log, error) {
+	log := state.EventLog()
+	if log == nil {
+		return nil, status.Error(codes.Unavailable, "CNS event log is not initialized")
+	}
+	return log, nil
+}
+
+func hasEvent(page []events.Event, types ...events.Type) bool {
+	for i := range page {
+		for _, t := range types {
+			if page[i].Type == t {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// changedEndpoints returns the IDs of the endpoints whose state changed in the events.
+func changedEndpoints(page []events.Event) map[string]struct{} {
+	changed := map[string]struct{}{}
+	for i := range page {
+		if page[i].Type == events.EndpointStateChanged && page[i].Endpoint != nil {
+			changed[page[i].Endpoint.ContainerID] = struct{}{}
+		}
+	}
+	return changed
+}
+
+// endpointState returns the endpoints with the given IDs which are in the CNS endpoint state, or every
+// endpoint if ids is nil.
+func endpointState(state *restserver.HTTPRestService, ids map[string]struct{}) map[string]*pb.EndpointInfo {
+	var endpoints map[string]*pb.EndpointInfo
+	state.ReadEndpointState(func(all map[string]*restserver.EndpointInfo) {
+		if ids == nil {
+			endpoints = make(map[string]*pb.EndpointInfo, len(all))
+			for id, info := range all {
+				endpoints[id] = EndpointInfoToProto(info)
+			}
+			return
+		}
+		endpoints = make(map[string]*pb.EndpointInfo, len(ids))
+		for id := range ids {
+			if info, ok := all[id]; ok {
+				endpoints[id] = EndpointInfoToProto(info)
+			}
+		}
+	})
+	return endpoints
+}
+
+// diffEndpoints returns the events which turn the previous endpoint state into the current one,
+// ordered by endpoint ID.
+func diffEndpoints(previous, current map[string]*pb.EndpointInfo) []*pb.EndpointEvent {
+	var events []*pb.EndpointEvent
+	for id, info := range current {
+		old, ok := previous[id]
+		switch {
+		case !ok:
+			events = append(events, &pb.EndpointEvent{Type: pb.EndpointEventType_ENDPOINT_EVENT_TYPE_ADDED, EndpointID: id, EndpointInfo: info})
+		case !proto.Equal(old, info):
+			events = append(events, &pb.EndpointEvent{Type: pb.EndpointEventType_ENDPOINT_EVENT_TYPE_UPDATED, EndpointID: id, EndpointInfo: info})
+		}
+	}
+	for id, info := range previous {
+		if _, ok := current[id]; !ok {
+			events = append(events, &pb.EndpointEvent{Type: pb.EndpointEventType_ENDPOINT_EVENT_TYPE_DELETED, EndpointID: id, EndpointInfo: info})
+		}
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].GetEndpointID() < events[j].GetEndpointID() })
+	return events
+}
@@ -0,0 +1,165 @@
+package grpc
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-container-networking/cns"
+	pb "github.com/Azure/azure-container-networking/cns/grpc/v1alpha"
+	"github.com/Azure/azure-container-networking/cns/restserver"
+	"github.com/Azure/azure-container-networking/cns/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestDiffEndpoints(t *testing.T) {
+	previous := map[string]*pb.EndpointInfo{
+		"kept":    {PodName: "a", PodNamespace: "default"},
+		"changed": {PodName: "b", PodNamespace: "default"},
+		"removed": {PodName: "c", PodNamespace: "default"},
+	}
+	current := map[string]*pb.EndpointInfo{
+		"kept":    {PodName: "a", PodNamespace: "default"},
+		"changed": {PodName: "b", PodNamespace: "kube-system"},
+		"added":   {PodName: "d", PodNamespace: "default"},
+	}
+
+	events := diffEndpoints(previous, current)
+	require.Len(t, events, 3)
+	assert.Equal(t, "added", events[0].GetEndpointID())
+	assert.Equal(t, pb.EndpointEventType_ENDPOINT_EVENT_TYPE_ADDED, events[0].GetType())
+	assert.Equal(t, "changed", events[1].GetEndpointID())
+	assert.Equal(t, pb.EndpointEventType_ENDPOINT_EVENT_TYPE_UPDATED, events[1].GetType())
+	assert.Equal(t, "kube-system", events[1].GetEndpointInfo().GetPodNamespace())
+	assert.Equal(t, "removed", events[2].GetEndpointID())
+	assert.Equal(t, pb.EndpointEventType_ENDPOINT_EVENT_TYPE_DELETED, events[2].GetType())
+
+	assert.Empty(t, diffEndpoints(current, current))
+}
+
+func TestIPConfigurationStatusRoundTrip(t *testing.T) {
+	status := cns.IPConfigurationStatus{
+		ID:          "id",
+		NCID:        "nc",
+		IPAddress:   "10.0.0.4",
+		PodInfo:     cns.NewPodInfo("infra", "iface", "pod", "default"),
+		LeaseExpiry: time.Unix(0, 42),
+	}
+	status.SetState(types.Assigned)
+
+	got := IPConfigurationStatusFromProto(IPConfigurationStatusToProto(status))
+	assert.Equal(t, status.ID, got.ID)
+	assert.Equal(t, status.NCID, got.NCID)
+	assert.Equal(t, status.IPAddress, got.IPAddress)
+	assert.Equal(t, types.Assigned, got.GetState())
+	assert.True(t, status.LastStateTransition.Equal(got.LastStateTransition))
+	assert.True(t, status.LeaseExpiry.Equal(got.LeaseExpiry))
+	assert.Equal(t, "pod", got.PodInfo.Name())
+	assert.Equal(t, "default", got.PodInfo.Namespace())
+	assert.Equal(t, "infra", got.PodInfo.InfraContainerID())
+	assert.Equal(t, "iface", got.PodInfo.InterfaceID())
+
+	unassigned := IPConfigurationStatusFromProto(IPConfigurationStatusToProto(cns.IPConfigurationStatus{ID: "free"}))
+	assert.Nil(t, unassigned.PodInfo)
+	assert.True(t, unassigned.LeaseExpiry.IsZero())
+}
+
+func TestEndpointInfoRoundTrip(t *testing.T) {
+	_, ipv4, err := net.ParseCIDR("10.0.0.4/24")
+	require.NoError(t, err)
+	info := &restserver.EndpointInfo{
+		PodName:      "pod",
+		PodNamespace: "default",
+		IfnameToIPMap: map[string]*restserver.IPInfo{
+			"eth0": {
+				IPv4:                     []net.IPNet{{IP: net.ParseIP("10.0.0.4"), Mask: ipv4.Mask}},
+				HostVethName:             "azv1234",
+				NICType:                  cns.InfraNIC,
+				PortMappings:             []restserver.PortMapping{{HostPort: 8080, ContainerPort: 80, Protocol: "tcp", HostIP: "10.1.0.4"}},
+				PortForwardAddress:       "169.254.128.5/17",
+				PortForwardMasqueradeAll: true,
+				Bandwidth:                &restserver.Bandwidth{IngressRate: 1000000, EgressRate: 2000000, EgressBurst: 80000},
+				NetworkMode:              "transparent",
+				EgressSNAT:               &cns.EgressSNAT{Mode: cns.EgressSNATNodeIP, Except: []string{"10.240.0.0/16"}},
+				NetworkID:                "azure",
+				Created:                  time.Unix(1700000000, 0),
+			},
+		},
+	}
+
+	got, err := EndpointInfoFromProto(EndpointInfoToProto(info))
+	require.NoError(t, err)
+	assert.Equal(t, info.PodName, got.PodName)
+	assert.Equal(t, info.PodNamespace, got.PodNamespace)
+	require.Contains(t, got.IfnameToIPMap, "eth0")
+	eth0 := got.IfnameToIPMap["eth0"]
+	require.Len(t, eth0.IPv4, 1)
+	assert.Equal(t, "10.0.0.4/24", eth0.IPv4[0].String())
+	assert.Nil(t, eth0.IPv6)
+	assert.Equal(t, "azv1234", eth0.HostVethName)
+	assert.Equal(t, cns.InfraNIC, eth0.NICType)
+	assert.Equal(t, info.IfnameToIPMap["eth0"].PortMappings, eth0.PortMappings)
+	assert.Equal(t, "169.254.128.5/17", eth0.PortForwardAddress)
+	assert.True(t, eth0.PortForwardMasqueradeAll)
+	assert.Equal(t, info.IfnameToIPMap["eth0"].Bandwidth, eth0.Bandwidth)
+	assert.Equal(t, "transparent", eth0.NetworkMode)
+	assert.Equal(t, info.IfnameToIPMap["eth0"].EgressSNAT, eth0.EgressSNAT)
+	assert.Equal(t, "azure", eth0.NetworkID)
+	assert.True(t, info.IfnameToIPMap["eth0"].Created.Equal(eth0.Created))
+
+	_, err = IPInfoFromProto(map[string]*pb.IPInfo{"eth0": {Ipv4: []string{"not-a-cidr"}}})
+	assert.Error(t, err)
+}
+
+func TestIPConfigsError(t *testing.T) {
+	errFailed := errors.New("failed")
+	tests := []struct {
+		name    string
+		resp    *cns.IPConfigsResponse
+		code    codes.Code
+		message string
+	}{
+		{
+			name:    "invalid orchestrator context",
+			resp:    &cns.IPConfigsResponse{Response: cns.Response{ReturnCode: types.UnsupportedOrchestratorContext, Message: "bad context"}},
+			code:    codes.InvalidArgument,
+			message: "bad context",
+		},
+		{
+			name:    "no free IPs",
+			resp:    &cns.IPConfigsResponse{Response: cns.Response{ReturnCode: types.FailedToAllocateIPConfig, Message: "no IPs"}},
+			code:    codes.ResourceExhausted,
+			message: "no IPs",
+		},
+		{
+			name:    "unknown container",
+			resp:    &cns.IPConfigsResponse{Response: cns.Response{ReturnCode: types.UnknownContainerID}},
+			code:    codes.NotFound,
+			message: "failed",
+		},
+		{
+			name:    "unexpected error",
+			resp:    &cns.IPConfigsResponse{Response: cns.Response{ReturnCode: types.UnexpectedError, Message: "boom"}},
+			code:    codes.Internal,
+			message: "boom",
+		},
+		{
+			name:    "no response",
+			code:    codes.Internal,
+			message: "failed",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			st, ok := status.FromError(ipConfigsError(tt.resp, errFailed))
+			require.True(t, ok)
+			assert.Equal(t, tt.code, st.Code())
+			assert.Equal(t, tt.message, st.Message())
+		})
+	}
+}
@@ -0,0 +1,437 @@
+package grpc
+
+import (
+	"net"
+	"time"
+
+	"github.com/Azure/azure-container-networking/cns"
+	pb "github.com/Azure/azure-container-networking/cns/grpc/v1alpha"
+	"github.com/Azure/azure-container-networking/cns/restserver"
+	"github.com/Azure/azure-container-networking/cns/types"
+	"github.com/pkg/errors"
+)
+
+// The conversions below translate between the CNS API types used by the JSON-over-HTTP API and
+// their protobuf counterparts, so that both transports share the same restserver logic.
+
+// ResponseToProto converts a cns.Response to its protobuf form.
+func ResponseToProto(r cns.Response) *pb.Response {
+	return &pb.Response{ReturnCode: int32(r.ReturnCode), Message: r.Message}
+}
+
+// ResponseFromProto converts a protobuf Response to a cns.Response.
+func ResponseFromProto(r *pb.Response) cns.Response {
+	return cns.Response{ReturnCode: types.ResponseCode(r.GetReturnCode()), Message: r.GetMessage()}
+}
+
+// IPConfigsRequestToProto converts a cns.IPConfigsRequest to its protobuf form.
+func IPConfigsRequestToProto(req cns.IPConfigsRequest) *pb.IPConfigsRequest { //nolint:gocritic // ignore hugeParam
+	return &pb.IPConfigsRequest{
+		DesiredIPAddresses:           req.DesiredIPAddresses,
+		PodInterfaceID:               req.PodInterfaceID,
+		InfraContainerID:             req.InfraContainerID,
+		OrchestratorContext:          req.OrchestratorContext,
+		Ifname:                       req.Ifname,
+		SecondaryInterfacesExist:     req.SecondaryInterfacesExist,
+		BackendInterfaceExist:        req.BackendInterfaceExist,
+		BackendInterfaceMacAddresses: req.BackendInterfaceMacAddresses,
+	}
+}
+
+// IPConfigsRequestFromProto converts a protobuf IPConfigsRequest to a cns.IPConfigsRequest.
+func IPConfigsRequestFromProto(req *pb.IPConfigsRequest) cns.IPConfigsRequest {
+	return cns.IPConfigsRequest{
+		DesiredIPAddresses:           req.GetDesiredIPAddresses(),
+		PodInterfaceID:               req.GetPodInterfaceID(),
+		InfraContainerID:             req.GetInfraContainerID(),
+		OrchestratorContext:          req.GetOrchestratorContext(),
+		Ifname:                       req.GetIfname(),
+		SecondaryInterfacesExist:     req.GetSecondaryInterfacesExist(),
+		BackendInterfaceExist:        req.GetBackendInterfaceExist(),
+		BackendInterfaceMacAddresses: req.GetBackendInterfaceMacAddresses(),
+	}
+}
+
+func ipSubnetToProto(s cns.IPSubnet) *pb.IPSubnet {
+	return &pb.IPSubnet{IpAddress: s.IPAddress, PrefixLength: uint32(s.PrefixLength)}
+}
+
+func ipSubnetFromProto(s *pb.IPSubnet) cns.IPSubnet {
+	return cns.IPSubnet{IPAddress: s.GetIpAddress(), PrefixLength: uint8(s.GetPrefixLength())}
+}
+
+func ipConfigurationToProto(c cns.IPConfiguration) *pb.IPConfiguration {
+	return &pb.IPConfiguration{
+		IpSubnet:         ipSubnetToProto(c.IPSubnet),
+		DnsServers:       c.DNSServers,
+		GatewayIPAddress: c.GatewayIPAddress,
+	}
+}
+
+func ipConfigurationFromProto(c *pb.IPConfiguration) cns.IPConfiguration {
+	return cns.IPConfiguration{
+		IPSubnet:         ipSubnetFromProto(c.GetIpSubnet()),
+		DNSServers:       c.GetDnsServers(),
+		GatewayIPAddress: c.GetGatewayIPAddress(),
+	}
+}
+
+func routesToProto(routes []cns.Route) []*pb.Route {
+	if routes == nil {
+		return nil
+	}
+	out := make([]*pb.Route, len(routes))
+	for i := range routes {
+		out[i] = &pb.Route{
+			IpAddress:        routes[i].IPAddress,
+			GatewayIPAddress: routes[i].GatewayIPAddress,
+			InterfaceToUse:   routes[i].InterfaceToUse,
+		}
+	}
+	return out
+}
+
+func routesFromProto(routes []*pb.Route) []cns.Route {
+	if routes == nil {
+		return nil
+	}
+	out := make([]cns.Route, len(routes))
+	for i, r := range routes {
+		out[i] = cns.Route{
+			IPAddress:        r.GetIpAddress(),
+			GatewayIPAddress: r.GetGatewayIPAddress(),
+			InterfaceToUse:   r.GetInterfaceToUse(),
+		}
+	}
+	return out
+}
+
+// IPConfigsResponseToProto converts a cns.IPConfigsResponse to its protobuf form.
+func IPConfigsResponseToProto(resp *cns.IPConfigsResponse) *pb.IPConfigsResponse {
+	out := &pb.IPConfigsResponse{Response: ResponseToProto(resp.Response)}
+	for i := range resp.PodIPInfo {
+		info := &resp.PodIPInfo[i]
+		out.PodIPInfo = append(out.PodIPInfo, &pb.PodIpInfo{
+			PodIPConfig:                     ipSubnetToProto(info.PodIPConfig),
+			NetworkContainerPrimaryIPConfig: ipConfigurationToProto(info.NetworkContainerPrimaryIPConfig),
+			HostPrimaryIPInfo: &pb.HostIPInfo{
+				Gateway:   info.HostPrimaryIPInfo.Gateway,
+				PrimaryIP: info.HostPrimaryIPInfo.PrimaryIP,
+				Subnet:    info.HostPrimaryIPInfo.Subnet,
+			},
+			NicType:           string(info.NICType),
+			InterfaceName:     info.InterfaceName,
+			MacAddress:        info.MacAddress,
+			SkipDefaultRoutes: info.SkipDefaultRoutes,
+			Routes:            routesToProto(info.Routes),
+			PnpID:             info.PnPID,
+		})
+	}
+	out.EgressSNAT = egressSNATToProto(resp.EgressSNAT)
+	return out
+}
+
+// IPConfigsResponseFromProto converts a protobuf IPConfigsResponse to a cns.IPConfigsResponse.
+func IPConfigsResponseFromProto(resp *pb.IPConfigsResponse) *cns.IPConfigsResponse {
+	out := &cns.IPConfigsResponse{Response: ResponseFromProto(resp.GetResponse())}
+	for _, info := range resp.GetPodIPInfo() {
+		out.PodIPInfo = append(out.PodIPInfo, cns.PodIpInfo{
+			PodIPConfig:                     ipSubnetFromProto(info.GetPodIPConfig()),
+			NetworkContainerPrimaryIPConfig: ipConfigurationFromProto(info.GetNetworkContainerPrimaryIPConfig()),
+			HostPrimaryIPInfo: cns.HostIPInfo{
+				Gateway:   info.GetHostPrimaryIPInfo().GetGateway(),
+				PrimaryIP: info.GetHostPrimaryIPInfo().GetPrimaryIP(),
+				Subnet:    info.GetHostPrimaryIPInfo().GetSubnet(),
+			},
+			NICType:           cns.NICType(info.GetNicType()),
+			InterfaceName:     info.GetInterfaceName(),
+			MacAddress:        info.GetMacAddress(),
+			SkipDefaultRoutes: info.GetSkipDefaultRoutes(),
+			Routes:            routesFromProto(info.GetRoutes()),
+			PnPID:             info.GetPnpID(),
+		})
+	}
+	out.EgressSNAT = egressSNATFromProto(resp.GetEgressSNAT())
+	return out
+}
+
+func unixNano(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano()
+}
+
+func fromUnixNano(n int64) time.Time {
+	if n == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, n)
+}
+
+// IPConfigurationStatusToProto converts a cns.IPConfigurationStatus to its protobuf form.
+func IPConfigurationStatusToProto(status cns.IPConfigurationStatus) *pb.IPConfigurationStatus { //nolint:gocritic // ignore hugeParam
+	out := &pb.IPConfigurationStatus{
+		Id:                  status.ID,
+		NcID:                status.NCID,
+		IpAddress:           status.IPAddress,
+		State:               string(status.GetState()),
+		LastStateTransition: unixNano(status.LastStateTransition),
+		LeaseExpiry:         unixNano(status.LeaseExpiry),
+	}
+	if status.PodInfo != nil {
+		out.PodInterfaceID = status.PodInfo.InterfaceID()
+		out.InfraContainerID = status.PodInfo.InfraContainerID()
+		out.PodName = status.PodInfo.Name()
+		out.PodNamespace = status.PodInfo.Namespace()
+	}
+	return out
+}
+
+// IPConfigurationStatusFromProto converts a protobuf IPConfigurationStatus to a cns.IPConfigurationStatus.
+func IPConfigurationStatusFromProto(status *pb.IPConfigurationStatus) cns.IPConfigurationStatus {
+	out := cns.IPConfigurationStatus{
+		ID:          status.GetId(),
+		NCID:        status.GetNcID(),
+		IPAddress:   status.GetIpAddress(),
+		LeaseExpiry: fromUnixNano(status.GetLeaseExpiry()),
+	}
+	out.SetState(types.IPState(status.GetState()))
+	out.LastStateTransition = fromUnixNano(status.GetLastStateTransition())
+	if status.GetPodName() != "" || status.GetInfraContainerID() != "" {
+		out.PodInfo = cns.NewPodInfo(status.GetInfraContainerID(), status.GetPodInterfaceID(), status.GetPodName(), status.GetPodNamespace())
+	}
+	return out
+}
+
+// NetworkContainerToProto converts a cns.GetNetworkContainerResponse to its protobuf form.
+func NetworkContainerToProto(nc *cns.GetNetworkContainerResponse) *pb.NetworkContainer {
+	out := &pb.NetworkContainer{
+		NetworkContainerID: nc.NetworkContainerID,
+		IpConfiguration:    ipConfigurationToProto(nc.IPConfiguration),
+		Routes:             routesToProto(nc.Routes),
+		MultiTenancyInfo: &pb.MultiTenancyInfo{
+			EncapType: nc.MultiTenancyInfo.EncapType,
+			Id:        int32(nc.MultiTenancyInfo.ID),
+		},
+		PrimaryInterfaceIdentifier: nc.PrimaryInterfaceIdentifier,
+		LocalIPConfiguration:       ipConfigurationToProto(nc.LocalIPConfiguration),
+		Response:                   ResponseToProto(nc.Response),
+		AllowHostToNCCommunication: nc.AllowHostToNCCommunication,
+		AllowNCToHostCommunication: nc.AllowNCToHostCommunication,
+		NetworkInterfaceInfo: &pb.NetworkInterfaceInfo{
+			NicType:    string(nc.NetworkInterfaceInfo.NICType),
+			MacAddress: nc.NetworkInterfaceInfo.MACAddress,
+		},
+	}
+	for _, s := range nc.CnetAddressSpace {
+		out.CnetAddressSpace = append(out.CnetAddressSpace, ipSubnetToProto(s))
+	}
+	return out
+}
+
+// NetworkContainerFromProto converts a protobuf NetworkContainer to a cns.GetNetworkContainerResponse.
+func NetworkContainerFromProto(nc *pb.NetworkContainer) cns.GetNetworkContainerResponse {
+	out := cns.GetNetworkContainerResponse{
+		NetworkContainerID: nc.GetNetworkContainerID(),
+		IPConfiguration:    ipConfigurationFromProto(nc.GetIpConfiguration()),
+		Routes:             routesFromProto(nc.GetRoutes()),
+		MultiTenancyInfo: cns.MultiTenancyInfo{
+			EncapType: nc.GetMultiTenancyInfo().GetEncapType(),
+			ID:        int(nc.GetMultiTenancyInfo().GetId()),
+		},
+		PrimaryInterfaceIdentifier: nc.GetPrimaryInterfaceIdentifier(),
+		LocalIPConfiguration:       ipConfigurationFromProto(nc.GetLocalIPConfiguration()),
+		Response:                   ResponseFromProto(nc.GetResponse()),
+		AllowHostToNCCommunication: nc.GetAllowHostToNCCommunication(),
+		AllowNCToHostCommunication: nc.GetAllowNCToHostCommunication(),
+		NetworkInterfaceInfo: cns.NetworkInterfaceInfo{
+			NICType:    cns.NICType(nc.GetNetworkInterfaceInfo().GetNicType()),
+			MACAddress: nc.GetNetworkInterfaceInfo().GetMacAddress(),
+		},
+	}
+	for _, s := range nc.GetCnetAddressSpace() {
+		out.CnetAddressSpace = append(out.CnetAddressSpace, ipSubnetFromProto(s))
+	}
+	return out
+}
+
+func ipNetsToProto(ipNets []net.IPNet) []string {
+	if ipNets == nil {
+		return nil
+	}
+	out := make([]string, len(ipNets))
+	for i := range ipNets {
+		out[i] = ipNets[i].String()
+	}
+	return out
+}
+
+func ipNetsFromProto(cidrs []string) ([]net.IPNet, error) {
+	if cidrs == nil {
+		return nil, nil
+	}
+	out := make([]net.IPNet, len(cidrs))
+	for i, cidr := range cidrs {
+		ip, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse IP %s", cidr)
+		}
+		out[i] = net.IPNet{IP: ip, Mask: ipNet.Mask}
+	}
+	return out, nil
+}
+
+func portMappingsToProto(mappings []restserver.PortMapping) []*pb.PortMapping {
+	if mappings == nil {
+		return nil
+	}
+	out := make([]*pb.PortMapping, len(mappings))
+	for i, m := range mappings {
+		out[i] = &pb.PortMapping{
+			HostPort:      int32(m.HostPort),
+			ContainerPort: int32(m.ContainerPort),
+			Protocol:      m.Protocol,
+			HostIP:        m.HostIP,
+		}
+	}
+	return out
+}
+
+func portMappingsFromProto(mappings []*pb.PortMapping) []restserver.PortMapping {
+	if mappings == nil {
+		return nil
+	}
+	out := make([]restserver.PortMapping, len(mappings))
+	for i, m := range mappings {
+		out[i] = restserver.PortMapping{
+			HostPort:      int(m.GetHostPort()),
+			ContainerPort: int(m.GetContainerPort()),
+			Protocol:      m.GetProtocol(),
+			HostIP:        m.GetHostIP(),
+		}
+	}
+	return out
+}
+
+func bandwidthToProto(bw *restserver.Bandwidth) *pb.Bandwidth {
+	if bw == nil {
+		return nil
+	}
+	return &pb.Bandwidth{IngressRate: bw.IngressRate, IngressBurst: bw.IngressBurst, EgressRate: bw.EgressRate, EgressBurst: bw.EgressBurst}
+}
+
+func bandwidthFromProto(bw *pb.Bandwidth) *restserver.Bandwidth {
+	if bw == nil {
+		return nil
+	}
+	return &restserver.Bandwidth{
+		IngressRate:  bw.GetIngressRate(),
+		IngressBurst: bw.GetIngressBurst(),
+		EgressRate:   bw.GetEgressRate(),
+		EgressBurst:  bw.GetEgressBurst(),
+	}
+}
+
+func egressSNATToProto(e *cns.EgressSNAT) *pb.EgressSNAT {
+	if e == nil {
+		return nil
+	}
+	return &pb.EgressSNAT{Mode: e.Mode, IpAddress: e.IPAddress, Except: e.Except}
+}
+
+func egressSNATFromProto(e *pb.EgressSNAT) *cns.EgressSNAT {
+	if e == nil {
+		return nil
+	}
+	return &cns.EgressSNAT{Mode: e.GetMode(), IPAddress: e.GetIpAddress(), Except: e.GetExcept()}
+}
+
+// IPInfoToProto converts the interface state of an endpoint to its protobuf form.
+func IPInfoToProto(ipInfo map[string]*restserver.IPInfo) map[string]*pb.IPInfo {
+	if ipInfo == nil {
+		return nil
+	}
+	out := make(map[string]*pb.IPInfo, len(ipInfo))
+	for ifName, info := range ipInfo {
+		if info == nil {
+			continue
+		}
+		out[ifName] = &pb.IPInfo{
+			Ipv4:                     ipNetsToProto(info.IPv4),
+			Ipv6:                     ipNetsToProto(info.IPv6),
+			HnsEndpointID:            info.HnsEndpointID,
+			HnsNetworkID:             info.HnsNetworkID,
+			HostVethName:             info.HostVethName,
+			MacAddress:               info.MacAddress,
+			NicType:                  string(info.NICType),
+			PortMappings:             portMappingsToProto(info.PortMappings),
+			PortForwardAddress:       info.PortForwardAddress,
+			PortForwardMasqueradeAll: info.PortForwardMasqueradeAll,
+			Bandwidth:                bandwidthToProto(info.Bandwidth),
+			NetworkMode:              info.NetworkMode,
+			EgressSNAT:               egressSNATToProto(info.EgressSNAT),
+			NetworkID:                info.NetworkID,
+			Created:                  unixNano(info.Created),
+		}
+	}
+	return out
+}
+
+// IPInfoFromProto converts the protobuf interface state of an endpoint to restserver.IPInfo.
+func IPInfoFromProto(ipInfo map[string]*pb.IPInfo) (map[string]*restserver.IPInfo, error) {
+	if ipInfo == nil {
+		return nil, nil
+	}
+	out := make(map[string]*restserver.IPInfo, len(ipInfo))
+	for ifName, info := range ipInfo {
+		ipv4, err := ipNetsFromProto(info.GetIpv4())
+		if err != nil {
+			return nil, err
+		}
+		ipv6, err := ipNetsFromProto(info.GetIpv6())
+		if err != nil {
+			return nil, err
+		}
+		out[ifName] = &restserver.IPInfo{
+			IPv4:                     ipv4,
+			IPv6:                     ipv6,
+			HnsEndpointID:            info.GetHnsEndpointID(),
+			HnsNetworkID:             info.GetHnsNetworkID(),
+			HostVethName:             info.GetHostVethName(),
+			MacAddress:               info.GetMacAddress(),
+			NICType:                  cns.NICType(info.GetNicType()),
+			PortMappings:             portMappingsFromProto(info.GetPortMappings()),
+			PortForwardAddress:       info.GetPortForwardAddress(),
+			PortForwardMasqueradeAll: info.GetPortForwardMasqueradeAll(),
+			Bandwidth:                bandwidthFromProto(info.GetBandwidth()),
+			NetworkMode:              info.GetNetworkMode(),
+			EgressSNAT:               egressSNATFromProto(info.GetEgressSNAT()),
+			NetworkID:                info.GetNetworkID(),
+			Created:                  fromUnixNano(info.GetCreated()),
+		}
+	}
+	return out, nil
+}
+
+// EndpointInfoToProto converts a restserver.EndpointInfo to its protobuf form.
+func EndpointInfoToProto(info *restserver.EndpointInfo) *pb.EndpointInfo {
+	return &pb.EndpointInfo{
+		PodName:       info.PodName,
+		PodNamespace:  info.PodNamespace,
+		IfnameToIPMap: IPInfoToProto(info.IfnameToIPMap),
+	}
+}
+
+// EndpointInfoFromProto converts a protobuf EndpointInfo to a restserver.EndpointInfo.
+func EndpointInfoFromProto(info *pb.EndpointInfo) (restserver.EndpointInfo, error) {
+	ipInfo, err := IPInfoFromProto(info.GetIfnameToIPMap())
+	if err != nil {
+		return restserver.EndpointInfo{}, err
+	}
+	return restserver.EndpointInfo{
+		PodName:       info.GetPodName(),
+		PodNamespace:  info.GetPodNamespace(),
+		IfnameToIPMap: ipInfo,
+	}, nil
+}
@@ -1,6 +1,6 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
-// 	protoc-gen-go v1.34.1
+// 	protoc-gen-go v1.34.2
 // 	protoc        v3.12.4
 // source: cns/grpc/proto/server.proto
 
@@ -20,6 +20,59 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// EndpointEventType is the kind of change to an endpoint.
+type EndpointEventType int32
+
+const (
+	EndpointEventType_ENDPOINT_EVENT_TYPE_UNSPECIFIED EndpointEventType = 0 // Unknown change.
+	EndpointEventType_ENDPOINT_EVENT_TYPE_ADDED       EndpointEventType = 1 // The endpoint was added.
+	EndpointEventType_ENDPOINT_EVENT_TYPE_UPDATED     EndpointEventType = 2 // The endpoint was updated.
+	EndpointEventType_ENDPOINT_EVENT_TYPE_DELETED     EndpointEventType = 3 // The endpoint was deleted.
+)
+
+// Enum value maps for EndpointEventType.
+var (
+	EndpointEventType_name = map[int32]string{
+		0: "ENDPOINT_EVENT_TYPE_UNSPECIFIED",
+		1: "ENDPOINT_EVENT_TYPE_ADDED",
+		2: "ENDPOINT_EVENT_TYPE_UPDATED",
+		3: "ENDPOINT_EVENT_TYPE_DELETED",
+	}
+	EndpointEventType_value = map[string]int32{
+		"ENDPOINT_EVENT_TYPE_UNSPECIFIED": 0,
+		"ENDPOINT_EVENT_TYPE_ADDED":       1,
+		"ENDPOINT_EVENT_TYPE_UPDATED":     2,
+		"ENDPOINT_EVENT_TYPE_DELETED":     3,
+	}
+)
+
+func (x EndpointEventType) Enum() *EndpointEventType {
+	p := new(EndpointEventType)
+	*p = x
+	return p
+}
+
+func (x EndpointEventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (EndpointEventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_cns_grpc_proto_server_proto_enumTypes[0].Descriptor()
+}
+
+func (EndpointEventType) Type() protoreflect.EnumType {
+	return &file_cns_grpc_proto_server_proto_enumTypes[0]
+}
+
+func (x EndpointEventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use EndpointEventType.Descriptor instead.
+func (EndpointEventType) EnumDescriptor() ([]byte, []int) {
+	return file_cns_grpc_proto_server_proto_rawDescGZIP(), []int{0}
+}
+
 // SetOrchestratorInfoRequest is the request message for setting the orchestrator information.
 type SetOrchestratorInfoRequest struct {
 	state         protoimpl.MessageState
@@ -259,124 +312,2731 @@ func (x *NodeInfoResponse) GetMessage() string {
 	return ""
 }
 
-var File_cns_grpc_proto_server_proto protoreflect.FileDescriptor
+// Response carries the CNS return code of an operation, matching the JSON-over-HTTP API.
+type Response struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-var file_cns_grpc_proto_server_proto_rawDesc = []byte{
-	0x0a, 0x1b, 0x63, 0x6e, 0x73, 0x2f, 0x67, 0x72, 0x70, 0x63, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x2f, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x03, 0x63,
-	0x6e, 0x73, 0x22, 0x8a, 0x01, 0x0a, 0x1a, 0x53, 0x65, 0x74, 0x4f, 0x72, 0x63, 0x68, 0x65, 0x73,
-	0x74, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x12, 0x28, 0x0a, 0x0f, 0x64, 0x6e, 0x63, 0x50, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f,
-	0x6e, 0x4b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x64, 0x6e, 0x63, 0x50,
-	0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x4b, 0x65, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x6e,
-	0x6f, 0x64, 0x65, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6e, 0x6f, 0x64,
-	0x65, 0x49, 0x44, 0x12, 0x2a, 0x0a, 0x10, 0x6f, 0x72, 0x63, 0x68, 0x65, 0x73, 0x74, 0x72, 0x61,
-	0x74, 0x6f, 0x72, 0x54, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x6f,
-	0x72, 0x63, 0x68, 0x65, 0x73, 0x74, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x54, 0x79, 0x70, 0x65, 0x22,
-	0x1d, 0x0a, 0x1b, 0x53, 0x65, 0x74, 0x4f, 0x72, 0x63, 0x68, 0x65, 0x73, 0x74, 0x72, 0x61, 0x74,
-	0x6f, 0x72, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x29,
-	0x0a, 0x0f, 0x4e, 0x6f, 0x64, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x12, 0x16, 0x0a, 0x06, 0x6e, 0x6f, 0x64, 0x65, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x06, 0x6e, 0x6f, 0x64, 0x65, 0x49, 0x44, 0x22, 0x9e, 0x01, 0x0a, 0x10, 0x4e, 0x6f,
-	0x64, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16,
-	0x0a, 0x06, 0x6e, 0x6f, 0x64, 0x65, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
-	0x6e, 0x6f, 0x64, 0x65, 0x49, 0x44, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x70,
-	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x70, 0x12, 0x1c, 0x0a, 0x09, 0x69, 0x73,
-	0x48, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x69,
-	0x73, 0x48, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74,
-	0x75, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73,
-	0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x32, 0x9b, 0x01, 0x0a, 0x03, 0x43,
-	0x4e, 0x53, 0x12, 0x58, 0x0a, 0x13, 0x53, 0x65, 0x74, 0x4f, 0x72, 0x63, 0x68, 0x65, 0x73, 0x74,
-	0x72, 0x61, 0x74, 0x6f, 0x72, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x1f, 0x2e, 0x63, 0x6e, 0x73, 0x2e,
-	0x53, 0x65, 0x74, 0x4f, 0x72, 0x63, 0x68, 0x65, 0x73, 0x74, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x49,
-	0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x63, 0x6e, 0x73,
-	0x2e, 0x53, 0x65, 0x74, 0x4f, 0x72, 0x63, 0x68, 0x65, 0x73, 0x74, 0x72, 0x61, 0x74, 0x6f, 0x72,
-	0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3a, 0x0a, 0x0b,
-	0x47, 0x65, 0x74, 0x4e, 0x6f, 0x64, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x14, 0x2e, 0x63, 0x6e,
-	0x73, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x1a, 0x15, 0x2e, 0x63, 0x6e, 0x73, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x49, 0x6e, 0x66, 0x6f,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x12, 0x5a, 0x10, 0x63, 0x6e, 0x73, 0x2f,
-	0x67, 0x72, 0x70, 0x63, 0x2f, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x62, 0x06, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x33,
+	ReturnCode int32  `protobuf:"varint,1,opt,name=returnCode,proto3" json:"returnCode,omitempty"` // The CNS return code, 0 on success.
+	Message    string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`        // Details on the failure.
 }
 
-var (
-	file_cns_grpc_proto_server_proto_rawDescOnce sync.Once
-	file_cns_grpc_proto_server_proto_rawDescData = file_cns_grpc_proto_server_proto_rawDesc
-)
+func (x *Response) Reset() {
+	*x = Response{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cns_grpc_proto_server_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
 
-func file_cns_grpc_proto_server_proto_rawDescGZIP() []byte {
-	file_cns_grpc_proto_server_proto_rawDescOnce.Do(func() {
-		file_cns_grpc_proto_server_proto_rawDescData = protoimpl.X.CompressGZIP(file_cns_grpc_proto_server_proto_rawDescData)
-	})
-	return file_cns_grpc_proto_server_proto_rawDescData
+func (x *Response) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-var file_cns_grpc_proto_server_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
-var file_cns_grpc_proto_server_proto_goTypes = []interface{}{
-	(*SetOrchestratorInfoRequest)(nil),  // 0: cns.SetOrchestratorInfoRequest
-	(*SetOrchestratorInfoResponse)(nil), // 1: cns.SetOrchestratorInfoResponse
-	(*NodeInfoRequest)(nil),             // 2: cns.NodeInfoRequest
-	(*NodeInfoResponse)(nil),            // 3: cns.NodeInfoResponse
+func (*Response) ProtoMessage() {}
+
+func (x *Response) ProtoReflect() protoreflect.Message {
+	mi := &file_cns_grpc_proto_server_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
-var file_cns_grpc_proto_server_proto_depIdxs = []int32{
-	0, // 0: cns.CNS.SetOrchestratorInfo:input_type -> cns.SetOrchestratorInfoRequest
-	2, // 1: cns.CNS.GetNodeInfo:input_type -> cns.NodeInfoRequest
-	1, // 2: cns.CNS.SetOrchestratorInfo:output_type -> cns.SetOrchestratorInfoResponse
-	3, // 3: cns.CNS.GetNodeInfo:output_type -> cns.NodeInfoResponse
-	2, // [2:4] is the sub-list for method output_type
-	0, // [0:2] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+
+// Deprecated: Use Response.ProtoReflect.Descriptor instead.
+func (*Response) Descriptor() ([]byte, []int) {
+	return file_cns_grpc_proto_server_proto_rawDescGZIP(), []int{4}
 }
 
-func init() { file_cns_grpc_proto_server_proto_init() }
-func file_cns_grpc_proto_server_proto_init() {
-	if File_cns_grpc_proto_server_proto != nil {
-		return
+func (x *Response) GetReturnCode() int32 {
+	if x != nil {
+		return x.ReturnCode
 	}
-	if !protoimpl.UnsafeEnabled {
-		file_cns_grpc_proto_server_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SetOrchestratorInfoRequest); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+	return 0
+}
+
+func (x *Response) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// IPConfigsRequest is the request message for the IPAM operations on a pod.
+type IPConfigsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DesiredIPAddresses           []string `protobuf:"bytes,1,rep,name=desiredIPAddresses,proto3" json:"desiredIPAddresses,omitempty"`                     // The IPs to assign, if any.
+	PodInterfaceID               string   `protobuf:"bytes,2,opt,name=podInterfaceID,proto3" json:"podInterfaceID,omitempty"`                             // The pod interface ID.
+	InfraContainerID             string   `protobuf:"bytes,3,opt,name=infraContainerID,proto3" json:"infraContainerID,omitempty"`                         // The pod infra container ID.
+	OrchestratorContext          []byte   `protobuf:"bytes,4,opt,name=orchestratorContext,proto3" json:"orchestratorContext,omitempty"`                   // The JSON encoded orchestrator context of the pod.
+	Ifname                       string   `protobuf:"bytes,5,opt,name=ifname,proto3" json:"ifname,omitempty"`                                             // The interface name, used by delegated IPAM.
+	SecondaryInterfacesExist     bool     `protobuf:"varint,6,opt,name=secondaryInterfacesExist,proto3" json:"secondaryInterfacesExist,omitempty"`        // Whether the pod has secondary interfaces.
+	BackendInterfaceExist        bool     `protobuf:"varint,7,opt,name=backendInterfaceExist,proto3" json:"backendInterfaceExist,omitempty"`              // Whether the pod has backend interfaces.
+	BackendInterfaceMacAddresses []string `protobuf:"bytes,8,rep,name=backendInterfaceMacAddresses,proto3" json:"backendInterfaceMacAddresses,omitempty"` // The MAC addresses of the backend interfaces.
+}
+
+func (x *IPConfigsRequest) Reset() {
+	*x = IPConfigsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cns_grpc_proto_server_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *IPConfigsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IPConfigsRequest) ProtoMessage() {}
+
+func (x *IPConfigsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cns_grpc_proto_server_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
-		file_cns_grpc_proto_server_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SetOrchestratorInfoResponse); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IPConfigsRequest.ProtoReflect.Descriptor instead.
+func (*IPConfigsRequest) Descriptor() ([]byte, []int) {
+	return file_cns_grpc_proto_server_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *IPConfigsRequest) GetDesiredIPAddresses() []string {
+	if x != nil {
+		return x.DesiredIPAddresses
+	}
+	return nil
+}
+
+func (x *IPConfigsRequest) GetPodInterfaceID() string {
+	if x != nil {
+		return x.PodInterfaceID
+	}
+	return ""
+}
+
+func (x *IPConfigsRequest) GetInfraContainerID() string {
+	if x != nil {
+		return x.InfraContainerID
+	}
+	return ""
+}
+
+func (x *IPConfigsRequest) GetOrchestratorContext() []byte {
+	if x != nil {
+		return x.OrchestratorContext
+	}
+	return nil
+}
+
+func (x *IPConfigsRequest) GetIfname() string {
+	if x != nil {
+		return x.Ifname
+	}
+	return ""
+}
+
+func (x *IPConfigsRequest) GetSecondaryInterfacesExist() bool {
+	if x != nil {
+		return x.SecondaryInterfacesExist
+	}
+	return false
+}
+
+func (x *IPConfigsRequest) GetBackendInterfaceExist() bool {
+	if x != nil {
+		return x.BackendInterfaceExist
+	}
+	return false
+}
+
+func (x *IPConfigsRequest) GetBackendInterfaceMacAddresses() []string {
+	if x != nil {
+		return x.BackendInterfaceMacAddresses
+	}
+	return nil
+}
+
+// IPSubnet is an IP address and its prefix length.
+type IPSubnet struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	IpAddress    string `protobuf:"bytes,1,opt,name=ipAddress,proto3" json:"ipAddress,omitempty"`        // The IP address.
+	PrefixLength uint32 `protobuf:"varint,2,opt,name=prefixLength,proto3" json:"prefixLength,omitempty"` // The prefix length of the subnet.
+}
+
+func (x *IPSubnet) Reset() {
+	*x = IPSubnet{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cns_grpc_proto_server_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *IPSubnet) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IPSubnet) ProtoMessage() {}
+
+func (x *IPSubnet) ProtoReflect() protoreflect.Message {
+	mi := &file_cns_grpc_proto_server_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
-		file_cns_grpc_proto_server_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*NodeInfoRequest); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IPSubnet.ProtoReflect.Descriptor instead.
+func (*IPSubnet) Descriptor() ([]byte, []int) {
+	return file_cns_grpc_proto_server_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *IPSubnet) GetIpAddress() string {
+	if x != nil {
+		return x.IpAddress
+	}
+	return ""
+}
+
+func (x *IPSubnet) GetPrefixLength() uint32 {
+	if x != nil {
+		return x.PrefixLength
+	}
+	return 0
+}
+
+// IPConfiguration is the IP configuration of a network container.
+type IPConfiguration struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	IpSubnet         *IPSubnet `protobuf:"bytes,1,opt,name=ipSubnet,proto3" json:"ipSubnet,omitempty"`                 // The IP address and subnet.
+	DnsServers       []string  `protobuf:"bytes,2,rep,name=dnsServers,proto3" json:"dnsServers,omitempty"`             // The DNS servers.
+	GatewayIPAddress string    `protobuf:"bytes,3,opt,name=gatewayIPAddress,proto3" json:"gatewayIPAddress,omitempty"` // The gateway IP address.
+}
+
+func (x *IPConfiguration) Reset() {
+	*x = IPConfiguration{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cns_grpc_proto_server_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *IPConfiguration) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IPConfiguration) ProtoMessage() {}
+
+func (x *IPConfiguration) ProtoReflect() protoreflect.Message {
+	mi := &file_cns_grpc_proto_server_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
-		file_cns_grpc_proto_server_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*NodeInfoResponse); i {
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IPConfiguration.ProtoReflect.Descriptor instead.
+func (*IPConfiguration) Descriptor() ([]byte, []int) {
+	return file_cns_grpc_proto_server_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *IPConfiguration) GetIpSubnet() *IPSubnet {
+	if x != nil {
+		return x.IpSubnet
+	}
+	return nil
+}
+
+func (x *IPConfiguration) GetDnsServers() []string {
+	if x != nil {
+		return x.DnsServers
+	}
+	return nil
+}
+
+func (x *IPConfiguration) GetGatewayIPAddress() string {
+	if x != nil {
+		return x.GatewayIPAddress
+	}
+	return ""
+}
+
+// HostIPInfo is the primary IP configuration of the host.
+type HostIPInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Gateway   string `protobuf:"bytes,1,opt,name=gateway,proto3" json:"gateway,omitempty"`     // The gateway IP address.
+	PrimaryIP string `protobuf:"bytes,2,opt,name=primaryIP,proto3" json:"primaryIP,omitempty"` // The primary IP address.
+	Subnet    string `protobuf:"bytes,3,opt,name=subnet,proto3" json:"subnet,omitempty"`       // The subnet of the primary IP.
+}
+
+func (x *HostIPInfo) Reset() {
+	*x = HostIPInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cns_grpc_proto_server_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *HostIPInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HostIPInfo) ProtoMessage() {}
+
+func (x *HostIPInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_cns_grpc_proto_server_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HostIPInfo.ProtoReflect.Descriptor instead.
+func (*HostIPInfo) Descriptor() ([]byte, []int) {
+	return file_cns_grpc_proto_server_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *HostIPInfo) GetGateway() string {
+	if x != nil {
+		return x.Gateway
+	}
+	return ""
+}
+
+func (x *HostIPInfo) GetPrimaryIP() string {
+	if x != nil {
+		return x.PrimaryIP
+	}
+	return ""
+}
+
+func (x *HostIPInfo) GetSubnet() string {
+	if x != nil {
+		return x.Subnet
+	}
+	return ""
+}
+
+// Route is a route to configure on an interface.
+type Route struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	IpAddress        string `protobuf:"bytes,1,opt,name=ipAddress,proto3" json:"ipAddress,omitempty"`               // The destination prefix.
+	GatewayIPAddress string `protobuf:"bytes,2,opt,name=gatewayIPAddress,proto3" json:"gatewayIPAddress,omitempty"` // The next hop.
+	InterfaceToUse   string `protobuf:"bytes,3,opt,name=interfaceToUse,proto3" json:"interfaceToUse,omitempty"`     // The interface to route through.
+}
+
+func (x *Route) Reset() {
+	*x = Route{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cns_grpc_proto_server_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Route) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Route) ProtoMessage() {}
+
+func (x *Route) ProtoReflect() protoreflect.Message {
+	mi := &file_cns_grpc_proto_server_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Route.ProtoReflect.Descriptor instead.
+func (*Route) Descriptor() ([]byte, []int) {
+	return file_cns_grpc_proto_server_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Route) GetIpAddress() string {
+	if x != nil {
+		return x.IpAddress
+	}
+	return ""
+}
+
+func (x *Route) GetGatewayIPAddress() string {
+	if x != nil {
+		return x.GatewayIPAddress
+	}
+	return ""
+}
+
+func (x *Route) GetInterfaceToUse() string {
+	if x != nil {
+		return x.InterfaceToUse
+	}
+	return ""
+}
+
+// PodIpInfo is the IP configuration of one pod interface.
+type PodIpInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PodIPConfig                     *IPSubnet        `protobuf:"bytes,1,opt,name=podIPConfig,proto3" json:"podIPConfig,omitempty"`                                         // The pod IP address.
+	NetworkContainerPrimaryIPConfig *IPConfiguration `protobuf:"bytes,2,opt,name=networkContainerPrimaryIPConfig,proto3" json:"networkContainerPrimaryIPConfig,omitempty"` // The primary IP configuration of the network container.
+	HostPrimaryIPInfo               *HostIPInfo      `protobuf:"bytes,3,opt,name=hostPrimaryIPInfo,proto3" json:"hostPrimaryIPInfo,omitempty"`                             // The primary IP configuration of the host.
+	NicType                         string           `protobuf:"bytes,4,opt,name=nicType,proto3" json:"nicType,omitempty"`                                                 // The type of the interface.
+	InterfaceName                   string           `protobuf:"bytes,5,opt,name=interfaceName,proto3" json:"interfaceName,omitempty"`                                     // The name of the interface.
+	MacAddress                      string           `protobuf:"bytes,6,opt,name=macAddress,proto3" json:"macAddress,omitempty"`                                           // The MAC address of the interface.
+	SkipDefaultRoutes               bool             `protobuf:"varint,7,opt,name=skipDefaultRoutes,proto3" json:"skipDefaultRoutes,omitempty"`                            // Whether default routes are skipped on the interface.
+	Routes                          []*Route         `protobuf:"bytes,8,rep,name=routes,proto3" json:"routes,omitempty"`                                                   // The routes to configure on the interface.
+	PnpID                           string           `protobuf:"bytes,9,opt,name=pnpID,proto3" json:"pnpID,omitempty"`                                                     // The PnP ID of backend interfaces.
+}
+
+func (x *PodIpInfo) Reset() {
+	*x = PodIpInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cns_grpc_proto_server_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PodIpInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PodIpInfo) ProtoMessage() {}
+
+func (x *PodIpInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_cns_grpc_proto_server_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PodIpInfo.ProtoReflect.Descriptor instead.
+func (*PodIpInfo) Descriptor() ([]byte, []int) {
+	return file_cns_grpc_proto_server_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *PodIpInfo) GetPodIPConfig() *IPSubnet {
+	if x != nil {
+		return x.PodIPConfig
+	}
+	return nil
+}
+
+func (x *PodIpInfo) GetNetworkContainerPrimaryIPConfig() *IPConfiguration {
+	if x != nil {
+		return x.NetworkContainerPrimaryIPConfig
+	}
+	return nil
+}
+
+func (x *PodIpInfo) GetHostPrimaryIPInfo() *HostIPInfo {
+	if x != nil {
+		return x.HostPrimaryIPInfo
+	}
+	return nil
+}
+
+func (x *PodIpInfo) GetNicType() string {
+	if x != nil {
+		return x.NicType
+	}
+	return ""
+}
+
+func (x *PodIpInfo) GetInterfaceName() string {
+	if x != nil {
+		return x.InterfaceName
+	}
+	return ""
+}
+
+func (x *PodIpInfo) GetMacAddress() string {
+	if x != nil {
+		return x.MacAddress
+	}
+	return ""
+}
+
+func (x *PodIpInfo) GetSkipDefaultRoutes() bool {
+	if x != nil {
+		return x.SkipDefaultRoutes
+	}
+	return false
+}
+
+func (x *PodIpInfo) GetRoutes() []*Route {
+	if x != nil {
+		return x.Routes
+	}
+	return nil
+}
+
+func (x *PodIpInfo) GetPnpID() string {
+	if x != nil {
+		return x.PnpID
+	}
+	return ""
+}
+
+// IPConfigsResponse is the response message for the IPAM operations on a pod.
+type IPConfigsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PodIPInfo  []*PodIpInfo `protobuf:"bytes,1,rep,name=podIPInfo,proto3" json:"podIPInfo,omitempty"`   // The IP configuration of each pod interface.
+	Response   *Response    `protobuf:"bytes,2,opt,name=response,proto3" json:"response,omitempty"`     // The result of the operation.
+	EgressSNAT *EgressSNAT  `protobuf:"bytes,3,opt,name=egressSNAT,proto3" json:"egressSNAT,omitempty"` // How the egress traffic of the pod is source NATed, unset to leave it as configured.
+}
+
+func (x *IPConfigsResponse) Reset() {
+	*x = IPConfigsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cns_grpc_proto_server_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *IPConfigsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IPConfigsResponse) ProtoMessage() {}
+
+func (x *IPConfigsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_cns_grpc_proto_server_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IPConfigsResponse.ProtoReflect.Descriptor instead.
+func (*IPConfigsResponse) Descriptor() ([]byte, []int) {
+	return file_cns_grpc_proto_server_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *IPConfigsResponse) GetPodIPInfo() []*PodIpInfo {
+	if x != nil {
+		return x.PodIPInfo
+	}
+	return nil
+}
+
+func (x *IPConfigsResponse) GetResponse() *Response {
+	if x != nil {
+		return x.Response
+	}
+	return nil
+}
+
+func (x *IPConfigsResponse) GetEgressSNAT() *EgressSNAT {
+	if x != nil {
+		return x.EgressSNAT
+	}
+	return nil
+}
+
+// EgressSNAT chooses how the traffic a pod sends out of the vnet is source NATed on the node.
+type EgressSNAT struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Mode      string   `protobuf:"bytes,1,opt,name=mode,proto3" json:"mode,omitempty"`           // node-ip, none or secondary-ip.
+	IpAddress string   `protobuf:"bytes,2,opt,name=ipAddress,proto3" json:"ipAddress,omitempty"` // The secondary IP of the node, for secondary-ip.
+	Except    []string `protobuf:"bytes,3,rep,name=except,proto3" json:"except,omitempty"`       // The CIDRs of the vnet, whose traffic is not source NATed, in the endpoint state only.
+}
+
+func (x *EgressSNAT) Reset() {
+	*x = EgressSNAT{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cns_grpc_proto_server_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EgressSNAT) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EgressSNAT) ProtoMessage() {}
+
+func (x *EgressSNAT) ProtoReflect() protoreflect.Message {
+	mi := &file_cns_grpc_proto_server_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EgressSNAT.ProtoReflect.Descriptor instead.
+func (*EgressSNAT) Descriptor() ([]byte, []int) {
+	return file_cns_grpc_proto_server_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *EgressSNAT) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+func (x *EgressSNAT) GetIpAddress() string {
+	if x != nil {
+		return x.IpAddress
+	}
+	return ""
+}
+
+func (x *EgressSNAT) GetExcept() []string {
+	if x != nil {
+		return x.Except
+	}
+	return nil
+}
+
+// GetIPAddressesRequest is the request message for retrieving IPs in the pool.
+type GetIPAddressesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	IpConfigStateFilter []string `protobuf:"bytes,1,rep,name=ipConfigStateFilter,proto3" json:"ipConfigStateFilter,omitempty"` // The states to match, e.g. Available or Assigned.
+}
+
+func (x *GetIPAddressesRequest) Reset() {
+	*x = GetIPAddressesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cns_grpc_proto_server_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetIPAddressesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetIPAddressesRequest) ProtoMessage() {}
+
+func (x *GetIPAddressesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cns_grpc_proto_server_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetIPAddressesRequest.ProtoReflect.Descriptor instead.
+func (*GetIPAddressesRequest) Descriptor() ([]byte, []int) {
+	return file_cns_grpc_proto_server_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetIPAddressesRequest) GetIpConfigStateFilter() []string {
+	if x != nil {
+		return x.IpConfigStateFilter
+	}
+	return nil
+}
+
+// IPConfigurationStatus is the state of one IP in the pool.
+type IPConfigurationStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id                  string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`                                    // The IP ID.
+	NcID                string `protobuf:"bytes,2,opt,name=ncID,proto3" json:"ncID,omitempty"`                                // The network container the IP belongs to.
+	IpAddress           string `protobuf:"bytes,3,opt,name=ipAddress,proto3" json:"ipAddress,omitempty"`                      // The IP address.
+	State               string `protobuf:"bytes,4,opt,name=state,proto3" json:"state,omitempty"`                              // The state of the IP.
+	PodInterfaceID      string `protobuf:"bytes,5,opt,name=podInterfaceID,proto3" json:"podInterfaceID,omitempty"`            // The interface ID of the pod the IP is assigned to.
+	InfraContainerID    string `protobuf:"bytes,6,opt,name=infraContainerID,proto3" json:"infraContainerID,omitempty"`        // The infra container ID of the pod the IP is assigned to.
+	PodName             string `protobuf:"bytes,7,opt,name=podName,proto3" json:"podName,omitempty"`                          // The name of the pod the IP is assigned to.
+	PodNamespace        string `protobuf:"bytes,8,opt,name=podNamespace,proto3" json:"podNamespace,omitempty"`                // The namespace of the pod the IP is assigned to.
+	LastStateTransition int64  `protobuf:"varint,9,opt,name=lastStateTransition,proto3" json:"lastStateTransition,omitempty"` // Unix time in nanoseconds of the last state transition.
+	LeaseExpiry         int64  `protobuf:"varint,10,opt,name=leaseExpiry,proto3" json:"leaseExpiry,omitempty"`                // Unix time in nanoseconds at which the lease expires, 0 without a lease.
+}
+
+func (x *IPConfigurationStatus) Reset() {
+	*x = IPConfigurationStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cns_grpc_proto_server_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *IPConfigurationStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IPConfigurationStatus) ProtoMessage() {}
+
+func (x *IPConfigurationStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_cns_grpc_proto_server_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IPConfigurationStatus.ProtoReflect.Descriptor instead.
+func (*IPConfigurationStatus) Descriptor() ([]byte, []int) {
+	return file_cns_grpc_proto_server_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *IPConfigurationStatus) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *IPConfigurationStatus) GetNcID() string {
+	if x != nil {
+		return x.NcID
+	}
+	return ""
+}
+
+func (x *IPConfigurationStatus) GetIpAddress() string {
+	if x != nil {
+		return x.IpAddress
+	}
+	return ""
+}
+
+func (x *IPConfigurationStatus) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *IPConfigurationStatus) GetPodInterfaceID() string {
+	if x != nil {
+		return x.PodInterfaceID
+	}
+	return ""
+}
+
+func (x *IPConfigurationStatus) GetInfraContainerID() string {
+	if x != nil {
+		return x.InfraContainerID
+	}
+	return ""
+}
+
+func (x *IPConfigurationStatus) GetPodName() string {
+	if x != nil {
+		return x.PodName
+	}
+	return ""
+}
+
+func (x *IPConfigurationStatus) GetPodNamespace() string {
+	if x != nil {
+		return x.PodNamespace
+	}
+	return ""
+}
+
+func (x *IPConfigurationStatus) GetLastStateTransition() int64 {
+	if x != nil {
+		return x.LastStateTransition
+	}
+	return 0
+}
+
+func (x *IPConfigurationStatus) GetLeaseExpiry() int64 {
+	if x != nil {
+		return x.LeaseExpiry
+	}
+	return 0
+}
+
+// GetIPAddressesResponse is the response message containing the matching IPs in the pool.
+type GetIPAddressesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	IpConfigurationStatus []*IPConfigurationStatus `protobuf:"bytes,1,rep,name=ipConfigurationStatus,proto3" json:"ipConfigurationStatus,omitempty"` // The matching IPs.
+	Response              *Response                `protobuf:"bytes,2,opt,name=response,proto3" json:"response,omitempty"`                           // The result of the operation.
+}
+
+func (x *GetIPAddressesResponse) Reset() {
+	*x = GetIPAddressesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cns_grpc_proto_server_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetIPAddressesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetIPAddressesResponse) ProtoMessage() {}
+
+func (x *GetIPAddressesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_cns_grpc_proto_server_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetIPAddressesResponse.ProtoReflect.Descriptor instead.
+func (*GetIPAddressesResponse) Descriptor() ([]byte, []int) {
+	return file_cns_grpc_proto_server_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetIPAddressesResponse) GetIpConfigurationStatus() []*IPConfigurationStatus {
+	if x != nil {
+		return x.IpConfigurationStatus
+	}
+	return nil
+}
+
+func (x *GetIPAddressesResponse) GetResponse() *Response {
+	if x != nil {
+		return x.Response
+	}
+	return nil
+}
+
+// GetNetworkContainerRequest is the request message for looking up network containers.
+type GetNetworkContainerRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	NetworkContainerID  string `protobuf:"bytes,1,opt,name=networkContainerID,proto3" json:"networkContainerID,omitempty"`   // The network container ID.
+	OrchestratorContext []byte `protobuf:"bytes,2,opt,name=orchestratorContext,proto3" json:"orchestratorContext,omitempty"` // The JSON encoded orchestrator context of the pod.
+}
+
+func (x *GetNetworkContainerRequest) Reset() {
+	*x = GetNetworkContainerRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cns_grpc_proto_server_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetNetworkContainerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetNetworkContainerRequest) ProtoMessage() {}
+
+func (x *GetNetworkContainerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cns_grpc_proto_server_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetNetworkContainerRequest.ProtoReflect.Descriptor instead.
+func (*GetNetworkContainerRequest) Descriptor() ([]byte, []int) {
+	return file_cns_grpc_proto_server_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *GetNetworkContainerRequest) GetNetworkContainerID() string {
+	if x != nil {
+		return x.NetworkContainerID
+	}
+	return ""
+}
+
+func (x *GetNetworkContainerRequest) GetOrchestratorContext() []byte {
+	if x != nil {
+		return x.OrchestratorContext
+	}
+	return nil
+}
+
+// MultiTenancyInfo is the encapsulation of a multitenant network container.
+type MultiTenancyInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	EncapType string `protobuf:"bytes,1,opt,name=encapType,proto3" json:"encapType,omitempty"` // The encapsulation type, e.g. Vlan.
+	Id        int32  `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`              // The encapsulation ID.
+}
+
+func (x *MultiTenancyInfo) Reset() {
+	*x = MultiTenancyInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cns_grpc_proto_server_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MultiTenancyInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MultiTenancyInfo) ProtoMessage() {}
+
+func (x *MultiTenancyInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_cns_grpc_proto_server_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MultiTenancyInfo.ProtoReflect.Descriptor instead.
+func (*MultiTenancyInfo) Descriptor() ([]byte, []int) {
+	return file_cns_grpc_proto_server_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *MultiTenancyInfo) GetEncapType() string {
+	if x != nil {
+		return x.EncapType
+	}
+	return ""
+}
+
+func (x *MultiTenancyInfo) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+// NetworkInterfaceInfo describes the interface of a network container.
+type NetworkInterfaceInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	NicType    string `protobuf:"bytes,1,opt,name=nicType,proto3" json:"nicType,omitempty"`       // The type of the interface.
+	MacAddress string `protobuf:"bytes,2,opt,name=macAddress,proto3" json:"macAddress,omitempty"` // The MAC address of the interface.
+}
+
+func (x *NetworkInterfaceInfo) Reset() {
+	*x = NetworkInterfaceInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cns_grpc_proto_server_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NetworkInterfaceInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkInterfaceInfo) ProtoMessage() {}
+
+func (x *NetworkInterfaceInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_cns_grpc_proto_server_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkInterfaceInfo.ProtoReflect.Descriptor instead.
+func (*NetworkInterfaceInfo) Descriptor() ([]byte, []int) {
+	return file_cns_grpc_proto_server_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *NetworkInterfaceInfo) GetNicType() string {
+	if x != nil {
+		return x.NicType
+	}
+	return ""
+}
+
+func (x *NetworkInterfaceInfo) GetMacAddress() string {
+	if x != nil {
+		return x.MacAddress
+	}
+	return ""
+}
+
+// NetworkContainer is the configuration of a network container.
+type NetworkContainer struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	NetworkContainerID         string                `protobuf:"bytes,1,opt,name=networkContainerID,proto3" json:"networkContainerID,omitempty"`                   // The network container ID.
+	IpConfiguration            *IPConfiguration      `protobuf:"bytes,2,opt,name=ipConfiguration,proto3" json:"ipConfiguration,omitempty"`                         // The IP configuration.
+	Routes                     []*Route              `protobuf:"bytes,3,rep,name=routes,proto3" json:"routes,omitempty"`                                           // The routes.
+	CnetAddressSpace           []*IPSubnet           `protobuf:"bytes,4,rep,name=cnetAddressSpace,proto3" json:"cnetAddressSpace,omitempty"`                       // The customer network address space.
+	MultiTenancyInfo           *MultiTenancyInfo     `protobuf:"bytes,5,opt,name=multiTenancyInfo,proto3" json:"multiTenancyInfo,omitempty"`                       // The multitenancy encapsulation.
+	PrimaryInterfaceIdentifier string                `protobuf:"bytes,6,opt,name=primaryInterfaceIdentifier,proto3" json:"primaryInterfaceIdentifier,omitempty"`   // The primary interface identifier.
+	LocalIPConfiguration       *IPConfiguration      `protobuf:"bytes,7,opt,name=localIPConfiguration,proto3" json:"localIPConfiguration,omitempty"`               // The local IP configuration.
+	Response                   *Response             `protobuf:"bytes,8,opt,name=response,proto3" json:"response,omitempty"`                                       // The result of the lookup.
+	AllowHostToNCCommunication bool                  `protobuf:"varint,9,opt,name=allowHostToNCCommunication,proto3" json:"allowHostToNCCommunication,omitempty"`  // Whether the host can reach the network container.
+	AllowNCToHostCommunication bool                  `protobuf:"varint,10,opt,name=allowNCToHostCommunication,proto3" json:"allowNCToHostCommunication,omitempty"` // Whether the network container can reach the host.
+	NetworkInterfaceInfo       *NetworkInterfaceInfo `protobuf:"bytes,11,opt,name=networkInterfaceInfo,proto3" json:"networkInterfaceInfo,omitempty"`              // The interface of the network container.
+}
+
+func (x *NetworkContainer) Reset() {
+	*x = NetworkContainer{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cns_grpc_proto_server_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NetworkContainer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkContainer) ProtoMessage() {}
+
+func (x *NetworkContainer) ProtoReflect() protoreflect.Message {
+	mi := &file_cns_grpc_proto_server_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkContainer.ProtoReflect.Descriptor instead.
+func (*NetworkContainer) Descriptor() ([]byte, []int) {
+	return file_cns_grpc_proto_server_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *NetworkContainer) GetNetworkContainerID() string {
+	if x != nil {
+		return x.NetworkContainerID
+	}
+	return ""
+}
+
+func (x *NetworkContainer) GetIpConfiguration() *IPConfiguration {
+	if x != nil {
+		return x.IpConfiguration
+	}
+	return nil
+}
+
+func (x *NetworkContainer) GetRoutes() []*Route {
+	if x != nil {
+		return x.Routes
+	}
+	return nil
+}
+
+func (x *NetworkContainer) GetCnetAddressSpace() []*IPSubnet {
+	if x != nil {
+		return x.CnetAddressSpace
+	}
+	return nil
+}
+
+func (x *NetworkContainer) GetMultiTenancyInfo() *MultiTenancyInfo {
+	if x != nil {
+		return x.MultiTenancyInfo
+	}
+	return nil
+}
+
+func (x *NetworkContainer) GetPrimaryInterfaceIdentifier() string {
+	if x != nil {
+		return x.PrimaryInterfaceIdentifier
+	}
+	return ""
+}
+
+func (x *NetworkContainer) GetLocalIPConfiguration() *IPConfiguration {
+	if x != nil {
+		return x.LocalIPConfiguration
+	}
+	return nil
+}
+
+func (x *NetworkContainer) GetResponse() *Response {
+	if x != nil {
+		return x.Response
+	}
+	return nil
+}
+
+func (x *NetworkContainer) GetAllowHostToNCCommunication() bool {
+	if x != nil {
+		return x.AllowHostToNCCommunication
+	}
+	return false
+}
+
+func (x *NetworkContainer) GetAllowNCToHostCommunication() bool {
+	if x != nil {
+		return x.AllowNCToHostCommunication
+	}
+	return false
+}
+
+func (x *NetworkContainer) GetNetworkInterfaceInfo() *NetworkInterfaceInfo {
+	if x != nil {
+		return x.NetworkInterfaceInfo
+	}
+	return nil
+}
+
+// GetAllNetworkContainersResponse is the response message containing the matching network containers.
+type GetAllNetworkContainersResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	NetworkContainers []*NetworkContainer `protobuf:"bytes,1,rep,name=networkContainers,proto3" json:"networkContainers,omitempty"` // The matching network containers.
+	Response          *Response           `protobuf:"bytes,2,opt,name=response,proto3" json:"response,omitempty"`                   // The result of the operation.
+}
+
+func (x *GetAllNetworkContainersResponse) Reset() {
+	*x = GetAllNetworkContainersResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cns_grpc_proto_server_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetAllNetworkContainersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAllNetworkContainersResponse) ProtoMessage() {}
+
+func (x *GetAllNetworkContainersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_cns_grpc_proto_server_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAllNetworkContainersResponse.ProtoReflect.Descriptor instead.
+func (*GetAllNetworkContainersResponse) Descriptor() ([]byte, []int) {
+	return file_cns_grpc_proto_server_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *GetAllNetworkContainersResponse) GetNetworkContainers() []*NetworkContainer {
+	if x != nil {
+		return x.NetworkContainers
+	}
+	return nil
+}
+
+func (x *GetAllNetworkContainersResponse) GetResponse() *Response {
+	if x != nil {
+		return x.Response
+	}
+	return nil
+}
+
+// IPInfo is the state of one interface of an endpoint.
+type IPInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Ipv4                     []string       `protobuf:"bytes,1,rep,name=ipv4,proto3" json:"ipv4,omitempty"`                                           // The IPv4 addresses in CIDR notation.
+	Ipv6                     []string       `protobuf:"bytes,2,rep,name=ipv6,proto3" json:"ipv6,omitempty"`                                           // The IPv6 addresses in CIDR notation.
+	HnsEndpointID            string         `protobuf:"bytes,3,opt,name=hnsEndpointID,proto3" json:"hnsEndpointID,omitempty"`                         // The HNS endpoint ID.
+	HnsNetworkID             string         `protobuf:"bytes,4,opt,name=hnsNetworkID,proto3" json:"hnsNetworkID,omitempty"`                           // The HNS network ID.
+	HostVethName             string         `protobuf:"bytes,5,opt,name=hostVethName,proto3" json:"hostVethName,omitempty"`                           // The name of the host veth.
+	MacAddress               string         `protobuf:"bytes,6,opt,name=macAddress,proto3" json:"macAddress,omitempty"`                               // The MAC address of the interface.
+	NicType                  string         `protobuf:"bytes,7,opt,name=nicType,proto3" json:"nicType,omitempty"`                                     // The type of the interface.
+	PortMappings             []*PortMapping `protobuf:"bytes,8,rep,name=portMappings,proto3" json:"portMappings,omitempty"`                           // The host ports forwarded to the interface.
+	Bandwidth                *Bandwidth     `protobuf:"bytes,9,opt,name=bandwidth,proto3" json:"bandwidth,omitempty"`                                 // The shaping of the interface traffic.
+	NetworkMode              string         `protobuf:"bytes,10,opt,name=networkMode,proto3" json:"networkMode,omitempty"`                            // The mode of the CNI network of the interface, such as transparent.
+	EgressSNAT               *EgressSNAT    `protobuf:"bytes,11,opt,name=egressSNAT,proto3" json:"egressSNAT,omitempty"`                              // The source NAT of the interface egress traffic.
+	NetworkID                string         `protobuf:"bytes,12,opt,name=networkID,proto3" json:"networkID,omitempty"`                                // The CNI network of the interface.
+	Created                  int64          `protobuf:"varint,13,opt,name=created,proto3" json:"created,omitempty"`                                   // When the interface was created, in nanoseconds since the unix epoch.
+	PortForwardAddress       string         `protobuf:"bytes,14,opt,name=portForwardAddress,proto3" json:"portForwardAddress,omitempty"`              // The address the host ports are forwarded to in place of the interface addresses, in CIDR notation.
+	PortForwardMasqueradeAll bool           `protobuf:"varint,15,opt,name=portForwardMasqueradeAll,proto3" json:"portForwardMasqueradeAll,omitempty"` // Whether all of the traffic forwarded to the host ports is masqueraded.
+}
+
+func (x *IPInfo) Reset() {
+	*x = IPInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cns_grpc_proto_server_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *IPInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IPInfo) ProtoMessage() {}
+
+func (x *IPInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_cns_grpc_proto_server_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IPInfo.ProtoReflect.Descriptor instead.
+func (*IPInfo) Descriptor() ([]byte, []int) {
+	return file_cns_grpc_proto_server_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *IPInfo) GetIpv4() []string {
+	if x != nil {
+		return x.Ipv4
+	}
+	return nil
+}
+
+func (x *IPInfo) GetIpv6() []string {
+	if x != nil {
+		return x.Ipv6
+	}
+	return nil
+}
+
+func (x *IPInfo) GetHnsEndpointID() string {
+	if x != nil {
+		return x.HnsEndpointID
+	}
+	return ""
+}
+
+func (x *IPInfo) GetHnsNetworkID() string {
+	if x != nil {
+		return x.HnsNetworkID
+	}
+	return ""
+}
+
+func (x *IPInfo) GetHostVethName() string {
+	if x != nil {
+		return x.HostVethName
+	}
+	return ""
+}
+
+func (x *IPInfo) GetMacAddress() string {
+	if x != nil {
+		return x.MacAddress
+	}
+	return ""
+}
+
+func (x *IPInfo) GetNicType() string {
+	if x != nil {
+		return x.NicType
+	}
+	return ""
+}
+
+func (x *IPInfo) GetPortMappings() []*PortMapping {
+	if x != nil {
+		return x.PortMappings
+	}
+	return nil
+}
+
+func (x *IPInfo) GetBandwidth() *Bandwidth {
+	if x != nil {
+		return x.Bandwidth
+	}
+	return nil
+}
+
+func (x *IPInfo) GetNetworkMode() string {
+	if x != nil {
+		return x.NetworkMode
+	}
+	return ""
+}
+
+func (x *IPInfo) GetEgressSNAT() *EgressSNAT {
+	if x != nil {
+		return x.EgressSNAT
+	}
+	return nil
+}
+
+func (x *IPInfo) GetNetworkID() string {
+	if x != nil {
+		return x.NetworkID
+	}
+	return ""
+}
+
+func (x *IPInfo) GetCreated() int64 {
+	if x != nil {
+		return x.Created
+	}
+	return 0
+}
+
+func (x *IPInfo) GetPortForwardAddress() string {
+	if x != nil {
+		return x.PortForwardAddress
+	}
+	return ""
+}
+
+func (x *IPInfo) GetPortForwardMasqueradeAll() bool {
+	if x != nil {
+		return x.PortForwardMasqueradeAll
+	}
+	return false
+}
+
+// PortMapping forwards a host port to a port of an endpoint interface.
+type PortMapping struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	HostPort      int32  `protobuf:"varint,1,opt,name=hostPort,proto3" json:"hostPort,omitempty"`           // The host port.
+	ContainerPort int32  `protobuf:"varint,2,opt,name=containerPort,proto3" json:"containerPort,omitempty"` // The port of the interface.
+	Protocol      string `protobuf:"bytes,3,opt,name=protocol,proto3" json:"protocol,omitempty"`            // tcp, udp or sctp.
+	HostIP        string `protobuf:"bytes,4,opt,name=hostIP,proto3" json:"hostIP,omitempty"`                // Only forward the host port on this address, if set.
+}
+
+func (x *PortMapping) Reset() {
+	*x = PortMapping{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cns_grpc_proto_server_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PortMapping) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PortMapping) ProtoMessage() {}
+
+func (x *PortMapping) ProtoReflect() protoreflect.Message {
+	mi := &file_cns_grpc_proto_server_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PortMapping.ProtoReflect.Descriptor instead.
+func (*PortMapping) Descriptor() ([]byte, []int) {
+	return file_cns_grpc_proto_server_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *PortMapping) GetHostPort() int32 {
+	if x != nil {
+		return x.HostPort
+	}
+	return 0
+}
+
+func (x *PortMapping) GetContainerPort() int32 {
+	if x != nil {
+		return x.ContainerPort
+	}
+	return 0
+}
+
+func (x *PortMapping) GetProtocol() string {
+	if x != nil {
+		return x.Protocol
+	}
+	return ""
+}
+
+func (x *PortMapping) GetHostIP() string {
+	if x != nil {
+		return x.HostIP
+	}
+	return ""
+}
+
+// Bandwidth limits the traffic to and from an endpoint interface.
+type Bandwidth struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	IngressRate  uint64 `protobuf:"varint,1,opt,name=ingressRate,proto3" json:"ingressRate,omitempty"`   // The rate of the traffic to the interface in bits per second, 0 for unshaped.
+	IngressBurst uint64 `protobuf:"varint,2,opt,name=ingressBurst,proto3" json:"ingressBurst,omitempty"` // The burst of the traffic to the interface in bits.
+	EgressRate   uint64 `protobuf:"varint,3,opt,name=egressRate,proto3" json:"egressRate,omitempty"`     // The rate of the traffic from the interface in bits per second, 0 for unshaped.
+	EgressBurst  uint64 `protobuf:"varint,4,opt,name=egressBurst,proto3" json:"egressBurst,omitempty"`   // The burst of the traffic from the interface in bits.
+}
+
+func (x *Bandwidth) Reset() {
+	*x = Bandwidth{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cns_grpc_proto_server_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Bandwidth) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Bandwidth) ProtoMessage() {}
+
+func (x *Bandwidth) ProtoReflect() protoreflect.Message {
+	mi := &file_cns_grpc_proto_server_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Bandwidth.ProtoReflect.Descriptor instead.
+func (*Bandwidth) Descriptor() ([]byte, []int) {
+	return file_cns_grpc_proto_server_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *Bandwidth) GetIngressRate() uint64 {
+	if x != nil {
+		return x.IngressRate
+	}
+	return 0
+}
+
+func (x *Bandwidth) GetIngressBurst() uint64 {
+	if x != nil {
+		return x.IngressBurst
+	}
+	return 0
+}
+
+func (x *Bandwidth) GetEgressRate() uint64 {
+	if x != nil {
+		return x.EgressRate
+	}
+	return 0
+}
+
+func (x *Bandwidth) GetEgressBurst() uint64 {
+	if x != nil {
+		return x.EgressBurst
+	}
+	return 0
+}
+
+// EndpointInfo is the endpoint state of a pod.
+type EndpointInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PodName       string             `protobuf:"bytes,1,opt,name=podName,proto3" json:"podName,omitempty"`                                                                                                     // The pod name.
+	PodNamespace  string             `protobuf:"bytes,2,opt,name=podNamespace,proto3" json:"podNamespace,omitempty"`                                                                                           // The pod namespace.
+	IfnameToIPMap map[string]*IPInfo `protobuf:"bytes,3,rep,name=ifnameToIPMap,proto3" json:"ifnameToIPMap,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"` // The state of each interface, keyed by interface name.
+}
+
+func (x *EndpointInfo) Reset() {
+	*x = EndpointInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cns_grpc_proto_server_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EndpointInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EndpointInfo) ProtoMessage() {}
+
+func (x *EndpointInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_cns_grpc_proto_server_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EndpointInfo.ProtoReflect.Descriptor instead.
+func (*EndpointInfo) Descriptor() ([]byte, []int) {
+	return file_cns_grpc_proto_server_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *EndpointInfo) GetPodName() string {
+	if x != nil {
+		return x.PodName
+	}
+	return ""
+}
+
+func (x *EndpointInfo) GetPodNamespace() string {
+	if x != nil {
+		return x.PodNamespace
+	}
+	return ""
+}
+
+func (x *EndpointInfo) GetIfnameToIPMap() map[string]*IPInfo {
+	if x != nil {
+		return x.IfnameToIPMap
+	}
+	return nil
+}
+
+// GetEndpointRequest is the request message for retrieving the endpoint state of a pod.
+type GetEndpointRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	EndpointID string `protobuf:"bytes,1,opt,name=endpointID,proto3" json:"endpointID,omitempty"` // The pod infra container ID.
+}
+
+func (x *GetEndpointRequest) Reset() {
+	*x = GetEndpointRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cns_grpc_proto_server_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetEndpointRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetEndpointRequest) ProtoMessage() {}
+
+func (x *GetEndpointRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cns_grpc_proto_server_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetEndpointRequest.ProtoReflect.Descriptor instead.
+func (*GetEndpointRequest) Descriptor() ([]byte, []int) {
+	return file_cns_grpc_proto_server_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *GetEndpointRequest) GetEndpointID() string {
+	if x != nil {
+		return x.EndpointID
+	}
+	return ""
+}
+
+// GetEndpointResponse is the response message containing the endpoint state of a pod.
+type GetEndpointResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Response     *Response     `protobuf:"bytes,1,opt,name=response,proto3" json:"response,omitempty"`         // The result of the operation.
+	EndpointInfo *EndpointInfo `protobuf:"bytes,2,opt,name=endpointInfo,proto3" json:"endpointInfo,omitempty"` // The endpoint state.
+}
+
+func (x *GetEndpointResponse) Reset() {
+	*x = GetEndpointResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cns_grpc_proto_server_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetEndpointResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetEndpointResponse) ProtoMessage() {}
+
+func (x *GetEndpointResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_cns_grpc_proto_server_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetEndpointResponse.ProtoReflect.Descriptor instead.
+func (*GetEndpointResponse) Descriptor() ([]byte, []int) {
+	return file_cns_grpc_proto_server_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *GetEndpointResponse) GetResponse() *Response {
+	if x != nil {
+		return x.Response
+	}
+	return nil
+}
+
+func (x *GetEndpointResponse) GetEndpointInfo() *EndpointInfo {
+	if x != nil {
+		return x.EndpointInfo
+	}
+	return nil
+}
+
+// UpdateEndpointRequest is the request message for updating the endpoint state of a pod.
+type UpdateEndpointRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	EndpointID string             `protobuf:"bytes,1,opt,name=endpointID,proto3" json:"endpointID,omitempty"`                                                                                 // The pod infra container ID.
+	IpInfo     map[string]*IPInfo `protobuf:"bytes,2,rep,name=ipInfo,proto3" json:"ipInfo,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"` // The interface state to update, keyed by interface name.
+}
+
+func (x *UpdateEndpointRequest) Reset() {
+	*x = UpdateEndpointRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cns_grpc_proto_server_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateEndpointRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateEndpointRequest) ProtoMessage() {}
+
+func (x *UpdateEndpointRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cns_grpc_proto_server_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateEndpointRequest.ProtoReflect.Descriptor instead.
+func (*UpdateEndpointRequest) Descriptor() ([]byte, []int) {
+	return file_cns_grpc_proto_server_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *UpdateEndpointRequest) GetEndpointID() string {
+	if x != nil {
+		return x.EndpointID
+	}
+	return ""
+}
+
+func (x *UpdateEndpointRequest) GetIpInfo() map[string]*IPInfo {
+	if x != nil {
+		return x.IpInfo
+	}
+	return nil
+}
+
+// WatchEndpointsRequest is the request message for streaming endpoint state changes.
+type WatchEndpointsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *WatchEndpointsRequest) Reset() {
+	*x = WatchEndpointsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cns_grpc_proto_server_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WatchEndpointsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchEndpointsRequest) ProtoMessage() {}
+
+func (x *WatchEndpointsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cns_grpc_proto_server_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchEndpointsRequest.ProtoReflect.Descriptor instead.
+func (*WatchEndpointsRequest) Descriptor() ([]byte, []int) {
+	return file_cns_grpc_proto_server_proto_rawDescGZIP(), []int{28}
+}
+
+// EndpointEvent is a change to the endpoint state.
+type EndpointEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type         EndpointEventType `protobuf:"varint,1,opt,name=type,proto3,enum=cns.EndpointEventType" json:"type,omitempty"` // The kind of change.
+	EndpointID   string            `protobuf:"bytes,2,opt,name=endpointID,proto3" json:"endpointID,omitempty"`                 // The pod infra container ID.
+	EndpointInfo *EndpointInfo     `protobuf:"bytes,3,opt,name=endpointInfo,proto3" json:"endpointInfo,omitempty"`             // The endpoint state after the change, the last known state on delete.
+}
+
+func (x *EndpointEvent) Reset() {
+	*x = EndpointEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cns_grpc_proto_server_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EndpointEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EndpointEvent) ProtoMessage() {}
+
+func (x *EndpointEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_cns_grpc_proto_server_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EndpointEvent.ProtoReflect.Descriptor instead.
+func (*EndpointEvent) Descriptor() ([]byte, []int) {
+	return file_cns_grpc_proto_server_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *EndpointEvent) GetType() EndpointEventType {
+	if x != nil {
+		return x.Type
+	}
+	return EndpointEventType_ENDPOINT_EVENT_TYPE_UNSPECIFIED
+}
+
+func (x *EndpointEvent) GetEndpointID() string {
+	if x != nil {
+		return x.EndpointID
+	}
+	return ""
+}
+
+func (x *EndpointEvent) GetEndpointInfo() *EndpointInfo {
+	if x != nil {
+		return x.EndpointInfo
+	}
+	return nil
+}
+
+var File_cns_grpc_proto_server_proto protoreflect.FileDescriptor
+
+var file_cns_grpc_proto_server_proto_rawDesc = []byte{
+	0x0a, 0x1b, 0x63, 0x6e, 0x73, 0x2f, 0x67, 0x72, 0x70, 0x63, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2f, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x03, 0x63,
+	0x6e, 0x73, 0x22, 0x8a, 0x01, 0x0a, 0x1a, 0x53, 0x65, 0x74, 0x4f, 0x72, 0x63, 0x68, 0x65, 0x73,
+	0x74, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x28, 0x0a, 0x0f, 0x64, 0x6e, 0x63, 0x50, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f,
+	0x6e, 0x4b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x64, 0x6e, 0x63, 0x50,
+	0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x4b, 0x65, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x6e,
+	0x6f, 0x64, 0x65, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6e, 0x6f, 0x64,
+	0x65, 0x49, 0x44, 0x12, 0x2a, 0x0a, 0x10, 0x6f, 0x72, 0x63, 0x68, 0x65, 0x73, 0x74, 0x72, 0x61,
+	0x74, 0x6f, 0x72, 0x54, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x6f,
+	0x72, 0x63, 0x68, 0x65, 0x73, 0x74, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x54, 0x79, 0x70, 0x65, 0x22,
+	0x1d, 0x0a, 0x1b, 0x53, 0x65, 0x74, 0x4f, 0x72, 0x63, 0x68, 0x65, 0x73, 0x74, 0x72, 0x61, 0x74,
+	0x6f, 0x72, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x29,
+	0x0a, 0x0f, 0x4e, 0x6f, 0x64, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x16, 0x0a, 0x06, 0x6e, 0x6f, 0x64, 0x65, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x6e, 0x6f, 0x64, 0x65, 0x49, 0x44, 0x22, 0x9e, 0x01, 0x0a, 0x10, 0x4e, 0x6f,
+	0x64, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16,
+	0x0a, 0x06, 0x6e, 0x6f, 0x64, 0x65, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x6e, 0x6f, 0x64, 0x65, 0x49, 0x44, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x70,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x70, 0x12, 0x1c, 0x0a, 0x09, 0x69, 0x73,
+	0x48, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x69,
+	0x73, 0x48, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x44, 0x0a, 0x08, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x72, 0x65, 0x74, 0x75, 0x72, 0x6e,
+	0x43, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x72, 0x65, 0x74, 0x75,
+	0x72, 0x6e, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x22, 0x96, 0x03, 0x0a, 0x10, 0x49, 0x50, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2e, 0x0a, 0x12, 0x64, 0x65, 0x73, 0x69, 0x72, 0x65, 0x64,
+	0x49, 0x50, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x12, 0x64, 0x65, 0x73, 0x69, 0x72, 0x65, 0x64, 0x49, 0x50, 0x41, 0x64, 0x64, 0x72,
+	0x65, 0x73, 0x73, 0x65, 0x73, 0x12, 0x26, 0x0a, 0x0e, 0x70, 0x6f, 0x64, 0x49, 0x6e, 0x74, 0x65,
+	0x72, 0x66, 0x61, 0x63, 0x65, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x70,
+	0x6f, 0x64, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x49, 0x44, 0x12, 0x2a, 0x0a,
+	0x10, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x49,
+	0x44, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x43, 0x6f,
+	0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x49, 0x44, 0x12, 0x30, 0x0a, 0x13, 0x6f, 0x72, 0x63,
+	0x68, 0x65, 0x73, 0x74, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x13, 0x6f, 0x72, 0x63, 0x68, 0x65, 0x73, 0x74, 0x72,
+	0x61, 0x74, 0x6f, 0x72, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x69,
+	0x66, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x69, 0x66, 0x6e,
+	0x61, 0x6d, 0x65, 0x12, 0x3a, 0x0a, 0x18, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x61, 0x72, 0x79,
+	0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x73, 0x45, 0x78, 0x69, 0x73, 0x74, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x18, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x61, 0x72, 0x79,
+	0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x73, 0x45, 0x78, 0x69, 0x73, 0x74, 0x12,
+	0x34, 0x0a, 0x15, 0x62, 0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66,
+	0x61, 0x63, 0x65, 0x45, 0x78, 0x69, 0x73, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x15,
+	0x62, 0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65,
+	0x45, 0x78, 0x69, 0x73, 0x74, 0x12, 0x42, 0x0a, 0x1c, 0x62, 0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64,
+	0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x4d, 0x61, 0x63, 0x41, 0x64, 0x64, 0x72,
+	0x65, 0x73, 0x73, 0x65, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x09, 0x52, 0x1c, 0x62, 0x61, 0x63,
+	0x6b, 0x65, 0x6e, 0x64, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x4d, 0x61, 0x63,
+	0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x22, 0x4c, 0x0a, 0x08, 0x49, 0x50, 0x53,
+	0x75, 0x62, 0x6e, 0x65, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x69, 0x70, 0x41, 0x64, 0x64, 0x72, 0x65,
+	0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x69, 0x70, 0x41, 0x64, 0x64, 0x72,
+	0x65, 0x73, 0x73, 0x12, 0x22, 0x0a, 0x0c, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x4c, 0x65, 0x6e,
+	0x67, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x70, 0x72, 0x65, 0x66, 0x69,
+	0x78, 0x4c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x22, 0x88, 0x01, 0x0a, 0x0f, 0x49, 0x50, 0x43, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x29, 0x0a, 0x08, 0x69,
+	0x70, 0x53, 0x75, 0x62, 0x6e, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e,
+	0x63, 0x6e, 0x73, 0x2e, 0x49, 0x50, 0x53, 0x75, 0x62, 0x6e, 0x65, 0x74, 0x52, 0x08, 0x69, 0x70,
+	0x53, 0x75, 0x62, 0x6e, 0x65, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x64, 0x6e, 0x73, 0x53, 0x65, 0x72,
+	0x76, 0x65, 0x72, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x64, 0x6e, 0x73, 0x53,
+	0x65, 0x72, 0x76, 0x65, 0x72, 0x73, 0x12, 0x2a, 0x0a, 0x10, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61,
+	0x79, 0x49, 0x50, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x10, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x49, 0x50, 0x41, 0x64, 0x64, 0x72, 0x65,
+	0x73, 0x73, 0x22, 0x5c, 0x0a, 0x0a, 0x48, 0x6f, 0x73, 0x74, 0x49, 0x50, 0x49, 0x6e, 0x66, 0x6f,
+	0x12, 0x18, 0x0a, 0x07, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x72,
+	0x69, 0x6d, 0x61, 0x72, 0x79, 0x49, 0x50, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x70,
+	0x72, 0x69, 0x6d, 0x61, 0x72, 0x79, 0x49, 0x50, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x75, 0x62, 0x6e,
+	0x65, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x75, 0x62, 0x6e, 0x65, 0x74,
+	0x22, 0x79, 0x0a, 0x05, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x69, 0x70, 0x41,
+	0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x69, 0x70,
+	0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x2a, 0x0a, 0x10, 0x67, 0x61, 0x74, 0x65, 0x77,
+	0x61, 0x79, 0x49, 0x50, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x10, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x49, 0x50, 0x41, 0x64, 0x64, 0x72,
+	0x65, 0x73, 0x73, 0x12, 0x26, 0x0a, 0x0e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65,
+	0x54, 0x6f, 0x55, 0x73, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x69, 0x6e, 0x74,
+	0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x54, 0x6f, 0x55, 0x73, 0x65, 0x22, 0xa3, 0x03, 0x0a, 0x09,
+	0x50, 0x6f, 0x64, 0x49, 0x70, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x2f, 0x0a, 0x0b, 0x70, 0x6f, 0x64,
+	0x49, 0x50, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d,
+	0x2e, 0x63, 0x6e, 0x73, 0x2e, 0x49, 0x50, 0x53, 0x75, 0x62, 0x6e, 0x65, 0x74, 0x52, 0x0b, 0x70,
+	0x6f, 0x64, 0x49, 0x50, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x5e, 0x0a, 0x1f, 0x6e, 0x65,
+	0x74, 0x77, 0x6f, 0x72, 0x6b, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x50, 0x72,
+	0x69, 0x6d, 0x61, 0x72, 0x79, 0x49, 0x50, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x63, 0x6e, 0x73, 0x2e, 0x49, 0x50, 0x43, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x1f, 0x6e, 0x65, 0x74, 0x77, 0x6f,
+	0x72, 0x6b, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x50, 0x72, 0x69, 0x6d, 0x61,
+	0x72, 0x79, 0x49, 0x50, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x3d, 0x0a, 0x11, 0x68, 0x6f,
+	0x73, 0x74, 0x50, 0x72, 0x69, 0x6d, 0x61, 0x72, 0x79, 0x49, 0x50, 0x49, 0x6e, 0x66, 0x6f, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x63, 0x6e, 0x73, 0x2e, 0x48, 0x6f, 0x73, 0x74,
+	0x49, 0x50, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x11, 0x68, 0x6f, 0x73, 0x74, 0x50, 0x72, 0x69, 0x6d,
+	0x61, 0x72, 0x79, 0x49, 0x50, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x18, 0x0a, 0x07, 0x6e, 0x69, 0x63,
+	0x54, 0x79, 0x70, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6e, 0x69, 0x63, 0x54,
+	0x79, 0x70, 0x65, 0x12, 0x24, 0x0a, 0x0d, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65,
+	0x4e, 0x61, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x69, 0x6e, 0x74, 0x65,
+	0x72, 0x66, 0x61, 0x63, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x6d, 0x61, 0x63,
+	0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6d,
+	0x61, 0x63, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x2c, 0x0a, 0x11, 0x73, 0x6b, 0x69,
+	0x70, 0x44, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x73, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x11, 0x73, 0x6b, 0x69, 0x70, 0x44, 0x65, 0x66, 0x61, 0x75, 0x6c,
+	0x74, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x73, 0x12, 0x22, 0x0a, 0x06, 0x72, 0x6f, 0x75, 0x74, 0x65,
+	0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x63, 0x6e, 0x73, 0x2e, 0x52, 0x6f,
+	0x75, 0x74, 0x65, 0x52, 0x06, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x70,
+	0x6e, 0x70, 0x49, 0x44, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x70, 0x6e, 0x70, 0x49,
+	0x44, 0x22, 0x9d, 0x01, 0x0a, 0x11, 0x49, 0x50, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x73, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2c, 0x0a, 0x09, 0x70, 0x6f, 0x64, 0x49, 0x50,
+	0x49, 0x6e, 0x66, 0x6f, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x63, 0x6e, 0x73,
+	0x2e, 0x50, 0x6f, 0x64, 0x49, 0x70, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x09, 0x70, 0x6f, 0x64, 0x49,
+	0x50, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x29, 0x0a, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x63, 0x6e, 0x73, 0x2e, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x2f, 0x0a, 0x0a, 0x65, 0x67, 0x72, 0x65, 0x73, 0x73, 0x53, 0x4e, 0x41, 0x54, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x63, 0x6e, 0x73, 0x2e, 0x45, 0x67, 0x72, 0x65, 0x73,
+	0x73, 0x53, 0x4e, 0x41, 0x54, 0x52, 0x0a, 0x65, 0x67, 0x72, 0x65, 0x73, 0x73, 0x53, 0x4e, 0x41,
+	0x54, 0x22, 0x56, 0x0a, 0x0a, 0x45, 0x67, 0x72, 0x65, 0x73, 0x73, 0x53, 0x4e, 0x41, 0x54, 0x12,
+	0x12, 0x0a, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6d,
+	0x6f, 0x64, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x69, 0x70, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x69, 0x70, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73,
+	0x73, 0x12, 0x16, 0x0a, 0x06, 0x65, 0x78, 0x63, 0x65, 0x70, 0x74, 0x18, 0x03, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x06, 0x65, 0x78, 0x63, 0x65, 0x70, 0x74, 0x22, 0x49, 0x0a, 0x15, 0x47, 0x65, 0x74,
+	0x49, 0x50, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x30, 0x0a, 0x13, 0x69, 0x70, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x53, 0x74,
+	0x61, 0x74, 0x65, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x13, 0x69, 0x70, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x53, 0x74, 0x61, 0x74, 0x65, 0x46, 0x69,
+	0x6c, 0x74, 0x65, 0x72, 0x22, 0xd5, 0x02, 0x0a, 0x15, 0x49, 0x50, 0x43, 0x6f, 0x6e, 0x66, 0x69,
+	0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x0e,
+	0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12,
+	0x0a, 0x04, 0x6e, 0x63, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x63,
+	0x49, 0x44, 0x12, 0x1c, 0x0a, 0x09, 0x69, 0x70, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x69, 0x70, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73,
+	0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x26, 0x0a, 0x0e, 0x70, 0x6f, 0x64, 0x49, 0x6e, 0x74,
+	0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x49, 0x44, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e,
+	0x70, 0x6f, 0x64, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x49, 0x44, 0x12, 0x2a,
+	0x0a, 0x10, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72,
+	0x49, 0x44, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x43,
+	0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x49, 0x44, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x6f,
+	0x64, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x70, 0x6f, 0x64,
+	0x4e, 0x61, 0x6d, 0x65, 0x12, 0x22, 0x0a, 0x0c, 0x70, 0x6f, 0x64, 0x4e, 0x61, 0x6d, 0x65, 0x73,
+	0x70, 0x61, 0x63, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x70, 0x6f, 0x64, 0x4e,
+	0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x12, 0x30, 0x0a, 0x13, 0x6c, 0x61, 0x73, 0x74,
+	0x53, 0x74, 0x61, 0x74, 0x65, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18,
+	0x09, 0x20, 0x01, 0x28, 0x03, 0x52, 0x13, 0x6c, 0x61, 0x73, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65,
+	0x54, 0x72, 0x61, 0x6e, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x20, 0x0a, 0x0b, 0x6c, 0x65,
+	0x61, 0x73, 0x65, 0x45, 0x78, 0x70, 0x69, 0x72, 0x79, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x0b, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x45, 0x78, 0x70, 0x69, 0x72, 0x79, 0x22, 0x95, 0x01, 0x0a,
+	0x16, 0x47, 0x65, 0x74, 0x49, 0x50, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x50, 0x0a, 0x15, 0x69, 0x70, 0x43, 0x6f, 0x6e,
+	0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x63, 0x6e, 0x73, 0x2e, 0x49, 0x50, 0x43,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x52, 0x15, 0x69, 0x70, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x29, 0x0a, 0x08, 0x72, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x63, 0x6e,
+	0x73, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x08, 0x72, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x7e, 0x0a, 0x1a, 0x47, 0x65, 0x74, 0x4e, 0x65, 0x74, 0x77, 0x6f,
+	0x72, 0x6b, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x2e, 0x0a, 0x12, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x43, 0x6f, 0x6e,
+	0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x12,
+	0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72,
+	0x49, 0x44, 0x12, 0x30, 0x0a, 0x13, 0x6f, 0x72, 0x63, 0x68, 0x65, 0x73, 0x74, 0x72, 0x61, 0x74,
+	0x6f, 0x72, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x13, 0x6f, 0x72, 0x63, 0x68, 0x65, 0x73, 0x74, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x43, 0x6f, 0x6e,
+	0x74, 0x65, 0x78, 0x74, 0x22, 0x40, 0x0a, 0x10, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x54, 0x65, 0x6e,
+	0x61, 0x6e, 0x63, 0x79, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x1c, 0x0a, 0x09, 0x65, 0x6e, 0x63, 0x61,
+	0x70, 0x54, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x65, 0x6e, 0x63,
+	0x61, 0x70, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x02, 0x69, 0x64, 0x22, 0x50, 0x0a, 0x14, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72,
+	0x6b, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x18,
+	0x0a, 0x07, 0x6e, 0x69, 0x63, 0x54, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x6e, 0x69, 0x63, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x6d, 0x61, 0x63, 0x41,
+	0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6d, 0x61,
+	0x63, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x22, 0xa8, 0x05, 0x0a, 0x10, 0x4e, 0x65, 0x74,
+	0x77, 0x6f, 0x72, 0x6b, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x12, 0x2e, 0x0a,
+	0x12, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65,
+	0x72, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x12, 0x6e, 0x65, 0x74, 0x77, 0x6f,
+	0x72, 0x6b, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x49, 0x44, 0x12, 0x3e, 0x0a,
+	0x0f, 0x69, 0x70, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x63, 0x6e, 0x73, 0x2e, 0x49, 0x50, 0x43,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0f, 0x69, 0x70,
+	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x22, 0x0a,
+	0x06, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0a, 0x2e,
+	0x63, 0x6e, 0x73, 0x2e, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x52, 0x06, 0x72, 0x6f, 0x75, 0x74, 0x65,
+	0x73, 0x12, 0x39, 0x0a, 0x10, 0x63, 0x6e, 0x65, 0x74, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73,
+	0x53, 0x70, 0x61, 0x63, 0x65, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x63, 0x6e,
+	0x73, 0x2e, 0x49, 0x50, 0x53, 0x75, 0x62, 0x6e, 0x65, 0x74, 0x52, 0x10, 0x63, 0x6e, 0x65, 0x74,
+	0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x53, 0x70, 0x61, 0x63, 0x65, 0x12, 0x41, 0x0a, 0x10,
+	0x6d, 0x75, 0x6c, 0x74, 0x69, 0x54, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x79, 0x49, 0x6e, 0x66, 0x6f,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x63, 0x6e, 0x73, 0x2e, 0x4d, 0x75, 0x6c,
+	0x74, 0x69, 0x54, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x79, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x10, 0x6d,
+	0x75, 0x6c, 0x74, 0x69, 0x54, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x79, 0x49, 0x6e, 0x66, 0x6f, 0x12,
+	0x3e, 0x0a, 0x1a, 0x70, 0x72, 0x69, 0x6d, 0x61, 0x72, 0x79, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66,
+	0x61, 0x63, 0x65, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x1a, 0x70, 0x72, 0x69, 0x6d, 0x61, 0x72, 0x79, 0x49, 0x6e, 0x74, 0x65,
+	0x72, 0x66, 0x61, 0x63, 0x65, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x12,
+	0x48, 0x0a, 0x14, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x49, 0x50, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e,
+	0x63, 0x6e, 0x73, 0x2e, 0x49, 0x50, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x52, 0x14, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x49, 0x50, 0x43, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x29, 0x0a, 0x08, 0x72, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x63, 0x6e,
+	0x73, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x08, 0x72, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3e, 0x0a, 0x1a, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x48, 0x6f, 0x73,
+	0x74, 0x54, 0x6f, 0x4e, 0x43, 0x43, 0x6f, 0x6d, 0x6d, 0x75, 0x6e, 0x69, 0x63, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x09, 0x20, 0x01, 0x28, 0x08, 0x52, 0x1a, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x48,
+	0x6f, 0x73, 0x74, 0x54, 0x6f, 0x4e, 0x43, 0x43, 0x6f, 0x6d, 0x6d, 0x75, 0x6e, 0x69, 0x63, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x3e, 0x0a, 0x1a, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x4e, 0x43, 0x54,
+	0x6f, 0x48, 0x6f, 0x73, 0x74, 0x43, 0x6f, 0x6d, 0x6d, 0x75, 0x6e, 0x69, 0x63, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x08, 0x52, 0x1a, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x4e,
+	0x43, 0x54, 0x6f, 0x48, 0x6f, 0x73, 0x74, 0x43, 0x6f, 0x6d, 0x6d, 0x75, 0x6e, 0x69, 0x63, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x4d, 0x0a, 0x14, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x49,
+	0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x18, 0x0b, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x19, 0x2e, 0x63, 0x6e, 0x73, 0x2e, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b,
+	0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x14, 0x6e,
+	0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x49,
+	0x6e, 0x66, 0x6f, 0x22, 0x91, 0x01, 0x0a, 0x1f, 0x47, 0x65, 0x74, 0x41, 0x6c, 0x6c, 0x4e, 0x65,
+	0x74, 0x77, 0x6f, 0x72, 0x6b, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x73, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x43, 0x0a, 0x11, 0x6e, 0x65, 0x74, 0x77, 0x6f,
+	0x72, 0x6b, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x15, 0x2e, 0x63, 0x6e, 0x73, 0x2e, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b,
+	0x43, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x52, 0x11, 0x6e, 0x65, 0x74, 0x77, 0x6f,
+	0x72, 0x6b, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x73, 0x12, 0x29, 0x0a, 0x08,
+	0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d,
+	0x2e, 0x63, 0x6e, 0x73, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x08, 0x72,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0xb3, 0x04, 0x0a, 0x06, 0x49, 0x50, 0x49, 0x6e,
+	0x66, 0x6f, 0x12, 0x12, 0x0a, 0x04, 0x69, 0x70, 0x76, 0x34, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x04, 0x69, 0x70, 0x76, 0x34, 0x12, 0x12, 0x0a, 0x04, 0x69, 0x70, 0x76, 0x36, 0x18, 0x02,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x69, 0x70, 0x76, 0x36, 0x12, 0x24, 0x0a, 0x0d, 0x68, 0x6e,
+	0x73, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x49, 0x44, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0d, 0x68, 0x6e, 0x73, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x49, 0x44,
+	0x12, 0x22, 0x0a, 0x0c, 0x68, 0x6e, 0x73, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x49, 0x44,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x68, 0x6e, 0x73, 0x4e, 0x65, 0x74, 0x77, 0x6f,
+	0x72, 0x6b, 0x49, 0x44, 0x12, 0x22, 0x0a, 0x0c, 0x68, 0x6f, 0x73, 0x74, 0x56, 0x65, 0x74, 0x68,
+	0x4e, 0x61, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x68, 0x6f, 0x73, 0x74,
+	0x56, 0x65, 0x74, 0x68, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x6d, 0x61, 0x63, 0x41,
+	0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6d, 0x61,
+	0x63, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x6e, 0x69, 0x63, 0x54,
+	0x79, 0x70, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6e, 0x69, 0x63, 0x54, 0x79,
+	0x70, 0x65, 0x12, 0x34, 0x0a, 0x0c, 0x70, 0x6f, 0x72, 0x74, 0x4d, 0x61, 0x70, 0x70, 0x69, 0x6e,
+	0x67, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x63, 0x6e, 0x73, 0x2e, 0x50,
+	0x6f, 0x72, 0x74, 0x4d, 0x61, 0x70, 0x70, 0x69, 0x6e, 0x67, 0x52, 0x0c, 0x70, 0x6f, 0x72, 0x74,
+	0x4d, 0x61, 0x70, 0x70, 0x69, 0x6e, 0x67, 0x73, 0x12, 0x2c, 0x0a, 0x09, 0x62, 0x61, 0x6e, 0x64,
+	0x77, 0x69, 0x64, 0x74, 0x68, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x63, 0x6e,
+	0x73, 0x2e, 0x42, 0x61, 0x6e, 0x64, 0x77, 0x69, 0x64, 0x74, 0x68, 0x52, 0x09, 0x62, 0x61, 0x6e,
+	0x64, 0x77, 0x69, 0x64, 0x74, 0x68, 0x12, 0x20, 0x0a, 0x0b, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72,
+	0x6b, 0x4d, 0x6f, 0x64, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x6e, 0x65, 0x74,
+	0x77, 0x6f, 0x72, 0x6b, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x2f, 0x0a, 0x0a, 0x65, 0x67, 0x72, 0x65,
+	0x73, 0x73, 0x53, 0x4e, 0x41, 0x54, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x63,
+	0x6e, 0x73, 0x2e, 0x45, 0x67, 0x72, 0x65, 0x73, 0x73, 0x53, 0x4e, 0x41, 0x54, 0x52, 0x0a, 0x65,
+	0x67, 0x72, 0x65, 0x73, 0x73, 0x53, 0x4e, 0x41, 0x54, 0x12, 0x1c, 0x0a, 0x09, 0x6e, 0x65, 0x74,
+	0x77, 0x6f, 0x72, 0x6b, 0x49, 0x44, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6e, 0x65,
+	0x74, 0x77, 0x6f, 0x72, 0x6b, 0x49, 0x44, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x64, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65,
+	0x64, 0x12, 0x2e, 0x0a, 0x12, 0x70, 0x6f, 0x72, 0x74, 0x46, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64,
+	0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x09, 0x52, 0x12, 0x70,
+	0x6f, 0x72, 0x74, 0x46, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73,
+	0x73, 0x12, 0x3a, 0x0a, 0x18, 0x70, 0x6f, 0x72, 0x74, 0x46, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64,
+	0x4d, 0x61, 0x73, 0x71, 0x75, 0x65, 0x72, 0x61, 0x64, 0x65, 0x41, 0x6c, 0x6c, 0x18, 0x0f, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x18, 0x70, 0x6f, 0x72, 0x74, 0x46, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64,
+	0x4d, 0x61, 0x73, 0x71, 0x75, 0x65, 0x72, 0x61, 0x64, 0x65, 0x41, 0x6c, 0x6c, 0x22, 0x83, 0x01,
+	0x0a, 0x0b, 0x50, 0x6f, 0x72, 0x74, 0x4d, 0x61, 0x70, 0x70, 0x69, 0x6e, 0x67, 0x12, 0x1a, 0x0a,
+	0x08, 0x68, 0x6f, 0x73, 0x74, 0x50, 0x6f, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x08, 0x68, 0x6f, 0x73, 0x74, 0x50, 0x6f, 0x72, 0x74, 0x12, 0x24, 0x0a, 0x0d, 0x63, 0x6f, 0x6e,
+	0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x50, 0x6f, 0x72, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x0d, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x50, 0x6f, 0x72, 0x74, 0x12,
+	0x1a, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x12, 0x16, 0x0a, 0x06, 0x68,
+	0x6f, 0x73, 0x74, 0x49, 0x50, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x68, 0x6f, 0x73,
+	0x74, 0x49, 0x50, 0x22, 0x93, 0x01, 0x0a, 0x09, 0x42, 0x61, 0x6e, 0x64, 0x77, 0x69, 0x64, 0x74,
+	0x68, 0x12, 0x20, 0x0a, 0x0b, 0x69, 0x6e, 0x67, 0x72, 0x65, 0x73, 0x73, 0x52, 0x61, 0x74, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x69, 0x6e, 0x67, 0x72, 0x65, 0x73, 0x73, 0x52,
+	0x61, 0x74, 0x65, 0x12, 0x22, 0x0a, 0x0c, 0x69, 0x6e, 0x67, 0x72, 0x65, 0x73, 0x73, 0x42, 0x75,
+	0x72, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0c, 0x69, 0x6e, 0x67, 0x72, 0x65,
+	0x73, 0x73, 0x42, 0x75, 0x72, 0x73, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x65, 0x67, 0x72, 0x65, 0x73,
+	0x73, 0x52, 0x61, 0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x65, 0x67, 0x72,
+	0x65, 0x73, 0x73, 0x52, 0x61, 0x74, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x65, 0x67, 0x72, 0x65, 0x73,
+	0x73, 0x42, 0x75, 0x72, 0x73, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x65, 0x67,
+	0x72, 0x65, 0x73, 0x73, 0x42, 0x75, 0x72, 0x73, 0x74, 0x22, 0xe7, 0x01, 0x0a, 0x0c, 0x45, 0x6e,
+	0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x6f,
+	0x64, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x70, 0x6f, 0x64,
+	0x4e, 0x61, 0x6d, 0x65, 0x12, 0x22, 0x0a, 0x0c, 0x70, 0x6f, 0x64, 0x4e, 0x61, 0x6d, 0x65, 0x73,
+	0x70, 0x61, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x70, 0x6f, 0x64, 0x4e,
+	0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x12, 0x4a, 0x0a, 0x0d, 0x69, 0x66, 0x6e, 0x61,
+	0x6d, 0x65, 0x54, 0x6f, 0x49, 0x50, 0x4d, 0x61, 0x70, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x24, 0x2e, 0x63, 0x6e, 0x73, 0x2e, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x49, 0x6e,
+	0x66, 0x6f, 0x2e, 0x49, 0x66, 0x6e, 0x61, 0x6d, 0x65, 0x54, 0x6f, 0x49, 0x50, 0x4d, 0x61, 0x70,
+	0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0d, 0x69, 0x66, 0x6e, 0x61, 0x6d, 0x65, 0x54, 0x6f, 0x49,
+	0x50, 0x4d, 0x61, 0x70, 0x1a, 0x4d, 0x0a, 0x12, 0x49, 0x66, 0x6e, 0x61, 0x6d, 0x65, 0x54, 0x6f,
+	0x49, 0x50, 0x4d, 0x61, 0x70, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65,
+	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x21, 0x0a, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x63, 0x6e,
+	0x73, 0x2e, 0x49, 0x50, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a,
+	0x02, 0x38, 0x01, 0x22, 0x34, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69,
+	0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x65, 0x6e, 0x64,
+	0x70, 0x6f, 0x69, 0x6e, 0x74, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x65,
+	0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x49, 0x44, 0x22, 0x77, 0x0a, 0x13, 0x47, 0x65, 0x74,
+	0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x29, 0x0a, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x63, 0x6e, 0x73, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x52, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x35, 0x0a, 0x0c, 0x65,
+	0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x11, 0x2e, 0x63, 0x6e, 0x73, 0x2e, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74,
+	0x49, 0x6e, 0x66, 0x6f, 0x52, 0x0c, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x49, 0x6e,
+	0x66, 0x6f, 0x22, 0xbf, 0x01, 0x0a, 0x15, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x45, 0x6e, 0x64,
+	0x70, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1e, 0x0a, 0x0a,
+	0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0a, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x49, 0x44, 0x12, 0x3e, 0x0a, 0x06,
+	0x69, 0x70, 0x49, 0x6e, 0x66, 0x6f, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x63,
+	0x6e, 0x73, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e,
+	0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x49, 0x70, 0x49, 0x6e, 0x66, 0x6f, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x69, 0x70, 0x49, 0x6e, 0x66, 0x6f, 0x1a, 0x46, 0x0a, 0x0b,
+	0x49, 0x70, 0x49, 0x6e, 0x66, 0x6f, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b,
+	0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x21, 0x0a,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x63,
+	0x6e, 0x73, 0x2e, 0x49, 0x50, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x3a, 0x02, 0x38, 0x01, 0x22, 0x17, 0x0a, 0x15, 0x57, 0x61, 0x74, 0x63, 0x68, 0x45, 0x6e, 0x64,
+	0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x92, 0x01,
+	0x0a, 0x0d, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12,
+	0x2a, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x16, 0x2e,
+	0x63, 0x6e, 0x73, 0x2e, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x45, 0x76, 0x65, 0x6e,
+	0x74, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x65,
+	0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0a, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x49, 0x44, 0x12, 0x35, 0x0a, 0x0c, 0x65,
+	0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x11, 0x2e, 0x63, 0x6e, 0x73, 0x2e, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74,
+	0x49, 0x6e, 0x66, 0x6f, 0x52, 0x0c, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x49, 0x6e,
+	0x66, 0x6f, 0x2a, 0x99, 0x01, 0x0a, 0x11, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x23, 0x0a, 0x1f, 0x45, 0x4e, 0x44, 0x50,
+	0x4f, 0x49, 0x4e, 0x54, 0x5f, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f,
+	0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x1d, 0x0a,
+	0x19, 0x45, 0x4e, 0x44, 0x50, 0x4f, 0x49, 0x4e, 0x54, 0x5f, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x5f,
+	0x54, 0x59, 0x50, 0x45, 0x5f, 0x41, 0x44, 0x44, 0x45, 0x44, 0x10, 0x01, 0x12, 0x1f, 0x0a, 0x1b,
+	0x45, 0x4e, 0x44, 0x50, 0x4f, 0x49, 0x4e, 0x54, 0x5f, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x5f, 0x54,
+	0x59, 0x50, 0x45, 0x5f, 0x55, 0x50, 0x44, 0x41, 0x54, 0x45, 0x44, 0x10, 0x02, 0x12, 0x1f, 0x0a,
+	0x1b, 0x45, 0x4e, 0x44, 0x50, 0x4f, 0x49, 0x4e, 0x54, 0x5f, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x5f,
+	0x54, 0x59, 0x50, 0x45, 0x5f, 0x44, 0x45, 0x4c, 0x45, 0x54, 0x45, 0x44, 0x10, 0x03, 0x32, 0xe6,
+	0x06, 0x0a, 0x03, 0x43, 0x4e, 0x53, 0x12, 0x58, 0x0a, 0x13, 0x53, 0x65, 0x74, 0x4f, 0x72, 0x63,
+	0x68, 0x65, 0x73, 0x74, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x1f, 0x2e,
+	0x63, 0x6e, 0x73, 0x2e, 0x53, 0x65, 0x74, 0x4f, 0x72, 0x63, 0x68, 0x65, 0x73, 0x74, 0x72, 0x61,
+	0x74, 0x6f, 0x72, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20,
+	0x2e, 0x63, 0x6e, 0x73, 0x2e, 0x53, 0x65, 0x74, 0x4f, 0x72, 0x63, 0x68, 0x65, 0x73, 0x74, 0x72,
+	0x61, 0x74, 0x6f, 0x72, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x3a, 0x0a, 0x0b, 0x47, 0x65, 0x74, 0x4e, 0x6f, 0x64, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x12,
+	0x14, 0x2e, 0x63, 0x6e, 0x73, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x63, 0x6e, 0x73, 0x2e, 0x4e, 0x6f, 0x64, 0x65,
+	0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x41, 0x0a, 0x10,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x50, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x73,
+	0x12, 0x15, 0x2e, 0x63, 0x6e, 0x73, 0x2e, 0x49, 0x50, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x63, 0x6e, 0x73, 0x2e, 0x49, 0x50,
+	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x41, 0x0a, 0x10, 0x52, 0x65, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x49, 0x50, 0x43, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x73, 0x12, 0x15, 0x2e, 0x63, 0x6e, 0x73, 0x2e, 0x49, 0x50, 0x43, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x63, 0x6e, 0x73,
+	0x2e, 0x49, 0x50, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x35, 0x0a, 0x0d, 0x52, 0x65, 0x6e, 0x65, 0x77, 0x49, 0x50, 0x4c, 0x65, 0x61,
+	0x73, 0x65, 0x73, 0x12, 0x15, 0x2e, 0x63, 0x6e, 0x73, 0x2e, 0x49, 0x50, 0x43, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0d, 0x2e, 0x63, 0x6e, 0x73,
+	0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x49, 0x0a, 0x0e, 0x47, 0x65, 0x74,
+	0x49, 0x50, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x12, 0x1a, 0x2e, 0x63, 0x6e,
+	0x73, 0x2e, 0x47, 0x65, 0x74, 0x49, 0x50, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x63, 0x6e, 0x73, 0x2e, 0x47, 0x65,
+	0x74, 0x49, 0x50, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4d, 0x0a, 0x13, 0x47, 0x65, 0x74, 0x4e, 0x65, 0x74, 0x77, 0x6f,
+	0x72, 0x6b, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x12, 0x1f, 0x2e, 0x63, 0x6e,
+	0x73, 0x2e, 0x47, 0x65, 0x74, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x43, 0x6f, 0x6e, 0x74,
+	0x61, 0x69, 0x6e, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x63,
+	0x6e, 0x73, 0x2e, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x69,
+	0x6e, 0x65, 0x72, 0x12, 0x60, 0x0a, 0x17, 0x47, 0x65, 0x74, 0x41, 0x6c, 0x6c, 0x4e, 0x65, 0x74,
+	0x77, 0x6f, 0x72, 0x6b, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x73, 0x12, 0x1f,
+	0x2e, 0x63, 0x6e, 0x73, 0x2e, 0x47, 0x65, 0x74, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x43,
+	0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x24, 0x2e, 0x63, 0x6e, 0x73, 0x2e, 0x47, 0x65, 0x74, 0x41, 0x6c, 0x6c, 0x4e, 0x65, 0x74, 0x77,
+	0x6f, 0x72, 0x6b, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x40, 0x0a, 0x0b, 0x47, 0x65, 0x74, 0x45, 0x6e, 0x64, 0x70,
+	0x6f, 0x69, 0x6e, 0x74, 0x12, 0x17, 0x2e, 0x63, 0x6e, 0x73, 0x2e, 0x47, 0x65, 0x74, 0x45, 0x6e,
+	0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e,
+	0x63, 0x6e, 0x73, 0x2e, 0x47, 0x65, 0x74, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3b, 0x0a, 0x0e, 0x55, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x1a, 0x2e, 0x63, 0x6e, 0x73, 0x2e,
+	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0d, 0x2e, 0x63, 0x6e, 0x73, 0x2e, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4d, 0x0a, 0x10, 0x57, 0x61, 0x74, 0x63, 0x68, 0x49, 0x50, 0x50,
+	0x6f, 0x6f, 0x6c, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x1a, 0x2e, 0x63, 0x6e, 0x73, 0x2e, 0x47,
+	0x65, 0x74, 0x49, 0x50, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x63, 0x6e, 0x73, 0x2e, 0x47, 0x65, 0x74, 0x49, 0x50,
+	0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x30, 0x01, 0x12, 0x42, 0x0a, 0x0e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x45, 0x6e, 0x64, 0x70,
+	0x6f, 0x69, 0x6e, 0x74, 0x73, 0x12, 0x1a, 0x2e, 0x63, 0x6e, 0x73, 0x2e, 0x57, 0x61, 0x74, 0x63,
+	0x68, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x12, 0x2e, 0x63, 0x6e, 0x73, 0x2e, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x30, 0x01, 0x42, 0x12, 0x5a, 0x10, 0x63, 0x6e, 0x73, 0x2f, 0x67,
+	0x72, 0x70, 0x63, 0x2f, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x62, 0x06, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x33,
+}
+
+var (
+	file_cns_grpc_proto_server_proto_rawDescOnce sync.Once
+	file_cns_grpc_proto_server_proto_rawDescData = file_cns_grpc_proto_server_proto_rawDesc
+)
+
+func file_cns_grpc_proto_server_proto_rawDescGZIP() []byte {
+	file_cns_grpc_proto_server_proto_rawDescOnce.Do(func() {
+		file_cns_grpc_proto_server_proto_rawDescData = protoimpl.X.CompressGZIP(file_cns_grpc_proto_server_proto_rawDescData)
+	})
+	return file_cns_grpc_proto_server_proto_rawDescData
+}
+
+var file_cns_grpc_proto_server_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_cns_grpc_proto_server_proto_msgTypes = make([]protoimpl.MessageInfo, 32)
+var file_cns_grpc_proto_server_proto_goTypes = []any{
+	(EndpointEventType)(0),                  // 0: cns.EndpointEventType
+	(*SetOrchestratorInfoRequest)(nil),      // 1: cns.SetOrchestratorInfoRequest
+	(*SetOrchestratorInfoResponse)(nil),     // 2: cns.SetOrchestratorInfoResponse
+	(*NodeInfoRequest)(nil),                 // 3: cns.NodeInfoRequest
+	(*NodeInfoResponse)(nil),                // 4: cns.NodeInfoResponse
+	(*Response)(nil),                        // 5: cns.Response
+	(*IPConfigsRequest)(nil),                // 6: cns.IPConfigsRequest
+	(*IPSubnet)(nil),                        // 7: cns.IPSubnet
+	(*IPConfiguration)(nil),                 // 8: cns.IPConfiguration
+	(*HostIPInfo)(nil),                      // 9: cns.HostIPInfo
+	(*Route)(nil),                           // 10: cns.Route
+	(*PodIpInfo)(nil),                       // 11: cns.PodIpInfo
+	(*IPConfigsResponse)(nil),               // 12: cns.IPConfigsResponse
+	(*EgressSNAT)(nil),                      // 13: cns.EgressSNAT
+	(*GetIPAddressesRequest)(nil),           // 14: cns.GetIPAddressesRequest
+	(*IPConfigurationStatus)(nil),           // 15: cns.IPConfigurationStatus
+	(*GetIPAddressesResponse)(nil),          // 16: cns.GetIPAddressesResponse
+	(*GetNetworkContainerRequest)(nil),      // 17: cns.GetNetworkContainerRequest
+	(*MultiTenancyInfo)(nil),                // 18: cns.MultiTenancyInfo
+	(*NetworkInterfaceInfo)(nil),            // 19: cns.NetworkInterfaceInfo
+	(*NetworkContainer)(nil),                // 20: cns.NetworkContainer
+	(*GetAllNetworkContainersResponse)(nil), // 21: cns.GetAllNetworkContainersResponse
+	(*IPInfo)(nil),                          // 22: cns.IPInfo
+	(*PortMapping)(nil),                     // 23: cns.PortMapping
+	(*Bandwidth)(nil),                       // 24: cns.Bandwidth
+	(*EndpointInfo)(nil),                    // 25: cns.EndpointInfo
+	(*GetEndpointRequest)(nil),              // 26: cns.GetEndpointRequest
+	(*GetEndpointResponse)(nil),             // 27: cns.GetEndpointResponse
+	(*UpdateEndpointRequest)(nil),           // 28: cns.UpdateEndpointRequest
+	(*WatchEndpointsRequest)(nil),           // 29: cns.WatchEndpointsRequest
+	(*EndpointEvent)(nil),                   // 30: cns.EndpointEvent
+	nil,                                     // 31: cns.EndpointInfo.IfnameToIPMapEntry
+	nil,                                     // 32: cns.UpdateEndpointRequest.IpInfoEntry
+}
+var file_cns_grpc_proto_server_proto_depIdxs = []int32{
+	7,  // 0: cns.IPConfiguration.ipSubnet:type_name -> cns.IPSubnet
+	7,  // 1: cns.PodIpInfo.podIPConfig:type_name -> cns.IPSubnet
+	8,  // 2: cns.PodIpInfo.networkContainerPrimaryIPConfig:type_name -> cns.IPConfiguration
+	9,  // 3: cns.PodIpInfo.hostPrimaryIPInfo:type_name -> cns.HostIPInfo
+	10, // 4: cns.PodIpInfo.routes:type_name -> cns.Route
+	11, // 5: cns.IPConfigsResponse.podIPInfo:type_name -> cns.PodIpInfo
+	5,  // 6: cns.IPConfigsResponse.response:type_name -> cns.Response
+	13, // 7: cns.IPConfigsResponse.egressSNAT:type_name -> cns.EgressSNAT
+	15, // 8: cns.GetIPAddressesResponse.ipConfigurationStatus:type_name -> cns.IPConfigurationStatus
+	5,  // 9: cns.GetIPAddressesResponse.response:type_name -> cns.Response
+	8,  // 10: cns.NetworkContainer.ipConfiguration:type_name -> cns.IPConfiguration
+	10, // 11: cns.NetworkContainer.routes:type_name -> cns.Route
+	7,  // 12: cns.NetworkContainer.cnetAddressSpace:type_name -> cns.IPSubnet
+	18, // 13: cns.NetworkContainer.multiTenancyInfo:type_name -> cns.MultiTenancyInfo
+	8,  // 14: cns.NetworkContainer.localIPConfiguration:type_name -> cns.IPConfiguration
+	5,  // 15: cns.NetworkContainer.response:type_name -> cns.Response
+	19, // 16: cns.NetworkContainer.networkInterfaceInfo:type_name -> cns.NetworkInterfaceInfo
+	20, // 17: cns.GetAllNetworkContainersResponse.networkContainers:type_name -> cns.NetworkContainer
+	5,  // 18: cns.GetAllNetworkContainersResponse.response:type_name -> cns.Response
+	23, // 19: cns.IPInfo.portMappings:type_name -> cns.PortMapping
+	24, // 20: cns.IPInfo.bandwidth:type_name -> cns.Bandwidth
+	13, // 21: cns.IPInfo.egressSNAT:type_name -> cns.EgressSNAT
+	31, // 22: cns.EndpointInfo.ifnameToIPMap:type_name -> cns.EndpointInfo.IfnameToIPMapEntry
+	5,  // 23: cns.GetEndpointResponse.response:type_name -> cns.Response
+	25, // 24: cns.GetEndpointResponse.endpointInfo:type_name -> cns.EndpointInfo
+	32, // 25: cns.UpdateEndpointRequest.ipInfo:type_name -> cns.UpdateEndpointRequest.IpInfoEntry
+	0,  // 26: cns.EndpointEvent.type:type_name -> cns.EndpointEventType
+	25, // 27: cns.EndpointEvent.endpointInfo:type_name -> cns.EndpointInfo
+	22, // 28: cns.EndpointInfo.IfnameToIPMapEntry.value:type_name -> cns.IPInfo
+	22, // 29: cns.UpdateEndpointRequest.IpInfoEntry.value:type_name -> cns.IPInfo
+	1,  // 30: cns.CNS.SetOrchestratorInfo:input_type -> cns.SetOrchestratorInfoRequest
+	3,  // 31: cns.CNS.GetNodeInfo:input_type -> cns.NodeInfoRequest
+	6,  // 32: cns.CNS.RequestIPConfigs:input_type -> cns.IPConfigsRequest
+	6,  // 33: cns.CNS.ReleaseIPConfigs:input_type -> cns.IPConfigsRequest
+	6,  // 34: cns.CNS.RenewIPLeases:input_type -> cns.IPConfigsRequest
+	14, // 35: cns.CNS.GetIPAddresses:input_type -> cns.GetIPAddressesRequest
+	17, // 36: cns.CNS.GetNetworkContainer:input_type -> cns.GetNetworkContainerRequest
+	17, // 37: cns.CNS.GetAllNetworkContainers:input_type -> cns.GetNetworkContainerRequest
+	26, // 38: cns.CNS.GetEndpoint:input_type -> cns.GetEndpointRequest
+	28, // 39: cns.CNS.UpdateEndpoint:input_type -> cns.UpdateEndpointRequest
+	14, // 40: cns.CNS.WatchIPPoolState:input_type -> cns.GetIPAddressesRequest
+	29, // 41: cns.CNS.WatchEndpoints:input_type -> cns.WatchEndpointsRequest
+	2,  // 42: cns.CNS.SetOrchestratorInfo:output_type -> cns.SetOrchestratorInfoResponse
+	4,  // 43: cns.CNS.GetNodeInfo:output_type -> cns.NodeInfoResponse
+	12, // 44: cns.CNS.RequestIPConfigs:output_type -> cns.IPConfigsResponse
+	12, // 45: cns.CNS.ReleaseIPConfigs:output_type -> cns.IPConfigsResponse
+	5,  // 46: cns.CNS.RenewIPLeases:output_type -> cns.Response
+	16, // 47: cns.CNS.GetIPAddresses:output_type -> cns.GetIPAddressesResponse
+	20, // 48: cns.CNS.GetNetworkContainer:output_type -> cns.NetworkContainer
+	21, // 49: cns.CNS.GetAllNetworkContainers:output_type -> cns.GetAllNetworkContainersResponse
+	27, // 50: cns.CNS.GetEndpoint:output_type -> cns.GetEndpointResponse
+	5,  // 51: cns.CNS.UpdateEndpoint:output_type -> cns.Response
+	16, // 52: cns.CNS.WatchIPPoolState:output_type -> cns.GetIPAddressesResponse
+	30, // 53: cns.CNS.WatchEndpoints:output_type -> cns.EndpointEvent
+	42, // [42:54] is the sub-list for method output_type
+	30, // [30:42] is the sub-list for method input_type
+	30, // [30:30] is the sub-list for extension type_name
+	30, // [30:30] is the sub-list for extension extendee
+	0,  // [0:30] is the sub-list for field type_name
+}
+
+func init() { file_cns_grpc_proto_server_proto_init() }
+func file_cns_grpc_proto_server_proto_init() {
+	if File_cns_grpc_proto_server_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_cns_grpc_proto_server_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*SetOrchestratorInfoRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cns_grpc_proto_server_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*SetOrchestratorInfoResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cns_grpc_proto_server_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*NodeInfoRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cns_grpc_proto_server_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*NodeInfoResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cns_grpc_proto_server_proto_msgTypes[4].Exporter = func(v any, i int) any {
+			switch v := v.(*Response); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cns_grpc_proto_server_proto_msgTypes[5].Exporter = func(v any, i int) any {
+			switch v := v.(*IPConfigsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cns_grpc_proto_server_proto_msgTypes[6].Exporter = func(v any, i int) any {
+			switch v := v.(*IPSubnet); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cns_grpc_proto_server_proto_msgTypes[7].Exporter = func(v any, i int) any {
+			switch v := v.(*IPConfiguration); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cns_grpc_proto_server_proto_msgTypes[8].Exporter = func(v any, i int) any {
+			switch v := v.(*HostIPInfo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cns_grpc_proto_server_proto_msgTypes[9].Exporter = func(v any, i int) any {
+			switch v := v.(*Route); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cns_grpc_proto_server_proto_msgTypes[10].Exporter = func(v any, i int) any {
+			switch v := v.(*PodIpInfo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cns_grpc_proto_server_proto_msgTypes[11].Exporter = func(v any, i int) any {
+			switch v := v.(*IPConfigsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cns_grpc_proto_server_proto_msgTypes[12].Exporter = func(v any, i int) any {
+			switch v := v.(*EgressSNAT); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cns_grpc_proto_server_proto_msgTypes[13].Exporter = func(v any, i int) any {
+			switch v := v.(*GetIPAddressesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cns_grpc_proto_server_proto_msgTypes[14].Exporter = func(v any, i int) any {
+			switch v := v.(*IPConfigurationStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cns_grpc_proto_server_proto_msgTypes[15].Exporter = func(v any, i int) any {
+			switch v := v.(*GetIPAddressesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cns_grpc_proto_server_proto_msgTypes[16].Exporter = func(v any, i int) any {
+			switch v := v.(*GetNetworkContainerRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cns_grpc_proto_server_proto_msgTypes[17].Exporter = func(v any, i int) any {
+			switch v := v.(*MultiTenancyInfo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cns_grpc_proto_server_proto_msgTypes[18].Exporter = func(v any, i int) any {
+			switch v := v.(*NetworkInterfaceInfo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cns_grpc_proto_server_proto_msgTypes[19].Exporter = func(v any, i int) any {
+			switch v := v.(*NetworkContainer); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cns_grpc_proto_server_proto_msgTypes[20].Exporter = func(v any, i int) any {
+			switch v := v.(*GetAllNetworkContainersResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cns_grpc_proto_server_proto_msgTypes[21].Exporter = func(v any, i int) any {
+			switch v := v.(*IPInfo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cns_grpc_proto_server_proto_msgTypes[22].Exporter = func(v any, i int) any {
+			switch v := v.(*PortMapping); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cns_grpc_proto_server_proto_msgTypes[23].Exporter = func(v any, i int) any {
+			switch v := v.(*Bandwidth); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cns_grpc_proto_server_proto_msgTypes[24].Exporter = func(v any, i int) any {
+			switch v := v.(*EndpointInfo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cns_grpc_proto_server_proto_msgTypes[25].Exporter = func(v any, i int) any {
+			switch v := v.(*GetEndpointRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cns_grpc_proto_server_proto_msgTypes[26].Exporter = func(v any, i int) any {
+			switch v := v.(*GetEndpointResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cns_grpc_proto_server_proto_msgTypes[27].Exporter = func(v any, i int) any {
+			switch v := v.(*UpdateEndpointRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cns_grpc_proto_server_proto_msgTypes[28].Exporter = func(v any, i int) any {
+			switch v := v.(*WatchEndpointsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cns_grpc_proto_server_proto_msgTypes[29].Exporter = func(v any, i int) any {
+			switch v := v.(*EndpointEvent); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -393,13 +3053,14 @@ func file_cns_grpc_proto_server_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_cns_grpc_proto_server_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   4,
+			NumEnums:      1,
+			NumMessages:   32,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_cns_grpc_proto_server_proto_goTypes,
 		DependencyIndexes: file_cns_grpc_proto_server_proto_depIdxs,
+		EnumInfos:         file_cns_grpc_proto_server_proto_enumTypes,
 		MessageInfos:      file_cns_grpc_proto_server_proto_msgTypes,
 	}.Build()
 	File_cns_grpc_proto_server_proto = out.File
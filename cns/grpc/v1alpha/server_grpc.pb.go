@@ -19,8 +19,18 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	CNS_SetOrchestratorInfo_FullMethodName = "/cns.CNS/SetOrchestratorInfo"
-	CNS_GetNodeInfo_FullMethodName         = "/cns.CNS/GetNodeInfo"
+	CNS_SetOrchestratorInfo_FullMethodName     = "/cns.CNS/SetOrchestratorInfo"
+	CNS_GetNodeInfo_FullMethodName             = "/cns.CNS/GetNodeInfo"
+	CNS_RequestIPConfigs_FullMethodName        = "/cns.CNS/RequestIPConfigs"
+	CNS_ReleaseIPConfigs_FullMethodName        = "/cns.CNS/ReleaseIPConfigs"
+	CNS_RenewIPLeases_FullMethodName           = "/cns.CNS/RenewIPLeases"
+	CNS_GetIPAddresses_FullMethodName          = "/cns.CNS/GetIPAddresses"
+	CNS_GetNetworkContainer_FullMethodName     = "/cns.CNS/GetNetworkContainer"
+	CNS_GetAllNetworkContainers_FullMethodName = "/cns.CNS/GetAllNetworkContainers"
+	CNS_GetEndpoint_FullMethodName             = "/cns.CNS/GetEndpoint"
+	CNS_UpdateEndpoint_FullMethodName          = "/cns.CNS/UpdateEndpoint"
+	CNS_WatchIPPoolState_FullMethodName        = "/cns.CNS/WatchIPPoolState"
+	CNS_WatchEndpoints_FullMethodName          = "/cns.CNS/WatchEndpoints"
 )
 
 // CNSClient is the client API for CNS service.
@@ -32,6 +42,28 @@ type CNSClient interface {
 	// Retrieves detailed information about a specific node.
 	// Primarily used for health checks.
 	GetNodeInfo(ctx context.Context, in *NodeInfoRequest, opts ...grpc.CallOption) (*NodeInfoResponse, error)
+	// Assigns IPs to a pod, or returns the IPs already assigned to it.
+	RequestIPConfigs(ctx context.Context, in *IPConfigsRequest, opts ...grpc.CallOption) (*IPConfigsResponse, error)
+	// Releases the IPs assigned to a pod.
+	ReleaseIPConfigs(ctx context.Context, in *IPConfigsRequest, opts ...grpc.CallOption) (*IPConfigsResponse, error)
+	// Extends the leases on the IPs assigned to a pod.
+	RenewIPLeases(ctx context.Context, in *IPConfigsRequest, opts ...grpc.CallOption) (*Response, error)
+	// Retrieves the IPs in the pool which are in any of the requested states.
+	GetIPAddresses(ctx context.Context, in *GetIPAddressesRequest, opts ...grpc.CallOption) (*GetIPAddressesResponse, error)
+	// Retrieves the first network container matching an orchestrator context.
+	GetNetworkContainer(ctx context.Context, in *GetNetworkContainerRequest, opts ...grpc.CallOption) (*NetworkContainer, error)
+	// Retrieves all network containers matching an orchestrator context.
+	GetAllNetworkContainers(ctx context.Context, in *GetNetworkContainerRequest, opts ...grpc.CallOption) (*GetAllNetworkContainersResponse, error)
+	// Retrieves the endpoint state of a pod infra container.
+	GetEndpoint(ctx context.Context, in *GetEndpointRequest, opts ...grpc.CallOption) (*GetEndpointResponse, error)
+	// Updates the endpoint state of a pod infra container.
+	UpdateEndpoint(ctx context.Context, in *UpdateEndpointRequest, opts ...grpc.CallOption) (*Response, error)
+	// Streams the IPs in the pool which are in any of the requested states.
+	// A snapshot is sent when the stream opens and whenever the matching IPs change.
+	WatchIPPoolState(ctx context.Context, in *GetIPAddressesRequest, opts ...grpc.CallOption) (CNS_WatchIPPoolStateClient, error)
+	// Streams changes to the endpoint state.
+	// Every existing endpoint is sent as added when the stream opens.
+	WatchEndpoints(ctx context.Context, in *WatchEndpointsRequest, opts ...grpc.CallOption) (CNS_WatchEndpointsClient, error)
 }
 
 type cNSClient struct {
@@ -60,6 +92,142 @@ func (c *cNSClient) GetNodeInfo(ctx context.Context, in *NodeInfoRequest, opts .
 	return out, nil
 }
 
+func (c *cNSClient) RequestIPConfigs(ctx context.Context, in *IPConfigsRequest, opts ...grpc.CallOption) (*IPConfigsResponse, error) {
+	out := new(IPConfigsResponse)
+	err := c.cc.Invoke(ctx, CNS_RequestIPConfigs_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cNSClient) ReleaseIPConfigs(ctx context.Context, in *IPConfigsRequest, opts ...grpc.CallOption) (*IPConfigsResponse, error) {
+	out := new(IPConfigsResponse)
+	err := c.cc.Invoke(ctx, CNS_ReleaseIPConfigs_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cNSClient) RenewIPLeases(ctx context.Context, in *IPConfigsRequest, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, CNS_RenewIPLeases_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cNSClient) GetIPAddresses(ctx context.Context, in *GetIPAddressesRequest, opts ...grpc.CallOption) (*GetIPAddressesResponse, error) {
+	out := new(GetIPAddressesResponse)
+	err := c.cc.Invoke(ctx, CNS_GetIPAddresses_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cNSClient) GetNetworkContainer(ctx context.Context, in *GetNetworkContainerRequest, opts ...grpc.CallOption) (*NetworkContainer, error) {
+	out := new(NetworkContainer)
+	err := c.cc.Invoke(ctx, CNS_GetNetworkContainer_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cNSClient) GetAllNetworkContainers(ctx context.Context, in *GetNetworkContainerRequest, opts ...grpc.CallOption) (*GetAllNetworkContainersResponse, error) {
+	out := new(GetAllNetworkContainersResponse)
+	err := c.cc.Invoke(ctx, CNS_GetAllNetworkContainers_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cNSClient) GetEndpoint(ctx context.Context, in *GetEndpointRequest, opts ...grpc.CallOption) (*GetEndpointResponse, error) {
+	out := new(GetEndpointResponse)
+	err := c.cc.Invoke(ctx, CNS_GetEndpoint_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cNSClient) UpdateEndpoint(ctx context.Context, in *UpdateEndpointRequest, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, CNS_UpdateEndpoint_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cNSClient) WatchIPPoolState(ctx context.Context, in *GetIPAddressesRequest, opts ...grpc.CallOption) (CNS_WatchIPPoolStateClient, error) {
+	stream, err := c.cc.NewStream(ctx, &CNS_ServiceDesc.Streams[0], CNS_WatchIPPoolState_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &cNSWatchIPPoolStateClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type CNS_WatchIPPoolStateClient interface {
+	Recv() (*GetIPAddressesResponse, error)
+	grpc.ClientStream
+}
+
+type cNSWatchIPPoolStateClient struct {
+	grpc.ClientStream
+}
+
+func (x *cNSWatchIPPoolStateClient) Recv() (*GetIPAddressesResponse, error) {
+	m := new(GetIPAddressesResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *cNSClient) WatchEndpoints(ctx context.Context, in *WatchEndpointsRequest, opts ...grpc.CallOption) (CNS_WatchEndpointsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &CNS_ServiceDesc.Streams[1], CNS_WatchEndpoints_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &cNSWatchEndpointsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type CNS_WatchEndpointsClient interface {
+	Recv() (*EndpointEvent, error)
+	grpc.ClientStream
+}
+
+type cNSWatchEndpointsClient struct {
+	grpc.ClientStream
+}
+
+func (x *cNSWatchEndpointsClient) Recv() (*EndpointEvent, error) {
+	m := new(EndpointEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // CNSServer is the server API for CNS service.
 // All implementations must embed UnimplementedCNSServer
 // for forward compatibility
@@ -69,6 +237,28 @@ type CNSServer interface {
 	// Retrieves detailed information about a specific node.
 	// Primarily used for health checks.
 	GetNodeInfo(context.Context, *NodeInfoRequest) (*NodeInfoResponse, error)
+	// Assigns IPs to a pod, or returns the IPs already assigned to it.
+	RequestIPConfigs(context.Context, *IPConfigsRequest) (*IPConfigsResponse, error)
+	// Releases the IPs assigned to a pod.
+	ReleaseIPConfigs(context.Context, *IPConfigsRequest) (*IPConfigsResponse, error)
+	// Extends the leases on the IPs assigned to a pod.
+	RenewIPLeases(context.Context, *IPConfigsRequest) (*Response, error)
+	// Retrieves the IPs in the pool which are in any of the requested states.
+	GetIPAddresses(context.Context, *GetIPAddressesRequest) (*GetIPAddressesResponse, error)
+	// Retrieves the first network container matching an orchestrator context.
+	GetNetworkContainer(context.Context, *GetNetworkContainerRequest) (*NetworkContainer, error)
+	// Retrieves all network containers matching an orchestrator context.
+	GetAllNetworkContainers(context.Context, *GetNetworkContainerRequest) (*GetAllNetworkContainersResponse, error)
+	// Retrieves the endpoint state of a pod infra container.
+	GetEndpoint(context.Context, *GetEndpointRequest) (*GetEndpointResponse, error)
+	// Updates the endpoint state of a pod infra container.
+	UpdateEndpoint(context.Context, *UpdateEndpointRequest) (*Response, error)
+	// Streams the IPs in the pool which are in any of the requested states.
+	// A snapshot is sent when the stream opens and whenever the matching IPs change.
+	WatchIPPoolState(*GetIPAddressesRequest, CNS_WatchIPPoolStateServer) error
+	// Streams changes to the endpoint state.
+	// Every existing endpoint is sent as added when the stream opens.
+	WatchEndpoints(*WatchEndpointsRequest, CNS_WatchEndpointsServer) error
 	mustEmbedUnimplementedCNSServer()
 }
 
@@ -82,6 +272,36 @@ func (UnimplementedCNSServer) SetOrchestratorInfo(context.Context, *SetOrchestra
 func (UnimplementedCNSServer) GetNodeInfo(context.Context, *NodeInfoRequest) (*NodeInfoResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetNodeInfo not implemented")
 }
+func (UnimplementedCNSServer) RequestIPConfigs(context.Context, *IPConfigsRequest) (*IPConfigsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RequestIPConfigs not implemented")
+}
+func (UnimplementedCNSServer) ReleaseIPConfigs(context.Context, *IPConfigsRequest) (*IPConfigsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReleaseIPConfigs not implemented")
+}
+func (UnimplementedCNSServer) RenewIPLeases(context.Context, *IPConfigsRequest) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RenewIPLeases not implemented")
+}
+func (UnimplementedCNSServer) GetIPAddresses(context.Context, *GetIPAddressesRequest) (*GetIPAddressesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetIPAddresses not implemented")
+}
+func (UnimplementedCNSServer) GetNetworkContainer(context.Context, *GetNetworkContainerRequest) (*NetworkContainer, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNetworkContainer not implemented")
+}
+func (UnimplementedCNSServer) GetAllNetworkContainers(context.Context, *GetNetworkContainerRequest) (*GetAllNetworkContainersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAllNetworkContainers not implemented")
+}
+func (UnimplementedCNSServer) GetEndpoint(context.Context, *GetEndpointRequest) (*GetEndpointResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetEndpoint not implemented")
+}
+func (UnimplementedCNSServer) UpdateEndpoint(context.Context, *UpdateEndpointRequest) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateEndpoint not implemented")
+}
+func (UnimplementedCNSServer) WatchIPPoolState(*GetIPAddressesRequest, CNS_WatchIPPoolStateServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchIPPoolState not implemented")
+}
+func (UnimplementedCNSServer) WatchEndpoints(*WatchEndpointsRequest, CNS_WatchEndpointsServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchEndpoints not implemented")
+}
 func (UnimplementedCNSServer) mustEmbedUnimplementedCNSServer() {}
 
 // UnsafeCNSServer may be embedded to opt out of forward compatibility for this service.
@@ -131,6 +351,192 @@ func _CNS_GetNodeInfo_Handler(srv interface{}, ctx context.Context, dec func(int
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CNS_RequestIPConfigs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IPConfigsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CNSServer).RequestIPConfigs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CNS_RequestIPConfigs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CNSServer).RequestIPConfigs(ctx, req.(*IPConfigsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CNS_ReleaseIPConfigs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IPConfigsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CNSServer).ReleaseIPConfigs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CNS_ReleaseIPConfigs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CNSServer).ReleaseIPConfigs(ctx, req.(*IPConfigsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CNS_RenewIPLeases_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IPConfigsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CNSServer).RenewIPLeases(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CNS_RenewIPLeases_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CNSServer).RenewIPLeases(ctx, req.(*IPConfigsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CNS_GetIPAddresses_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetIPAddressesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CNSServer).GetIPAddresses(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CNS_GetIPAddresses_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CNSServer).GetIPAddresses(ctx, req.(*GetIPAddressesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CNS_GetNetworkContainer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetNetworkContainerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CNSServer).GetNetworkContainer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CNS_GetNetworkContainer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CNSServer).GetNetworkContainer(ctx, req.(*GetNetworkContainerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CNS_GetAllNetworkContainers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetNetworkContainerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CNSServer).GetAllNetworkContainers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CNS_GetAllNetworkContainers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CNSServer).GetAllNetworkContainers(ctx, req.(*GetNetworkContainerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CNS_GetEndpoint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetEndpointRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CNSServer).GetEndpoint(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CNS_GetEndpoint_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CNSServer).GetEndpoint(ctx, req.(*GetEndpointRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CNS_UpdateEndpoint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateEndpointRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CNSServer).UpdateEndpoint(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CNS_UpdateEndpoint_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CNSServer).UpdateEndpoint(ctx, req.(*UpdateEndpointRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CNS_WatchIPPoolState_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetIPAddressesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CNSServer).WatchIPPoolState(m, &cNSWatchIPPoolStateServer{stream})
+}
+
+type CNS_WatchIPPoolStateServer interface {
+	Send(*GetIPAddressesResponse) error
+	grpc.ServerStream
+}
+
+type cNSWatchIPPoolStateServer struct {
+	grpc.ServerStream
+}
+
+func (x *cNSWatchIPPoolStateServer) Send(m *GetIPAddressesResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _CNS_WatchEndpoints_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchEndpointsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CNSServer).WatchEndpoints(m, &cNSWatchEndpointsServer{stream})
+}
+
+type CNS_WatchEndpointsServer interface {
+	Send(*EndpointEvent) error
+	grpc.ServerStream
+}
+
+type cNSWatchEndpointsServer struct {
+	grpc.ServerStream
+}
+
+func (x *cNSWatchEndpointsServer) Send(m *EndpointEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // CNS_ServiceDesc is the grpc.ServiceDesc for CNS service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -146,7 +552,50 @@ var CNS_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetNodeInfo",
 			Handler:    _CNS_GetNodeInfo_Handler,
 		},
+		{
+			MethodName: "RequestIPConfigs",
+			Handler:    _CNS_RequestIPConfigs_Handler,
+		},
+		{
+			MethodName: "ReleaseIPConfigs",
+			Handler:    _CNS_ReleaseIPConfigs_Handler,
+		},
+		{
+			MethodName: "RenewIPLeases",
+			Handler:    _CNS_RenewIPLeases_Handler,
+		},
+		{
+			MethodName: "GetIPAddresses",
+			Handler:    _CNS_GetIPAddresses_Handler,
+		},
+		{
+			MethodName: "GetNetworkContainer",
+			Handler:    _CNS_GetNetworkContainer_Handler,
+		},
+		{
+			MethodName: "GetAllNetworkContainers",
+			Handler:    _CNS_GetAllNetworkContainers_Handler,
+		},
+		{
+			MethodName: "GetEndpoint",
+			Handler:    _CNS_GetEndpoint_Handler,
+		},
+		{
+			MethodName: "UpdateEndpoint",
+			Handler:    _CNS_UpdateEndpoint_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchIPPoolState",
+			Handler:       _CNS_WatchIPPoolState_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchEndpoints",
+			Handler:       _CNS_WatchEndpoints_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "cns/grpc/proto/server.proto",
 }
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/Azure/azure-container-networking/cns"
+	"github.com/Azure/azure-container-networking/cns/events"
 	"github.com/Azure/azure-container-networking/cns/logger"
 	"github.com/Azure/azure-container-networking/cns/metric"
 	"github.com/Azure/azure-container-networking/cns/types"
@@ -51,6 +52,11 @@ type metaState struct {
 type Options struct {
 	RefreshDelay time.Duration
 	MaxIPs       int64
+	// Events, if set, receives the Monitor's scaling decisions.
+	Events events.Publisher
+	// ReservedIPs, if set, returns how many Available IPs are held back from assignment, such as the IPs
+	// of sticky IP reservations. The Monitor doesn't count them as free.
+	ReservedIPs func() int
 }
 
 type Monitor struct {
@@ -103,12 +109,7 @@ func (pm *Monitor) Start(ctx context.Context) error {
 				// if we have initialized and enter this case, we proceed out of the select and continue to reconcile.
 			}
 		case css := <-pm.cssSource: // received an updated ClusterSubnetState
-			pm.metastate.exhausted = css.Status.Exhausted
-			logger.Printf("subnet exhausted status = %t", pm.metastate.exhausted)
-			IpamSubnetExhaustionCount.With(prometheus.Labels{
-				subnetLabel: pm.metastate.subnet, subnetCIDRLabel: pm.metastate.subnetCIDR,
-				podnetARMIDLabel: pm.metastate.subnetARMID, subnetExhaustionStateLabel: strconv.FormatBool(pm.metastate.exhausted),
-			}).Inc()
+			pm.ingestClusterSubnetState(&css)
 			select {
 			default:
 				// if we have NOT initialized and enter this case, we continue out of this iteration and let the for loop begin again.
@@ -117,39 +118,9 @@ func (pm *Monitor) Start(ctx context.Context) error {
 				// if we have initialized and enter this case, we proceed out of the select and continue to reconcile.
 			}
 		case nnc := <-pm.nncSource: // received a new NodeNetworkConfig, extract the data from it and re-reconcile.
-			if len(nnc.Status.NetworkContainers) > 0 {
-				// Set SubnetName, SubnetAddressSpace and Pod Network ARM ID values to the global subnet, subnetCIDR and subnetARM variables.
-				pm.metastate.subnet = nnc.Status.NetworkContainers[0].SubnetName
-				pm.metastate.subnetCIDR = nnc.Status.NetworkContainers[0].SubnetAddressSpace
-				pm.metastate.subnetARMID = GenerateARMID(&nnc.Status.NetworkContainers[0])
+			if err := pm.ingestNodeNetworkConfig(&nnc); err != nil {
+				return err
 			}
-			pm.metastate.primaryIPAddresses = make(map[string]struct{})
-			// Add Primary IP to Map, if not present.
-			// This is only for Swift i.e. if NC Type is vnet.
-			for i := 0; i < len(nnc.Status.NetworkContainers); i++ {
-				nc := nnc.Status.NetworkContainers[i]
-				if nc.Type == "" || nc.Type == v1alpha.VNET {
-					pm.metastate.primaryIPAddresses[nc.PrimaryIP] = struct{}{}
-				}
-
-				if nc.Type == v1alpha.VNETBlock {
-					primaryPrefix, err := netip.ParsePrefix(nc.PrimaryIP)
-					if err != nil {
-						return errors.Wrapf(err, "unable to parse ip prefix: %s", nc.PrimaryIP)
-					}
-					pm.metastate.primaryIPAddresses[primaryPrefix.Addr().String()] = struct{}{}
-				}
-			}
-
-			scaler := nnc.Status.Scaler
-			pm.metastate.batch = scaler.BatchSize
-			pm.metastate.max = scaler.MaxIPCount
-			pm.metastate.minFreeCount, pm.metastate.maxFreeCount = CalculateMinFreeIPs(scaler), CalculateMaxFreeIPs(scaler)
-			pm.once.Do(func() {
-				pm.spec = nnc.Spec // set the spec from the NNC initially (afterwards we write the Spec so we know target state).
-				logger.Printf("[ipam-pool-monitor] set initial pool spec %+v", pm.spec)
-				close(pm.started) // close the init channel the first time we fully receive a NodeNetworkConfig.
-			})
 		}
 		// if control has flowed through the select(s) to this point, we can now reconcile.
 		err := pm.reconcile(ctx)
@@ -159,15 +130,87 @@ func (pm *Monitor) Start(ctx context.Context) error {
 	}
 }
 
+// ingestClusterSubnetState records the subnet exhaustion from the ClusterSubnetState.
+func (pm *Monitor) ingestClusterSubnetState(css *v1alpha1.ClusterSubnetState) {
+	pm.metastate.exhausted = css.Status.Exhausted
+	logger.Printf("subnet exhausted status = %t", pm.metastate.exhausted)
+	IpamSubnetExhaustionCount.With(prometheus.Labels{
+		subnetLabel: pm.metastate.subnet, subnetCIDRLabel: pm.metastate.subnetCIDR,
+		podnetARMIDLabel: pm.metastate.subnetARMID, subnetExhaustionStateLabel: strconv.FormatBool(pm.metastate.exhausted),
+	}).Inc()
+}
+
+// ingestNodeNetworkConfig extracts the pool configuration from the NodeNetworkConfig, and takes the initial
+// spec from the first one received.
+func (pm *Monitor) ingestNodeNetworkConfig(nnc *v1alpha.NodeNetworkConfig) error {
+	if len(nnc.Status.NetworkContainers) > 0 {
+		// Set SubnetName, SubnetAddressSpace and Pod Network ARM ID values to the global subnet, subnetCIDR and subnetARM variables.
+		pm.metastate.subnet = nnc.Status.NetworkContainers[0].SubnetName
+		pm.metastate.subnetCIDR = nnc.Status.NetworkContainers[0].SubnetAddressSpace
+		pm.metastate.subnetARMID = GenerateARMID(&nnc.Status.NetworkContainers[0])
+	}
+	pm.metastate.primaryIPAddresses = make(map[string]struct{})
+	// Add Primary IP to Map, if not present.
+	// This is only for Swift i.e. if NC Type is vnet.
+	for i := 0; i < len(nnc.Status.NetworkContainers); i++ {
+		nc := nnc.Status.NetworkContainers[i]
+		if nc.Type == "" || nc.Type == v1alpha.VNET {
+			pm.metastate.primaryIPAddresses[nc.PrimaryIP] = struct{}{}
+		}
+
+		if nc.Type == v1alpha.VNETBlock {
+			primaryPrefix, err := netip.ParsePrefix(nc.PrimaryIP)
+			if err != nil {
+				return errors.Wrapf(err, "unable to parse ip prefix: %s", nc.PrimaryIP)
+			}
+			pm.metastate.primaryIPAddresses[primaryPrefix.Addr().String()] = struct{}{}
+		}
+	}
+
+	scaler := nnc.Status.Scaler
+	pm.metastate.batch = scaler.BatchSize
+	pm.metastate.max = scaler.MaxIPCount
+	pm.metastate.minFreeCount, pm.metastate.maxFreeCount = CalculateMinFreeIPs(scaler), CalculateMaxFreeIPs(scaler)
+	pm.once.Do(func() {
+		pm.spec = nnc.Spec // set the spec from the NNC initially (afterwards we write the Spec so we know target state).
+		logger.Printf("[ipam-pool-monitor] set initial pool spec %+v", pm.spec)
+		close(pm.started) // close the init channel the first time we fully receive a NodeNetworkConfig.
+	})
+	return nil
+}
+
+// Step synchronously runs one iteration of the pool reconcile loop: it ingests the passed NodeNetworkConfig
+// and ClusterSubnetState, either of which may be nil, and then reconciles the pool if the Monitor has
+// received a NodeNetworkConfig.
+// Step drives the Monitor deterministically in place of Start, such as when replaying recorded pool
+// updates, and must not be used on a Monitor which has been Started.
+func (pm *Monitor) Step(ctx context.Context, nnc *v1alpha.NodeNetworkConfig, css *v1alpha1.ClusterSubnetState) error {
+	if css != nil {
+		pm.ingestClusterSubnetState(css)
+	}
+	if nnc != nil {
+		pm.clampScaler(&nnc.Status.Scaler)
+		if err := pm.ingestNodeNetworkConfig(nnc); err != nil {
+			return err
+		}
+	}
+	select {
+	default:
+		return nil
+	case <-pm.started:
+	}
+	return pm.reconcile(ctx)
+}
+
 // ipPoolState is the current actual state of the CNS IP pool.
 type ipPoolState struct {
 	// allocatedToPods are the IPs CNS gives to Pods.
 	allocatedToPods int64
 	// available are the IPs in state "Available".
 	available int64
-	// currentAvailableIPs are the current available IPs: allocated - assigned - pendingRelease.
+	// currentAvailableIPs are the current available IPs: allocated - assigned - pendingRelease - reserved.
 	currentAvailableIPs int64
-	// expectedAvailableIPs are the "future" available IPs, if the requested IP count is honored: requested - assigned.
+	// expectedAvailableIPs are the "future" available IPs, if the requested IP count is honored: requested - assigned - reserved.
 	expectedAvailableIPs int64
 	// pendingProgramming are the IPs in state "PendingProgramming".
 	pendingProgramming int64
@@ -175,11 +218,13 @@ type ipPoolState struct {
 	pendingRelease int64
 	// requestedIPs are the IPs CNS has requested that it be allocated by DNC.
 	requestedIPs int64
+	// reserved are the IPs in state "Available" which are held back from assignment.
+	reserved int64
 	// secondaryIPs are all the IPs given to CNS by DNC, not including the primary IP of the NC.
 	secondaryIPs int64
 }
 
-func buildIPPoolState(ips map[string]cns.IPConfigurationStatus, spec v1alpha.NodeNetworkConfigSpec) ipPoolState {
+func buildIPPoolState(ips map[string]cns.IPConfigurationStatus, spec v1alpha.NodeNetworkConfigSpec, reserved int) ipPoolState {
 	state := ipPoolState{
 		secondaryIPs: int64(len(ips)),
 		requestedIPs: spec.RequestedIPCount,
@@ -197,8 +242,9 @@ func buildIPPoolState(ips map[string]cns.IPConfigurationStatus, spec v1alpha.Nod
 			state.pendingRelease++
 		}
 	}
-	state.currentAvailableIPs = state.secondaryIPs - state.allocatedToPods - state.pendingRelease
-	state.expectedAvailableIPs = state.requestedIPs - state.allocatedToPods
+	state.reserved = min(int64(reserved), state.available)
+	state.currentAvailableIPs = state.secondaryIPs - state.allocatedToPods - state.pendingRelease - state.reserved
+	state.expectedAvailableIPs = state.requestedIPs - state.allocatedToPods - state.reserved
 	return state
 }
 
@@ -206,8 +252,12 @@ var statelogDownsample int
 
 func (pm *Monitor) reconcile(ctx context.Context) error {
 	allocatedIPs := pm.httpService.GetPodIPConfigState()
+	reserved := 0
+	if pm.opts.ReservedIPs != nil {
+		reserved = pm.opts.ReservedIPs()
+	}
 	meta := pm.metastate
-	state := buildIPPoolState(allocatedIPs, pm.spec)
+	state := buildIPPoolState(allocatedIPs, pm.spec, reserved)
 	observeIPPoolState(state, meta)
 
 	// log every 30th reconcile to reduce the AI load. we will always log when the monitor
@@ -289,6 +339,7 @@ func (pm *Monitor) increasePoolSize(ctx context.Context, meta metaState, state i
 	}
 
 	logger.Printf("[ipam-pool-monitor] Increasing pool size: UpdateCRDSpec succeeded for spec %+v", tempNNCSpec)
+	pm.publishScaled(meta, previouslyRequestedIPCount, tempNNCSpec.RequestedIPCount, 0)
 	// start an alloc timer
 	metric.StartPoolIncreaseTimer(batchSize)
 	// save the updated state to cachedSpec
@@ -363,6 +414,7 @@ func (pm *Monitor) decreasePoolSize(ctx context.Context, meta metaState, state i
 	}
 
 	logger.Printf("[ipam-pool-monitor] Decreasing pool size: UpdateCRDSpec succeeded for spec %+v", tempNNCSpec)
+	pm.publishScaled(meta, previouslyRequestedIPCount, tempNNCSpec.RequestedIPCount, len(pendingIPAddresses))
 	// start a dealloc timer
 	metric.StartPoolDecreaseTimer(batchSize)
 
@@ -375,6 +427,23 @@ func (pm *Monitor) decreasePoolSize(ctx context.Context, meta metaState, state i
 	return nil
 }
 
+// publishScaled publishes a PoolScaled event for a successful change of the requested IP count.
+func (pm *Monitor) publishScaled(meta metaState, previous, requested int64, released int) { //nolint:gocritic // ignore hugeParam
+	if pm.opts.Events == nil {
+		return
+	}
+	pm.opts.Events.Publish(events.Event{
+		Type: events.PoolScaled,
+		Pool: &events.PoolEvent{
+			PreviousRequestedIPCount: previous,
+			RequestedIPCount:         requested,
+			BatchSize:                meta.batch,
+			MaxIPCount:               meta.max,
+			ReleasedIPCount:          released,
+		},
+	})
+}
+
 // cleanPendingRelease removes IPs from the cache and CRD if the request controller has reconciled
 // CNS state and the pending IP release map is empty.
 func (pm *Monitor) cleanPendingRelease(ctx context.Context) error {
@@ -9,6 +9,7 @@ import (
 	"github.com/Azure/azure-container-networking/cns"
 	"github.com/Azure/azure-container-networking/cns/fakes"
 	"github.com/Azure/azure-container-networking/cns/logger"
+	"github.com/Azure/azure-container-networking/crd/clustersubnetstate/api/v1alpha1"
 	"github.com/Azure/azure-container-networking/crd/nodenetworkconfig/api/v1alpha"
 	"github.com/stretchr/testify/assert"
 )
@@ -288,6 +289,28 @@ func TestIncreaseWithPendingRelease(t *testing.T) {
 	assert.Empty(t, mon.spec.IPsNotInUse)
 }
 
+func TestPoolIncreaseWithReservedIPs(t *testing.T) {
+	initState := testState{
+		batch:                   10,
+		assigned:                5,
+		allocated:               10,
+		requestThresholdPercent: 50,
+		releaseThresholdPercent: 150,
+		max:                     30,
+	}
+	_, rc, mon := initFakes(initState, nil)
+	assert.NoError(t, rc.Reconcile(true))
+
+	// five IPs are free, which is enough to not scale up
+	assert.NoError(t, mon.reconcile(context.Background()))
+	assert.Equal(t, int64(10), mon.spec.RequestedIPCount)
+
+	// but three of them are held back from assignment, so they are not free
+	mon.opts.ReservedIPs = func() int { return 3 }
+	assert.NoError(t, mon.reconcile(context.Background()))
+	assert.Equal(t, int64(20), mon.spec.RequestedIPCount)
+}
+
 func TestPoolDecrease(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -672,3 +695,27 @@ func TestCalculateIPs(t *testing.T) {
 		})
 	}
 }
+
+func TestStep(t *testing.T) {
+	_, fakerc, poolmonitor := initFakes(testState{
+		allocated:               10,
+		assigned:                8,
+		batch:                   10,
+		max:                     30,
+		releaseThresholdPercent: 150,
+		requestThresholdPercent: 50,
+	}, nil)
+	nnc := fakerc.NNC.DeepCopy()
+
+	// the pool isn't reconciled until the Monitor has received an NNC
+	assert.NoError(t, poolmonitor.Step(context.Background(), nil, &v1alpha1.ClusterSubnetState{}))
+	assert.Equal(t, int64(0), poolmonitor.spec.RequestedIPCount)
+
+	assert.NoError(t, poolmonitor.Step(context.Background(), nnc, nil))
+	assert.Equal(t, int64(20), poolmonitor.spec.RequestedIPCount)
+	assert.Equal(t, int64(20), fakerc.NNC.Spec.RequestedIPCount)
+
+	// a tick with nothing new is idempotent
+	assert.NoError(t, poolmonitor.Step(context.Background(), nil, nil))
+	assert.Equal(t, int64(20), poolmonitor.spec.RequestedIPCount)
+}
@@ -3,11 +3,15 @@ package v2
 import (
 	"github.com/Azure/azure-container-networking/cns"
 	"github.com/Azure/azure-container-networking/crd/nodenetworkconfig/api/v1alpha"
+	"github.com/pkg/errors"
 	v1 "k8s.io/api/core/v1"
 )
 
 var _ cns.IPAMPoolMonitor = (*adapter)(nil)
 
+// ErrStateSnapshotUnsupported is returned in place of the pool monitor state snapshot by the IPAMv2 monitor.
+var ErrStateSnapshotUnsupported = errors.New("the IPAM pool monitor state is not supported with IPAMv2")
+
 type adapter struct {
 	nncSink chan<- v1alpha.NodeNetworkConfig
 	*Monitor
@@ -25,10 +29,18 @@ func (m *adapter) Update(nnc *v1alpha.NodeNetworkConfig) error {
 	return nil
 }
 
+// GetStateSnapshot returns an empty snapshot, see StateSnapshot.
 func (m *adapter) GetStateSnapshot() cns.IpamPoolMonitorStateSnapshot {
 	return cns.IpamPoolMonitorStateSnapshot{}
 }
 
+// StateSnapshot is the pool monitor state getter for the CNS debug API when running the IPAMv2 monitor.
+// It always returns ErrStateSnapshotUnsupported: the IPAMv2 monitor can't fill an IpamPoolMonitorStateSnapshot
+// as it scales on demand instead of free IP thresholds, and doesn't cache the NodeNetworkConfig.
+func StateSnapshot() (cns.IpamPoolMonitorStateSnapshot, error) {
+	return cns.IpamPoolMonitorStateSnapshot{}, ErrStateSnapshotUnsupported
+}
+
 func PodIPDemandListener(ch chan<- int) func([]v1.Pod) {
 	return func(pods []v1.Pod) {
 		ch <- len(pods)
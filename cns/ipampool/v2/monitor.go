@@ -4,8 +4,10 @@ import (
 	"context"
 	"math"
 	"sync"
+	"time"
 
 	"github.com/Azure/azure-container-networking/cns"
+	"github.com/Azure/azure-container-networking/cns/events"
 	"github.com/Azure/azure-container-networking/crd/clustersubnetstate/api/v1alpha1"
 	"github.com/Azure/azure-container-networking/crd/nodenetworkconfig/api/v1alpha"
 	"github.com/pkg/errors"
@@ -47,6 +49,9 @@ type Monitor struct {
 	nncSource    <-chan v1alpha.NodeNetworkConfig
 	started      chan interface{}
 	once         sync.Once
+	events       events.Publisher
+	policy       Policy
+	reservedIPs  func() int
 }
 
 func NewMonitor(z *zap.Logger, store ipStateStore, nnccli nodeNetworkConfigSpecUpdater, demandSource <-chan int, nncSource <-chan v1alpha.NodeNetworkConfig, cssSource <-chan v1alpha1.ClusterSubnetState) *Monitor { //nolint:lll // it's fine
@@ -58,19 +63,47 @@ func NewMonitor(z *zap.Logger, store ipStateStore, nnccli nodeNetworkConfigSpecU
 		cssSource:    cssSource,
 		nncSource:    nncSource,
 		started:      make(chan interface{}),
+		policy:       StaticPolicy{},
 	}
 }
 
+// WithPolicy sets the Policy which decides the IP count to request. The default is the StaticPolicy.
+func (pm *Monitor) WithPolicy(p Policy) *Monitor {
+	pm.policy = p
+	return pm
+}
+
+// WithEvents sets the publisher which receives the Monitor's scaling decisions.
+func (pm *Monitor) WithEvents(p events.Publisher) *Monitor {
+	pm.events = p
+	return pm
+}
+
+// WithReservedIPs sets the func which returns how many IPs are held back from assignment without a Pod,
+// such as the IPs of sticky IP reservations. The Monitor requests them on top of the Pod demand.
+func (pm *Monitor) WithReservedIPs(f func() int) *Monitor {
+	pm.reservedIPs = f
+	return pm
+}
+
 // Start begins the Monitor's pool reconcile loop.
 // On first run, it will block until a NodeNetworkConfig is received (through a call to Update()).
 // Subsequently, it will run run once per RefreshDelay and attempt to re-reconcile the pool.
 func (pm *Monitor) Start(ctx context.Context) error {
 	pm.z.Debug("starting")
+	// periodic policies are re-evaluated on a timer, as their target changes over time.
+	var tick <-chan time.Time
+	if p, ok := pm.policy.(PeriodicPolicy); ok && p.Interval() > 0 {
+		ticker := time.NewTicker(p.Interval())
+		defer ticker.Stop()
+		tick = ticker.C
+	}
 	for {
 		// proceed when things happen:
 		select {
 		case <-ctx.Done(): // calling context has closed, we'll exit.
 			return errors.Wrap(ctx.Err(), "pool monitor context closed")
+		case <-tick: // time has passed, re-evaluate the policy
 		case demand := <-pm.demandSource: // updated demand for IPs, recalculate request
 			pm.demand = int64(demand)
 			pm.z.Info("demand update", zap.Int64("demand", pm.demand))
@@ -112,8 +145,19 @@ func (pm *Monitor) reconcile(ctx context.Context) error {
 	}
 
 	// calculate the target state from the current pool state and scaler
-	target := calculateTargetIPCountOrMax(pm.demand, s.batch, s.max, s.buffer)
-	pm.z.Info("calculated new request", zap.Int64("demand", pm.demand), zap.Int64("batch", s.batch), zap.Int64("max", s.max), zap.Float64("buffer", s.buffer), zap.Int64("target", target))
+	policy := pm.policy
+	if policy == nil {
+		policy = StaticPolicy{}
+	}
+	demand := pm.demand
+	if pm.reservedIPs != nil {
+		demand += int64(pm.reservedIPs())
+	}
+	target := policy.Target(time.Now(), demand, Scaler{Batch: s.batch, Buffer: s.buffer, Max: s.max, Exhausted: s.exhausted})
+	if target > s.max {
+		target = s.max
+	}
+	pm.z.Info("calculated new request", zap.Int64("demand", demand), zap.Int64("batch", s.batch), zap.Int64("max", s.max), zap.Float64("buffer", s.buffer), zap.Int64("target", target))
 	delta := target - pm.request
 	if delta == 0 {
 		return nil
@@ -127,6 +171,23 @@ func (pm *Monitor) reconcile(ctx context.Context) error {
 	if _, err := pm.nnccli.PatchSpec(ctx, &spec, fieldManager); err != nil {
 		return errors.Wrap(err, "failed to UpdateSpec with NNC client")
 	}
+	if pm.events != nil {
+		released := 0
+		if delta < 0 {
+			released = int(-delta)
+		}
+		pm.events.Publish(events.Event{
+			Type: events.PoolScaled,
+			Pool: &events.PoolEvent{
+				PreviousRequestedIPCount: pm.request,
+				RequestedIPCount:         target,
+				BatchSize:                s.batch,
+				MaxIPCount:               s.max,
+				Demand:                   demand,
+				ReleasedIPCount:          released,
+			},
+		})
+	}
 	pm.request = target
 	pm.z.Info("scaled pool", zap.Int64("request", pm.request))
 	return nil
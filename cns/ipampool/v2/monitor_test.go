@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/Azure/azure-container-networking/cns"
+	"github.com/Azure/azure-container-networking/cns/events"
 	"github.com/Azure/azure-container-networking/cns/types"
 	"github.com/Azure/azure-container-networking/crd/nodenetworkconfig/api/v1alpha"
 	"github.com/google/uuid"
@@ -356,3 +357,49 @@ func TestReconcile(t *testing.T) {
 		})
 	}
 }
+
+func TestReconcilePublishesScaleEvents(t *testing.T) {
+	log := events.NewLog(8)
+	start := log.Head()
+	pm := (&Monitor{
+		z:       zap.NewNop(),
+		demand:  5,
+		request: 32,
+		scaler:  scaler{batch: 16, buffer: .5, max: 250},
+		nnccli:  &nncClientMock{},
+		store:   &ipStateStoreMock{},
+	}).WithEvents(log)
+
+	require.NoError(t, pm.reconcile(context.Background()))
+	// a reconcile which doesn't change the request publishes nothing
+	require.NoError(t, pm.reconcile(context.Background()))
+
+	page := log.Since(start)
+	require.Len(t, page.Events, 1)
+	assert.Equal(t, events.PoolScaled, page.Events[0].Type)
+	assert.Equal(t, &events.PoolEvent{
+		PreviousRequestedIPCount: 32,
+		RequestedIPCount:         16,
+		BatchSize:                16,
+		MaxIPCount:               250,
+		Demand:                   5,
+		ReleasedIPCount:          16,
+	}, page.Events[0].Pool)
+}
+
+func TestReconcileRequestsReservedIPs(t *testing.T) {
+	nnccli := &nncClientMock{}
+	pm := (&Monitor{
+		z:       zap.NewNop(),
+		demand:  5,
+		request: 32,
+		scaler:  scaler{batch: 16, buffer: .5, max: 250},
+		nnccli:  nnccli,
+		store:   &ipStateStoreMock{},
+	}).WithReservedIPs(func() int { return 10 })
+
+	// the 10 IPs held without a pod keep the request from scaling down to 16
+	require.NoError(t, pm.reconcile(context.Background()))
+	assert.Equal(t, int64(32), pm.request)
+	assert.Empty(t, nnccli.req.IPsNotInUse)
+}
@@ -0,0 +1,178 @@
+package v2
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Names of the built-in policies, for selecting them from configuration.
+const (
+	StaticPolicyName     = "static"
+	PredictivePolicyName = "predictive"
+)
+
+// Scaler holds the scaling parameters passed to a Policy.
+type Scaler struct {
+	// Batch is the granularity of the IP request.
+	Batch int64
+	// Buffer is the fraction of a Batch to keep free above the demand.
+	Buffer float64
+	// Max is the most IPs which may be requested.
+	Max int64
+	// Exhausted is set when the subnet is out of IPs and the request should track demand as closely as possible.
+	Exhausted bool
+}
+
+// Policy decides how many IPs the Monitor requests for the pool.
+type Policy interface {
+	// Target returns the IP count to request for the demand observed at now. The Monitor clamps the result to s.Max.
+	Target(now time.Time, demand int64, s Scaler) int64
+}
+
+// NewPolicy returns the built-in Policy with the passed name. An empty name selects the StaticPolicy.
+func NewPolicy(name string) (Policy, error) {
+	switch name {
+	case "", StaticPolicyName:
+		return StaticPolicy{}, nil
+	case PredictivePolicyName:
+		return NewPredictivePolicy(PredictiveOptions{}), nil
+	default:
+		return nil, errors.Errorf("unknown IPAM scaling policy %q", name)
+	}
+}
+
+// PolicyFunc adapts a function to a Policy.
+type PolicyFunc func(now time.Time, demand int64, s Scaler) int64
+
+func (f PolicyFunc) Target(now time.Time, demand int64, s Scaler) int64 {
+	return f(now, demand, s)
+}
+
+// PeriodicPolicy is a Policy whose target changes over time without the demand changing,
+// and which the Monitor should re-evaluate every Interval.
+type PeriodicPolicy interface {
+	Policy
+	Interval() time.Duration
+}
+
+// StaticPolicy is the default Policy, which requests IPs for the current demand plus the buffer.
+type StaticPolicy struct{}
+
+func (StaticPolicy) Target(_ time.Time, demand int64, s Scaler) int64 {
+	return calculateTargetIPCountOrMax(demand, s.Batch, s.Max, s.Buffer)
+}
+
+// PredictiveOptions configures a PredictivePolicy.
+type PredictiveOptions struct {
+	// Alpha is the EWMA smoothing factor for demand surges, in (0,1]. Higher values react faster to recent surges.
+	Alpha float64
+	// SlotWidth is the width of the time-of-day slots in which peak demand is remembered.
+	SlotWidth time.Duration
+	// Lookahead is how far ahead the remembered time-of-day peaks are pre-requested.
+	Lookahead time.Duration
+	// PeakDecay is applied to a slot's remembered peak each day, so that peaks which stop recurring are forgotten.
+	PeakDecay float64
+}
+
+const (
+	defaultPredictiveAlpha     = 0.5
+	defaultPredictiveSlotWidth = 15 * time.Minute
+	defaultPredictiveLookahead = 5 * time.Minute
+	defaultPredictivePeakDecay = 0.8
+)
+
+// PredictivePolicy pre-requests IPs ahead of demand. It tracks an exponentially weighted moving average of
+// demand surges, and the peak demand seen in each time-of-day slot, and requests for whichever is higher of
+// the current demand plus the expected surge, or the remembered peak of the slot coming up within the Lookahead.
+// When the subnet is exhausted it falls back to the StaticPolicy.
+type PredictivePolicy struct {
+	opts PredictiveOptions
+
+	mu         sync.Mutex
+	started    bool
+	lastDemand int64
+	surge      float64
+	peaks      []float64   // peak demand by time-of-day slot
+	peakDays   []time.Time // the day on which each slot's peak was last updated
+}
+
+// NewPredictivePolicy returns a PredictivePolicy, defaulting any unset options.
+func NewPredictivePolicy(opts PredictiveOptions) *PredictivePolicy {
+	if opts.Alpha <= 0 || opts.Alpha > 1 {
+		opts.Alpha = defaultPredictiveAlpha
+	}
+	if opts.SlotWidth <= 0 || opts.SlotWidth > 24*time.Hour {
+		opts.SlotWidth = defaultPredictiveSlotWidth
+	}
+	if opts.Lookahead <= 0 {
+		opts.Lookahead = defaultPredictiveLookahead
+	}
+	if opts.PeakDecay <= 0 || opts.PeakDecay > 1 {
+		opts.PeakDecay = defaultPredictivePeakDecay
+	}
+	slots := int((24*time.Hour + opts.SlotWidth - 1) / opts.SlotWidth)
+	return &PredictivePolicy{
+		opts:     opts,
+		peaks:    make([]float64, slots),
+		peakDays: make([]time.Time, slots),
+	}
+}
+
+// Interval re-evaluates the policy as each time-of-day slot comes within the Lookahead.
+func (p *PredictivePolicy) Interval() time.Duration {
+	if p.opts.SlotWidth < p.opts.Lookahead {
+		return p.opts.SlotWidth
+	}
+	return p.opts.Lookahead
+}
+
+// slot returns the time-of-day slot of t and its calendar day. Both are taken from the wall clock of t,
+// so that a peak at 09:00 stays in the 09:00 slot across DST changes, and the 23h and 25h days at a DST
+// change don't run past the last slot. The day is returned at midnight UTC so that days are 24h apart.
+func (p *PredictivePolicy) slot(t time.Time) (int, time.Time) {
+	hour, minute, sec := t.Clock()
+	offset := time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute + time.Duration(sec)*time.Second + time.Duration(t.Nanosecond())
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return int(offset / p.opts.SlotWidth), day
+}
+
+// peak returns the remembered peak of the slot, decayed by the number of days since it was last seen.
+func (p *PredictivePolicy) peak(slot int, day time.Time) float64 {
+	if p.peakDays[slot].IsZero() {
+		return 0
+	}
+	days := day.Sub(p.peakDays[slot]).Hours() / 24 //nolint:gomnd // hours in a day
+	return p.peaks[slot] * math.Pow(p.opts.PeakDecay, math.Max(days, 0))
+}
+
+func (p *PredictivePolicy) Target(now time.Time, demand int64, s Scaler) int64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// learn from the observed demand
+	if p.started {
+		delta := float64(demand - p.lastDemand)
+		if delta < 0 {
+			delta = 0
+		}
+		p.surge = p.opts.Alpha*delta + (1-p.opts.Alpha)*p.surge
+	}
+	p.started = true
+	p.lastDemand = demand
+	slot, day := p.slot(now)
+	p.peaks[slot] = math.Max(p.peak(slot, day), float64(demand))
+	p.peakDays[slot] = day
+
+	if s.Exhausted {
+		return StaticPolicy{}.Target(now, demand, s)
+	}
+
+	predicted := float64(demand) + p.surge
+	upcoming, upcomingDay := p.slot(now.Add(p.opts.Lookahead))
+	// the upcoming slot's peak is from a previous day if the slot hasn't been seen yet today.
+	predicted = math.Max(predicted, p.peak(upcoming, upcomingDay))
+	return calculateTargetIPCountOrMax(int64(math.Ceil(predicted)), s.Batch, s.Max, s.Buffer)
+}
@@ -0,0 +1,153 @@
+package v2
+
+import (
+	"context"
+	"testing"
+	"time"
+	_ "time/tzdata" // for the DST test zone
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+var (
+	testScaler = Scaler{Batch: 16, Buffer: .5, Max: 250}
+	// a Monday at midnight
+	testDay = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+)
+
+func TestStaticPolicy(t *testing.T) {
+	for _, demand := range []int64{0, 8, 16, 100, 300} {
+		assert.Equal(t, calculateTargetIPCountOrMax(demand, 16, 250, .5), StaticPolicy{}.Target(testDay, demand, testScaler))
+	}
+}
+
+func TestPredictivePolicySurge(t *testing.T) {
+	p := NewPredictivePolicy(PredictiveOptions{Alpha: .5})
+	assert.Equal(t, int64(16), p.Target(testDay, 5, testScaler))
+
+	// a surge of 40 pods leaves room for half as many again
+	assert.Equal(t, int64(80), p.Target(testDay.Add(time.Second), 45, testScaler))
+	// the expected surge decays while demand holds steady
+	assert.Equal(t, int64(64), p.Target(testDay.Add(2*time.Second), 45, testScaler))
+	assert.Equal(t, int64(64), p.Target(testDay.Add(3*time.Second), 45, testScaler))
+	assert.Equal(t, StaticPolicy{}.Target(testDay, 45, testScaler), p.Target(testDay.Add(30*time.Second), 45, testScaler))
+
+	// an exhausted subnet gets the static request
+	exhausted := testScaler
+	exhausted.Exhausted = true
+	assert.Equal(t, StaticPolicy{}.Target(testDay, 90, exhausted), p.Target(testDay.Add(time.Minute), 90, exhausted))
+}
+
+func TestPredictivePolicyTimeOfDay(t *testing.T) {
+	p := NewPredictivePolicy(PredictiveOptions{Alpha: 1, SlotWidth: 15 * time.Minute, Lookahead: 5 * time.Minute, PeakDecay: .5})
+	assert.Equal(t, 5*time.Minute, p.Interval())
+
+	// yesterday, a job ran at 09:00
+	p.Target(testDay.Add(9*time.Hour), 100, testScaler)
+	p.Target(testDay.Add(9*time.Hour+10*time.Minute), 5, testScaler)
+	p.Target(testDay.Add(12*time.Hour), 5, testScaler)
+
+	tomorrow := testDay.Add(24 * time.Hour)
+	// outside the lookahead nothing is pre-requested
+	assert.Equal(t, int64(16), p.Target(tomorrow.Add(8*time.Hour+50*time.Minute), 5, testScaler))
+	// within the lookahead the decayed peak of the 09:00 slot is pre-requested
+	assert.Equal(t, StaticPolicy{}.Target(tomorrow, 50, testScaler), p.Target(tomorrow.Add(8*time.Hour+56*time.Minute), 5, testScaler))
+}
+
+func TestPredictivePolicyDST(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		day  time.Time
+		len  time.Duration
+	}{
+		{
+			name: "23h day",
+			day:  time.Date(2024, time.March, 10, 0, 0, 0, 0, newYork),
+			len:  23 * time.Hour,
+		},
+		{
+			name: "25h day",
+			day:  time.Date(2024, time.November, 3, 0, 0, 0, 0, newYork),
+			len:  25 * time.Hour,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewPredictivePolicy(PredictiveOptions{Alpha: 1, SlotWidth: 15 * time.Minute, Lookahead: 5 * time.Minute, PeakDecay: .5})
+			require.Equal(t, tt.len, tt.day.AddDate(0, 0, 1).Sub(tt.day))
+			// every slot of the day, up to its last minute, is tracked without running past the last slot
+			for at := time.Duration(0); at < tt.len; at += 5 * time.Minute {
+				p.Target(tt.day.Add(at), 5, testScaler)
+			}
+			// a job at 23:30 on the DST day is remembered in the 23:30 wall clock slot of the next day
+			p.Target(time.Date(tt.day.Year(), tt.day.Month(), tt.day.Day(), 23, 30, 0, 0, newYork), 100, testScaler)
+			next := tt.day.AddDate(0, 0, 1)
+			assert.Equal(t, StaticPolicy{}.Target(next, 50, testScaler), p.Target(time.Date(next.Year(), next.Month(), next.Day(), 23, 26, 0, 0, newYork), 5, testScaler))
+		})
+	}
+}
+
+func TestMonitorUsesPolicy(t *testing.T) {
+	nnccli := &nncClientMock{}
+	pm := (&Monitor{
+		z:       zap.NewNop(),
+		demand:  5,
+		request: 16,
+		scaler:  scaler{batch: 16, buffer: .5, max: 250},
+		nnccli:  nnccli,
+		store:   &ipStateStoreMock{},
+	}).WithPolicy(PolicyFunc(func(_ time.Time, demand int64, s Scaler) int64 {
+		assert.Equal(t, int64(5), demand)
+		assert.Equal(t, Scaler{Batch: 16, Buffer: .5, Max: 250}, s)
+		return 1000
+	}))
+
+	require.NoError(t, pm.reconcile(context.Background()))
+	// the request is clamped to the max
+	assert.Equal(t, int64(250), pm.request)
+	assert.Equal(t, int64(250), nnccli.req.RequestedIPCount)
+}
+
+// cronTrace is a baseline of 10 pods with a burst to 60 pods at the top of every hour, for five minutes.
+func cronTrace(days int) []DemandSample {
+	var trace []DemandSample
+	for h := 0; h < 24*days; h++ {
+		hour := time.Duration(h) * time.Hour
+		trace = append(trace,
+			DemandSample{At: hour, Demand: 60},
+			DemandSample{At: hour + 5*time.Minute, Demand: 10},
+		)
+	}
+	return trace
+}
+
+func TestSimulate(t *testing.T) {
+	cfg := SimulationConfig{Start: testDay, Scaler: testScaler, Latency: 30 * time.Second}
+	trace := cronTrace(2)
+
+	static := Simulate(StaticPolicy{}, cfg, trace)
+	// every burst waits on the NNC round-trip
+	assert.Equal(t, 48, static.WaitEvents)
+	// the first burst starts from the initial batch, the rest from the request for the baseline
+	assert.Equal(t, int64((60-16)+47*(60-32)), static.WaitedIPs)
+	assert.Equal(t, 96, static.Requests)
+	assert.Equal(t, int64(80), static.PeakAllocated)
+	assert.Positive(t, static.AvgWastedIPs)
+
+	predictive := Simulate(NewPredictivePolicy(PredictiveOptions{SlotWidth: 15 * time.Minute, Lookahead: 5 * time.Minute}), cfg, trace)
+	// on the second day the bursts are pre-requested
+	assert.Equal(t, 24, predictive.WaitEvents)
+	assert.Less(t, predictive.WaitedIPs, static.WaitedIPs)
+	// at the cost of holding more IPs
+	assert.Greater(t, predictive.AvgWastedIPs, static.AvgWastedIPs)
+}
+
+func TestSimulateEmptyTrace(t *testing.T) {
+	assert.Equal(t, SimulationReport{}, Simulate(StaticPolicy{}, SimulationConfig{Scaler: testScaler}, nil))
+}
@@ -0,0 +1,138 @@
+package v2
+
+import (
+	"sort"
+	"time"
+)
+
+// DemandSample is the pod IP demand observed at an offset into a demand trace.
+type DemandSample struct {
+	At     time.Duration
+	Demand int64
+}
+
+// SimulationConfig configures a replay of a demand trace against a Policy.
+type SimulationConfig struct {
+	// Start is the wall clock time of the beginning of the trace, which time-of-day policies depend on.
+	Start time.Time
+	// Scaler is passed to the Policy on every evaluation.
+	Scaler Scaler
+	// Latency is the NodeNetworkConfig round-trip between requesting IPs and the node being allocated them.
+	Latency time.Duration
+	// Initial is the IP count allocated to the node at the start of the trace. Defaults to one batch.
+	Initial int64
+}
+
+// SimulationReport summarizes how a Policy served a demand trace.
+type SimulationReport struct {
+	// Requests is the number of times the requested IP count changed, each of which is a NodeNetworkConfig update.
+	Requests int
+	// PeakAllocated is the most IPs allocated to the node at once.
+	PeakAllocated int64
+	// WaitEvents is the number of samples at which demand exceeded the allocated IPs, so pods waited on IPs.
+	WaitEvents int
+	// WaitedIPs is the total demand which was unmet across the WaitEvents.
+	WaitedIPs int64
+	// WastedIPSeconds is the integral of the allocated but unused IPs over the trace.
+	WastedIPSeconds float64
+	// AvgWastedIPs is the mean number of allocated but unused IPs over the trace.
+	AvgWastedIPs float64
+}
+
+type pendingAllocation struct {
+	at    time.Duration
+	count int64
+}
+
+// Simulate replays the demand trace against the Policy, modeling the delay between the request changing and the
+// IPs being allocated, and reports the requests made, the demand which had to wait for IPs and the IPs wasted.
+// A PeriodicPolicy is also evaluated every Interval between samples, as the Monitor would.
+func Simulate(p Policy, cfg SimulationConfig, trace []DemandSample) SimulationReport { //nolint:gocritic // ignore hugeParam
+	var report SimulationReport
+	if len(trace) == 0 {
+		return report
+	}
+	samples := make([]DemandSample, len(trace))
+	copy(samples, trace)
+	sort.SliceStable(samples, func(i, j int) bool { return samples[i].At < samples[j].At })
+	end := samples[len(samples)-1].At
+
+	// the evaluation points are the samples, plus the policy's ticks.
+	type point struct {
+		at     time.Duration
+		sample bool
+		demand int64
+	}
+	points := make([]point, 0, len(samples))
+	for _, s := range samples {
+		points = append(points, point{at: s.At, sample: true, demand: s.Demand})
+	}
+	if pp, ok := p.(PeriodicPolicy); ok && pp.Interval() > 0 {
+		for at := samples[0].At + pp.Interval(); at < end; at += pp.Interval() {
+			points = append(points, point{at: at})
+		}
+		// samples go before ticks at the same time.
+		sort.SliceStable(points, func(i, j int) bool {
+			if points[i].at == points[j].at {
+				return points[i].sample && !points[j].sample
+			}
+			return points[i].at < points[j].at
+		})
+	}
+
+	allocated := cfg.Initial
+	if allocated == 0 {
+		allocated = cfg.Scaler.Batch
+	}
+	request := allocated
+	report.PeakAllocated = allocated
+	var (
+		demand  int64
+		last    = samples[0].At
+		pending []pendingAllocation
+	)
+	advance := func(to time.Duration) {
+		if free := allocated - demand; free > 0 && to > last {
+			report.WastedIPSeconds += float64(free) * (to - last).Seconds()
+		}
+		last = to
+	}
+	allocate := func(until time.Duration) {
+		for len(pending) > 0 && pending[0].at <= until {
+			advance(pending[0].at)
+			allocated = pending[0].count
+			if allocated > report.PeakAllocated {
+				report.PeakAllocated = allocated
+			}
+			pending = pending[1:]
+		}
+	}
+
+	for _, pt := range points {
+		allocate(pt.at)
+		advance(pt.at)
+		if pt.sample {
+			demand = pt.demand
+			if demand > allocated {
+				report.WaitEvents++
+				report.WaitedIPs += demand - allocated
+			}
+		}
+		target := p.Target(cfg.Start.Add(pt.at), demand, cfg.Scaler)
+		if target > cfg.Scaler.Max {
+			target = cfg.Scaler.Max
+		}
+		if target != request {
+			report.Requests++
+			request = target
+			pending = append(pending, pendingAllocation{at: pt.at + cfg.Latency, count: target})
+		}
+	}
+	allocate(end)
+	advance(end)
+
+	if duration := (end - samples[0].At).Seconds(); duration > 0 {
+		report.AvgWastedIPs = report.WastedIPSeconds / duration
+	}
+	return report
+}
@@ -58,6 +58,13 @@ func NewReconciler(cnscli cnsClient, ipampoolmonitorcli nodeNetworkConfigListene
 	}
 }
 
+// WithGetter sets the client that the Reconciler gets NodeNetworkConfigs from, so that it can be driven without
+// a manager. SetupWithManager replaces it with the manager's client.
+func (r *Reconciler) WithGetter(nnccli nncGetter) *Reconciler {
+	r.nnccli = nnccli
+	return r
+}
+
 // Reconcile is called on CRD status changes
 func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
 	listenersToNotify := []nodeNetworkConfigListener{}
@@ -16,6 +16,7 @@ import (
 	"strings"
 
 	"github.com/Azure/azure-container-networking/cns"
+	"github.com/Azure/azure-container-networking/cns/events"
 	"github.com/Azure/azure-container-networking/cns/hnsclient"
 	"github.com/Azure/azure-container-networking/cns/logger"
 	"github.com/Azure/azure-container-networking/cns/types"
@@ -890,6 +891,14 @@ func (service *HTTPRestService) GetAllNetworkContainers(w http.ResponseWriter, r
 		return
 	}
 
+	resp := service.GetAllNetworkContainersHandlerHelper(req)
+	err = common.Encode(w, &resp)
+	logger.Response(service.Name, resp, resp.Response.ReturnCode, err)
+}
+
+// GetAllNetworkContainersHandlerHelper returns the network containers matching the request
+// and reports the network containers which could not be retrieved in the response.
+func (service *HTTPRestService) GetAllNetworkContainersHandlerHelper(req cns.GetNetworkContainerRequest) cns.GetAllNetworkContainersResponse {
 	getAllNetworkContainerResponses := service.getAllNetworkContainerResponses(req) // nolint
 
 	var resp cns.GetAllNetworkContainersResponse
@@ -915,9 +924,7 @@ func (service *HTTPRestService) GetAllNetworkContainers(w http.ResponseWriter, r
 		resp.Response.ReturnCode = types.Success
 		resp.Response.Message = "Successfully retrieved NCs"
 	}
-
-	err = common.Encode(w, &resp)
-	logger.Response(service.Name, resp, resp.Response.ReturnCode, err)
+	return resp
 }
 
 func (service *HTTPRestService) GetNetworkContainerByOrchestratorContext(w http.ResponseWriter, r *http.Request) {
@@ -931,9 +938,15 @@ func (service *HTTPRestService) GetNetworkContainerByOrchestratorContext(w http.
 		return
 	}
 
+	resp := service.GetNetworkContainerHandlerHelper(req)
+	err = common.Encode(w, &resp)
+	logger.Response(service.Name, resp, resp.Response.ReturnCode, err)
+}
+
+// GetNetworkContainerHandlerHelper returns the first network container matching the request.
+func (service *HTTPRestService) GetNetworkContainerHandlerHelper(req cns.GetNetworkContainerRequest) cns.GetNetworkContainerResponse {
 	getNetworkContainerResponses := service.getAllNetworkContainerResponses(req) // nolint
-	err = common.Encode(w, &getNetworkContainerResponses[0])
-	logger.Response(service.Name, getNetworkContainerResponses[0], getNetworkContainerResponses[0].Response.ReturnCode, err)
+	return getNetworkContainerResponses[0]
 }
 
 // getOrRefreshNetworkContainers is to check whether refresh association is needed. The state file in CNS will get updated if it is lost.
@@ -1002,6 +1015,7 @@ func (service *HTTPRestService) deleteNetworkContainer(w http.ResponseWriter, r
 
 		if service.state.ContainerStatus != nil {
 			delete(service.state.ContainerStatus, ncid)
+			service.publishNCEvent(events.NetworkContainerDeleted, ncid, &containerStatus.CreateNetworkContainerRequest)
 		}
 
 		if service.state.ContainerIDByOrchestratorContext != nil {
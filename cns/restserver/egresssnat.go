@@ -0,0 +1,72 @@
+package restserver
+
+import (
+	"github.com/Azure/azure-container-networking/cns"
+	"github.com/Azure/azure-container-networking/cns/logger"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	// EgressSNATAnnotation chooses how the egress traffic of a Pod is source NATed: "node-ip", "none" or
+	// "secondary-ip". It takes precedence over the egress SNAT configured for the namespace of the Pod.
+	EgressSNATAnnotation = "kubernetes.azure.com/egress-snat"
+	// EgressSNATIPAnnotation is the secondary IP of the Node the egress traffic of a Pod is source NATed to, with
+	// the "secondary-ip" egress SNAT.
+	EgressSNATIPAnnotation = "kubernetes.azure.com/egress-snat-ip"
+)
+
+// SetEgressSNATNamespaces sets the egress SNAT of the Pods in each namespace which are not annotated with their own.
+// Invalid entries are logged and ignored.
+func (service *HTTPRestService) SetEgressSNATNamespaces(namespaces map[string]cns.EgressSNAT) {
+	valid := make(map[string]cns.EgressSNAT, len(namespaces))
+	for namespace, egressSNAT := range namespaces {
+		if err := egressSNAT.Validate(); err != nil {
+			logger.Errorf("[egressSNAT] Ignoring egress SNAT of namespace %s: %v", namespace, err)
+			continue
+		}
+		valid[namespace] = egressSNAT
+	}
+
+	service.Lock()
+	defer service.Unlock()
+	service.egressSNATNamespaces = valid
+}
+
+// EgressSNATPodListener records the egress SNAT the Pods on the Node are annotated with. Invalid annotations are
+// logged and ignored, leaving the Pod with the egress SNAT of its namespace.
+func (service *HTTPRestService) EgressSNATPodListener(pods []v1.Pod) {
+	egressSNATPods := make(map[string]cns.EgressSNAT)
+	for i := range pods {
+		mode, ok := pods[i].Annotations[EgressSNATAnnotation]
+		if !ok {
+			continue
+		}
+		egressSNAT := cns.EgressSNAT{Mode: mode, IPAddress: pods[i].Annotations[EgressSNATIPAnnotation]}
+		if err := egressSNAT.Validate(); err != nil {
+			logger.Warnf("[egressSNAT] Ignoring egress SNAT annotation of pod %s/%s: %v", pods[i].Namespace, pods[i].Name, err)
+			continue
+		}
+		egressSNATPods[stickyIPKey(pods[i].Name, pods[i].Namespace)] = egressSNAT
+	}
+
+	service.Lock()
+	defer service.Unlock()
+	service.egressSNATPods = egressSNATPods
+}
+
+// egressSNATFor returns the egress SNAT of the Pod, or nil to leave it as configured in the network config.
+func (service *HTTPRestService) egressSNATFor(podInfo cns.PodInfo) *cns.EgressSNAT {
+	if podInfo == nil {
+		return nil
+	}
+
+	service.RLock()
+	defer service.RUnlock()
+	if egressSNAT, ok := service.egressSNATPods[stickyIPKey(podInfo.Name(), podInfo.Namespace())]; ok {
+		return &egressSNAT
+	}
+	if egressSNAT, ok := service.egressSNATNamespaces[podInfo.Namespace()]; ok {
+		return &egressSNAT
+	}
+	return nil
+}
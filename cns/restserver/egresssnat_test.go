@@ -0,0 +1,90 @@
+package restserver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-container-networking/cns"
+	"github.com/Azure/azure-container-networking/cns/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func egressSNATPod(name, namespace string, annotations map[string]string) v1.Pod {
+	return v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Annotations: annotations}}
+}
+
+func TestEgressSNATFor(t *testing.T) {
+	svc := getTestService()
+	svc.SetEgressSNATNamespaces(map[string]cns.EgressSNAT{
+		testPod1Info.Namespace(): {Mode: cns.EgressSNATNone},
+		testPod2Info.Namespace(): {Mode: cns.EgressSNATSecondaryIP, IPAddress: "10.240.0.10"},
+		testPod3Info.Namespace(): {Mode: cns.EgressSNATSecondaryIP},
+	})
+	svc.EgressSNATPodListener([]v1.Pod{
+		egressSNATPod(testPod2Info.Name(), testPod2Info.Namespace(), map[string]string{EgressSNATAnnotation: cns.EgressSNATNodeIP}),
+		egressSNATPod(testPod3Info.Name(), testPod3Info.Namespace(), map[string]string{EgressSNATAnnotation: "bogus"}),
+		egressSNATPod("other", testPod1Info.Namespace(), map[string]string{
+			EgressSNATAnnotation:   cns.EgressSNATSecondaryIP,
+			EgressSNATIPAnnotation: "fd00::10",
+		}),
+	})
+
+	tests := []struct {
+		name    string
+		podInfo cns.PodInfo
+		want    *cns.EgressSNAT
+	}{
+		{
+			name:    "namespace egress snat",
+			podInfo: testPod1Info,
+			want:    &cns.EgressSNAT{Mode: cns.EgressSNATNone},
+		},
+		{
+			name:    "pod annotation takes precedence over namespace",
+			podInfo: testPod2Info,
+			want:    &cns.EgressSNAT{Mode: cns.EgressSNATNodeIP},
+		},
+		{
+			name:    "invalid annotation and namespace are ignored",
+			podInfo: testPod3Info,
+		},
+		{
+			name:    "secondary ip annotation",
+			podInfo: cns.NewPodInfo("abc-eth0", "abc", "other", testPod1Info.Namespace()),
+			want:    &cns.EgressSNAT{Mode: cns.EgressSNATSecondaryIP, IPAddress: "fd00::10"},
+		},
+		{
+			name:    "unconfigured namespace",
+			podInfo: cns.NewPodInfo("abc-eth0", "abc", "other", "default"),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, svc.egressSNATFor(tt.podInfo))
+		})
+	}
+}
+
+func TestRequestIPConfigsReturnsEgressSNAT(t *testing.T) {
+	svc := getTestService()
+	ipconfigs := map[string]cns.IPConfigurationStatus{
+		testIPID1: NewPodState(testIP1, testIPID1, testNCID, types.Available, 0),
+	}
+	require.NoError(t, UpdatePodIPConfigState(t, svc, ipconfigs, testNCID))
+	svc.EgressSNATPodListener([]v1.Pod{
+		egressSNATPod(testPod1Info.Name(), testPod1Info.Namespace(), map[string]string{EgressSNATAnnotation: cns.EgressSNATNone}),
+	})
+
+	req := cns.IPConfigsRequest{
+		PodInterfaceID:   testPod1Info.InterfaceID(),
+		InfraContainerID: testPod1Info.InfraContainerID(),
+	}
+	req.OrchestratorContext, _ = testPod1Info.OrchestratorContext()
+	resp, err := svc.requestIPConfigHandlerHelper(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, &cns.EgressSNAT{Mode: cns.EgressSNATNone}, resp.EgressSNAT)
+}
@@ -0,0 +1,266 @@
+package restserver
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/Azure/azure-container-networking/cns"
+	"github.com/Azure/azure-container-networking/cns/events"
+	"github.com/Azure/azure-container-networking/cns/logger"
+	"github.com/Azure/azure-container-networking/netlink"
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// endpointDriftDebounce is how long the drift watcher waits for further changes before checking the endpoints,
+	// so that a burst of changes, such as the deletion of a pod, is checked once.
+	endpointDriftDebounce = time.Second
+	// endpointDriftResubscribeDelay is how long the drift watcher waits before subscribing again after its
+	// subscription to the link and route changes ended.
+	endpointDriftResubscribeDelay = 5 * time.Second
+	// endpointDriftConfirmDelay is how long the drift watcher waits before checking drift found by a check again, as
+	// drift is only reported once two checks in a row find it.
+	endpointDriftConfirmDelay = 5 * time.Second
+
+	// Modes of the CNI network of an endpoint, as recorded by stateless CNI. Only transparent endpoints have host
+	// routes to the pod IPs, and transparent-vlan endpoints have their veth in the vnet namespace instead of the host's.
+	networkModeTransparent     = "transparent"
+	networkModeTransparentVlan = "transparent-vlan"
+)
+
+// endpointDrift is a host veth or host route of an endpoint in the CNS endpoint state which is missing from the node.
+type endpointDrift struct {
+	events.EndpointEvent
+	// route is the missing host route, nil for a missing host veth.
+	route *netlink.Route
+}
+
+func (d *endpointDrift) key() string {
+	return d.ContainerID + "/" + d.Resource + "/" + d.Name
+}
+
+// hostEndpoint is the host side of an endpoint interface in the CNS endpoint state.
+type hostEndpoint struct {
+	event     events.EndpointEvent
+	mode      string
+	addresses []net.IPNet
+}
+
+// hostEndpoints returns the endpoint interfaces in the CNS endpoint state which have a host veth.
+func (service *HTTPRestService) hostEndpoints() []hostEndpoint {
+	service.RLock()
+	defer service.RUnlock()
+
+	var endpoints []hostEndpoint
+	for containerID, endpointInfo := range service.EndpointState {
+		for ifName, ipInfo := range endpointInfo.IfnameToIPMap {
+			// Delegated nics are moved into the pod wholesale and have no host side.
+			if ipInfo.HostVethName == "" || ipInfo.NICType == cns.DelegatedVMNIC || ipInfo.NetworkMode == networkModeTransparentVlan {
+				continue
+			}
+			ep := hostEndpoint{
+				event: events.EndpointEvent{
+					ContainerID:  containerID,
+					PodName:      endpointInfo.PodName,
+					PodNamespace: endpointInfo.PodNamespace,
+					IfName:       ifName,
+					HostIfName:   ipInfo.HostVethName,
+				},
+				mode: ipInfo.NetworkMode,
+			}
+			ep.addresses = append(ep.addresses, ipInfo.IPv4...)
+			ep.addresses = append(ep.addresses, ipInfo.IPv6...)
+			endpoints = append(endpoints, ep)
+		}
+	}
+	return endpoints
+}
+
+// checkEndpointDrift returns the host veths, and the host routes to the pod IPs through them of transparent endpoints, as
+// set up by the transparent endpoint client, of the endpoints in the CNS endpoint state which are missing from the node.
+func (service *HTTPRestService) checkEndpointDrift(nl netlink.NetlinkInterface) ([]endpointDrift, error) {
+	endpoints := service.hostEndpoints()
+	if len(endpoints) == 0 {
+		return nil, nil
+	}
+
+	routes := map[string]bool{}
+	for _, family := range []int{unix.AF_INET, unix.AF_INET6} {
+		familyRoutes, err := nl.GetIPRoute(&netlink.Route{Family: family})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list host routes")
+		}
+		for _, route := range familyRoutes {
+			if route.Dst != nil {
+				routes[route.Dst.String()+"/"+strconv.Itoa(route.LinkIndex)] = true
+			}
+		}
+	}
+
+	var drifts []endpointDrift
+	for i := range endpoints {
+		ep := &endpoints[i]
+		link, err := nl.GetLinkByName(ep.event.HostIfName)
+		if errors.Is(err, unix.ENODEV) {
+			drift := endpointDrift{EndpointEvent: ep.event}
+			drift.Resource = events.EndpointResourceHostInterface
+			drift.Name = ep.event.HostIfName
+			drift.Reason = "host veth not found"
+			drifts = append(drifts, drift)
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get host veth %s", ep.event.HostIfName)
+		}
+
+		if ep.mode != networkModeTransparent {
+			continue
+		}
+		linkIndex := link.Info().Index
+		for _, addr := range ep.addresses {
+			family, bits := unix.AF_INET, 32
+			if addr.IP.To4() == nil {
+				family, bits = unix.AF_INET6, 128
+			}
+			dst := &net.IPNet{IP: addr.IP, Mask: net.CIDRMask(bits, bits)}
+			if routes[dst.String()+"/"+strconv.Itoa(linkIndex)] {
+				continue
+			}
+			drift := endpointDrift{
+				EndpointEvent: ep.event,
+				route:         &netlink.Route{Family: family, Dst: dst, LinkIndex: linkIndex, Scope: netlink.RT_SCOPE_LINK},
+			}
+			drift.Resource = events.EndpointResourceHostRoute
+			drift.Name = dst.String()
+			drift.Reason = "route via " + ep.event.HostIfName + " not found"
+			drifts = append(drifts, drift)
+		}
+	}
+	return drifts, nil
+}
+
+// endpointDriftWatcher reports the drift of the endpoints in the CNS endpoint state from the node.
+type endpointDriftWatcher struct {
+	service  *HTTPRestService
+	nl       netlink.NetlinkInterface
+	repair   bool
+	debounce time.Duration
+	confirm  time.Duration
+	// found is the drift found by the previous check. Drift is only acted on once two checks in a row find it, so
+	// that an endpoint whose host side is torn down or set up ahead of its state isn't reported.
+	found map[string]bool
+	// reported is the drift reported by the previous checks, each drift is reported once until it goes away.
+	reported map[string]bool
+	// recheck fires when drift found by the last check is due to be checked again.
+	recheck <-chan time.Time
+}
+
+// check reports new drift of the endpoints which was also found by the previous check, and adds back missing host
+// routes if the watcher repairs drift.
+func (w *endpointDriftWatcher) check() {
+	drifts, err := w.service.checkEndpointDrift(w.nl)
+	if err != nil {
+		logger.Errorf("[endpointDrift] Failed to check endpoints: %v", err)
+		return
+	}
+
+	found := make(map[string]bool, len(drifts))
+	reported := make(map[string]bool, len(drifts))
+	unconfirmed := false
+	for i := range drifts {
+		drift := &drifts[i]
+		key := drift.key()
+		found[key] = true
+		if !w.found[key] {
+			unconfirmed = true
+			continue
+		}
+
+		if w.repair && drift.route != nil {
+			if err := w.nl.AddIPRoute(drift.route); err == nil {
+				logger.Printf("[endpointDrift] Restored host route %s of endpoint %s", drift.Name, drift.ContainerID)
+				w.service.publishEvent(events.Event{Type: events.EndpointDriftRepaired, Endpoint: &drift.EndpointEvent})
+				continue
+			}
+			logger.Errorf("[endpointDrift] Failed to restore host route %s of endpoint %s: %v", drift.Name, drift.ContainerID, err)
+		}
+
+		reported[key] = true
+		if w.reported[key] {
+			continue
+		}
+		logger.Warnf("[endpointDrift] Endpoint %s of pod %s/%s drifted, %s %s: %s", drift.ContainerID,
+			drift.PodNamespace, drift.PodName, drift.Resource, drift.Name, drift.Reason)
+		w.service.publishEvent(events.Event{Type: events.EndpointDriftDetected, Endpoint: &drift.EndpointEvent})
+	}
+	w.found = found
+	w.reported = reported
+
+	w.recheck = nil
+	if unconfirmed {
+		w.recheck = time.After(w.confirm)
+	}
+}
+
+// watch checks the endpoints shortly after links or routes are deleted and on every resync, until ctx is done or the
+// subscription ends.
+func (w *endpointDriftWatcher) watch(ctx context.Context, changes <-chan *netlink.Event, resync <-chan time.Time) {
+	var debounce <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case change, ok := <-changes:
+			if !ok {
+				return
+			}
+			// Added or changed links and routes cannot make an endpoint drift.
+			if change.Deleted && debounce == nil {
+				debounce = time.After(w.debounce)
+			}
+		case <-debounce:
+			debounce = nil
+			w.check()
+		case <-w.recheck:
+			w.check()
+		case <-resync:
+			w.check()
+		}
+	}
+}
+
+// WatchEndpointDrift checks the host veths and host routes of the endpoints in the CNS endpoint state against the
+// node whenever links or routes are deleted, and every resyncInterval, until ctx is done. Drift which lasts for two
+// checks in a row is logged and published as events. If repair is set missing host routes are added back; a missing host veth is only reported,
+// as it is recreated with the pod.
+func (service *HTTPRestService) WatchEndpointDrift(ctx context.Context, nl netlink.NetlinkInterface, repair bool, resyncInterval time.Duration) {
+	w := &endpointDriftWatcher{service: service, nl: nl, repair: repair, debounce: endpointDriftDebounce, confirm: endpointDriftConfirmDelay}
+
+	resync := time.NewTicker(resyncInterval)
+	defer resync.Stop()
+
+	for {
+		changes := make(chan *netlink.Event, 256) //nolint:gomnd // room for a burst of changes
+		done := make(chan struct{})
+		err := nl.Subscribe(changes, done, netlink.RTNLGRP_LINK, netlink.RTNLGRP_IPV4_ROUTE, netlink.RTNLGRP_IPV6_ROUTE)
+		if err != nil {
+			logger.Errorf("[endpointDrift] Failed to subscribe to link and route changes: %v", err)
+		}
+
+		// Check what changed while not subscribed.
+		w.check()
+		if err == nil {
+			w.watch(ctx, changes, resync.C)
+			close(done)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(endpointDriftResubscribeDelay):
+		}
+	}
+}
@@ -0,0 +1,233 @@
+//go:build linux
+// +build linux
+
+package restserver
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-container-networking/cns"
+	"github.com/Azure/azure-container-networking/cns/events"
+	"github.com/Azure/azure-container-networking/netlink"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+)
+
+// fakeHostNetlink holds the host veths and routes of the node.
+type fakeHostNetlink struct {
+	*netlink.MockNetlink
+	links   map[string]int
+	routes  []*netlink.Route
+	changes chan<- *netlink.Event
+}
+
+func (f *fakeHostNetlink) GetLinkByName(name string) (netlink.Link, error) {
+	index, ok := f.links[name]
+	if !ok {
+		return nil, unix.ENODEV
+	}
+	return &netlink.LinkInfo{Name: name, Index: index}, nil
+}
+
+func (f *fakeHostNetlink) GetIPRoute(filter *netlink.Route) ([]*netlink.Route, error) {
+	var routes []*netlink.Route
+	for _, route := range f.routes {
+		if route.Family == filter.Family {
+			routes = append(routes, route)
+		}
+	}
+	return routes, nil
+}
+
+func (f *fakeHostNetlink) AddIPRoute(route *netlink.Route) error {
+	f.routes = append(f.routes, route)
+	return nil
+}
+
+func (f *fakeHostNetlink) Subscribe(changes chan<- *netlink.Event, _ <-chan struct{}, _ ...int) error {
+	f.changes = changes
+	return nil
+}
+
+func driftTestService() *HTTPRestService {
+	svc := getTestService()
+	svc.EndpointState = map[string]*EndpointInfo{
+		"container1": {
+			PodName:      "pod1",
+			PodNamespace: "default",
+			IfnameToIPMap: map[string]*IPInfo{
+				"eth0": {
+					IPv4:         []net.IPNet{{IP: net.ParseIP("10.0.0.4"), Mask: net.CIDRMask(24, 32)}},
+					IPv6:         []net.IPNet{{IP: net.ParseIP("fd00::4"), Mask: net.CIDRMask(64, 128)}},
+					HostVethName: "azv1",
+					NICType:      cns.InfraNIC,
+					NetworkMode:  networkModeTransparent,
+				},
+				"eth1": {HostVethName: "azv9", NICType: cns.DelegatedVMNIC},
+			},
+		},
+		"container2": {
+			PodName:       "pod2",
+			PodNamespace:  "default",
+			IfnameToIPMap: map[string]*IPInfo{"eth0": {HostVethName: "azv2"}},
+		},
+		// bridged endpoints have no host routes, and transparent-vlan ones have their veth in the vnet namespace
+		"container3": {
+			PodName:      "pod3",
+			PodNamespace: "default",
+			IfnameToIPMap: map[string]*IPInfo{
+				"eth0": {
+					IPv4:         []net.IPNet{{IP: net.ParseIP("10.0.0.6"), Mask: net.CIDRMask(24, 32)}},
+					HostVethName: "azv3",
+					NetworkMode:  "bridge",
+				},
+			},
+		},
+		"container4": {
+			PodName:      "pod4",
+			PodNamespace: "default",
+			IfnameToIPMap: map[string]*IPInfo{
+				"eth0": {
+					IPv4:         []net.IPNet{{IP: net.ParseIP("10.0.0.7"), Mask: net.CIDRMask(24, 32)}},
+					HostVethName: "azv4",
+					NetworkMode:  networkModeTransparentVlan,
+				},
+			},
+		},
+	}
+	return svc
+}
+
+func hostRoute(cidr string, linkIndex int) *netlink.Route {
+	_, dst, _ := net.ParseCIDR(cidr)
+	family := unix.AF_INET
+	if dst.IP.To4() == nil {
+		family = unix.AF_INET6
+	}
+	return &netlink.Route{Family: family, Dst: dst, LinkIndex: linkIndex}
+}
+
+func TestCheckEndpointDrift(t *testing.T) {
+	svc := driftTestService()
+	nl := &fakeHostNetlink{
+		MockNetlink: netlink.NewMockNetlink(false, ""),
+		links:       map[string]int{"azv1": 5, "azv3": 6},
+		routes:      []*netlink.Route{hostRoute("10.0.0.4/32", 5), hostRoute("fd00::4/128", 6)},
+	}
+
+	drifts, err := svc.checkEndpointDrift(nl)
+	require.NoError(t, err)
+	require.Len(t, drifts, 2)
+	byResource := map[string]endpointDrift{}
+	for _, d := range drifts {
+		byResource[d.Resource] = d
+	}
+
+	// the v6 route goes through another interface
+	route := byResource[events.EndpointResourceHostRoute]
+	assert.Equal(t, "container1", route.ContainerID)
+	assert.Equal(t, "pod1", route.PodName)
+	assert.Equal(t, "fd00::4/128", route.Name)
+	assert.Equal(t, hostRoute("fd00::4/128", 5).Dst, route.route.Dst)
+	assert.Equal(t, 5, route.route.LinkIndex)
+
+	link := byResource[events.EndpointResourceHostInterface]
+	assert.Equal(t, "container2", link.ContainerID)
+	assert.Equal(t, "azv2", link.Name)
+	assert.Nil(t, link.route)
+}
+
+func TestEndpointDriftWatcherCheck(t *testing.T) {
+	svc := driftTestService()
+	nl := &fakeHostNetlink{
+		MockNetlink: netlink.NewMockNetlink(false, ""),
+		links:       map[string]int{"azv1": 5, "azv2": 7, "azv3": 9},
+		routes:      []*netlink.Route{hostRoute("10.0.0.4/32", 5), hostRoute("fd00::4/128", 5)},
+	}
+	w := &endpointDriftWatcher{service: svc, nl: nl, confirm: time.Hour}
+	start := svc.EventLog().Head()
+
+	w.check()
+	assert.Empty(t, svc.EventLog().Since(start).Events)
+	assert.Nil(t, w.recheck)
+
+	// drift is reported once the next check finds it too, once while it lasts, and again when it comes back
+	delete(nl.links, "azv2")
+	w.check()
+	assert.Empty(t, svc.EventLog().Since(start).Events)
+	assert.NotNil(t, w.recheck)
+	w.check()
+	w.check()
+	page := svc.EventLog().Since(start)
+	require.Equal(t, []events.Type{events.EndpointDriftDetected}, eventTypes(page.Events))
+	assert.Equal(t, "azv2", page.Events[0].Endpoint.HostIfName)
+
+	nl.links["azv2"] = 8
+	w.check()
+	delete(nl.links, "azv2")
+	w.check()
+	w.check()
+	page = svc.EventLog().Since(page.Cursor)
+	require.Equal(t, []events.Type{events.EndpointDriftDetected}, eventTypes(page.Events))
+
+	// drift which goes away before the next check is not reported
+	nl.links["azv2"] = 8
+	w.check()
+	delete(nl.links, "azv2")
+	w.check()
+	nl.links["azv2"] = 8
+	w.check()
+	assert.Empty(t, svc.EventLog().Since(page.Cursor).Events)
+
+	// missing routes are added back when repairing
+	nl.routes = nl.routes[:1]
+	w.repair = true
+	w.check()
+	assert.Empty(t, svc.EventLog().Since(page.Cursor).Events)
+	w.check()
+	page = svc.EventLog().Since(page.Cursor)
+	require.Equal(t, []events.Type{events.EndpointDriftRepaired}, eventTypes(page.Events))
+	assert.Equal(t, "fd00::4/128", page.Events[0].Endpoint.Name)
+	require.Len(t, nl.routes, 2)
+	assert.Equal(t, netlink.RT_SCOPE_LINK, nl.routes[1].Scope)
+
+	w.check()
+	assert.Empty(t, svc.EventLog().Since(page.Cursor).Events)
+}
+
+func TestEndpointDriftWatcherWatch(t *testing.T) {
+	svc := driftTestService()
+	nl := &fakeHostNetlink{
+		MockNetlink: netlink.NewMockNetlink(false, ""),
+		links:       map[string]int{"azv1": 5, "azv3": 6},
+		routes:      []*netlink.Route{hostRoute("10.0.0.4/32", 5), hostRoute("fd00::4/128", 5)},
+	}
+	w := &endpointDriftWatcher{service: svc, nl: nl, debounce: time.Millisecond, confirm: time.Millisecond}
+	start := svc.EventLog().Head()
+
+	changes := make(chan *netlink.Event, 2)
+	stopped := make(chan struct{})
+	go func() {
+		w.watch(context.Background(), changes, nil)
+		close(stopped)
+	}()
+
+	// added links don't trigger a check
+	changes <- &netlink.Event{Type: netlink.EVENT_LINK, Link: &netlink.LinkInfo{Name: "azv3"}}
+	time.Sleep(20 * time.Millisecond)
+	assert.Empty(t, svc.EventLog().Since(start).Events)
+
+	changes <- &netlink.Event{Type: netlink.EVENT_LINK, Deleted: true, Link: &netlink.LinkInfo{Name: "azv3"}}
+	require.Eventually(t, func() bool {
+		return len(svc.EventLog().Since(start).Events) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	// container2 lost its host veth
+	assert.Equal(t, "azv2", svc.EventLog().Since(start).Events[0].Endpoint.HostIfName)
+
+	close(changes)
+	<-stopped
+}
@@ -0,0 +1,14 @@
+package restserver
+
+import (
+	"context"
+	"time"
+
+	"github.com/Azure/azure-container-networking/cns/logger"
+	"github.com/Azure/azure-container-networking/netlink"
+)
+
+// WatchEndpointDrift is not supported on Windows, where endpoints have no host veth.
+func (service *HTTPRestService) WatchEndpointDrift(_ context.Context, _ netlink.NetlinkInterface, _ bool, _ time.Duration) {
+	logger.Printf("[endpointDrift] Endpoint drift watcher is not supported on Windows")
+}
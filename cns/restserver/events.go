@@ -0,0 +1,207 @@
+package restserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-container-networking/cns"
+	"github.com/Azure/azure-container-networking/cns/events"
+	"github.com/Azure/azure-container-networking/cns/logger"
+	"github.com/Azure/azure-container-networking/cns/types"
+	"github.com/Azure/azure-container-networking/common"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultEventsWait = 30 * time.Second
+	maxEventsWait     = 5 * time.Minute
+	// eventsKeepAlive is how often an idle event stream sends a comment so that proxies don't close it.
+	eventsKeepAlive = 15 * time.Second
+	eventStreamType = "text/event-stream"
+)
+
+// GetEventsResponse is the long-poll response of the events API.
+type GetEventsResponse struct {
+	Response Response `json:"response"`
+	events.Page
+}
+
+// EventLog returns the log of IP, network container, pool scaling and endpoint events published by CNS.
+func (service *HTTPRestService) EventLog() *events.Log {
+	return service.eventLog
+}
+
+// publishEvent adds the event to the event log, if the service has one.
+func (service *HTTPRestService) publishEvent(e events.Event) { //nolint:gocritic // events are passed by value
+	if service.eventLog == nil {
+		return
+	}
+	service.eventLog.Publish(e)
+}
+
+// publishIPEvent records the transition of ipConfig out of the previous state, with the pod that the IP was
+// assigned to or released from, if any. Caller must hold the service lock.
+func (service *HTTPRestService) publishIPEvent(previous types.IPState, podInfo cns.PodInfo, ipConfig *cns.IPConfigurationStatus) {
+	e := &events.IPEvent{
+		ID:            ipConfig.ID,
+		NCID:          ipConfig.NCID,
+		IPAddress:     ipConfig.IPAddress,
+		PreviousState: previous,
+		State:         ipConfig.GetState(),
+	}
+	if podInfo != nil {
+		e.PodName = podInfo.Name()
+		e.PodNamespace = podInfo.Namespace()
+	}
+	service.publishEvent(events.Event{Type: events.IPStateChanged, IP: e})
+}
+
+// publishIPRemovedEvent records that ipConfig was removed from the pool. Caller must hold the service lock.
+func (service *HTTPRestService) publishIPRemovedEvent(ipConfig *cns.IPConfigurationStatus) {
+	service.publishEvent(events.Event{
+		Type: events.IPRemoved,
+		IP: &events.IPEvent{
+			ID:            ipConfig.ID,
+			NCID:          ipConfig.NCID,
+			IPAddress:     ipConfig.IPAddress,
+			PreviousState: ipConfig.GetState(),
+		},
+	})
+}
+
+// publishEndpointStateEvent records that the endpoint of the container was added to, updated in or removed from
+// the endpoint state. Caller must hold the service lock.
+func (service *HTTPRestService) publishEndpointStateEvent(containerID, podName, podNamespace string) {
+	service.publishEvent(events.Event{
+		Type: events.EndpointStateChanged,
+		Endpoint: &events.EndpointEvent{
+			ContainerID:  containerID,
+			PodName:      podName,
+			PodNamespace: podNamespace,
+		},
+	})
+}
+
+func (service *HTTPRestService) publishNCEvent(t events.Type, ncID string, req *cns.CreateNetworkContainerRequest) {
+	e := &events.NetworkContainerEvent{ID: ncID}
+	if req != nil {
+		e.Type = string(req.NetworkContainerType)
+		e.Version = req.Version
+	}
+	service.publishEvent(events.Event{Type: t, NetworkContainer: e})
+}
+
+// eventsCursor returns the cursor the client wants to resume from: the cursor query parameter, or the
+// Last-Event-ID header sent by reconnecting event stream clients. Without either, only new events are returned.
+func (service *HTTPRestService) eventsCursor(r *http.Request) (uint64, error) {
+	raw := r.URL.Query().Get("cursor")
+	if raw == "" {
+		raw = r.Header.Get("Last-Event-ID")
+	}
+	if raw == "" {
+		return service.eventLog.Head(), nil
+	}
+	cursor, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid cursor %q", raw)
+	}
+	return cursor, nil
+}
+
+// HandleEvents serves the CNS event log.
+// Clients which accept text/event-stream receive the events as Server-Sent Events, with the cursor of each
+// event as its id so that a reconnecting client resumes with Last-Event-ID. All other clients long-poll: the
+// request blocks for up to the wait query parameter until there are events after the cursor, and returns them
+// with the cursor to pass on the next request.
+func (service *HTTPRestService) HandleEvents(w http.ResponseWriter, r *http.Request) {
+	cursor, err := service.eventsCursor(r)
+	if err != nil {
+		resp := GetEventsResponse{Response: Response{ReturnCode: types.InvalidParameter, Message: err.Error()}}
+		err = common.Encode(w, &resp)
+		logger.Response(service.Name, resp, resp.Response.ReturnCode, err)
+		return
+	}
+
+	if strings.Contains(r.Header.Get("Accept"), eventStreamType) {
+		service.streamEvents(w, r, cursor)
+		return
+	}
+
+	wait := defaultEventsWait
+	if raw := r.URL.Query().Get("wait"); raw != "" {
+		if wait, err = time.ParseDuration(raw); err != nil {
+			resp := GetEventsResponse{Response: Response{ReturnCode: types.InvalidParameter, Message: fmt.Sprintf("invalid wait %q: %v", raw, err)}}
+			err = common.Encode(w, &resp)
+			logger.Response(service.Name, resp, resp.Response.ReturnCode, err)
+			return
+		}
+	}
+	if wait > maxEventsWait {
+		wait = maxEventsWait
+	}
+
+	var resp GetEventsResponse
+	if wait <= 0 {
+		resp.Page = service.eventLog.Since(cursor)
+	} else {
+		ctx, cancel := context.WithTimeout(r.Context(), wait)
+		defer cancel()
+		// a timeout just means that there were no new events.
+		resp.Page, _ = service.eventLog.Wait(ctx, cursor)
+	}
+	err = common.Encode(w, &resp)
+	if err != nil {
+		logger.Errorf("[Azure CNS] Failed to encode events response: %v", err)
+	}
+}
+
+func (service *HTTPRestService) streamEvents(w http.ResponseWriter, r *http.Request, cursor uint64) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusNotImplemented)
+		return
+	}
+	w.Header().Set("Content-Type", eventStreamType)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		ctx, cancel := context.WithTimeout(r.Context(), eventsKeepAlive)
+		page, err := service.eventLog.Wait(ctx, cursor)
+		cancel()
+		if err != nil {
+			if r.Context().Err() != nil {
+				return
+			}
+			if _, err := fmt.Fprint(w, ": keepalive\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+			continue
+		}
+		if page.Truncated {
+			if _, err := fmt.Fprint(w, "event: truncated\ndata: {}\n\n"); err != nil {
+				return
+			}
+		}
+		for i := range page.Events {
+			data, err := json.Marshal(page.Events[i])
+			if err != nil {
+				logger.Errorf("[Azure CNS] Failed to marshal event %d: %v", page.Events[i].Cursor, err)
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", page.Events[i].Cursor, page.Events[i].Type, data); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+		cursor = page.Cursor
+	}
+}
@@ -0,0 +1,91 @@
+package restserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/Azure/azure-container-networking/cns"
+	"github.com/Azure/azure-container-networking/cns/events"
+	"github.com/Azure/azure-container-networking/cns/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func eventTypes(evs []events.Event) []events.Type {
+	out := make([]events.Type, len(evs))
+	for i := range evs {
+		out[i] = evs[i].Type
+	}
+	return out
+}
+
+func TestIPAndNCEvents(t *testing.T) {
+	svc := getTestService()
+	start := svc.EventLog().Head()
+
+	ipconfigs := map[string]cns.IPConfigurationStatus{
+		testIPID1: NewPodState(testIP1, testIPID1, testNCID, types.Available, 0),
+	}
+	require.NoError(t, UpdatePodIPConfigState(t, svc, ipconfigs, testNCID))
+
+	page := svc.EventLog().Since(start)
+	require.Equal(t, []events.Type{events.IPStateChanged, events.NetworkContainerCreated}, eventTypes(page.Events))
+	assert.Equal(t, testIPID1, page.Events[0].IP.ID)
+	assert.Equal(t, types.Available, page.Events[0].IP.State)
+	assert.Empty(t, page.Events[0].IP.PreviousState)
+	assert.Equal(t, testNCID, page.Events[1].NetworkContainer.ID)
+
+	requestIPForPod(t, testPod1Info)
+	require.NoError(t, svc.releaseIPConfigs(testPod1Info))
+
+	page = svc.EventLog().Since(page.Cursor)
+	require.Equal(t, []events.Type{events.IPStateChanged, events.IPStateChanged}, eventTypes(page.Events))
+	assigned, released := page.Events[0].IP, page.Events[1].IP
+	assert.Equal(t, types.Available, assigned.PreviousState)
+	assert.Equal(t, types.Assigned, assigned.State)
+	assert.Equal(t, testPod1Info.Name(), assigned.PodName)
+	assert.Equal(t, types.Assigned, released.PreviousState)
+	assert.Equal(t, types.Available, released.State)
+	// the release names the pod the IP was taken from
+	assert.Equal(t, testPod1Info.Name(), released.PodName)
+
+	assert.Equal(t, types.Success, svc.DeleteNetworkContainerInternal(cns.DeleteNetworkContainerRequest{NetworkContainerid: testNCID}))
+	page = svc.EventLog().Since(page.Cursor)
+	require.Equal(t, []events.Type{events.NetworkContainerDeleted}, eventTypes(page.Events))
+	assert.Equal(t, testNCID, page.Events[0].NetworkContainer.ID)
+}
+
+func TestHandleEventsLongPoll(t *testing.T) {
+	svc := getTestService()
+	start := svc.EventLog().Head()
+	svc.publishNCEvent(events.NetworkContainerCreated, testNCID, nil)
+
+	get := func(query string) GetEventsResponse {
+		req := httptest.NewRequest(http.MethodGet, cns.PathEvents+query, http.NoBody)
+		w := httptest.NewRecorder()
+		svc.HandleEvents(w, req)
+		var resp GetEventsResponse
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+		return resp
+	}
+
+	resp := get("?wait=0s&cursor=" + strconv.FormatUint(start, 10))
+	assert.Equal(t, types.Success, resp.Response.ReturnCode)
+	require.Len(t, resp.Events, 1)
+	assert.Equal(t, events.NetworkContainerCreated, resp.Events[0].Type)
+	assert.Equal(t, resp.Events[0].Cursor, resp.Cursor)
+
+	// without a cursor only new events are returned
+	resp = get("?wait=0s")
+	assert.Empty(t, resp.Events)
+	assert.Equal(t, svc.EventLog().Head(), resp.Cursor)
+
+	resp = get("?wait=0s&cursor=1")
+	assert.True(t, resp.Truncated)
+
+	resp = get("?cursor=notanumber")
+	assert.Equal(t, types.InvalidParameter, resp.Response.ReturnCode)
+}
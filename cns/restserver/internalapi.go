@@ -17,6 +17,7 @@ import (
 	"time"
 
 	"github.com/Azure/azure-container-networking/cns"
+	"github.com/Azure/azure-container-networking/cns/events"
 	"github.com/Azure/azure-container-networking/cns/logger"
 	"github.com/Azure/azure-container-networking/cns/types"
 	"github.com/Azure/azure-container-networking/common"
@@ -447,7 +448,7 @@ func (service *HTTPRestService) DeleteNetworkContainerInternal(
 	req cns.DeleteNetworkContainerRequest,
 ) types.ResponseCode {
 	ncid := req.NetworkContainerid
-	_, exist := service.getNetworkContainerDetails(ncid)
+	containerStatus, exist := service.getNetworkContainerDetails(ncid)
 	if !exist {
 		logger.Printf("network container for id %v doesn't exist", ncid)
 		return types.Success
@@ -457,6 +458,7 @@ func (service *HTTPRestService) DeleteNetworkContainerInternal(
 	defer service.Unlock()
 	if service.state.ContainerStatus != nil {
 		delete(service.state.ContainerStatus, ncid)
+		service.publishNCEvent(events.NetworkContainerDeleted, ncid, &containerStatus.CreateNetworkContainerRequest)
 	}
 
 	if service.state.ContainerIDByOrchestratorContext != nil {
@@ -492,7 +494,7 @@ func (service *HTTPRestService) MustEnsureNoStaleNCs(validNCIDs []string) {
 	}
 
 	mutated := false
-	for ncID := range service.state.ContainerStatus {
+	for ncID, containerStatus := range service.state.ContainerStatus { //nolint:gocritic // copy is fine
 		if _, ok := valid[ncID]; !ok {
 			// stale NCs with assigned IPs are an unexpected CNS state which we need to alert on.
 			if assignedIPs, hasAssignedIPs := ncIDToAssignedIPs[ncID]; hasAssignedIPs {
@@ -503,6 +505,7 @@ func (service *HTTPRestService) MustEnsureNoStaleNCs(validNCIDs []string) {
 
 			logger.Errorf("[Azure CNS] Found stale NC ID %s in CNS state. Removing...", ncID)
 			delete(service.state.ContainerStatus, ncID)
+			service.publishNCEvent(events.NetworkContainerDeleted, ncID, &containerStatus.CreateNetworkContainerRequest)
 			mutated = true
 		}
 	}
@@ -110,7 +110,8 @@ func (service *HTTPRestService) requestIPConfigHandlerHelper(ctx context.Context
 		Response: cns.Response{
 			ReturnCode: types.Success,
 		},
-		PodIPInfo: podIPInfoResult,
+		PodIPInfo:  podIPInfoResult,
+		EgressSNAT: service.egressSNATFor(podInfo),
 	}, nil
 }
 
@@ -264,17 +265,8 @@ func (service *HTTPRestService) RequestIPConfigHandler(w http.ResponseWriter, r
 	logger.ResponseEx(service.Name+operationName, ipconfigsRequest, reserveResp, reserveResp.Response.ReturnCode, err)
 }
 
-// RequestIPConfigsHandler requests multiple IPConfigs from the CNS state
-func (service *HTTPRestService) RequestIPConfigsHandler(w http.ResponseWriter, r *http.Request) {
-	var ipconfigsRequest cns.IPConfigsRequest
-	err := common.Decode(w, r, &ipconfigsRequest)
-	operationName := "requestIPConfigsHandler"
-	logger.Request(service.Name+operationName, ipconfigsRequest, err)
-	if err != nil {
-		return
-	}
-	var ipConfigsResp *cns.IPConfigsResponse
-
+// RequestIPConfigsHandlerHelper assigns IPConfigs to the pod in the request through the IPConfigsHandlerMiddleware, if set.
+func (service *HTTPRestService) RequestIPConfigsHandlerHelper(ctx context.Context, ipconfigsRequest cns.IPConfigsRequest) (*cns.IPConfigsResponse, error) {
 	// Check if IPConfigsHandlerMiddleware is set
 	if service.IPConfigsHandlerMiddleware != nil {
 		// Wrap the default datapath handlers with the middleware depending on middleware type
@@ -287,11 +279,22 @@ func (service *HTTPRestService) RequestIPConfigsHandler(w http.ResponseWriter, r
 			wrappedHandler = service.IPConfigsHandlerMiddleware.IPConfigsRequestHandlerWrapper(service.requestIPConfigHandlerHelperStandalone, nil)
 		}
 
-		ipConfigsResp, err = wrappedHandler(r.Context(), ipconfigsRequest)
-	} else {
-		ipConfigsResp, err = service.requestIPConfigHandlerHelper(r.Context(), ipconfigsRequest) // nolint:contextcheck // appease linter
+		return wrappedHandler(ctx, ipconfigsRequest)
+	}
+	return service.requestIPConfigHandlerHelper(ctx, ipconfigsRequest)
+}
+
+// RequestIPConfigsHandler requests multiple IPConfigs from the CNS state
+func (service *HTTPRestService) RequestIPConfigsHandler(w http.ResponseWriter, r *http.Request) {
+	var ipconfigsRequest cns.IPConfigsRequest
+	err := common.Decode(w, r, &ipconfigsRequest)
+	operationName := "requestIPConfigsHandler"
+	logger.Request(service.Name+operationName, ipconfigsRequest, err)
+	if err != nil {
+		return
 	}
 
+	ipConfigsResp, err := service.RequestIPConfigsHandlerHelper(r.Context(), ipconfigsRequest)
 	if err != nil {
 		w.Header().Set(cnsReturnCode, ipConfigsResp.Response.ReturnCode.String())
 		err = common.Encode(w, &ipConfigsResp)
@@ -362,6 +365,7 @@ func (service *HTTPRestService) updateEndpointState(ipconfigsRequest cns.IPConfi
 		if err != nil {
 			return fmt.Errorf("failed to write endpoint state to store: %w", err)
 		}
+		service.publishEndpointStateEvent(ipconfigsRequest.InfraContainerID, podInfo.Name(), podInfo.Namespace())
 	}
 	return nil
 }
@@ -502,6 +506,7 @@ func (service *HTTPRestService) removeEndpointState(podInfo cns.PodInfo) error {
 		if err != nil {
 			return fmt.Errorf("failed to write endpoint state to store: %w", err)
 		}
+		service.publishEndpointStateEvent(podInfo.InfraContainerID(), podInfo.Name(), podInfo.Namespace())
 	} else { // will not fail if no endpoint state for infra container id is found
 		logger.Printf("[removeEndpointState] No endpoint state found for infra container %s", podInfo.InfraContainerID())
 	}
@@ -530,7 +535,11 @@ func (service *HTTPRestService) MarkIPAsPendingRelease(totalIpsToRelease int) (m
 	}
 
 	// if not all expected IPs are set to PendingRelease, then check the Available IPs
+	stickyIPs := service.reservedStickyIPsUntransacted(nil)
 	for uuid, existingIpConfig := range service.PodIPConfigState {
+		if _, reserved := stickyIPs[existingIpConfig.IPAddress]; reserved {
+			continue
+		}
 		if existingIpConfig.GetState() == types.Available {
 			updatedIPConfig, err := service.updateIPConfigState(uuid, types.PendingRelease, existingIpConfig.PodInfo)
 			if err != nil {
@@ -575,12 +584,16 @@ func (service *HTTPRestService) MarkNIPsPendingRelease(n int) (map[string]cns.IP
 		}
 	}
 
-	// try to release from Available
+	// try to release from Available, keeping the IPs held for sticky pods
 	availableIPs := make(map[string]cns.IPConfigurationStatus)
+	stickyIPs := service.reservedStickyIPsUntransacted(nil)
 	for uuid, ipConfig := range service.PodIPConfigState { //nolint:gocritic // intentional value copy
 		if n <= 0 {
 			break
 		}
+		if _, reserved := stickyIPs[ipConfig.IPAddress]; reserved {
+			continue
+		}
 		if ipConfig.GetState() == types.Available {
 			updatedIPConfig, err := service.updateIPConfigState(uuid, types.PendingRelease, ipConfig.PodInfo)
 			if err != nil {
@@ -612,9 +625,15 @@ func (service *HTTPRestService) MarkNIPsPendingRelease(n int) (map[string]cns.IP
 func (service *HTTPRestService) updateIPConfigState(ipID string, updatedState types.IPState, podInfo cns.PodInfo) (cns.IPConfigurationStatus, error) {
 	if ipConfig, found := service.PodIPConfigState[ipID]; found {
 		logger.Printf("[updateIPConfigState] Changing IpId [%s] state to [%s], podInfo [%+v]. Current config [%+v]", ipID, updatedState, podInfo, ipConfig)
+		previousState, eventPod := ipConfig.GetState(), podInfo
+		if eventPod == nil {
+			eventPod = ipConfig.PodInfo
+		}
 		ipConfig.SetState(updatedState)
 		ipConfig.PodInfo = podInfo
+		ipConfig.LeaseExpiry = service.ipLeaseExpiryUntransacted(ipID, updatedState, ipConfig.LastStateTransition)
 		service.PodIPConfigState[ipID] = ipConfig
+		service.publishIPEvent(previousState, eventPod, &ipConfig)
 		return ipConfig, nil
 	}
 
@@ -710,6 +729,26 @@ func (service *HTTPRestService) HandleDebugIPAddresses(w http.ResponseWriter, r
 	logger.ResponseEx(service.Name, req, resp, resp.Response.ReturnCode, err)
 }
 
+// HandleDebugPoolMonitor returns the state of the IPAM pool monitor, if CNS is running one.
+func (service *HTTPRestService) HandleDebugPoolMonitor(w http.ResponseWriter, r *http.Request) { //nolint
+	var resp cns.GetPoolMonitorStateResponse
+	if service.poolMonitorState == nil {
+		resp.Response = cns.Response{
+			ReturnCode: types.NotFound,
+			Message:    "CNS is not running an IPAM pool monitor",
+		}
+	} else if snapshot, err := service.poolMonitorState(); err != nil {
+		resp.Response = cns.Response{
+			ReturnCode: types.UnsupportedAPI,
+			Message:    err.Error(),
+		}
+	} else {
+		resp.Snapshot = snapshot
+	}
+	err := common.Encode(w, &resp)
+	logger.Response(service.Name, resp, resp.Response.ReturnCode, err)
+}
+
 // GetAssignedIPConfigs returns a filtered list of IPs which are in
 // Assigned State.
 func (service *HTTPRestService) GetAssignedIPConfigs() []cns.IPConfigurationStatus {
@@ -776,6 +815,11 @@ func (service *HTTPRestService) unassignIPConfig(ipconfig cns.IPConfigurationSta
 func (service *HTTPRestService) releaseIPConfigs(podInfo cns.PodInfo) error {
 	service.Lock()
 	defer service.Unlock()
+	return service.releaseIPConfigsUntransacted(podInfo)
+}
+
+// releaseIPConfigsUntransacted releases all IPs assigned to the passed Pod, does not take a lock.
+func (service *HTTPRestService) releaseIPConfigsUntransacted(podInfo cns.PodInfo) error {
 	ipsToBeReleased := make([]cns.IPConfigurationStatus, 0)
 	logger.Printf("[releaseIPConfigs] Releasing pod with key %s", podInfo.Key())
 	for i, ipID := range service.PodIPIDByPodInterfaceKey[podInfo.Key()] {
@@ -815,6 +859,7 @@ func (service *HTTPRestService) releaseIPConfigs(podInfo cns.PodInfo) error {
 		return fmt.Errorf("[releaseIPConfigs] Failed to release one or more IPs. Not releasing any IPs for pod %+v", podInfo)
 	}
 
+	service.releaseStickyIPsUntransacted(podInfo, ipsToBeReleased)
 	logger.Printf("[releaseIPConfigs] Successfully released all IPs for pod %+v", podInfo)
 	return nil
 }
@@ -831,8 +876,11 @@ func (service *HTTPRestService) MarkExistingIPsAsPendingRelease(pendingIPIDs []s
 			}
 
 			logger.Printf("[MarkExistingIPsAsPending]: Marking IP [%+v] to PendingRelease", ipconfig)
+			previousState := ipconfig.GetState()
 			ipconfig.SetState(types.PendingRelease)
+			ipconfig.LeaseExpiry = service.ipLeaseExpiryUntransacted(id, types.PendingRelease, ipconfig.LastStateTransition)
 			service.PodIPConfigState[id] = ipconfig
+			service.publishIPEvent(previousState, nil, &ipconfig)
 		} else {
 			logger.Errorf("Inconsistent state, ipconfig with ID [%v] marked as pending release, but does not exist in state", id)
 		}
@@ -985,6 +1033,8 @@ func (service *HTTPRestService) AssignAvailableIPConfigs(podInfo cns.PodInfo) ([
 	podIPInfo := make([]cns.PodIpInfo, numOfNCs)
 	// This map is used to store whether or not we have found an available IP from an NC when looping through the pool
 	ipsToAssign := make(map[string]cns.IPConfigurationStatus)
+	// IPs held for the identity of other sticky pods are not handed out
+	stickyIPs := service.reservedStickyIPsUntransacted(podInfo)
 
 	// Searches for available IPs in the pool
 	for _, ipState := range service.PodIPConfigState {
@@ -996,6 +1046,9 @@ func (service *HTTPRestService) AssignAvailableIPConfigs(podInfo cns.PodInfo) ([
 		if ipState.GetState() != types.Available {
 			continue
 		}
+		if _, reserved := stickyIPs[ipState.IPAddress]; reserved {
+			continue
+		}
 		ipsToAssign[ipState.NCID] = ipState
 		// Once one IP per container is found break out of the loop and stop searching
 		if len(ipsToAssign) == numOfNCs {
@@ -1059,12 +1112,29 @@ func requestIPConfigsHelper(service *HTTPRestService, req cns.IPConfigsRequest)
 	}
 
 	if podIPInfo, isExist, err := service.GetExistingIPConfig(podInfo); err != nil || isExist {
+		if isExist {
+			// a repeated ADD for the same pod is proof of life, extend its lease
+			service.renewIPLeases(podInfo)
+		}
 		return podIPInfo, err
 	}
 
 	// if the desired IP configs are not specified, assign any free IPConfigs
 	if len(req.DesiredIPAddresses) == 0 {
-		return service.AssignAvailableIPConfigs(podInfo)
+		// hand a sticky pod back the IPs reserved for its identity when they are still free
+		if reserved := service.stickyIPsForPod(podInfo); len(reserved) > 0 {
+			podIPInfo, err := service.AssignDesiredIPConfigs(podInfo, reserved)
+			if err == nil {
+				service.reserveStickyIPs(podInfo)
+				return podIPInfo, nil
+			}
+			service.recordStickyIPConflicts(podInfo, reserved, err)
+		}
+		podIPInfo, err := service.AssignAvailableIPConfigs(podInfo)
+		if err == nil {
+			service.reserveStickyIPs(podInfo)
+		}
+		return podIPInfo, err
 	}
 
 	if err := validateDesiredIPAddresses(req.DesiredIPAddresses); err != nil {
@@ -1115,38 +1185,66 @@ func (service *HTTPRestService) EndpointHandlerAPI(w http.ResponseWriter, r *htt
 func (service *HTTPRestService) GetEndpointHandler(w http.ResponseWriter, r *http.Request) {
 	logger.Printf("[GetEndpointState] GetEndpoint for %s", r.URL.Path)
 	endpointID := strings.TrimPrefix(r.URL.Path, cns.EndpointPath)
-	endpointInfo, err := service.GetEndpointHelper(endpointID)
-	// Check if the request is valid
+	if endpointID == "" {
+		response := service.GetEndpointsHandlerHelper()
+		w.Header().Set(cnsReturnCode, response.Response.ReturnCode.String())
+		err := common.Encode(w, &response)
+		logger.Response(service.Name, response, response.Response.ReturnCode, err)
+		return
+	}
+	response := service.GetEndpointHandlerHelper(endpointID)
+	w.Header().Set(cnsReturnCode, response.Response.ReturnCode.String())
+	err := common.Encode(w, &response)
+	logger.Response(service.Name, response, response.Response.ReturnCode, err)
+}
+
+// GetEndpointsHandlerHelper returns the state of all endpoints as a GetEndpointsResponse
+func (service *HTTPRestService) GetEndpointsHandlerHelper() GetEndpointsResponse {
+	endpointInfos, err := service.GetAllEndpointsHelper()
 	if err != nil {
-		response := GetEndpointResponse{
+		return GetEndpointsResponse{
 			Response: Response{
 				ReturnCode: types.UnexpectedError,
-				Message:    fmt.Sprintf("[GetEndpointState] GetEndpoint failed with error: %s", err.Error()),
+				Message:    fmt.Sprintf("[GetEndpointState] GetEndpoints failed with error: %s", err.Error()),
 			},
 		}
+	}
+	return GetEndpointsResponse{
+		Response: Response{
+			ReturnCode: types.Success,
+			Message:    "[GetEndpointState] GetEndpoints returned successfully",
+		},
+		EndpointInfos: endpointInfos,
+	}
+}
+
+// GetEndpointHandlerHelper returns the state of the given endpointId as a GetEndpointResponse
+func (service *HTTPRestService) GetEndpointHandlerHelper(endpointID string) GetEndpointResponse {
+	endpointInfo, err := service.GetEndpointHelper(endpointID)
+	// Check if the request is valid
+	if err != nil {
 		if errors.Is(err, ErrEndpointStateNotFound) {
-			response = GetEndpointResponse{
+			return GetEndpointResponse{
 				Response: Response{
 					ReturnCode: types.NotFound,
 					Message:    fmt.Sprintf("[GetEndpointState] %s", err.Error()),
 				},
 			}
 		}
-		w.Header().Set(cnsReturnCode, response.Response.ReturnCode.String())
-		err = common.Encode(w, &response)
-		logger.Response(service.Name, response, response.Response.ReturnCode, err)
-		return
+		return GetEndpointResponse{
+			Response: Response{
+				ReturnCode: types.UnexpectedError,
+				Message:    fmt.Sprintf("[GetEndpointState] GetEndpoint failed with error: %s", err.Error()),
+			},
+		}
 	}
-	response := GetEndpointResponse{
+	return GetEndpointResponse{
 		Response: Response{
 			ReturnCode: types.Success,
 			Message:    "[GetEndpointState] GetEndpoint retruned successfully",
 		},
 		EndpointInfo: *endpointInfo,
 	}
-	w.Header().Set(cnsReturnCode, response.Response.ReturnCode.String())
-	err = common.Encode(w, &response)
-	logger.Response(service.Name, response, response.Response.ReturnCode, err)
 }
 
 // GetEndpointHelper returns the state of the given endpointId
@@ -1185,6 +1283,29 @@ func (service *HTTPRestService) GetEndpointHelper(endpointID string) (*EndpointI
 	return nil, ErrEndpointStateNotFound
 }
 
+// GetAllEndpointsHelper returns the state of all endpoints, keyed by endpointId
+func (service *HTTPRestService) GetAllEndpointsHelper() (map[string]*EndpointInfo, error) {
+	// Skip if a store is not provided.
+	if service.EndpointStateStore == nil {
+		return nil, ErrStoreEmpty
+	}
+
+	endpointState := map[string]*EndpointInfo{}
+	err := service.EndpointStateStore.Read(EndpointStoreKey, &endpointState)
+	if err != nil && !errors.Is(err, store.ErrKeyNotFound) {
+		return nil, errors.Wrap(err, "[GetEndpointState]  Failed to retrieve state")
+	}
+	return endpointState, nil
+}
+
+// ReadEndpointState calls f with the in-memory endpoint state under the service read lock, so that callers which
+// follow the endpoint state don't have to read the store on every change. f must not keep or modify the state.
+func (service *HTTPRestService) ReadEndpointState(f func(map[string]*EndpointInfo)) {
+	service.RLock()
+	defer service.RUnlock()
+	f(service.EndpointState)
+}
+
 // UpdateEndpointHandler handles the incoming UpdateEndpoint requests with http Patch method
 func (service *HTTPRestService) UpdateEndpointHandler(w http.ResponseWriter, r *http.Request) {
 	logger.Printf("[updateEndpoint] updateEndpoint for %s", r.URL.Path)
@@ -1204,35 +1325,31 @@ func (service *HTTPRestService) UpdateEndpointHandler(w http.ResponseWriter, r *
 		logger.Response(service.Name, response, response.ReturnCode, err)
 		return
 	}
-	if err = verifyUpdateEndpointStateRequest(req); err != nil {
-		response := cns.Response{
+	response := service.UpdateEndpointHandlerHelper(endpointID, req)
+	w.Header().Set(cnsReturnCode, response.ReturnCode.String())
+	err = common.Encode(w, &response)
+	logger.Response(service.Name, response, response.ReturnCode, err)
+}
+
+// UpdateEndpointHandlerHelper validates the request and updates the state of the given endpointId
+func (service *HTTPRestService) UpdateEndpointHandlerHelper(endpointID string, req map[string]*IPInfo) cns.Response {
+	if err := verifyUpdateEndpointStateRequest(req); err != nil {
+		return cns.Response{
 			ReturnCode: types.InvalidRequest,
 			Message:    err.Error(),
 		}
-		w.Header().Set(cnsReturnCode, response.ReturnCode.String())
-		err = common.Encode(w, &response)
-		logger.Response(service.Name, response, response.ReturnCode, err)
-		return
 	}
 	// Update the endpoint state
-	err = service.UpdateEndpointHelper(endpointID, req)
-	if err != nil {
-		response := cns.Response{
+	if err := service.UpdateEndpointHelper(endpointID, req); err != nil {
+		return cns.Response{
 			ReturnCode: types.UnexpectedError,
 			Message:    fmt.Sprintf("[updateEndpoint] updateEndpoint failed with error: %s", err.Error()),
 		}
-		w.Header().Set(cnsReturnCode, response.ReturnCode.String())
-		err = common.Encode(w, &response)
-		logger.Response(service.Name, response, response.ReturnCode, err)
-		return
 	}
-	response := cns.Response{
+	return cns.Response{
 		ReturnCode: types.Success,
 		Message:    "[updateEndpoint] updateEndpoint retruned successfully",
 	}
-	w.Header().Set(cnsReturnCode, response.ReturnCode.String())
-	err = common.Encode(w, &response)
-	logger.Response(service.Name, response, response.ReturnCode, err)
 }
 
 // UpdateEndpointHelper updates the state of the given endpointId with HNSId, VethName or other InterfaceInfo fields
@@ -1240,6 +1357,8 @@ func (service *HTTPRestService) UpdateEndpointHelper(endpointID string, req map[
 	if service.EndpointStateStore == nil {
 		return ErrStoreEmpty
 	}
+	service.Lock()
+	defer service.Unlock()
 	logger.Printf("[updateEndpoint] Updating endpoint state for infra container %s", endpointID)
 	if endpointInfo, ok := service.EndpointState[endpointID]; ok {
 		// Updating the InterfaceInfo map of endpoint states with the interfaceInfo map that is given by Stateless Azure CNI
@@ -1251,6 +1370,7 @@ func (service *HTTPRestService) UpdateEndpointHelper(endpointID string, req map[
 		if err != nil {
 			return fmt.Errorf("[updateEndpoint] failed to write endpoint state to store for pod %s :  %w", endpointInfo.PodName, err)
 		}
+		service.publishEndpointStateEvent(endpointID, endpointInfo.PodName, endpointInfo.PodNamespace)
 		logger.Printf("[updateEndpoint] successfully write the state to the file %s", endpointID)
 		return nil
 	}
@@ -1289,6 +1409,35 @@ func updateIPInfoMap(iPInfo map[string]*IPInfo, interfaceInfo *IPInfo, ifName, e
 		iPInfo[ifName].MacAddress = interfaceInfo.MacAddress
 		logger.Printf("[updateEndpoint] update the endpoint %s with MacAddress  %s", endpointID, interfaceInfo.MacAddress)
 	}
+	if len(interfaceInfo.PortMappings) > 0 {
+		iPInfo[ifName].PortMappings = interfaceInfo.PortMappings
+		logger.Printf("[updateEndpoint] update the endpoint %s with PortMappings  %+v", endpointID, interfaceInfo.PortMappings)
+	}
+	if interfaceInfo.PortForwardAddress != "" {
+		iPInfo[ifName].PortForwardAddress = interfaceInfo.PortForwardAddress
+		iPInfo[ifName].PortForwardMasqueradeAll = interfaceInfo.PortForwardMasqueradeAll
+		logger.Printf("[updateEndpoint] update the endpoint %s with PortForwardAddress %s, masquerade all %t", endpointID,
+			interfaceInfo.PortForwardAddress, interfaceInfo.PortForwardMasqueradeAll)
+	}
+	if interfaceInfo.Bandwidth != nil {
+		iPInfo[ifName].Bandwidth = interfaceInfo.Bandwidth
+		logger.Printf("[updateEndpoint] update the endpoint %s with Bandwidth  %+v", endpointID, *interfaceInfo.Bandwidth)
+	}
+	if interfaceInfo.NetworkMode != "" {
+		iPInfo[ifName].NetworkMode = interfaceInfo.NetworkMode
+		logger.Printf("[updateEndpoint] update the endpoint %s with NetworkMode  %s", endpointID, interfaceInfo.NetworkMode)
+	}
+	if interfaceInfo.EgressSNAT != nil {
+		iPInfo[ifName].EgressSNAT = interfaceInfo.EgressSNAT
+		logger.Printf("[updateEndpoint] update the endpoint %s with EgressSNAT %+v", endpointID, *interfaceInfo.EgressSNAT)
+	}
+	if interfaceInfo.NetworkID != "" {
+		iPInfo[ifName].NetworkID = interfaceInfo.NetworkID
+		logger.Printf("[updateEndpoint] update the endpoint %s with NetworkID  %s", endpointID, interfaceInfo.NetworkID)
+	}
+	if !interfaceInfo.Created.IsZero() {
+		iPInfo[ifName].Created = interfaceInfo.Created
+	}
 }
 
 // verifyUpdateEndpointStateRequest verify the CNI request body for the UpdateENdpointState API
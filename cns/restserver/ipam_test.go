@@ -2,8 +2,11 @@ package restserver
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net"
+	"net/http"
+	"net/http/httptest"
 	"net/netip"
 	"strconv"
 	"testing"
@@ -12,12 +15,15 @@ import (
 	"github.com/Azure/azure-container-networking/cns/common"
 	"github.com/Azure/azure-container-networking/cns/configuration"
 	"github.com/Azure/azure-container-networking/cns/fakes"
+	ipampoolv2 "github.com/Azure/azure-container-networking/cns/ipampool/v2"
 	"github.com/Azure/azure-container-networking/cns/middlewares"
 	"github.com/Azure/azure-container-networking/cns/middlewares/mock"
 	"github.com/Azure/azure-container-networking/cns/types"
+	"github.com/Azure/azure-container-networking/crd/nodenetworkconfig/api/v1alpha"
 	"github.com/Azure/azure-container-networking/store"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 var (
@@ -1893,3 +1899,40 @@ func TestIPAMGetK8sInfinibandFailure(t *testing.T) {
 		t.Fatalf("Expected failing requesting IPs due to not able to set routes")
 	}
 }
+
+func TestHandleDebugPoolMonitor(t *testing.T) {
+	svc := getTestService()
+	get := func() cns.GetPoolMonitorStateResponse {
+		req := httptest.NewRequest(http.MethodGet, cns.PathDebugPoolMonitor, http.NoBody)
+		w := httptest.NewRecorder()
+		svc.HandleDebugPoolMonitor(w, req)
+		var resp cns.GetPoolMonitorStateResponse
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+		return resp
+	}
+
+	assert.Equal(t, types.NotFound, get().Response.ReturnCode)
+
+	monitor := &fakes.MonitorFake{
+		IPsNotInUseCount: 2,
+		NodeNetworkConfig: &v1alpha.NodeNetworkConfig{
+			Spec:   v1alpha.NodeNetworkConfigSpec{RequestedIPCount: 16},
+			Status: v1alpha.NodeNetworkConfigStatus{Scaler: v1alpha.Scaler{BatchSize: 16, RequestThresholdPercent: 50, ReleaseThresholdPercent: 150}},
+		},
+	}
+	svc.AttachPoolMonitorState(func() (cns.IpamPoolMonitorStateSnapshot, error) {
+		return monitor.GetStateSnapshot(), nil
+	})
+	resp := get()
+	assert.Equal(t, types.Success, resp.Response.ReturnCode)
+	assert.Equal(t, int64(8), resp.Snapshot.MinimumFreeIps)
+	assert.Equal(t, int64(24), resp.Snapshot.MaximumFreeIps)
+	assert.Equal(t, int64(2), resp.Snapshot.UpdatingIpsNotInUseCount)
+	assert.Equal(t, int64(16), resp.Snapshot.CachedNNC.Spec.RequestedIPCount)
+
+	// the IPAMv2 monitor has no snapshot to report
+	svc.AttachPoolMonitorState(ipampoolv2.StateSnapshot)
+	resp = get()
+	assert.Equal(t, types.UnsupportedAPI, resp.Response.ReturnCode)
+	assert.Equal(t, ipampoolv2.ErrStateSnapshotUnsupported.Error(), resp.Response.Message)
+}
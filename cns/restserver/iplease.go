@@ -0,0 +1,217 @@
+package restserver
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/Azure/azure-container-networking/cns"
+	"github.com/Azure/azure-container-networking/cns/logger"
+	"github.com/Azure/azure-container-networking/cns/types"
+	"github.com/Azure/azure-container-networking/common"
+	v1 "k8s.io/api/core/v1"
+)
+
+// SetIPLeaseDuration enables leases on Assigned IPs. IPs assigned after this call expire if the
+// CNI does not renew them within the passed duration. A zero duration disables leases.
+func (service *HTTPRestService) SetIPLeaseDuration(d time.Duration) {
+	service.Lock()
+	defer service.Unlock()
+	service.ipLeaseDuration = d
+}
+
+// ipLeaseExpiryUntransacted returns the lease expiry of an IP moving to the passed state. An IP which
+// moves to Assigned picks up the lease persisted in the CNS state before a restart, if any, so that the
+// reconcile doesn't grant fresh leases. Does not take a lock.
+func (service *HTTPRestService) ipLeaseExpiryUntransacted(ipID string, updatedState types.IPState, assignedAt time.Time) time.Time {
+	if updatedState != types.Assigned || service.ipLeaseDuration == 0 {
+		if _, ok := service.state.IPLeaseExpiry[ipID]; ok {
+			delete(service.state.IPLeaseExpiry, ipID)
+			service.ipLeasesChanged = true
+		}
+		return time.Time{}
+	}
+	if expiry, ok := service.state.IPLeaseExpiry[ipID]; ok {
+		return expiry
+	}
+	expiry := assignedAt.Add(service.ipLeaseDuration)
+	service.setIPLeaseExpiryUntransacted(ipID, expiry)
+	return expiry
+}
+
+// setIPLeaseExpiryUntransacted records the lease expiry of an IP in the CNS state, which the sweeper
+// persists. Does not take a lock.
+func (service *HTTPRestService) setIPLeaseExpiryUntransacted(ipID string, expiry time.Time) {
+	if service.state.IPLeaseExpiry == nil {
+		service.state.IPLeaseExpiry = make(map[string]time.Time)
+	}
+	service.state.IPLeaseExpiry[ipID] = expiry
+	service.ipLeasesChanged = true
+}
+
+// renewIPLeases extends the lease on all IPs assigned to the passed Pod and returns the number of
+// Assigned IPs found for the Pod.
+func (service *HTTPRestService) renewIPLeases(podInfo cns.PodInfo) int {
+	service.Lock()
+	defer service.Unlock()
+	return service.renewIPLeasesUntransacted(podInfo.Key(), time.Now())
+}
+
+// renewIPLeasesUntransacted extends the lease on all IPs assigned to the Pod with the passed key from
+// the passed time and returns the number of Assigned IPs found for the Pod. Does not take a lock.
+func (service *HTTPRestService) renewIPLeasesUntransacted(podKey string, now time.Time) int {
+	expiry := now.Add(service.ipLeaseDuration)
+	found := 0
+	for _, ipID := range service.PodIPIDByPodInterfaceKey[podKey] {
+		ipConfig, ok := service.PodIPConfigState[ipID]
+		if !ok || ipConfig.GetState() != types.Assigned {
+			continue
+		}
+		found++
+		if service.ipLeaseDuration > 0 && expiry.After(ipConfig.LeaseExpiry) {
+			ipConfig.LeaseExpiry = expiry
+			service.PodIPConfigState[ipID] = ipConfig
+			service.setIPLeaseExpiryUntransacted(ipID, expiry)
+		}
+	}
+	return found
+}
+
+// RenewIPLeasesHandler extends the leases on the IPs assigned to a pod. The CNI calls this on CHECK
+// to prove that the pod still holds its IPs, between the renewals from the pod watcher.
+func (service *HTTPRestService) RenewIPLeasesHandler(w http.ResponseWriter, r *http.Request) {
+	var ipconfigsRequest cns.IPConfigsRequest
+	err := common.Decode(w, r, &ipconfigsRequest)
+	logger.Request(service.Name+"renewIPLeasesHandler", ipconfigsRequest, err)
+	if err != nil {
+		resp := cns.Response{
+			ReturnCode: types.UnexpectedError,
+			Message:    err.Error(),
+		}
+		w.Header().Set(cnsReturnCode, resp.ReturnCode.String())
+		err = common.Encode(w, &resp)
+		logger.ResponseEx(service.Name, ipconfigsRequest, resp, resp.ReturnCode, err)
+		return
+	}
+
+	resp := service.RenewIPLeasesHandlerHelper(r.Context(), ipconfigsRequest)
+	w.Header().Set(cnsReturnCode, resp.ReturnCode.String())
+	err = common.Encode(w, &resp)
+	logger.ResponseEx(service.Name, ipconfigsRequest, resp, resp.ReturnCode, err)
+}
+
+// RenewIPLeasesHandlerHelper validates the request and extends the leases on the IPs assigned to the pod.
+func (service *HTTPRestService) RenewIPLeasesHandlerHelper(ctx context.Context, ipconfigsRequest cns.IPConfigsRequest) cns.Response {
+	podInfo, returnCode, returnMessage := service.validateIPConfigsRequest(ctx, ipconfigsRequest)
+	if returnCode != types.Success {
+		return cns.Response{
+			ReturnCode: returnCode,
+			Message:    returnMessage,
+		}
+	}
+	if service.renewIPLeases(podInfo) == 0 {
+		return cns.Response{
+			ReturnCode: types.NotFound,
+			Message:    "no IPs are assigned to pod " + podInfo.Key(),
+		}
+	}
+	return cns.Response{
+		ReturnCode: types.Success,
+	}
+}
+
+// PodIPLeaseListener records the Pods currently scheduled on the Node and renews the leases of
+// their IPs, so that only the IPs of Pods which are gone from the Node without a CNI DEL expire.
+func (service *HTTPRestService) PodIPLeaseListener(pods []v1.Pod) {
+	livePods := make(map[string]struct{}, len(pods))
+	for i := range pods {
+		livePods[pods[i].Namespace+"/"+pods[i].Name] = struct{}{}
+	}
+	service.Lock()
+	defer service.Unlock()
+	service.livePods = livePods
+	service.renewLiveIPLeasesUntransacted(time.Now())
+}
+
+// renewLiveIPLeasesUntransacted renews the leases of the IPs of the Pods which the pod watcher last
+// reported on the Node. Does not take a lock.
+func (service *HTTPRestService) renewLiveIPLeasesUntransacted(now time.Time) {
+	if service.ipLeaseDuration == 0 || service.livePods == nil {
+		return
+	}
+	renewed := make(map[string]struct{})
+	for _, ipConfig := range service.PodIPConfigState { //nolint:gocritic // ignore copy
+		if ipConfig.GetState() != types.Assigned || ipConfig.PodInfo == nil {
+			continue
+		}
+		if _, ok := renewed[ipConfig.PodInfo.Key()]; ok {
+			continue
+		}
+		if _, ok := service.livePods[ipConfig.PodInfo.Namespace()+"/"+ipConfig.PodInfo.Name()]; ok {
+			service.renewIPLeasesUntransacted(ipConfig.PodInfo.Key(), now)
+			renewed[ipConfig.PodInfo.Key()] = struct{}{}
+		}
+	}
+}
+
+// pruneIPLeasesUntransacted drops the lease expiries in the CNS state of IPs which are not Assigned, such
+// as those of persisted leases whose IPs the reconcile did not assign again. Does not take a lock.
+func (service *HTTPRestService) pruneIPLeasesUntransacted() {
+	for ipID := range service.state.IPLeaseExpiry {
+		if ipConfig, ok := service.PodIPConfigState[ipID]; ok && ipConfig.GetState() == types.Assigned {
+			continue
+		}
+		delete(service.state.IPLeaseExpiry, ipID)
+		service.ipLeasesChanged = true
+	}
+}
+
+// SweepExpiredIPLeases renews the leases of the Pods which the pod watcher last reported on the Node
+// and moves the IPs of the other Pods whose lease has expired back to Available. Until the pod watcher
+// has reported, nothing is renewed or reclaimed. The lease expiries are persisted in the CNS state
+// here rather than on every renewal, after dropping those of IPs which are no longer Assigned.
+func (service *HTTPRestService) SweepExpiredIPLeases(now time.Time) {
+	service.Lock()
+	service.renewLiveIPLeasesUntransacted(now)
+	expiredPods := make(map[string]cns.PodInfo)
+	for _, ipConfig := range service.PodIPConfigState { //nolint:gocritic // ignore copy
+		if ipConfig.GetState() != types.Assigned || ipConfig.PodInfo == nil || ipConfig.GetLeaseState(now) != types.LeaseExpired {
+			continue
+		}
+		if service.livePods == nil {
+			continue
+		}
+		if _, ok := service.livePods[ipConfig.PodInfo.Namespace()+"/"+ipConfig.PodInfo.Name()]; ok {
+			continue
+		}
+		expiredPods[ipConfig.PodInfo.Key()] = ipConfig.PodInfo
+	}
+
+	reclaimed := make([]cns.PodInfo, 0, len(expiredPods))
+	for _, podInfo := range expiredPods {
+		numIPs := len(service.PodIPIDByPodInterfaceKey[podInfo.Key()])
+		logger.Printf("[SweepExpiredIPLeases] Lease expired for pod %+v which no longer exists, releasing %d IPs", podInfo, numIPs)
+		if err := service.releaseIPConfigsUntransacted(podInfo); err != nil {
+			logger.Errorf("[SweepExpiredIPLeases] Failed to release IPs for pod %+v: %v", podInfo, err)
+			continue
+		}
+		reclaimedLeaseIPCount.Add(float64(numIPs))
+		reclaimed = append(reclaimed, podInfo)
+	}
+	service.pruneIPLeasesUntransacted()
+	if service.ipLeasesChanged {
+		if err := service.saveState(); err == nil {
+			service.ipLeasesChanged = false
+		}
+	}
+	service.Unlock()
+
+	// Check if http rest service managed endpoint state is set
+	if service.Options[common.OptManageEndpointState] == true {
+		for _, podInfo := range reclaimed {
+			if err := service.removeEndpointState(podInfo); err != nil {
+				logger.Errorf("[SweepExpiredIPLeases] Failed to remove endpoint state for pod %+v: %v", podInfo, err)
+			}
+		}
+	}
+}
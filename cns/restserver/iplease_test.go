@@ -0,0 +1,151 @@
+package restserver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-container-networking/cns"
+	"github.com/Azure/azure-container-networking/cns/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func requestIPForPod(t *testing.T, podInfo cns.PodInfo) cns.IPConfigurationStatus {
+	req := cns.IPConfigsRequest{
+		PodInterfaceID:   podInfo.InterfaceID(),
+		InfraContainerID: podInfo.InfraContainerID(),
+	}
+	b, _ := podInfo.OrchestratorContext()
+	req.OrchestratorContext = b
+	state, err := requestIPAddressAndGetState(t, req)
+	require.NoError(t, err)
+	require.Len(t, state, 1)
+	return state[0]
+}
+
+func getIPState(svc *HTTPRestService, ipID string) types.IPState {
+	ipConfig := svc.PodIPConfigState[ipID]
+	return ipConfig.GetState()
+}
+
+func setupIPLeaseTest(t *testing.T) *HTTPRestService {
+	svc := getTestService()
+	svc.SetIPLeaseDuration(time.Minute)
+
+	ipconfigs := map[string]cns.IPConfigurationStatus{
+		testIPID1: NewPodState(testIP1, testIPID1, testNCID, types.Available, 0),
+		testIPID2: NewPodState(testIP2, testIPID2, testNCID, types.Available, 0),
+	}
+	require.NoError(t, UpdatePodIPConfigState(t, svc, ipconfigs, testNCID))
+	return svc
+}
+
+func TestIPLeaseRenewal(t *testing.T) {
+	svc := setupIPLeaseTest(t)
+
+	ip := requestIPForPod(t, testPod1Info)
+	require.False(t, ip.LeaseExpiry.IsZero())
+	assert.Equal(t, types.LeaseActive, ip.GetLeaseState(time.Now()))
+	assert.Equal(t, types.LeaseExpired, ip.GetLeaseState(ip.LeaseExpiry.Add(time.Second)))
+
+	time.Sleep(time.Millisecond)
+	assert.Equal(t, 1, svc.renewIPLeases(testPod1Info))
+	assert.True(t, svc.PodIPConfigState[ip.ID].LeaseExpiry.After(ip.LeaseExpiry))
+
+	// a repeated ADD renews the lease as well
+	renewed := svc.PodIPConfigState[ip.ID].LeaseExpiry
+	time.Sleep(time.Millisecond)
+	requestIPForPod(t, testPod1Info)
+	assert.True(t, svc.PodIPConfigState[ip.ID].LeaseExpiry.After(renewed))
+
+	assert.Zero(t, svc.renewIPLeases(testPod2Info))
+
+	// releasing the IP drops the lease
+	require.NoError(t, svc.releaseIPConfigs(testPod1Info))
+	assert.True(t, svc.PodIPConfigState[ip.ID].LeaseExpiry.IsZero())
+}
+
+func TestSweepExpiredIPLeases(t *testing.T) {
+	svc := setupIPLeaseTest(t)
+
+	ip1 := requestIPForPod(t, testPod1Info)
+	ip2 := requestIPForPod(t, testPod2Info)
+	expired := time.Now().Add(2 * time.Minute)
+
+	// nothing is reclaimed until the pod watcher has reported which pods exist
+	svc.SweepExpiredIPLeases(expired)
+	assert.Equal(t, types.Assigned, getIPState(svc, ip1.ID))
+	assert.Equal(t, types.Assigned, getIPState(svc, ip2.ID))
+
+	svc.PodIPLeaseListener([]v1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: testPod1Info.Name(), Namespace: testPod1Info.Namespace()}},
+	})
+
+	// leases which have not expired are never reclaimed
+	svc.SweepExpiredIPLeases(time.Now())
+	assert.Equal(t, types.Assigned, getIPState(svc, ip2.ID))
+
+	// pod 1 still exists so it keeps its IP, pod 2 is gone so its IP is reclaimed
+	svc.SweepExpiredIPLeases(expired)
+	assert.Equal(t, types.Assigned, getIPState(svc, ip1.ID))
+	assert.Equal(t, types.Available, getIPState(svc, ip2.ID))
+	assert.Empty(t, svc.PodIPIDByPodInterfaceKey[testPod2Info.Key()])
+}
+
+func TestIPLeaseRenewedFromPodWatcher(t *testing.T) {
+	svc := setupIPLeaseTest(t)
+
+	ip1 := requestIPForPod(t, testPod1Info)
+	ip2 := requestIPForPod(t, testPod2Info)
+	svc.PodIPLeaseListener([]v1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: testPod1Info.Name(), Namespace: testPod1Info.Namespace()}},
+	})
+
+	// without a CHECK from the CNI, the pod watcher keeps the lease of pod 1 alive across sweeps
+	for i := 1; i <= 3; i++ {
+		now := time.Now().Add(time.Duration(i) * 50 * time.Second)
+		svc.SweepExpiredIPLeases(now)
+		assert.Equal(t, now.Add(time.Minute), svc.PodIPConfigState[ip1.ID].LeaseExpiry)
+	}
+	assert.Equal(t, types.Assigned, getIPState(svc, ip1.ID))
+	assert.Equal(t, types.Available, getIPState(svc, ip2.ID))
+
+	// the sweep persists the leases in the CNS state
+	assert.Equal(t, svc.PodIPConfigState[ip1.ID].LeaseExpiry, svc.state.IPLeaseExpiry[ip1.ID])
+	assert.NotContains(t, svc.state.IPLeaseExpiry, ip2.ID)
+	assert.False(t, svc.ipLeasesChanged)
+}
+
+func TestIPLeaseRestoredFromState(t *testing.T) {
+	svc := setupIPLeaseTest(t)
+
+	// the leases persisted before a restart are picked up again when the reconcile assigns the IPs
+	restored := time.Now().Add(10 * time.Second).Truncate(time.Second)
+	svc.state.IPLeaseExpiry = map[string]time.Time{testIPID1: restored, testIPID2: restored}
+	ip := requestIPForPod(t, testPod1Info)
+	assert.Equal(t, restored, ip.LeaseExpiry)
+
+	require.NoError(t, svc.releaseIPConfigs(testPod1Info))
+	assert.NotContains(t, svc.state.IPLeaseExpiry, ip.ID)
+}
+
+func TestIPLeaseDroppedWhenNotAssigned(t *testing.T) {
+	svc := setupIPLeaseTest(t)
+
+	// marking an IP as pending release drops its lease
+	svc.state.IPLeaseExpiry = map[string]time.Time{testIPID1: time.Now().Add(time.Minute)}
+	require.NoError(t, svc.MarkExistingIPsAsPendingRelease([]string{testIPID1}))
+	assert.NotContains(t, svc.state.IPLeaseExpiry, testIPID1)
+
+	// a persisted lease whose IP the reconcile did not assign again is dropped by the sweep
+	svc.state.IPLeaseExpiry = map[string]time.Time{testIPID2: time.Now().Add(time.Minute), "gone": time.Now()}
+	svc.SweepExpiredIPLeases(time.Now())
+	assert.Empty(t, svc.state.IPLeaseExpiry)
+
+	// the lease of an Assigned IP is kept
+	ip := requestIPForPod(t, testPod1Info)
+	svc.SweepExpiredIPLeases(time.Now())
+	assert.Contains(t, svc.state.IPLeaseExpiry, ip.ID)
+}
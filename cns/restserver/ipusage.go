@@ -1,6 +1,8 @@
 package restserver
 
 import (
+	"time"
+
 	"github.com/Azure/azure-container-networking/cns/logger"
 	"github.com/Azure/azure-container-networking/cns/types"
 )
@@ -16,6 +18,8 @@ type ipState struct {
 	programmingIPs int64
 	// releasingIPs are the IPs in state "PendingReleasr".
 	releasingIPs int64
+	// expiredLeaseIPs are the IPs in state "Assigned" whose lease has expired.
+	expiredLeaseIPs int64
 }
 
 func (service *HTTPRestService) buildIPState() *ipState {
@@ -30,11 +34,15 @@ func (service *HTTPRestService) buildIPState() *ipState {
 		releasingIPs:   0,
 	}
 
+	now := time.Now()
 	//nolint:gocritic // This has to iterate over the IP Config state to get the counts.
 	for _, ipConfig := range service.PodIPConfigState {
 		state.allocatedIPs++
 		if ipConfig.GetState() == types.Assigned {
 			state.assignedIPs++
+			if ipConfig.GetLeaseState(now) == types.LeaseExpired {
+				state.expiredLeaseIPs++
+			}
 		}
 		if ipConfig.GetState() == types.Available {
 			state.availableIPs++
@@ -47,12 +55,13 @@ func (service *HTTPRestService) buildIPState() *ipState {
 		}
 	}
 
-	logger.Printf("[IP Usage] Allocated IPs: %d, Assigned IPs: %d, Available IPs: %d, PendingProgramming IPs: %d, PendingRelease IPs: %d",
+	logger.Printf("[IP Usage] Allocated IPs: %d, Assigned IPs: %d, Available IPs: %d, PendingProgramming IPs: %d, PendingRelease IPs: %d, Expired Lease IPs: %d",
 		state.allocatedIPs,
 		state.assignedIPs,
 		state.availableIPs,
 		state.programmingIPs,
 		state.releasingIPs,
+		state.expiredLeaseIPs,
 	)
 	return &state
 }
@@ -102,6 +102,19 @@ var (
 		},
 		[]string{},
 	)
+	expiredLeaseIPCount = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "expired_lease_ips",
+			Help: "Count of Assigned IPs whose lease has expired but which have not been reclaimed",
+		},
+		[]string{},
+	)
+	reclaimedLeaseIPCount = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "reclaimed_lease_ips_total",
+			Help: "Count of Assigned IPs reclaimed by the lease sweeper",
+		},
+	)
 )
 
 func init() {
@@ -116,6 +129,8 @@ func init() {
 		availableIPCount,
 		pendingProgrammingIPCount,
 		pendingReleaseIPCount,
+		expiredLeaseIPCount,
+		reclaimedLeaseIPCount,
 	)
 }
 
@@ -149,4 +164,5 @@ func publishIPStateMetrics(state *ipState) {
 	availableIPCount.WithLabelValues(labels...).Set(float64(state.availableIPs))
 	pendingProgrammingIPCount.WithLabelValues(labels...).Set(float64(state.programmingIPs))
 	pendingReleaseIPCount.WithLabelValues(labels...).Set(float64(state.releasingIPs))
+	expiredLeaseIPCount.WithLabelValues(labels...).Set(float64(state.expiredLeaseIPs))
 }
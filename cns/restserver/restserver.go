@@ -11,6 +11,7 @@ import (
 	"github.com/Azure/azure-container-networking/cns"
 	"github.com/Azure/azure-container-networking/cns/common"
 	"github.com/Azure/azure-container-networking/cns/dockerclient"
+	"github.com/Azure/azure-container-networking/cns/events"
 	"github.com/Azure/azure-container-networking/cns/ipamclient"
 	"github.com/Azure/azure-container-networking/cns/logger"
 	"github.com/Azure/azure-container-networking/cns/networkcontainers"
@@ -78,6 +79,16 @@ type HTTPRestService struct {
 	IPConfigsHandlerMiddleware cns.IPConfigsHandlerMiddleware
 	PnpIDByMacAddress          map[string]string
 	imdsClient                 imdsClient
+	ipLeaseDuration            time.Duration
+	livePods                   map[string]struct{} // nil until the pod watcher has reported, key : namespace/name
+	ipLeasesChanged            bool                // the lease expiries changed since the last sweep saved them
+	stickyIPHoldTime           time.Duration
+	stickyPods                 map[string]struct{} // key : namespace/name
+	stickyIPsChanged           bool                // the sticky IP reservations changed since they were last saved
+	egressSNATNamespaces       map[string]cns.EgressSNAT
+	egressSNATPods             map[string]cns.EgressSNAT // key : namespace/name
+	eventLog                   *events.Log
+	poolMonitorState           func() (cns.IpamPoolMonitorStateSnapshot, error)
 }
 
 type CNIConflistGenerator interface {
@@ -109,6 +120,39 @@ type IPInfo struct {
 	HostVethName  string      `json:",omitempty"`
 	MacAddress    string      `json:",omitempty"`
 	NICType       cns.NICType
+	// PortMappings are the host ports forwarded to the interface, kept so stateless CNI can remove them on delete.
+	PortMappings []PortMapping `json:",omitempty"`
+	// PortForwardAddress is the address the host ports are forwarded to in place of the interface addresses, such as
+	// the SNAT endpoint of transparent-vlan endpoints, kept so stateless CNI can remove the same rules on delete.
+	PortForwardAddress string `json:",omitempty"`
+	// PortForwardMasqueradeAll is set when all of the traffic forwarded to the host ports is masqueraded.
+	PortForwardMasqueradeAll bool `json:",omitempty"`
+	// Bandwidth is the shaping of the interface traffic, kept so stateless CNI can remove it on delete.
+	Bandwidth *Bandwidth `json:",omitempty"`
+	// NetworkMode is the mode of the CNI network of the interface, such as transparent, which decides its host side.
+	NetworkMode string `json:",omitempty"`
+	// EgressSNAT is the source NAT of the interface egress traffic, kept so stateless CNI can remove it on delete.
+	EgressSNAT *cns.EgressSNAT `json:",omitempty"`
+	// NetworkID is the CNI network of the interface, so that stateless CNI GC only collects the endpoints of its network.
+	NetworkID string `json:",omitempty"`
+	// Created is when CNI created the interface, so that GC leaves the endpoints of in-flight ADDs alone.
+	Created time.Time
+}
+
+// PortMapping forwards a host port to a port of an endpoint interface.
+type PortMapping struct {
+	HostPort      int
+	ContainerPort int
+	Protocol      string // tcp, udp or sctp
+	HostIP        string `json:",omitempty"` // only forward the host port on this address
+}
+
+// Bandwidth limits the traffic to and from an endpoint interface. Rates are in bits per second and bursts in bits.
+type Bandwidth struct {
+	IngressRate  uint64 `json:",omitempty"`
+	IngressBurst uint64 `json:",omitempty"`
+	EgressRate   uint64 `json:",omitempty"`
+	EgressBurst  uint64 `json:",omitempty"`
 }
 
 type GetHTTPServiceDataResponse struct {
@@ -134,6 +178,12 @@ type GetEndpointResponse struct {
 	EndpointInfo EndpointInfo `json:"endpointInfo"`
 }
 
+// GetEndpointsResponse is the state of all endpoints, keyed by endpointId
+type GetEndpointsResponse struct {
+	Response      Response                 `json:"response"`
+	EndpointInfos map[string]*EndpointInfo `json:"endpointInfos"`
+}
+
 // containerstatus is used to save status of an existing container
 type containerstatus struct {
 	ID                            string
@@ -157,6 +207,8 @@ type httpRestServiceState struct {
 	joinedNetworks                   map[string]struct{}
 	primaryInterface                 *wireserver.InterfaceInfo
 	PnpIDByMacAddress                map[string]string
+	StickyIPReservations             map[string]*cns.StickyIPReservation // namespace/name is key
+	IPLeaseExpiry                    map[string]time.Time                // IP ID is key
 }
 
 type networkInfo struct {
@@ -232,6 +284,7 @@ func NewHTTPRestService(config *common.ServiceConfig, wscli interfaceGetter, wsp
 		homeAzMonitor:            homeAzMonitor,
 		cniConflistGenerator:     gen,
 		imdsClient:               imdsClient,
+		eventLog:                 events.NewLog(events.DefaultCapacity),
 	}, nil
 }
 
@@ -280,10 +333,14 @@ func (service *HTTPRestService) Init(config *common.ServiceConfig) error {
 	listener.AddHandler(cns.RequestIPConfigs, NewHandlerFuncWithHistogram(service.RequestIPConfigsHandler, HTTPRequestLatency))
 	listener.AddHandler(cns.ReleaseIPConfig, NewHandlerFuncWithHistogram(service.ReleaseIPConfigHandler, HTTPRequestLatency))
 	listener.AddHandler(cns.ReleaseIPConfigs, NewHandlerFuncWithHistogram(service.ReleaseIPConfigsHandler, HTTPRequestLatency))
+	listener.AddHandler(cns.RenewIPLeases, NewHandlerFuncWithHistogram(service.RenewIPLeasesHandler, HTTPRequestLatency))
 	listener.AddHandler(cns.NmAgentSupportedApisPath, service.nmAgentSupportedApisHandler)
 	listener.AddHandler(cns.PathDebugIPAddresses, service.HandleDebugIPAddresses)
 	listener.AddHandler(cns.PathDebugPodContext, service.HandleDebugPodContext)
 	listener.AddHandler(cns.PathDebugRestData, service.HandleDebugRestData)
+	listener.AddHandler(cns.PathDebugStickyIPs, service.HandleDebugStickyIPs)
+	listener.AddHandler(cns.PathDebugPoolMonitor, service.HandleDebugPoolMonitor)
+	listener.AddHandler(cns.PathEvents, service.HandleEvents)
 	listener.AddHandler(cns.NetworkContainersURLPath, service.getOrRefreshNetworkContainers)
 	listener.AddHandler(cns.GetHomeAz, service.getHomeAz)
 	listener.AddHandler(cns.EndpointPath, service.EndpointHandlerAPI)
@@ -382,6 +439,12 @@ func (service *HTTPRestService) MustGenerateCNIConflistOnce() {
 	})
 }
 
+// AttachPoolMonitorState sets the getter of the IPAM pool monitor state served by the pool monitor debug API.
+// The getter returns an error if the running pool monitor can't report its state.
+func (service *HTTPRestService) AttachPoolMonitorState(getter func() (cns.IpamPoolMonitorStateSnapshot, error)) {
+	service.poolMonitorState = getter
+}
+
 func (service *HTTPRestService) AttachIPConfigsHandlerMiddleware(middleware cns.IPConfigsHandlerMiddleware) {
 	service.IPConfigsHandlerMiddleware = middleware
 }
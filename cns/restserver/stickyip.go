@@ -0,0 +1,315 @@
+package restserver
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/Azure/azure-container-networking/cns"
+	"github.com/Azure/azure-container-networking/cns/logger"
+	"github.com/Azure/azure-container-networking/cns/types"
+	"github.com/Azure/azure-container-networking/common"
+	"golang.org/x/exp/slices"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	// StickyIPAnnotation opts a Pod in ("true") or out ("false") of sticky IPs. Pods owned by a
+	// StatefulSet get sticky IPs unless they opt out.
+	StickyIPAnnotation = "kubernetes.azure.com/sticky-ip"
+	// maxStickyIPConflicts bounds the conflict history kept per reservation.
+	maxStickyIPConflicts = 10
+	// StickyIPPersistInterval is how often the changed sticky IP reservations are saved to the CNS state.
+	StickyIPPersistInterval = 5 * time.Second
+)
+
+func stickyIPKey(name, namespace string) string {
+	return namespace + "/" + name
+}
+
+func isStickyPod(pod *v1.Pod) bool {
+	if v, ok := pod.Annotations[StickyIPAnnotation]; ok {
+		return v == "true"
+	}
+	for i := range pod.OwnerReferences {
+		if pod.OwnerReferences[i].Kind == "StatefulSet" {
+			return true
+		}
+	}
+	return false
+}
+
+// SetStickyIPHoldTime enables sticky IPs. The IPs of a sticky Pod stay reserved for its identity for
+// the passed duration after the Pod releases them. A zero duration disables sticky IPs.
+func (service *HTTPRestService) SetStickyIPHoldTime(d time.Duration) {
+	service.Lock()
+	defer service.Unlock()
+	service.stickyIPHoldTime = d
+}
+
+// StickyIPPodListener records which Pods on the Node are eligible for sticky IPs, and reserves the
+// IPs of eligible Pods which were assigned before the pod watcher reported them.
+func (service *HTTPRestService) StickyIPPodListener(pods []v1.Pod) {
+	stickyPods := make(map[string]struct{})
+	for i := range pods {
+		if isStickyPod(&pods[i]) {
+			stickyPods[stickyIPKey(pods[i].Name, pods[i].Namespace)] = struct{}{}
+		}
+	}
+
+	service.Lock()
+	defer service.Unlock()
+	service.stickyPods = stickyPods
+	if service.stickyIPHoldTime == 0 {
+		return
+	}
+
+	assignedPods := make(map[string]cns.PodInfo)
+	for _, ipConfig := range service.PodIPConfigState { //nolint:gocritic // ignore copy
+		if ipConfig.GetState() != types.Assigned || ipConfig.PodInfo == nil {
+			continue
+		}
+		if _, ok := stickyPods[stickyIPKey(ipConfig.PodInfo.Name(), ipConfig.PodInfo.Namespace())]; ok {
+			assignedPods[ipConfig.PodInfo.Key()] = ipConfig.PodInfo
+		}
+	}
+
+	changed := false
+	for _, podInfo := range assignedPods {
+		changed = service.reserveStickyIPsUntransacted(podInfo) || changed
+	}
+	if changed {
+		service.stickyIPsChanged = true
+	}
+}
+
+// pruneStickyIPReservationsUntransacted drops the reservations which have been held for longer than the
+// hold time since their Pod released them, does not take a lock.
+func (service *HTTPRestService) pruneStickyIPReservationsUntransacted(now time.Time) bool {
+	pruned := false
+	for key, r := range service.state.StickyIPReservations {
+		if !r.ReleasedAt.IsZero() && now.Sub(r.ReleasedAt) > service.stickyIPHoldTime {
+			logger.Printf("[StickyIP] Hold time for reservation %s of IPs %v has passed, dropping it", key, r.IPAddresses)
+			delete(service.state.StickyIPReservations, key)
+			pruned = true
+		}
+	}
+	return pruned
+}
+
+// reservedStickyIPsUntransacted returns the IP addresses held by sticky reservations of Pods other than
+// the passed one, which may be nil. Does not take a lock.
+func (service *HTTPRestService) reservedStickyIPsUntransacted(except cns.PodInfo) map[string]struct{} {
+	if service.stickyIPHoldTime == 0 || len(service.state.StickyIPReservations) == 0 {
+		return nil
+	}
+	if service.pruneStickyIPReservationsUntransacted(time.Now()) {
+		service.stickyIPsChanged = true
+	}
+	exceptKey := ""
+	if except != nil {
+		exceptKey = stickyIPKey(except.Name(), except.Namespace())
+	}
+	reserved := make(map[string]struct{})
+	for key, r := range service.state.StickyIPReservations {
+		if key == exceptKey {
+			continue
+		}
+		for _, ip := range r.IPAddresses {
+			reserved[ip] = struct{}{}
+		}
+	}
+	return reserved
+}
+
+// ReservedStickyIPCount returns how many Available IPs are held by sticky reservations. They are skipped
+// when assigning IPs to other Pods, so the IPAM pool monitor must not count them as free.
+func (service *HTTPRestService) ReservedStickyIPCount() int {
+	service.Lock()
+	defer service.Unlock()
+	reserved := service.reservedStickyIPsUntransacted(nil)
+	if len(reserved) == 0 {
+		return 0
+	}
+	count := 0
+	for _, ipConfig := range service.PodIPConfigState { //nolint:gocritic // ignore copy
+		if _, ok := reserved[ipConfig.IPAddress]; ok && ipConfig.GetState() == types.Available {
+			count++
+		}
+	}
+	return count
+}
+
+// stickyIPsForPod returns the IP addresses reserved for the identity of the passed Pod.
+func (service *HTTPRestService) stickyIPsForPod(podInfo cns.PodInfo) []string {
+	service.Lock()
+	defer service.Unlock()
+	if service.stickyIPHoldTime == 0 {
+		return nil
+	}
+	if service.pruneStickyIPReservationsUntransacted(time.Now()) {
+		service.stickyIPsChanged = true
+	}
+	r, ok := service.state.StickyIPReservations[stickyIPKey(podInfo.Name(), podInfo.Namespace())]
+	if !ok {
+		return nil
+	}
+	return append([]string(nil), r.IPAddresses...)
+}
+
+// reserveStickyIPs reserves the IPs assigned to the passed Pod for its identity if the Pod is sticky.
+func (service *HTTPRestService) reserveStickyIPs(podInfo cns.PodInfo) {
+	service.Lock()
+	defer service.Unlock()
+	if service.stickyIPHoldTime == 0 {
+		return
+	}
+	key := stickyIPKey(podInfo.Name(), podInfo.Namespace())
+	_, sticky := service.stickyPods[key]
+	_, reserved := service.state.StickyIPReservations[key]
+	if !sticky && !reserved {
+		return
+	}
+	if service.reserveStickyIPsUntransacted(podInfo) {
+		service.stickyIPsChanged = true
+	}
+}
+
+// reserveStickyIPsUntransacted creates or refreshes the reservation for the identity of the passed Pod
+// with the IPs currently assigned to it and reports whether the reservation changed. Does not take a lock.
+func (service *HTTPRestService) reserveStickyIPsUntransacted(podInfo cns.PodInfo) bool {
+	ips := make([]string, 0, len(service.PodIPIDByPodInterfaceKey[podInfo.Key()]))
+	for _, ipID := range service.PodIPIDByPodInterfaceKey[podInfo.Key()] {
+		if ipConfig, ok := service.PodIPConfigState[ipID]; ok {
+			ips = append(ips, ipConfig.IPAddress)
+		}
+	}
+	if len(ips) == 0 {
+		return false
+	}
+	sort.Strings(ips)
+
+	key := stickyIPKey(podInfo.Name(), podInfo.Namespace())
+	if service.state.StickyIPReservations == nil {
+		service.state.StickyIPReservations = make(map[string]*cns.StickyIPReservation)
+	}
+	r, ok := service.state.StickyIPReservations[key]
+	if ok && r.ReleasedAt.IsZero() && slices.Equal(r.IPAddresses, ips) {
+		return false
+	}
+	if !ok {
+		r = &cns.StickyIPReservation{
+			PodName:      podInfo.Name(),
+			PodNamespace: podInfo.Namespace(),
+		}
+		service.state.StickyIPReservations[key] = r
+	}
+	logger.Printf("[StickyIP] Reserving IPs %v for pod %s", ips, key)
+	r.IPAddresses = ips
+	r.ReleasedAt = time.Time{}
+	return true
+}
+
+// releaseStickyIPsUntransacted starts the hold time on the reservation for the identity of the passed
+// Pod once it releases the reserved IPs. Does not take a lock.
+func (service *HTTPRestService) releaseStickyIPsUntransacted(podInfo cns.PodInfo, released []cns.IPConfigurationStatus) {
+	r, ok := service.state.StickyIPReservations[stickyIPKey(podInfo.Name(), podInfo.Namespace())]
+	if !ok || !r.ReleasedAt.IsZero() {
+		return
+	}
+	for i := range released {
+		for _, ip := range r.IPAddresses {
+			if released[i].IPAddress == ip {
+				r.ReleasedAt = time.Now()
+				service.stickyIPsChanged = true
+				return
+			}
+		}
+	}
+}
+
+// recordStickyIPConflicts records why the IPs reserved for the passed Pod could not be assigned back to it.
+func (service *HTTPRestService) recordStickyIPConflicts(podInfo cns.PodInfo, reserved []string, assignErr error) {
+	service.Lock()
+	defer service.Unlock()
+	r, ok := service.state.StickyIPReservations[stickyIPKey(podInfo.Name(), podInfo.Namespace())]
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	ipConfigByAddress := make(map[string]cns.IPConfigurationStatus, len(service.PodIPConfigState))
+	for _, ipConfig := range service.PodIPConfigState { //nolint:gocritic // ignore copy
+		ipConfigByAddress[ipConfig.IPAddress] = ipConfig
+	}
+	conflicts := make([]cns.StickyIPConflict, 0, len(reserved))
+	for _, ip := range reserved {
+		reason := ""
+		ipConfig, found := ipConfigByAddress[ip]
+		switch {
+		case !found:
+			reason = "IP is no longer in the NC pool"
+		case ipConfig.GetState() == types.Assigned && ipConfig.PodInfo != nil && ipConfig.PodInfo.Key() != podInfo.Key():
+			reason = "IP is assigned to pod " + stickyIPKey(ipConfig.PodInfo.Name(), ipConfig.PodInfo.Namespace())
+		case ipConfig.GetState() != types.Available && ipConfig.GetState() != types.Assigned:
+			reason = fmt.Sprintf("IP is in state %s", ipConfig.GetState())
+		}
+		if reason != "" {
+			conflicts = append(conflicts, cns.StickyIPConflict{IPAddress: ip, Reason: reason, Time: now})
+		}
+	}
+	if len(conflicts) == 0 && assignErr != nil {
+		for _, ip := range reserved {
+			conflicts = append(conflicts, cns.StickyIPConflict{IPAddress: ip, Reason: assignErr.Error(), Time: now})
+		}
+	}
+
+	logger.Errorf("[StickyIP] Failed to assign reserved IPs %v to pod %+v: %+v", reserved, podInfo, conflicts)
+	r.Conflicts = append(r.Conflicts, conflicts...)
+	if len(r.Conflicts) > maxStickyIPConflicts {
+		r.Conflicts = r.Conflicts[len(r.Conflicts)-maxStickyIPConflicts:]
+	}
+	service.stickyIPsChanged = true
+}
+
+// PersistStickyIPReservations drops the reservations whose hold time has passed and saves the CNS state
+// if the reservations changed since the last call. The IP assignment paths only change the reservations
+// in memory, so that they don't write the whole state on every request under the service lock.
+func (service *HTTPRestService) PersistStickyIPReservations(now time.Time) {
+	service.Lock()
+	defer service.Unlock()
+	if service.pruneStickyIPReservationsUntransacted(now) {
+		service.stickyIPsChanged = true
+	}
+	if !service.stickyIPsChanged {
+		return
+	}
+	if err := service.saveState(); err != nil {
+		logger.Errorf("[StickyIP] Failed to persist sticky IP reservations, retrying on the next flush: %v", err)
+		return
+	}
+	service.stickyIPsChanged = false
+}
+
+// GetStickyIPReservations returns the current sticky IP reservations.
+func (service *HTTPRestService) GetStickyIPReservations() []cns.StickyIPReservation {
+	service.RLock()
+	defer service.RUnlock()
+	reservations := make([]cns.StickyIPReservation, 0, len(service.state.StickyIPReservations))
+	for _, r := range service.state.StickyIPReservations {
+		reservations = append(reservations, *r)
+	}
+	sort.Slice(reservations, func(i, j int) bool {
+		return stickyIPKey(reservations[i].PodName, reservations[i].PodNamespace) < stickyIPKey(reservations[j].PodName, reservations[j].PodNamespace)
+	})
+	return reservations
+}
+
+func (service *HTTPRestService) HandleDebugStickyIPs(w http.ResponseWriter, r *http.Request) { //nolint
+	resp := cns.GetStickyIPReservationsResponse{
+		Reservations: service.GetStickyIPReservations(),
+	}
+	err := common.Encode(w, &resp)
+	logger.Response(service.Name, resp, resp.Response.ReturnCode, err)
+}
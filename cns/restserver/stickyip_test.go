@@ -0,0 +1,206 @@
+package restserver
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-container-networking/cns"
+	"github.com/Azure/azure-container-networking/cns/types"
+	"github.com/Azure/azure-container-networking/store"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func setupStickyIPTest(t *testing.T, holdTime time.Duration) *HTTPRestService {
+	svc := getTestService()
+	svc.SetStickyIPHoldTime(holdTime)
+
+	ipconfigs := map[string]cns.IPConfigurationStatus{
+		testIPID1: NewPodState(testIP1, testIPID1, testNCID, types.Available, 0),
+		testIPID2: NewPodState(testIP2, testIPID2, testNCID, types.Available, 0),
+		testIPID3: NewPodState(testIP3, testIPID3, testNCID, types.Available, 0),
+	}
+	require.NoError(t, UpdatePodIPConfigState(t, svc, ipconfigs, testNCID))
+
+	svc.StickyIPPodListener([]v1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            testPod1Info.Name(),
+				Namespace:       testPod1Info.Namespace(),
+				OwnerReferences: []metav1.OwnerReference{{Kind: "StatefulSet", Name: "testpod"}},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        testPod2Info.Name(),
+				Namespace:   testPod2Info.Namespace(),
+				Annotations: map[string]string{StickyIPAnnotation: "false"},
+			},
+		},
+	})
+	return svc
+}
+
+func TestIsStickyPod(t *testing.T) {
+	statefulSetOwner := []metav1.OwnerReference{{Kind: "StatefulSet", Name: "db"}}
+	tests := []struct {
+		name string
+		pod  v1.Pod
+		want bool
+	}{
+		{
+			name: "statefulset pod",
+			pod:  v1.Pod{ObjectMeta: metav1.ObjectMeta{OwnerReferences: statefulSetOwner}},
+			want: true,
+		},
+		{
+			name: "statefulset pod opted out",
+			pod:  v1.Pod{ObjectMeta: metav1.ObjectMeta{OwnerReferences: statefulSetOwner, Annotations: map[string]string{StickyIPAnnotation: "false"}}},
+		},
+		{
+			name: "annotated pod",
+			pod:  v1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{StickyIPAnnotation: "true"}}},
+			want: true,
+		},
+		{
+			name: "replicaset pod",
+			pod:  v1.Pod{ObjectMeta: metav1.ObjectMeta{OwnerReferences: []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "web"}}}},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isStickyPod(&tt.pod))
+		})
+	}
+}
+
+func TestStickyIPReassignedToRecreatedPod(t *testing.T) {
+	svc := setupStickyIPTest(t, time.Hour)
+
+	ip := requestIPForPod(t, testPod1Info)
+	require.NoError(t, svc.releaseIPConfigs(testPod1Info))
+
+	reservations := svc.GetStickyIPReservations()
+	require.Len(t, reservations, 1)
+	assert.Equal(t, []string{ip.IPAddress}, reservations[0].IPAddresses)
+	assert.False(t, reservations[0].ReleasedAt.IsZero())
+
+	// while the pod is gone its IP is not handed to other pods
+	for i := 0; i < 2; i++ {
+		other := requestIPForPod(t, cns.NewPodInfo(fmt.Sprintf("other%d", i), fmt.Sprintf("other%d-eth0", i), fmt.Sprintf("other%d", i), "default"))
+		assert.NotEqual(t, ip.IPAddress, other.IPAddress)
+	}
+
+	// the recreated pod, with a new sandbox, gets its old IP back
+	recreated := cns.NewPodInfo("recreated", "recreated-eth0", testPod1Info.Name(), testPod1Info.Namespace())
+	got := requestIPForPod(t, recreated)
+	assert.Equal(t, ip.IPAddress, got.IPAddress)
+	assert.True(t, svc.GetStickyIPReservations()[0].ReleasedAt.IsZero())
+}
+
+func TestReservedStickyIPCount(t *testing.T) {
+	svc := setupStickyIPTest(t, time.Hour)
+
+	// the IP of a running sticky pod is Assigned, not held back
+	requestIPForPod(t, testPod1Info)
+	assert.Zero(t, svc.ReservedStickyIPCount())
+
+	// once the pod is gone, its Available IP is held for it
+	require.NoError(t, svc.releaseIPConfigs(testPod1Info))
+	assert.Equal(t, 1, svc.ReservedStickyIPCount())
+}
+
+func TestStickyIPOptOut(t *testing.T) {
+	svc := setupStickyIPTest(t, time.Hour)
+
+	requestIPForPod(t, testPod2Info)
+	require.NoError(t, svc.releaseIPConfigs(testPod2Info))
+	assert.Empty(t, svc.GetStickyIPReservations())
+}
+
+func TestStickyIPHoldTimeExpires(t *testing.T) {
+	svc := setupStickyIPTest(t, time.Nanosecond)
+
+	requestIPForPod(t, testPod1Info)
+	require.NoError(t, svc.releaseIPConfigs(testPod1Info))
+	time.Sleep(time.Millisecond)
+
+	assert.Empty(t, svc.stickyIPsForPod(testPod1Info))
+	assert.Empty(t, svc.GetStickyIPReservations())
+}
+
+func TestStickyIPConflict(t *testing.T) {
+	svc := setupStickyIPTest(t, time.Hour)
+
+	ip := requestIPForPod(t, testPod1Info)
+	require.NoError(t, svc.releaseIPConfigs(testPod1Info))
+
+	// another pod takes the reserved IP by asking for it explicitly
+	req := cns.IPConfigsRequest{
+		PodInterfaceID:     testPod3Info.InterfaceID(),
+		InfraContainerID:   testPod3Info.InfraContainerID(),
+		DesiredIPAddresses: []string{ip.IPAddress},
+	}
+	req.OrchestratorContext, _ = testPod3Info.OrchestratorContext()
+	_, err := requestIPAddressAndGetState(t, req)
+	require.NoError(t, err)
+
+	// the recreated pod gets a different IP and the conflict is reported
+	got := requestIPForPod(t, testPod1Info)
+	assert.NotEqual(t, ip.IPAddress, got.IPAddress)
+
+	reservations := svc.GetStickyIPReservations()
+	require.Len(t, reservations, 1)
+	assert.Equal(t, []string{got.IPAddress}, reservations[0].IPAddresses)
+	require.Len(t, reservations[0].Conflicts, 1)
+	assert.Equal(t, ip.IPAddress, reservations[0].Conflicts[0].IPAddress)
+	assert.Contains(t, reservations[0].Conflicts[0].Reason, testPod3Info.Name())
+}
+
+// countingStore counts the writes of the CNS state and fails them while err is set
+type countingStore struct {
+	store.KeyValueStore
+	writes int
+	err    error
+}
+
+func (s *countingStore) Write(key string, value interface{}) error {
+	s.writes++
+	if s.err != nil {
+		return s.err
+	}
+	return s.KeyValueStore.Write(key, value) //nolint:wrapcheck // test store
+}
+
+func TestPersistStickyIPReservations(t *testing.T) {
+	svc := setupStickyIPTest(t, time.Hour)
+	st := &countingStore{KeyValueStore: store.NewMockStore("")}
+	svc.store = st
+
+	// assigning and releasing sticky IPs doesn't write the state
+	requestIPForPod(t, testPod1Info)
+	require.NoError(t, svc.releaseIPConfigs(testPod1Info))
+	requestIPForPod(t, testPod1Info)
+	assert.Zero(t, st.writes)
+
+	// a failed write is retried on the next call
+	st.err = errors.New("disk full")
+	svc.PersistStickyIPReservations(time.Now())
+	assert.Equal(t, 1, st.writes)
+	st.err = nil
+	svc.PersistStickyIPReservations(time.Now())
+	assert.Equal(t, 2, st.writes)
+
+	var state httpRestServiceState
+	require.NoError(t, st.Read(storeKey, &state))
+	require.Len(t, state.StickyIPReservations, 1)
+
+	// nothing changed, so nothing is written
+	svc.PersistStickyIPReservations(time.Now())
+	assert.Equal(t, 2, st.writes)
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/Azure/azure-container-networking/aitelemetry"
 	"github.com/Azure/azure-container-networking/cns"
 	"github.com/Azure/azure-container-networking/cns/dockerclient"
+	"github.com/Azure/azure-container-networking/cns/events"
 	"github.com/Azure/azure-container-networking/cns/logger"
 	"github.com/Azure/azure-container-networking/cns/networkcontainers"
 	"github.com/Azure/azure-container-networking/cns/types"
@@ -153,7 +154,9 @@ func (service *HTTPRestService) saveNetworkContainerGoalState(req cns.CreateNetw
 	}
 
 	existingNCStatus, ok := service.state.ContainerStatus[req.NetworkContainerid]
+	ncEvent := events.NetworkContainerCreated
 	if ok {
+		ncEvent = events.NetworkContainerUpdated
 		hostVersion = existingNCStatus.HostVersion
 		existingSecondaryIPConfigs = existingNCStatus.CreateNetworkContainerRequest.SecondaryIPConfigs
 		vfpUpdateComplete = existingNCStatus.VfpUpdateComplete
@@ -242,6 +245,7 @@ func (service *HTTPRestService) saveNetworkContainerGoalState(req cns.CreateNetw
 	}
 
 	service.saveState()
+	service.publishNCEvent(ncEvent, req.NetworkContainerid, &createNetworkContainerRequest)
 	return 0, ""
 }
 
@@ -337,9 +341,12 @@ func (service *HTTPRestService) addIPConfigStateUntransacted(ncID string, hostVe
 		}
 		ipconfigStatus.WithStateMiddleware(stateTransitionMiddleware)
 		ipconfigStatus.SetState(newIPCNSStatus)
+		// the lease persisted in the CNS state for this IP is kept for the reconcile, which assigns the IP again,
+		// and the lease sweeper drops it if the IP is not
 		logger.Printf("[Azure-Cns] Add IP %s as %s", ipconfig.IPAddress, newIPCNSStatus)
 
 		service.PodIPConfigState[ipID] = ipconfigStatus
+		service.publishIPEvent("", nil, &ipconfigStatus)
 
 		// Todo Update batch API and maintain the count
 	}
@@ -377,6 +384,9 @@ func (service *HTTPRestService) removeToBeDeletedIPStateUntransacted(
 	logger.Printf("[Azure-Cns] Delete the PodIpConfigState, IpId: %s, IPConfigStatus: %v",
 		ipID,
 		service.PodIPConfigState[ipID])
+	if ipConfigStatus, exists := service.PodIPConfigState[ipID]; exists {
+		service.publishIPRemovedEvent(&ipConfigStatus)
+	}
 	delete(service.PodIPConfigState, ipID)
 	return 0, ""
 }
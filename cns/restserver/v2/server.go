@@ -20,15 +20,38 @@ func New(s *restserver.HTTPRestService) *Server {
 }
 
 func (s Server) Start(ctx context.Context, addr string) error {
+	e := s.router()
+
+	if err := e.Start(addr); err != nil {
+		logger.Errorf("failed to run echo server due to %+v", err)
+		return errors.Wrap(err, "failed to start echo server")
+	}
+
+	// after context is done, shutdown local server
+	<-ctx.Done()
+	if err := e.Shutdown(ctx); err != nil {
+		logger.Errorf("failed to shutdown echo server due to %+v", err)
+		return errors.Wrap(err, "failed to shutdown echo server")
+	}
+
+	return nil
+}
+
+// router returns the echo server with the CNS routes registered.
+func (s Server) router() *echo.Echo {
 	e := echo.New()
 	e.HideBanner = true
 	e.POST(cns.RequestIPConfig, echo.WrapHandler(restserver.NewHandlerFuncWithHistogram(s.RequestIPConfigHandler, restserver.HTTPRequestLatency)))
 	e.POST(cns.RequestIPConfigs, echo.WrapHandler(restserver.NewHandlerFuncWithHistogram(s.RequestIPConfigsHandler, restserver.HTTPRequestLatency)))
 	e.POST(cns.ReleaseIPConfig, echo.WrapHandler(restserver.NewHandlerFuncWithHistogram(s.ReleaseIPConfigHandler, restserver.HTTPRequestLatency)))
 	e.POST(cns.ReleaseIPConfigs, echo.WrapHandler(restserver.NewHandlerFuncWithHistogram(s.ReleaseIPConfigsHandler, restserver.HTTPRequestLatency)))
+	e.POST(cns.RenewIPLeases, echo.WrapHandler(restserver.NewHandlerFuncWithHistogram(s.RenewIPLeasesHandler, restserver.HTTPRequestLatency)))
 	e.POST(cns.PathDebugIPAddresses, echo.WrapHandler(http.HandlerFunc(s.HandleDebugIPAddresses)))
 	e.POST(cns.PathDebugPodContext, echo.WrapHandler(http.HandlerFunc(s.HandleDebugPodContext)))
 	e.POST(cns.PathDebugRestData, echo.WrapHandler(http.HandlerFunc(s.HandleDebugRestData)))
+	e.GET(cns.PathDebugStickyIPs, echo.WrapHandler(http.HandlerFunc(s.HandleDebugStickyIPs)))
+	e.GET(cns.PathDebugPoolMonitor, echo.WrapHandler(http.HandlerFunc(s.HandleDebugPoolMonitor)))
+	e.GET(cns.PathEvents, echo.WrapHandler(http.HandlerFunc(s.HandleEvents)))
 	e.POST(cns.GetNetworkContainerByOrchestratorContext, echo.WrapHandler(http.HandlerFunc(s.GetNetworkContainerByOrchestratorContext)))
 	e.POST(cns.GetAllNetworkContainers, echo.WrapHandler(http.HandlerFunc(s.GetAllNetworkContainers)))
 	e.POST(cns.CreateHostNCApipaEndpointPath, echo.WrapHandler(http.HandlerFunc(s.CreateHostNCApipaEndpoint)))
@@ -39,18 +62,6 @@ func (s Server) Start(ctx context.Context, addr string) error {
 	e.POST(cns.V2Prefix+cns.GetAllNetworkContainers, echo.WrapHandler(http.HandlerFunc(s.GetAllNetworkContainers)))
 	e.POST(cns.V2Prefix+cns.CreateHostNCApipaEndpointPath, echo.WrapHandler(http.HandlerFunc(s.CreateHostNCApipaEndpoint)))
 	e.POST(cns.V2Prefix+cns.DeleteHostNCApipaEndpointPath, echo.WrapHandler(http.HandlerFunc(s.DeleteHostNCApipaEndpoint)))
-
-	if err := e.Start(addr); err != nil {
-		logger.Errorf("failed to run echo server due to %+v", err)
-		return errors.Wrap(err, "failed to start echo server")
-	}
-
-	// after context is done, shutdown local server
-	<-ctx.Done()
-	if err := e.Shutdown(ctx); err != nil {
-		logger.Errorf("failed to shutdown echo server due to %+v", err)
-		return errors.Wrap(err, "failed to shutdown echo server")
-	}
-
-	return nil
+	e.GET(cns.V2Prefix+cns.PathEvents, echo.WrapHandler(http.HandlerFunc(s.HandleEvents)))
+	return e
 }
@@ -1,17 +1,23 @@
 package v2
 
 import (
+	"context"
 	"net"
+	"net/http/httptest"
 	"net/url"
 	"testing"
 	"time"
 
+	"github.com/Azure/azure-container-networking/cns"
+	"github.com/Azure/azure-container-networking/cns/client"
 	"github.com/Azure/azure-container-networking/cns/common"
 	"github.com/Azure/azure-container-networking/cns/fakes"
 	"github.com/Azure/azure-container-networking/cns/logger"
 	"github.com/Azure/azure-container-networking/cns/restserver"
 	acncommon "github.com/Azure/azure-container-networking/common"
 	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TestStartServices will test three scenarios:
@@ -98,3 +104,41 @@ func startService(cnsPort, cnsURL string) error {
 
 	return nil
 }
+
+func TestGetStickyIPReservations(t *testing.T) {
+	logger.InitLogger("testlogs", 0, 0, "./")
+	service, err := restserver.NewHTTPRestService(&common.ServiceConfig{}, &fakes.WireserverClientFake{},
+		&fakes.WireserverProxyFake{}, &fakes.NMAgentClientFake{}, nil, nil, nil,
+		fakes.NewMockIMDSClient())
+	require.NoError(t, err)
+
+	ts := httptest.NewServer(New(service).router())
+	defer ts.Close()
+	cli, err := client.New(ts.URL, time.Second)
+	require.NoError(t, err)
+
+	reservations, err := cli.GetStickyIPReservations(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, reservations)
+}
+
+func TestGetPoolMonitorState(t *testing.T) {
+	logger.InitLogger("testlogs", 0, 0, "./")
+	service, err := restserver.NewHTTPRestService(&common.ServiceConfig{}, &fakes.WireserverClientFake{},
+		&fakes.WireserverProxyFake{}, &fakes.NMAgentClientFake{}, nil, nil, nil,
+		fakes.NewMockIMDSClient())
+	require.NoError(t, err)
+	service.AttachPoolMonitorState(func() (cns.IpamPoolMonitorStateSnapshot, error) {
+		return cns.IpamPoolMonitorStateSnapshot{MinimumFreeIps: 8, MaximumFreeIps: 24}, nil
+	})
+
+	ts := httptest.NewServer(New(service).router())
+	defer ts.Close()
+	cli, err := client.New(ts.URL, time.Second)
+	require.NoError(t, err)
+
+	snapshot, err := cli.GetPoolMonitorState(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(8), snapshot.MinimumFreeIps)
+	assert.Equal(t, int64(24), snapshot.MaximumFreeIps)
+}
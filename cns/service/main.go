@@ -57,6 +57,7 @@ import (
 	"github.com/Azure/azure-container-networking/crd/nodenetworkconfig/api/v1alpha"
 	acnfs "github.com/Azure/azure-container-networking/internal/fs"
 	"github.com/Azure/azure-container-networking/log"
+	"github.com/Azure/azure-container-networking/netlink"
 	"github.com/Azure/azure-container-networking/nmagent"
 	"github.com/Azure/azure-container-networking/platform"
 	"github.com/Azure/azure-container-networking/processlock"
@@ -706,7 +707,7 @@ func main() {
 
 	// Create the key value store.
 	storeFileName := storeFileLocation + name + ".json"
-	config.Store, err = store.NewJsonFileStore(storeFileName, lockclient, nil)
+	config.Store, err = newKeyValueStore(cnsconfig, storeFileName, lockclient)
 	if err != nil {
 		logger.Errorf("Failed to create store file: %s, due to error %v\n", storeFileName, err)
 		return
@@ -730,7 +731,7 @@ func main() {
 		// Create the key value store.
 		storeFileName := endpointStorePath + endpointStoreName + ".json"
 		logger.Printf("EndpointStoreState path is %s", storeFileName)
-		endpointStateStore, err = store.NewJsonFileStore(storeFileName, endpointStoreLock, nil)
+		endpointStateStore, err = newKeyValueStore(cnsconfig, storeFileName, endpointStoreLock)
 		if err != nil {
 			logger.Errorf("Failed to create endpoint state store file: %s, due to error %v\n", storeFileName, err)
 			return
@@ -897,7 +898,7 @@ func main() {
 		}
 
 		// Initialize CNS service
-		cnsService := &grpc.CNS{Logger: z}
+		cnsService := &grpc.CNS{Logger: z, State: httpRemoteRestService}
 
 		// Create a new gRPC server
 		server, grpcErr := grpc.NewServer(settings, cnsService, z)
@@ -977,6 +978,12 @@ func main() {
 		}()
 	}
 
+	if cnsconfig.ManageEndpointState && cnsconfig.EndpointDriftSettings.Enable {
+		logger.Printf("Starting endpoint drift watcher")
+		go httpRemoteRestService.WatchEndpointDrift(rootCtx, netlink.NewNetlink(), cnsconfig.EndpointDriftSettings.Repair,
+			time.Duration(cnsconfig.EndpointDriftSettings.ResyncIntervalSecs)*time.Second)
+	}
+
 	if !disableTelemetry {
 		go metric.SendHeartBeat(rootCtx, time.Minute*time.Duration(cnsconfig.TelemetrySettings.HeartBeatIntervalInMins), homeAzMonitor, cnsconfig.ChannelMode)
 		go httpRemoteRestService.SendNCSnapShotPeriodically(rootCtx, cnsconfig.TelemetrySettings.SnapshotIntervalInMins)
@@ -1022,6 +1029,7 @@ func main() {
 		netPlugin     network.NetPlugin
 		ipamPlugin    ipam.IpamPlugin
 		lockclientCnm processlock.Interface
+		pluginStore   store.KeyValueStore
 	)
 
 	if startCNM {
@@ -1053,11 +1061,12 @@ func main() {
 
 		// Create the key value store.
 		pluginStoreFile := storeFileLocation + pluginName + ".json"
-		pluginConfig.Store, err = store.NewJsonFileStore(pluginStoreFile, lockclientCnm, nil)
+		pluginConfig.Store, err = newKeyValueStore(cnsconfig, pluginStoreFile, lockclientCnm)
 		if err != nil {
 			logger.Errorf("Failed to create plugin store file %s, due to error : %v\n", pluginStoreFile, err)
 			return
 		}
+		pluginStore = pluginConfig.Store
 
 		// Set plugin options.
 		netPlugin.SetOption(acn.OptAPIServerURL, url)
@@ -1112,6 +1121,11 @@ func main() {
 		}
 	}
 
+	if cnsconfig.EnableWALStore {
+		// Fold the write-ahead logs into the state files so nothing is lost if the WAL store is turned off.
+		flushKeyValueStores(config.Store, endpointStateStore, pluginStore)
+	}
+
 	if err = lockclient.Unlock(); err != nil {
 		log.Errorf("lockclient cns unlock error:%v", err)
 	}
@@ -1279,6 +1293,17 @@ func InitializeCRDState(ctx context.Context, httpRestService cns.HTTPService, cn
 	}
 	httpRestServiceImplementation.SetNodeOrchestrator(&orchestrator)
 
+	// leases and sticky IPs must be enabled before the initial reconcile so that reconciled IPs are covered too
+	if cnsconfig.IPLeaseDurationSecs > 0 {
+		httpRestServiceImplementation.SetIPLeaseDuration(time.Duration(cnsconfig.IPLeaseDurationSecs) * time.Second)
+	}
+	if cnsconfig.StickyIPHoldTimeSecs > 0 {
+		httpRestServiceImplementation.SetStickyIPHoldTime(time.Duration(cnsconfig.StickyIPHoldTimeSecs) * time.Second)
+	}
+	if cnsconfig.EgressSNATSettings.Enable {
+		httpRestServiceImplementation.SetEgressSNATNamespaces(cnsconfig.EgressSNATSettings.Namespaces)
+	}
+
 	// build default clientset.
 	kubeConfig, err := ctrl.GetConfig()
 	if err != nil {
@@ -1445,17 +1470,34 @@ func InitializeCRDState(ctx context.Context, httpRestService cns.HTTPService, cn
 
 	// Build the IPAM Pool monitor
 	var poolMonitor cns.IPAMPoolMonitor
+	var poolMonitorState func() (cns.IpamPoolMonitorStateSnapshot, error)
 	cssCh := make(chan cssv1alpha1.ClusterSubnetState)
 	ipDemandCh := make(chan int)
 	if cnsconfig.EnableIPAMv2 {
 		nncCh := make(chan v1alpha.NodeNetworkConfig)
-		poolMonitor = ipampoolv2.NewMonitor(z, httpRestServiceImplementation, cachedscopedcli, ipDemandCh, nncCh, cssCh).AsV1(nncCh)
+		policy, err := ipampoolv2.NewPolicy(cnsconfig.IPAMv2ScalingPolicy)
+		if err != nil {
+			return errors.Wrap(err, "failed to create IPAM pool scaling policy")
+		}
+		poolMonitor = ipampoolv2.NewMonitor(z, httpRestServiceImplementation, cachedscopedcli, ipDemandCh, nncCh, cssCh).
+			WithReservedIPs(httpRestServiceImplementation.ReservedStickyIPCount).
+			WithEvents(httpRestServiceImplementation.EventLog()).
+			WithPolicy(policy).
+			AsV1(nncCh)
+		poolMonitorState = ipampoolv2.StateSnapshot
 	} else {
 		poolOpts := ipampool.Options{
 			RefreshDelay: poolIPAMRefreshRateInMilliseconds * time.Millisecond,
+			Events:       httpRestServiceImplementation.EventLog(),
+			ReservedIPs:  httpRestServiceImplementation.ReservedStickyIPCount,
+		}
+		monitor := ipampool.NewMonitor(httpRestServiceImplementation, cachedscopedcli, cssCh, &poolOpts)
+		poolMonitor = monitor
+		poolMonitorState = func() (cns.IpamPoolMonitorStateSnapshot, error) {
+			return monitor.GetStateSnapshot(), nil
 		}
-		poolMonitor = ipampool.NewMonitor(httpRestServiceImplementation, cachedscopedcli, cssCh, &poolOpts)
 	}
+	httpRestServiceImplementation.AttachPoolMonitorState(poolMonitorState)
 
 	// Start building the NNC Reconciler
 
@@ -1484,6 +1526,21 @@ func InitializeCRDState(ctx context.Context, httpRestService cns.HTTPService, cn
 			limit := rate.NewLimiter(rate.Every(500*time.Millisecond), 1) //nolint:gomnd // clearly 500ms
 			pw.With(pw.NewNotifierFunc(hostNetworkListOpt, limit, ipampoolv2.PodIPDemandListener(ipDemandCh)))
 		}
+		var podListeners []func([]corev1.Pod)
+		if cnsconfig.IPLeaseDurationSecs > 0 {
+			podListeners = append(podListeners, httpRestServiceImplementation.PodIPLeaseListener)
+		}
+		if cnsconfig.StickyIPHoldTimeSecs > 0 {
+			podListeners = append(podListeners, httpRestServiceImplementation.StickyIPPodListener)
+		}
+		if cnsconfig.EgressSNATSettings.Enable {
+			podListeners = append(podListeners, httpRestServiceImplementation.EgressSNATPodListener)
+		}
+		if len(podListeners) > 0 {
+			// don't relist pods more than every 500ms
+			limit := rate.NewLimiter(rate.Every(500*time.Millisecond), 1) //nolint:gomnd // clearly 500ms
+			pw.With(pw.NewNotifierFunc(&client.ListOptions{}, limit, podListeners...))
+		}
 		if err := pw.SetupWithManager(ctx, manager); err != nil {
 			return errors.Wrapf(err, "failed to setup pod watcher with manager")
 		}
@@ -1499,6 +1556,39 @@ func InitializeCRDState(ctx context.Context, httpRestService cns.HTTPService, cn
 		httpRestService.AttachIPConfigsHandlerMiddleware(swiftV2Middleware)
 	}
 
+	if cnsconfig.IPLeaseDurationSecs > 0 {
+		go func() {
+			logger.Printf("Starting IP lease sweeper")
+			ticker := time.NewTicker(time.Duration(cnsconfig.IPLeaseSweepIntervalSecs) * time.Second)
+			defer ticker.Stop()
+			for {
+				select {
+				case now := <-ticker.C:
+					httpRestServiceImplementation.SweepExpiredIPLeases(now)
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	if cnsconfig.StickyIPHoldTimeSecs > 0 {
+		go func() {
+			logger.Printf("Starting sticky IP reservation persister")
+			ticker := time.NewTicker(restserver.StickyIPPersistInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case now := <-ticker.C:
+					httpRestServiceImplementation.PersistStickyIPReservations(now)
+				case <-ctx.Done():
+					httpRestServiceImplementation.PersistStickyIPReservations(time.Now())
+					return
+				}
+			}
+		}()
+	}
+
 	// start the pool Monitor before the Reconciler, since it needs to be ready to receive an
 	// NodeNetworkConfig update by the time the Reconciler tries to send it.
 	go func() {
@@ -1614,3 +1704,29 @@ func PopulateCNSEndpointState(endpointStateStore store.KeyValueStore) error {
 	}
 	return nil
 }
+
+// newKeyValueStore creates the CNS state store using the backend selected in the CNS config.
+// Existing JSON state files are picked up as the snapshot of the write-ahead log store, so
+// switching EnableWALStore on requires no separate migration step. Switching it off folds the
+// write-ahead log left behind into the JSON state file.
+func newKeyValueStore(cnsconfig *configuration.CNSConfig, storeFileName string, lockclient processlock.Interface) (store.KeyValueStore, error) {
+	if cnsconfig.EnableWALStore {
+		logger.Printf("Using write-ahead log store for %s", storeFileName)
+		return store.NewWALFileStore(storeFileName, lockclient, nil) //nolint:wrapcheck // ignore
+	}
+	if err := store.FoldWALFile(storeFileName, nil); err != nil {
+		return nil, errors.Wrapf(err, "failed to fold write-ahead log into %s", storeFileName)
+	}
+	return store.NewJsonFileStore(storeFileName, lockclient, nil) //nolint:wrapcheck // ignore
+}
+
+func flushKeyValueStores(stores ...store.KeyValueStore) {
+	for _, kvs := range stores {
+		if kvs == nil {
+			continue
+		}
+		if err := kvs.Flush(); err != nil {
+			logger.Errorf("Failed to flush store, due to error: %v", err)
+		}
+	}
+}
@@ -14,3 +14,14 @@ const (
 	// PendingProgramming IPConfigState for allocated IPs pending programming.
 	PendingProgramming IPState = "PendingProgramming"
 )
+
+// LeaseState describes the lease attached to an Assigned IP when CNS IP leases are enabled.
+type LeaseState string
+
+const (
+	// LeaseActive for Assigned IPs which have been renewed by the CNI within the lease duration.
+	LeaseActive LeaseState = "Active"
+	// LeaseExpired for Assigned IPs which have not been renewed within the lease duration and
+	// are candidates to be reclaimed by the lease sweeper.
+	LeaseExpired LeaseState = "Expired"
+)
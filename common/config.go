@@ -149,4 +149,8 @@ const (
 	OptCNIConflistScenario = "cni-conflist-scenario"
 	// OptCNIConflistScenarioAlias "shorthand" for the cni conflist scenairo, see above
 	OptCNIConflistScenarioAlias = "cniconflistscenario"
+
+	// Dry run, print the plan of an ADD without running it
+	OptDryRun      = "dry-run"
+	OptDryRunAlias = "dr"
 )
@@ -42,6 +42,8 @@ type PluginConfig struct {
 	ErrChan   chan error
 	Store     store.KeyValueStore
 	Stateless bool
+	// EnableWALStore keeps the state in a write-ahead log backed store instead of a JSON file.
+	EnableWALStore bool
 }
 
 // NewPlugin creates a new Plugin object.
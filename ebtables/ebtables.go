@@ -42,23 +42,47 @@ func SetSnatForInterface(interfaceName string, macAddress net.HardwareAddr, acti
 	return runEbCmd(table, action, chain, rule)
 }
 
+// Client runs the ebtables commands with its exec client.
+type Client struct {
+	plc platform.ExecClient
+}
+
+// NewClient creates a client running the ebtables commands with the exec client.
+func NewClient(plc platform.ExecClient) *Client {
+	return &Client{plc: plc}
+}
+
+func defaultClient() *Client {
+	return NewClient(platform.NewExecClient(nil))
+}
+
 // SetArpReply sets an ARP reply rule for the given target IP address and MAC address.
 func SetArpReply(ipAddress net.IP, macAddress net.HardwareAddr, action string) error {
+	return defaultClient().SetArpReply(ipAddress, macAddress, action)
+}
+
+// SetArpReply sets an ARP reply rule for the given target IP address and MAC address.
+func (c *Client) SetArpReply(ipAddress net.IP, macAddress net.HardwareAddr, action string) error {
 	table := Nat
 	chain := PreRouting
 	rule := fmt.Sprintf("-p ARP --arp-op Request --arp-ip-dst %s -j arpreply --arpreply-mac %s --arpreply-target DROP",
 		ipAddress, macAddress.String())
 
-	return runEbCmd(table, action, chain, rule)
+	return c.runEbCmd(table, action, chain, rule)
 }
 
 // SetBrouteAccept sets an EB rule.
 func SetBrouteAccept(ipAddress, action string) error {
+	return defaultClient().SetBrouteAccept(ipAddress, action)
+}
+
+// SetBrouteAccept sets an EB rule.
+func (c *Client) SetBrouteAccept(ipAddress, action string) error {
 	table := Broute
 	chain := Brouting
 	rule := fmt.Sprintf("--ip-dst %s -p IPv4 -j redirect --redirect-target ACCEPT", ipAddress)
 
-	return runEbCmd(table, action, chain, rule)
+	return c.runEbCmd(table, action, chain, rule)
 }
 
 // SetDnatForArpReplies sets a MAC DNAT rule for ARP replies received on an interface.
@@ -92,6 +116,11 @@ func SetVepaMode(bridgeName string, downstreamIfNamePrefix string, upstreamMacAd
 
 // SetDnatForIPAddress sets a MAC DNAT rule for an IP address.
 func SetDnatForIPAddress(interfaceName string, ipAddress net.IP, macAddress net.HardwareAddr, action string) error {
+	return defaultClient().SetDnatForIPAddress(interfaceName, ipAddress, macAddress, action)
+}
+
+// SetDnatForIPAddress sets a MAC DNAT rule for an IP address.
+func (c *Client) SetDnatForIPAddress(interfaceName string, ipAddress net.IP, macAddress net.HardwareAddr, action string) error {
 	protocol := "IPv4"
 	dst := "--ip-dst"
 	if ipAddress.To4() == nil {
@@ -104,7 +133,7 @@ func SetDnatForIPAddress(interfaceName string, ipAddress net.IP, macAddress net.
 	rule := fmt.Sprintf("-p %s -i %s %s %s -j dnat --to-dst %s --dnat-target ACCEPT",
 		protocol, interfaceName, dst, ipAddress.String(), macAddress.String())
 
-	return runEbCmd(table, action, chain, rule)
+	return c.runEbCmd(table, action, chain, rule)
 }
 
 // Drop Icmpv6 discovery messages going out of interface
@@ -125,15 +154,19 @@ func SetEbRule(table, action, chain, rule string) error {
 
 // GetEbtableRules gets EB rules for a table and chain.
 func GetEbtableRules(tableName, chainName string) ([]string, error) {
+	return defaultClient().GetEbtableRules(tableName, chainName)
+}
+
+// GetEbtableRules gets EB rules for a table and chain.
+func (c *Client) GetEbtableRules(tableName, chainName string) ([]string, error) {
 	var (
 		inChain bool
 		rules   []string
 	)
-	p := platform.NewExecClient(nil)
 	command := fmt.Sprintf(
 		"ebtables -t %s -L %s --Lmac2",
 		tableName, chainName)
-	out, err := p.ExecuteCommand(command)
+	out, err := c.plc.ExecuteCommand(command)
 	if err != nil {
 		return nil, err
 	}
@@ -195,8 +228,12 @@ func SetBrouteAcceptByInterface(ifName string, protocol, action, target string)
 }
 
 func SetArpDropRuleForIpCidr(ipCidr string, ifName string) error {
+	return defaultClient().SetArpDropRuleForIpCidr(ipCidr, ifName)
+}
+
+func (c *Client) SetArpDropRuleForIpCidr(ipCidr string, ifName string) error {
 	rule := fmt.Sprintf("-p ARP -o %s --arp-op Request --arp-ip-dst %s -j DROP", ifName, ipCidr)
-	exists, err := EbTableRuleExists(Nat, PostRouting, rule)
+	exists, err := c.EbTableRuleExists(Nat, PostRouting, rule)
 	if err != nil {
 		return err
 	}
@@ -205,12 +242,17 @@ func SetArpDropRuleForIpCidr(ipCidr string, ifName string) error {
 		return nil
 	}
 
-	return runEbCmd(Nat, Append, PostRouting, rule)
+	return c.runEbCmd(Nat, Append, PostRouting, rule)
 }
 
 // EbTableRuleExists checks if eb rule exists in table and chain.
 func EbTableRuleExists(tableName, chainName, matchSet string) (bool, error) {
-	rules, err := GetEbtableRules(tableName, chainName)
+	return defaultClient().EbTableRuleExists(tableName, chainName, matchSet)
+}
+
+// EbTableRuleExists checks if eb rule exists in table and chain.
+func (c *Client) EbTableRuleExists(tableName, chainName, matchSet string) (bool, error) {
+	rules, err := c.GetEbtableRules(tableName, chainName)
 	if err != nil {
 		return false, err
 	}
@@ -226,9 +268,12 @@ func EbTableRuleExists(tableName, chainName, matchSet string) (bool, error) {
 
 // runEbCmd runs an EB rule command.
 func runEbCmd(table, action, chain, rule string) error {
-	p := platform.NewExecClient(nil)
+	return defaultClient().runEbCmd(table, action, chain, rule)
+}
+
+func (c *Client) runEbCmd(table, action, chain, rule string) error {
 	command := fmt.Sprintf("ebtables -t %s %s %s %s", table, action, chain, rule)
-	_, err := p.ExecuteCommand(command)
+	_, err := c.plc.ExecuteCommand(command)
 
 	return err
 }
@@ -14,8 +14,10 @@ var logger = log.CNILogger.With(zap.String("component", "cni-iptables"))
 
 // cni iptable chains
 const (
-	CNIInputChain  = "AZURECNIINPUT"
-	CNIOutputChain = "AZURECNIOUTPUT"
+	CNIInputChain    = "AZURECNIINPUT"
+	CNIOutputChain   = "AZURECNIOUTPUT"
+	CNIHostPortChain = "AZURECNIHOSTPORT"
+	CNIEgressChain   = "AZURECNIEGRESS"
 )
 
 // standard iptable chains
@@ -42,6 +44,7 @@ const (
 	Accept     = "ACCEPT"
 	Drop       = "DROP"
 	Masquerade = "MASQUERADE"
+	Dnat       = "DNAT"
 )
 
 // actions
@@ -76,8 +79,9 @@ const (
 
 // known protocols
 const (
-	UDP = "udp"
-	TCP = "tcp"
+	UDP  = "udp"
+	TCP  = "tcp"
+	SCTP = "sctp"
 )
 
 var DisableIPTableLock bool
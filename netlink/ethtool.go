@@ -0,0 +1,22 @@
+package netlink
+
+// Offload is a segmentation or receive offload of a network interface which can be turned on or off.
+type Offload int
+
+const (
+	// OffloadTSO is TCP segmentation offload.
+	OffloadTSO Offload = iota
+	// OffloadGRO is generic receive offload.
+	OffloadGRO
+)
+
+func (o Offload) String() string {
+	switch o {
+	case OffloadTSO:
+		return "tso"
+	case OffloadGRO:
+		return "gro"
+	default:
+		return "unknown"
+	}
+}
@@ -0,0 +1,81 @@
+package netlink
+
+import (
+	"unsafe"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+)
+
+// ethtoolValue is struct ethtool_value, the argument of the ethtool requests getting or setting a single setting.
+type ethtoolValue struct {
+	cmd  uint32
+	data uint32
+}
+
+// ethtoolIfreq is struct ifreq with ifr_data pointing to the ethtool request.
+type ethtoolIfreq struct {
+	name [unix.IFNAMSIZ]byte
+	data unsafe.Pointer
+	_    [16]byte
+}
+
+// SetLinkOffload turns an offload of a network interface on or off.
+func (n Netlink) SetLinkOffload(name string, offload Offload, on bool) error {
+	var cmd uint32
+	switch offload {
+	case OffloadTSO:
+		cmd = unix.ETHTOOL_STSO
+	case OffloadGRO:
+		cmd = unix.ETHTOOL_SGRO
+	default:
+		return errors.Errorf("unknown offload %d", offload)
+	}
+
+	var data uint32
+	if on {
+		data = 1
+	}
+	if _, err := n.ethtool(name, cmd, data); err != nil {
+		return errors.Wrapf(err, "failed to set %s of %s", offload, name)
+	}
+	return nil
+}
+
+// Sends an ethtool request for a single setting of a network interface, in the network namespace of the netlink.
+func (n Netlink) ethtool(name string, cmd, data uint32) (uint32, error) {
+	if len(name) >= unix.IFNAMSIZ {
+		return 0, errors.Errorf("interface name %s too long", name)
+	}
+
+	// The interface is looked up in the network namespace of the socket the request is sent on.
+	var (
+		fd  int
+		err error
+	)
+	if n.nsPath != "" {
+		nsErr := inNamespace(n.nsPath, func() {
+			fd, err = unix.Socket(unix.AF_INET, unix.SOCK_DGRAM|unix.SOCK_CLOEXEC, 0)
+		})
+		if nsErr != nil {
+			if err == nil {
+				unix.Close(fd)
+			}
+			return 0, nsErr
+		}
+	} else {
+		fd, err = unix.Socket(unix.AF_INET, unix.SOCK_DGRAM|unix.SOCK_CLOEXEC, 0)
+	}
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to open ethtool socket")
+	}
+	defer unix.Close(fd)
+
+	value := ethtoolValue{cmd: cmd, data: data}
+	req := ethtoolIfreq{data: unsafe.Pointer(&value)}
+	copy(req.name[:], name)
+	if _, _, errno := unix.Syscall(unix.SYS_IOCTL, uintptr(fd), unix.SIOCETHTOOL, uintptr(unsafe.Pointer(&req))); errno != 0 {
+		return 0, errno
+	}
+	return value.data, nil
+}
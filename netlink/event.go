@@ -0,0 +1,60 @@
+// Copyright 2017 Microsoft. All rights reserved.
+// MIT License
+
+package netlink
+
+import "net"
+
+// Routing netlink multicast groups, which deliver the changes of the network configuration.
+const (
+	RTNLGRP_LINK        = 1
+	RTNLGRP_NEIGH       = 3
+	RTNLGRP_IPV4_IFADDR = 5
+	RTNLGRP_IPV4_ROUTE  = 7
+	RTNLGRP_IPV6_IFADDR = 9
+	RTNLGRP_IPV6_ROUTE  = 11
+)
+
+// Event types.
+type EventType int
+
+const (
+	EVENT_LINK EventType = iota + 1
+	EVENT_ADDR
+	EVENT_ROUTE
+	EVENT_NEIGH
+)
+
+func (t EventType) String() string {
+	switch t {
+	case EVENT_LINK:
+		return "link"
+	case EVENT_ADDR:
+		return "addr"
+	case EVENT_ROUTE:
+		return "route"
+	case EVENT_NEIGH:
+		return "neigh"
+	default:
+		return "unknown"
+	}
+}
+
+// Addr represents an IP address assigned to a network interface.
+type Addr struct {
+	Family    int
+	LinkIndex int
+	Scope     int
+	IPNet     *net.IPNet
+}
+
+// Event is a change of the network configuration. One of Link, Addr, Route or Neigh is set, matching the Type.
+type Event struct {
+	Type EventType
+	// Deleted is set if the object was deleted, otherwise it was added or changed.
+	Deleted bool
+	Link    Link
+	Addr    *Addr
+	Route   *Route
+	Neigh   *Neigh
+}
@@ -0,0 +1,160 @@
+// Copyright 2017 Microsoft. All rights reserved.
+// MIT License
+
+//go:build linux
+// +build linux
+
+package netlink
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+
+	"github.com/Azure/azure-container-networking/log"
+	"golang.org/x/sys/unix"
+)
+
+// deserializeAddr decodes a netlink message into an Addr struct.
+func deserializeAddr(msg *message) (*Addr, error) {
+	if len(msg.data) < unix.SizeofIfAddrmsg {
+		return nil, fmt.Errorf("Invalid address message length %d", len(msg.data))
+	}
+
+	addr := Addr{
+		Family:    int(msg.data[0]),
+		Scope:     int(msg.data[3]),
+		LinkIndex: int(encoder.Uint32(msg.data[4:8])),
+	}
+	prefixLen := int(msg.data[1])
+
+	var address, local net.IP
+	for _, attr := range msg.getAttributes(nil) {
+		switch attr.Type {
+		case unix.IFA_ADDRESS:
+			address = net.IP(attr.value)
+		case unix.IFA_LOCAL:
+			local = net.IP(attr.value)
+		}
+	}
+
+	// On point to point interfaces the address attribute is the address of the peer.
+	if local != nil {
+		address = local
+	}
+	if address != nil {
+		addr.IPNet = &net.IPNet{IP: address, Mask: net.CIDRMask(prefixLen, 8*len(address))}
+	}
+
+	return &addr, nil
+}
+
+// deserializeEvent decodes a netlink notification into an Event, nil for notifications of other objects.
+func deserializeEvent(msg *message) (*Event, error) {
+	var err error
+	event := Event{}
+
+	switch msg.Type {
+	case unix.RTM_NEWLINK, unix.RTM_DELLINK:
+		event.Type = EVENT_LINK
+		event.Deleted = msg.Type == unix.RTM_DELLINK
+		event.Link, err = deserializeLink(msg)
+	case unix.RTM_NEWADDR, unix.RTM_DELADDR:
+		event.Type = EVENT_ADDR
+		event.Deleted = msg.Type == unix.RTM_DELADDR
+		event.Addr, err = deserializeAddr(msg)
+	case unix.RTM_NEWROUTE, unix.RTM_DELROUTE:
+		event.Type = EVENT_ROUTE
+		event.Deleted = msg.Type == unix.RTM_DELROUTE
+		event.Route, err = deserializeRoute(msg)
+	case unix.RTM_NEWNEIGH, unix.RTM_DELNEIGH:
+		event.Type = EVENT_NEIGH
+		event.Deleted = msg.Type == unix.RTM_DELNEIGH
+		event.Neigh = deserializeNeigh(msg)
+	default:
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &event, nil
+}
+
+// Subscribe delivers the changes of the network configuration in the multicast groups on events until done is
+// closed. events is closed when the subscription ends, also when changes were lost because events was not drained
+// fast enough, so the subscriber can read the current configuration and subscribe again.
+func (n Netlink) Subscribe(events chan<- *Event, done <-chan struct{}, groups ...int) error {
+	var s *socket
+	var err error
+
+	if n.nsPath != "" {
+		s, err = newSocketInNamespace(n.nsPath, groups...)
+	} else {
+		s, err = newSocket(groups...)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Reads from the file of a non-blocking socket return when the file is closed.
+	if err = unix.SetNonblock(s.fd, true); err != nil {
+		s.close()
+		return err
+	}
+	file := os.NewFile(uintptr(s.fd), "netlink")
+
+	stopped := make(chan struct{})
+	go func() {
+		select {
+		case <-done:
+		case <-stopped:
+		}
+		file.Close()
+	}()
+
+	go func() {
+		defer close(events)
+		defer close(stopped)
+
+		buffer := make([]byte, max(unix.Getpagesize(), receiveBufferSize))
+		for {
+			l, err := file.Read(buffer)
+			if err != nil {
+				select {
+				case <-done:
+				default:
+					log.Printf("[netlink] Subscription ended, err=%v\n", err)
+				}
+				return
+			}
+
+			nlMsgs, err := syscall.ParseNetlinkMessage(buffer[:l])
+			if err != nil {
+				log.Printf("[netlink] Failed to parse notification, err=%v\n", err)
+				continue
+			}
+
+			for _, nlMsg := range nlMsgs {
+				event, err := deserializeEvent(newResponseMessage(nlMsg))
+				if err != nil {
+					log.Printf("[netlink] Failed to decode notification, err=%v\n", err)
+					continue
+				}
+				if event == nil {
+					continue
+				}
+
+				select {
+				case events <- event:
+				case <-done:
+					return
+				}
+			}
+		}
+	}()
+
+	return nil
+}
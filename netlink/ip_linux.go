@@ -25,15 +25,15 @@ const (
 )
 
 // setIPAddress sends an IP address set request.
-func (Netlink) setIPAddress(ifName string, ipAddress net.IP, ipNet *net.IPNet, add bool) error {
+func (n Netlink) setIPAddress(ifName string, ipAddress net.IP, ipNet *net.IPNet, add bool) error {
 	var msgType, flags int
 
-	s, err := getSocket()
+	s, err := n.socket()
 	if err != nil {
 		return err
 	}
 
-	iface, err := net.InterfaceByName(ifName)
+	iface, err := n.interfaceByName(ifName)
 	if err != nil {
 		return err
 	}
@@ -140,8 +140,8 @@ func deserializeRoute(msg *message) (*Route, error) {
 }
 
 // GetIPRoute returns a list of IP routes matching the given filter.
-func (Netlink) GetIPRoute(filter *Route) ([]*Route, error) {
-	s, err := getSocket()
+func (n Netlink) GetIPRoute(filter *Route) ([]*Route, error) {
+	s, err := n.socket()
 	if err != nil {
 		return nil, err
 	}
@@ -212,10 +212,10 @@ func (Netlink) GetIPRoute(filter *Route) ([]*Route, error) {
 }
 
 // setIpRoute sends an IP route set request.
-func setIpRoute(route *Route, add bool) error {
+func (n Netlink) setIpRoute(route *Route, add bool) error {
 	var msgType, flags int
 
-	s, err := getSocket()
+	s, err := n.socket()
 	if err != nil {
 		return err
 	}
@@ -280,13 +280,13 @@ func setIpRoute(route *Route, add bool) error {
 }
 
 // AddIPRoute adds an IP route to the route table.
-func (Netlink) AddIPRoute(route *Route) error {
-	return setIpRoute(route, true)
+func (n Netlink) AddIPRoute(route *Route) error {
+	return n.setIpRoute(route, true)
 }
 
 // DeleteIPRoute deletes an IP route from the route table.
-func (Netlink) DeleteIPRoute(route *Route) error {
-	return setIpRoute(route, false)
+func (n Netlink) DeleteIPRoute(route *Route) error {
+	return n.setIpRoute(route, false)
 }
 
 // GetIPAddressFamily returns the address family of an IP address.
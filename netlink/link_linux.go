@@ -7,8 +7,10 @@
 package netlink
 
 import (
+	"encoding/binary"
 	"fmt"
 	"net"
+	"strings"
 
 	"github.com/Azure/azure-container-networking/log"
 	"github.com/pkg/errors"
@@ -17,10 +19,14 @@ import (
 
 // Link types.
 const (
-	LINK_TYPE_BRIDGE = "bridge"
-	LINK_TYPE_VETH   = "veth"
-	LINK_TYPE_IPVLAN = "ipvlan"
-	LINK_TYPE_DUMMY  = "dummy"
+	LINK_TYPE_BRIDGE  = "bridge"
+	LINK_TYPE_VETH    = "veth"
+	LINK_TYPE_IPVLAN  = "ipvlan"
+	LINK_TYPE_DUMMY   = "dummy"
+	LINK_TYPE_IFB     = "ifb"
+	LINK_TYPE_VLAN    = "vlan"
+	LINK_TYPE_MACVLAN = "macvlan"
+	LINK_TYPE_VXLAN   = "vxlan"
 )
 
 // IPVLAN link attributes.
@@ -33,6 +39,49 @@ const (
 	IPVLAN_MODE_MAX
 )
 
+// MACVLAN link attributes.
+type MacvlanMode uint32
+
+const (
+	MACVLAN_MODE_PRIVATE  MacvlanMode = 1
+	MACVLAN_MODE_VEPA     MacvlanMode = 2
+	MACVLAN_MODE_BRIDGE   MacvlanMode = 4
+	MACVLAN_MODE_PASSTHRU MacvlanMode = 8
+	MACVLAN_MODE_SOURCE   MacvlanMode = 16
+)
+
+// Operational states of a network interface, as defined by RFC 2863.
+type LinkOperState uint8
+
+const (
+	OPER_UNKNOWN LinkOperState = iota
+	OPER_NOTPRESENT
+	OPER_DOWN
+	OPER_LOWERLAYERDOWN
+	OPER_TESTING
+	OPER_DORMANT
+	OPER_UP
+)
+
+func (state LinkOperState) String() string {
+	switch state {
+	case OPER_NOTPRESENT:
+		return "notpresent"
+	case OPER_DOWN:
+		return "down"
+	case OPER_LOWERLAYERDOWN:
+		return "lowerlayerdown"
+	case OPER_TESTING:
+		return "testing"
+	case OPER_DORMANT:
+		return "dormant"
+	case OPER_UP:
+		return "up"
+	default:
+		return "unknown"
+	}
+}
+
 const (
 	ADD = iota
 	REMOVE
@@ -53,6 +102,26 @@ type LinkInfo struct {
 	ParentIndex int
 	MacAddress  net.HardwareAddr
 	IPAddr      net.IP
+
+	// Attributes below are returned by ListLinks, GetLinkByName and GetLinkByIndex and ignored by AddLink.
+	Index       int
+	MasterIndex int
+	OperState   LinkOperState
+	// NetNsID is the id of the network namespace of the peer or parent interface, -1 if it is in the same one.
+	NetNsID int
+	Stats   *LinkStats
+}
+
+// LinkStats represents the traffic counters of a network interface.
+type LinkStats struct {
+	RxPackets uint64
+	TxPackets uint64
+	RxBytes   uint64
+	TxBytes   uint64
+	RxErrors  uint64
+	TxErrors  uint64
+	RxDropped uint64
+	TxDropped uint64
 }
 
 func (linkInfo *LinkInfo) Info() *LinkInfo {
@@ -81,15 +150,44 @@ type DummyLink struct {
 	LinkInfo
 }
 
+// IFBLink represents an intermediate functional block interface, used to shape traffic redirected to it.
+type IFBLink struct {
+	LinkInfo
+}
+
+// VlanLink represents an 802.1Q VLAN network interface on its parent interface.
+type VlanLink struct {
+	LinkInfo
+	VlanId int
+}
+
+// MacvlanLink represents a MACVLAN network interface on its parent interface.
+type MacvlanLink struct {
+	LinkInfo
+	Mode MacvlanMode
+}
+
+// VxlanLink represents a VXLAN network interface.
+type VxlanLink struct {
+	LinkInfo
+	VxlanId      int
+	VtepDevIndex int    // interface sending the encapsulated traffic, any if zero
+	SrcAddr      net.IP // local tunnel endpoint address
+	Group        net.IP // remote tunnel endpoint or multicast group address
+	Port         int    // destination UDP port, the kernel default if zero
+	TTL          int
+	Learning     bool
+}
+
 // AddLink adds a new network interface of a specified type.
-func (Netlink) AddLink(link Link) error {
+func (n Netlink) AddLink(link Link) error {
 	info := link.Info()
 
 	if info.Name == "" || info.Type == "" {
 		return fmt.Errorf("Invalid link name or type")
 	}
 
-	s, err := getSocket()
+	s, err := n.socket()
 	if err != nil {
 		return err
 	}
@@ -152,6 +250,26 @@ func (Netlink) AddLink(link Link) error {
 		attrData.addNested(newAttributeUint16(IFLA_IPVLAN_MODE, uint16(ipvlan.Mode)))
 
 		attrLinkInfo.addNested(attrData)
+
+	} else if vlan, ok := link.(*VlanLink); ok {
+		// Set VLAN attributes.
+		attrData := newAttribute(IFLA_INFO_DATA, nil)
+		attrData.addNested(newAttributeUint16(unix.IFLA_VLAN_ID, uint16(vlan.VlanId)))
+
+		attrLinkInfo.addNested(attrData)
+
+	} else if macvlan, ok := link.(*MacvlanLink); ok {
+		// Set MACVLAN attributes.
+		if macvlan.Mode != 0 {
+			attrData := newAttribute(IFLA_INFO_DATA, nil)
+			attrData.addNested(newAttributeUint32(unix.IFLA_MACVLAN_MODE, uint32(macvlan.Mode)))
+
+			attrLinkInfo.addNested(attrData)
+		}
+
+	} else if vxlan, ok := link.(*VxlanLink); ok {
+		// Set VXLAN attributes.
+		attrLinkInfo.addNested(vxlanData(vxlan))
 	}
 
 	req.addPayload(attrLinkInfo)
@@ -159,14 +277,294 @@ func (Netlink) AddLink(link Link) error {
 	return s.sendAndWaitForAck(req)
 }
 
-func (Netlink) SetLinkMTU(name string, mtu int) error {
-	iface, err := net.InterfaceByName(name)
+// Returns the VXLAN attributes of a VXLAN link.
+func vxlanData(vxlan *VxlanLink) *attribute {
+	attrData := newAttribute(IFLA_INFO_DATA, nil)
+	attrData.addNested(newAttributeUint32(unix.IFLA_VXLAN_ID, uint32(vxlan.VxlanId)))
+
+	if vxlan.VtepDevIndex != 0 {
+		attrData.addNested(newAttributeUint32(unix.IFLA_VXLAN_LINK, uint32(vxlan.VtepDevIndex)))
+	}
+
+	if vxlan.SrcAddr != nil {
+		if ip := vxlan.SrcAddr.To4(); ip != nil {
+			attrData.addNested(newAttribute(unix.IFLA_VXLAN_LOCAL, ip))
+		} else {
+			attrData.addNested(newAttribute(unix.IFLA_VXLAN_LOCAL6, vxlan.SrcAddr.To16()))
+		}
+	}
+
+	if vxlan.Group != nil {
+		if ip := vxlan.Group.To4(); ip != nil {
+			attrData.addNested(newAttribute(unix.IFLA_VXLAN_GROUP, ip))
+		} else {
+			attrData.addNested(newAttribute(unix.IFLA_VXLAN_GROUP6, vxlan.Group.To16()))
+		}
+	}
+
+	if vxlan.TTL > 0 {
+		attrData.addNested(newAttribute(unix.IFLA_VXLAN_TTL, []byte{uint8(vxlan.TTL)}))
+	}
+
+	// The kernel learns by default.
+	learning := []byte{0}
+	if vxlan.Learning {
+		learning[0] = 1
+	}
+	attrData.addNested(newAttribute(unix.IFLA_VXLAN_LEARNING, learning))
+
+	if vxlan.Port > 0 {
+		// The port is in network byte order.
+		port := make([]byte, 2)
+		binary.BigEndian.PutUint16(port, uint16(vxlan.Port))
+		attrData.addNested(newAttribute(unix.IFLA_VXLAN_PORT, port))
+	}
+
+	return attrData
+}
+
+// Converts the flags of an interface info message to net.Flags, as the net package does.
+func linkFlags(rawFlags uint32) net.Flags {
+	var flags net.Flags
+	if rawFlags&unix.IFF_UP != 0 {
+		flags |= net.FlagUp
+	}
+	if rawFlags&unix.IFF_BROADCAST != 0 {
+		flags |= net.FlagBroadcast
+	}
+	if rawFlags&unix.IFF_LOOPBACK != 0 {
+		flags |= net.FlagLoopback
+	}
+	if rawFlags&unix.IFF_POINTOPOINT != 0 {
+		flags |= net.FlagPointToPoint
+	}
+	if rawFlags&unix.IFF_MULTICAST != 0 {
+		flags |= net.FlagMulticast
+	}
+	if rawFlags&unix.IFF_RUNNING != 0 {
+		flags |= net.FlagRunning
+	}
+	return flags
+}
+
+// deserializeLink decodes a netlink message into a Link of the type of the interface.
+func deserializeLink(msg *message) (Link, error) {
+	if len(msg.data) < unix.SizeofIfInfomsg {
+		return nil, fmt.Errorf("Invalid link message length %d", len(msg.data))
+	}
+
+	info := LinkInfo{
+		Index:   int(int32(encoder.Uint32(msg.data[4:8]))),
+		Flags:   linkFlags(encoder.Uint32(msg.data[8:12])),
+		NetNsID: -1,
+	}
+	var kind string
+	var data []*attribute
+
+	for _, attr := range msg.getAttributes(nil) {
+		switch attr.Type & NLA_TYPE_MASK {
+		case unix.IFLA_IFNAME:
+			info.Name = strings.TrimRight(string(attr.value), "\x00")
+		case unix.IFLA_MTU:
+			info.MTU = uint(encoder.Uint32(attr.value[0:4]))
+		case unix.IFLA_TXQLEN:
+			info.TxQLen = uint(encoder.Uint32(attr.value[0:4]))
+		case unix.IFLA_LINK:
+			info.ParentIndex = int(encoder.Uint32(attr.value[0:4]))
+		case unix.IFLA_MASTER:
+			info.MasterIndex = int(encoder.Uint32(attr.value[0:4]))
+		case unix.IFLA_ADDRESS:
+			info.MacAddress = net.HardwareAddr(attr.value)
+		case unix.IFLA_OPERSTATE:
+			info.OperState = LinkOperState(attr.value[0])
+		case unix.IFLA_LINK_NETNSID:
+			info.NetNsID = int(int32(encoder.Uint32(attr.value[0:4])))
+		case unix.IFLA_STATS64:
+			info.Stats = deserializeLinkStats(attr.value)
+		case unix.IFLA_LINKINFO:
+			for _, nested := range parseAttributes(attr.value) {
+				switch nested.Type {
+				case IFLA_INFO_KIND:
+					kind = strings.TrimRight(string(nested.value), "\x00")
+				case IFLA_INFO_DATA:
+					data = parseAttributes(nested.value)
+				}
+			}
+		}
+	}
+
+	// Interfaces without a kind, such as physical ones, are reported with an empty type.
+	info.Type = kind
+
+	switch kind {
+	case LINK_TYPE_BRIDGE:
+		return &BridgeLink{LinkInfo: info}, nil
+	case LINK_TYPE_VETH:
+		return &VEthLink{LinkInfo: info}, nil
+	case LINK_TYPE_DUMMY:
+		return &DummyLink{LinkInfo: info}, nil
+	case LINK_TYPE_IFB:
+		return &IFBLink{LinkInfo: info}, nil
+	case LINK_TYPE_IPVLAN:
+		link := &IPVlanLink{LinkInfo: info}
+		for _, attr := range data {
+			if attr.Type == IFLA_IPVLAN_MODE && len(attr.value) >= 2 {
+				link.Mode = IPVlanMode(encoder.Uint16(attr.value[0:2]))
+			}
+		}
+		return link, nil
+	case LINK_TYPE_VLAN:
+		link := &VlanLink{LinkInfo: info}
+		for _, attr := range data {
+			if attr.Type == unix.IFLA_VLAN_ID && len(attr.value) >= 2 {
+				link.VlanId = int(encoder.Uint16(attr.value[0:2]))
+			}
+		}
+		return link, nil
+	case LINK_TYPE_MACVLAN:
+		link := &MacvlanLink{LinkInfo: info}
+		for _, attr := range data {
+			if attr.Type == unix.IFLA_MACVLAN_MODE && len(attr.value) >= 4 {
+				link.Mode = MacvlanMode(encoder.Uint32(attr.value[0:4]))
+			}
+		}
+		return link, nil
+	case LINK_TYPE_VXLAN:
+		return deserializeVxlan(info, data), nil
+	default:
+		return &info, nil
+	}
+}
+
+// Decodes the VXLAN attributes of a VXLAN link.
+func deserializeVxlan(info LinkInfo, data []*attribute) *VxlanLink {
+	link := &VxlanLink{LinkInfo: info}
+	for _, attr := range data {
+		switch attr.Type {
+		case unix.IFLA_VXLAN_ID:
+			link.VxlanId = int(encoder.Uint32(attr.value[0:4]))
+		case unix.IFLA_VXLAN_LINK:
+			link.VtepDevIndex = int(encoder.Uint32(attr.value[0:4]))
+		case unix.IFLA_VXLAN_LOCAL, unix.IFLA_VXLAN_LOCAL6:
+			link.SrcAddr = net.IP(attr.value)
+		case unix.IFLA_VXLAN_GROUP, unix.IFLA_VXLAN_GROUP6:
+			link.Group = net.IP(attr.value)
+		case unix.IFLA_VXLAN_TTL:
+			link.TTL = int(attr.value[0])
+		case unix.IFLA_VXLAN_LEARNING:
+			link.Learning = attr.value[0] != 0
+		case unix.IFLA_VXLAN_PORT:
+			link.Port = int(binary.BigEndian.Uint16(attr.value[0:2]))
+		}
+	}
+	return link
+}
+
+// Decodes the leading counters of struct rtnl_link_stats64.
+func deserializeLinkStats(b []byte) *LinkStats {
+	if len(b) < 64 {
+		return nil
+	}
+	return &LinkStats{
+		RxPackets: encoder.Uint64(b[0:8]),
+		TxPackets: encoder.Uint64(b[8:16]),
+		RxBytes:   encoder.Uint64(b[16:24]),
+		TxBytes:   encoder.Uint64(b[24:32]),
+		RxErrors:  encoder.Uint64(b[32:40]),
+		TxErrors:  encoder.Uint64(b[40:48]),
+		RxDropped: encoder.Uint64(b[48:56]),
+		TxDropped: encoder.Uint64(b[56:64]),
+	}
+}
+
+// ListLinks returns all network interfaces.
+func (n Netlink) ListLinks() ([]Link, error) {
+	s, err := n.socket()
+	if err != nil {
+		return nil, err
+	}
+
+	req := newRequest(unix.RTM_GETLINK, unix.NLM_F_DUMP)
+	req.addPayload(newIfInfoMsg())
+
+	msgs, err := s.sendAndWaitForResponse(req)
+	if err != nil {
+		return nil, err
+	}
+
+	links := make([]Link, 0, len(msgs))
+	for _, msg := range msgs {
+		if msg.Type != unix.RTM_NEWLINK {
+			continue
+		}
+		link, err := deserializeLink(msg)
+		if err != nil {
+			return nil, err
+		}
+		links = append(links, link)
+	}
+
+	return links, nil
+}
+
+// GetLinkByName returns the network interface with the given name.
+func (n Netlink) GetLinkByName(name string) (Link, error) {
+	if name == "" {
+		return nil, fmt.Errorf("Invalid link name")
+	}
+
+	return n.getLink(0, name)
+}
+
+// GetLinkByIndex returns the network interface with the given index.
+func (n Netlink) GetLinkByIndex(index int) (Link, error) {
+	if index <= 0 {
+		return nil, fmt.Errorf("Invalid link index %d", index)
+	}
+
+	return n.getLink(index, "")
+}
+
+// Sends a get link request for an interface by index or name.
+func (n Netlink) getLink(index int, name string) (Link, error) {
+	s, err := n.socket()
+	if err != nil {
+		return nil, err
+	}
+
+	// The response is the link message alone, an ack would follow it.
+	req := newRequest(unix.RTM_GETLINK, 0)
+
+	ifInfo := newIfInfoMsg()
+	ifInfo.Index = int32(index)
+	req.addPayload(ifInfo)
+
+	if name != "" {
+		req.addPayload(newAttributeStringZ(unix.IFLA_IFNAME, name))
+	}
+
+	msgs, err := s.sendAndWaitForResponse(req)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, msg := range msgs {
+		if msg.Type == unix.RTM_NEWLINK {
+			return deserializeLink(msg)
+		}
+	}
+
+	return nil, unix.ENODEV
+}
+
+func (n Netlink) SetLinkMTU(name string, mtu int) error {
+	iface, err := n.interfaceByName(name)
 	if err != nil {
 		log.Printf("[net] Interface not found. returning error")
 		return errors.Wrap(err, "SetLinkMTU:InterfaceByName failed")
 	}
 
-	s, err := getSocket()
+	s, err := n.socket()
 	if err != nil {
 		return err
 	}
@@ -182,20 +580,41 @@ func (Netlink) SetLinkMTU(name string, mtu int) error {
 	return s.sendAndWaitForAck(req)
 }
 
+// SetLinkTxQLen sets the transmit queue length of a network interface.
+func (n Netlink) SetLinkTxQLen(name string, txQLen int) error {
+	iface, err := n.interfaceByName(name)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get interface %s", name)
+	}
+
+	s, err := n.socket()
+	if err != nil {
+		return err
+	}
+
+	req := newRequest(unix.RTM_SETLINK, unix.NLM_F_ACK)
+	ifInfo := newIfInfoMsg()
+	ifInfo.Index = int32(iface.Index)
+	req.addPayload(ifInfo)
+	req.addPayload(newAttributeUint32(unix.IFLA_TXQLEN, uint32(txQLen)))
+
+	return s.sendAndWaitForAck(req)
+}
+
 // DeleteLink deletes a network interface.
-func (Netlink) DeleteLink(name string) error {
+func (n Netlink) DeleteLink(name string) error {
 	if name == "" {
 		log.Printf("[net] Invalid link name. Not returning error")
 		return nil
 	}
 
-	iface, err := net.InterfaceByName(name)
+	iface, err := n.interfaceByName(name)
 	if err != nil {
 		log.Printf("[net] Interface not found. Not returning error")
 		return nil
 	}
 
-	s, err := getSocket()
+	s, err := n.socket()
 	if err != nil {
 		return err
 	}
@@ -210,13 +629,13 @@ func (Netlink) DeleteLink(name string) error {
 }
 
 // SetLinkName sets the name of a network interface.
-func (Netlink) SetLinkName(name string, newName string) error {
-	s, err := getSocket()
+func (n Netlink) SetLinkName(name string, newName string) error {
+	s, err := n.socket()
 	if err != nil {
 		return err
 	}
 
-	iface, err := net.InterfaceByName(name)
+	iface, err := n.interfaceByName(name)
 	if err != nil {
 		return err
 	}
@@ -237,13 +656,13 @@ func (Netlink) SetLinkName(name string, newName string) error {
 }
 
 // SetLinkState sets the operational state of a network interface.
-func (Netlink) SetLinkState(name string, up bool) error {
-	s, err := getSocket()
+func (n Netlink) SetLinkState(name string, up bool) error {
+	s, err := n.socket()
 	if err != nil {
 		return err
 	}
 
-	iface, err := net.InterfaceByName(name)
+	iface, err := n.interfaceByName(name)
 	if err != nil {
 		return err
 	}
@@ -268,20 +687,20 @@ func (Netlink) SetLinkState(name string, up bool) error {
 }
 
 // SetLinkMaster sets the master (upper) device of a network interface.
-func (Netlink) SetLinkMaster(name string, master string) error {
-	s, err := getSocket()
+func (n Netlink) SetLinkMaster(name string, master string) error {
+	s, err := n.socket()
 	if err != nil {
 		return err
 	}
 
-	iface, err := net.InterfaceByName(name)
+	iface, err := n.interfaceByName(name)
 	if err != nil {
 		return err
 	}
 
 	var masterIndex uint32
 	if master != "" {
-		masterIface, err := net.InterfaceByName(master)
+		masterIface, err := n.interfaceByName(master)
 		if err != nil {
 			return err
 		}
@@ -304,13 +723,13 @@ func (Netlink) SetLinkMaster(name string, master string) error {
 }
 
 // SetLinkNetNs sets the network namespace of a network interface.
-func (Netlink) SetLinkNetNs(name string, fd uintptr) error {
-	s, err := getSocket()
+func (n Netlink) SetLinkNetNs(name string, fd uintptr) error {
+	s, err := n.socket()
 	if err != nil {
 		return err
 	}
 
-	iface, err := net.InterfaceByName(name)
+	iface, err := n.interfaceByName(name)
 	if err != nil {
 		return err
 	}
@@ -331,13 +750,13 @@ func (Netlink) SetLinkNetNs(name string, fd uintptr) error {
 }
 
 // SetLinkAddress sets the link layer hardware address of a network interface.
-func (Netlink) SetLinkAddress(ifName string, hwAddress net.HardwareAddr) error {
-	s, err := getSocket()
+func (n Netlink) SetLinkAddress(ifName string, hwAddress net.HardwareAddr) error {
+	s, err := n.socket()
 	if err != nil {
 		return err
 	}
 
-	iface, err := net.InterfaceByName(ifName)
+	iface, err := n.interfaceByName(ifName)
 	if err != nil {
 		return err
 	}
@@ -358,13 +777,13 @@ func (Netlink) SetLinkAddress(ifName string, hwAddress net.HardwareAddr) error {
 
 // SetLinkPromisc sets the promiscuous mode of a network interface.
 // TODO do we need this function, not used anywhere currently
-func (Netlink) SetLinkPromisc(ifName string, on bool) error {
-	s, err := getSocket()
+func (n Netlink) SetLinkPromisc(ifName string, on bool) error {
+	s, err := n.socket()
 	if err != nil {
 		return err
 	}
 
-	iface, err := net.InterfaceByName(ifName)
+	iface, err := n.interfaceByName(ifName)
 	if err != nil {
 		return err
 	}
@@ -389,13 +808,13 @@ func (Netlink) SetLinkPromisc(ifName string, on bool) error {
 }
 
 // SetLinkHairpin sets the hairpin (reflective relay) mode of a bridged interface.
-func (Netlink) SetLinkHairpin(bridgeName string, on bool) error {
-	s, err := getSocket()
+func (n Netlink) SetLinkHairpin(bridgeName string, on bool) error {
+	s, err := n.socket()
 	if err != nil {
 		return err
 	}
 
-	iface, err := net.InterfaceByName(bridgeName)
+	iface, err := n.interfaceByName(bridgeName)
 	if err != nil {
 		return err
 	}
@@ -423,44 +842,18 @@ func (Netlink) SetLinkHairpin(bridgeName string, on bool) error {
 }
 
 // SetOrRemoveLinkAddress sets/removes static arp entry based on mode
-func (Netlink) SetOrRemoveLinkAddress(linkInfo LinkInfo, mode, linkState int) error {
-	s, err := getSocket()
-	if err != nil {
-		return err
-	}
-
-	var req *message
-	state := 0
-	if mode == ADD {
-		req = newRequest(unix.RTM_NEWNEIGH, unix.NLM_F_CREATE|unix.NLM_F_REPLACE|unix.NLM_F_ACK)
-	} else {
-		req = newRequest(unix.RTM_DELNEIGH, unix.NLM_F_ACK)
-	}
-	state = linkState
-
-	iface, err := net.InterfaceByName(linkInfo.Name)
+func (n Netlink) SetOrRemoveLinkAddress(linkInfo LinkInfo, mode, linkState int) error {
+	iface, err := n.interfaceByName(linkInfo.Name)
 	if err != nil {
 		return err
 	}
 
-	msg := neighMsg{
-		Family: uint8(GetIPAddressFamily(linkInfo.IPAddr)),
-		Index:  uint32(iface.Index),
-		State:  uint16(state),
+	neigh := &Neigh{
+		LinkIndex:    iface.Index,
+		State:        linkState,
+		IP:           linkInfo.IPAddr,
+		HardwareAddr: linkInfo.MacAddress,
 	}
 
-	req.addPayload(&msg)
-
-	ipData := linkInfo.IPAddr.To4()
-	if ipData == nil {
-		ipData = linkInfo.IPAddr.To16()
-	}
-
-	dstData := newRtAttr(NDA_DST, ipData)
-	req.addPayload(dstData)
-
-	hwData := newRtAttr(NDA_LLADDR, []byte(linkInfo.MacAddress))
-	req.addPayload(hwData)
-
-	return s.sendAndWaitForAck(req)
+	return n.setNeigh(neigh, mode == ADD)
 }
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"reflect"
 )
 
 const BadEth = "badeth"
@@ -17,11 +18,16 @@ func newErrorMockNetlink(errStr string) error {
 
 type routeValidateFn func(route *Route) error
 
+type getRouteFn func(filter *Route) ([]*Route, error)
+
 type MockNetlink struct {
 	returnError   bool
 	errorString   string
 	deleteRouteFn routeValidateFn
 	addRouteFn    routeValidateFn
+	getRouteFn    getRouteFn
+	rules         []*Rule
+	neighs        []*Neigh
 }
 
 func NewMockNetlink(returnError bool, errorString string) *MockNetlink {
@@ -39,6 +45,10 @@ func (f *MockNetlink) SetAddRouteValidationFn(fn routeValidateFn) {
 	f.addRouteFn = fn
 }
 
+func (f *MockNetlink) SetGetRouteFn(fn getRouteFn) {
+	f.getRouteFn = fn
+}
+
 func (f *MockNetlink) error() error {
 	if f.returnError {
 		return newErrorMockNetlink(f.errorString)
@@ -50,10 +60,30 @@ func (f *MockNetlink) AddLink(l Link) error {
 	return f.error()
 }
 
+func (f *MockNetlink) ListLinks() ([]Link, error) {
+	return nil, f.error()
+}
+
+func (f *MockNetlink) GetLinkByName(name string) (Link, error) {
+	return &LinkInfo{Name: name}, f.error()
+}
+
+func (f *MockNetlink) GetLinkByIndex(int) (Link, error) {
+	return &LinkInfo{}, f.error()
+}
+
 func (f *MockNetlink) SetLinkMTU(name string, mtu int) error {
 	return f.error()
 }
 
+func (f *MockNetlink) SetLinkTxQLen(string, int) error {
+	return f.error()
+}
+
+func (f *MockNetlink) SetLinkOffload(string, Offload, bool) error {
+	return f.error()
+}
+
 func (f *MockNetlink) DeleteLink(name string) error {
 	return f.error()
 }
@@ -102,7 +132,10 @@ func (f *MockNetlink) DeleteIPAddress(string, net.IP, *net.IPNet) error {
 	return f.error()
 }
 
-func (f *MockNetlink) GetIPRoute(*Route) ([]*Route, error) {
+func (f *MockNetlink) GetIPRoute(filter *Route) ([]*Route, error) {
+	if f.getRouteFn != nil {
+		return f.getRouteFn(filter)
+	}
 	return nil, f.error()
 }
 
@@ -119,3 +152,109 @@ func (f *MockNetlink) DeleteIPRoute(r *Route) error {
 	}
 	return f.error()
 }
+
+// AddRule keeps the rule, to be returned by ListRules.
+func (f *MockNetlink) AddRule(rule *Rule) error {
+	if err := f.error(); err != nil {
+		return err
+	}
+	for _, r := range f.rules {
+		if reflect.DeepEqual(r, rule) {
+			return newErrorMockNetlink("rule exists")
+		}
+	}
+	r := *rule
+	f.rules = append(f.rules, &r)
+	return nil
+}
+
+func (f *MockNetlink) DeleteRule(rule *Rule) error {
+	if err := f.error(); err != nil {
+		return err
+	}
+	for i, r := range f.rules {
+		if reflect.DeepEqual(r, rule) {
+			f.rules = append(f.rules[:i], f.rules[i+1:]...)
+			return nil
+		}
+	}
+	return newErrorMockNetlink("rule not found")
+}
+
+func (f *MockNetlink) ListRules(family int) ([]*Rule, error) {
+	if err := f.error(); err != nil {
+		return nil, err
+	}
+	var rules []*Rule
+	for _, r := range f.rules {
+		if family == 0 || r.Family == family {
+			rules = append(rules, r)
+		}
+	}
+	return rules, nil
+}
+
+// AddNeigh keeps the neighbor entry, replacing the entry for its IP address on the link, to be returned by ListNeigh.
+func (f *MockNetlink) AddNeigh(neigh *Neigh) error {
+	if err := f.error(); err != nil {
+		return err
+	}
+	n := *neigh
+	for i := range f.neighs {
+		if f.neighs[i].LinkIndex == neigh.LinkIndex && f.neighs[i].IP.Equal(neigh.IP) {
+			f.neighs[i] = &n
+			return nil
+		}
+	}
+	f.neighs = append(f.neighs, &n)
+	return nil
+}
+
+func (f *MockNetlink) DeleteNeigh(neigh *Neigh) error {
+	if err := f.error(); err != nil {
+		return err
+	}
+	for i := range f.neighs {
+		if f.neighs[i].LinkIndex == neigh.LinkIndex && f.neighs[i].IP.Equal(neigh.IP) {
+			f.neighs = append(f.neighs[:i], f.neighs[i+1:]...)
+			return nil
+		}
+	}
+	return newErrorMockNetlink("neighbor not found")
+}
+
+func (f *MockNetlink) ListNeigh(linkIndex, family int) ([]*Neigh, error) {
+	if err := f.error(); err != nil {
+		return nil, err
+	}
+	var neighs []*Neigh
+	for _, n := range f.neighs {
+		if (linkIndex == 0 || n.LinkIndex == linkIndex) && (family == 0 || n.Family == family) {
+			neighs = append(neighs, n)
+		}
+	}
+	return neighs, nil
+}
+
+func (f *MockNetlink) AddQdisc(Qdisc) error {
+	return f.error()
+}
+
+func (f *MockNetlink) DeleteQdisc(Qdisc) error {
+	return f.error()
+}
+
+func (f *MockNetlink) AddRedirectFilter(*RedirectFilter) error {
+	return f.error()
+}
+
+func (f *MockNetlink) Subscribe(events chan<- *Event, done <-chan struct{}, _ ...int) error {
+	if err := f.error(); err != nil {
+		return err
+	}
+	go func() {
+		<-done
+		close(events)
+	}()
+	return nil
+}
@@ -0,0 +1,114 @@
+// Copyright 2017 Microsoft. All rights reserved.
+// MIT License
+
+//go:build linux
+// +build linux
+
+package netlink
+
+import (
+	"net"
+
+	"golang.org/x/sys/unix"
+)
+
+// deserializeNeigh decodes a netlink message into a Neigh struct.
+func deserializeNeigh(msg *message) *Neigh {
+	neigh := Neigh{
+		Family:    int(msg.data[0]),
+		LinkIndex: int(int32(encoder.Uint32(msg.data[4:8]))),
+		State:     int(encoder.Uint16(msg.data[8:10])),
+		Flags:     int(msg.data[10]),
+	}
+
+	for _, attr := range msg.getAttributes(nil) {
+		switch attr.Type {
+		case NDA_DST:
+			neigh.IP = net.IP(attr.value)
+		case NDA_LLADDR:
+			neigh.HardwareAddr = net.HardwareAddr(attr.value)
+		}
+	}
+
+	return &neigh
+}
+
+// setNeigh sends a neighbor entry set request.
+func (n Netlink) setNeigh(neigh *Neigh, add bool) error {
+	var msgType, flags int
+
+	s, err := n.socket()
+	if err != nil {
+		return err
+	}
+
+	if add {
+		msgType = unix.RTM_NEWNEIGH
+		flags = unix.NLM_F_CREATE | unix.NLM_F_REPLACE | unix.NLM_F_ACK
+	} else {
+		msgType = unix.RTM_DELNEIGH
+		flags = unix.NLM_F_ACK
+	}
+
+	req := newRequest(msgType, flags)
+
+	family := neigh.Family
+	if family == 0 {
+		family = GetIPAddressFamily(neigh.IP)
+	}
+
+	req.addPayload(&neighMsg{
+		Family: uint8(family),
+		Index:  uint32(neigh.LinkIndex),
+		State:  uint16(neigh.State),
+		Flags:  uint8(neigh.Flags),
+	})
+
+	req.addPayload(newAttributeIpAddress(NDA_DST, neigh.IP))
+
+	if neigh.HardwareAddr != nil {
+		req.addPayload(newAttribute(NDA_LLADDR, []byte(neigh.HardwareAddr)))
+	}
+
+	return s.sendAndWaitForAck(req)
+}
+
+// AddNeigh adds a neighbor entry, replacing any existing entry for its IP address on the link.
+func (n Netlink) AddNeigh(neigh *Neigh) error {
+	return n.setNeigh(neigh, true)
+}
+
+// DeleteNeigh deletes the neighbor entry for an IP address on a link.
+func (n Netlink) DeleteNeigh(neigh *Neigh) error {
+	return n.setNeigh(neigh, false)
+}
+
+// ListNeigh returns the neighbor entries of an address family on a link, or on all links if linkIndex is zero.
+func (n Netlink) ListNeigh(linkIndex, family int) ([]*Neigh, error) {
+	s, err := n.socket()
+	if err != nil {
+		return nil, err
+	}
+
+	req := newRequest(unix.RTM_GETNEIGH, unix.NLM_F_DUMP)
+	req.addPayload(&neighMsg{Family: uint8(family), Index: uint32(linkIndex)})
+
+	msgs, err := s.sendAndWaitForResponse(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var neighs []*Neigh
+	for _, msg := range msgs {
+		neigh := deserializeNeigh(msg)
+
+		// Filter by link index, as older kernels dump all links.
+		if linkIndex != 0 && neigh.LinkIndex != linkIndex {
+			continue
+		}
+
+		neighs = append(neighs, neigh)
+	}
+
+	return neighs, nil
+}
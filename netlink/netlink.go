@@ -1,6 +1,12 @@
 package netlink
 
-type Netlink struct{}
+// Netlink sends requests on the netlink socket of the network namespace it was created in.
+type Netlink struct {
+	// s is the socket of a netlink created for another network namespace, nil for the default socket.
+	s *socket
+	// nsPath is the path of the network namespace of a netlink created for another network namespace.
+	nsPath string
+}
 
 func NewNetlink() *Netlink {
 	return &Netlink{}
@@ -7,6 +7,12 @@
 package netlink
 
 import (
+	"fmt"
+	"net"
+	"os"
+	"runtime"
+
+	"github.com/pkg/errors"
 	"golang.org/x/sys/unix"
 )
 
@@ -32,3 +38,101 @@ func Echo(text string) error {
 
 	return s.sendAndWaitForAck(req)
 }
+
+// NewNetlinkInNamespace returns a netlink sending its requests in the network namespace at nsPath, without the
+// caller entering the namespace. It must be closed when no longer used.
+func NewNetlinkInNamespace(nsPath string) (*Netlink, error) {
+	s, err := newSocketInNamespace(nsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Netlink{s: s, nsPath: nsPath}, nil
+}
+
+// Creates a new netlink socket in the network namespace at nsPath, joined to the given multicast groups.
+func newSocketInNamespace(nsPath string, groups ...int) (*socket, error) {
+	var (
+		s       *socket
+		sockErr error
+	)
+	// The socket is bound to the network namespace of the thread creating it.
+	if err := inNamespace(nsPath, func() { s, sockErr = newSocket(groups...) }); err != nil {
+		if s != nil {
+			s.close()
+		}
+		return nil, err
+	}
+
+	if sockErr != nil {
+		return nil, sockErr
+	}
+
+	return s, nil
+}
+
+// Runs f on a thread in the network namespace at nsPath.
+func inNamespace(nsPath string, f func()) error {
+	ns, err := os.Open(nsPath)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open network namespace %s", nsPath)
+	}
+	defer ns.Close()
+
+	runtime.LockOSThread()
+
+	current, err := os.Open(fmt.Sprintf("/proc/%d/task/%d/ns/net", os.Getpid(), unix.Gettid()))
+	if err != nil {
+		runtime.UnlockOSThread()
+		return errors.Wrap(err, "failed to open current network namespace")
+	}
+	defer current.Close()
+
+	if err = unix.Setns(int(ns.Fd()), unix.CLONE_NEWNET); err != nil {
+		runtime.UnlockOSThread()
+		return errors.Wrapf(err, "failed to enter network namespace %s", nsPath)
+	}
+
+	f()
+
+	if err = unix.Setns(int(current.Fd()), unix.CLONE_NEWNET); err != nil {
+		// Leave the thread locked so it exits with the goroutine instead of running others in the wrong namespace.
+		return errors.Wrap(err, "failed to return to the current network namespace")
+	}
+	runtime.UnlockOSThread()
+
+	return nil
+}
+
+// Close closes the socket of a netlink created for another network namespace.
+func (n *Netlink) Close() error {
+	if n.s != nil {
+		n.s.close()
+		n.s = nil
+		n.nsPath = ""
+	}
+	return nil
+}
+
+// Returns the socket to send requests on.
+func (n Netlink) socket() (*socket, error) {
+	if n.s != nil {
+		return n.s, nil
+	}
+	return getSocket()
+}
+
+// Returns the network interface with the given name, in the network namespace of the netlink.
+func (n Netlink) interfaceByName(name string) (*net.Interface, error) {
+	if n.s == nil {
+		return net.InterfaceByName(name)
+	}
+
+	link, err := n.GetLinkByName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	info := link.Info()
+	return &net.Interface{Index: info.Index, MTU: int(info.MTU), Name: info.Name, HardwareAddr: info.MacAddress, Flags: info.Flags}, nil
+}
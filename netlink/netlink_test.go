@@ -7,8 +7,12 @@
 package netlink
 
 import (
+	"fmt"
 	"net"
+	"os"
+	"runtime"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"golang.org/x/sys/unix"
@@ -133,6 +137,63 @@ func TestSetMTU(t *testing.T) {
 	require.Equal(t, 1028, iface.MTU, "Expected mtu:1024 but got %d", iface.MTU)
 }
 
+// TestSetLinkTxQLen tests setting the transmit queue length of a link.
+func TestSetLinkTxQLen(t *testing.T) {
+	link := VEthLink{
+		LinkInfo: LinkInfo{
+			Type: LINK_TYPE_VETH,
+			Name: ifName,
+		},
+		PeerName: ifName2,
+	}
+	nl := NewNetlink()
+
+	err := nl.AddLink(&link)
+	require.NoError(t, err)
+
+	//nolint:errcheck // not testing deletelink here
+	defer nl.DeleteLink(ifName)
+
+	err = nl.SetLinkTxQLen(ifName, 2000)
+	require.NoError(t, err)
+
+	got, err := nl.GetLinkByName(ifName)
+	require.NoError(t, err)
+	require.Equal(t, uint(2000), got.Info().TxQLen)
+}
+
+// TestSetLinkOffload tests turning the offloads of a link off and on.
+func TestSetLinkOffload(t *testing.T) {
+	link := VEthLink{
+		LinkInfo: LinkInfo{
+			Type: LINK_TYPE_VETH,
+			Name: ifName,
+		},
+		PeerName: ifName2,
+	}
+	nl := NewNetlink()
+
+	err := nl.AddLink(&link)
+	require.NoError(t, err)
+
+	//nolint:errcheck // not testing deletelink here
+	defer nl.DeleteLink(ifName)
+
+	for offload, getCmd := range map[Offload]uint32{OffloadTSO: unix.ETHTOOL_GTSO, OffloadGRO: unix.ETHTOOL_GGRO} {
+		err = nl.SetLinkOffload(ifName, offload, false)
+		require.NoError(t, err)
+		on, err := nl.ethtool(ifName, getCmd, 0)
+		require.NoError(t, err)
+		require.Zero(t, on, "%s should be off", offload)
+
+		err = nl.SetLinkOffload(ifName, offload, true)
+		require.NoError(t, err)
+		on, err = nl.ethtool(ifName, getCmd, 0)
+		require.NoError(t, err)
+		require.Equal(t, uint32(1), on, "%s should be on", offload)
+	}
+}
+
 // TestAddDeleteIPVlan tests adding and deleting an IPVLAN interface.
 func TestAddDeleteIPVlan(t *testing.T) {
 	dummy, err := addDummyInterface(dummyName)
@@ -360,12 +421,12 @@ func TestAddDeleteRoute(t *testing.T) {
 		Scope:     RT_SCOPE_LINK,
 	}
 
-	err = setIpRoute(&route, true)
+	err = nl.setIpRoute(&route, true)
 	if err != nil {
 		t.Errorf("ret val %v", err)
 	}
 
-	err = setIpRoute(&route, false)
+	err = nl.setIpRoute(&route, false)
 	if err != nil {
 		t.Errorf("ret val %v", err)
 	}
@@ -375,3 +436,304 @@ func TestAddDeleteRoute(t *testing.T) {
 		t.Errorf("DeleteLink failed: %+v", err)
 	}
 }
+
+// TestAddDeleteQdisc tests shaping the traffic sent on an interface and redirected to an ifb interface.
+func TestAddDeleteQdisc(t *testing.T) {
+	nl := NewNetlink()
+	err := nl.AddLink(&VEthLink{
+		LinkInfo: LinkInfo{
+			Type: LINK_TYPE_VETH,
+			Name: ifName,
+		},
+		PeerName: dummyName,
+	})
+	require.NoError(t, err)
+	defer nl.DeleteLink(ifName) //nolint:errcheck // cleanup
+
+	err = nl.AddLink(&IFBLink{
+		LinkInfo: LinkInfo{
+			Type:  LINK_TYPE_IFB,
+			Name:  ifName2,
+			Flags: net.FlagUp,
+		},
+	})
+	require.NoError(t, err)
+	defer nl.DeleteLink(ifName2) //nolint:errcheck // cleanup
+
+	tbf := &TbfQdisc{
+		QdiscInfo: QdiscInfo{LinkName: ifName2, Handle: MakeHandle(1, 0), Parent: TC_H_ROOT},
+		Rate:      125000,
+		Burst:     12500,
+		Limit:     15625,
+	}
+	require.NoError(t, nl.AddQdisc(tbf))
+
+	ingress := &IngressQdisc{QdiscInfo: QdiscInfo{LinkName: ifName, Handle: MakeHandle(0xffff, 0), Parent: TC_H_INGRESS}}
+	require.NoError(t, nl.AddQdisc(ingress))
+	require.NoError(t, nl.AddRedirectFilter(&RedirectFilter{LinkName: ifName, Parent: ingress.Handle, Priority: 1, RedirectTo: ifName2}))
+
+	require.NoError(t, nl.DeleteQdisc(ingress))
+	require.NoError(t, nl.DeleteQdisc(tbf))
+	require.Error(t, nl.DeleteQdisc(tbf))
+}
+
+// TestAddDeleteRule tests adding, listing and deleting a policy routing rule.
+func TestAddDeleteRule(t *testing.T) {
+	nl := NewNetlink()
+	_, src, _ := net.ParseCIDR("10.1.0.0/16")
+	rule := &Rule{
+		Family:   unix.AF_INET,
+		Priority: 1000,
+		Table:    1000,
+		Mark:     333,
+		Mask:     0xffff,
+		Src:      src,
+		IifName:  "lo",
+	}
+
+	require.NoError(t, nl.AddRule(rule))
+	require.Error(t, nl.AddRule(rule))
+
+	rules, err := nl.ListRules(unix.AF_INET)
+	require.NoError(t, err)
+	var found bool
+	for _, r := range rules {
+		if r.Priority == rule.Priority {
+			require.Equal(t, rule, r)
+			found = true
+		}
+	}
+	require.True(t, found, "rule not listed")
+
+	require.NoError(t, nl.DeleteRule(rule))
+	require.Error(t, nl.DeleteRule(rule))
+}
+
+// TestAddDeleteNeigh tests adding, listing and deleting a neighbor entry.
+func TestAddDeleteNeigh(t *testing.T) {
+	nl := NewNetlink()
+	err := nl.AddLink(&VEthLink{
+		LinkInfo: LinkInfo{
+			Type: LINK_TYPE_VETH,
+			Name: ifName,
+		},
+		PeerName: ifName2,
+	})
+	require.NoError(t, err)
+	defer nl.DeleteLink(ifName) //nolint:errcheck // cleanup
+
+	iface, err := net.InterfaceByName(ifName)
+	require.NoError(t, err)
+	mac, _ := net.ParseMAC("12:34:56:78:9a:bc")
+	neigh := &Neigh{
+		LinkIndex:    iface.Index,
+		State:        NUD_PERMANENT,
+		IP:           net.ParseIP("169.254.2.1").To4(),
+		HardwareAddr: mac,
+	}
+
+	require.NoError(t, nl.AddNeigh(neigh))
+	// adding again replaces the entry
+	require.NoError(t, nl.AddNeigh(neigh))
+
+	neighs, err := nl.ListNeigh(iface.Index, unix.AF_INET)
+	require.NoError(t, err)
+	require.Len(t, neighs, 1)
+	require.Equal(t, &Neigh{
+		Family:       unix.AF_INET,
+		LinkIndex:    iface.Index,
+		State:        NUD_PERMANENT,
+		IP:           neigh.IP,
+		HardwareAddr: mac,
+	}, neighs[0])
+
+	require.NoError(t, nl.DeleteNeigh(neigh))
+	neighs, err = nl.ListNeigh(iface.Index, unix.AF_INET)
+	require.NoError(t, err)
+	require.Empty(t, neighs)
+}
+
+// TestListGetLinks tests reading back the attributes of links of each type.
+func TestListGetLinks(t *testing.T) {
+	nl := NewNetlink()
+	err := nl.AddLink(&VEthLink{
+		LinkInfo: LinkInfo{
+			Type:  LINK_TYPE_VETH,
+			Name:  ifName,
+			Flags: net.FlagUp,
+			MTU:   1400,
+		},
+		PeerName: ifName2,
+	})
+	require.NoError(t, err)
+	defer nl.DeleteLink(ifName) //nolint:errcheck // cleanup
+
+	link, err := nl.GetLinkByName(ifName)
+	require.NoError(t, err)
+	veth, ok := link.(*VEthLink)
+	require.True(t, ok, "got %T", link)
+	require.Equal(t, LINK_TYPE_VETH, veth.Type)
+	require.Equal(t, uint(1400), veth.MTU)
+	require.NotZero(t, veth.Flags&net.FlagUp)
+	require.Equal(t, -1, veth.NetNsID)
+	require.NotNil(t, veth.Stats)
+
+	peer, err := nl.GetLinkByIndex(veth.ParentIndex)
+	require.NoError(t, err)
+	require.Equal(t, ifName2, peer.Info().Name)
+	require.Equal(t, veth.Index, peer.Info().ParentIndex)
+
+	links := []Link{
+		&VlanLink{LinkInfo: LinkInfo{Type: LINK_TYPE_VLAN, Name: "nlvlan", ParentIndex: veth.Index}, VlanId: 10},
+		&MacvlanLink{LinkInfo: LinkInfo{Type: LINK_TYPE_MACVLAN, Name: "nlmacvlan", ParentIndex: veth.Index}, Mode: MACVLAN_MODE_BRIDGE},
+		&VxlanLink{
+			LinkInfo:     LinkInfo{Type: LINK_TYPE_VXLAN, Name: "nlvxlan"},
+			VxlanId:      42,
+			VtepDevIndex: veth.Index,
+			SrcAddr:      net.ParseIP("10.0.0.1").To4(),
+			Port:         4789,
+			TTL:          64,
+		},
+	}
+	for _, l := range links {
+		require.NoError(t, nl.AddLink(l))
+		defer nl.DeleteLink(l.Info().Name) //nolint:errcheck // cleanup
+
+		got, err := nl.GetLinkByName(l.Info().Name)
+		require.NoError(t, err)
+		switch want := l.(type) {
+		case *VlanLink:
+			require.Equal(t, want.VlanId, got.(*VlanLink).VlanId)
+			require.Equal(t, veth.Index, got.Info().ParentIndex)
+		case *MacvlanLink:
+			require.Equal(t, want.Mode, got.(*MacvlanLink).Mode)
+		case *VxlanLink:
+			vxlan := got.(*VxlanLink)
+			require.Equal(t, want.VxlanId, vxlan.VxlanId)
+			require.Equal(t, want.VtepDevIndex, vxlan.VtepDevIndex)
+			require.Equal(t, want.SrcAddr, vxlan.SrcAddr)
+			require.Equal(t, want.Port, vxlan.Port)
+			require.Equal(t, want.TTL, vxlan.TTL)
+			require.False(t, vxlan.Learning)
+		}
+	}
+
+	all, err := nl.ListLinks()
+	require.NoError(t, err)
+	names := map[string]string{}
+	for _, l := range all {
+		names[l.Info().Name] = l.Info().Type
+	}
+	require.Equal(t, LINK_TYPE_VETH, names[ifName2])
+	require.Equal(t, LINK_TYPE_VLAN, names["nlvlan"])
+	require.Equal(t, LINK_TYPE_MACVLAN, names["nlmacvlan"])
+	require.Equal(t, LINK_TYPE_VXLAN, names["nlvxlan"])
+	require.Contains(t, names, "lo")
+
+	_, err = nl.GetLinkByName("nlmissing")
+	require.Error(t, err)
+}
+
+// TestNetlinkInNamespace tests managing links in another network namespace without entering it.
+func TestNetlinkInNamespace(t *testing.T) {
+	// Create a network namespace, kept open by its file.
+	runtime.LockOSThread()
+	current, err := os.Open(fmt.Sprintf("/proc/self/task/%d/ns/net", unix.Gettid()))
+	require.NoError(t, err)
+	defer current.Close()
+	require.NoError(t, unix.Unshare(unix.CLONE_NEWNET))
+	ns, err := os.Open(fmt.Sprintf("/proc/self/task/%d/ns/net", unix.Gettid()))
+	require.NoError(t, unix.Setns(int(current.Fd()), unix.CLONE_NEWNET))
+	runtime.UnlockOSThread()
+	require.NoError(t, err)
+	defer ns.Close()
+
+	nl, err := NewNetlinkInNamespace(fmt.Sprintf("/proc/self/fd/%d", ns.Fd()))
+	require.NoError(t, err)
+	defer nl.Close()
+
+	err = nl.AddLink(&VEthLink{
+		LinkInfo: LinkInfo{
+			Type: LINK_TYPE_VETH,
+			Name: ifName,
+		},
+		PeerName: ifName2,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, nl.SetLinkState(ifName, true))
+	link, err := nl.GetLinkByName(ifName)
+	require.NoError(t, err)
+	require.NotZero(t, link.Info().Flags&net.FlagUp)
+
+	_, err = net.InterfaceByName(ifName)
+	require.Error(t, err, "link created in the current namespace")
+
+	require.NoError(t, nl.DeleteLink(ifName))
+	_, err = nl.GetLinkByName(ifName)
+	require.Error(t, err)
+}
+
+// Returns the next event matching the filter, failing the test if none is received in time.
+func waitForEvent(t *testing.T, events <-chan *Event, match func(*Event) bool) *Event {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case event, ok := <-events:
+			require.True(t, ok, "subscription ended")
+			if match(event) {
+				return event
+			}
+		case <-timeout:
+			require.FailNow(t, "no matching event received")
+		}
+	}
+}
+
+// TestSubscribe tests receiving link and route changes.
+func TestSubscribe(t *testing.T) {
+	nl := NewNetlink()
+	events := make(chan *Event, 64)
+	done := make(chan struct{})
+	require.NoError(t, nl.Subscribe(events, done, RTNLGRP_LINK, RTNLGRP_IPV4_ROUTE))
+
+	err := nl.AddLink(&VEthLink{
+		LinkInfo: LinkInfo{
+			Type:  LINK_TYPE_VETH,
+			Name:  ifName,
+			Flags: net.FlagUp,
+		},
+		PeerName: ifName2,
+	})
+	require.NoError(t, err)
+	defer nl.DeleteLink(ifName) //nolint:errcheck // cleanup
+
+	event := waitForEvent(t, events, func(e *Event) bool {
+		return e.Type == EVENT_LINK && !e.Deleted && e.Link.Info().Name == ifName
+	})
+	require.Equal(t, LINK_TYPE_VETH, event.Link.Info().Type)
+	index := event.Link.Info().Index
+
+	_, dst, _ := net.ParseCIDR("10.1.2.3/32")
+	route := &Route{Family: unix.AF_INET, Dst: dst, LinkIndex: index, Scope: RT_SCOPE_LINK}
+	require.NoError(t, nl.AddIPRoute(route))
+	event = waitForEvent(t, events, func(e *Event) bool { return e.Type == EVENT_ROUTE })
+	require.False(t, event.Deleted)
+	require.Equal(t, dst.String(), event.Route.Dst.String())
+	require.Equal(t, index, event.Route.LinkIndex)
+
+	require.NoError(t, nl.DeleteIPRoute(route))
+	event = waitForEvent(t, events, func(e *Event) bool { return e.Type == EVENT_ROUTE })
+	require.True(t, event.Deleted)
+
+	// The routes of a deleted link go without notifications.
+	require.NoError(t, nl.DeleteLink(ifName))
+	waitForEvent(t, events, func(e *Event) bool {
+		return e.Type == EVENT_LINK && e.Deleted && e.Link.Info().Index == index
+	})
+
+	close(done)
+	for range events {
+	}
+}
@@ -3,7 +3,10 @@
 
 package netlink
 
-import "net"
+import (
+	"errors"
+	"net"
+)
 
 // Link represents a network interface.
 type Link interface {
@@ -26,10 +29,30 @@ func (Netlink) AddLink(link Link) error {
 	return nil
 }
 
+func (Netlink) ListLinks() ([]Link, error) {
+	return nil, nil
+}
+
+func (Netlink) GetLinkByName(name string) (Link, error) {
+	return &LinkInfo{Name: name}, nil
+}
+
+func (Netlink) GetLinkByIndex(index int) (Link, error) {
+	return &LinkInfo{}, nil
+}
+
 func (Netlink) SetLinkMTU(name string, mtu int) error {
 	return nil
 }
 
+func (Netlink) SetLinkTxQLen(name string, txQLen int) error {
+	return nil
+}
+
+func (Netlink) SetLinkOffload(name string, offload Offload, on bool) error {
+	return nil
+}
+
 func (Netlink) DeleteLink(name string) error {
 	return nil
 }
@@ -85,3 +108,54 @@ func (Netlink) AddIPRoute(route *Route) error {
 func (Netlink) DeleteIPRoute(route *Route) error {
 	return nil
 }
+
+func (Netlink) AddRule(rule *Rule) error {
+	return nil
+}
+
+func (Netlink) DeleteRule(rule *Rule) error {
+	return nil
+}
+
+func (Netlink) ListRules(family int) ([]*Rule, error) {
+	return nil, nil
+}
+
+func (Netlink) AddNeigh(neigh *Neigh) error {
+	return nil
+}
+
+func (Netlink) DeleteNeigh(neigh *Neigh) error {
+	return nil
+}
+
+func (Netlink) ListNeigh(linkIndex, family int) ([]*Neigh, error) {
+	return nil, nil
+}
+
+func (Netlink) AddQdisc(qdisc Qdisc) error {
+	return nil
+}
+
+func (Netlink) DeleteQdisc(qdisc Qdisc) error {
+	return nil
+}
+
+func (Netlink) AddRedirectFilter(filter *RedirectFilter) error {
+	return nil
+}
+
+func (Netlink) Subscribe(events chan<- *Event, done <-chan struct{}, groups ...int) error {
+	return errors.New("netlink subscriptions are not supported on windows")
+}
+
+type socket struct{}
+
+// NewNetlinkInNamespace is not supported on windows.
+func NewNetlinkInNamespace(nsPath string) (*Netlink, error) {
+	return &Netlink{}, nil
+}
+
+func (*Netlink) Close() error {
+	return nil
+}
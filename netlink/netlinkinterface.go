@@ -9,10 +9,15 @@ import (
 
 type NetlinkInterface interface {
 	AddLink(link Link) error
+	ListLinks() ([]Link, error)
+	GetLinkByName(name string) (Link, error)
+	GetLinkByIndex(index int) (Link, error)
 	DeleteLink(name string) error
 	SetLinkName(name string, newName string) error
 	SetLinkState(name string, up bool) error
 	SetLinkMTU(name string, mtu int) error
+	SetLinkTxQLen(name string, txQLen int) error
+	SetLinkOffload(name string, offload Offload, on bool) error
 	SetLinkMaster(name string, master string) error
 	SetLinkNetNs(name string, fd uintptr) error
 	SetLinkAddress(ifName string, hwAddress net.HardwareAddr) error
@@ -24,4 +29,14 @@ type NetlinkInterface interface {
 	GetIPRoute(filter *Route) ([]*Route, error)
 	AddIPRoute(route *Route) error
 	DeleteIPRoute(route *Route) error
+	AddRule(rule *Rule) error
+	DeleteRule(rule *Rule) error
+	ListRules(family int) ([]*Rule, error)
+	AddNeigh(neigh *Neigh) error
+	DeleteNeigh(neigh *Neigh) error
+	ListNeigh(linkIndex, family int) ([]*Neigh, error)
+	AddQdisc(qdisc Qdisc) error
+	DeleteQdisc(qdisc Qdisc) error
+	AddRedirectFilter(filter *RedirectFilter) error
+	Subscribe(events chan<- *Event, done <-chan struct{}, groups ...int) error
 }
@@ -57,6 +57,25 @@ const (
 	IFLA_BRPORT_MODE = 4
 	VETH_INFO_PEER   = 1
 	DEFAULT_CHANGE   = 0xFFFFFFFF
+	NLA_TYPE_MASK    = ^uint16(unix.NLA_F_NESTED | unix.NLA_F_NET_BYTEORDER)
+)
+
+// Traffic control protocol constants that are not defined in unix package.
+const (
+	TCA_KIND              = 1
+	TCA_OPTIONS           = 2
+	TCA_TBF_PARMS         = 1
+	TCA_TBF_RATE64        = 4
+	TCA_U32_SEL           = 5
+	TCA_U32_ACT           = 7
+	TCA_ACT_KIND          = 1
+	TCA_ACT_OPTIONS       = 2
+	TCA_MIRRED_PARMS      = 2
+	TCA_EGRESS_REDIR      = 1
+	TC_ACT_STOLEN         = 4
+	TC_U32_TERMINAL       = 1
+	TC_LINKLAYER_ETHERNET = 1
+	ETH_P_ALL             = 0x0003
 )
 
 // Serializable types are used to construct netlink messages.
@@ -182,7 +201,34 @@ func (msg *message) getAttributes(body serializable) []*attribute {
 	return attrs
 }
 
-//
+// Parses a buffer of netlink attributes, such as the value of a nested attribute.
+func parseAttributes(b []byte) []*attribute {
+	var attrs []*attribute
+
+	for len(b) >= unix.SizeofNlAttr {
+		l := int(encoder.Uint16(b[0:2]))
+		if l < unix.SizeofNlAttr || l > len(b) {
+			break
+		}
+
+		attrs = append(attrs, &attribute{
+			NlAttr: unix.NlAttr{
+				Len:  uint16(l),
+				Type: encoder.Uint16(b[2:4]) & NLA_TYPE_MASK,
+			},
+			value: b[unix.SizeofNlAttr:l],
+		})
+
+		l = (l + unix.NLA_ALIGNTO - 1) & ^(unix.NLA_ALIGNTO - 1)
+		if l >= len(b) {
+			break
+		}
+		b = b[l:]
+	}
+
+	return attrs
+}
+
 // Netlink message attribute
 //
 // Creates a new attribute.
@@ -456,3 +502,114 @@ func (rta *rtAttr) length() int {
 func (rta *rtAttr) addChild(attr serializable) {
 	rta.children = append(rta.children, attr)
 }
+
+//
+// Traffic control service module
+//
+
+// Traffic control message
+type tcMsg struct {
+	Family  uint8
+	Ifindex int32
+	Handle  uint32
+	Parent  uint32
+	Info    uint32
+}
+
+// Creates a new traffic control message.
+func newTcMsg(ifIndex int, handle, parent uint32) *tcMsg {
+	return &tcMsg{
+		Family:  uint8(unix.AF_UNSPEC),
+		Ifindex: int32(ifIndex),
+		Handle:  handle,
+		Parent:  parent,
+	}
+}
+
+// Serializes a traffic control message.
+func (tc *tcMsg) serialize() []byte {
+	b := make([]byte, tc.length())
+	b[0] = tc.Family
+	encoder.PutUint32(b[4:8], uint32(tc.Ifindex))
+	encoder.PutUint32(b[8:12], tc.Handle)
+	encoder.PutUint32(b[12:16], tc.Parent)
+	encoder.PutUint32(b[16:20], tc.Info)
+	return b
+}
+
+// Returns the length of a traffic control message.
+func (tc *tcMsg) length() int {
+	return 20
+}
+
+// Token bucket filter parameters, struct tc_tbf_qopt.
+type tcTbfQopt struct {
+	RateLinkLayer uint8
+	Rate          uint32
+	Limit         uint32
+	Buffer        uint32
+}
+
+// Serializes token bucket filter parameters. The peak rate is left unset.
+func (opt *tcTbfQopt) serialize() []byte {
+	b := make([]byte, opt.length())
+	b[1] = opt.RateLinkLayer
+	encoder.PutUint32(b[8:12], opt.Rate)
+	encoder.PutUint32(b[24:28], opt.Limit)
+	encoder.PutUint32(b[28:32], opt.Buffer)
+	return b
+}
+
+// Returns the length of token bucket filter parameters.
+func (opt *tcTbfQopt) length() int {
+	return 36
+}
+
+// u32 filter selector matching all packets, struct tc_u32_sel with a single zero key.
+type tcU32MatchAll struct{}
+
+// Serializes a u32 selector matching all packets.
+func (sel *tcU32MatchAll) serialize() []byte {
+	b := make([]byte, sel.length())
+	b[0] = TC_U32_TERMINAL
+	b[2] = 1 // nkeys
+	return b
+}
+
+// Returns the length of a u32 selector with a single key.
+func (sel *tcU32MatchAll) length() int {
+	return 32
+}
+
+// Mirror or redirect action parameters, struct tc_mirred.
+type tcMirred struct {
+	Action  int32
+	Eaction int32
+	Ifindex uint32
+}
+
+// Serializes mirror or redirect action parameters.
+func (m *tcMirred) serialize() []byte {
+	b := make([]byte, m.length())
+	encoder.PutUint32(b[8:12], uint32(m.Action))
+	encoder.PutUint32(b[20:24], uint32(m.Eaction))
+	encoder.PutUint32(b[24:28], m.Ifindex)
+	return b
+}
+
+// Returns the length of mirror or redirect action parameters.
+func (m *tcMirred) length() int {
+	return 28
+}
+
+// Creates a new attribute with a serializable value.
+func newAttributeValue(attrType int, value serializable) *attribute {
+	return newAttribute(attrType, value.serialize())
+}
+
+// Returns a uint16 value in network byte order, as written by the encoder.
+func htons(value uint16) uint16 {
+	b := make([]byte, 2)
+	binary.BigEndian.PutUint16(b, value)
+	return encoder.Uint16(b)
+}
@@ -0,0 +1,53 @@
+// Copyright 2017 Microsoft. All rights reserved.
+// MIT License
+
+package netlink
+
+// Traffic control handles.
+const (
+	TC_H_ROOT    = 0xFFFFFFFF
+	TC_H_INGRESS = 0xFFFFFFF1
+)
+
+// MakeHandle returns the traffic control handle major:minor.
+func MakeHandle(major, minor uint16) uint32 {
+	return uint32(major)<<16 | uint32(minor)
+}
+
+// Qdisc represents a queueing discipline attached to a network interface.
+type Qdisc interface {
+	Info() *QdiscInfo
+}
+
+// QdiscInfo represents the common properties of all queueing disciplines.
+type QdiscInfo struct {
+	LinkName string
+	Handle   uint32
+	Parent   uint32
+}
+
+func (qdiscInfo *QdiscInfo) Info() *QdiscInfo {
+	return qdiscInfo
+}
+
+// TbfQdisc represents a token bucket filter, shaping the traffic sent on the interface.
+type TbfQdisc struct {
+	QdiscInfo
+	Rate  uint64 // bytes per second
+	Burst uint32 // bytes
+	Limit uint32 // bytes which can be queued waiting for tokens
+}
+
+// IngressQdisc represents the ingress queueing discipline, which filters can be attached to.
+type IngressQdisc struct {
+	QdiscInfo
+}
+
+// RedirectFilter is a filter matching all the traffic of its parent qdisc and redirecting it
+// to the egress of another network interface.
+type RedirectFilter struct {
+	LinkName   string
+	Parent     uint32
+	Priority   uint16
+	RedirectTo string
+}
@@ -0,0 +1,180 @@
+// Copyright 2017 Microsoft. All rights reserved.
+// MIT License
+
+//go:build linux
+// +build linux
+
+package netlink
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"sync"
+
+	"github.com/Azure/azure-container-networking/log"
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+)
+
+// Qdisc kinds.
+const (
+	QDISC_KIND_TBF     = "tbf"
+	QDISC_KIND_INGRESS = "ingress"
+)
+
+const (
+	pschedPath     = "/proc/net/psched"
+	timeUnitsInSec = 1000000
+	// Kernel default, used if the packet scheduler clock cannot be read.
+	defaultTickInUsec = 15.625
+)
+
+var (
+	tickInUsec     float64
+	tickInUsecOnce sync.Once
+)
+
+// Returns the number of packet scheduler ticks per microsecond, as tc computes it.
+func getTickInUsec() float64 {
+	tickInUsecOnce.Do(func() {
+		tickInUsec = defaultTickInUsec
+
+		f, err := os.Open(pschedPath)
+		if err != nil {
+			log.Printf("[net] Failed to open %s, using default tick, err:%v", pschedPath, err)
+			return
+		}
+		defer f.Close()
+
+		var t2us, us2t, clockRes uint32
+		if _, err := fmt.Fscanf(f, "%08x%08x%08x", &t2us, &us2t, &clockRes); err != nil || us2t == 0 {
+			log.Printf("[net] Failed to parse %s, using default tick, err:%v", pschedPath, err)
+			return
+		}
+
+		if clockRes == 1000000000 {
+			t2us = us2t
+		}
+		clockFactor := float64(clockRes) / timeUnitsInSec
+		tickInUsec = float64(t2us) / float64(us2t) * clockFactor
+	})
+
+	return tickInUsec
+}
+
+// Returns the ticks needed to transmit size bytes at rate bytes per second.
+func xmitTicks(rate uint64, size uint32) uint32 {
+	if rate == 0 {
+		return 0
+	}
+	ticks := float64(timeUnitsInSec) * float64(size) / float64(rate) * getTickInUsec()
+	if ticks > math.MaxUint32 {
+		return math.MaxUint32
+	}
+	return uint32(ticks)
+}
+
+// Sends a qdisc request for the interface of the qdisc.
+func (n Netlink) sendQdiscRequest(qdisc Qdisc, msgType int, flags int, withOptions bool) error {
+	info := qdisc.Info()
+
+	iface, err := n.interfaceByName(info.LinkName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get interface %s", info.LinkName)
+	}
+
+	s, err := n.socket()
+	if err != nil {
+		return err
+	}
+
+	req := newRequest(msgType, flags)
+	req.addPayload(newTcMsg(iface.Index, info.Handle, info.Parent))
+
+	switch q := qdisc.(type) {
+	case *TbfQdisc:
+		req.addPayload(newAttributeStringZ(TCA_KIND, QDISC_KIND_TBF))
+		if withOptions {
+			opt := &tcTbfQopt{
+				RateLinkLayer: TC_LINKLAYER_ETHERNET,
+				Rate:          uint32(min(q.Rate, math.MaxUint32)),
+				Limit:         q.Limit,
+				Buffer:        xmitTicks(q.Rate, q.Burst),
+			}
+			attrOptions := newAttribute(TCA_OPTIONS, nil)
+			attrOptions.addNested(newAttributeValue(TCA_TBF_PARMS, opt))
+			// Rates which do not fit the 32 bit rate are set separately.
+			if q.Rate >= math.MaxUint32 {
+				buf := make([]byte, 8)
+				encoder.PutUint64(buf, q.Rate)
+				attrOptions.addNested(newAttribute(TCA_TBF_RATE64, buf))
+			}
+			req.addPayload(attrOptions)
+		}
+	case *IngressQdisc:
+		req.addPayload(newAttributeStringZ(TCA_KIND, QDISC_KIND_INGRESS))
+	default:
+		return fmt.Errorf("Unsupported qdisc %T", qdisc)
+	}
+
+	return s.sendAndWaitForAck(req)
+}
+
+// AddQdisc attaches a new queueing discipline to a network interface.
+func (n Netlink) AddQdisc(qdisc Qdisc) error {
+	return n.sendQdiscRequest(qdisc, unix.RTM_NEWQDISC, unix.NLM_F_CREATE|unix.NLM_F_EXCL|unix.NLM_F_ACK, true)
+}
+
+// DeleteQdisc detaches a queueing discipline from a network interface.
+func (n Netlink) DeleteQdisc(qdisc Qdisc) error {
+	return n.sendQdiscRequest(qdisc, unix.RTM_DELQDISC, unix.NLM_F_ACK, false)
+}
+
+// AddRedirectFilter adds a filter redirecting all traffic of a qdisc to another network interface.
+func (n Netlink) AddRedirectFilter(filter *RedirectFilter) error {
+	iface, err := n.interfaceByName(filter.LinkName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get interface %s", filter.LinkName)
+	}
+
+	target, err := n.interfaceByName(filter.RedirectTo)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get interface %s", filter.RedirectTo)
+	}
+
+	s, err := n.socket()
+	if err != nil {
+		return err
+	}
+
+	req := newRequest(unix.RTM_NEWTFILTER, unix.NLM_F_CREATE|unix.NLM_F_EXCL|unix.NLM_F_ACK)
+
+	tcm := newTcMsg(iface.Index, 0, filter.Parent)
+	tcm.Info = uint32(filter.Priority)<<16 | uint32(htons(ETH_P_ALL))
+	req.addPayload(tcm)
+
+	req.addPayload(newAttributeStringZ(TCA_KIND, "u32"))
+
+	// Redirect to the egress of the target interface.
+	attrMirredOptions := newAttribute(TCA_ACT_OPTIONS, nil)
+	attrMirredOptions.addNested(newAttributeValue(TCA_MIRRED_PARMS, &tcMirred{
+		Action:  TC_ACT_STOLEN,
+		Eaction: TCA_EGRESS_REDIR,
+		Ifindex: uint32(target.Index),
+	}))
+
+	attrAction := newAttribute(1, nil) // The first action in the list.
+	attrAction.addNested(newAttributeStringZ(TCA_ACT_KIND, "mirred"))
+	attrAction.addNested(attrMirredOptions)
+
+	attrActions := newAttribute(TCA_U32_ACT, nil)
+	attrActions.addNested(attrAction)
+
+	attrOptions := newAttribute(TCA_OPTIONS, nil)
+	attrOptions.addNested(newAttributeValue(TCA_U32_SEL, &tcU32MatchAll{}))
+	attrOptions.addNested(attrActions)
+	req.addPayload(attrOptions)
+
+	return s.sendAndWaitForAck(req)
+}
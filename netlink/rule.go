@@ -0,0 +1,29 @@
+// Copyright 2017 Microsoft. All rights reserved.
+// MIT License
+
+package netlink
+
+import "net"
+
+// Rule represents a policy routing rule, looking up Table for the packets matching all its set selectors.
+type Rule struct {
+	Family   int
+	Priority int // zero lets the kernel pick the priority of an added rule
+	Table    int
+	Mark     uint32
+	Mask     uint32
+	Src      *net.IPNet
+	Dst      *net.IPNet
+	IifName  string
+	OifName  string
+}
+
+// Neigh represents a neighbor table entry.
+type Neigh struct {
+	Family       int
+	LinkIndex    int
+	State        int
+	Flags        int
+	IP           net.IP
+	HardwareAddr net.HardwareAddr
+}
@@ -0,0 +1,190 @@
+// Copyright 2017 Microsoft. All rights reserved.
+// MIT License
+
+//go:build linux
+// +build linux
+
+package netlink
+
+import (
+	"net"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// Routing rule message, struct fib_rule_hdr.
+type ruleMsg struct {
+	Family uint8
+	DstLen uint8
+	SrcLen uint8
+	Tos    uint8
+	Table  uint8
+	Action uint8
+	Flags  uint32
+}
+
+// Serializes a routing rule message.
+func (msg *ruleMsg) serialize() []byte {
+	b := make([]byte, msg.length())
+	b[0] = msg.Family
+	b[1] = msg.DstLen
+	b[2] = msg.SrcLen
+	b[3] = msg.Tos
+	b[4] = msg.Table
+	b[7] = msg.Action
+	encoder.PutUint32(b[8:12], msg.Flags)
+	return b
+}
+
+// Returns the length of a routing rule message.
+func (msg *ruleMsg) length() int {
+	return 12
+}
+
+// Returns the family of a rule, from its selectors if not set.
+func ruleFamily(rule *Rule) int {
+	switch {
+	case rule.Family != 0:
+		return rule.Family
+	case rule.Src != nil:
+		return GetIPAddressFamily(rule.Src.IP)
+	case rule.Dst != nil:
+		return GetIPAddressFamily(rule.Dst.IP)
+	default:
+		return unix.AF_INET
+	}
+}
+
+// deserializeRule decodes a netlink message into a Rule struct.
+func deserializeRule(msg *message) *Rule {
+	rule := Rule{
+		Family: int(msg.data[0]),
+		Table:  int(msg.data[4]),
+	}
+	dstLen, srcLen := int(msg.data[1]), int(msg.data[2])
+
+	for _, attr := range msg.getAttributes(nil) {
+		switch attr.Type {
+		case unix.FRA_DST:
+			rule.Dst = &net.IPNet{IP: attr.value, Mask: net.CIDRMask(dstLen, 8*len(attr.value))}
+		case unix.FRA_SRC:
+			rule.Src = &net.IPNet{IP: attr.value, Mask: net.CIDRMask(srcLen, 8*len(attr.value))}
+		case unix.FRA_IIFNAME:
+			rule.IifName = strings.TrimRight(string(attr.value), "\x00")
+		case unix.FRA_OIFNAME:
+			rule.OifName = strings.TrimRight(string(attr.value), "\x00")
+		case unix.FRA_PRIORITY:
+			rule.Priority = int(encoder.Uint32(attr.value[0:4]))
+		case unix.FRA_FWMARK:
+			rule.Mark = encoder.Uint32(attr.value[0:4])
+		case unix.FRA_FWMASK:
+			rule.Mask = encoder.Uint32(attr.value[0:4])
+		case unix.FRA_TABLE:
+			rule.Table = int(encoder.Uint32(attr.value[0:4]))
+		}
+	}
+
+	return &rule
+}
+
+// setRule sends a routing rule set request.
+func (n Netlink) setRule(rule *Rule, add bool) error {
+	var msgType, flags int
+
+	s, err := n.socket()
+	if err != nil {
+		return err
+	}
+
+	if add {
+		msgType = unix.RTM_NEWRULE
+		flags = unix.NLM_F_CREATE | unix.NLM_F_EXCL | unix.NLM_F_ACK
+	} else {
+		msgType = unix.RTM_DELRULE
+		flags = unix.NLM_F_ACK
+	}
+
+	req := newRequest(msgType, flags)
+
+	msg := &ruleMsg{
+		Family: uint8(ruleFamily(rule)),
+		Action: unix.FR_ACT_TO_TBL,
+	}
+	// Tables which do not fit the header are only set by attribute.
+	if rule.Table < 256 {
+		msg.Table = uint8(rule.Table)
+	}
+	req.addPayload(msg)
+
+	if rule.Dst != nil {
+		prefixLength, _ := rule.Dst.Mask.Size()
+		msg.DstLen = uint8(prefixLength)
+		req.addPayload(newAttributeIpAddress(unix.FRA_DST, rule.Dst.IP))
+	}
+
+	if rule.Src != nil {
+		prefixLength, _ := rule.Src.Mask.Size()
+		msg.SrcLen = uint8(prefixLength)
+		req.addPayload(newAttributeIpAddress(unix.FRA_SRC, rule.Src.IP))
+	}
+
+	if rule.IifName != "" {
+		req.addPayload(newAttributeStringZ(unix.FRA_IIFNAME, rule.IifName))
+	}
+
+	if rule.OifName != "" {
+		req.addPayload(newAttributeStringZ(unix.FRA_OIFNAME, rule.OifName))
+	}
+
+	if rule.Priority != 0 {
+		req.addPayload(newAttributeUint32(unix.FRA_PRIORITY, uint32(rule.Priority)))
+	}
+
+	if rule.Mark != 0 {
+		req.addPayload(newAttributeUint32(unix.FRA_FWMARK, rule.Mark))
+	}
+
+	if rule.Mask != 0 {
+		req.addPayload(newAttributeUint32(unix.FRA_FWMASK, rule.Mask))
+	}
+
+	if rule.Table != 0 {
+		req.addPayload(newAttributeUint32(unix.FRA_TABLE, uint32(rule.Table)))
+	}
+
+	return s.sendAndWaitForAck(req)
+}
+
+// AddRule adds a policy routing rule.
+func (n Netlink) AddRule(rule *Rule) error {
+	return n.setRule(rule, true)
+}
+
+// DeleteRule deletes the first policy routing rule matching the set fields of the given rule.
+func (n Netlink) DeleteRule(rule *Rule) error {
+	return n.setRule(rule, false)
+}
+
+// ListRules returns the policy routing rules of an address family.
+func (n Netlink) ListRules(family int) ([]*Rule, error) {
+	s, err := n.socket()
+	if err != nil {
+		return nil, err
+	}
+
+	req := newRequest(unix.RTM_GETRULE, unix.NLM_F_DUMP)
+	req.addPayload(&ruleMsg{Family: uint8(family)})
+
+	msgs, err := s.sendAndWaitForResponse(req)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := make([]*Rule, 0, len(msgs))
+	for _, msg := range msgs {
+		rules = append(rules, deserializeRule(msg))
+	}
+
+	return rules, nil
+}
@@ -16,6 +16,9 @@ import (
 	"golang.org/x/sys/unix"
 )
 
+// Size of the buffer receiving netlink messages, large enough for the biggest link messages of a dump.
+const receiveBufferSize = 32 * 1024
+
 // Represents a netlink socket.
 type socket struct {
 	fd  int
@@ -53,8 +56,8 @@ func ResetSocket() {
 	s = nil
 }
 
-// Creates a new netlink socket object.
-func newSocket() (*socket, error) {
+// Creates a new netlink socket object, joined to the given multicast groups.
+func newSocket(groups ...int) (*socket, error) {
 	fd, err := unix.Socket(unix.AF_NETLINK, unix.SOCK_RAW, unix.NETLINK_ROUTE)
 	if err != nil {
 		log.Debugf("[netlink] Failed to create socket, err=%v\n", err)
@@ -76,6 +79,15 @@ func newSocket() (*socket, error) {
 		return nil, err
 	}
 
+	for _, group := range groups {
+		err = unix.SetsockoptInt(fd, unix.SOL_NETLINK, unix.NETLINK_ADD_MEMBERSHIP, group)
+		if err != nil {
+			unix.Close(fd)
+			log.Debugf("[netlink] Failed to join multicast group %d, err=%v\n", group, err)
+			return nil, err
+		}
+	}
+
 	log.Debugf("[netlink] Socket created.\n")
 	return s, nil
 }
@@ -115,7 +127,7 @@ func (s *socket) sendAndWaitForAck(msg *message) error {
 
 // Receives a netlink message.
 func (s *socket) receive() ([]syscall.NetlinkMessage, error) {
-	buffer := make([]byte, unix.Getpagesize())
+	buffer := make([]byte, max(unix.Getpagesize(), receiveBufferSize))
 	n, _, err := unix.Recvfrom(s.fd, buffer, 0)
 	if err != nil {
 		return nil, err
@@ -145,16 +157,7 @@ func (s *socket) receiveResponse(sent *message) ([]*message, error) {
 		// Process received messages.
 		for _, nlMsg := range nlMsgs {
 			// Convert to message object.
-			msg := message{
-				NlMsghdr: unix.NlMsghdr{
-					Len:   nlMsg.Header.Len,
-					Type:  nlMsg.Header.Type,
-					Flags: nlMsg.Header.Flags,
-					Seq:   nlMsg.Header.Seq,
-					Pid:   nlMsg.Header.Pid,
-				},
-				data: nlMsg.Data,
-			}
+			msg := newResponseMessage(nlMsg)
 
 			// Ignore if the message is not in response to the sent message.
 			if msg.Seq != sent.Seq || msg.Pid != sent.Pid {
@@ -179,25 +182,6 @@ func (s *socket) receiveResponse(sent *message) ([]*message, error) {
 			// Log response message.
 			log.Debugf("[netlink] Received %+v\n", msg)
 
-			// Parse body.
-			msg.payload = append(msg.payload, nil)
-
-			// Parse attributes.
-			// Ignore failures as not all messages have attributes.
-			nlAttrs, _ := syscall.ParseNetlinkRouteAttr(&nlMsg)
-
-			// Convert to attribute objects.
-			for _, nlAttr := range nlAttrs {
-				attr := attribute{
-					NlAttr: unix.NlAttr{
-						Len:  nlAttr.Attr.Len,
-						Type: nlAttr.Attr.Type,
-					},
-					value: nlAttr.Value,
-				}
-				msg.payload = append(msg.payload, &attr)
-			}
-
 			multi = ((msg.Flags & unix.NLM_F_MULTI) != 0)
 			done = (msg.Type == unix.NLMSG_DONE)
 
@@ -206,7 +190,7 @@ func (s *socket) receiveResponse(sent *message) ([]*message, error) {
 				break
 			}
 
-			messages = append(messages, &msg)
+			messages = append(messages, msg)
 		}
 
 		// Exit if response is a single message,
@@ -218,3 +202,50 @@ func (s *socket) receiveResponse(sent *message) ([]*message, error) {
 
 	return messages, nil
 }
+
+// Converts a received netlink message to a message object with its attributes.
+func newResponseMessage(nlMsg syscall.NetlinkMessage) *message {
+	msg := message{
+		NlMsghdr: unix.NlMsghdr{
+			Len:   nlMsg.Header.Len,
+			Type:  nlMsg.Header.Type,
+			Flags: nlMsg.Header.Flags,
+			Seq:   nlMsg.Header.Seq,
+			Pid:   nlMsg.Header.Pid,
+		},
+		data: nlMsg.Data,
+	}
+
+	// Parse body.
+	msg.payload = append(msg.payload, nil)
+
+	// Parse attributes.
+	// Ignore failures as not all messages have attributes.
+	nlAttrs, _ := parseRouteAttributes(nlMsg)
+
+	// Convert to attribute objects.
+	for _, nlAttr := range nlAttrs {
+		attr := attribute{
+			NlAttr: unix.NlAttr{
+				Len:  nlAttr.Attr.Len,
+				Type: nlAttr.Attr.Type,
+			},
+			value: nlAttr.Value,
+		}
+		msg.payload = append(msg.payload, &attr)
+	}
+
+	return &msg
+}
+
+// Parses the attributes of a routing message, including the neighbor and rule
+// messages which the syscall package does not parse.
+func parseRouteAttributes(nlMsg syscall.NetlinkMessage) ([]syscall.NetlinkRouteAttr, error) {
+	switch nlMsg.Header.Type {
+	case unix.RTM_NEWNEIGH, unix.RTM_DELNEIGH, unix.RTM_NEWRULE, unix.RTM_DELRULE:
+		// Their headers have the length of a route message header.
+		nlMsg.Header.Type = unix.RTM_NEWROUTE
+	}
+
+	return syscall.ParseNetlinkRouteAttr(&nlMsg)
+}
@@ -0,0 +1,126 @@
+package network
+
+import (
+	"math"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-container-networking/netlink"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// Prefix for the ifb interfaces shaping the traffic sent by endpoints.
+	ifbInterfacePrefix = commonInterfacePrefix + "b"
+	// bandwidthLatency is how long traffic can wait for tokens before it is dropped, as in the CNI bandwidth plugin.
+	bandwidthLatency = 25 * time.Millisecond
+	bitsPerByte      = 8
+)
+
+var (
+	bandwidthRootHandle    = netlink.MakeHandle(1, 0)
+	bandwidthIngressHandle = netlink.MakeHandle(0xffff, 0) //nolint:gomnd // ingress qdisc handle
+)
+
+// bandwidthIfbName returns the name of the ifb interface shaping the traffic sent by the endpoint of the host veth.
+func bandwidthIfbName(hostIfName string) string {
+	return ifbInterfacePrefix + strings.TrimPrefix(hostIfName, hostVEthInterfacePrefix)
+}
+
+// tbfQdisc returns the token bucket filter shaping the traffic sent on the interface to rate and burst, in bits.
+func tbfQdisc(linkName string, rate, burst uint64) *netlink.TbfQdisc {
+	rateInBytes := rate / bitsPerByte
+	burstInBytes := burst / bitsPerByte
+	limit := rateInBytes*uint64(bandwidthLatency.Milliseconds())/uint64(time.Second.Milliseconds()) + burstInBytes
+	return &netlink.TbfQdisc{
+		QdiscInfo: netlink.QdiscInfo{LinkName: linkName, Handle: bandwidthRootHandle, Parent: netlink.TC_H_ROOT},
+		Rate:      rateInBytes,
+		Burst:     uint32(min(burstInBytes, math.MaxUint32)),
+		Limit:     uint32(min(limit, math.MaxUint32)),
+	}
+}
+
+// addBandwidth shapes the traffic to and from the endpoint of the host veth. The traffic to the endpoint is shaped as
+// it is sent on the host veth, the traffic from it is redirected from the ingress of the host veth to an ifb interface
+// and shaped as it is sent there. Shaping from a previous add of the endpoint is replaced.
+func addBandwidth(nl netlink.NetlinkInterface, hostIfName string, bw *Bandwidth) error {
+	if bw == nil {
+		return nil
+	}
+	removeBandwidth(nl, hostIfName)
+
+	if bw.IngressRate > 0 {
+		logger.Info("Shaping traffic to the endpoint", zap.String("hostIfName", hostIfName), zap.Uint64("rate", bw.IngressRate))
+		if err := nl.AddQdisc(tbfQdisc(hostIfName, bw.IngressRate, bw.IngressBurst)); err != nil {
+			return errors.Wrapf(err, "failed to add tbf qdisc to %s", hostIfName)
+		}
+	}
+
+	if bw.EgressRate > 0 {
+		ifbName := bandwidthIfbName(hostIfName)
+		logger.Info("Shaping traffic from the endpoint", zap.String("hostIfName", hostIfName), zap.String("ifbName", ifbName), zap.Uint64("rate", bw.EgressRate))
+		ifb := &netlink.IFBLink{
+			LinkInfo: netlink.LinkInfo{
+				Type:  netlink.LINK_TYPE_IFB,
+				Name:  ifbName,
+				Flags: net.FlagUp,
+			},
+		}
+		if err := nl.AddLink(ifb); err != nil {
+			return errors.Wrapf(err, "failed to add ifb %s", ifbName)
+		}
+		if err := nl.AddQdisc(tbfQdisc(ifbName, bw.EgressRate, bw.EgressBurst)); err != nil {
+			return errors.Wrapf(err, "failed to add tbf qdisc to %s", ifbName)
+		}
+		ingress := &netlink.IngressQdisc{
+			QdiscInfo: netlink.QdiscInfo{LinkName: hostIfName, Handle: bandwidthIngressHandle, Parent: netlink.TC_H_INGRESS},
+		}
+		if err := nl.AddQdisc(ingress); err != nil {
+			return errors.Wrapf(err, "failed to add ingress qdisc to %s", hostIfName)
+		}
+		filter := &netlink.RedirectFilter{LinkName: hostIfName, Parent: bandwidthIngressHandle, Priority: 1, RedirectTo: ifbName}
+		if err := nl.AddRedirectFilter(filter); err != nil {
+			return errors.Wrapf(err, "failed to redirect %s to %s", hostIfName, ifbName)
+		}
+	}
+
+	return nil
+}
+
+// deleteBandwidth removes the shaping added by addBandwidth, logging failures. The ifb is deleted even when no
+// bandwidth is recorded for the endpoint, so that it doesn't outlive an endpoint whose state lost it; deleting a
+// missing ifb is a no-op, see deleteBandwidthIfb.
+func deleteBandwidth(nl netlink.NetlinkInterface, hostIfName string, bw *Bandwidth) {
+	if bw == nil {
+		deleteBandwidthIfb(nl, hostIfName)
+		return
+	}
+	removeBandwidth(nl, hostIfName)
+}
+
+// removeBandwidth removes any shaping of the host veth. The qdiscs go with the host veth when it is deleted, the ifb has
+// to be deleted.
+func removeBandwidth(nl netlink.NetlinkInterface, hostIfName string) {
+	qdiscs := []netlink.Qdisc{
+		&netlink.TbfQdisc{QdiscInfo: netlink.QdiscInfo{LinkName: hostIfName, Handle: bandwidthRootHandle, Parent: netlink.TC_H_ROOT}},
+		&netlink.IngressQdisc{QdiscInfo: netlink.QdiscInfo{LinkName: hostIfName, Handle: bandwidthIngressHandle, Parent: netlink.TC_H_INGRESS}},
+	}
+	for _, qdisc := range qdiscs {
+		if err := nl.DeleteQdisc(qdisc); err != nil {
+			logger.Info("No qdisc to delete", zap.String("hostIfName", hostIfName), zap.Uint32("parent", qdisc.Info().Parent), zap.Error(err))
+		}
+	}
+	deleteBandwidthIfb(nl, hostIfName)
+}
+
+// deleteBandwidthIfb deletes the ifb shaping the traffic sent by the endpoint of the host veth, if there is one.
+// Most endpoints aren't shaped, so a missing ifb is not logged.
+func deleteBandwidthIfb(nl netlink.NetlinkInterface, hostIfName string) {
+	ifbName := bandwidthIfbName(hostIfName)
+	if err := nl.DeleteLink(ifbName); err != nil && !errors.Is(err, unix.ENODEV) {
+		logger.Error("Failed to delete ifb", zap.String("ifbName", ifbName), zap.Error(err))
+	}
+}
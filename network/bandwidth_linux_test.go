@@ -0,0 +1,95 @@
+//go:build linux
+// +build linux
+
+package network
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-container-networking/netlink"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingNetlink keeps the qdiscs, filters and links programmed through it.
+type recordingNetlink struct {
+	*netlink.MockNetlink
+	links   []string
+	qdiscs  []netlink.Qdisc
+	filters []*netlink.RedirectFilter
+	deleted []string
+}
+
+func newRecordingNetlink() *recordingNetlink {
+	return &recordingNetlink{MockNetlink: netlink.NewMockNetlink(false, "")}
+}
+
+func (r *recordingNetlink) AddLink(link netlink.Link) error {
+	r.links = append(r.links, link.Info().Name)
+	return nil
+}
+
+func (r *recordingNetlink) DeleteLink(name string) error {
+	r.deleted = append(r.deleted, name)
+	return nil
+}
+
+func (r *recordingNetlink) AddQdisc(qdisc netlink.Qdisc) error {
+	r.qdiscs = append(r.qdiscs, qdisc)
+	return nil
+}
+
+func (r *recordingNetlink) DeleteQdisc(qdisc netlink.Qdisc) error {
+	r.deleted = append(r.deleted, qdisc.Info().LinkName)
+	return nil
+}
+
+func (r *recordingNetlink) AddRedirectFilter(filter *netlink.RedirectFilter) error {
+	r.filters = append(r.filters, filter)
+	return nil
+}
+
+func TestAddBandwidth(t *testing.T) {
+	nl := newRecordingNetlink()
+	require.NoError(t, addBandwidth(nl, "azv1234567890a", nil))
+	assert.Empty(t, nl.deleted)
+	assert.Empty(t, nl.qdiscs)
+
+	bw := &Bandwidth{IngressRate: 8000000, IngressBurst: 800000, EgressRate: 1000000, EgressBurst: 80000}
+	require.NoError(t, addBandwidth(nl, "azv1234567890a", bw))
+
+	// shaping from a previous add is removed first
+	assert.Equal(t, []string{"azv1234567890a", "azv1234567890a", "azb1234567890a"}, nl.deleted)
+	assert.Equal(t, []string{"azb1234567890a"}, nl.links)
+	assert.Equal(t, []netlink.Qdisc{
+		&netlink.TbfQdisc{
+			QdiscInfo: netlink.QdiscInfo{LinkName: "azv1234567890a", Handle: bandwidthRootHandle, Parent: netlink.TC_H_ROOT},
+			Rate:      1000000,
+			Burst:     100000,
+			Limit:     125000,
+		},
+		&netlink.TbfQdisc{
+			QdiscInfo: netlink.QdiscInfo{LinkName: "azb1234567890a", Handle: bandwidthRootHandle, Parent: netlink.TC_H_ROOT},
+			Rate:      125000,
+			Burst:     10000,
+			Limit:     13125,
+		},
+		&netlink.IngressQdisc{
+			QdiscInfo: netlink.QdiscInfo{LinkName: "azv1234567890a", Handle: bandwidthIngressHandle, Parent: netlink.TC_H_INGRESS},
+		},
+	}, nl.qdiscs)
+	assert.Equal(t, []*netlink.RedirectFilter{
+		{LinkName: "azv1234567890a", Parent: bandwidthIngressHandle, Priority: 1, RedirectTo: "azb1234567890a"},
+	}, nl.filters)
+}
+
+func TestDeleteBandwidth(t *testing.T) {
+	nl := newRecordingNetlink()
+	// the ifb is deleted even when no bandwidth is recorded
+	deleteBandwidth(nl, "azv1234567890a", nil)
+	assert.Equal(t, []string{"azb1234567890a"}, nl.deleted)
+	nl.deleted = nil
+
+	deleteBandwidth(nl, "azv1234567890a", &Bandwidth{EgressRate: 1000000, EgressBurst: 80000})
+	assert.Equal(t, []string{"azv1234567890a", "azv1234567890a", "azb1234567890a"}, nl.deleted)
+}
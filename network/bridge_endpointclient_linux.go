@@ -31,6 +31,8 @@ type LinuxBridgeEndpointClient struct {
 	plClient          platform.ExecClient
 	netioshim         netio.NetIOInterface
 	nuc               networkutils.NetworkUtils
+	iptablesClient    ipTablesClient
+	ebtablesClient    *ebtables.Client
 }
 
 func NewLinuxBridgeEndpointClient(
@@ -39,7 +41,9 @@ func NewLinuxBridgeEndpointClient(
 	containerVethName string,
 	mode string,
 	nl netlink.NetlinkInterface,
+	nioc netio.NetIOInterface,
 	plc platform.ExecClient,
+	iptc ipTablesClient,
 ) *LinuxBridgeEndpointClient {
 	client := &LinuxBridgeEndpointClient{
 		bridgeName:        extIf.BridgeName,
@@ -51,7 +55,9 @@ func NewLinuxBridgeEndpointClient(
 		mode:              mode,
 		netlink:           nl,
 		plClient:          plc,
-		netioshim:         &netio.NetIO{},
+		netioshim:         nioc,
+		iptablesClient:    iptc,
+		ebtablesClient:    ebtables.NewClient(plc),
 	}
 
 	client.hostIPAddresses = append(client.hostIPAddresses, extIf.IPAddresses...)
@@ -64,7 +70,15 @@ func (client *LinuxBridgeEndpointClient) AddEndpoints(epInfo *EndpointInfo) erro
 		return err
 	}
 
-	containerIf, err := net.InterfaceByName(client.containerVethName)
+	if err := setLinksMTU(client.netlink, epInfo.MTU, client.hostVethName, client.containerVethName); err != nil {
+		return err
+	}
+
+	if err := setLinksOffloads(client.netlink, epInfo.Offloads, client.hostVethName, client.containerVethName); err != nil {
+		return err
+	}
+
+	containerIf, err := client.netioshim.GetNetworkInterfaceByName(client.containerVethName)
 	if err != nil {
 		return err
 	}
@@ -85,14 +99,14 @@ func (client *LinuxBridgeEndpointClient) AddEndpointRules(epInfo *EndpointInfo)
 		if ipAddr.IP.To4() != nil {
 			// Add ARP reply rule.
 			logger.Info("Adding ARP reply rule for IP address", zap.String("address", ipAddr.String()))
-			if err = ebtables.SetArpReply(ipAddr.IP, client.getArpReplyAddress(client.containerMac), ebtables.Append); err != nil {
+			if err = client.ebtablesClient.SetArpReply(ipAddr.IP, client.getArpReplyAddress(client.containerMac), ebtables.Append); err != nil {
 				return err
 			}
 		}
 
 		// Add MAC address translation rule.
 		logger.Info("Adding MAC DNAT rule for IP address", zap.String("address", ipAddr.String()))
-		if err := ebtables.SetDnatForIPAddress(client.hostPrimaryIfName, ipAddr.IP, client.containerMac, ebtables.Append); err != nil {
+		if err := client.ebtablesClient.SetDnatForIPAddress(client.hostPrimaryIfName, ipAddr.IP, client.containerMac, ebtables.Append); err != nil {
 			return err
 		}
 
@@ -110,7 +124,7 @@ func (client *LinuxBridgeEndpointClient) AddEndpointRules(epInfo *EndpointInfo)
 		}
 	}
 
-	addRuleToRouteViaHost(epInfo)
+	addRuleToRouteViaHost(client.ebtablesClient, epInfo)
 
 	logger.Info("Setting hairpin for ", zap.String("hostveth", client.hostVethName))
 	if err := client.netlink.SetLinkHairpin(client.hostVethName, true); err != nil {
@@ -118,16 +132,28 @@ func (client *LinuxBridgeEndpointClient) AddEndpointRules(epInfo *EndpointInfo)
 		return err
 	}
 
-	return nil
+	if err := addPortMappings(client.iptablesClient, epInfo.ContainerID, epInfo.PortMappings, epInfo.IPAddresses, false); err != nil {
+		return err
+	}
+
+	if err := addEgressSNAT(client.iptablesClient, epInfo.ContainerID, epInfo.EgressSNAT, epInfo.IPAddresses); err != nil {
+		return err
+	}
+
+	return addBandwidth(client.netlink, client.hostVethName, epInfo.Bandwidth)
 }
 
 func (client *LinuxBridgeEndpointClient) DeleteEndpointRules(ep *endpoint) {
+	deletePortMappings(client.iptablesClient, ep.ContainerID, ep.PortMappings, ep.IPAddresses, false)
+	deleteEgressSNAT(client.iptablesClient, ep.ContainerID, ep.EgressSNAT, ep.IPAddresses)
+	deleteBandwidth(client.netlink, ep.HostIfName, ep.Bandwidth)
+
 	// Delete rules for IP addresses on the container interface.
 	for _, ipAddr := range ep.IPAddresses {
 		if ipAddr.IP.To4() != nil {
 			// Delete ARP reply rule.
 			logger.Info("Deleting ARP reply rule for IP address on", zap.String("address", ipAddr.String()), zap.String("id", ep.Id))
-			err := ebtables.SetArpReply(ipAddr.IP, client.getArpReplyAddress(ep.MacAddress), ebtables.Delete)
+			err := client.ebtablesClient.SetArpReply(ipAddr.IP, client.getArpReplyAddress(ep.MacAddress), ebtables.Delete)
 			if err != nil {
 				logger.Error("Failed to delete ARP reply rule for IP address", zap.String("address", ipAddr.String()), zap.Error(err))
 			}
@@ -135,7 +161,7 @@ func (client *LinuxBridgeEndpointClient) DeleteEndpointRules(ep *endpoint) {
 
 		// Delete MAC address translation rule.
 		logger.Info("Deleting MAC DNAT rule for IP address on", zap.String("address", ipAddr.String()), zap.String("id", ep.Id))
-		err := ebtables.SetDnatForIPAddress(client.hostPrimaryIfName, ipAddr.IP, ep.MacAddress, ebtables.Delete)
+		err := client.ebtablesClient.SetDnatForIPAddress(client.hostPrimaryIfName, ipAddr.IP, ep.MacAddress, ebtables.Delete)
 		if err != nil {
 			logger.Error("Failed to delete MAC DNAT rule for IP address", zap.String("address", ipAddr.String()), zap.Error(err))
 		}
@@ -221,7 +247,7 @@ func (client *LinuxBridgeEndpointClient) DeleteEndpoints(ep *endpoint) error {
 	return nil
 }
 
-func addRuleToRouteViaHost(epInfo *EndpointInfo) error {
+func addRuleToRouteViaHost(ebtablesClient *ebtables.Client, epInfo *EndpointInfo) error {
 	for _, ipAddr := range epInfo.IPsToRouteViaHost {
 		tableName := "broute"
 		chainName := "BROUTING"
@@ -229,7 +255,7 @@ func addRuleToRouteViaHost(epInfo *EndpointInfo) error {
 
 		// Check if EB rule exists
 		logger.Info("Checking if EB rule already exists in table chain", zap.String("rule", rule), zap.String("tableName", tableName), zap.String("chainName", chainName))
-		exists, err := ebtables.EbTableRuleExists(tableName, chainName, rule)
+		exists, err := ebtablesClient.EbTableRuleExists(tableName, chainName, rule)
 		if err != nil {
 			logger.Error("Failed to check if EB table rule exists", zap.Error(err))
 			return err
@@ -241,7 +267,7 @@ func addRuleToRouteViaHost(epInfo *EndpointInfo) error {
 		} else {
 			// Add EB rule to route via host.
 			logger.Info("Adding EB rule to route via host for IP", zap.Any("address", ipAddr))
-			if err := ebtables.SetBrouteAccept(ipAddr, ebtables.Append); err != nil {
+			if err := ebtablesClient.SetBrouteAccept(ipAddr, ebtables.Append); err != nil {
 				logger.Error("Failed to add EB rule to route via host with", zap.Error(err))
 				return err
 			}
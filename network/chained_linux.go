@@ -0,0 +1,190 @@
+package network
+
+import (
+	"net"
+	"os"
+	"time"
+
+	"github.com/Azure/azure-container-networking/netio"
+	"github.com/Azure/azure-container-networking/netlink"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// ChainedEndpointInfo is an endpoint whose interfaces and addresses are set up by the previous plugin of a chain, and
+// which only gets the Azure host routes, egress SNAT and routes of the IPs to route via the host.
+type ChainedEndpointInfo struct {
+	ContainerID string
+	NetNsPath   string
+	IfName      string
+	// HostIfName is the host side of the container interface, empty when the previous plugin reports none.
+	HostIfName  string
+	IPAddresses []net.IPNet
+	// Gateways are the gateways of the container interface, by IP family, reported by the previous plugin.
+	Gateways          []net.IP
+	IPsToRouteViaHost []string
+	EgressSNAT        *EgressSNAT
+	// HostRoutes are the routes through the host interface AddEndpoint added, the routes which existed already are
+	// left out so that DeleteEndpoint leaves them alone.
+	HostRoutes []RouteInfo
+	// Created is when AddEndpoint added the endpoint, so that GC leaves the endpoints of in-flight ADDs alone.
+	Created time.Time
+}
+
+// ChainedClient adds and deletes the Azure part of chained endpoints.
+type ChainedClient struct {
+	netlink        netlink.NetlinkInterface
+	netioshim      netio.NetIOInterface
+	nsClient       NamespaceClientInterface
+	iptablesClient ipTablesClient
+}
+
+func NewChainedClient(nl netlink.NetlinkInterface, nioc netio.NetIOInterface, nsc NamespaceClientInterface, iptc ipTablesClient) *ChainedClient {
+	return &ChainedClient{
+		netlink:        nl,
+		netioshim:      nioc,
+		nsClient:       nsc,
+		iptablesClient: iptc,
+	}
+}
+
+// AddEndpoint routes the endpoint addresses through the host interface, routes the IPs to route via the host through
+// the gateway of the container interface and source NATs the egress traffic of the endpoint.
+func (client *ChainedClient) AddEndpoint(info *ChainedEndpointInfo) error {
+	info.Created = time.Now()
+	if info.HostIfName != "" {
+		// ip route add <podip> dev <hostveth>
+		added, err := addNewRoutes(client.netlink, client.netioshim, info.HostIfName, chainedHostRoutes(info))
+		info.HostRoutes = added
+		if err != nil {
+			return errors.Wrapf(err, "failed to add routes through %s", info.HostIfName)
+		}
+	}
+
+	if routes := chainedContainerRoutes(info); len(routes) > 0 {
+		if err := client.inNetNs(info.NetNsPath, func() error {
+			return addRoutes(client.netlink, client.netioshim, info.IfName, routes)
+		}); err != nil {
+			return errors.Wrap(err, "failed to add routes of the ips to route via host")
+		}
+	}
+
+	return addEgressSNAT(client.iptablesClient, info.ContainerID, info.EgressSNAT, info.IPAddresses)
+}
+
+// DeleteEndpoint removes what AddEndpoint added, logging failures. Only the host routes AddEndpoint added are deleted,
+// the routes in the netns are left to be removed with it.
+func (client *ChainedClient) DeleteEndpoint(info *ChainedEndpointInfo) {
+	deleteEgressSNAT(client.iptablesClient, info.ContainerID, info.EgressSNAT, info.IPAddresses)
+
+	if info.HostIfName != "" && len(info.HostRoutes) > 0 {
+		// ip route del <podip> dev <hostveth>
+		if err := deleteRoutes(client.netlink, client.netioshim, info.HostIfName, info.HostRoutes); err != nil {
+			logger.Error("Failed to delete routes through", zap.String("hostIfName", info.HostIfName), zap.Error(err))
+		}
+	}
+}
+
+// VerifyEndpoint checks that what AddEndpoint added still exists.
+func (client *ChainedClient) VerifyEndpoint(info *ChainedEndpointInfo) ([]DatapathDrift, error) {
+	var drifts []DatapathDrift
+	if info.HostIfName != "" {
+		hostDrifts, err := verifyHostVeth(client.netlink, client.netioshim, &endpoint{HostIfName: info.HostIfName, IPAddresses: info.IPAddresses}, true)
+		if err != nil {
+			return nil, err
+		}
+		drifts = append(drifts, hostDrifts...)
+	}
+
+	if info.EgressSNAT != nil {
+		rules, err := egressSNATRules(info.ContainerID, info.EgressSNAT, info.IPAddresses)
+		if err != nil {
+			return nil, err
+		}
+		drifts = append(drifts, verifyRules(client.iptablesClient, rules)...)
+	}
+
+	routes := chainedContainerRoutes(info)
+	if len(routes) == 0 {
+		return drifts, nil
+	}
+	err := client.inNetNs(info.NetNsPath, func() error {
+		for i := range routes {
+			found, err := routeExists(client.netlink, &routes[i].Dst, 0)
+			if err != nil {
+				return err
+			}
+			if !found {
+				drifts = append(drifts, DatapathDrift{Resource: DriftResourceRoute, Name: routes[i].Dst.String(), Reason: "route not found in container"})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return append(drifts, DatapathDrift{Resource: DriftResourceNetNs, Name: info.NetNsPath, Reason: err.Error()}), nil
+		}
+		return nil, err
+	}
+	return drifts, nil
+}
+
+// chainedHostRoutes returns the host routes of the endpoint addresses.
+func chainedHostRoutes(info *ChainedEndpointInfo) []RouteInfo {
+	routes := make([]RouteInfo, 0, len(info.IPAddresses))
+	for _, ipAddr := range info.IPAddresses {
+		ipNet := net.IPNet{IP: ipAddr.IP, Mask: net.CIDRMask(ipv4FullMask, ipv4Bits)}
+		if ipAddr.IP.To4() == nil {
+			ipNet = net.IPNet{IP: ipAddr.IP, Mask: net.CIDRMask(ipv6FullMask, ipv6Bits)}
+		}
+		routes = append(routes, RouteInfo{Dst: ipNet, Scope: netlink.RT_SCOPE_LINK})
+	}
+	return routes
+}
+
+// chainedContainerRoutes returns the routes of the IPs to route via the host, through the gateway of their IP family,
+// or directly through the container interface without one.
+func chainedContainerRoutes(info *ChainedEndpointInfo) []RouteInfo {
+	routes := make([]RouteInfo, 0, len(info.IPsToRouteViaHost))
+	for _, ipStr := range info.IPsToRouteViaHost {
+		ip := net.ParseIP(ipStr)
+		if ip == nil {
+			logger.Error("Ignoring invalid ip to route via host", zap.String("ip", ipStr))
+			continue
+		}
+		route := RouteInfo{Dst: net.IPNet{IP: ip, Mask: net.CIDRMask(ipv4FullMask, ipv4Bits)}, Scope: netlink.RT_SCOPE_LINK}
+		if ip.To4() == nil {
+			route.Dst.Mask = net.CIDRMask(ipv6FullMask, ipv6Bits)
+		}
+		for _, gw := range info.Gateways {
+			if (gw.To4() == nil) == (ip.To4() == nil) {
+				route.Gw, route.Scope = gw, netlink.RT_SCOPE_UNIVERSE
+				break
+			}
+		}
+		routes = append(routes, route)
+	}
+	return routes
+}
+
+// inNetNs runs fn in the network namespace.
+func (client *ChainedClient) inNetNs(netNsPath string, fn func() error) error {
+	ns, err := client.nsClient.OpenNamespace(netNsPath)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open netns %s", netNsPath)
+	}
+	defer ns.Close()
+
+	logger.Info("Entering netns", zap.String("NetNsPath", netNsPath))
+	if err := ns.Enter(); err != nil {
+		return errors.Wrapf(err, "failed to enter netns %s", netNsPath)
+	}
+	defer func() {
+		logger.Info("Exiting netns", zap.String("NetNsPath", netNsPath))
+		if err := ns.Exit(); err != nil {
+			logger.Error("Failed to exit netns with", zap.Error(err))
+		}
+	}()
+
+	return fn()
+}
@@ -0,0 +1,148 @@
+//go:build linux
+// +build linux
+
+package network
+
+import (
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/Azure/azure-container-networking/cns"
+	"github.com/Azure/azure-container-networking/netio"
+	"github.com/Azure/azure-container-networking/netlink"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func chainedEndpointInfo() *ChainedEndpointInfo {
+	return &ChainedEndpointInfo{
+		ContainerID:       "abc",
+		NetNsPath:         "/var/run/netns/pod",
+		IfName:            "eth0",
+		HostIfName:        "lxc1234",
+		IPAddresses:       dualStackAddresses(),
+		Gateways:          []net.IP{net.ParseIP("10.240.0.1")},
+		IPsToRouteViaHost: []string{"169.254.169.254", "fd00::254"},
+		EgressSNAT:        &EgressSNAT{Mode: cns.EgressSNATNodeIP, Except: []string{"10.240.0.0/16"}},
+	}
+}
+
+func TestChainedClientAddDeleteEndpoint(t *testing.T) {
+	nl := netlink.NewMockNetlink(false, "")
+	var added, deleted []string
+	nl.SetAddRouteValidationFn(func(r *netlink.Route) error {
+		route := r.Dst.String()
+		if r.Gw != nil {
+			route += " via " + r.Gw.String()
+		}
+		added = append(added, route)
+		return nil
+	})
+	nl.SetDeleteRouteValidationFn(func(r *netlink.Route) error {
+		deleted = append(deleted, r.Dst.String())
+		return nil
+	})
+	iptc := &recordingIPTables{}
+	client := NewChainedClient(nl, netio.NewMockNetIO(false, 0), NewMockNamespaceClient(), iptc)
+
+	info := chainedEndpointInfo()
+	require.NoError(t, client.AddEndpoint(info))
+	// the host routes of the pod addresses, then the routes of the ips to route via host in the netns, through the
+	// gateway of their family if there is one
+	assert.Equal(t, []string{"10.240.0.5/32", "fd00::5/128", "169.254.169.254/32 via 10.240.0.1", "fd00::254/128"}, added)
+	assert.Equal(t, []string{"4/nat/AZURECNIEGRESS", "6/nat/AZURECNIEGRESS"}, iptc.chains)
+	assert.Len(t, iptc.rules, 5)
+
+	client.DeleteEndpoint(info)
+	assert.Equal(t, []string{"10.240.0.5/32", "fd00::5/128"}, deleted)
+	// only the jumps to the shared chain are left
+	assert.Len(t, iptc.rules, 2)
+}
+
+func TestChainedClientDeleteEndpointKeepsExistingRoutes(t *testing.T) {
+	nl := netlink.NewMockNetlink(false, "")
+	var deleted []string
+	nl.SetAddRouteValidationFn(func(r *netlink.Route) error {
+		if r.Dst.String() == "10.240.0.5/32" {
+			return errors.New("file exists")
+		}
+		return nil
+	})
+	nl.SetDeleteRouteValidationFn(func(r *netlink.Route) error {
+		deleted = append(deleted, r.Dst.String())
+		return nil
+	})
+	client := NewChainedClient(nl, netio.NewMockNetIO(false, 0), NewMockNamespaceClient(), &recordingIPTables{})
+
+	info := chainedEndpointInfo()
+	info.IPsToRouteViaHost = nil
+	require.NoError(t, client.AddEndpoint(info))
+	require.Len(t, info.HostRoutes, 1)
+	assert.False(t, info.Created.IsZero())
+
+	// the route which existed before the endpoint was added is left alone
+	client.DeleteEndpoint(info)
+	assert.Equal(t, []string{"fd00::5/128"}, deleted)
+}
+
+func TestChainedClientAddEndpointWithoutHostInterface(t *testing.T) {
+	nl := netlink.NewMockNetlink(false, "")
+	var added []string
+	nl.SetAddRouteValidationFn(func(r *netlink.Route) error {
+		added = append(added, r.Dst.String())
+		return nil
+	})
+	client := NewChainedClient(nl, netio.NewMockNetIO(false, 0), NewMockNamespaceClient(), &recordingIPTables{})
+
+	info := chainedEndpointInfo()
+	info.HostIfName = ""
+	info.IPsToRouteViaHost = nil
+	info.EgressSNAT = nil
+	require.NoError(t, client.AddEndpoint(info))
+	assert.Empty(t, added)
+}
+
+func TestChainedClientAddEndpointFailures(t *testing.T) {
+	client := NewChainedClient(netlink.NewMockNetlink(true, "route"), netio.NewMockNetIO(false, 0), NewMockNamespaceClient(), &recordingIPTables{})
+	require.Error(t, client.AddEndpoint(chainedEndpointInfo()))
+
+	info := chainedEndpointInfo()
+	info.HostIfName = ""
+	info.NetNsPath = failToEnterNamespaceName
+	client = NewChainedClient(netlink.NewMockNetlink(false, ""), netio.NewMockNetIO(false, 0), NewMockNamespaceClient(), &recordingIPTables{})
+	require.Error(t, client.AddEndpoint(info))
+}
+
+func TestChainedClientVerifyEndpoint(t *testing.T) {
+	nl := netlink.NewMockNetlink(false, "")
+	var routes []string
+	nl.SetGetRouteFn(func(filter *netlink.Route) ([]*netlink.Route, error) {
+		for _, route := range routes {
+			if route == filter.Dst.String() {
+				return []*netlink.Route{filter}, nil
+			}
+		}
+		return nil, nil
+	})
+	iptc := &recordingIPTables{}
+	client := NewChainedClient(nl, netio.NewMockNetIO(false, 0), NewMockNamespaceClient(), iptc)
+
+	info := chainedEndpointInfo()
+	require.NoError(t, client.AddEndpoint(info))
+	routes = []string{"10.240.0.5/32", "fd00::5/128", "169.254.169.254/32", "fd00::254/128"}
+	drifts, err := client.VerifyEndpoint(info)
+	require.NoError(t, err)
+	assert.Empty(t, drifts)
+
+	// the host route of the IPv6 address and the egress snat rules are gone
+	routes = []string{"10.240.0.5/32", "169.254.169.254/32", "fd00::254/128"}
+	client.DeleteEndpoint(info)
+	drifts, err = client.VerifyEndpoint(info)
+	require.NoError(t, err)
+	require.Len(t, drifts, 4)
+	assert.Equal(t, DatapathDrift{Resource: DriftResourceHostRoute, Name: "fd00::5/128", Reason: "route via lxc1234 not found"}, drifts[0])
+	for _, drift := range drifts[1:] {
+		assert.Equal(t, DriftResourceHostRule, drift.Resource)
+	}
+}
@@ -0,0 +1,135 @@
+package network
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+
+	"github.com/Azure/azure-container-networking/cns"
+	"github.com/Azure/azure-container-networking/iptables"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// egressJumpMatch sends traffic to the egress chain, except DNATed connections, such as to host ports and services,
+// which are left to the rules for them.
+const egressJumpMatch = "-m conntrack ! --ctstate DNAT"
+
+// egressSNATRules returns the nat rules source NATing the traffic from the endpoint addresses to destinations outside
+// of the except CIDRs of the same IP family. Traffic to the except CIDRs returns from the egress chain.
+func egressSNATRules(containerID string, egressSNAT *EgressSNAT, addresses []net.IPNet) ([]iptablesRule, error) {
+	var snatIP netip.Addr
+	var target string
+	switch egressSNAT.Mode {
+	case cns.EgressSNATNodeIP:
+		target = iptables.Masquerade
+	case cns.EgressSNATNone:
+		target = iptables.Accept
+	case cns.EgressSNATSecondaryIP:
+		var err error
+		if snatIP, err = netip.ParseAddr(egressSNAT.IPAddress); err != nil {
+			return nil, errors.Wrap(err, "invalid secondary IP of egress snat")
+		}
+		snatIP = snatIP.Unmap()
+		target = iptables.Snat + " --to-source " + snatIP.String()
+	default:
+		return nil, errors.Errorf("unknown egress snat mode %q", egressSNAT.Mode)
+	}
+
+	except := make([]netip.Prefix, 0, len(egressSNAT.Except))
+	for _, cidr := range egressSNAT.Except {
+		prefix, err := netip.ParsePrefix(cidr)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid except CIDR of egress snat")
+		}
+		except = append(except, prefix.Masked())
+	}
+
+	var rules []iptablesRule
+	comment := fmt.Sprintf("-m comment --comment azure-cni-egress:%s", containerID)
+	for _, ipAddr := range addresses {
+		podIP, ok := netip.AddrFromSlice(ipAddr.IP)
+		if !ok {
+			continue
+		}
+		podIP = podIP.Unmap()
+		// the secondary IP only SNATs the pod addresses of its family, the others are left as configured.
+		if snatIP.IsValid() && snatIP.Is4() != podIP.Is4() {
+			continue
+		}
+		version := iptables.V4
+		if podIP.Is6() {
+			version = iptables.V6
+		}
+
+		for _, prefix := range except {
+			if prefix.Addr().Is4() != podIP.Is4() {
+				continue
+			}
+			rules = append(rules, iptablesRule{
+				version: version,
+				table:   iptables.Nat,
+				chain:   iptables.CNIEgressChain,
+				match:   fmt.Sprintf("-s %s -d %s %s", podIP, prefix, comment),
+				target:  iptables.Return,
+			})
+		}
+		rules = append(rules, iptablesRule{
+			version: version,
+			table:   iptables.Nat,
+			chain:   iptables.CNIEgressChain,
+			match:   fmt.Sprintf("-s %s %s", podIP, comment),
+			target:  target,
+		})
+	}
+	return rules, nil
+}
+
+// addEgressSNAT programs the rules source NATing the egress traffic of the endpoint addresses.
+func addEgressSNAT(iptc ipTablesClient, containerID string, egressSNAT *EgressSNAT, addresses []net.IPNet) error {
+	if egressSNAT == nil {
+		return nil
+	}
+	rules, err := egressSNATRules(containerID, egressSNAT, addresses)
+	if err != nil {
+		return err
+	}
+
+	chains := map[string]bool{}
+	for _, rule := range rules {
+		if !chains[rule.version] {
+			if err := iptc.CreateChain(rule.version, iptables.Nat, iptables.CNIEgressChain); err != nil {
+				return errors.Wrap(err, "failed to create egress chain")
+			}
+			// the egress chain goes first, ahead of the rules masquerading all traffic leaving the vnet.
+			if err := iptc.InsertIptableRule(rule.version, iptables.Nat, iptables.Postrouting, egressJumpMatch, iptables.CNIEgressChain); err != nil {
+				return errors.Wrap(err, "failed to jump to egress chain")
+			}
+			chains[rule.version] = true
+		}
+
+		logger.Info("Adding egress snat rule", zap.String("match", rule.match), zap.String("target", rule.target))
+		if err := iptc.AppendIptableRule(rule.version, rule.table, rule.chain, rule.match, rule.target); err != nil {
+			return errors.Wrapf(err, "failed to add egress snat rule %s", rule.match)
+		}
+	}
+	return nil
+}
+
+// deleteEgressSNAT removes the rules added by addEgressSNAT, logging failures.
+func deleteEgressSNAT(iptc ipTablesClient, containerID string, egressSNAT *EgressSNAT, addresses []net.IPNet) {
+	if egressSNAT == nil {
+		return
+	}
+	rules, err := egressSNATRules(containerID, egressSNAT, addresses)
+	if err != nil {
+		logger.Error("Failed to build egress snat rules to delete", zap.Error(err))
+		return
+	}
+	for _, rule := range rules {
+		logger.Info("Deleting egress snat rule", zap.String("match", rule.match), zap.String("target", rule.target))
+		if err := iptc.DeleteIptableRule(rule.version, rule.table, rule.chain, rule.match, rule.target); err != nil {
+			logger.Error("Failed to delete egress snat rule", zap.String("match", rule.match), zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,68 @@
+//go:build linux
+// +build linux
+
+package network
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-container-networking/cns"
+	"github.com/Azure/azure-container-networking/iptables"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEgressSNATRules(t *testing.T) {
+	const comment = " -m comment --comment azure-cni-egress:abc"
+	except := []string{"10.240.0.0/16", "10.0.0.1/8", "fd00::/48"}
+
+	rules, err := egressSNATRules("abc", &EgressSNAT{Mode: cns.EgressSNATNodeIP, Except: except}, dualStackAddresses())
+	require.NoError(t, err)
+	assert.Equal(t, []iptablesRule{
+		{iptables.V4, iptables.Nat, iptables.CNIEgressChain, "-s 10.240.0.5 -d 10.240.0.0/16" + comment, "RETURN"},
+		{iptables.V4, iptables.Nat, iptables.CNIEgressChain, "-s 10.240.0.5 -d 10.0.0.0/8" + comment, "RETURN"},
+		{iptables.V4, iptables.Nat, iptables.CNIEgressChain, "-s 10.240.0.5" + comment, "MASQUERADE"},
+		{iptables.V6, iptables.Nat, iptables.CNIEgressChain, "-s fd00::5 -d fd00::/48" + comment, "RETURN"},
+		{iptables.V6, iptables.Nat, iptables.CNIEgressChain, "-s fd00::5" + comment, "MASQUERADE"},
+	}, rules)
+
+	rules, err = egressSNATRules("abc", &EgressSNAT{Mode: cns.EgressSNATNone}, dualStackAddresses())
+	require.NoError(t, err)
+	require.Len(t, rules, 2)
+	assert.Equal(t, "ACCEPT", rules[0].target)
+
+	// the secondary IP only SNATs the address of its family
+	rules, err = egressSNATRules("abc", &EgressSNAT{Mode: cns.EgressSNATSecondaryIP, IPAddress: "10.240.0.10"}, dualStackAddresses())
+	require.NoError(t, err)
+	assert.Equal(t, []iptablesRule{
+		{iptables.V4, iptables.Nat, iptables.CNIEgressChain, "-s 10.240.0.5" + comment, "SNAT --to-source 10.240.0.10"},
+	}, rules)
+
+	_, err = egressSNATRules("abc", &EgressSNAT{Mode: cns.EgressSNATSecondaryIP, IPAddress: "node"}, dualStackAddresses())
+	require.Error(t, err)
+	_, err = egressSNATRules("abc", &EgressSNAT{Mode: cns.EgressSNATNone, Except: []string{"vnet"}}, dualStackAddresses())
+	require.Error(t, err)
+	_, err = egressSNATRules("abc", &EgressSNAT{Mode: "masquerade"}, dualStackAddresses())
+	require.Error(t, err)
+}
+
+func TestAddDeleteEgressSNAT(t *testing.T) {
+	iptc := &recordingIPTables{}
+	egressSNAT := &EgressSNAT{Mode: cns.EgressSNATNone, Except: []string{"10.240.0.0/16"}}
+
+	require.NoError(t, addEgressSNAT(iptc, "abc", nil, dualStackAddresses()))
+	assert.Empty(t, iptc.chains)
+
+	require.NoError(t, addEgressSNAT(iptc, "abc", egressSNAT, dualStackAddresses()))
+	assert.Equal(t, []string{"4/nat/AZURECNIEGRESS", "6/nat/AZURECNIEGRESS"}, iptc.chains)
+	// a jump from POSTROUTING per IP family, a return rule for the IPv4 vnet and a rule per address
+	assert.Len(t, iptc.rules, 5)
+
+	deleteEgressSNAT(iptc, "abc", egressSNAT, dualStackAddresses())
+	// only the jumps to the shared chain are left
+	require.Len(t, iptc.rules, 2)
+	for _, rule := range iptc.rules {
+		assert.Equal(t, iptables.Postrouting, rule.chain)
+		assert.Equal(t, iptables.CNIEgressChain, rule.target)
+	}
+}
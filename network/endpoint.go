@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"net"
 	"strings"
+	"time"
 
 	"github.com/Azure/azure-container-networking/cni/log"
 	"github.com/Azure/azure-container-networking/cns"
@@ -59,6 +60,25 @@ type endpoint struct {
 	SecondaryInterfaces map[string]*InterfaceInfo
 	// Store nic type since we no longer populate SecondaryInterfaces
 	NICType cns.NICType
+	// PortMappings are the host ports forwarded to the endpoint, kept so they can be removed on delete.
+	PortMappings []PortMapping `json:",omitempty"`
+	// PortForwardAddress is the address the host ports are forwarded to in place of the endpoint addresses, such as
+	// the SNAT endpoint of transparent-vlan endpoints, kept so the rules can be removed from the CNS state on delete.
+	PortForwardAddress string `json:",omitempty"`
+	// PortForwardMasqueradeAll is set when all of the traffic forwarded to the host ports is masqueraded.
+	PortForwardMasqueradeAll bool `json:",omitempty"`
+	// Bandwidth is the shaping of the endpoint traffic, kept so it can be removed on delete.
+	Bandwidth *Bandwidth `json:",omitempty"`
+	// MTU is the effective MTU of the container interface.
+	MTU int `json:",omitempty"`
+	// EgressSNAT is the source NAT of the endpoint egress traffic, kept so it can be removed on delete.
+	EgressSNAT *EgressSNAT `json:",omitempty"`
+	// NetworkMode is the mode of the network of the endpoint, recorded in the CNS endpoint state in stateless mode.
+	NetworkMode string `json:",omitempty"`
+	// NetworkID is the network of the endpoint, recorded in the CNS endpoint state in stateless mode.
+	NetworkID string `json:",omitempty"`
+	// Created is when the endpoint was created, so that GC leaves the endpoints of in-flight ADDs alone.
+	Created time.Time
 }
 
 // EndpointInfo contains read-only information about an endpoint.
@@ -112,6 +132,49 @@ type EndpointInfo struct {
 	IsIPv6Enabled                 bool
 	HostSubnetPrefix              string // can be used later to add an external interface
 	PnPID                         string
+	PortMappings                  []PortMapping // used in linux, windows maps ports with endpoint policies
+	PortForwardAddress            string        // used in linux, the address the host ports are forwarded to if not IPAddresses
+	PortForwardMasqueradeAll      bool          // used in linux, masquerade all of the traffic forwarded to the host ports
+	Bandwidth                     *Bandwidth    // used in linux
+	Tuning                        *Tuning       // used in linux
+	MTU                           int           // used in linux, 0 leaves the default MTU; set to the effective MTU once the endpoint is created
+	Offloads                      *Offloads     // used in linux
+	EgressSNAT                    *EgressSNAT   // used in linux, nil leaves the egress traffic as configured for the network
+	Created                       time.Time     // when the endpoint was created, zero if it was not recorded
+}
+
+// PortMapping forwards a host port to a port of the endpoint.
+type PortMapping struct {
+	HostPort      int
+	ContainerPort int
+	Protocol      string // tcp, udp or sctp
+	HostIP        string `json:",omitempty"` // only forward the host port on this address
+}
+
+// Tuning is applied in the network namespace of an endpoint once its interfaces and routes are configured.
+type Tuning struct {
+	// Sysctls are netns-scoped sysctls by key, such as net.ipv4.tcp_keepalive_time.
+	Sysctls    map[string]string
+	Promisc    bool
+	TxQueueLen int // 0 leaves the default length
+}
+
+// Offloads turns the offloads of the veth pair of an endpoint on or off, a nil offload is left as it is.
+type Offloads struct {
+	TSO *bool
+	GRO *bool
+}
+
+// EgressSNAT chooses how the traffic an endpoint sends out of the vnet is source NATed on the host.
+type EgressSNAT = cns.EgressSNAT
+
+// Bandwidth limits the traffic to and from an endpoint. Rates are in bits per second and bursts in bits,
+// a zero rate leaves that direction unshaped.
+type Bandwidth struct {
+	IngressRate  uint64 `json:",omitempty"`
+	IngressBurst uint64 `json:",omitempty"`
+	EgressRate   uint64 `json:",omitempty"`
+	EgressBurst  uint64 `json:",omitempty"`
 }
 
 // RouteInfo contains information about an IP route.
@@ -145,6 +208,28 @@ type IPConfig struct {
 	Gateway net.IP
 }
 
+// Resources reported in DatapathDrift.
+const (
+	DriftResourceHostInterface      = "hostInterface"
+	DriftResourceHostRoute          = "hostRoute"
+	DriftResourceHostRule           = "hostRule"
+	DriftResourceNetNs              = "netns"
+	DriftResourceContainerInterface = "interface"
+	DriftResourceAddress            = "address"
+	DriftResourceRoute              = "route"
+)
+
+// DatapathDrift describes a piece of datapath state recorded for an endpoint that no longer exists on the host.
+type DatapathDrift struct {
+	Resource string `json:"resource"`
+	Name     string `json:"name"`
+	Reason   string `json:"reason"`
+}
+
+func (d DatapathDrift) String() string {
+	return fmt.Sprintf("%s %s: %s", d.Resource, d.Name, d.Reason)
+}
+
 type apipaClient interface {
 	DeleteHostNCApipaEndpoint(ctx context.Context, networkContainerID string) error
 	CreateHostNCApipaEndpoint(ctx context.Context, networkContainerID string) (string, error)
@@ -187,6 +272,9 @@ func (nw *network) newEndpoint(
 		return nil, err
 	}
 
+	ep.NetworkMode = nw.Mode
+	ep.NetworkID = nw.Id
+	ep.Created = time.Now()
 	nw.Endpoints[ep.Id] = ep
 	logger.Info("Created endpoint. Num of endpoints", zap.Any("ep", ep), zap.Int("numEndpoints", len(nw.Endpoints)))
 
@@ -299,6 +387,13 @@ func (ep *endpoint) getInfo() *EndpointInfo {
 		HNSEndpointID:            ep.HnsId,
 		HostIfName:               ep.HostIfName,
 		NICType:                  ep.NICType,
+		PortMappings:             ep.PortMappings,
+		PortForwardAddress:       ep.PortForwardAddress,
+		PortForwardMasqueradeAll: ep.PortForwardMasqueradeAll,
+		Bandwidth:                ep.Bandwidth,
+		MTU:                      ep.MTU,
+		EgressSNAT:               ep.EgressSNAT,
+		Created:                  ep.Created,
 	}
 
 	info.Routes = append(info.Routes, ep.Routes...)
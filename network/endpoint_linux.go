@@ -8,6 +8,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"net"
+	"os"
 	"strings"
 
 	"github.com/Azure/azure-container-networking/cns"
@@ -16,6 +17,7 @@ import (
 	"github.com/Azure/azure-container-networking/network/networkutils"
 	"github.com/Azure/azure-container-networking/ovsctl"
 	"github.com/Azure/azure-container-networking/platform"
+	"github.com/pkg/errors"
 	"go.uber.org/zap"
 )
 
@@ -27,6 +29,15 @@ const (
 	hostVEthInterfacePrefix = commonInterfacePrefix + "v"
 )
 
+// Endpoint clients, as chosen by endpointClientKind.
+const (
+	endpointClientBridge          = "LinuxBridgeEndpointClient"
+	endpointClientOVS             = "OVSEndpointClient"
+	endpointClientSecondary       = "SecondaryEndpointClient"
+	endpointClientTransparent     = "TransparentEndpointClient"
+	endpointClientTransparentVlan = "TransparentVlanEndpointClient"
+)
+
 type AzureHNSEndpointClient interface{}
 
 func generateVethName(key string) string {
@@ -35,6 +46,34 @@ func generateVethName(key string) string {
 	return hex.EncodeToString(h.Sum(nil))[:11]
 }
 
+// endpointVethNames returns the names of the host and container veths of the endpoint. The container veth is renamed
+// to the interface name of the endpoint once it is moved into the container network namespace.
+func endpointVethNames(epInfo *EndpointInfo) (hostIfName, contIfName string) {
+	if key, ok := epInfo.Data[OptVethName].(string); ok {
+		logger.Info("Generate veth name based on the key provided", zap.String("key", key))
+		vethname := generateVethName(key)
+		return fmt.Sprintf("%s%s", hostVEthInterfacePrefix, vethname), fmt.Sprintf("%s%s2", hostVEthInterfacePrefix, vethname)
+	}
+	logger.Info("Generate veth name based on endpoint id")
+	return fmt.Sprintf("%s%s", hostVEthInterfacePrefix, epInfo.EndpointID[:7]), fmt.Sprintf("%s%s-2", hostVEthInterfacePrefix, epInfo.EndpointID[:7])
+}
+
+// endpointClientKind returns the endpoint client creating the endpoints of a network in the mode.
+func endpointClientKind(mode string, vlanID int, nicType cns.NICType) string {
+	switch {
+	case vlanID != 0 && mode == opModeTransparentVlan:
+		return endpointClientTransparentVlan
+	case vlanID != 0:
+		return endpointClientOVS
+	case mode != opModeTransparent:
+		return endpointClientBridge
+	case nicType == cns.DelegatedVMNIC:
+		return endpointClientSecondary
+	default:
+		return endpointClientTransparent
+	}
+}
+
 func ConstructEndpointID(containerID string, _ string, ifName string) (string, string) {
 	if len(containerID) > 8 {
 		containerID = containerID[:8]
@@ -84,20 +123,9 @@ func (nw *network) newEndpointImpl(
 		}
 	}
 
-	if _, ok := epInfo.Data[OptVethName]; ok {
-		key := epInfo.Data[OptVethName].(string)
-		logger.Info("Generate veth name based on the key provided", zap.String("key", key))
-		vethname := generateVethName(key)
-		hostIfName = fmt.Sprintf("%s%s", hostVEthInterfacePrefix, vethname)
-		// we don't save the contIfName here or below because it will be renamed to ep.IfName anyway when we call SetupContainerInterfaces in the clients
-		// however, contIfName is still passed into our clients
-		contIfName = fmt.Sprintf("%s%s2", hostVEthInterfacePrefix, vethname)
-	} else {
-		// Create a veth pair.
-		logger.Info("Generate veth name based on endpoint id")
-		hostIfName = fmt.Sprintf("%s%s", hostVEthInterfacePrefix, epInfo.EndpointID[:7])
-		contIfName = fmt.Sprintf("%s%s-2", hostVEthInterfacePrefix, epInfo.EndpointID[:7])
-	}
+	// we don't save the contIfName because it will be renamed to ep.IfName anyway when we call SetupContainerInterfaces in the clients
+	// however, contIfName is still passed into our clients
+	hostIfName, contIfName = endpointVethNames(epInfo)
 
 	nicName := epInfo.IfName
 	// infra nic nicname will look like eth0, and delegated/secondary nics will be moved into the container namespace
@@ -127,51 +155,23 @@ func (nw *network) newEndpointImpl(
 		Routes:                   epInfo.Routes,
 		SecondaryInterfaces:      make(map[string]*InterfaceInfo),
 		NICType:                  epInfo.NICType,
+		PortMappings:             epInfo.PortMappings,
+		Bandwidth:                epInfo.Bandwidth,
+		EgressSNAT:               epInfo.EgressSNAT,
 	}
 	if nw.extIf != nil {
 		ep.Gateways = []net.IP{nw.extIf.IPv4Gateway}
 	}
+	if nw.Mode == opModeTransparentVlan && vlanid != 0 && len(ep.PortMappings) > 0 {
+		// host ports are forwarded to the SNAT endpoint, see TransparentVlanEndpointClient.portMappingAddresses
+		ep.PortForwardAddress, ep.PortForwardMasqueradeAll = localIP, true
+	}
 
-	// testEpClient is non-nil only when the endpoint is created for the unit test
+	// testEpClient is non-nil only when the endpoint is created for the unit test, or planned by PlanEndpoint
 	// resetting epClient to testEpClient in loop to use the test endpoint client if specified
 	epClient := testEpClient
 	if epClient == nil {
-		//nolint:gocritic
-		if vlanid != 0 {
-			if nw.Mode == opModeTransparentVlan {
-				logger.Info("Transparent vlan client")
-				if _, ok := epInfo.Data[SnatBridgeIPKey]; ok {
-					nw.SnatBridgeIP = epInfo.Data[SnatBridgeIPKey].(string)
-				}
-				epClient = NewTransparentVlanEndpointClient(nw, epInfo, hostIfName, contIfName, vlanid, localIP, nl, plc, nsc, iptc)
-			} else {
-				logger.Info("OVS client")
-				if _, ok := epInfo.Data[SnatBridgeIPKey]; ok {
-					nw.SnatBridgeIP = epInfo.Data[SnatBridgeIPKey].(string)
-				}
-
-				epClient = NewOVSEndpointClient(
-					nw,
-					epInfo,
-					hostIfName,
-					contIfName,
-					vlanid,
-					localIP,
-					nl,
-					ovsctl.NewOvsctl(),
-					plc,
-					iptc)
-			}
-		} else if nw.Mode != opModeTransparent {
-			logger.Info("Bridge client")
-			epClient = NewLinuxBridgeEndpointClient(nw.extIf, hostIfName, contIfName, nw.Mode, nl, plc)
-		} else if epInfo.NICType == cns.DelegatedVMNIC {
-			logger.Info("Secondary client")
-			epClient = NewSecondaryEndpointClient(nl, netioCli, plc, nsc, ep)
-		} else {
-			logger.Info("Transparent client")
-			epClient = NewTransparentEndpointClient(nw.extIf, hostIfName, contIfName, nw.Mode, nl, netioCli, plc)
-		}
+		epClient = nw.newEndpointClient(epInfo, ep, contIfName, nl, plc, netioCli, nsc, iptc)
 	}
 
 	//nolint:gocritic
@@ -202,6 +202,8 @@ func (nw *network) newEndpointImpl(
 				return epErr
 			}
 			ep.MacAddress = containerIf.HardwareAddr
+			ep.MTU = containerIf.MTU
+			epInfo.MTU = containerIf.MTU
 		}
 
 		// Setup rules for IP addresses on the container interface.
@@ -254,7 +256,15 @@ func (nw *network) newEndpointImpl(
 			}
 		}
 
-		return epClient.ConfigureContainerInterfacesAndRoutes(epInfo)
+		if epErr := epClient.ConfigureContainerInterfacesAndRoutes(epInfo); epErr != nil {
+			return epErr
+		}
+
+		// Tune the container network namespace, which the thread is still in.
+		if epInfo.Tuning != nil && epInfo.NetNsPath == "" {
+			return errTuningNoNetNs
+		}
+		return applyTuning(nl, plc, epInfo.IfName, epInfo.Tuning)
 	}()
 	if err != nil {
 		return nil, err
@@ -263,6 +273,53 @@ func (nw *network) newEndpointImpl(
 	return ep, nil
 }
 
+// newEndpointClient returns the endpoint client creating the endpoint in the network.
+func (nw *network) newEndpointClient(
+	epInfo *EndpointInfo,
+	ep *endpoint,
+	contIfName string,
+	nl netlink.NetlinkInterface,
+	plc platform.ExecClient,
+	netioCli netio.NetIOInterface,
+	nsc NamespaceClientInterface,
+	iptc ipTablesClient,
+) EndpointClient {
+	switch endpointClientKind(nw.Mode, ep.VlanID, epInfo.NICType) {
+	case endpointClientTransparentVlan:
+		logger.Info("Transparent vlan client")
+		if _, ok := epInfo.Data[SnatBridgeIPKey]; ok {
+			nw.SnatBridgeIP = epInfo.Data[SnatBridgeIPKey].(string)
+		}
+		return NewTransparentVlanEndpointClient(nw, epInfo, ep.HostIfName, contIfName, ep.VlanID, ep.LocalIP, nl, netioCli, plc, nsc, iptc)
+	case endpointClientOVS:
+		logger.Info("OVS client")
+		if _, ok := epInfo.Data[SnatBridgeIPKey]; ok {
+			nw.SnatBridgeIP = epInfo.Data[SnatBridgeIPKey].(string)
+		}
+
+		return NewOVSEndpointClient(
+			nw,
+			epInfo,
+			ep.HostIfName,
+			contIfName,
+			ep.VlanID,
+			ep.LocalIP,
+			nl,
+			ovsctl.NewOvsctl(),
+			plc,
+			iptc)
+	case endpointClientBridge:
+		logger.Info("Bridge client")
+		return NewLinuxBridgeEndpointClient(nw.extIf, ep.HostIfName, contIfName, nw.Mode, nl, netioCli, plc, iptc)
+	case endpointClientSecondary:
+		logger.Info("Secondary client")
+		return NewSecondaryEndpointClient(nl, netioCli, plc, nsc, ep)
+	default:
+		logger.Info("Transparent client")
+		return NewTransparentEndpointClient(nw.extIf, ep.HostIfName, contIfName, nw.Mode, nl, netioCli, plc, iptc)
+	}
+}
+
 // deleteEndpointImpl deletes an existing endpoint from the network.
 func (nw *network) deleteEndpointImpl(nl netlink.NetlinkInterface, plc platform.ExecClient, epClient EndpointClient, nioc netio.NetIOInterface, nsc NamespaceClientInterface,
 	iptc ipTablesClient, ep *endpoint,
@@ -277,13 +334,13 @@ func (nw *network) deleteEndpointImpl(nl netlink.NetlinkInterface, plc platform.
 		if ep.VlanID != 0 {
 			epInfo := ep.getInfo()
 			if nw.Mode == opModeTransparentVlan {
-				epClient = NewTransparentVlanEndpointClient(nw, epInfo, ep.HostIfName, "", ep.VlanID, ep.LocalIP, nl, plc, nsc, iptc)
+				epClient = NewTransparentVlanEndpointClient(nw, epInfo, ep.HostIfName, "", ep.VlanID, ep.LocalIP, nl, nioc, plc, nsc, iptc)
 
 			} else {
 				epClient = NewOVSEndpointClient(nw, epInfo, ep.HostIfName, "", ep.VlanID, ep.LocalIP, nl, ovsctl.NewOvsctl(), plc, iptc)
 			}
 		} else if nw.Mode != opModeTransparent {
-			epClient = NewLinuxBridgeEndpointClient(nw.extIf, ep.HostIfName, "", nw.Mode, nl, plc)
+			epClient = NewLinuxBridgeEndpointClient(nw.extIf, ep.HostIfName, "", nw.Mode, nl, nioc, plc, iptc)
 		} else {
 			// delete if secondary interfaces populated or endpoint of type delegated (new way)
 			if len(ep.SecondaryInterfaces) > 0 || ep.NICType == cns.DelegatedVMNIC {
@@ -297,7 +354,7 @@ func (nw *network) deleteEndpointImpl(nl netlink.NetlinkInterface, plc platform.
 				}
 			}
 
-			epClient = NewTransparentEndpointClient(nw.extIf, ep.HostIfName, "", nw.Mode, nl, nioc, plc)
+			epClient = NewTransparentEndpointClient(nw.extIf, ep.HostIfName, "", nw.Mode, nl, nioc, plc, iptc)
 		}
 	}
 
@@ -310,12 +367,274 @@ func (nw *network) deleteEndpointImpl(nl netlink.NetlinkInterface, plc platform.
 	return nil
 }
 
+// deleteEndpointStateImpl removes the host rules and shaping of an endpoint read from the CNS endpoint state, in
+// stateless CNI mode, where the endpoint client which added them can't be rebuilt.
+func (nw *network) deleteEndpointStateImpl(nl netlink.NetlinkInterface, iptc ipTablesClient, ep *endpoint) {
+	addresses := ep.IPAddresses
+	if ep.PortForwardAddress != "" {
+		ip, ipNet, err := net.ParseCIDR(ep.PortForwardAddress)
+		if err != nil {
+			logger.Error("Failed to parse the host port forward address", zap.String("address", ep.PortForwardAddress), zap.Error(err))
+		} else {
+			addresses = []net.IPNet{{IP: ip, Mask: ipNet.Mask}}
+		}
+	}
+	deletePortMappings(iptc, ep.ContainerID, ep.PortMappings, addresses, ep.PortForwardMasqueradeAll)
+	deleteEgressSNAT(iptc, ep.ContainerID, ep.EgressSNAT, ep.IPAddresses)
+	if ep.HostIfName != "" {
+		deleteBandwidth(nl, ep.HostIfName, ep.Bandwidth)
+	}
+}
+
 // getInfoImpl returns information about the endpoint.
 func (ep *endpoint) getInfoImpl(epInfo *EndpointInfo) {
 }
 
+// verifyEndpointImpl checks the host side of the endpoint, as programmed by its endpoint client, and the container
+// interface, addresses and routes of the endpoint.
+func (nw *network) verifyEndpointImpl(nl netlink.NetlinkInterface, nioc netio.NetIOInterface, nsc NamespaceClientInterface,
+	iptc ipTablesClient, ep *endpoint,
+) ([]DatapathDrift, error) {
+	var drifts []DatapathDrift
+
+	// Only veth based endpoints have host side state; delegated nics are moved into the container wholesale.
+	if ep.HostIfName != "" && ep.NICType != cns.DelegatedVMNIC {
+		hostDrifts, err := nw.verifyHostSide(nl, nioc, nsc, ep)
+		if err != nil {
+			return nil, err
+		}
+		drifts = append(drifts, hostDrifts...)
+		drifts = append(drifts, nw.verifyEndpointRules(iptc, ep)...)
+	}
+
+	if ep.NetworkNameSpace == "" {
+		return drifts, nil
+	}
+
+	ns, err := nsc.OpenNamespace(ep.NetworkNameSpace)
+	if err != nil {
+		drifts = append(drifts, DatapathDrift{Resource: DriftResourceNetNs, Name: ep.NetworkNameSpace, Reason: err.Error()})
+		return drifts, nil
+	}
+	defer ns.Close()
+
+	logger.Info("Entering netns", zap.String("NetNsPath", ep.NetworkNameSpace))
+	if err := ns.Enter(); err != nil {
+		return nil, fmt.Errorf("failed to enter netns %s: %w", ep.NetworkNameSpace, err)
+	}
+
+	defer func() {
+		logger.Info("Exiting netns", zap.String("NetNsPath", ep.NetworkNameSpace))
+		if err := ns.Exit(); err != nil {
+			logger.Error("Failed to exit netns with", zap.Error(err))
+		}
+	}()
+
+	containerIf, err := nioc.GetNetworkInterfaceByName(ep.IfName)
+	if err != nil {
+		drifts = append(drifts, DatapathDrift{Resource: DriftResourceContainerInterface, Name: ep.IfName, Reason: err.Error()})
+		return drifts, nil
+	}
+
+	addrs, err := nioc.GetNetworkInterfaceAddrs(containerIf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get addresses of %s: %w", ep.IfName, err)
+	}
+
+	for _, ipAddr := range ep.IPAddresses {
+		found := false
+		for _, addr := range addrs {
+			if ip, _, err := net.ParseCIDR(addr.String()); err == nil && ip.Equal(ipAddr.IP) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			drifts = append(drifts, DatapathDrift{Resource: DriftResourceAddress, Name: ipAddr.String(), Reason: "address not assigned to " + ep.IfName})
+		}
+	}
+
+	for i := range ep.Routes {
+		dst := ep.Routes[i].Dst
+		if found, err := routeExists(nl, &dst, 0); err != nil {
+			return nil, err
+		} else if !found {
+			drifts = append(drifts, DatapathDrift{Resource: DriftResourceRoute, Name: dst.String(), Reason: "route not found in container"})
+		}
+	}
+
+	return drifts, nil
+}
+
+// verifyHostSide checks the host veth of the endpoint and what its endpoint client attached to it: the routes to the
+// endpoint addresses in transparent and transparent vlan mode, where the latter are in the vnet namespace of the vlan,
+// and the bridge in bridge mode. The mode is not recorded in stateless CNI mode, so only the host veth is checked there.
+func (nw *network) verifyHostSide(nl netlink.NetlinkInterface, nioc netio.NetIOInterface, nsc NamespaceClientInterface,
+	ep *endpoint,
+) ([]DatapathDrift, error) {
+	if nw.Mode == "" {
+		return verifyHostVeth(nl, nioc, ep, false)
+	}
+
+	switch endpointClientKind(nw.Mode, ep.VlanID, ep.NICType) {
+	case endpointClientTransparent:
+		return verifyHostVeth(nl, nioc, ep, true)
+	case endpointClientTransparentVlan:
+		vnetNSName := fmt.Sprintf("az_ns_%d", ep.VlanID)
+		var drifts []DatapathDrift
+		err := ExecuteInNS(nsc, vnetNSName, func() error {
+			var err error
+			drifts, err = verifyHostVeth(nl, nioc, ep, true)
+			return err
+		})
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return []DatapathDrift{{Resource: DriftResourceNetNs, Name: vnetNSName, Reason: err.Error()}}, nil
+			}
+			return nil, fmt.Errorf("failed to verify endpoint in vnet namespace %s: %w", vnetNSName, err)
+		}
+		return drifts, nil
+	case endpointClientBridge:
+		drifts, err := verifyHostVeth(nl, nioc, ep, false)
+		if err != nil || len(drifts) > 0 || nw.extIf == nil || nw.extIf.BridgeName == "" {
+			return drifts, err
+		}
+		bridge, err := nioc.GetNetworkInterfaceByName(nw.extIf.BridgeName)
+		if err != nil {
+			return append(drifts, DatapathDrift{Resource: DriftResourceHostInterface, Name: nw.extIf.BridgeName, Reason: err.Error()}), nil
+		}
+		link, err := nl.GetLinkByName(ep.HostIfName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get link %s: %w", ep.HostIfName, err)
+		}
+		if link.Info().MasterIndex != bridge.Index {
+			drifts = append(drifts, DatapathDrift{Resource: DriftResourceHostInterface, Name: ep.HostIfName, Reason: "not attached to " + nw.extIf.BridgeName})
+		}
+		return drifts, nil
+	default:
+		return verifyHostVeth(nl, nioc, ep, false)
+	}
+}
+
+// verifyHostVeth checks that the host veth exists and, if withRoutes is set, that the endpoint addresses are routed through it.
+func verifyHostVeth(nl netlink.NetlinkInterface, nioc netio.NetIOInterface, ep *endpoint, withRoutes bool) ([]DatapathDrift, error) {
+	hostIf, err := nioc.GetNetworkInterfaceByName(ep.HostIfName)
+	if err != nil {
+		return []DatapathDrift{{Resource: DriftResourceHostInterface, Name: ep.HostIfName, Reason: err.Error()}}, nil
+	}
+	if !withRoutes {
+		return nil, nil
+	}
+
+	var drifts []DatapathDrift
+	// ip route <podip> dev <hostveth> set up by the transparent and transparent vlan endpoint clients.
+	for _, ipAddr := range ep.IPAddresses {
+		dst := net.IPNet{IP: ipAddr.IP, Mask: net.CIDRMask(ipv4FullMask, ipv4Bits)}
+		if ipAddr.IP.To4() == nil {
+			dst = net.IPNet{IP: ipAddr.IP, Mask: net.CIDRMask(ipv6FullMask, ipv6Bits)}
+		}
+		if found, err := routeExists(nl, &dst, hostIf.Index); err != nil {
+			return nil, err
+		} else if !found {
+			drifts = append(drifts, DatapathDrift{Resource: DriftResourceHostRoute, Name: dst.String(), Reason: "route via " + ep.HostIfName + " not found"})
+		}
+	}
+	return drifts, nil
+}
+
+// verifyEndpointRules checks the host port and egress SNAT iptables rules of the endpoint.
+func (nw *network) verifyEndpointRules(iptc ipTablesClient, ep *endpoint) []DatapathDrift {
+	var rules []iptablesRule
+	addresses, masqueradeAll := ep.IPAddresses, false
+	if nw.Mode == opModeTransparentVlan && ep.VlanID != 0 {
+		// host ports are forwarded to the SNAT endpoint, see TransparentVlanEndpointClient.portMappingAddresses
+		addresses, masqueradeAll = nil, true
+		if ip, ipNet, err := net.ParseCIDR(ep.LocalIP); err == nil {
+			addresses = []net.IPNet{{IP: ip, Mask: ipNet.Mask}}
+		}
+	}
+	if portMappingRules, err := portMappingRules(ep.ContainerID, ep.PortMappings, addresses, masqueradeAll); err == nil {
+		rules = append(rules, portMappingRules...)
+	}
+	if ep.EgressSNAT != nil {
+		if egressRules, err := egressSNATRules(ep.ContainerID, ep.EgressSNAT, ep.IPAddresses); err == nil {
+			rules = append(rules, egressRules...)
+		}
+	}
+
+	return verifyRules(iptc, rules)
+}
+
+// verifyRules checks that the iptables rules exist.
+func verifyRules(iptc ipTablesClient, rules []iptablesRule) []DatapathDrift {
+	var drifts []DatapathDrift
+	for _, rule := range rules {
+		if !iptc.RuleExists(rule.version, rule.table, rule.chain, rule.match, rule.target) {
+			drifts = append(drifts, DatapathDrift{
+				Resource: DriftResourceHostRule,
+				Name:     fmt.Sprintf("-t %s -A %s %s -j %s", rule.table, rule.chain, rule.match, rule.target),
+				Reason:   "iptables rule not found",
+			})
+		}
+	}
+	return drifts
+}
+
+// routeExists reports whether a route to dst exists in the main table, optionally restricted to a link.
+func routeExists(nl netlink.NetlinkInterface, dst *net.IPNet, linkIndex int) (bool, error) {
+	family := netlink.GetIPAddressFamily(dst.IP)
+	routes, err := nl.GetIPRoute(&netlink.Route{Family: family, Dst: dst, LinkIndex: linkIndex})
+	if err != nil {
+		return false, fmt.Errorf("failed to list routes: %w", err)
+	}
+	return len(routes) > 0, nil
+}
+
+// setLinksMTU sets the MTU of the interfaces, unless mtu is 0.
+func setLinksMTU(nl netlink.NetlinkInterface, mtu int, ifNames ...string) error {
+	if mtu == 0 {
+		return nil
+	}
+	for _, ifName := range ifNames {
+		logger.Info("Setting mtu", zap.String("ifName", ifName), zap.Int("MTU", mtu))
+		if err := nl.SetLinkMTU(ifName, mtu); err != nil {
+			return fmt.Errorf("failed to set mtu %d on %s: %w", mtu, ifName, err)
+		}
+	}
+	return nil
+}
+
+// setLinksOffloads turns the offloads of the interfaces on or off, unless offloads is nil.
+func setLinksOffloads(nl netlink.NetlinkInterface, offloads *Offloads, ifNames ...string) error {
+	if offloads == nil {
+		return nil
+	}
+	for _, ifName := range ifNames {
+		for _, o := range []struct {
+			offload netlink.Offload
+			on      *bool
+		}{{netlink.OffloadTSO, offloads.TSO}, {netlink.OffloadGRO, offloads.GRO}} {
+			if o.on == nil {
+				continue
+			}
+			logger.Info("Setting offload", zap.String("ifName", ifName), zap.Stringer("offload", o.offload), zap.Bool("on", *o.on))
+			if err := nl.SetLinkOffload(ifName, o.offload, *o.on); err != nil {
+				return fmt.Errorf("failed to set offloads of %s: %w", ifName, err)
+			}
+		}
+	}
+	return nil
+}
+
 func addRoutes(nl netlink.NetlinkInterface, netioshim netio.NetIOInterface, interfaceName string, routes []RouteInfo) error {
+	_, err := addNewRoutes(nl, netioshim, interfaceName, routes)
+	return err
+}
+
+// addNewRoutes adds the routes like addRoutes, and returns those it added, leaving out the routes which existed already.
+func addNewRoutes(nl netlink.NetlinkInterface, netioshim netio.NetIOInterface, interfaceName string, routes []RouteInfo) ([]RouteInfo, error) {
 	ifIndex := 0
+	var added []RouteInfo
 
 	for _, route := range routes {
 		if route.DevName != "" {
@@ -325,7 +644,7 @@ func addRoutes(nl netlink.NetlinkInterface, netioshim netio.NetIOInterface, inte
 			interfaceIf, err := netioshim.GetNetworkInterfaceByName(interfaceName)
 			if err != nil {
 				logger.Error("Interface not found with", zap.Error(err))
-				return fmt.Errorf("addRoutes failed: %w", err)
+				return added, fmt.Errorf("addRoutes failed: %w", err)
 			}
 			ifIndex = interfaceIf.Index
 		}
@@ -349,14 +668,16 @@ func addRoutes(nl netlink.NetlinkInterface, netioshim netio.NetIOInterface, inte
 		logger.Info("Adding IP route to link", zap.Any("route", route), zap.String("interfaceName", interfaceName))
 		if err := nl.AddIPRoute(nlRoute); err != nil {
 			if !strings.Contains(strings.ToLower(err.Error()), "file exists") {
-				return err
+				return added, err
 			} else {
 				logger.Info("route already exists")
 			}
+		} else {
+			added = append(added, route)
 		}
 	}
 
-	return nil
+	return added, nil
 }
 
 func deleteRoutes(nl netlink.NetlinkInterface, netioshim netio.NetIOInterface, interfaceName string, routes []RouteInfo) error {
@@ -4,6 +4,7 @@ import (
 	"net"
 	"testing"
 
+	"github.com/Azure/azure-container-networking/cns"
 	"github.com/Azure/azure-container-networking/netio"
 	"github.com/Azure/azure-container-networking/netlink"
 	. "github.com/onsi/ginkgo"
@@ -134,4 +135,120 @@ var _ = Describe("Test TestEndpointLinux", func() {
 			Expect(err).ToNot(BeNil())
 		})
 	})
+	Describe("Test verifyEndpointImpl", func() {
+		podIP := net.IPNet{IP: net.ParseIP("10.240.0.5"), Mask: net.CIDRMask(16, 32)}
+		_, defaultDst, _ := net.ParseCIDR("0.0.0.0/0")
+		nw := &network{Mode: opModeTransparent}
+		newEp := func() *endpoint {
+			return &endpoint{
+				Id:               "ep1",
+				IfName:           "eth0",
+				HostIfName:       "azv1",
+				NetworkNameSpace: "/var/run/netns/test",
+				IPAddresses:      []net.IPNet{podIP},
+				Routes:           []RouteInfo{{Dst: *defaultDst}},
+				NICType:          cns.InfraNIC,
+			}
+		}
+
+		It("Should report no drift when the datapath is intact", func() {
+			nlc := netlink.NewMockNetlink(false, "")
+			nlc.SetGetRouteFn(func(filter *netlink.Route) ([]*netlink.Route, error) {
+				return []*netlink.Route{filter}, nil
+			})
+			netiocl := netio.NewMockNetIO(false, 0)
+			netiocl.SetGetInterfaceAddrsFn(func(*net.Interface) ([]net.Addr, error) {
+				return []net.Addr{&podIP}, nil
+			})
+
+			drifts, err := nw.verifyEndpointImpl(nlc, netiocl, NewMockNamespaceClient(), &recordingIPTables{}, newEp())
+			Expect(err).To(BeNil())
+			Expect(drifts).To(BeEmpty())
+		})
+		It("Should report missing addresses and routes", func() {
+			nlc := netlink.NewMockNetlink(false, "")
+			netiocl := netio.NewMockNetIO(false, 0)
+
+			drifts, err := nw.verifyEndpointImpl(nlc, netiocl, NewMockNamespaceClient(), &recordingIPTables{}, newEp())
+			Expect(err).To(BeNil())
+			Expect(drifts).To(ConsistOf(
+				DatapathDrift{Resource: DriftResourceHostRoute, Name: "10.240.0.5/32", Reason: "route via azv1 not found"},
+				DatapathDrift{Resource: DriftResourceAddress, Name: podIP.String(), Reason: "address not assigned to eth0"},
+				DatapathDrift{Resource: DriftResourceRoute, Name: defaultDst.String(), Reason: "route not found in container"},
+			))
+		})
+		It("Should report a missing host veth and container interface", func() {
+			nlc := netlink.NewMockNetlink(false, "")
+			netiocl := netio.NewMockNetIO(false, 0)
+			netiocl.SetGetInterfaceValidatonFn(func(ifName string) (*net.Interface, error) {
+				return nil, errors.Wrap(netio.ErrInterfaceNotFound, ifName)
+			})
+
+			drifts, err := nw.verifyEndpointImpl(nlc, netiocl, NewMockNamespaceClient(), &recordingIPTables{}, newEp())
+			Expect(err).To(BeNil())
+			Expect(drifts).To(HaveLen(2))
+			Expect(drifts[0].Resource).To(Equal(DriftResourceHostInterface))
+			Expect(drifts[1].Resource).To(Equal(DriftResourceContainerInterface))
+		})
+		It("Should report missing host port and egress snat rules", func() {
+			nlc := netlink.NewMockNetlink(false, "")
+			nlc.SetGetRouteFn(func(filter *netlink.Route) ([]*netlink.Route, error) {
+				return []*netlink.Route{filter}, nil
+			})
+			netiocl := netio.NewMockNetIO(false, 0)
+			netiocl.SetGetInterfaceAddrsFn(func(*net.Interface) ([]net.Addr, error) {
+				return []net.Addr{&podIP}, nil
+			})
+			ep := newEp()
+			ep.ContainerID = "abc"
+			ep.PortMappings = []PortMapping{{HostPort: 8080, ContainerPort: 80, Protocol: "tcp"}}
+			ep.EgressSNAT = &EgressSNAT{Mode: cns.EgressSNATNone}
+			iptc := &recordingIPTables{}
+			Expect(addPortMappings(iptc, ep.ContainerID, ep.PortMappings, ep.IPAddresses, false)).To(Succeed())
+
+			drifts, err := nw.verifyEndpointImpl(nlc, netiocl, NewMockNamespaceClient(), iptc, ep)
+			Expect(err).To(BeNil())
+			Expect(drifts).To(ConsistOf(DatapathDrift{
+				Resource: DriftResourceHostRule,
+				Name:     "-t nat -A AZURECNIEGRESS -s 10.240.0.5 -m comment --comment azure-cni-egress:abc -j ACCEPT",
+				Reason:   "iptables rule not found",
+			}))
+		})
+		It("Should report a host veth which is not attached to the bridge", func() {
+			bridgeNw := &network{Mode: opModeBridge, extIf: &externalInterface{BridgeName: "azure0"}}
+			netiocl := netio.NewMockNetIO(false, 0)
+			netiocl.SetGetInterfaceAddrsFn(func(*net.Interface) ([]net.Addr, error) {
+				return []net.Addr{&podIP}, nil
+			})
+			nlc := netlink.NewMockNetlink(false, "")
+			nlc.SetGetRouteFn(func(filter *netlink.Route) ([]*netlink.Route, error) {
+				return []*netlink.Route{filter}, nil
+			})
+
+			drifts, err := bridgeNw.verifyEndpointImpl(nlc, netiocl, NewMockNamespaceClient(), &recordingIPTables{}, newEp())
+			Expect(err).To(BeNil())
+			Expect(drifts).To(ConsistOf(DatapathDrift{Resource: DriftResourceHostInterface, Name: "azv1", Reason: "not attached to azure0"}))
+		})
+		It("Should check the routes to the endpoint in the vnet namespace in transparent vlan mode", func() {
+			vlanNw := &network{Mode: opModeTransparentVlan}
+			ep := newEp()
+			ep.VlanID = 1
+			ep.NetworkNameSpace = ""
+
+			drifts, err := vlanNw.verifyEndpointImpl(netlink.NewMockNetlink(false, ""), netio.NewMockNetIO(false, 0), NewMockNamespaceClient(), &recordingIPTables{}, ep)
+			Expect(err).To(BeNil())
+			Expect(drifts).To(ConsistOf(DatapathDrift{Resource: DriftResourceHostRoute, Name: "10.240.0.5/32", Reason: "route via azv1 not found"}))
+		})
+		It("Should fail when the netns cannot be entered", func() {
+			ep := newEp()
+			ep.NetworkNameSpace = failToEnterNamespaceName
+			nlc := netlink.NewMockNetlink(false, "")
+			nlc.SetGetRouteFn(func(filter *netlink.Route) ([]*netlink.Route, error) {
+				return []*netlink.Route{filter}, nil
+			})
+
+			_, err := nw.verifyEndpointImpl(nlc, netio.NewMockNetIO(false, 0), NewMockNamespaceClient(), &recordingIPTables{}, ep)
+			Expect(err).ToNot(BeNil())
+		})
+	})
 })
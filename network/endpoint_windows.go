@@ -529,6 +529,10 @@ func (nw *network) deleteEndpointImpl(_ netlink.NetlinkInterface, _ platform.Exe
 	return nw.deleteEndpointImplHnsV1(ep)
 }
 
+// deleteEndpointStateImpl is a no-op on Windows, where the endpoint policies are removed with the HNS endpoint.
+func (nw *network) deleteEndpointStateImpl(_ netlink.NetlinkInterface, _ ipTablesClient, _ *endpoint) {
+}
+
 // deleteEndpointImplHnsV1 deletes an existing endpoint from the network using HNS v1.
 func (nw *network) deleteEndpointImplHnsV1(ep *endpoint) error {
 	logger.Info("HNSEndpointRequest DELETE id", zap.String("id", ep.HnsId))
@@ -591,6 +595,39 @@ func (nw *network) deleteEndpointImplHnsV2(ep *endpoint) error {
 	return nil
 }
 
+// verifyEndpointImpl checks that the HNS endpoint recorded for the endpoint still exists.
+func (nw *network) verifyEndpointImpl(_ netlink.NetlinkInterface, _ netio.NetIOInterface, _ NamespaceClientInterface,
+	_ ipTablesClient, ep *endpoint,
+) ([]DatapathDrift, error) {
+	if ep.NICType == cns.BackendNIC || ep.HnsId == "" {
+		return nil, nil
+	}
+
+	if useHnsV2, err := UseHnsV2(ep.NetNs); useHnsV2 {
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := Hnsv2.GetEndpointByID(ep.HnsId); err != nil {
+			if _, endpointNotFound := err.(hcn.EndpointNotFoundError); !endpointNotFound {
+				return nil, fmt.Errorf("Failed to get hcn endpoint with id: %s due to err: %w", ep.HnsId, err)
+			}
+			return []DatapathDrift{{Resource: DriftResourceHostInterface, Name: ep.HnsId, Reason: err.Error()}}, nil
+		}
+
+		return nil, nil
+	}
+
+	if _, err := Hnsv1.GetHNSEndpointByID(ep.HnsId); err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "not found") {
+			return []DatapathDrift{{Resource: DriftResourceHostInterface, Name: ep.HnsId, Reason: err.Error()}}, nil
+		}
+		return nil, err
+	}
+
+	return nil, nil
+}
+
 // getInfoImpl returns information about the endpoint.
 func (ep *endpoint) getInfoImpl(epInfo *EndpointInfo) {
 	epInfo.Data["hnsid"] = ep.HnsId
@@ -6,4 +6,5 @@ type ipTablesClient interface {
 	DeleteIptableRule(version, tableName, chainName, match, target string) error
 	CreateChain(version, tableName, chainName string) error
 	RunCmd(version, params string) error
+	RuleExists(version, tableName, chainName, match, target string) bool
 }
@@ -107,6 +107,7 @@ type NetworkManager interface {
 	EndpointCreate(client apipaClient, epInfos []*EndpointInfo) error // TODO: change name
 	DeleteEndpoint(networkID string, endpointID string, epInfo *EndpointInfo) error
 	GetEndpointInfo(networkID string, endpointID string) (*EndpointInfo, error)
+	VerifyEndpoint(networkID string, endpointID string) ([]DatapathDrift, error)
 	GetAllEndpoints(networkID string) (map[string]*EndpointInfo, error)
 	GetEndpointInfoBasedOnPODDetails(networkID string, podName string, podNameSpace string, doExactMatchForPodName bool) (*EndpointInfo, error)
 	AttachEndpoint(networkID string, endpointID string, sandboxKey string) (*endpoint, error)
@@ -119,6 +120,7 @@ type NetworkManager interface {
 	DeleteState(epInfos []*EndpointInfo) error
 	GetEndpointInfosFromContainerID(containerID string) []*EndpointInfo
 	GetEndpointState(networkID, containerID string) ([]*EndpointInfo, error)
+	GetAllEndpointState(networkID string) ([]*EndpointInfo, error)
 }
 
 // Creates a new network manager.
@@ -474,6 +476,35 @@ func (nm *networkManager) GetEndpointState(networkID, containerID string) ([]*En
 	return epInfos, nil
 }
 
+// GetAllEndpointState returns the endpoint infos of every container in the CNS endpoint state
+func (nm *networkManager) GetAllEndpointState(networkID string) ([]*EndpointInfo, error) {
+	endpoints, err := nm.CnsClient.GetEndpoints(context.TODO())
+	if err != nil {
+		return nil, errors.Wrapf(err, "Get endpoints API returned with error")
+	}
+	epInfos := []*EndpointInfo{}
+	for containerID, endpointInfo := range endpoints {
+		for _, epInfo := range cnsEndpointInfotoCNIEpInfos(endpointInfoInNetwork(endpointInfo, networkID), containerID) {
+			epInfo.NetworkID = networkID
+			epInfos = append(epInfos, epInfo)
+		}
+	}
+	return epInfos, nil
+}
+
+// endpointInfoInNetwork returns the CNS endpoint state with only the interfaces in the network. CNS keeps the endpoints
+// of every network, and those added before the network was recorded are left out, since they may belong to any of them.
+func endpointInfoInNetwork(endpointInfo *restserver.EndpointInfo, networkID string) restserver.EndpointInfo {
+	inNetwork := *endpointInfo
+	inNetwork.IfnameToIPMap = make(map[string]*restserver.IPInfo, len(endpointInfo.IfnameToIPMap))
+	for ifName, ipInfo := range endpointInfo.IfnameToIPMap {
+		if ipInfo != nil && ipInfo.NetworkID == networkID {
+			inNetwork.IfnameToIPMap[ifName] = ipInfo
+		}
+	}
+	return inNetwork
+}
+
 // DeleteEndpoint deletes an existing container endpoint.
 func (nm *networkManager) DeleteEndpoint(networkID, endpointID string, epInfo *EndpointInfo) error {
 	nm.Lock()
@@ -528,8 +559,17 @@ func (nm *networkManager) DeleteEndpointState(networkID string, epInfo *Endpoint
 		NetNs:                    dummyGUID,                 // to trigger hnsv2, windows
 		NICType:                  epInfo.NICType,
 		IfName:                   epInfo.IfName, // TODO: For stateless cni linux populate IfName here to use in deletion in secondary endpoint client
+		ContainerID:              epInfo.ContainerID,
+		IPAddresses:              epInfo.IPAddresses,
+		PortMappings:             epInfo.PortMappings,
+		PortForwardAddress:       epInfo.PortForwardAddress,
+		PortForwardMasqueradeAll: epInfo.PortForwardMasqueradeAll,
+		Bandwidth:                epInfo.Bandwidth,
+		EgressSNAT:               epInfo.EgressSNAT,
 	}
 	logger.Info("Deleting endpoint with", zap.String("Endpoint Info: ", epInfo.PrettyString()), zap.String("HNISID : ", ep.HnsId))
+	// the host rules and ifb of the endpoint are not torn down with its container, remove the ones recorded in the state
+	nw.deleteEndpointStateImpl(nm.netlink, nm.iptablesClient, ep)
 	// do not need to Delete HNS endpoint if the there is no HNS in state
 	if ep.HnsId != "" {
 		err := nw.deleteEndpointImpl(netlink.NewNetlink(), platform.NewExecClient(logger), nil, nil, nil, nil, ep)
@@ -582,6 +622,61 @@ func (nm *networkManager) GetEndpointInfo(networkID, endpointID string) (*Endpoi
 	return ep.getInfo(), nil
 }
 
+// VerifyEndpoint compares the datapath recorded for the given endpoint against the host and
+// returns every piece of state that has drifted. An error is returned only if the check itself could not run.
+func (nm *networkManager) VerifyEndpoint(networkID, endpointID string) ([]DatapathDrift, error) {
+	nm.Lock()
+	defer nm.Unlock()
+
+	if nm.IsStatelessCNIMode() {
+		return nm.verifyEndpointState(networkID, endpointID)
+	}
+
+	nw, err := nm.getNetwork(networkID)
+	if err != nil {
+		return nil, err
+	}
+
+	ep, err := nw.getEndpoint(endpointID)
+	if err != nil {
+		return nil, err
+	}
+
+	return nw.verifyEndpointImpl(nm.netlink, nm.netio, nm.nsClient, nm.iptablesClient, ep)
+}
+
+// verifyEndpointState verifies the endpoint recorded in the CNS endpoint state, in stateless CNI mode. The state has
+// neither the network mode nor the netns of the endpoint, so only its host veth or HNS endpoint and its host rules are checked.
+func (nm *networkManager) verifyEndpointState(networkID, endpointID string) ([]DatapathDrift, error) {
+	epInfos, err := nm.GetEndpointState(networkID, endpointID)
+	if err != nil {
+		return nil, err
+	}
+
+	nw := &network{Id: networkID}
+	var drifts []DatapathDrift
+	for _, epInfo := range epInfos {
+		ep := &endpoint{
+			Id:           epInfo.EndpointID,
+			IfName:       epInfo.IfName,
+			HostIfName:   epInfo.HostIfName,
+			HnsId:        epInfo.HNSEndpointID,
+			NetNs:        dummyGUID, // to use hnsv2, windows
+			IPAddresses:  epInfo.IPAddresses,
+			ContainerID:  epInfo.ContainerID,
+			NICType:      epInfo.NICType,
+			PortMappings: epInfo.PortMappings,
+			EgressSNAT:   epInfo.EgressSNAT,
+		}
+		epDrifts, err := nw.verifyEndpointImpl(nm.netlink, nm.netio, nm.nsClient, nm.iptablesClient, ep)
+		if err != nil {
+			return nil, err
+		}
+		drifts = append(drifts, epDrifts...)
+	}
+	return drifts, nil
+}
+
 func (nm *networkManager) GetAllEndpoints(networkId string) (map[string]*EndpointInfo, error) {
 	nm.Lock()
 	defer nm.Unlock()
@@ -803,6 +898,13 @@ func cnsEndpointInfotoCNIEpInfos(endpointInfo restserver.EndpointInfo, endpointI
 		epInfo.NICType = ipInfo.NICType
 		epInfo.HNSNetworkID = ipInfo.HnsNetworkID
 		epInfo.MacAddress = net.HardwareAddr(ipInfo.MacAddress)
+		epInfo.PortMappings = portMappingsFromCNS(ipInfo.PortMappings)
+		epInfo.PortForwardAddress = ipInfo.PortForwardAddress
+		epInfo.PortForwardMasqueradeAll = ipInfo.PortForwardMasqueradeAll
+		epInfo.Bandwidth = bandwidthFromCNS(ipInfo.Bandwidth)
+		epInfo.Mode = ipInfo.NetworkMode
+		epInfo.EgressSNAT = ipInfo.EgressSNAT
+		epInfo.Created = ipInfo.Created
 		ret = append(ret, epInfo)
 	}
 	return ret
@@ -831,13 +933,57 @@ func generateCNSIPInfoMap(eps []*endpoint) map[string]*restserver.IPInfo {
 
 	for _, ep := range eps {
 		ifNametoIPInfoMap[ep.IfName] = &restserver.IPInfo{ // in windows, the nicname is args ifname, in linux, it's ethX
-			NICType:       ep.NICType,
-			HnsEndpointID: ep.HnsId,
-			HnsNetworkID:  ep.HNSNetworkID,
-			HostVethName:  ep.HostIfName,
-			MacAddress:    ep.MacAddress.String(),
+			NICType:                  ep.NICType,
+			HnsEndpointID:            ep.HnsId,
+			HnsNetworkID:             ep.HNSNetworkID,
+			HostVethName:             ep.HostIfName,
+			MacAddress:               ep.MacAddress.String(),
+			PortMappings:             portMappingsToCNS(ep.PortMappings),
+			PortForwardAddress:       ep.PortForwardAddress,
+			PortForwardMasqueradeAll: ep.PortForwardMasqueradeAll,
+			Bandwidth:                bandwidthToCNS(ep.Bandwidth),
+			NetworkMode:              ep.NetworkMode,
+			EgressSNAT:               ep.EgressSNAT,
+			NetworkID:                ep.NetworkID,
+			Created:                  ep.Created,
 		}
 	}
 
 	return ifNametoIPInfoMap
 }
+
+func portMappingsToCNS(mappings []PortMapping) []restserver.PortMapping {
+	if len(mappings) == 0 {
+		return nil
+	}
+	out := make([]restserver.PortMapping, len(mappings))
+	for i, m := range mappings {
+		out[i] = restserver.PortMapping{HostPort: m.HostPort, ContainerPort: m.ContainerPort, Protocol: m.Protocol, HostIP: m.HostIP}
+	}
+	return out
+}
+
+func bandwidthToCNS(bw *Bandwidth) *restserver.Bandwidth {
+	if bw == nil {
+		return nil
+	}
+	return &restserver.Bandwidth{IngressRate: bw.IngressRate, IngressBurst: bw.IngressBurst, EgressRate: bw.EgressRate, EgressBurst: bw.EgressBurst}
+}
+
+func bandwidthFromCNS(bw *restserver.Bandwidth) *Bandwidth {
+	if bw == nil {
+		return nil
+	}
+	return &Bandwidth{IngressRate: bw.IngressRate, IngressBurst: bw.IngressBurst, EgressRate: bw.EgressRate, EgressBurst: bw.EgressBurst}
+}
+
+func portMappingsFromCNS(mappings []restserver.PortMapping) []PortMapping {
+	if len(mappings) == 0 {
+		return nil
+	}
+	out := make([]PortMapping, len(mappings))
+	for i, m := range mappings {
+		out[i] = PortMapping{HostPort: m.HostPort, ContainerPort: m.ContainerPort, Protocol: m.Protocol, HostIP: m.HostIP}
+	}
+	return out
+}
@@ -10,6 +10,8 @@ type MockNetworkManager struct {
 	TestEndpointInfoMap map[string]*EndpointInfo
 	TestEndpointClient  *MockEndpointClient
 	SaveStateMap        map[string]*endpoint
+	TestDriftMap        map[string][]DatapathDrift
+	StatelessCNIMode    bool
 }
 
 // NewMockNetworkmanager returns a new mock
@@ -19,6 +21,7 @@ func NewMockNetworkmanager(mockEndpointclient *MockEndpointClient) *MockNetworkM
 		TestEndpointInfoMap: make(map[string]*EndpointInfo),
 		TestEndpointClient:  mockEndpointclient,
 		SaveStateMap:        make(map[string]*endpoint),
+		TestDriftMap:        make(map[string][]DatapathDrift),
 	}
 }
 
@@ -78,7 +81,7 @@ func (nm *MockNetworkManager) SetStatelessCNIMode() error {
 
 // IsStatelessCNIMode checks if the Stateless CNI mode has been enabled or not
 func (nm *MockNetworkManager) IsStatelessCNIMode() bool {
-	return false
+	return nm.StatelessCNIMode
 }
 
 // GetEndpointID returns the ContainerID value
@@ -106,6 +109,14 @@ func (nm *MockNetworkManager) GetEndpointInfo(_, endpointID string) (*EndpointIn
 	return nil, errEndpointNotFound
 }
 
+// VerifyEndpoint mock
+func (nm *MockNetworkManager) VerifyEndpoint(_, endpointID string) ([]DatapathDrift, error) {
+	if _, exists := nm.TestEndpointInfoMap[endpointID]; !exists {
+		return nil, errEndpointNotFound
+	}
+	return nm.TestDriftMap[endpointID], nil
+}
+
 // GetEndpointInfoBasedOnPODDetails mock
 func (nm *MockNetworkManager) GetEndpointInfoBasedOnPODDetails(networkID string, podName string, podNameSpace string, doExactMatchForPodName bool) (*EndpointInfo, error) {
 	return &EndpointInfo{}, nil
@@ -207,6 +218,14 @@ func (nm *MockNetworkManager) GetEndpointInfosFromContainerID(containerID string
 	return ret
 }
 
-func (nm *MockNetworkManager) GetEndpointState(_, _ string) ([]*EndpointInfo, error) {
-	return []*EndpointInfo{}, nil
+func (nm *MockNetworkManager) GetEndpointState(_, containerID string) ([]*EndpointInfo, error) {
+	return nm.GetEndpointInfosFromContainerID(containerID), nil
+}
+
+func (nm *MockNetworkManager) GetAllEndpointState(_ string) ([]*EndpointInfo, error) {
+	epInfos := []*EndpointInfo{}
+	for _, epInfo := range nm.TestEndpointInfoMap {
+		epInfos = append(epInfos, epInfo)
+	}
+	return epInfos, nil
 }
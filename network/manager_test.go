@@ -386,6 +386,9 @@ var _ = Describe("Test Manager", func() {
 							HnsNetworkID:  "hnsNetworkID1",
 							MacAddress:    "12:34:56:78:9a:bc",
 							NICType:       cns.InfraNIC,
+							PortMappings:  []restserver.PortMapping{{HostPort: 8080, ContainerPort: 80, Protocol: "tcp"}},
+							Bandwidth:     &restserver.Bandwidth{EgressRate: 1000000, EgressBurst: 80000},
+							EgressSNAT:    &cns.EgressSNAT{Mode: cns.EgressSNATSecondaryIP, IPAddress: "10.240.0.10"},
 						},
 						"ifName2": {
 							IPv4:          dummyIPv4Slice2,
@@ -412,6 +415,9 @@ var _ = Describe("Test Manager", func() {
 						NICType:            cns.InfraNIC,
 						HNSNetworkID:       "hnsNetworkID1",
 						MacAddress:         net.HardwareAddr("12:34:56:78:9a:bc"),
+						PortMappings:       []PortMapping{{HostPort: 8080, ContainerPort: 80, Protocol: "tcp"}},
+						Bandwidth:          &Bandwidth{EgressRate: 1000000, EgressBurst: 80000},
+						EgressSNAT:         &EgressSNAT{Mode: cns.EgressSNATSecondaryIP, IPAddress: "10.240.0.10"},
 						ContainerID:        endpointID,
 						EndpointID:         endpointID,
 						NetworkContainerID: endpointID,
@@ -443,6 +449,7 @@ var _ = Describe("Test Manager", func() {
 			It("Should generate the cns endpoint info data from the endpoint structs", func() {
 				mac1, _ := net.ParseMAC("12:34:56:78:9a:bc")
 				mac2, _ := net.ParseMAC("22:34:56:78:9a:bc")
+				created := time.Unix(1700000000, 0)
 				endpoints := []*endpoint{
 					{
 						IfName:       "eth0",
@@ -451,6 +458,12 @@ var _ = Describe("Test Manager", func() {
 						HNSNetworkID: "hnsNetworkID1",
 						HostIfName:   "hostIfName1",
 						MacAddress:   mac1,
+						PortMappings: []PortMapping{{HostPort: 8080, ContainerPort: 80, Protocol: "tcp", HostIP: "10.1.0.4"}},
+						Bandwidth:    &Bandwidth{IngressRate: 1000000, IngressBurst: 80000},
+						NetworkMode:  opModeTransparent,
+						EgressSNAT:   &EgressSNAT{Mode: cns.EgressSNATNodeIP, Except: []string{"10.240.0.0/16"}},
+						NetworkID:    "azure",
+						Created:      created,
 					},
 					{
 						IfName:       "eth1",
@@ -472,6 +485,12 @@ var _ = Describe("Test Manager", func() {
 						HnsNetworkID:  "hnsNetworkID1",
 						HostVethName:  "hostIfName1",
 						MacAddress:    "12:34:56:78:9a:bc",
+						PortMappings:  []restserver.PortMapping{{HostPort: 8080, ContainerPort: 80, Protocol: "tcp", HostIP: "10.1.0.4"}},
+						Bandwidth:     &restserver.Bandwidth{IngressRate: 1000000, IngressBurst: 80000},
+						NetworkMode:   opModeTransparent,
+						EgressSNAT:    &cns.EgressSNAT{Mode: cns.EgressSNATNodeIP, Except: []string{"10.240.0.0/16"}},
+						NetworkID:     "azure",
+						Created:       created,
 					},
 				))
 
@@ -488,4 +507,26 @@ var _ = Describe("Test Manager", func() {
 			})
 		})
 	})
+	Describe("Test stateless endpointInfoInNetwork", func() {
+		Context("When CNS keeps the endpoints of several networks", func() {
+			It("Should only keep the interfaces recorded in the network", func() {
+				endpointInfo := &restserver.EndpointInfo{
+					PodName:      "test-pod",
+					PodNamespace: "test-pod-ns",
+					IfnameToIPMap: map[string]*restserver.IPInfo{
+						"eth0": {NICType: cns.InfraNIC, NetworkID: "azure"},
+						"eth1": {NICType: cns.DelegatedVMNIC, NetworkID: "other"},
+						"eth2": {NICType: cns.DelegatedVMNIC},
+					},
+				}
+				inNetwork := endpointInfoInNetwork(endpointInfo, "azure")
+				Expect(inNetwork.PodName).To(Equal("test-pod"))
+				Expect(inNetwork.IfnameToIPMap).To(HaveLen(1))
+				Expect(inNetwork.IfnameToIPMap).To(HaveKey("eth0"))
+				Expect(endpointInfo.IfnameToIPMap).To(HaveLen(3))
+
+				Expect(endpointInfoInNetwork(endpointInfo, "unknown").IfnameToIPMap).To(BeEmpty())
+			})
+		})
+	})
 })
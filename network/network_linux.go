@@ -562,6 +562,11 @@ func (nm *networkManager) connectExternalInterface(extIf *externalInterface, nwI
 		return errors.Wrap(err, "failed to set external interface up")
 	}
 
+	// Bridge MTU, after the external interface joined as that lowers it to the smallest MTU of the ports.
+	if err = setLinksMTU(nm.netlink, nwInfo.MTU, bridgeName); err != nil {
+		return err
+	}
+
 	// Bridge up.
 	err = nm.netlink.SetLinkState(bridgeName, true)
 	if err != nil {
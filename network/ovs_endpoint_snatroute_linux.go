@@ -31,6 +31,7 @@ func (client *OVSEndpointClient) NewSnatClient(snatBridgeIP, localIP string, epI
 			epInfo.EndpointDNS.Servers,
 			false,
 			client.netlink,
+			client.netioshim,
 			client.plClient,
 			client.iptablesClient,
 		)
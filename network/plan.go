@@ -0,0 +1,81 @@
+package network
+
+// EndpointPlan is what creating an endpoint would do on the host, as planned by PlanEndpoint without touching it.
+type EndpointPlan struct {
+	EndpointID     string `json:"endpointID"`
+	EndpointClient string `json:"endpointClient"`
+	// Namespaces are the network namespaces which are created.
+	Namespaces    []string          `json:"namespaces,omitempty"`
+	Links         []PlannedLink     `json:"links,omitempty"`
+	Addresses     []PlannedAddress  `json:"addresses,omitempty"`
+	Routes        []PlannedRoute    `json:"routes,omitempty"`
+	DeletedRoutes []PlannedRoute    `json:"deletedRoutes,omitempty"`
+	Neighbors     []PlannedNeighbor `json:"neighbors,omitempty"`
+	IPRules       []PlannedIPRule   `json:"ipRules,omitempty"`
+	Sysctls       []PlannedSysctl   `json:"sysctls,omitempty"`
+	// The rules and commands run in other network namespaces than the host one are prefixed with nsenter.
+	IPTablesRules []string   `json:"iptablesRules,omitempty"`
+	EbtablesRules []string   `json:"ebtablesRules,omitempty"`
+	Commands      []string   `json:"commands,omitempty"`
+	Bandwidth     *Bandwidth `json:"bandwidth,omitempty"`
+	// Notes are the steps which are not planned in detail, such as those of endpoint clients without a planner.
+	Notes []string `json:"notes,omitempty"`
+}
+
+// PlannedLink is a link which is created or changed. Links without a netns are in the host network namespace.
+type PlannedLink struct {
+	Name   string `json:"name"`
+	Kind   string `json:"kind,omitempty"`
+	Peer   string `json:"peer,omitempty"`
+	Parent string `json:"parent,omitempty"`
+	VlanID int    `json:"vlanID,omitempty"`
+	Master string `json:"master,omitempty"`
+	Netns  string `json:"netns,omitempty"`
+	// RenamedFrom is the name of the link before it is moved into the netns.
+	RenamedFrom string          `json:"renamedFrom,omitempty"`
+	MAC         string          `json:"mac,omitempty"`
+	MTU         int             `json:"mtu,omitempty"`
+	TxQueueLen  int             `json:"txQueueLen,omitempty"`
+	Offloads    map[string]bool `json:"offloads,omitempty"`
+	Promisc     bool            `json:"promisc,omitempty"`
+	Hairpin     bool            `json:"hairpin,omitempty"`
+}
+
+// PlannedAddress is an address which is assigned to a link.
+type PlannedAddress struct {
+	Link    string `json:"link"`
+	Netns   string `json:"netns,omitempty"`
+	Address string `json:"address"`
+}
+
+// PlannedRoute is a route which is added through a link, or deleted.
+type PlannedRoute struct {
+	Dst   string `json:"dst"`
+	Gw    string `json:"gw,omitempty"`
+	Link  string `json:"link,omitempty"`
+	Netns string `json:"netns,omitempty"`
+	Scope string `json:"scope,omitempty"`
+	Table int    `json:"table,omitempty"`
+}
+
+// PlannedNeighbor is a static neighbor entry which is added to a link.
+type PlannedNeighbor struct {
+	Link  string `json:"link"`
+	Netns string `json:"netns,omitempty"`
+	IP    string `json:"ip"`
+	MAC   string `json:"mac"`
+}
+
+// PlannedIPRule is a policy routing rule which is added.
+type PlannedIPRule struct {
+	Mark  uint32 `json:"mark,omitempty"`
+	Table int    `json:"table"`
+	Netns string `json:"netns,omitempty"`
+}
+
+// PlannedSysctl is a sysctl which is set.
+type PlannedSysctl struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+	Netns string `json:"netns,omitempty"`
+}
@@ -0,0 +1,775 @@
+package network
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-container-networking/iptables"
+	"github.com/Azure/azure-container-networking/netio"
+	"github.com/Azure/azure-container-networking/netlink"
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// plannedLinkIndex is the index of the first link the endpoint clients create in a plan, above those of the host.
+	plannedLinkIndex = 1 << 16
+	// defaultLinkMTU is the MTU the kernel gives the links which are created without one.
+	defaultLinkMTU = 1500
+	netnsDir       = "/var/run/netns/"
+)
+
+var (
+	sysctlCmdRegex  = regexp.MustCompile(`^sysctl -w ([^=\s]+)='?([^']*)'?$`)
+	procSysCmdRegex = regexp.MustCompile(`^echo (\S+) > /proc/sys/(\S+)$`)
+)
+
+// PlanEndpoint returns what creating the endpoint would do on the host, without touching it. The endpoint client of
+// the endpoint runs against a planRecorder, which records its changes in the plan instead of applying them.
+func PlanEndpoint(epInfo *EndpointInfo) (*EndpointPlan, error) {
+	return planEndpoint(epInfo, net.Interfaces)
+}
+
+func planEndpoint(epInfo *EndpointInfo, hostInterfaces func() ([]net.Interface, error)) (*EndpointPlan, error) {
+	// the endpoint clients update the endpoint info as they go
+	info := *epInfo
+	info.Routes = append([]RouteInfo(nil), epInfo.Routes...)
+
+	mode := info.Mode
+	if mode == "" {
+		mode = opModeDefault
+	}
+	vlanID, _ := info.Data[VlanIDKey].(int)
+	localIP, _ := info.Data[LocalIPKey].(string)
+	plan := &EndpointPlan{
+		EndpointID:     info.EndpointID,
+		EndpointClient: endpointClientKind(mode, vlanID, info.NICType),
+		Bandwidth:      info.Bandwidth,
+	}
+	if plan.EndpointClient == endpointClientOVS {
+		plan.Notes = append(plan.Notes, fmt.Sprintf("the steps of the %s are not planned", plan.EndpointClient))
+		return plan, nil
+	}
+
+	r, err := newPlanRecorder(plan, hostInterfaces)
+	if err != nil {
+		return nil, err
+	}
+	nw := &network{Id: info.NetworkID, Mode: mode, Endpoints: make(map[string]*endpoint)}
+	if nw.extIf, err = r.externalInterface(&info); err != nil {
+		return nil, err
+	}
+
+	// the options are only applied when the network is created, as in newNetworkImpl.
+	ifName := nw.extIf.Name
+	if plan.EndpointClient == endpointClientBridge {
+		ifName = nw.extIf.BridgeName
+		r.planBridge(nw.extIf)
+	}
+	nm := &networkManager{netlink: r, netio: r, plClient: r.exec, iptablesClient: r}
+	if err := nm.handleCommonOptions(ifName, &info); err != nil {
+		return nil, errors.Wrap(err, "failed to plan network options")
+	}
+
+	hostIfName, contIfName := endpointVethNames(&info)
+	ep := &endpoint{HostIfName: hostIfName, VlanID: vlanID, LocalIP: localIP, SecondaryInterfaces: make(map[string]*InterfaceInfo)}
+	epClient := nw.newEndpointClient(&info, ep, contIfName, r, r.exec, r, r, r)
+	if client, ok := epClient.(*TransparentVlanEndpointClient); ok {
+		client.netnsClient = r
+	}
+	if _, err := nw.newEndpointImpl(nil, r, r.exec, r, epClient, r, r, &info); err != nil {
+		return nil, errors.Wrap(err, "failed to plan endpoint")
+	}
+
+	for _, l := range r.links {
+		if l.changed && !l.deleted {
+			plan.Links = append(plan.Links, l.PlannedLink)
+		}
+	}
+	return plan, nil
+}
+
+// planRecorder stands in for the netlink, netio, exec, namespace and iptables clients of an endpoint client, and
+// records what it changes through them in the plan instead of changing the host. The interfaces of the host are read,
+// as is /proc when the endpoint client checks whether IPv6 router advertisements are accepted, while the links the
+// endpoint client creates are made up, with placeholder MAC addresses. The links in network namespaces which exist
+// already are assumed to exist, iptables and ebtables rules are assumed not to exist yet.
+type planRecorder struct {
+	exec             *planExec
+	iptablesCommands *iptables.Client
+	plan             *EndpointPlan
+	hostInterfaces   []net.Interface
+	links            []*recordedLink
+	nextIndex        int
+	// netns is the path of the network namespace the endpoint client is in, empty for the host one.
+	netns string
+	// namespaces are the paths of the network namespaces, by their made up file descriptors.
+	namespaces        []string
+	deletedNamespaces map[string]bool
+	// macs are the placeholders of the made up MAC addresses.
+	macs map[string]string
+}
+
+// recordedLink is a link of the host or made up by the plan. Only the links which are created or changed are planned.
+type recordedLink struct {
+	PlannedLink
+	index   int
+	mac     net.HardwareAddr
+	hostMTU int
+	created bool
+	changed bool
+	deleted bool
+}
+
+func newPlanRecorder(plan *EndpointPlan, hostInterfaces func() ([]net.Interface, error)) (*planRecorder, error) {
+	ifs, err := hostInterfaces()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list host interfaces")
+	}
+	r := &planRecorder{
+		iptablesCommands:  iptables.NewClient(),
+		plan:              plan,
+		hostInterfaces:    ifs,
+		nextIndex:         plannedLinkIndex,
+		namespaces:        []string{""},
+		deletedNamespaces: make(map[string]bool),
+		macs:              make(map[string]string),
+	}
+	r.exec = &planExec{r: r}
+	return r, nil
+}
+
+// externalInterface returns the external interface of the network of the endpoint, which is its master interface.
+func (r *planRecorder) externalInterface(epInfo *EndpointInfo) (*externalInterface, error) {
+	extIf := &externalInterface{Name: epInfo.MasterIfName, BridgeName: epInfo.BridgeName}
+	if hostIf := r.hostInterface(epInfo.MasterIfName); hostIf != nil {
+		extIf.MacAddress = hostIf.HardwareAddr
+		if extIf.BridgeName == "" {
+			extIf.BridgeName = fmt.Sprintf("%s%d", bridgePrefix, hostIf.Index)
+		}
+	} else if r.plan.EndpointClient == endpointClientBridge && extIf.BridgeName == "" {
+		return nil, errors.Errorf("failed to find master interface %s", epInfo.MasterIfName)
+	}
+	return extIf, nil
+}
+
+// planBridge plans the bridge of the network, which is only created with the network.
+func (r *planRecorder) planBridge(extIf *externalInterface) {
+	if r.hostInterface(extIf.BridgeName) != nil {
+		return
+	}
+	bridge := r.newLink(extIf.BridgeName, nil)
+	bridge.Kind = netlink.LINK_TYPE_BRIDGE
+	if master := r.findLink(extIf.Name); master != nil {
+		master.Master = extIf.BridgeName
+		master.changed = true
+	}
+	r.plan.Notes = append(r.plan.Notes, fmt.Sprintf("the bridge %s is created and %s is connected to it, moving its addresses and routes, only if the network does not exist yet",
+		extIf.BridgeName, extIf.Name))
+}
+
+func (r *planRecorder) hostInterface(name string) *net.Interface {
+	for i := range r.hostInterfaces {
+		if r.hostInterfaces[i].Name == name {
+			return &r.hostInterfaces[i]
+		}
+	}
+	return nil
+}
+
+func (r *planRecorder) isPlannedNamespace(path string) bool {
+	for _, ns := range r.plan.Namespaces {
+		if ns == path {
+			return true
+		}
+	}
+	return false
+}
+
+// findLink returns the link in the network namespace the endpoint client is in, nil if it does not exist.
+func (r *planRecorder) findLink(name string) *recordedLink {
+	for _, l := range r.links {
+		if l.Name == name && l.Netns == r.netns {
+			if l.deleted {
+				return nil
+			}
+			return l
+		}
+	}
+
+	switch {
+	case r.netns == "":
+		hostIf := r.hostInterface(name)
+		if hostIf == nil {
+			return nil
+		}
+		l := &recordedLink{PlannedLink: PlannedLink{Name: name}, index: hostIf.Index, mac: hostIf.HardwareAddr, hostMTU: hostIf.MTU}
+		r.links = append(r.links, l)
+		return l
+	case r.isPlannedNamespace(r.netns):
+		return nil
+	default:
+		l := r.newLink(name, nil)
+		l.created = false
+		l.changed = false
+		return l
+	}
+}
+
+// newLink makes up a link in the network namespace the endpoint client is in.
+func (r *planRecorder) newLink(name string, mac net.HardwareAddr) *recordedLink {
+	index := r.nextIndex
+	r.nextIndex++
+	if mac == nil {
+		mac = net.HardwareAddr{0x02, 0, 0, byte(index >> 16), byte(index >> 8), byte(index)}
+		r.macs[mac.String()] = fmt.Sprintf("<mac of %s>", name)
+	}
+	l := &recordedLink{
+		PlannedLink: PlannedLink{Name: name, Netns: r.netns},
+		index:       index,
+		mac:         mac,
+		hostMTU:     defaultLinkMTU,
+		created:     true,
+		changed:     true,
+	}
+	r.links = append(r.links, l)
+	return l
+}
+
+func (r *planRecorder) linkName(index int) string {
+	for _, l := range r.links {
+		if l.index == index {
+			return l.Name
+		}
+	}
+	for i := range r.hostInterfaces {
+		if r.hostInterfaces[i].Index == index {
+			return r.hostInterfaces[i].Name
+		}
+	}
+	return ""
+}
+
+func (r *planRecorder) change(name string, f func(l *recordedLink)) error {
+	l := r.findLink(name)
+	if l == nil {
+		return errors.Wrapf(unix.ENODEV, "failed to find link %s", name)
+	}
+	f(l)
+	l.changed = true
+	return nil
+}
+
+// describe replaces the made up MAC addresses in s with their placeholders.
+func (r *planRecorder) describe(s string) string {
+	for mac, placeholder := range r.macs {
+		s = strings.ReplaceAll(s, mac, placeholder)
+	}
+	return s
+}
+
+// inNetns returns the command run in the network namespace the endpoint client is in.
+func (r *planRecorder) inNetns(cmd string) string {
+	if r.netns == "" {
+		return cmd
+	}
+	return fmt.Sprintf("nsenter --net=%s %s", r.netns, cmd)
+}
+
+func (r *planRecorder) namespaceFd(path string) int {
+	for fd, ns := range r.namespaces {
+		if ns == path {
+			return fd
+		}
+	}
+	r.namespaces = append(r.namespaces, path)
+	return len(r.namespaces) - 1
+}
+
+func (r *planRecorder) plannedRoute(route *netlink.Route) PlannedRoute {
+	planned := PlannedRoute{Link: r.linkName(route.LinkIndex), Netns: r.netns, Table: route.Table}
+	if route.Dst != nil {
+		planned.Dst = route.Dst.String()
+	}
+	if route.Gw != nil {
+		planned.Gw = route.Gw.String()
+	}
+	switch route.Scope {
+	case netlink.RT_SCOPE_LINK:
+		planned.Scope = "link"
+	case netlink.RT_SCOPE_HOST:
+		planned.Scope = "host"
+	}
+	return planned
+}
+
+// The netlink changes of the endpoint client are recorded in the plan.
+
+func (r *planRecorder) AddLink(link netlink.Link) error {
+	info := link.Info()
+	if r.findLink(info.Name) != nil {
+		return errors.Wrapf(unix.EEXIST, "failed to add link %s", info.Name)
+	}
+	l := r.newLink(info.Name, info.MacAddress)
+	l.Kind = info.Type
+	l.MTU = int(info.MTU)
+	l.TxQueueLen = int(info.TxQLen)
+	if info.ParentIndex != 0 {
+		l.Parent = r.linkName(info.ParentIndex)
+	}
+
+	switch link := link.(type) {
+	case *netlink.VEthLink:
+		l.Peer = link.PeerName
+		peer := r.newLink(link.PeerName, nil)
+		peer.Kind = info.Type
+		peer.Peer = info.Name
+		peer.MTU = l.MTU
+	case *netlink.VlanLink:
+		l.VlanID = link.VlanId
+	}
+	return nil
+}
+
+func (r *planRecorder) DeleteLink(name string) error {
+	l := r.findLink(name)
+	if l == nil {
+		return errors.Wrapf(unix.ENODEV, "failed to find link %s", name)
+	}
+	l.deleted = true
+	if !l.created {
+		r.plan.Notes = append(r.plan.Notes, r.inNetns("the existing link "+name+" is deleted"))
+	}
+	return nil
+}
+
+func (r *planRecorder) SetLinkName(name, newName string) error {
+	return r.change(name, func(l *recordedLink) {
+		if l.RenamedFrom == "" {
+			l.RenamedFrom = name
+		}
+		l.Name = newName
+	})
+}
+
+// SetLinkState is not recorded, the planned links are up.
+func (r *planRecorder) SetLinkState(string, bool) error {
+	return nil
+}
+
+func (r *planRecorder) SetLinkMTU(name string, mtu int) error {
+	return r.change(name, func(l *recordedLink) { l.MTU = mtu })
+}
+
+func (r *planRecorder) SetLinkTxQLen(name string, txQLen int) error {
+	return r.change(name, func(l *recordedLink) { l.TxQueueLen = txQLen })
+}
+
+func (r *planRecorder) SetLinkOffload(name string, offload netlink.Offload, on bool) error {
+	return r.change(name, func(l *recordedLink) {
+		if l.Offloads == nil {
+			l.Offloads = make(map[string]bool)
+		}
+		l.Offloads[offload.String()] = on
+	})
+}
+
+func (r *planRecorder) SetLinkMaster(name, master string) error {
+	return r.change(name, func(l *recordedLink) { l.Master = master })
+}
+
+func (r *planRecorder) SetLinkNetNs(name string, fd uintptr) error {
+	if int(fd) >= len(r.namespaces) {
+		return errors.Errorf("unknown network namespace %d", fd)
+	}
+	return r.change(name, func(l *recordedLink) { l.Netns = r.namespaces[fd] })
+}
+
+func (r *planRecorder) SetLinkAddress(name string, hwAddress net.HardwareAddr) error {
+	return r.change(name, func(l *recordedLink) {
+		l.mac = hwAddress
+		l.MAC = hwAddress.String()
+	})
+}
+
+func (r *planRecorder) SetLinkPromisc(name string, on bool) error {
+	return r.change(name, func(l *recordedLink) { l.Promisc = on })
+}
+
+func (r *planRecorder) SetLinkHairpin(name string, on bool) error {
+	return r.change(name, func(l *recordedLink) { l.Hairpin = on })
+}
+
+func (r *planRecorder) SetOrRemoveLinkAddress(linkInfo netlink.LinkInfo, mode, _ int) error {
+	if mode == netlink.ADD {
+		r.plan.Neighbors = append(r.plan.Neighbors, PlannedNeighbor{
+			Link:  linkInfo.Name,
+			Netns: r.netns,
+			IP:    linkInfo.IPAddr.String(),
+			MAC:   r.describe(linkInfo.MacAddress.String()),
+		})
+	}
+	return nil
+}
+
+func (r *planRecorder) AddIPAddress(ifName string, ipAddress net.IP, ipNet *net.IPNet) error {
+	address := net.IPNet{IP: ipAddress, Mask: ipNet.Mask}
+	r.plan.Addresses = append(r.plan.Addresses, PlannedAddress{Link: ifName, Netns: r.netns, Address: address.String()})
+	return nil
+}
+
+func (r *planRecorder) AddIPRoute(route *netlink.Route) error {
+	r.plan.Routes = append(r.plan.Routes, r.plannedRoute(route))
+	return nil
+}
+
+func (r *planRecorder) DeleteIPRoute(route *netlink.Route) error {
+	r.plan.DeletedRoutes = append(r.plan.DeletedRoutes, r.plannedRoute(route))
+	return nil
+}
+
+func (r *planRecorder) AddRule(rule *netlink.Rule) error {
+	r.plan.IPRules = append(r.plan.IPRules, PlannedIPRule{Mark: rule.Mark, Table: rule.Table, Netns: r.netns})
+	return nil
+}
+
+// DeleteRule is not recorded, the endpoint clients only delete rules when they clean up.
+func (r *planRecorder) DeleteRule(*netlink.Rule) error {
+	return nil
+}
+
+// ListRules returns the planned rules in the network namespace the endpoint client is in, the existing rules are
+// assumed not to exist.
+func (r *planRecorder) ListRules(family int) ([]*netlink.Rule, error) {
+	var rules []*netlink.Rule
+	for _, rule := range r.plan.IPRules {
+		if rule.Netns == r.netns {
+			rules = append(rules, &netlink.Rule{Family: family, Mark: rule.Mark, Table: rule.Table})
+		}
+	}
+	return rules, nil
+}
+
+// DeleteIPAddress is not recorded, the endpoint clients only delete addresses when they clean up.
+func (r *planRecorder) DeleteIPAddress(string, net.IP, *net.IPNet) error {
+	return nil
+}
+
+// GetIPRoute finds no route, the existing routes are assumed not to exist.
+func (r *planRecorder) GetIPRoute(*netlink.Route) ([]*netlink.Route, error) {
+	return nil, nil
+}
+
+func (r *planRecorder) AddNeigh(neigh *netlink.Neigh) error {
+	r.plan.Neighbors = append(r.plan.Neighbors, PlannedNeighbor{
+		Link:  r.linkName(neigh.LinkIndex),
+		Netns: r.netns,
+		IP:    neigh.IP.String(),
+		MAC:   r.describe(neigh.HardwareAddr.String()),
+	})
+	return nil
+}
+
+// DeleteNeigh is not recorded, the endpoint clients only delete neighbor entries when they clean up.
+func (r *planRecorder) DeleteNeigh(*netlink.Neigh) error {
+	return nil
+}
+
+// ListNeigh finds no neighbor entry, the existing entries are assumed not to exist.
+func (r *planRecorder) ListNeigh(int, int) ([]*netlink.Neigh, error) {
+	return nil, nil
+}
+
+// The qdiscs and filters are not recorded, the shaping is planned from the bandwidth of the endpoint.
+
+func (r *planRecorder) AddQdisc(netlink.Qdisc) error {
+	return nil
+}
+
+func (r *planRecorder) DeleteQdisc(netlink.Qdisc) error {
+	return nil
+}
+
+func (r *planRecorder) AddRedirectFilter(*netlink.RedirectFilter) error {
+	return nil
+}
+
+// Subscribe fails, there are no link events in a plan.
+func (r *planRecorder) Subscribe(chan<- *netlink.Event, <-chan struct{}, ...int) error {
+	return errors.New("link events are not planned")
+}
+
+// The links are looked up among the host interfaces and the planned links.
+
+func (r *planRecorder) ListLinks() ([]netlink.Link, error) {
+	if r.netns == "" {
+		for i := range r.hostInterfaces {
+			r.findLink(r.hostInterfaces[i].Name)
+		}
+	}
+	var links []netlink.Link
+	for _, l := range r.links {
+		if l.Netns == r.netns && !l.deleted {
+			links = append(links, r.linkInfo(l))
+		}
+	}
+	return links, nil
+}
+
+func (r *planRecorder) GetLinkByName(name string) (netlink.Link, error) {
+	l := r.findLink(name)
+	if l == nil {
+		return nil, errors.Wrapf(unix.ENODEV, "failed to find link %s", name)
+	}
+	return r.linkInfo(l), nil
+}
+
+func (r *planRecorder) GetLinkByIndex(index int) (netlink.Link, error) {
+	name := r.linkName(index)
+	if name == "" {
+		return nil, errors.Wrapf(unix.ENODEV, "failed to find link %d", index)
+	}
+	return r.GetLinkByName(name)
+}
+
+func (r *planRecorder) linkInfo(l *recordedLink) *netlink.LinkInfo {
+	mtu := l.MTU
+	if mtu == 0 {
+		mtu = l.hostMTU
+	}
+	info := &netlink.LinkInfo{
+		Type:       l.Kind,
+		Name:       l.Name,
+		MTU:        uint(mtu),
+		TxQLen:     uint(l.TxQueueLen),
+		MacAddress: l.mac,
+		Index:      l.index,
+		NetNsID:    -1,
+	}
+	if l.Master != "" {
+		if master := r.findLink(l.Master); master != nil {
+			info.MasterIndex = master.index
+		}
+	}
+	return info
+}
+
+// The network interfaces are looked up among the host interfaces and the planned links.
+
+func (r *planRecorder) GetNetworkInterfaceByName(name string) (*net.Interface, error) {
+	l := r.findLink(name)
+	if l == nil {
+		return nil, errors.Wrapf(netio.ErrInterfaceNotFound, "%s", name)
+	}
+	mtu := l.MTU
+	if mtu == 0 {
+		mtu = l.hostMTU
+	}
+	return &net.Interface{Index: l.index, MTU: mtu, Name: l.Name, HardwareAddr: l.mac}, nil
+}
+
+func (r *planRecorder) GetNetworkInterfaceAddrs(*net.Interface) ([]net.Addr, error) {
+	return nil, nil
+}
+
+func (r *planRecorder) GetNetworkInterfaceByMac(mac net.HardwareAddr) (*net.Interface, error) {
+	for i := range r.hostInterfaces {
+		if bytes.Equal(r.hostInterfaces[i].HardwareAddr, mac) {
+			return &r.hostInterfaces[i], nil
+		}
+	}
+	return nil, netio.ErrInterfaceNotFound
+}
+
+// The commands of the endpoint client are recorded as sysctls, ebtables rules or other commands.
+
+func (r *planRecorder) execute(cmd string) (string, error) {
+	if strings.HasPrefix(cmd, "ebtables ") {
+		// listing the rules finds none
+		if !strings.Contains(cmd, " -L ") {
+			r.plan.EbtablesRules = append(r.plan.EbtablesRules, r.inNetns(r.describe(cmd)))
+		}
+		return "", nil
+	}
+	if m := sysctlCmdRegex.FindStringSubmatch(cmd); m != nil {
+		r.plan.Sysctls = append(r.plan.Sysctls, PlannedSysctl{Key: m[1], Value: m[2], Netns: r.netns})
+	} else if m := procSysCmdRegex.FindStringSubmatch(cmd); m != nil {
+		r.plan.Sysctls = append(r.plan.Sysctls, PlannedSysctl{Key: strings.ReplaceAll(m[2], "/", "."), Value: m[1], Netns: r.netns})
+	} else {
+		r.plan.Commands = append(r.plan.Commands, r.inNetns(r.describe(cmd)))
+	}
+	return "", nil
+}
+
+// planExec stands in for the exec client of the endpoint client, the commands are recorded by the planRecorder.
+type planExec struct {
+	r *planRecorder
+}
+
+func (e *planExec) ExecuteCommand(cmd string) (string, error) {
+	return e.r.execute(cmd)
+}
+
+// GetLastRebootTime returns the zero time, the host is never considered rebooted while planning.
+func (e *planExec) GetLastRebootTime() (time.Time, error) {
+	return time.Time{}, nil
+}
+
+func (e *planExec) ClearNetworkConfiguration() (bool, error) {
+	return false, nil
+}
+
+func (e *planExec) ExecutePowershellCommand(cmd string) (string, error) {
+	return "", errors.Errorf("powershell is not planned: %s", cmd)
+}
+
+func (e *planExec) ExecutePowershellCommandWithContext(_ context.Context, cmd string) (string, error) {
+	return e.ExecutePowershellCommand(cmd)
+}
+
+func (e *planExec) KillProcessByName(string) error {
+	return nil
+}
+
+// The network namespaces are made up, only their paths are tracked.
+
+func (r *planRecorder) OpenNamespace(nsPath string) (NamespaceInterface, error) {
+	return &plannedNamespace{r: r, path: nsPath, fd: r.namespaceFd(nsPath)}, nil
+}
+
+func (r *planRecorder) GetCurrentThreadNamespace() (NamespaceInterface, error) {
+	return &plannedNamespace{r: r, path: r.netns, fd: r.namespaceFd(r.netns)}, nil
+}
+
+func (r *planRecorder) Get() (int, error) {
+	return r.namespaceFd(r.netns), nil
+}
+
+func (r *planRecorder) GetFromName(name string) (int, error) {
+	path := netnsDir + name
+	if r.deletedNamespaces[path] {
+		return 0, errors.Wrapf(os.ErrNotExist, "network namespace %s", name)
+	}
+	if !r.isPlannedNamespace(path) {
+		if _, err := os.Stat(path); err != nil {
+			return 0, errors.Wrapf(err, "network namespace %s", name)
+		}
+	}
+	return r.namespaceFd(path), nil
+}
+
+func (r *planRecorder) Set(fd int) error {
+	if fd >= len(r.namespaces) {
+		return errors.Errorf("unknown network namespace %d", fd)
+	}
+	r.netns = r.namespaces[fd]
+	return nil
+}
+
+func (r *planRecorder) NewNamed(name string) (int, error) {
+	path := netnsDir + name
+	delete(r.deletedNamespaces, path)
+	r.plan.Namespaces = append(r.plan.Namespaces, path)
+	r.netns = path
+	return r.namespaceFd(path), nil
+}
+
+func (r *planRecorder) DeleteNamed(name string) error {
+	path := netnsDir + name
+	r.deletedNamespaces[path] = true
+	for i, ns := range r.plan.Namespaces {
+		if ns == path {
+			r.plan.Namespaces = append(r.plan.Namespaces[:i], r.plan.Namespaces[i+1:]...)
+			return nil
+		}
+	}
+	r.plan.Notes = append(r.plan.Notes, fmt.Sprintf("the existing network namespace %s is deleted", name))
+	return nil
+}
+
+func (r *planRecorder) IsNamespaceEqual(fd1, fd2 int) bool {
+	return fd1 == fd2
+}
+
+func (r *planRecorder) NamespaceUniqueID(fd int) string {
+	if fd >= len(r.namespaces) {
+		return ""
+	}
+	return r.namespaces[fd]
+}
+
+// The iptables rules of the endpoint client are recorded as the commands adding them.
+
+func (r *planRecorder) InsertIptableRule(version, tableName, chainName, match, target string) error {
+	r.recordIPTables(r.iptablesCommands.GetInsertIptableRuleCmd(version, tableName, chainName, match, target))
+	return nil
+}
+
+func (r *planRecorder) AppendIptableRule(version, tableName, chainName, match, target string) error {
+	r.recordIPTables(r.iptablesCommands.GetAppendIptableRuleCmd(version, tableName, chainName, match, target))
+	return nil
+}
+
+func (r *planRecorder) DeleteIptableRule(version, tableName, chainName, match, target string) error {
+	r.recordIPTables(iptables.IPTableEntry{Version: version, Params: fmt.Sprintf("-t %s -D %s %s -j %s", tableName, chainName, match, target)})
+	return nil
+}
+
+func (r *planRecorder) CreateChain(version, tableName, chainName string) error {
+	r.recordIPTables(r.iptablesCommands.GetCreateChainCmd(version, tableName, chainName))
+	return nil
+}
+
+func (r *planRecorder) RunCmd(version, params string) error {
+	r.recordIPTables(iptables.IPTableEntry{Version: version, Params: params})
+	return nil
+}
+
+func (r *planRecorder) RuleExists(string, string, string, string, string) bool {
+	return false
+}
+
+func (r *planRecorder) recordIPTables(cmd iptables.IPTableEntry) {
+	binary := "iptables"
+	if cmd.Version == iptables.V6 {
+		binary = "ip6tables"
+	}
+	r.plan.IPTablesRules = append(r.plan.IPTablesRules, r.inNetns(binary+" "+strings.Join(strings.Fields(cmd.Params), " ")))
+}
+
+// plannedNamespace is a network namespace of the plan, which the endpoint client enters and exits.
+type plannedNamespace struct {
+	r    *planRecorder
+	path string
+	fd   int
+	prev string
+}
+
+func (ns *plannedNamespace) GetFd() uintptr {
+	return uintptr(ns.fd)
+}
+
+func (ns *plannedNamespace) GetName() string {
+	return ns.path
+}
+
+func (ns *plannedNamespace) Enter() error {
+	ns.prev = ns.r.netns
+	ns.r.netns = ns.path
+	return nil
+}
+
+func (ns *plannedNamespace) Exit() error {
+	ns.r.netns = ns.prev
+	return nil
+}
+
+func (ns *plannedNamespace) Close() error {
+	return nil
+}
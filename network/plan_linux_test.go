@@ -0,0 +1,189 @@
+//go:build linux
+// +build linux
+
+package network
+
+import (
+	"net"
+	"testing"
+
+	"github.com/Azure/azure-container-networking/cns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	planHostMac      = net.HardwareAddr{0xaa, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa}
+	planSecondaryMac = net.HardwareAddr{0xbb, 0xbb, 0xbb, 0xbb, 0xbb, 0xbb}
+)
+
+func planHostInterfaces() ([]net.Interface, error) {
+	return []net.Interface{
+		{Index: 1, MTU: 65536, Name: "lo"},
+		{Index: 2, MTU: 1500, Name: "eth0", HardwareAddr: planHostMac},
+		{Index: 3, MTU: 1500, Name: "eth1", HardwareAddr: planSecondaryMac},
+	}, nil
+}
+
+// withoutAcceptRA drops the sysctl disabling IPv6 router advertisements, which is only planned when the host has IPv6.
+func withoutAcceptRA(sysctls []PlannedSysctl) []PlannedSysctl {
+	filtered := []PlannedSysctl{}
+	for _, sysctl := range sysctls {
+		if sysctl.Key != "net.ipv6.conf.eth0.accept_ra" {
+			filtered = append(filtered, sysctl)
+		}
+	}
+	return filtered
+}
+
+func TestPlanTransparentEndpoint(t *testing.T) {
+	epInfo := &EndpointInfo{
+		EndpointID:   "abcdef12-eth0",
+		ContainerID:  "abcdef12",
+		Mode:         opModeTransparent,
+		MasterIfName: "eth0",
+		IfName:       "eth0",
+		NetNsPath:    "/var/run/netns/pod",
+		NICType:      cns.InfraNIC,
+		MTU:          1400,
+		IPAddresses:  []net.IPNet{{IP: net.ParseIP("10.240.0.5"), Mask: net.CIDRMask(16, 32)}},
+		PortMappings: []PortMapping{{HostPort: 8080, ContainerPort: 80, Protocol: "tcp"}},
+		EgressSNAT:   &EgressSNAT{Mode: cns.EgressSNATNone},
+		Tuning:       &Tuning{Sysctls: map[string]string{"net.ipv4.tcp_keepalive_time": "600", "net.core.somaxconn": "1024"}},
+	}
+
+	plan, err := planEndpoint(epInfo, planHostInterfaces)
+	require.NoError(t, err)
+	assert.Equal(t, endpointClientTransparent, plan.EndpointClient)
+	assert.Equal(t, []PlannedLink{
+		{Name: "azvabcdef1", Kind: "veth", Peer: "azvabcdef1-2", MTU: 1400},
+		{Name: "eth0", Kind: "veth", Peer: "azvabcdef1", Netns: "/var/run/netns/pod", RenamedFrom: "azvabcdef1-2", MTU: 1400},
+	}, plan.Links)
+	assert.Equal(t, []PlannedAddress{{Link: "eth0", Netns: "/var/run/netns/pod", Address: "10.240.0.5/16"}}, plan.Addresses)
+	assert.Equal(t, []PlannedRoute{
+		{Dst: "10.240.0.5/32", Link: "azvabcdef1"},
+		{Dst: "169.254.1.1/32", Link: "eth0", Netns: "/var/run/netns/pod", Scope: "link"},
+		{Dst: "0.0.0.0/0", Gw: "169.254.1.1", Link: "eth0", Netns: "/var/run/netns/pod"},
+	}, plan.Routes)
+	assert.Equal(t, []PlannedRoute{{Dst: "10.240.0.0/16", Link: "eth0", Netns: "/var/run/netns/pod", Scope: "link"}}, plan.DeletedRoutes)
+	assert.Equal(t, []PlannedNeighbor{{Link: "eth0", Netns: "/var/run/netns/pod", IP: "169.254.1.1", MAC: planHostMac.String()}}, plan.Neighbors)
+	assert.Equal(t, []PlannedSysctl{
+		{Key: "net.ipv4.conf.azvabcdef1.proxy_arp", Value: "1"},
+		{Key: "net.core.somaxconn", Value: "1024", Netns: "/var/run/netns/pod"},
+		{Key: "net.ipv4.tcp_keepalive_time", Value: "600", Netns: "/var/run/netns/pod"},
+	}, withoutAcceptRA(plan.Sysctls))
+	assert.Contains(t, plan.IPTablesRules, "iptables -t nat -A AZURECNIHOSTPORT -p tcp --dport 8080 -m comment --comment azure-cni-hostport:abcdef12 -j DNAT --to-destination 10.240.0.5:80")
+	assert.Contains(t, plan.IPTablesRules, "iptables -t nat -N AZURECNIEGRESS")
+	assert.Contains(t, plan.IPTablesRules, "iptables -t nat -I POSTROUTING 1 -m conntrack ! --ctstate DNAT -j AZURECNIEGRESS")
+	assert.Contains(t, plan.IPTablesRules, "iptables -t nat -A AZURECNIEGRESS -s 10.240.0.5 -m comment --comment azure-cni-egress:abcdef12 -j ACCEPT")
+	assert.Empty(t, plan.EbtablesRules)
+	assert.Empty(t, plan.Namespaces)
+	assert.Empty(t, plan.Notes)
+
+	// the endpoint info is left as it is
+	assert.Empty(t, epInfo.Routes)
+}
+
+func TestPlanBridgeEndpoint(t *testing.T) {
+	epInfo := &EndpointInfo{
+		EndpointID:   "abcdef12-eth0",
+		Mode:         opModeTunnel,
+		MasterIfName: "eth0",
+		BridgeName:   "azure0",
+		IfName:       "eth0",
+		NetNsPath:    "/var/run/netns/pod",
+		NICType:      cns.InfraNIC,
+		IPAddresses:  []net.IPNet{{IP: net.ParseIP("10.240.0.5"), Mask: net.CIDRMask(16, 32)}},
+	}
+
+	plan, err := planEndpoint(epInfo, planHostInterfaces)
+	require.NoError(t, err)
+	assert.Equal(t, endpointClientBridge, plan.EndpointClient)
+	assert.Equal(t, []PlannedLink{
+		{Name: "azure0", Kind: "bridge"},
+		{Name: "eth0", Master: "azure0"},
+		{Name: "azvabcdef1", Kind: "veth", Peer: "azvabcdef1-2", Master: "azure0", Hairpin: true},
+		{Name: "eth0", Kind: "veth", Peer: "azvabcdef1", Netns: "/var/run/netns/pod", RenamedFrom: "azvabcdef1-2"},
+	}, plan.Links)
+	assert.Equal(t, []PlannedAddress{{Link: "eth0", Netns: "/var/run/netns/pod", Address: "10.240.0.5/16"}}, plan.Addresses)
+	assert.Equal(t, []string{
+		"ebtables -t nat -A PREROUTING -p ARP --arp-op Request --arp-ip-dst 10.240.0.5 -j arpreply --arpreply-mac " + virtualMacAddress + " --arpreply-target DROP",
+		"ebtables -t nat -A PREROUTING -p IPv4 -i eth0 --ip-dst 10.240.0.5 -j dnat --to-dst <mac of azvabcdef1-2> --dnat-target ACCEPT",
+	}, plan.EbtablesRules)
+	assert.Empty(t, plan.IPTablesRules)
+	assert.Len(t, plan.Notes, 1)
+
+	// the bridge of the network is not planned when it exists
+	plan, err = planEndpoint(epInfo, func() ([]net.Interface, error) {
+		ifs, _ := planHostInterfaces()
+		return append(ifs, net.Interface{Index: 4, MTU: 1500, Name: "azure0"}), nil
+	})
+	require.NoError(t, err)
+	assert.Len(t, plan.Links, 2)
+	assert.Empty(t, plan.Notes)
+
+	// the OVS endpoint client is only named
+	epInfo.Data = map[string]interface{}{VlanIDKey: 10}
+	plan, err = planEndpoint(epInfo, planHostInterfaces)
+	require.NoError(t, err)
+	assert.Equal(t, endpointClientOVS, plan.EndpointClient)
+	assert.Empty(t, plan.Links)
+	assert.NotEmpty(t, plan.Notes)
+}
+
+func TestPlanTransparentVlanEndpoint(t *testing.T) {
+	epInfo := &EndpointInfo{
+		EndpointID:         "abcdef12-eth0",
+		ContainerID:        "abcdef12",
+		Mode:               opModeTransparentVlan,
+		MasterIfName:       "eth0",
+		IfName:             "eth0",
+		NetNsPath:          "/var/run/netns/pod",
+		NetworkContainerID: "nc1",
+		NICType:            cns.InfraNIC,
+		Data:               map[string]interface{}{VlanIDKey: 10, LocalIPKey: "169.254.0.4/17"},
+		IPAddresses:        []net.IPNet{{IP: net.ParseIP("10.240.0.5"), Mask: net.CIDRMask(16, 32)}},
+	}
+
+	plan, err := planEndpoint(epInfo, planHostInterfaces)
+	require.NoError(t, err)
+	assert.Equal(t, endpointClientTransparentVlan, plan.EndpointClient)
+	assert.Equal(t, []string{"/var/run/netns/az_ns_10"}, plan.Namespaces)
+	assert.Equal(t, []PlannedLink{
+		{Name: "eth0_10", Kind: "vlan", Parent: "eth0", VlanID: 10, Netns: "/var/run/netns/az_ns_10"},
+		{Name: "azvabcdef1", Kind: "veth", Peer: "azvabcdef1-2", Netns: "/var/run/netns/az_ns_10"},
+		{Name: "eth0", Kind: "veth", Peer: "azvabcdef1", Netns: "/var/run/netns/pod", RenamedFrom: "azvabcdef1-2"},
+	}, plan.Links)
+	assert.Contains(t, plan.Routes, PlannedRoute{Dst: "10.240.0.5/32", Link: "azvabcdef1", Netns: "/var/run/netns/az_ns_10"})
+	assert.Contains(t, plan.Routes, PlannedRoute{Dst: "0.0.0.0/0", Gw: "169.254.2.1", Link: "eth0_10", Netns: "/var/run/netns/az_ns_10", Table: 2})
+	assert.Equal(t, []PlannedIPRule{{Mark: 333, Table: 2, Netns: "/var/run/netns/az_ns_10"}}, plan.IPRules)
+	assert.Contains(t, plan.Sysctls, PlannedSysctl{Key: "net.ipv4.conf.eth0_10.rp_filter", Value: "0", Netns: "/var/run/netns/az_ns_10"})
+	assert.Contains(t, plan.IPTablesRules, "nsenter --net=/var/run/netns/az_ns_10 iptables -t mangle -I PREROUTING 1 -j MARK --set-mark 333")
+}
+
+func TestPlanSecondaryEndpoint(t *testing.T) {
+	epInfo := &EndpointInfo{
+		EndpointID:   "abcdef12-eth1",
+		ContainerID:  "abcdef12",
+		Mode:         opModeTransparent,
+		MasterIfName: "eth1",
+		IfName:       "eth1",
+		NetNsPath:    "/var/run/netns/pod",
+		NICType:      cns.DelegatedVMNIC,
+		MacAddress:   planSecondaryMac,
+		IPAddresses:  []net.IPNet{{IP: net.ParseIP("10.241.0.5"), Mask: net.CIDRMask(16, 32)}},
+		Routes:       []RouteInfo{{Dst: net.IPNet{IP: net.ParseIP("10.241.0.0"), Mask: net.CIDRMask(16, 32)}}},
+	}
+
+	plan, err := planEndpoint(epInfo, planHostInterfaces)
+	require.NoError(t, err)
+	assert.Equal(t, endpointClientSecondary, plan.EndpointClient)
+	assert.Equal(t, []PlannedLink{{Name: "eth1", Netns: "/var/run/netns/pod"}}, plan.Links)
+	assert.Equal(t, []PlannedAddress{{Link: "eth1", Netns: "/var/run/netns/pod", Address: "10.241.0.5/16"}}, plan.Addresses)
+	assert.Equal(t, []PlannedRoute{{Dst: "10.241.0.0/16", Link: "eth1", Netns: "/var/run/netns/pod", Scope: "link"}}, plan.Routes)
+
+	// the secondary interface has to be on the host
+	epInfo.MacAddress = net.HardwareAddr{0xcc, 0xcc, 0xcc, 0xcc, 0xcc, 0xcc}
+	_, err = planEndpoint(epInfo, planHostInterfaces)
+	require.Error(t, err)
+}
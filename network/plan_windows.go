@@ -0,0 +1,11 @@
+package network
+
+// PlanEndpoint returns the endpoint client creating the endpoint. Windows endpoints are created by HNS from the
+// endpoint policies, so their steps are not planned.
+func PlanEndpoint(epInfo *EndpointInfo) (*EndpointPlan, error) {
+	return &EndpointPlan{
+		EndpointID:     epInfo.EndpointID,
+		EndpointClient: "HNS",
+		Notes:          []string{"the endpoint is created by HNS from the endpoint policies, its steps are not planned"},
+	}, nil
+}
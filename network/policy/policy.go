@@ -13,6 +13,7 @@ const (
 	ACLPolicy         CNIPolicyType = "ACL"
 	L4WFPProxyPolicy  CNIPolicyType = "L4WFPPROXY"
 	LoopbackDSRPolicy CNIPolicyType = "LoopbackDSR"
+	TuningPolicy      CNIPolicyType = "Tuning"
 )
 
 type CNIPolicyType string
@@ -22,6 +23,15 @@ type Policy struct {
 	Data json.RawMessage
 }
 
+// KVPairTuning tunes the network namespace of the container, on linux only. Sysctls are netns-scoped sysctls by key,
+// as passed to sysctl -w, and the link settings apply to the container interface.
+type KVPairTuning struct {
+	Type       CNIPolicyType     `json:"Type"`
+	Sysctls    map[string]string `json:"Sysctls"`
+	Promisc    bool              `json:"Promisc"`
+	TxQueueLen int               `json:"TxQueueLen"`
+}
+
 // NATInfo contains information about NAT rules
 type NATInfo struct {
 	Destinations []string
@@ -0,0 +1,141 @@
+package network
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+
+	"github.com/Azure/azure-container-networking/iptables"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// hostPortJumpMatch sends traffic to local addresses, from other hosts or this one, to the host port chain.
+const hostPortJumpMatch = "-m addrtype --dst-type LOCAL"
+
+type iptablesRule struct {
+	version string
+	table   string
+	chain   string
+	match   string
+	target  string
+}
+
+// portMappingRules returns the nat rules forwarding the host ports to the endpoint address of the same IP family, and
+// masquerading the forwarded traffic. When masqueradeAll is false only traffic from the endpoint to its own host ports
+// is masqueraded, so the replies to the hairpinned connection return through the host, otherwise all of it is, for
+// endpoints reached through an interface other than their default route.
+func portMappingRules(containerID string, mappings []PortMapping, addresses []net.IPNet, masqueradeAll bool) ([]iptablesRule, error) {
+	var rules []iptablesRule
+	comment := fmt.Sprintf("-m comment --comment azure-cni-hostport:%s", containerID)
+	for _, m := range mappings {
+		protocol := m.Protocol
+		if protocol == "" {
+			protocol = iptables.TCP
+		}
+		var hostIP netip.Addr
+		if m.HostIP != "" {
+			var err error
+			if hostIP, err = netip.ParseAddr(m.HostIP); err != nil {
+				return nil, errors.Wrapf(err, "invalid host IP of port mapping %d", m.HostPort)
+			}
+		}
+		for _, ipAddr := range addresses {
+			podIP, ok := netip.AddrFromSlice(ipAddr.IP)
+			if !ok {
+				continue
+			}
+			podIP = podIP.Unmap()
+			if hostIP.IsValid() && hostIP.Is4() != podIP.Is4() {
+				continue
+			}
+			version := iptables.V4
+			destination := fmt.Sprintf("%s:%d", podIP, m.ContainerPort)
+			if podIP.Is6() {
+				version = iptables.V6
+				destination = fmt.Sprintf("[%s]:%d", podIP, m.ContainerPort)
+			}
+
+			dnatMatch := fmt.Sprintf("-p %s --dport %d", protocol, m.HostPort)
+			if hostIP.IsValid() && !hostIP.IsUnspecified() {
+				dnatMatch += " -d " + hostIP.String()
+			}
+			rules = append(rules, iptablesRule{
+				version: version,
+				table:   iptables.Nat,
+				chain:   iptables.CNIHostPortChain,
+				match:   dnatMatch + " " + comment,
+				target:  iptables.Dnat + " --to-destination " + destination,
+			})
+
+			masqMatch := fmt.Sprintf("-d %s -p %s --dport %d", podIP, protocol, m.ContainerPort)
+			if !masqueradeAll {
+				masqMatch = fmt.Sprintf("-s %s ", podIP) + masqMatch
+			}
+			rules = append(rules, iptablesRule{
+				version: version,
+				table:   iptables.Nat,
+				chain:   iptables.Postrouting,
+				match:   masqMatch + " -m conntrack --ctstate DNAT " + comment,
+				target:  iptables.Masquerade,
+			})
+		}
+	}
+	return rules, nil
+}
+
+// addPortMappings programs the rules forwarding the host ports of the mappings to the endpoint addresses.
+func addPortMappings(iptc ipTablesClient, containerID string, mappings []PortMapping, addresses []net.IPNet, masqueradeAll bool) error {
+	if len(mappings) == 0 {
+		return nil
+	}
+	rules, err := portMappingRules(containerID, mappings, addresses, masqueradeAll)
+	if err != nil {
+		return err
+	}
+
+	chains := map[string]bool{}
+	for _, rule := range rules {
+		if !chains[rule.version] {
+			if err := iptc.CreateChain(rule.version, iptables.Nat, iptables.CNIHostPortChain); err != nil {
+				return errors.Wrap(err, "failed to create host port chain")
+			}
+			for _, chain := range []string{iptables.Prerouting, iptables.Output} {
+				if err := iptc.AppendIptableRule(rule.version, iptables.Nat, chain, hostPortJumpMatch, iptables.CNIHostPortChain); err != nil {
+					return errors.Wrapf(err, "failed to jump to host port chain from %s", chain)
+				}
+			}
+			chains[rule.version] = true
+		}
+
+		logger.Info("Adding host port rule", zap.String("chain", rule.chain), zap.String("match", rule.match), zap.String("target", rule.target))
+		// the masquerade rules go first, ahead of rules which return from the chain for traffic staying in the vnet.
+		if rule.chain == iptables.Postrouting {
+			err = iptc.InsertIptableRule(rule.version, rule.table, rule.chain, rule.match, rule.target)
+		} else {
+			err = iptc.AppendIptableRule(rule.version, rule.table, rule.chain, rule.match, rule.target)
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to add host port rule %s", rule.match)
+		}
+	}
+	return nil
+}
+
+// deletePortMappings removes the rules added by addPortMappings, logging failures.
+func deletePortMappings(iptc ipTablesClient, containerID string, mappings []PortMapping, addresses []net.IPNet, masqueradeAll bool) {
+	if len(mappings) == 0 {
+		return
+	}
+	rules, err := portMappingRules(containerID, mappings, addresses, masqueradeAll)
+	if err != nil {
+		logger.Error("Failed to build host port rules to delete", zap.Error(err))
+		return
+	}
+	for _, rule := range rules {
+		logger.Info("Deleting host port rule", zap.String("chain", rule.chain), zap.String("match", rule.match), zap.String("target", rule.target))
+		if err := iptc.DeleteIptableRule(rule.version, rule.table, rule.chain, rule.match, rule.target); err != nil {
+			logger.Error("Failed to delete host port rule", zap.String("match", rule.match), zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,220 @@
+//go:build linux
+// +build linux
+
+package network
+
+import (
+	"net"
+	"testing"
+
+	"github.com/Azure/azure-container-networking/cns"
+	"github.com/Azure/azure-container-networking/cns/restserver"
+	"github.com/Azure/azure-container-networking/iptables"
+	"github.com/Azure/azure-container-networking/netio"
+	"github.com/Azure/azure-container-networking/netlink"
+	"github.com/Azure/azure-container-networking/platform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingIPTables keeps the rules programmed through it.
+type recordingIPTables struct {
+	chains []string
+	rules  []iptablesRule
+}
+
+func (r *recordingIPTables) InsertIptableRule(version, tableName, chainName, match, target string) error {
+	return r.AppendIptableRule(version, tableName, chainName, match, target)
+}
+
+func (r *recordingIPTables) AppendIptableRule(version, tableName, chainName, match, target string) error {
+	rule := iptablesRule{version: version, table: tableName, chain: chainName, match: match, target: target}
+	for _, existing := range r.rules {
+		if existing == rule {
+			return nil
+		}
+	}
+	r.rules = append(r.rules, rule)
+	return nil
+}
+
+func (r *recordingIPTables) DeleteIptableRule(version, tableName, chainName, match, target string) error {
+	rule := iptablesRule{version: version, table: tableName, chain: chainName, match: match, target: target}
+	for i := range r.rules {
+		if r.rules[i] == rule {
+			r.rules = append(r.rules[:i], r.rules[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (r *recordingIPTables) CreateChain(version, tableName, chainName string) error {
+	r.chains = append(r.chains, version+"/"+tableName+"/"+chainName)
+	return nil
+}
+
+func (r *recordingIPTables) RunCmd(_, _ string) error {
+	return nil
+}
+
+func (r *recordingIPTables) RuleExists(version, tableName, chainName, match, target string) bool {
+	rule := iptablesRule{version: version, table: tableName, chain: chainName, match: match, target: target}
+	for _, existing := range r.rules {
+		if existing == rule {
+			return true
+		}
+	}
+	return false
+}
+
+func dualStackAddresses() []net.IPNet {
+	return []net.IPNet{
+		{IP: net.ParseIP("10.240.0.5"), Mask: net.CIDRMask(16, 32)},
+		{IP: net.ParseIP("fd00::5"), Mask: net.CIDRMask(64, 128)},
+	}
+}
+
+func TestPortMappingRules(t *testing.T) {
+	mappings := []PortMapping{
+		{HostPort: 8080, ContainerPort: 80, Protocol: "tcp"},
+		{HostPort: 5353, ContainerPort: 53, Protocol: "udp", HostIP: "10.0.0.4"},
+	}
+	rules, err := portMappingRules("abc", mappings, dualStackAddresses(), false)
+	require.NoError(t, err)
+
+	const comment = " -m comment --comment azure-cni-hostport:abc"
+	assert.Equal(t, []iptablesRule{
+		{iptables.V4, iptables.Nat, iptables.CNIHostPortChain, "-p tcp --dport 8080" + comment, "DNAT --to-destination 10.240.0.5:80"},
+		{iptables.V4, iptables.Nat, iptables.Postrouting, "-s 10.240.0.5 -d 10.240.0.5 -p tcp --dport 80 -m conntrack --ctstate DNAT" + comment, "MASQUERADE"},
+		{iptables.V6, iptables.Nat, iptables.CNIHostPortChain, "-p tcp --dport 8080" + comment, "DNAT --to-destination [fd00::5]:80"},
+		{iptables.V6, iptables.Nat, iptables.Postrouting, "-s fd00::5 -d fd00::5 -p tcp --dport 80 -m conntrack --ctstate DNAT" + comment, "MASQUERADE"},
+		// the IPv4 host IP only forwards to the IPv4 address
+		{iptables.V4, iptables.Nat, iptables.CNIHostPortChain, "-p udp --dport 5353 -d 10.0.0.4" + comment, "DNAT --to-destination 10.240.0.5:53"},
+		{iptables.V4, iptables.Nat, iptables.Postrouting, "-s 10.240.0.5 -d 10.240.0.5 -p udp --dport 53 -m conntrack --ctstate DNAT" + comment, "MASQUERADE"},
+	}, rules)
+
+	rules, err = portMappingRules("abc", mappings[:1], dualStackAddresses()[:1], true)
+	require.NoError(t, err)
+	require.Len(t, rules, 2)
+	assert.Equal(t, "-d 10.240.0.5 -p tcp --dport 80 -m conntrack --ctstate DNAT"+comment, rules[1].match)
+
+	_, err = portMappingRules("abc", []PortMapping{{HostPort: 80, ContainerPort: 80, HostIP: "node"}}, dualStackAddresses(), false)
+	require.Error(t, err)
+}
+
+func TestAddDeletePortMappings(t *testing.T) {
+	iptc := &recordingIPTables{}
+	mappings := []PortMapping{{HostPort: 8080, ContainerPort: 80, Protocol: "tcp"}}
+
+	require.NoError(t, addPortMappings(iptc, "abc", nil, dualStackAddresses(), false))
+	assert.Empty(t, iptc.chains)
+
+	require.NoError(t, addPortMappings(iptc, "abc", mappings, dualStackAddresses(), false))
+	assert.Equal(t, []string{"4/nat/AZURECNIHOSTPORT", "6/nat/AZURECNIHOSTPORT"}, iptc.chains)
+	// jumps from PREROUTING and OUTPUT, a DNAT and a masquerade rule per IP family
+	assert.Len(t, iptc.rules, 8)
+
+	deletePortMappings(iptc, "abc", mappings, dualStackAddresses(), false)
+	// only the jumps to the shared chain are left
+	require.Len(t, iptc.rules, 4)
+	for _, rule := range iptc.rules {
+		assert.Equal(t, iptables.CNIHostPortChain, rule.target)
+	}
+}
+
+func TestTransparentVlanPortMappingAddresses(t *testing.T) {
+	mappings := []PortMapping{{HostPort: 8080, ContainerPort: 80, Protocol: "tcp"}}
+
+	client := &TransparentVlanEndpointClient{localIP: "169.254.128.5/17"}
+	_, err := client.portMappingAddresses(mappings)
+	require.ErrorContains(t, err, "require the SNAT endpoint")
+
+	client.enableSnatOnHost = true
+	addresses, err := client.portMappingAddresses(mappings)
+	require.NoError(t, err)
+	assert.Equal(t, []net.IPNet{{IP: net.ParseIP("169.254.128.5"), Mask: net.CIDRMask(17, 32)}}, addresses)
+
+	addresses, err = client.portMappingAddresses(nil)
+	require.NoError(t, err)
+	assert.Empty(t, addresses)
+}
+
+func TestDeleteEndpointStateRemovesHostConfig(t *testing.T) {
+	iptc := &recordingIPTables{}
+	nl := newRecordingNetlink()
+	mappings := []PortMapping{{HostPort: 8080, ContainerPort: 80, Protocol: "tcp"}}
+	egressSNAT := &EgressSNAT{Mode: cns.EgressSNATNodeIP, Except: []string{"10.240.0.0/16"}}
+	require.NoError(t, addPortMappings(iptc, "abc", mappings, dualStackAddresses(), false))
+	require.NoError(t, addEgressSNAT(iptc, "abc", egressSNAT, dualStackAddresses()))
+
+	// stateless CNI reads the mappings, egress snat and shaping back from the CNS endpoint state on delete
+	nm := &networkManager{iptablesClient: iptc, netlink: nl}
+	epInfo := &EndpointInfo{
+		EndpointID:   "abc",
+		ContainerID:  "abc",
+		IfName:       "eth0",
+		HostIfName:   "azv1",
+		IPAddresses:  dualStackAddresses(),
+		NICType:      cns.InfraNIC,
+		PortMappings: mappings,
+		Bandwidth:    &Bandwidth{EgressRate: 1000000, EgressBurst: 80000},
+		EgressSNAT:   egressSNAT,
+	}
+	require.NoError(t, nm.DeleteEndpointState("azure", epInfo))
+	// only the jumps to the shared chains are left
+	require.Len(t, iptc.rules, 6)
+	for _, rule := range iptc.rules {
+		assert.Contains(t, []string{iptables.CNIHostPortChain, iptables.CNIEgressChain}, rule.target)
+	}
+	assert.Equal(t, []string{"azv1", "azv1", "azb1"}, nl.deleted)
+}
+
+// vlanPortMappingClient is a mock endpoint client which programs the host ports as the transparent-vlan client does.
+type vlanPortMappingClient struct {
+	*MockEndpointClient
+	vlan *TransparentVlanEndpointClient
+}
+
+func (c *vlanPortMappingClient) AddEndpointRules(epInfo *EndpointInfo) error {
+	return c.vlan.addPortMappings(epInfo.ContainerID, epInfo.PortMappings)
+}
+
+func TestStatelessTransparentVlanPortMappings(t *testing.T) {
+	iptc := &recordingIPTables{}
+	const localIP = "169.254.128.5/17"
+	nw := &network{Id: "azure", Mode: opModeTransparentVlan, Endpoints: map[string]*endpoint{}}
+	epInfo := &EndpointInfo{
+		EndpointID:   "abc-eth0",
+		ContainerID:  "abc",
+		IfName:       eth0IfName,
+		NICType:      cns.InfraNIC,
+		IPAddresses:  dualStackAddresses()[:1],
+		PortMappings: []PortMapping{{HostPort: 8080, ContainerPort: 80, Protocol: "tcp"}},
+		Data:         map[string]interface{}{VlanIDKey: 100, LocalIPKey: localIP},
+	}
+	client := &vlanPortMappingClient{
+		MockEndpointClient: NewMockEndpointClient(nil),
+		vlan:               &TransparentVlanEndpointClient{localIP: localIP, enableSnatOnHost: true, iptablesClient: iptc},
+	}
+
+	// ADD forwards the host ports to the SNAT endpoint and records it with the endpoint
+	ep, err := nw.newEndpointImpl(nil, netlink.NewMockNetlink(false, ""), platform.NewMockExecClient(false),
+		netio.NewMockNetIO(false, 0), client, NewMockNamespaceClient(), iptc, epInfo)
+	require.NoError(t, err)
+	assert.Equal(t, localIP, ep.PortForwardAddress)
+	assert.True(t, ep.PortForwardMasqueradeAll)
+	require.Len(t, iptc.rules, 4)
+
+	// stateless DEL reads the forward address back from the CNS endpoint state and removes the same rules
+	state := restserver.EndpointInfo{IfnameToIPMap: generateCNSIPInfoMap([]*endpoint{ep})}
+	epInfos := cnsEndpointInfotoCNIEpInfos(state, "abc")
+	require.Len(t, epInfos, 1)
+	nm := &networkManager{iptablesClient: iptc, netlink: newRecordingNetlink()}
+	require.NoError(t, nm.DeleteEndpointState("azure", epInfos[0]))
+	// only the jumps to the shared chain are left
+	require.Len(t, iptc.rules, 2)
+	for _, rule := range iptc.rules {
+		assert.Equal(t, iptables.CNIHostPortChain, rule.target)
+	}
+}
@@ -74,7 +74,11 @@ func (client *SecondaryEndpointClient) AddEndpoints(epInfo *EndpointInfo) error
 	return nil
 }
 
-func (client *SecondaryEndpointClient) AddEndpointRules(_ *EndpointInfo) error {
+func (client *SecondaryEndpointClient) AddEndpointRules(epInfo *EndpointInfo) error {
+	if epInfo.EgressSNAT != nil {
+		logger.Warn("Egress snat is not supported on secondary interfaces, leaving the egress traffic as configured for the network",
+			zap.String("ifName", epInfo.IfName), zap.String("mode", epInfo.EgressSNAT.Mode))
+	}
 	return nil
 }
 
@@ -11,6 +11,7 @@ import (
 	"github.com/Azure/azure-container-networking/cni/log"
 	"github.com/Azure/azure-container-networking/ebtables"
 	"github.com/Azure/azure-container-networking/iptables"
+	"github.com/Azure/azure-container-networking/netio"
 	"github.com/Azure/azure-container-networking/netlink"
 	"github.com/Azure/azure-container-networking/network/networkutils"
 	"github.com/Azure/azure-container-networking/platform"
@@ -53,6 +54,7 @@ type Client struct {
 	SkipAddressesFromBlock []string
 	enableProxyArpOnBridge bool
 	netlink                netlink.NetlinkInterface
+	netioshim              netio.NetIOInterface
 	plClient               platform.ExecClient
 	ipTablesClient         ipTablesClient
 }
@@ -65,6 +67,7 @@ func NewSnatClient(hostIfName string,
 	skipAddressesFromBlock []string,
 	enableProxyArpOnBridge bool,
 	nl netlink.NetlinkInterface,
+	nioc netio.NetIOInterface,
 	plClient platform.ExecClient,
 	iptc ipTablesClient,
 ) Client {
@@ -76,6 +79,7 @@ func NewSnatClient(hostIfName string,
 		hostPrimaryMac:         hostPrimaryMac,
 		enableProxyArpOnBridge: enableProxyArpOnBridge,
 		netlink:                nl,
+		netioshim:              nioc,
 		plClient:               plClient,
 		ipTablesClient:         iptc,
 	}
@@ -223,7 +227,7 @@ func (client *Client) AllowInboundFromHostToNC() error {
 		return newErrorSnatClient(err.Error())
 	}
 
-	snatContainerVeth, _ := net.InterfaceByName(client.containerSnatVethName)
+	snatContainerVeth, _ := client.netioshim.GetNetworkInterfaceByName(client.containerSnatVethName)
 
 	// Add static arp entry for localIP to prevent arp going out of VM
 	logger.Info("Adding static arp entry for ip", zap.Any("containerIP", containerIP),
@@ -319,7 +323,7 @@ func (client *Client) AllowInboundFromNCToHost() error {
 		return err
 	}
 
-	snatContainerVeth, _ := net.InterfaceByName(client.containerSnatVethName)
+	snatContainerVeth, _ := client.netioshim.GetNetworkInterfaceByName(client.containerSnatVethName)
 
 	// Add static arp entry for localIP to prevent arp going out of VM
 	logger.Info("Adding static arp entry for ip", zap.Any("containerIP", containerIP), zap.String("HardwareAddr", snatContainerVeth.HardwareAddr.String()))
@@ -407,7 +411,7 @@ func (client *Client) setBridgeMac(hostPrimaryMac string) error {
 func (client *Client) DropArpForSnatBridgeApipaRange(snatBridgeIP, azSnatVethIfName string) error {
 	var err error
 	_, ipCidr, _ := net.ParseCIDR(snatBridgeIP)
-	if err = ebtables.SetArpDropRuleForIpCidr(ipCidr.String(), azSnatVethIfName); err != nil {
+	if err = ebtables.NewClient(client.plClient).SetArpDropRuleForIpCidr(ipCidr.String(), azSnatVethIfName); err != nil {
 		logger.Error("Error setting arp drop rule for snatbridge ip", zap.String("snatBridgeIP", snatBridgeIP))
 	}
 
@@ -416,7 +420,7 @@ func (client *Client) DropArpForSnatBridgeApipaRange(snatBridgeIP, azSnatVethIfN
 
 // This function creates linux bridge which will be used for outbound connectivity by NCs
 func (client *Client) createSnatBridge(snatBridgeIP, hostPrimaryMac string) error {
-	_, err := net.InterfaceByName(SnatBridgeName)
+	_, err := client.netioshim.GetNetworkInterfaceByName(SnatBridgeName)
 	if err == nil {
 		logger.Info("Snat Bridge already exists")
 	} else {
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/Azure/azure-container-networking/iptables"
+	"github.com/Azure/azure-container-networking/netio"
 	"github.com/Azure/azure-container-networking/netlink"
 )
 
@@ -26,6 +27,7 @@ func TestAllowInboundFromHostToNC(t *testing.T) {
 		localIP:               "169.254.0.4/16",
 		containerSnatVethName: anyInterface,
 		netlink:               nl,
+		netioshim:             &netio.NetIO{},
 		ipTablesClient:        iptc,
 	}
 
@@ -75,6 +77,7 @@ func TestAllowInboundFromNCToHost(t *testing.T) {
 		localIP:               "169.254.0.4/16",
 		containerSnatVethName: anyInterface,
 		netlink:               nl,
+		netioshim:             &netio.NetIO{},
 		ipTablesClient:        iptc,
 	}
 
@@ -4,6 +4,7 @@
 package network
 
 import (
+	"fmt"
 	"net"
 	"testing"
 
@@ -134,6 +135,51 @@ func TestTransAddEndpoints(t *testing.T) {
 	}
 }
 
+// linkSettingsNetlink keeps the MTUs and offloads set through it.
+type linkSettingsNetlink struct {
+	*netlink.MockNetlink
+	mtus     map[string]int
+	offloads []string
+}
+
+func (n *linkSettingsNetlink) SetLinkMTU(ifName string, mtu int) error {
+	n.mtus[ifName] = mtu
+	return nil
+}
+
+func (n *linkSettingsNetlink) SetLinkOffload(ifName string, offload netlink.Offload, on bool) error {
+	n.offloads = append(n.offloads, fmt.Sprintf("%s %s %t", ifName, offload, on))
+	return nil
+}
+
+func TestTransAddEndpointsLinkSettings(t *testing.T) {
+	nl := &linkSettingsNetlink{MockNetlink: netlink.NewMockNetlink(false, ""), mtus: map[string]int{}}
+	plc := platform.NewMockExecClient(false)
+	client := &TransparentEndpointClient{
+		hostPrimaryIfName: "eth0",
+		hostVethName:      "azvhost",
+		containerVethName: "azvcontainer",
+		netlink:           nl,
+		plClient:          plc,
+		netUtilsClient:    networkutils.NewNetworkUtils(nl, plc),
+		netioshim:         netio.NewMockNetIO(false, 0),
+	}
+	off := false
+	epInfo := &EndpointInfo{MTU: 9000, Offloads: &Offloads{TSO: &off, GRO: &off}}
+
+	// the offloads are set after an explicit MTU too
+	require.NoError(t, client.AddEndpoints(epInfo))
+	require.Equal(t, map[string]int{"azvhost": 9000, "azvcontainer": 9000}, nl.mtus)
+	require.Equal(t, []string{"azvhost tso false", "azvhost gro false", "azvcontainer tso false", "azvcontainer gro false"}, nl.offloads)
+
+	// a nil offload is left as it is
+	nl.offloads = nil
+	on := true
+	epInfo = &EndpointInfo{Offloads: &Offloads{GRO: &on}}
+	require.NoError(t, client.AddEndpoints(epInfo))
+	require.Equal(t, []string{"azvhost gro true", "azvcontainer gro true"}, nl.offloads)
+}
+
 func TestTransAddEndpointsRules(t *testing.T) {
 	nl := netlink.NewMockNetlink(false, "")
 	plc := platform.NewMockExecClient(false)
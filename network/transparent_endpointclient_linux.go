@@ -44,6 +44,7 @@ type TransparentEndpointClient struct {
 	netioshim         netio.NetIOInterface
 	plClient          platform.ExecClient
 	netUtilsClient    networkutils.NetworkUtils
+	iptablesClient    ipTablesClient
 }
 
 func NewTransparentEndpointClient(
@@ -54,6 +55,7 @@ func NewTransparentEndpointClient(
 	nl netlink.NetlinkInterface,
 	nioc netio.NetIOInterface,
 	plc platform.ExecClient,
+	iptc ipTablesClient,
 ) *TransparentEndpointClient {
 	client := &TransparentEndpointClient{
 		bridgeName:        extIf.BridgeName,
@@ -66,6 +68,7 @@ func NewTransparentEndpointClient(
 		netioshim:         nioc,
 		plClient:          plc,
 		netUtilsClient:    networkutils.NewNetworkUtils(nl, plc),
+		iptablesClient:    iptc,
 	}
 
 	return client
@@ -122,15 +125,26 @@ func (client *TransparentEndpointClient) AddEndpoints(epInfo *EndpointInfo) erro
 
 	client.hostVethMac = hostVethIf.HardwareAddr
 
-	logger.Info("Setting mtu on veth interface", zap.Int("MTU", primaryIf.MTU), zap.String("hostVethName", client.hostVethName))
-	if err := client.netlink.SetLinkMTU(client.hostVethName, primaryIf.MTU); err != nil {
-		logger.Error("Setting mtu failed for hostveth", zap.String("hostVethName", client.hostVethName),
-			zap.Error(err))
+	// An explicit MTU has to be applied, otherwise the veths follow the primary interface.
+	if epInfo.MTU != 0 {
+		if err = setLinksMTU(client.netlink, epInfo.MTU, client.hostVethName, client.containerVethName); err != nil {
+			return newErrorTransparentEndpointClient(err)
+		}
+	} else {
+		logger.Info("Setting mtu on veth interface", zap.Int("MTU", primaryIf.MTU), zap.String("hostVethName", client.hostVethName))
+		if err := client.netlink.SetLinkMTU(client.hostVethName, primaryIf.MTU); err != nil {
+			logger.Error("Setting mtu failed for hostveth", zap.String("hostVethName", client.hostVethName),
+				zap.Error(err))
+		}
+
+		if err := client.netlink.SetLinkMTU(client.containerVethName, primaryIf.MTU); err != nil {
+			logger.Error("Setting mtu failed for containerveth", zap.String("containerVethName", client.containerVethName),
+				zap.Error(err))
+		}
 	}
 
-	if err := client.netlink.SetLinkMTU(client.containerVethName, primaryIf.MTU); err != nil {
-		logger.Error("Setting mtu failed for containerveth", zap.String("containerVethName", client.containerVethName),
-			zap.Error(err))
+	if err = setLinksOffloads(client.netlink, epInfo.Offloads, client.hostVethName, client.containerVethName); err != nil {
+		return newErrorTransparentEndpointClient(err)
 	}
 
 	return nil
@@ -167,10 +181,26 @@ func (client *TransparentEndpointClient) AddEndpointRules(epInfo *EndpointInfo)
 		return err
 	}
 
+	if err := addPortMappings(client.iptablesClient, epInfo.ContainerID, epInfo.PortMappings, epInfo.IPAddresses, false); err != nil {
+		return newErrorTransparentEndpointClient(err)
+	}
+
+	if err := addEgressSNAT(client.iptablesClient, epInfo.ContainerID, epInfo.EgressSNAT, epInfo.IPAddresses); err != nil {
+		return newErrorTransparentEndpointClient(err)
+	}
+
+	if err := addBandwidth(client.netlink, client.hostVethName, epInfo.Bandwidth); err != nil {
+		return newErrorTransparentEndpointClient(err)
+	}
+
 	return nil
 }
 
 func (client *TransparentEndpointClient) DeleteEndpointRules(ep *endpoint) {
+	deletePortMappings(client.iptablesClient, ep.ContainerID, ep.PortMappings, ep.IPAddresses, false)
+	deleteEgressSNAT(client.iptablesClient, ep.ContainerID, ep.EgressSNAT, ep.IPAddresses)
+	deleteBandwidth(client.netlink, client.hostVethName, ep.Bandwidth)
+
 	// ip route del <podip> dev <hostveth>
 	// Deleting the route set up for routing the incoming packets to pod
 	for _, ipAddr := range ep.IPAddresses {
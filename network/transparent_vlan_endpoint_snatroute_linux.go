@@ -19,6 +19,7 @@ func (client *TransparentVlanEndpointClient) NewSnatClient(snatBridgeIP, localIP
 			epInfo.EndpointDNS.Servers,
 			true,
 			client.netlink,
+			client.netioshim,
 			client.plClient,
 			client.iptablesClient,
 		)
@@ -16,6 +16,7 @@ import (
 	"github.com/pkg/errors"
 	vishnetlink "github.com/vishvananda/netlink"
 	"go.uber.org/zap"
+	"golang.org/x/sys/unix"
 )
 
 const (
@@ -55,6 +56,7 @@ type TransparentVlanEndpointClient struct {
 	vnetNSFileDescriptor int
 
 	snatClient               snat.Client
+	localIP                  string // address of the SNAT endpoint in the container, with prefix
 	vlanID                   int
 	enableSnatOnHost         bool
 	allowInboundFromHostToNC bool
@@ -77,6 +79,7 @@ func NewTransparentVlanEndpointClient(
 	vlanid int,
 	localIP string,
 	nl netlink.NetlinkInterface,
+	nioc netio.NetIOInterface,
 	plc platform.ExecClient,
 	nsc NamespaceClientInterface,
 	iptc ipTablesClient,
@@ -92,13 +95,14 @@ func NewTransparentVlanEndpointClient(
 		containerVethName:        containerVethName,
 		vnetNSName:               vnetNSName,
 		vlanID:                   vlanid,
+		localIP:                  localIP,
 		enableSnatOnHost:         ep.EnableSnatOnHost,
 		allowInboundFromHostToNC: ep.AllowInboundFromHostToNC,
 		allowInboundFromNCToHost: ep.AllowInboundFromNCToHost,
 		enableSnatForDNS:         ep.EnableSnatForDns,
 		netnsClient:              netns.New(),
 		netlink:                  nl,
-		netioshim:                &netio.NetIO{},
+		netioshim:                nioc,
 		plClient:                 plc,
 		netUtilsClient:           networkutils.NewNetworkUtils(nl, plc),
 		nsClient:                 nsc,
@@ -249,17 +253,17 @@ func (client *TransparentVlanEndpointClient) PopulateVM(epInfo *EndpointInfo) er
 		if deleteNSIfNotNilErr != nil {
 			return errors.Wrap(deleteNSIfNotNilErr, "failed to get eth0 interface")
 		}
-		linkAttrs := vishnetlink.NewLinkAttrs()
-		linkAttrs.Name = client.vlanIfName
-		// Set the peer
-		linkAttrs.ParentIndex = eth0.Index
-		link := &vishnetlink.Vlan{
-			LinkAttrs: linkAttrs,
-			VlanId:    client.vlanID,
+		link := &netlink.VlanLink{
+			LinkInfo: netlink.LinkInfo{
+				Type:        netlink.LINK_TYPE_VLAN,
+				Name:        client.vlanIfName,
+				ParentIndex: eth0.Index,
+			},
+			VlanId: client.vlanID,
 		}
 		logger.Info("Attempting to create link in VM NS", zap.String("vlanIfName", client.vlanIfName))
 		// Create vlan veth
-		deleteNSIfNotNilErr = vishnetlink.LinkAdd(link)
+		deleteNSIfNotNilErr = client.netlink.AddLink(link)
 		if deleteNSIfNotNilErr != nil {
 			// ignore link already exists error
 			if !strings.Contains(deleteNSIfNotNilErr.Error(), "file exists") {
@@ -293,6 +297,11 @@ func (client *TransparentVlanEndpointClient) PopulateVM(epInfo *EndpointInfo) er
 		if deleteNSIfNotNilErr != nil {
 			return errors.Wrap(deleteNSIfNotNilErr, "failed to disable router advertisements for vlan vnet link")
 		}
+		// The vlan link is shared by the endpoints of the vnet, so it only takes the MTU of the endpoint creating it.
+		deleteNSIfNotNilErr = setLinksMTU(client.netlink, epInfo.MTU, client.vlanIfName)
+		if deleteNSIfNotNilErr != nil {
+			return errors.Wrap(deleteNSIfNotNilErr, "failed to set mtu of vlan vnet link")
+		}
 		// vlan veth was created successfully, so move the vlan veth you created
 		logger.Info("Move vlan link to vnet NS", zap.String("vlanIfName", client.vlanIfName), zap.Any("vnetNSFileDescriptor", uintptr(client.vnetNSFileDescriptor)))
 		deleteNSIfNotNilErr = client.setLinkNetNSAndConfirm(client.vlanIfName, uintptr(client.vnetNSFileDescriptor))
@@ -348,6 +357,18 @@ func (client *TransparentVlanEndpointClient) PopulateVM(epInfo *EndpointInfo) er
 		}
 		return errors.Wrap(err, "failed to disable RA on container veth, deleting")
 	}
+	if err = setLinksMTU(client.netlink, epInfo.MTU, client.vnetVethName, client.containerVethName); err != nil {
+		if delErr := client.netlink.DeleteLink(client.vnetVethName); delErr != nil {
+			logger.Error("Deleting vnet veth failed on addendpoint failure with", zap.Error(delErr))
+		}
+		return errors.Wrap(err, "failed to set mtu on veth pair, deleting")
+	}
+	if err = setLinksOffloads(client.netlink, epInfo.Offloads, client.vnetVethName, client.containerVethName); err != nil {
+		if delErr := client.netlink.DeleteLink(client.vnetVethName); delErr != nil {
+			logger.Error("Deleting vnet veth failed on addendpoint failure with", zap.Error(delErr))
+		}
+		return errors.Wrap(err, "failed to set offloads on veth pair, deleting")
+	}
 
 	if err = client.setLinkNetNSAndConfirm(client.vnetVethName, uintptr(client.vnetNSFileDescriptor)); err != nil {
 		if delErr := client.netlink.DeleteLink(client.vnetVethName); delErr != nil {
@@ -384,9 +405,16 @@ func (client *TransparentVlanEndpointClient) PopulateVnet(epInfo *EndpointInfo)
 }
 
 func (client *TransparentVlanEndpointClient) AddEndpointRules(epInfo *EndpointInfo) error {
+	if epInfo.EgressSNAT != nil {
+		logger.Warn("Egress snat is not supported in transparent vlan mode, leaving the egress traffic as configured for the network",
+			zap.String("containerID", epInfo.ContainerID), zap.String("mode", epInfo.EgressSNAT.Mode))
+	}
 	if err := client.AddSnatEndpointRules(); err != nil {
 		return errors.Wrap(err, "failed to add snat endpoint rules")
 	}
+	if err := client.addPortMappings(epInfo.ContainerID, epInfo.PortMappings); err != nil {
+		return errors.Wrap(err, "failed to add host port rules")
+	}
 	logger.Info("[transparent-vlan] Adding tunneling rules in vnet namespace")
 	err := ExecuteInNS(client.nsClient, client.vnetNSName, func() error {
 		return client.AddVnetRules(epInfo)
@@ -412,10 +440,12 @@ func (client *TransparentVlanEndpointClient) AddVnetRules(epInfo *EndpointInfo)
 	}
 
 	// Packets that are marked should go to the tunneling table
-	newRule := vishnetlink.NewRule()
-	newRule.Mark = tunnelingMark
-	newRule.Table = tunnelingTable
-	rules, err := vishnetlink.RuleList(vishnetlink.FAMILY_V4)
+	newRule := &netlink.Rule{
+		Family: unix.AF_INET,
+		Mark:   tunnelingMark,
+		Table:  tunnelingTable,
+	}
+	rules, err := client.netlink.ListRules(unix.AF_INET)
 	if err != nil {
 		return errors.Wrap(err, "unable to get existing ip rule list")
 	}
@@ -427,16 +457,48 @@ func (client *TransparentVlanEndpointClient) AddVnetRules(epInfo *EndpointInfo)
 		}
 	}
 	if !ruleExists {
-		if err := vishnetlink.RuleAdd(newRule); err != nil {
+		if err := client.netlink.AddRule(newRule); err != nil {
 			return errors.Wrap(err, "failed to add rule that forwards packet with mark to tunneling routing table")
 		}
 	}
 
+	// The container traffic is shaped on its veth in the vnet namespace
+	if err := addBandwidth(client.netlink, client.vnetVethName, epInfo.Bandwidth); err != nil {
+		return errors.Wrap(err, "failed to shape container traffic")
+	}
+
 	return nil
 }
 
 func (client *TransparentVlanEndpointClient) DeleteEndpointRules(ep *endpoint) {
 	client.DeleteSnatEndpointRules()
+	if addresses, err := client.portMappingAddresses(ep.PortMappings); err == nil {
+		deletePortMappings(client.iptablesClient, ep.ContainerID, ep.PortMappings, addresses, true)
+	}
+}
+
+// The container is only reachable from the VM namespace through its SNAT endpoint, so host ports are forwarded to the
+// SNAT endpoint address and masqueraded to the SNAT bridge, which the container routes its replies through.
+func (client *TransparentVlanEndpointClient) portMappingAddresses(mappings []PortMapping) ([]net.IPNet, error) {
+	if len(mappings) == 0 {
+		return nil, nil
+	}
+	if !client.isSnatEnabled() || client.localIP == "" {
+		return nil, errors.New("host ports require the SNAT endpoint in transparent vlan mode")
+	}
+	ip, ipNet, err := net.ParseCIDR(client.localIP)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse local IP %s", client.localIP)
+	}
+	return []net.IPNet{{IP: ip, Mask: ipNet.Mask}}, nil
+}
+
+func (client *TransparentVlanEndpointClient) addPortMappings(containerID string, mappings []PortMapping) error {
+	addresses, err := client.portMappingAddresses(mappings)
+	if err != nil {
+		return err
+	}
+	return addPortMappings(client.iptablesClient, containerID, mappings, addresses, true)
 }
 
 func (client *TransparentVlanEndpointClient) MoveEndpointsToContainerNS(epInfo *EndpointInfo, nsID uintptr) error {
@@ -652,6 +714,9 @@ func (client *TransparentVlanEndpointClient) DeleteEndpointsImpl(ep *endpoint, _
 		return errors.Wrap(err, "failed to remove routes")
 	}
 
+	// remove the shaping before deleting the host veth, which its qdiscs are attached to
+	deleteBandwidth(client.netlink, client.vnetVethName, ep.Bandwidth)
+
 	logger.Info("Deleting host veth", zap.String("vnetVethName", client.vnetVethName))
 	// Delete Host Veth
 	if err := client.netlink.DeleteLink(client.vnetVethName); err != nil {
@@ -13,6 +13,7 @@ import (
 	"github.com/Azure/azure-container-networking/platform"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
 )
 
 var errNetnsMock = errors.New("mock netns error")
@@ -375,6 +376,30 @@ func TestTransparentVlanAddEndpoints(t *testing.T) {
 			wantErr:    true,
 			wantErrMsg: "failed to move or detect vnetVethName in vnet ns, deleting: failed to set A1veth0 inside namespace 1: " + netlink.ErrorMockNetlink.Error() + " : netlink fail",
 		},
+		{
+			name: "Add endpoints set mtu fail",
+			client: &TransparentVlanEndpointClient{
+				primaryHostIfName: "eth0",
+				vlanIfName:        "eth0.1",
+				vnetVethName:      "A1veth0",
+				containerVethName: "B1veth0",
+				vnetNSName:        "az_ns_1",
+				netnsClient: &mockNetns{
+					get:         defaultGet,
+					getFromName: defaultGetFromName,
+					newNamed:    defaultNewNamed,
+					set:         defaultSet,
+					deleteNamed: defaultDeleteNamed,
+				},
+				netlink:        netlink.NewMockNetlink(true, "netlink fail"),
+				plClient:       platform.NewMockExecClient(false),
+				netUtilsClient: networkutils.NewNetworkUtils(nl, plc),
+				netioshim:      netio.NewMockNetIO(false, 0),
+			},
+			epInfo:     &EndpointInfo{MTU: 9000},
+			wantErr:    true,
+			wantErrMsg: "failed to set mtu on veth pair, deleting: failed to set mtu 9000 on A1veth0: " + netlink.ErrorMockNetlink.Error() + " : netlink fail",
+		},
 		{
 			name: "Add endpoints get interface fail for primary interface (eth0)",
 			client: &TransparentVlanEndpointClient{
@@ -963,3 +988,25 @@ func TestRunWithRetries(t *testing.T) {
 		})
 	}
 }
+
+func TestTransparentVlanAddVnetRules(t *testing.T) {
+	nl := netlink.NewMockNetlink(false, "")
+	plc := platform.NewMockExecClient(false)
+	client := &TransparentVlanEndpointClient{
+		vlanIfName:     "eth0.1",
+		vnetVethName:   "A1veth0",
+		netlink:        nl,
+		netUtilsClient: networkutils.NewNetworkUtils(nl, plc),
+		iptablesClient: &recordingIPTables{},
+	}
+
+	// the tunneling rule is only added once
+	require.NoError(t, client.AddVnetRules(&EndpointInfo{}))
+	require.NoError(t, client.AddVnetRules(&EndpointInfo{}))
+	rules, err := nl.ListRules(unix.AF_INET)
+	require.NoError(t, err)
+	require.Equal(t, []*netlink.Rule{{Family: unix.AF_INET, Mark: tunnelingMark, Table: tunnelingTable}}, rules)
+
+	client.netlink = netlink.NewMockNetlink(true, "netlink fail")
+	require.ErrorContains(t, client.AddVnetRules(&EndpointInfo{}), "unable to get existing ip rule list")
+}
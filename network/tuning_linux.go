@@ -0,0 +1,118 @@
+package network
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Azure/azure-container-networking/netlink"
+	"github.com/Azure/azure-container-networking/platform"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const sysctlCmd = "sysctl -w %s='%s'"
+
+// tuningSysctls are the sysctls an endpoint can be tuned with. They are all scoped to the network namespace, so
+// tuning an endpoint cannot change the node. A * matches a single part of the key, such as the name of an interface.
+var tuningSysctls = []string{
+	"net.core.somaxconn",
+	"net.ipv4.conf.*.accept_redirects",
+	"net.ipv4.conf.*.arp_announce",
+	"net.ipv4.conf.*.arp_filter",
+	"net.ipv4.conf.*.arp_ignore",
+	"net.ipv4.conf.*.rp_filter",
+	"net.ipv4.conf.*.send_redirects",
+	"net.ipv4.ip_local_port_range",
+	"net.ipv4.ip_local_reserved_ports",
+	"net.ipv4.ip_unprivileged_port_start",
+	"net.ipv4.ping_group_range",
+	"net.ipv4.tcp_*",
+	"net.ipv6.conf.*.accept_ra",
+	"net.ipv6.conf.*.accept_redirects",
+	"net.ipv6.conf.*.disable_ipv6",
+	"net.ipv6.conf.*.use_tempaddr",
+}
+
+// hostTCPSysctls are the net.ipv4.tcp_ sysctls which are shared by all network namespaces.
+var hostTCPSysctls = map[string]bool{
+	"net.ipv4.tcp_allowed_congestion_control":   true,
+	"net.ipv4.tcp_available_congestion_control": true,
+	"net.ipv4.tcp_available_ulp":                true,
+	"net.ipv4.tcp_low_latency":                  true,
+	"net.ipv4.tcp_max_orphans":                  true,
+	"net.ipv4.tcp_mem":                          true,
+}
+
+var (
+	sysctlKeyRegex   = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+	sysctlValueRegex = regexp.MustCompile(`^[A-Za-z0-9_ ,.:-]+$`)
+)
+
+// Validate checks that the tuning only sets allowed sysctls, to values which are safe to pass to sysctl.
+func (t *Tuning) Validate() error {
+	for key, value := range t.Sysctls {
+		if !sysctlKeyRegex.MatchString(key) || !isTuningSysctl(key) {
+			return errors.Errorf("sysctl %s cannot be tuned, only netns-scoped sysctls are allowed", key)
+		}
+		if !sysctlValueRegex.MatchString(value) {
+			return errors.Errorf("invalid value %q of sysctl %s", value, key)
+		}
+	}
+	if t.TxQueueLen < 0 {
+		return errors.Errorf("invalid tx queue length %d", t.TxQueueLen)
+	}
+	return nil
+}
+
+func isTuningSysctl(key string) bool {
+	if hostTCPSysctls[key] {
+		return false
+	}
+	// Match on the parts of the key, so that a * cannot span several of them.
+	keyPath := strings.ReplaceAll(key, ".", "/")
+	for _, pattern := range tuningSysctls {
+		if ok, _ := path.Match(strings.ReplaceAll(pattern, ".", "/"), keyPath); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// applyTuning tunes the network namespace the thread is in, and the container interface in it.
+func applyTuning(nl netlink.NetlinkInterface, plc platform.ExecClient, ifName string, tuning *Tuning) error {
+	if tuning == nil {
+		return nil
+	}
+	if err := tuning.Validate(); err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(tuning.Sysctls))
+	for key := range tuning.Sysctls {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		logger.Info("Setting sysctl in container", zap.String("key", key), zap.String("value", tuning.Sysctls[key]))
+		if _, err := plc.ExecuteCommand(fmt.Sprintf(sysctlCmd, key, tuning.Sysctls[key])); err != nil {
+			return errors.Wrapf(err, "failed to set sysctl %s", key)
+		}
+	}
+
+	if tuning.Promisc {
+		logger.Info("Setting container interface promiscuous", zap.String("ifName", ifName))
+		if err := nl.SetLinkPromisc(ifName, true); err != nil {
+			return errors.Wrapf(err, "failed to set %s promiscuous", ifName)
+		}
+	}
+	if tuning.TxQueueLen != 0 {
+		logger.Info("Setting tx queue length of container interface", zap.String("ifName", ifName), zap.Int("txQueueLen", tuning.TxQueueLen))
+		if err := nl.SetLinkTxQLen(ifName, tuning.TxQueueLen); err != nil {
+			return errors.Wrapf(err, "failed to set tx queue length of %s", ifName)
+		}
+	}
+	return nil
+}
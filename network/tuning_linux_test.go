@@ -0,0 +1,106 @@
+//go:build linux
+// +build linux
+
+package network
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-container-networking/netlink"
+	"github.com/Azure/azure-container-networking/platform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tuningNetlink keeps the link settings made through it.
+type tuningNetlink struct {
+	*netlink.MockNetlink
+	promisc []string
+	txQLen  map[string]int
+}
+
+func (n *tuningNetlink) SetLinkPromisc(ifName string, on bool) error {
+	if on {
+		n.promisc = append(n.promisc, ifName)
+	}
+	return nil
+}
+
+func (n *tuningNetlink) SetLinkTxQLen(ifName string, txQLen int) error {
+	n.txQLen[ifName] = txQLen
+	return nil
+}
+
+func TestTuningValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		tuning  Tuning
+		wantErr bool
+	}{
+		{name: "empty"},
+		{
+			name: "netns-scoped sysctls",
+			tuning: Tuning{Sysctls: map[string]string{
+				"net.core.somaxconn":              "4096",
+				"net.ipv4.tcp_keepalive_time":     "600",
+				"net.ipv4.conf.eth0.rp_filter":    "2",
+				"net.ipv4.conf.all.arp_ignore":    "1",
+				"net.ipv4.ip_local_port_range":    "1024 65000",
+				"net.ipv6.conf.default.accept_ra": "0",
+			}},
+		},
+		{name: "host-scoped sysctl", tuning: Tuning{Sysctls: map[string]string{"vm.max_map_count": "262144"}}, wantErr: true},
+		{name: "host-scoped tcp sysctl", tuning: Tuning{Sysctls: map[string]string{"net.ipv4.tcp_mem": "1 2 3"}}, wantErr: true},
+		{name: "wildcard spanning parts", tuning: Tuning{Sysctls: map[string]string{"net.ipv4.conf.eth0.1.rp_filter": "1"}}, wantErr: true},
+		{name: "shell in key", tuning: Tuning{Sysctls: map[string]string{"net.ipv4.conf.$(reboot).rp_filter": "1"}}, wantErr: true},
+		{name: "shell in value", tuning: Tuning{Sysctls: map[string]string{"net.core.somaxconn": "1';reboot'"}}, wantErr: true},
+		{name: "empty value", tuning: Tuning{Sysctls: map[string]string{"net.core.somaxconn": ""}}, wantErr: true},
+		{name: "negative tx queue length", tuning: Tuning{TxQueueLen: -1}, wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.tuning.Validate()
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestApplyTuning(t *testing.T) {
+	nl := &tuningNetlink{MockNetlink: netlink.NewMockNetlink(false, ""), txQLen: map[string]int{}}
+	plc := platform.NewMockExecClient(false)
+	var cmds []string
+	plc.SetExecCommand(func(cmd string) (string, error) {
+		cmds = append(cmds, cmd)
+		return "", nil
+	})
+
+	require.NoError(t, applyTuning(nl, plc, "eth0", nil))
+	assert.Empty(t, cmds)
+
+	tuning := &Tuning{
+		Sysctls: map[string]string{
+			"net.ipv4.tcp_keepalive_time":  "600",
+			"net.ipv4.conf.eth0.rp_filter": "2",
+		},
+		Promisc:    true,
+		TxQueueLen: 2000,
+	}
+	require.NoError(t, applyTuning(nl, plc, "eth0", tuning))
+	assert.Equal(t, []string{
+		"sysctl -w net.ipv4.conf.eth0.rp_filter='2'",
+		"sysctl -w net.ipv4.tcp_keepalive_time='600'",
+	}, cmds)
+	assert.Equal(t, []string{"eth0"}, nl.promisc)
+	assert.Equal(t, map[string]int{"eth0": 2000}, nl.txQLen)
+
+	// nothing is applied if a sysctl is not allowed
+	cmds = nil
+	tuning.Sysctls["kernel.panic"] = "1"
+	require.Error(t, applyTuning(nl, plc, "eth0", tuning))
+	assert.Empty(t, cmds)
+}
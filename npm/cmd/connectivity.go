@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"net/netip"
+
+	"github.com/Azure/azure-container-networking/npm/pkg/dataplane/debug"
+	"github.com/spf13/cobra"
+)
+
+var errManifestsNotSpecified = fmt.Errorf("must specify at least one manifest file or directory")
+
+func newConnectivityCmd() *cobra.Command {
+	connectivityCmd := &cobra.Command{
+		Use:   "connectivity",
+		Short: "Print the pod-to-pod and pod-to-CIDR connectivity which NPM would enforce for NetworkPolicy, Namespace, and Pod manifests",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			files, _ := cmd.Flags().GetStringSlice("file")
+			if len(files) == 0 {
+				return errManifestsNotSpecified
+			}
+			cidrFlags, _ := cmd.Flags().GetStringSlice("cidr")
+			portFlags, _ := cmd.Flags().GetStringSlice("ports")
+			podCIDRFlag, _ := cmd.Flags().GetString("pod-cidr")
+
+			opts := &debug.MatrixOptions{}
+			for _, cidrFlag := range cidrFlags {
+				cidr, err := netip.ParsePrefix(cidrFlag)
+				if err != nil {
+					return fmt.Errorf("failed to parse CIDR: %w", err)
+				}
+				opts.CIDRs = append(opts.CIDRs, cidr)
+			}
+			for _, portFlag := range portFlags {
+				port, err := debug.ParsePort(portFlag)
+				if err != nil {
+					return fmt.Errorf("%w", err)
+				}
+				opts.Ports = append(opts.Ports, port)
+			}
+			podCIDR, err := netip.ParsePrefix(podCIDRFlag)
+			if err != nil {
+				return fmt.Errorf("failed to parse pod CIDR: %w", err)
+			}
+			opts.PodCIDR = podCIDR
+
+			manifests, err := debug.LoadManifests(files...)
+			if err != nil {
+				return fmt.Errorf("%w", err)
+			}
+			matrix, err := debug.ComputeConnectivityMatrix(manifests, opts)
+			if err != nil {
+				return fmt.Errorf("%w", err)
+			}
+
+			fmt.Print(matrix.Table())
+			fmt.Printf("Allowed: %s, Denied: %s", debug.Allowed, debug.Denied)
+			if len(opts.CIDRs) > 0 {
+				fmt.Printf(", Denied for some addresses of the CIDR: %s", debug.PartiallyDenied)
+			}
+			fmt.Println()
+			return nil
+		},
+	}
+
+	connectivityCmd.Flags().StringSliceP("file", "f", nil, "Set the manifest files or directories, which are read recursively")
+	connectivityCmd.Flags().StringSlice("cidr", nil, "Set extra destination CIDRs (optional)")
+	connectivityCmd.Flags().StringSliceP("ports", "p", nil,
+		"Set the ports to check, e.g. TCP/80,UDP/53 (optional, defaults to the ports of the NetworkPolicies and the named ports of the pods)")
+	connectivityCmd.Flags().String("pod-cidr", "10.224.0.0/16", "Set the CIDR with the IPs for the pods without a status.podIP")
+	return connectivityCmd
+}
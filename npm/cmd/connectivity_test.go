@@ -0,0 +1,75 @@
+package main
+
+import "testing"
+
+const (
+	netpolFile     = "../pkg/dataplane/testdata/netpol.yaml"
+	netpolPodsFile = "../pkg/dataplane/testdata/netpol-pods.yaml"
+	testDataDir    = "../pkg/dataplane/testdata"
+
+	connectivityCmdString = "connectivity"
+	fileFlag              = "-f"
+	portsFlag             = "-p"
+	cidrFlag              = "--cidr"
+	podCIDRFlag           = "--pod-cidr"
+)
+
+func TestConnectivityCmd(t *testing.T) {
+	baseArgs := []string{debugCmdString, connectivityCmdString}
+	standardArgs := concatArgs(baseArgs, fileFlag, netpolFile, fileFlag, netpolPodsFile)
+
+	tests := []*testCases{
+		{
+			name:    "no files",
+			args:    baseArgs,
+			wantErr: true,
+		},
+		{
+			name:    "non-existing file",
+			args:    concatArgs(baseArgs, fileFlag, nonExistingFile),
+			wantErr: true,
+		},
+		{
+			name:    "files",
+			args:    standardArgs,
+			wantErr: false,
+		},
+		{
+			name:    "directory",
+			args:    concatArgs(baseArgs, fileFlag, testDataDir),
+			wantErr: false,
+		},
+		{
+			name:    "ports and CIDRs",
+			args:    concatArgs(standardArgs, portsFlag, "TCP/80,udp/53", cidrFlag, "10.0.0.0/8", cidrFlag, "8.8.8.8/32"),
+			wantErr: false,
+		},
+		{
+			name:    "bad port",
+			args:    concatArgs(standardArgs, portsFlag, "80"),
+			wantErr: true,
+		},
+		{
+			name:    "bad CIDR",
+			args:    concatArgs(standardArgs, cidrFlag, "10.0.0.0"),
+			wantErr: true,
+		},
+		{
+			name:    "IPv6 CIDR",
+			args:    concatArgs(standardArgs, cidrFlag, "fd00::/64"),
+			wantErr: true,
+		},
+		{
+			name:    "bad pod CIDR",
+			args:    concatArgs(standardArgs, podCIDRFlag, "10.224.0.0"),
+			wantErr: true,
+		},
+		{
+			name:    "pod CIDR too small",
+			args:    concatArgs(standardArgs, podCIDRFlag, "10.224.0.0/29"),
+			wantErr: true,
+		},
+	}
+
+	testCommand(t, tests)
+}
@@ -5,6 +5,7 @@ package main
 import (
 	"fmt"
 	"math/rand"
+	"net/http"
 	"time"
 
 	"github.com/Azure/azure-container-networking/common"
@@ -13,7 +14,9 @@ import (
 	npmconfig "github.com/Azure/azure-container-networking/npm/config"
 	restserver "github.com/Azure/azure-container-networking/npm/http/server"
 	"github.com/Azure/azure-container-networking/npm/metrics"
+	"github.com/Azure/azure-container-networking/npm/pkg/apis/policy/v1alpha1"
 	"github.com/Azure/azure-container-networking/npm/pkg/dataplane"
+	"github.com/Azure/azure-container-networking/npm/pkg/dataplane/flowlog"
 	"github.com/Azure/azure-container-networking/npm/pkg/dataplane/ipsets"
 	"github.com/Azure/azure-container-networking/npm/pkg/dataplane/policies"
 	"github.com/Azure/azure-container-networking/npm/pkg/models"
@@ -22,6 +25,9 @@ import (
 	"github.com/spf13/viper"
 	"k8s.io/apimachinery/pkg/util/wait"
 	k8sversion "k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
@@ -30,6 +36,11 @@ import (
 	"k8s.io/utils/exec"
 )
 
+var (
+	errUnsupportedToggles = fmt.Errorf("unsupported combination of toggles")
+	errMissingCRD         = fmt.Errorf("CRD is not installed")
+)
+
 var npmV2DataplaneCfg = &dataplane.Config{
 	IPSetManagerCfg: &ipsets.IPSetManagerCfg{
 		// NOTE: NetworkName and IPSetMode must be set later by the npm ConfigMap or default config
@@ -65,6 +76,23 @@ func newStartNPMCmd() *cobra.Command {
 	return startNPMCmd
 }
 
+// validateToggles fails for the toggles which are not supported together. Ignoring them would enforce policies
+// differently than configured: NetworkPolicies instead of auditing them, or without the admin policies.
+func validateToggles(toggles npmconfig.Toggles) error {
+	if toggles.AuditNetworkPolicies && toggles.EnableNFTables {
+		return fmt.Errorf("AuditNetworkPolicies is not supported with EnableNFTables: %w", errUnsupportedToggles)
+	}
+	if toggles.EnableV2NPM && toggles.EnableAdminNetworkPolicies {
+		if util.IsWindowsDP() {
+			return fmt.Errorf("EnableAdminNetworkPolicies is not supported in Windows: %w", errUnsupportedToggles)
+		}
+		if toggles.EnableNFTables {
+			return fmt.Errorf("EnableAdminNetworkPolicies is not supported with EnableNFTables: %w", errUnsupportedToggles)
+		}
+	}
+	return nil
+}
+
 func start(config npmconfig.Config, flags npmconfig.Flags) error {
 	klog.Infof("loaded config: %+v", config)
 	if util.IsWindowsDP() {
@@ -75,6 +103,10 @@ func start(config npmconfig.Config, flags npmconfig.Flags) error {
 	}
 	klog.Infof("starting NPM version %d with image %s", config.NPMVersion(), version)
 
+	if err := validateToggles(config.Toggles); err != nil {
+		return err
+	}
+
 	var err error
 
 	err = initLogging()
@@ -120,6 +152,8 @@ func start(config npmconfig.Config, flags npmconfig.Flags) error {
 	k8sServerVersion := k8sServerVersion(clientset)
 
 	var dp dataplane.GenericDataplane
+	// v2Dataplane is the same as dp for v2 NPM. Flow logs use it to find NetworkPolicies
+	var v2Dataplane *dataplane.DataPlane
 	stopChannel := wait.NeverStop
 	if config.Toggles.EnableV2NPM {
 		// update the dataplane config
@@ -157,6 +191,17 @@ func start(config npmconfig.Config, flags npmconfig.Flags) error {
 		}
 
 		npmV2DataplaneCfg.PlaceAzureChainFirst = config.Toggles.PlaceAzureChainFirst
+		npmV2DataplaneCfg.EnableNFTables = config.Toggles.EnableNFTables
+		if config.Toggles.EnableFlowLogs {
+			switch {
+			case util.IsWindowsDP():
+				klog.Infof("ignoring EnableFlowLogs in Windows")
+			case config.Toggles.EnableNFTables:
+				klog.Infof("ignoring EnableFlowLogs since it is not supported with EnableNFTables")
+			default:
+				npmV2DataplaneCfg.FlowLogging = true
+			}
+		}
 		if config.Toggles.ApplyIPSetsOnNeed {
 			npmV2DataplaneCfg.IPSetMode = ipsets.ApplyOnNeed
 		} else {
@@ -174,20 +219,44 @@ func start(config npmconfig.Config, flags npmconfig.Flags) error {
 		}
 		npmV2DataplaneCfg.NodeIP = nodeIP
 
-		dp, err = dataplane.NewDataPlane(models.GetNodeName(), common.NewIOShim(), npmV2DataplaneCfg, stopChannel)
+		v2Dataplane, err = dataplane.NewDataPlane(models.GetNodeName(), common.NewIOShim(), npmV2DataplaneCfg, stopChannel)
 		if err != nil {
 			metrics.SendErrorLogAndMetric(util.NpmID, "error: failed to create dataplane with error %v", err)
 			return fmt.Errorf("failed to create dataplane with error %w", err)
 		}
+		dp = v2Dataplane
 		dp.RunPeriodicTasks()
 	}
 	npMgr := npm.NewNetworkPolicyManager(config, factory, dp, exec.New(), version, k8sServerVersion)
+	if config.Toggles.EnableV2NPM && config.Toggles.EnableAdminNetworkPolicies {
+		if err := checkAdminNetworkPolicyCRDs(clientset.Discovery()); err != nil {
+			metrics.SendErrorLogAndMetric(util.NpmID, "error: %v", err)
+			return err
+		}
+		dynamicClient, err := dynamic.NewForConfig(k8sConfig)
+		if err != nil {
+			return fmt.Errorf("failed to generate dynamic client with cluster config: %w", err)
+		}
+		npMgr.EnableAdminNetworkPolicies(dynamicinformer.NewDynamicSharedInformerFactory(dynamicClient, resyncPeriod))
+	}
 	err = metrics.CreateTelemetryHandle(config.NPMVersion(), version, npm.GetAIMetadata())
 	if err != nil {
 		klog.Infof("CreateTelemetryHandle failed with error %v. AITelemetry is not initialized.", err)
 	}
 
-	go restserver.NPMRestServerListenAndServe(config, npMgr)
+	// the handler must be a nil interface when flow logs are disabled
+	var flowLogs http.Handler
+	if config.Toggles.EnableV2NPM && npmV2DataplaneCfg.FlowLogging {
+		collector := flowlog.NewCollector(npMgr.PodControllerV2, v2Dataplane)
+		go func() {
+			if err := collector.Run(stopChannel); err != nil {
+				metrics.SendErrorLogAndMetric(util.NpmID, "error: stopped collecting flow logs with error %v", err)
+			}
+		}()
+		flowLogs = collector
+	}
+
+	go restserver.NPMRestServerListenAndServe(config, npMgr, flowLogs)
 
 	metrics.SendLog(util.NpmID, "starting NPM", metrics.PrintLog)
 	if err = npMgr.Start(config, stopChannel); err != nil {
@@ -209,6 +278,29 @@ func initLogging() error {
 	return nil
 }
 
+// checkAdminNetworkPolicyCRDs returns an error unless the API server serves AdminNetworkPolicies and
+// BaselineAdminNetworkPolicies, since the informers of resources which aren't served never sync.
+func checkAdminNetworkPolicyCRDs(discoveryClient discovery.DiscoveryInterface) error {
+	groupVersion := v1alpha1.SchemeGroupVersion.String()
+	resources, err := discoveryClient.ServerResourcesForGroupVersion(groupVersion)
+	if err != nil {
+		return fmt.Errorf("EnableAdminNetworkPolicies requires the CRDs of %s: %w", groupVersion, err)
+	}
+	for _, resource := range []string{v1alpha1.AdminNetworkPolicyResource.Resource, v1alpha1.BaselineAdminNetworkPolicyResource.Resource} {
+		found := false
+		for i := range resources.APIResources {
+			if resources.APIResources[i].Name == resource {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("EnableAdminNetworkPolicies requires the CRD of %s in %s: %w", resource, groupVersion, errMissingCRD)
+		}
+	}
+	return nil
+}
+
 func k8sServerVersion(kubeclientset kubernetes.Interface) *k8sversion.Info {
 	var err error
 	var serverVersion *k8sversion.Info
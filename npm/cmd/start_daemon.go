@@ -74,7 +74,7 @@ func startDaemon(config npmconfig.Config) error {
 
 	dp.RunPeriodicTasks()
 	// TODO Daemon should implement cache encoder
-	go restserver.NPMRestServerListenAndServe(config, nil)
+	go restserver.NPMRestServerListenAndServe(config, nil, nil)
 
 	client, err := transport.NewEventsClient(ctx, pod, node, addr)
 	if err != nil {
@@ -4,7 +4,13 @@ import (
 	"testing"
 
 	"github.com/Azure/azure-container-networking/log"
+	npmconfig "github.com/Azure/azure-container-networking/npm/config"
+	"github.com/Azure/azure-container-networking/npm/pkg/apis/policy/v1alpha1"
+	"github.com/Azure/azure-container-networking/npm/util"
 	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
 )
 
 func TestInitLogging(t *testing.T) {
@@ -13,3 +19,65 @@ func TestInitLogging(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, expectedLogPath, log.GetLogDirectory())
 }
+
+func TestValidateToggles(t *testing.T) {
+	tests := []struct {
+		name        string
+		toggles     npmconfig.Toggles
+		unsupported bool
+	}{
+		{
+			name:    "defaults",
+			toggles: npmconfig.DefaultConfig.Toggles,
+		},
+		{
+			name:    "nftables",
+			toggles: npmconfig.Toggles{EnableV2NPM: true, EnableNFTables: true},
+		},
+		{
+			name:        "audit with nftables",
+			toggles:     npmconfig.Toggles{EnableV2NPM: true, EnableNFTables: true, AuditNetworkPolicies: true},
+			unsupported: true,
+		},
+		{
+			name:        "admin network policies with nftables",
+			toggles:     npmconfig.Toggles{EnableV2NPM: true, EnableNFTables: true, EnableAdminNetworkPolicies: true},
+			unsupported: true,
+		},
+		{
+			name:        "admin network policies",
+			toggles:     npmconfig.Toggles{EnableV2NPM: true, EnableAdminNetworkPolicies: true},
+			unsupported: util.IsWindowsDP(),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateToggles(tt.toggles)
+			if tt.unsupported {
+				require.ErrorIs(t, err, errUnsupportedToggles)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCheckAdminNetworkPolicyCRDs(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	discovery, ok := clientset.Discovery().(*fakediscovery.FakeDiscovery)
+	require.True(t, ok)
+
+	// the informers would wait forever for resources which aren't served
+	require.Error(t, checkAdminNetworkPolicyCRDs(discovery))
+
+	discovery.Resources = []*metav1.APIResourceList{{
+		GroupVersion: v1alpha1.SchemeGroupVersion.String(),
+		APIResources: []metav1.APIResource{{Name: v1alpha1.AdminNetworkPolicyResource.Resource}},
+	}}
+	require.ErrorIs(t, checkAdminNetworkPolicyCRDs(discovery), errMissingCRD)
+
+	discovery.Resources[0].APIResources = append(discovery.Resources[0].APIResources, metav1.APIResource{Name: v1alpha1.BaselineAdminNetworkPolicyResource.Resource})
+	require.NoError(t, checkAdminNetworkPolicyCRDs(discovery))
+}
@@ -51,6 +51,11 @@ var DefaultConfig = Config{
 		ApplyInBackground: true,
 		// NetPolInBackground is currently used in Linux to apply NetPol controller Add events in the background
 		NetPolInBackground: true,
+		EnableNFTables:     false,
+		// EnableAdminNetworkPolicies requires the AdminNetworkPolicy and BaselineAdminNetworkPolicy CRDs to be installed
+		EnableAdminNetworkPolicies: false,
+		AuditNetworkPolicies:       false,
+		EnableFlowLogs:             false,
 	},
 }
 
@@ -94,6 +99,16 @@ type Toggles struct {
 	ApplyInBackground bool
 	// NetPolInBackground
 	NetPolInBackground bool
+	// EnableNFTables applies for Linux only. It replaces iptables and ipset with a single nftables table.
+	EnableNFTables bool
+	// EnableAdminNetworkPolicies applies for Linux v2 NPM with iptables only, and NPM fails to start otherwise, or when the AdminNetworkPolicy CRDs are not installed. It watches AdminNetworkPolicies and the BaselineAdminNetworkPolicy.
+	EnableAdminNetworkPolicies bool
+	// AuditNetworkPolicies applies for Linux v2 NPM with iptables only, and NPM fails to start with nftables. It counts the traffic that NetworkPolicies would deny instead of enforcing them,
+	// like NetworkPolicies with the npm.azure.com/audit-only annotation.
+	AuditNetworkPolicies bool
+	// EnableFlowLogs applies for Linux v2 NPM with iptables only. It logs the connections denied by NetworkPolicies with NFLOG
+	// and streams them from the flow logs path of the HTTP server.
+	EnableFlowLogs bool
 }
 
 type Flags struct {
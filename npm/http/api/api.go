@@ -6,6 +6,7 @@ const (
 	NodeMetricsPath    = "/node-metrics"
 	ClusterMetricsPath = "/cluster-metrics"
 	NPMMgrPath         = "/npm/v1/debug/manager"
+	FlowLogsPath       = "/npm/v1/flows"
 )
 
 type DescribeIPSetRequest struct{}
@@ -21,7 +21,7 @@ type NPMRestServer struct {
 	router           *mux.Router
 }
 
-func NPMRestServerListenAndServe(config npmconfig.Config, npmEncoder json.Marshaler) {
+func NPMRestServerListenAndServe(config npmconfig.Config, npmEncoder json.Marshaler, flowLogs http.Handler) {
 	rs := NPMRestServer{}
 
 	rs.router = mux.NewRouter()
@@ -38,6 +38,11 @@ func NPMRestServerListenAndServe(config npmconfig.Config, npmEncoder json.Marsha
 		rs.router.Handle(api.NPMMgrPath, rs.npmCacheHandler(npmEncoder)).Methods(http.MethodGet)
 	}
 
+	// the nil check is for when flow logs are disabled
+	if flowLogs != nil {
+		rs.router.Handle(api.FlowLogsPath, flowLogs).Methods(http.MethodGet)
+	}
+
 	if config.Toggles.EnablePprof {
 		rs.router.PathPrefix("/debug/").Handler(http.DefaultServeMux)
 		rs.router.HandleFunc("/debug/pprof/", pprof.Index)
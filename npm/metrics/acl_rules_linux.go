@@ -41,3 +41,30 @@ func TotalIPTablesRestoreFailures(op OperationKind) (int, error) {
 		operationLabel: string(op),
 	}))
 }
+
+// SetAuditDeniedPackets sets the number of packets that the audit-only NetworkPolicy would have denied.
+func SetAuditDeniedPackets(policyKey string, count int) {
+	auditDeniedPackets.With(prometheus.Labels{policyKeyLabel: policyKey}).Set(float64(count))
+}
+
+// DeleteAuditDeniedPackets stops reporting the audit-only NetworkPolicy.
+func DeleteAuditDeniedPackets(policyKey string) {
+	auditDeniedPackets.Delete(prometheus.Labels{policyKeyLabel: policyKey})
+}
+
+// GetAuditDeniedPackets returns the number of packets that the audit-only NetworkPolicy would have denied.
+// This function is slow.
+func GetAuditDeniedPackets(policyKey string) (int, error) {
+	return getVecValue(auditDeniedPackets, prometheus.Labels{policyKeyLabel: policyKey})
+}
+
+// IncDeniedFlows counts a logged connection that the policy denied in the direction (ingress or egress).
+func IncDeniedFlows(policyKey, direction string) {
+	deniedFlows.With(prometheus.Labels{policyKeyLabel: policyKey, directionLabel: direction}).Inc()
+}
+
+// TotalDeniedFlows returns the number of logged connections that the policy denied in the direction.
+// This function is slow.
+func TotalDeniedFlows(policyKey, direction string) (int, error) {
+	return counterValue(deniedFlows.With(prometheus.Labels{policyKeyLabel: policyKey, directionLabel: direction}))
+}
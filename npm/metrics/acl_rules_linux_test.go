@@ -47,3 +47,31 @@ func TestIncIPTablesRestoreFailures(t *testing.T) {
 	require.Nil(t, err, "failed to get metric")
 	require.Equal(t, 1, count, "should have failed to update once")
 }
+
+func TestAuditDeniedPackets(t *testing.T) {
+	SetAuditDeniedPackets("x/test", 3)
+	SetAuditDeniedPackets("x/test", 5)
+
+	count, err := GetAuditDeniedPackets("x/test")
+	require.Nil(t, err, "failed to get metric")
+	require.Equal(t, 5, count, "should have set the count")
+
+	DeleteAuditDeniedPackets("x/test")
+	count, err = GetAuditDeniedPackets("x/test")
+	require.Nil(t, err, "failed to get metric")
+	require.Equal(t, 0, count, "should have deleted the count")
+}
+
+func TestIncDeniedFlows(t *testing.T) {
+	IncDeniedFlows("x/test", "ingress")
+	IncDeniedFlows("x/test", "egress")
+	IncDeniedFlows("x/test", "ingress")
+
+	count, err := TotalDeniedFlows("x/test", "ingress")
+	require.Nil(t, err, "failed to get metric")
+	require.Equal(t, 2, count, "should have denied two ingress flows")
+
+	count, err = TotalDeniedFlows("x/test", "egress")
+	require.Nil(t, err, "failed to get metric")
+	require.Equal(t, 1, count, "should have denied one egress flow")
+}
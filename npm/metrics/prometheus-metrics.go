@@ -125,6 +125,13 @@ var (
 	itpablesRestoreLatency  *prometheus.HistogramVec
 	iptablesDeleteLatency   prometheus.Histogram
 	iptablesRestoreFailures *prometheus.CounterVec
+	auditDeniedPackets      *prometheus.GaugeVec
+	deniedFlows             *prometheus.CounterVec
+)
+
+const (
+	policyKeyLabel = "policy"
+	directionLabel = "direction"
 )
 
 type RegistryType string
@@ -196,6 +203,8 @@ func InitializeAll() {
 		register(itpablesRestoreLatency, "iptables_restore_latency_seconds", NodeMetrics)
 		register(iptablesDeleteLatency, "iptables_delete_latency_seconds", NodeMetrics)
 		register(iptablesRestoreFailures, "iptables_restore_failure_total", NodeMetrics)
+		register(auditDeniedPackets, "audit_denied_packets", NodeMetrics)
+		register(deniedFlows, "denied_flows_total", NodeMetrics)
 	}
 
 	log.Logf("Finished initializing all Prometheus metrics")
@@ -354,6 +363,26 @@ func InitializeLinuxMetrics() {
 		},
 		[]string{operationLabel},
 	)
+
+	auditDeniedPackets = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "audit_denied_packets",
+			Subsystem: linuxPrefix,
+			Help:      "Number of packets that an audit-only NetworkPolicy would have denied since the policy was last applied, by policy label",
+		},
+		[]string{policyKeyLabel},
+	)
+
+	deniedFlows = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "denied_flows_total",
+			Subsystem: linuxPrefix,
+			Help:      "Number of logged connections denied by a policy, by policy and direction labels. Logging is rate-limited",
+		},
+		[]string{policyKeyLabel, directionLabel},
+	)
 }
 
 // GetHandler returns the HTTP handler for the metrics endpoint
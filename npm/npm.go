@@ -9,6 +9,7 @@ import (
 
 	npmconfig "github.com/Azure/azure-container-networking/npm/config"
 	"github.com/Azure/azure-container-networking/npm/ipsm"
+	"github.com/Azure/azure-container-networking/npm/pkg/apis/policy/v1alpha1"
 	"github.com/Azure/azure-container-networking/npm/pkg/controlplane/controllers/common"
 	controllersv1 "github.com/Azure/azure-container-networking/npm/pkg/controlplane/controllers/v1"
 	controllersv2 "github.com/Azure/azure-container-networking/npm/pkg/controlplane/controllers/v2"
@@ -17,6 +18,7 @@ import (
 	"github.com/Azure/azure-container-networking/npm/util"
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/dynamic/dynamicinformer"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog"
@@ -87,7 +89,7 @@ func NewNetworkPolicyManager(config npmconfig.Config,
 		npMgr.PodControllerV2 = controllersv2.NewPodController(npMgr.PodInformer, dp, npMgr.NpmNamespaceCacheV2)
 		npMgr.NamespaceControllerV2 = controllersv2.NewNamespaceController(npMgr.NsInformer, dp, npMgr.NpmNamespaceCacheV2)
 		// Question(jungukcho): Is config.Toggles.PlaceAzureChainFirst needed for v2?
-		npMgr.NetPolControllerV2 = controllersv2.NewNetworkPolicyController(npMgr.NpInformer, dp)
+		npMgr.NetPolControllerV2 = controllersv2.NewNetworkPolicyController(npMgr.NpInformer, dp, config.Toggles.AuditNetworkPolicies)
 		return npMgr
 	}
 
@@ -101,6 +103,15 @@ func NewNetworkPolicyManager(config npmconfig.Config,
 	return npMgr
 }
 
+// EnableAdminNetworkPolicies creates the controller for AdminNetworkPolicies and the BaselineAdminNetworkPolicy.
+// It must be called before Start and only for v2 NPM.
+func (npMgr *NetworkPolicyManager) EnableAdminNetworkPolicies(dynamicInformerFactory dynamicinformer.DynamicSharedInformerFactory) {
+	npMgr.DynamicInformerFactory = dynamicInformerFactory
+	npMgr.AnpInformer = dynamicInformerFactory.ForResource(v1alpha1.AdminNetworkPolicyResource)
+	npMgr.BanpInformer = dynamicInformerFactory.ForResource(v1alpha1.BaselineAdminNetworkPolicyResource)
+	npMgr.AdminNetPolControllerV2 = controllersv2.NewAdminNetworkPolicyController(npMgr.AnpInformer, npMgr.BanpInformer, npMgr.Dataplane)
+}
+
 // Dear Time Traveler:
 // This is the server end of the debug dragons den. Several of these properties of the
 // npMgr struct have overridden methods which override the MarshalJson, just as this one
@@ -200,9 +211,19 @@ func (npMgr *NetworkPolicyManager) Start(config npmconfig.Config, stopCh <-chan
 		return fmt.Errorf("NetworkPolicy informer error: %w", models.ErrInformerSyncFailure)
 	}
 
+	if npMgr.DynamicInformerFactory != nil {
+		npMgr.DynamicInformerFactory.Start(stopCh)
+		if !cache.WaitForCacheSync(stopCh, npMgr.AnpInformer.Informer().HasSynced, npMgr.BanpInformer.Informer().HasSynced) {
+			return fmt.Errorf("AdminNetworkPolicy informer error: %w", models.ErrInformerSyncFailure)
+		}
+	}
+
 	// start v2 NPM controllers after synced
 	if config.Toggles.EnableV2NPM {
 		go npMgr.NetPolControllerV2.Run(stopCh)
+		if npMgr.AdminNetPolControllerV2 != nil {
+			go npMgr.AdminNetPolControllerV2.Run(stopCh)
+		}
 
 		if util.IsWindowsDP() && config.Toggles.ApplyInBackground {
 			klog.Infof("optimizing NPM bootup by letting NetPol controller process changes first. waiting %v before starting pod and namespace controllers", waitDurationAfterStartingNetPolController)
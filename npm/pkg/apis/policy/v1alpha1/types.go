@@ -0,0 +1,191 @@
+// Package v1alpha1 holds the policy.networking.k8s.io/v1alpha1 AdminNetworkPolicy and BaselineAdminNetworkPolicy types
+// (https://network-policy-api.sigs.k8s.io/) that NPM translates.
+// NPM reads these CRDs with the dynamic client, so only the fields that NPM uses are declared here.
+package v1alpha1
+
+import (
+	"errors"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// GroupName is the API group of the CRDs.
+const GroupName = "policy.networking.k8s.io"
+
+var (
+	// SchemeGroupVersion is the group version of the CRDs.
+	SchemeGroupVersion = schema.GroupVersion{Group: GroupName, Version: "v1alpha1"}
+	// AdminNetworkPolicyResource is the resource for watching AdminNetworkPolicies.
+	AdminNetworkPolicyResource = SchemeGroupVersion.WithResource("adminnetworkpolicies")
+	// BaselineAdminNetworkPolicyResource is the resource for watching the BaselineAdminNetworkPolicy.
+	BaselineAdminNetworkPolicyResource = SchemeGroupVersion.WithResource("baselineadminnetworkpolicies")
+)
+
+var errUnexpectedObjectType = errors.New("unexpected object type")
+
+// BaselineAdminNetworkPolicyName is the name of the only BaselineAdminNetworkPolicy allowed in a cluster.
+const BaselineAdminNetworkPolicyName = "default"
+
+// AdminNetworkPolicy is a cluster-scoped policy which is evaluated before NetworkPolicies.
+// AdminNetworkPolicies are evaluated from the lowest to the highest priority number.
+type AdminNetworkPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec AdminNetworkPolicySpec `json:"spec"`
+}
+
+type AdminNetworkPolicySpec struct {
+	// Priority is between 0 and 1000. A lower number is evaluated first.
+	Priority int32                     `json:"priority"`
+	Subject  AdminNetworkPolicySubject `json:"subject"`
+	// Ingress rules are evaluated in order, and the first matching rule decides.
+	Ingress []AdminNetworkPolicyIngressRule `json:"ingress,omitempty"`
+	// Egress rules are evaluated in order, and the first matching rule decides.
+	Egress []AdminNetworkPolicyEgressRule `json:"egress,omitempty"`
+}
+
+// AdminNetworkPolicySubject selects the pods of the policy. Exactly one field is set.
+type AdminNetworkPolicySubject struct {
+	Namespaces *metav1.LabelSelector `json:"namespaces,omitempty"`
+	Pods       *NamespacedPod        `json:"pods,omitempty"`
+}
+
+// NamespacedPod selects the pods matching PodSelector in the namespaces matching NamespaceSelector.
+type NamespacedPod struct {
+	NamespaceSelector metav1.LabelSelector `json:"namespaceSelector"`
+	PodSelector       metav1.LabelSelector `json:"podSelector"`
+}
+
+type AdminNetworkPolicyRuleAction string
+
+const (
+	// AdminNetworkPolicyRuleActionAllow allows the traffic regardless of lower priority policies.
+	AdminNetworkPolicyRuleActionAllow AdminNetworkPolicyRuleAction = "Allow"
+	// AdminNetworkPolicyRuleActionDeny denies the traffic regardless of lower priority policies.
+	AdminNetworkPolicyRuleActionDeny AdminNetworkPolicyRuleAction = "Deny"
+	// AdminNetworkPolicyRuleActionPass skips the remaining AdminNetworkPolicies and leaves the traffic to NetworkPolicies.
+	AdminNetworkPolicyRuleActionPass AdminNetworkPolicyRuleAction = "Pass"
+)
+
+type AdminNetworkPolicyIngressRule struct {
+	Name   string                          `json:"name,omitempty"`
+	Action AdminNetworkPolicyRuleAction    `json:"action"`
+	From   []AdminNetworkPolicyIngressPeer `json:"from"`
+	// Ports restricts the rule to these destination ports. All ports match if Ports is nil.
+	Ports *[]AdminNetworkPolicyPort `json:"ports,omitempty"`
+}
+
+type AdminNetworkPolicyEgressRule struct {
+	Name   string                         `json:"name,omitempty"`
+	Action AdminNetworkPolicyRuleAction   `json:"action"`
+	To     []AdminNetworkPolicyEgressPeer `json:"to"`
+	// Ports restricts the rule to these destination ports. All ports match if Ports is nil.
+	Ports *[]AdminNetworkPolicyPort `json:"ports,omitempty"`
+}
+
+// AdminNetworkPolicyIngressPeer selects the sources of ingress traffic. Exactly one field is set.
+type AdminNetworkPolicyIngressPeer struct {
+	Namespaces *metav1.LabelSelector `json:"namespaces,omitempty"`
+	Pods       *NamespacedPod        `json:"pods,omitempty"`
+}
+
+// AdminNetworkPolicyEgressPeer selects the destinations of egress traffic. Exactly one field is set.
+type AdminNetworkPolicyEgressPeer struct {
+	Namespaces *metav1.LabelSelector `json:"namespaces,omitempty"`
+	Pods       *NamespacedPod        `json:"pods,omitempty"`
+	Nodes      *metav1.LabelSelector `json:"nodes,omitempty"`
+	Networks   []CIDR                `json:"networks,omitempty"`
+}
+
+// CIDR is an IPv4 or IPv6 CIDR.
+type CIDR string
+
+// AdminNetworkPolicyPort selects destination ports. Exactly one field is set.
+type AdminNetworkPolicyPort struct {
+	PortNumber *Port      `json:"portNumber,omitempty"`
+	NamedPort  *string    `json:"namedPort,omitempty"`
+	PortRange  *PortRange `json:"portRange,omitempty"`
+}
+
+type Port struct {
+	Protocol v1.Protocol `json:"protocol"`
+	Port     int32       `json:"port"`
+}
+
+type PortRange struct {
+	Protocol v1.Protocol `json:"protocol"`
+	Start    int32       `json:"start"`
+	End      int32       `json:"end"`
+}
+
+// BaselineAdminNetworkPolicy is a cluster-scoped policy which is evaluated for pods that no NetworkPolicy isolates.
+type BaselineAdminNetworkPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec BaselineAdminNetworkPolicySpec `json:"spec"`
+}
+
+type BaselineAdminNetworkPolicySpec struct {
+	Subject AdminNetworkPolicySubject `json:"subject"`
+	// Ingress rules are evaluated in order, and the first matching rule decides.
+	Ingress []BaselineAdminNetworkPolicyIngressRule `json:"ingress,omitempty"`
+	// Egress rules are evaluated in order, and the first matching rule decides.
+	Egress []BaselineAdminNetworkPolicyEgressRule `json:"egress,omitempty"`
+}
+
+type BaselineAdminNetworkPolicyRuleAction string
+
+const (
+	BaselineAdminNetworkPolicyRuleActionAllow BaselineAdminNetworkPolicyRuleAction = "Allow"
+	BaselineAdminNetworkPolicyRuleActionDeny  BaselineAdminNetworkPolicyRuleAction = "Deny"
+)
+
+type BaselineAdminNetworkPolicyIngressRule struct {
+	Name   string                               `json:"name,omitempty"`
+	Action BaselineAdminNetworkPolicyRuleAction `json:"action"`
+	From   []AdminNetworkPolicyIngressPeer      `json:"from"`
+	Ports  *[]AdminNetworkPolicyPort            `json:"ports,omitempty"`
+}
+
+type BaselineAdminNetworkPolicyEgressRule struct {
+	Name   string                               `json:"name,omitempty"`
+	Action BaselineAdminNetworkPolicyRuleAction `json:"action"`
+	To     []AdminNetworkPolicyEgressPeer       `json:"to"`
+	Ports  *[]AdminNetworkPolicyPort            `json:"ports,omitempty"`
+}
+
+// AdminNetworkPolicyFromUnstructured converts an object from the dynamic client.
+func AdminNetworkPolicyFromUnstructured(obj runtime.Object) (*AdminNetworkPolicy, error) {
+	anp := &AdminNetworkPolicy{}
+	if err := fromUnstructured(obj, anp); err != nil {
+		return nil, err
+	}
+	return anp, nil
+}
+
+// BaselineAdminNetworkPolicyFromUnstructured converts an object from the dynamic client.
+func BaselineAdminNetworkPolicyFromUnstructured(obj runtime.Object) (*BaselineAdminNetworkPolicy, error) {
+	banp := &BaselineAdminNetworkPolicy{}
+	if err := fromUnstructured(obj, banp); err != nil {
+		return nil, err
+	}
+	return banp, nil
+}
+
+func fromUnstructured(obj runtime.Object, into interface{}) error {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return fmt.Errorf("expected *unstructured.Unstructured but got %T: %w", obj, errUnexpectedObjectType)
+	}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.UnstructuredContent(), into); err != nil {
+		return fmt.Errorf("failed to convert %s %s: %w", u.GetKind(), u.GetName(), err)
+	}
+	return nil
+}
@@ -0,0 +1,239 @@
+// Copyright 2018 Microsoft. All rights reserved.
+// MIT License
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-container-networking/npm/metrics"
+	"github.com/Azure/azure-container-networking/npm/pkg/apis/policy/v1alpha1"
+	"github.com/Azure/azure-container-networking/npm/pkg/controlplane/translation"
+	"github.com/Azure/azure-container-networking/npm/pkg/dataplane"
+	"github.com/Azure/azure-container-networking/npm/pkg/dataplane/policies"
+	"github.com/Azure/azure-container-networking/npm/util"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog"
+)
+
+var errAdminPolicyKeyFormat = errors.New("invalid admin network policy key format")
+
+// AdminNetworkPolicyController programs AdminNetworkPolicies and the BaselineAdminNetworkPolicy.
+// Both CRDs are watched with the dynamic client, and the workqueue holds policy keys
+// (see translation.AdminPolicyKey and translation.BaselineAdminPolicyKey).
+type AdminNetworkPolicyController struct {
+	sync.RWMutex
+	anpLister  cache.GenericLister
+	banpLister cache.GenericLister
+	workqueue  workqueue.RateLimitingInterface
+	// rawSpecMap holds the lastly applied *v1alpha1.AdminNetworkPolicySpec or *v1alpha1.BaselineAdminNetworkPolicySpec.
+	// Key is the policy key.
+	rawSpecMap map[string]interface{}
+	dp         dataplane.GenericDataplane
+}
+
+func (c *AdminNetworkPolicyController) GetCache() map[string]interface{} {
+	c.RLock()
+	defer c.RUnlock()
+	return c.rawSpecMap
+}
+
+func NewAdminNetworkPolicyController(anpInformer, banpInformer informers.GenericInformer, dp dataplane.GenericDataplane) *AdminNetworkPolicyController {
+	c := &AdminNetworkPolicyController{
+		anpLister:  anpInformer.Lister(),
+		banpLister: banpInformer.Lister(),
+		workqueue:  workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "AdminNetworkPolicy"),
+		rawSpecMap: make(map[string]interface{}),
+		dp:         dp,
+	}
+
+	anpInformer.Informer().AddEventHandler(c.eventHandler(translation.AdminPolicyKey))
+	banpInformer.Informer().AddEventHandler(c.eventHandler(translation.BaselineAdminPolicyKey))
+	return c
+}
+
+func (c *AdminNetworkPolicyController) eventHandler(policyKey func(name string) string) cache.ResourceEventHandlerFuncs {
+	enqueue := func(obj interface{}) {
+		// DeleteFunc may get an object of type DeletedFinalStateUnknown if the watch missed the delete event
+		if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+			obj = tombstone.Obj
+		}
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			metrics.SendErrorLogAndMetric(util.NetpolID, "[ADMIN NETPOL EVENT] Received unexpected object type: %v", obj)
+			return
+		}
+		c.workqueue.Add(policyKey(accessor.GetName()))
+	}
+
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: enqueue,
+		UpdateFunc: func(old, newObj interface{}) {
+			oldAccessor, oldErr := meta.Accessor(old)
+			newAccessor, newErr := meta.Accessor(newObj)
+			if oldErr == nil && newErr == nil && oldAccessor.GetResourceVersion() == newAccessor.GetResourceVersion() {
+				// Periodic resync will send update events for all known policies.
+				return
+			}
+			enqueue(newObj)
+		},
+		DeleteFunc: enqueue,
+	}
+}
+
+func (c *AdminNetworkPolicyController) Run(stopCh <-chan struct{}) {
+	defer utilruntime.HandleCrash()
+	defer c.workqueue.ShutDown()
+
+	klog.Infof("Starting Admin Network Policy worker")
+	go wait.Until(c.runWorker, time.Second, stopCh)
+
+	klog.Infof("Started Admin Network Policy worker")
+	<-stopCh
+	klog.Info("Shutting down Admin Network Policy workers")
+}
+
+func (c *AdminNetworkPolicyController) runWorker() {
+	for c.processNextWorkItem() {
+	}
+}
+
+func (c *AdminNetworkPolicyController) processNextWorkItem() bool {
+	obj, shutdown := c.workqueue.Get()
+
+	if shutdown {
+		return false
+	}
+
+	err := func(obj interface{}) error {
+		defer c.workqueue.Done(obj)
+		var key string
+		var ok bool
+		if key, ok = obj.(string); !ok {
+			c.workqueue.Forget(obj)
+			utilruntime.HandleError(fmt.Errorf("expected string in workqueue but got %#v, err %w", obj, errWorkqueueFormatting))
+			return nil
+		}
+		if err := c.syncAdminPolicy(key); err != nil {
+			// Put the item back on the workqueue to handle any transient errors.
+			c.workqueue.AddRateLimited(key)
+			return fmt.Errorf("error syncing '%s': %w, requeuing", key, err)
+		}
+		c.workqueue.Forget(obj)
+		klog.Infof("Successfully synced '%s'", key)
+		return nil
+	}(obj)
+	if err != nil {
+		utilruntime.HandleError(err)
+		metrics.SendErrorLogAndMetric(util.NetpolID, "syncAdminPolicy error due to %v", err)
+		return true
+	}
+
+	return true
+}
+
+// syncAdminPolicy compares the actual state with the desired, and attempts to converge the two.
+func (c *AdminNetworkPolicyController) syncAdminPolicy(key string) error {
+	c.Lock()
+	defer c.Unlock()
+
+	kind, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil || name == "" {
+		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s err: %w", key, errAdminPolicyKeyFormat))
+		return nil //nolint HandleError  is used instead of returning error to caller
+	}
+
+	lister := c.anpLister
+	if key == translation.BaselineAdminPolicyKey(name) {
+		lister = c.banpLister
+	}
+	obj, err := lister.Get(name)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			klog.Infof("%s %s is not found, may be it is deleted", kind, name)
+			return c.cleanUpAdminPolicy(key)
+		}
+		return err
+	}
+
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("unexpected object for key %s: %w", key, err))
+		return nil //nolint HandleError  is used instead of returning error to caller
+	}
+	if accessor.GetDeletionTimestamp() != nil || accessor.GetDeletionGracePeriodSeconds() != nil {
+		return c.cleanUpAdminPolicy(key)
+	}
+
+	var spec interface{}
+	var npmNetPol *policies.NPMNetworkPolicy
+	if lister == c.banpLister {
+		if name != v1alpha1.BaselineAdminNetworkPolicyName {
+			klog.Warningf("BaselineAdminNetworkPolicy %s is ignored since it must be named %s", name, v1alpha1.BaselineAdminNetworkPolicyName)
+			return nil
+		}
+		banp, convErr := v1alpha1.BaselineAdminNetworkPolicyFromUnstructured(obj)
+		if convErr != nil {
+			klog.Errorf("Failed to read %s: %s", key, convErr.Error())
+			return nil
+		}
+		spec = &banp.Spec
+		if c.isApplied(key, spec) {
+			return nil
+		}
+		npmNetPol, err = translation.TranslateBaselineAdminPolicy(banp)
+	} else {
+		anp, convErr := v1alpha1.AdminNetworkPolicyFromUnstructured(obj)
+		if convErr != nil {
+			klog.Errorf("Failed to read %s: %s", key, convErr.Error())
+			return nil
+		}
+		spec = &anp.Spec
+		if c.isApplied(key, spec) {
+			return nil
+		}
+		npmNetPol, err = translation.TranslateAdminPolicy(anp)
+	}
+	if err != nil {
+		// Returning nil to prevent re-queuing since this is not a transient error.
+		klog.Warningf("%s is not translated because it has unsupported features: %s", key, err.Error())
+		return nil
+	}
+
+	// DP update policy call will check if this policy already exists in kernel
+	// if yes: then will delete old rules and program new rules
+	// if no: then will program add new rules
+	if err := c.dp.UpdatePolicy(npmNetPol); err != nil {
+		return fmt.Errorf("[syncAdminPolicy] Error: failed to update translated NPMNetworkPolicy into Dataplane due to %w", err)
+	}
+	c.rawSpecMap[key] = spec
+	return nil
+}
+
+// isApplied returns true if the spec is the lastly applied spec of the policy.
+func (c *AdminNetworkPolicyController) isApplied(key string, spec interface{}) bool {
+	cachedSpec, ok := c.rawSpecMap[key]
+	return ok && reflect.DeepEqual(cachedSpec, spec)
+}
+
+// cleanUpAdminPolicy removes the policy from the dataplane if it was applied.
+func (c *AdminNetworkPolicyController) cleanUpAdminPolicy(key string) error {
+	if _, ok := c.rawSpecMap[key]; !ok {
+		return nil
+	}
+
+	if err := c.dp.RemovePolicy(key); err != nil {
+		return fmt.Errorf("[cleanUpAdminPolicy] Error: failed to remove policy due to %w", err)
+	}
+	delete(c.rawSpecMap, key)
+	return nil
+}
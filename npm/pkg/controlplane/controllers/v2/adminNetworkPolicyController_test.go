@@ -0,0 +1,103 @@
+// Copyright 2018 Microsoft. All rights reserved.
+// MIT License
+package controllers
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-container-networking/npm/pkg/apis/policy/v1alpha1"
+	"github.com/Azure/azure-container-networking/npm/pkg/controlplane/translation"
+	dpmocks "github.com/Azure/azure-container-networking/npm/pkg/dataplane/mocks"
+	"github.com/Azure/azure-container-networking/npm/pkg/dataplane/policies"
+	"github.com/Azure/azure-container-networking/npm/util"
+	gomock "github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func newAdminPolicyObj(kind, name, resourceVersion string, spec map[string]interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": v1alpha1.SchemeGroupVersion.String(),
+		"kind":       kind,
+		"metadata":   map[string]interface{}{"name": name, "resourceVersion": resourceVersion},
+		"spec":       spec,
+	}}
+}
+
+func TestAdminNetworkPolicyController(t *testing.T) {
+	if util.IsWindowsDP() {
+		t.Skip("admin network policies are not supported on windows")
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	dp := dpmocks.NewMockGenericDataplane(ctrl)
+
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		v1alpha1.AdminNetworkPolicyResource:         "AdminNetworkPolicyList",
+		v1alpha1.BaselineAdminNetworkPolicyResource: "BaselineAdminNetworkPolicyList",
+	})
+	factory := dynamicinformer.NewDynamicSharedInformerFactory(client, 0)
+	anpInformer := factory.ForResource(v1alpha1.AdminNetworkPolicyResource)
+	banpInformer := factory.ForResource(v1alpha1.BaselineAdminNetworkPolicyResource)
+	c := NewAdminNetworkPolicyController(anpInformer, banpInformer, dp)
+
+	anpSpec := map[string]interface{}{
+		"priority": int64(10),
+		"subject":  map[string]interface{}{"namespaces": map[string]interface{}{}},
+		"ingress": []interface{}{
+			map[string]interface{}{
+				"action": "Deny",
+				"from":   []interface{}{map[string]interface{}{"namespaces": map[string]interface{}{}}},
+			},
+		},
+	}
+	anp := newAdminPolicyObj("AdminNetworkPolicy", "deny-all", "1", anpSpec)
+	banp := newAdminPolicyObj("BaselineAdminNetworkPolicy", "default", "1", map[string]interface{}{
+		"subject": map[string]interface{}{"namespaces": map[string]interface{}{}},
+	})
+	ignoredBANP := newAdminPolicyObj("BaselineAdminNetworkPolicy", "other", "1", map[string]interface{}{
+		"subject": map[string]interface{}{"namespaces": map[string]interface{}{}},
+	})
+
+	// 1. add an AdminNetworkPolicy
+	dp.EXPECT().UpdatePolicy(gomock.Any()).DoAndReturn(func(policy *policies.NPMNetworkPolicy) error {
+		require.Equal(t, "AdminNetworkPolicy/deny-all", policy.PolicyKey)
+		require.Equal(t, policies.AdminTier, policy.Tier)
+		require.Equal(t, int32(10), policy.Priority)
+		return nil
+	}).Times(1)
+	require.NoError(t, anpInformer.Informer().GetIndexer().Add(anp))
+	c.eventHandler(translation.AdminPolicyKey).OnAdd(anp, false)
+	require.True(t, c.processNextWorkItem())
+	require.Len(t, c.GetCache(), 1)
+
+	// 2. an update with the same spec is not applied again
+	updatedANP := anp.DeepCopy()
+	updatedANP.SetResourceVersion("2")
+	updatedANP.SetLabels(map[string]string{"a": "b"})
+	require.NoError(t, anpInformer.Informer().GetIndexer().Update(updatedANP))
+	require.NoError(t, c.syncAdminPolicy("AdminNetworkPolicy/deny-all"))
+
+	// 3. add the BaselineAdminNetworkPolicy, and ignore a BaselineAdminNetworkPolicy with another name
+	dp.EXPECT().UpdatePolicy(gomock.Any()).DoAndReturn(func(policy *policies.NPMNetworkPolicy) error {
+		require.Equal(t, "BaselineAdminNetworkPolicy/default", policy.PolicyKey)
+		require.Equal(t, policies.BaselineTier, policy.Tier)
+		return nil
+	}).Times(1)
+	require.NoError(t, banpInformer.Informer().GetIndexer().Add(banp))
+	require.NoError(t, banpInformer.Informer().GetIndexer().Add(ignoredBANP))
+	require.NoError(t, c.syncAdminPolicy("BaselineAdminNetworkPolicy/default"))
+	require.NoError(t, c.syncAdminPolicy("BaselineAdminNetworkPolicy/other"))
+	require.Len(t, c.GetCache(), 2)
+
+	// 4. delete the AdminNetworkPolicy
+	dp.EXPECT().RemovePolicy("AdminNetworkPolicy/deny-all").Return(nil).Times(1)
+	require.NoError(t, anpInformer.Informer().GetIndexer().Delete(updatedANP))
+	require.NoError(t, c.syncAdminPolicy("AdminNetworkPolicy/deny-all"))
+	require.Len(t, c.GetCache(), 1)
+}
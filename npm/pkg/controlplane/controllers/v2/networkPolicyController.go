@@ -12,6 +12,7 @@ import (
 	"github.com/Azure/azure-container-networking/npm/metrics"
 	"github.com/Azure/azure-container-networking/npm/pkg/controlplane/translation"
 	"github.com/Azure/azure-container-networking/npm/pkg/dataplane"
+	"github.com/Azure/azure-container-networking/npm/pkg/dataplane/policies"
 	"github.com/Azure/azure-container-networking/npm/util"
 	networkingv1 "k8s.io/api/networking/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
@@ -34,7 +35,11 @@ type NetworkPolicyController struct {
 	netPolLister netpollister.NetworkPolicyLister
 	workqueue    workqueue.RateLimitingInterface
 	rawNpSpecMap map[string]*networkingv1.NetworkPolicySpec // Key is <nsname>/<policyname>
-	dp           dataplane.GenericDataplane
+	// auditNpMap holds the keys of the network policies in rawNpSpecMap which were applied as audit-only
+	auditNpMap map[string]struct{}
+	// auditAllPolicies applies every network policy as audit-only, regardless of its annotations
+	auditAllPolicies bool
+	dp               dataplane.GenericDataplane
 }
 
 func (c *NetworkPolicyController) GetCache() map[string]*networkingv1.NetworkPolicySpec {
@@ -43,12 +48,14 @@ func (c *NetworkPolicyController) GetCache() map[string]*networkingv1.NetworkPol
 	return c.rawNpSpecMap
 }
 
-func NewNetworkPolicyController(npInformer networkinginformers.NetworkPolicyInformer, dp dataplane.GenericDataplane) *NetworkPolicyController {
+func NewNetworkPolicyController(npInformer networkinginformers.NetworkPolicyInformer, dp dataplane.GenericDataplane, auditAllPolicies bool) *NetworkPolicyController {
 	netPolController := &NetworkPolicyController{
-		netPolLister: npInformer.Lister(),
-		workqueue:    workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "NetworkPolicy"),
-		rawNpSpecMap: make(map[string]*networkingv1.NetworkPolicySpec),
-		dp:           dp,
+		netPolLister:     npInformer.Lister(),
+		workqueue:        workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "NetworkPolicy"),
+		rawNpSpecMap:     make(map[string]*networkingv1.NetworkPolicySpec),
+		auditNpMap:       make(map[string]struct{}),
+		auditAllPolicies: auditAllPolicies,
+		dp:               dp,
 	}
 
 	npInformer.Informer().AddEventHandler(
@@ -260,7 +267,8 @@ func (c *NetworkPolicyController) syncNetPol(key string) error {
 		// netPolController does not need to reconcile this update.
 		// In this updateNetworkPolicy event,
 		// newNetPol was updated with states which netPolController does not need to reconcile.
-		if reflect.DeepEqual(cachedNetPolSpecObj, &netPolObj.Spec) {
+		_, appliedAsAudit := c.auditNpMap[key]
+		if reflect.DeepEqual(cachedNetPolSpecObj, &netPolObj.Spec) && appliedAsAudit == c.isAuditOnly(netPolObj) {
 			return nil
 		}
 	}
@@ -298,6 +306,14 @@ func (c *NetworkPolicyController) syncAddAndUpdateNetPol(netPolObj *networkingv1
 		// The exec time isn't relevant here, so consider a no-op. Returning nil to prevent re-queuing since this is not a transient error.
 		return metrics.NoOp, nil
 	}
+	if c.auditAllPolicies {
+		if util.IsWindowsDP() {
+			klog.Warningf("NetworkPolicy %s in namespace %s is not applied because audit-only network policies are unsupported on Windows",
+				netPolObj.ObjectMeta.Name, netPolObj.ObjectMeta.Namespace)
+			return metrics.NoOp, nil
+		}
+		npmNetPolObj.AuditOnly = true
+	}
 
 	_, policyExisted := c.rawNpSpecMap[netpolKey]
 	var operationKind metrics.OperationKind
@@ -313,6 +329,13 @@ func (c *NetworkPolicyController) syncAddAndUpdateNetPol(netPolObj *networkingv1
 	// if no: then will program add new rules
 	err = c.dp.UpdatePolicy(npmNetPolObj)
 	if err != nil {
+		if errors.Is(err, policies.ErrUnsupportedNFTablesAudit) {
+			klog.Warningf("NetworkPolicy %s in namespace %s is not applied because audit-only network policies are unsupported with nftables",
+				netPolObj.ObjectMeta.Name, netPolObj.ObjectMeta.Namespace)
+			// Re-queuing will result in the same error. The exec time isn't relevant here, so consider a no-op.
+			return metrics.NoOp, nil
+		}
+
 		// if error occurred the key is re-queued in workqueue and process this function again,
 		// which eventually meets desired states of network policy
 		return operationKind, fmt.Errorf("[syncAddAndUpdateNetPol] Error: failed to update translated NPMNetworkPolicy into Dataplane due to %w", err)
@@ -324,6 +347,11 @@ func (c *NetworkPolicyController) syncAddAndUpdateNetPol(netPolObj *networkingv1
 	}
 
 	c.rawNpSpecMap[netpolKey] = &netPolObj.Spec
+	if npmNetPolObj.AuditOnly {
+		c.auditNpMap[netpolKey] = struct{}{}
+	} else {
+		delete(c.auditNpMap, netpolKey)
+	}
 	return operationKind, nil
 }
 
@@ -342,12 +370,19 @@ func (c *NetworkPolicyController) cleanUpNetworkPolicy(netPolKey string) error {
 
 	// Success to clean up ipset and iptables operations in kernel and delete the cached network policy from RawNpMap
 	delete(c.rawNpSpecMap, netPolKey)
+	delete(c.auditNpMap, netPolKey)
 	metrics.DecNumPolicies()
 	return nil
 }
 
+// isAuditOnly returns true if the network policy is applied as audit-only.
+func (c *NetworkPolicyController) isAuditOnly(netPolObj *networkingv1.NetworkPolicy) bool {
+	return c.auditAllPolicies || translation.IsAuditOnly(netPolObj)
+}
+
 func isUnsupportedWindowsTranslationErr(err error) bool {
-	return errors.Is(err, translation.ErrUnsupportedNamedPort) ||
+	return errors.Is(err, translation.ErrUnsupportedAuditOnly) ||
+		errors.Is(err, translation.ErrUnsupportedNamedPort) ||
 		errors.Is(err, translation.ErrUnsupportedNegativeMatch) ||
 		errors.Is(err, translation.ErrUnsupportedSCTP) ||
 		errors.Is(err, translation.ErrUnsupportedExceptCIDR)
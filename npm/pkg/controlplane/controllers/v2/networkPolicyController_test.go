@@ -9,8 +9,11 @@ import (
 
 	"github.com/Azure/azure-container-networking/npm/metrics"
 	"github.com/Azure/azure-container-networking/npm/metrics/promutil"
+	"github.com/Azure/azure-container-networking/npm/pkg/controlplane/translation"
 	"github.com/Azure/azure-container-networking/npm/pkg/dataplane"
 	dpmocks "github.com/Azure/azure-container-networking/npm/pkg/dataplane/mocks"
+	"github.com/Azure/azure-container-networking/npm/pkg/dataplane/policies"
+	"github.com/Azure/azure-container-networking/npm/util"
 	gomock "github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
@@ -48,7 +51,7 @@ func (f *netPolFixture) newNetPolController(_ chan struct{}, dp dataplane.Generi
 	kubeclient := k8sfake.NewSimpleClientset(f.kubeobjects...)
 	f.kubeInformer = kubeinformers.NewSharedInformerFactory(kubeclient, noResyncPeriodFunc())
 
-	f.netPolController = NewNetworkPolicyController(f.kubeInformer.Networking().V1().NetworkPolicies(), dp)
+	f.netPolController = NewNetworkPolicyController(f.kubeInformer.Networking().V1().NetworkPolicies(), dp, false)
 
 	for _, netPol := range f.netPolLister {
 		err := f.kubeInformer.Networking().V1().NetworkPolicies().Informer().GetIndexer().Add(netPol)
@@ -425,3 +428,74 @@ func TestLabelUpdateNetworkPolicy(t *testing.T) {
 	}
 	checkNetPolTestResult("TestUpdateNetPol", f, testCases)
 }
+
+func TestAddAuditOnlyNetworkPolicyWithNFTables(t *testing.T) {
+	if util.IsWindowsDP() {
+		t.Skip("audit-only network policies are not supported on windows")
+	}
+	netPolObj := createNetPol()
+	netPolObj.Annotations = map[string]string{translation.AuditOnlyAnnotation: "true"}
+
+	f := newNetPolFixture(t)
+	f.netPolLister = append(f.netPolLister, netPolObj)
+	f.kubeobjects = append(f.kubeobjects, netPolObj)
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dp := dpmocks.NewMockGenericDataplane(ctrl)
+	f.newNetPolController(stopCh, dp)
+
+	// the policy is not re-queued since it would fail again
+	dp.EXPECT().UpdatePolicy(gomock.Any()).Return(fmt.Errorf("policy x: %w", policies.ErrUnsupportedNFTablesAudit)).Times(1)
+
+	addNetPol(f, netPolObj)
+	testCases := []expectedNetPolValues{
+		{0, 0, netPolPromVals{0, 0, 0, 0}},
+	}
+	checkNetPolTestResult("TestAddAuditOnlyNetworkPolicyWithNFTables", f, testCases)
+	require.NotContains(t, f.netPolController.auditNpMap, "test-nwpolicy/allow-ingress")
+}
+
+func TestAuditOnlyAnnotationUpdateNetworkPolicy(t *testing.T) {
+	if util.IsWindowsDP() {
+		t.Skip("audit-only network policies are not supported on windows")
+	}
+	oldNetPolObj := createNetPol()
+
+	f := newNetPolFixture(t)
+	f.netPolLister = append(f.netPolLister, oldNetPolObj)
+	f.kubeobjects = append(f.kubeobjects, oldNetPolObj)
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dp := dpmocks.NewMockGenericDataplane(ctrl)
+	f.newNetPolController(stopCh, dp)
+
+	// only the annotation changes, but the policy must be applied again as audit-only
+	newNetPolObj := oldNetPolObj.DeepCopy()
+	newNetPolObj.Annotations = map[string]string{translation.AuditOnlyAnnotation: "true"}
+	newRV, _ := strconv.Atoi(oldNetPolObj.ResourceVersion)
+	newNetPolObj.ResourceVersion = fmt.Sprintf("%d", newRV+1)
+	gomock.InOrder(
+		dp.EXPECT().UpdatePolicy(gomock.Any()).DoAndReturn(func(policy *policies.NPMNetworkPolicy) error {
+			require.False(t, policy.AuditOnly)
+			return nil
+		}),
+		dp.EXPECT().UpdatePolicy(gomock.Any()).DoAndReturn(func(policy *policies.NPMNetworkPolicy) error {
+			require.True(t, policy.AuditOnly)
+			return nil
+		}),
+	)
+
+	updateNetPol(t, f, oldNetPolObj, newNetPolObj)
+
+	testCases := []expectedNetPolValues{
+		{1, 0, netPolPromVals{1, 1, 1, 0}},
+	}
+	checkNetPolTestResult("TestAuditOnlyAnnotationUpdateNetworkPolicy", f, testCases)
+	require.Contains(t, f.netPolController.auditNpMap, "test-nwpolicy/allow-ingress")
+}
@@ -44,6 +44,8 @@ type PodController struct {
 	workqueue workqueue.RateLimitingInterface
 	dp        dataplane.GenericDataplane
 	podMap    map[string]*common.NpmPod // Key is <nsname>/<podname>
+	// podKeyByIP indexes podMap by the IP of the last synced pod with the IP, for looking up the pods of flow logs
+	podKeyByIP map[string]string
 	sync.RWMutex
 	npmNamespaceCache *NpmNamespaceCache
 }
@@ -54,6 +56,7 @@ func NewPodController(podInformer coreinformer.PodInformer, dp dataplane.Generic
 		workqueue:         workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "Pods"),
 		dp:                dp,
 		podMap:            make(map[string]*common.NpmPod),
+		podKeyByIP:        make(map[string]string),
 		npmNamespaceCache: npmNamespaceCache,
 	}
 
@@ -83,6 +86,18 @@ func (c *PodController) LengthOfPodMap() int {
 	return len(c.podMap)
 }
 
+// GetPodByIP returns the namespace and name of the cached Pod with the IP.
+func (c *PodController) GetPodByIP(podIP string) (namespace, name string, ok bool) {
+	c.RLock()
+	defer c.RUnlock()
+
+	npmPod, ok := c.podMap[c.podKeyByIP[podIP]]
+	if !ok {
+		return "", "", false
+	}
+	return npmPod.Namespace, npmPod.Name, true
+}
+
 // needSync filters the event if the event is not required to handle
 func (c *PodController) needSync(eventType string, obj interface{}) (string, bool) {
 	needSync := false
@@ -377,6 +392,7 @@ func (c *PodController) syncAddedPod(podObj *corev1.Pod) error {
 	// Create npmPod and add it to the podMap
 	npmPodObj := common.NewNpmPod(podObj)
 	c.podMap[podKey] = npmPodObj
+	c.podKeyByIP[npmPodObj.PodIP] = podKey
 	metrics.AddPod()
 
 	// Get lists of podLabelKey and podLabelKey + podLavelValue ,and then start adding them to ipsets.
@@ -582,6 +598,10 @@ func (c *PodController) cleanUpDeletedPod(cachedNpmPodKey string) error {
 
 	metrics.RemovePod()
 	delete(c.podMap, cachedNpmPodKey)
+	// a newer pod may have reused the IP
+	if c.podKeyByIP[cachedNpmPod.PodIP] == cachedNpmPodKey {
+		delete(c.podKeyByIP, cachedNpmPod.PodIP)
+	}
 	return nil
 }
 
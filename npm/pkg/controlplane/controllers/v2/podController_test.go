@@ -1039,6 +1039,47 @@ func TestPodMapMarshalJSON(t *testing.T) {
 	assert.ElementsMatch(t, expect, npMapRaw)
 }
 
+func TestGetPodByIP(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dp := dpmocks.NewMockGenericDataplane(ctrl)
+	f := newFixture(t, dp)
+	oldPod := createPod("old-pod", "test-namespace", "0", "1.2.3.4", map[string]string{}, NonHostNetwork, corev1.PodRunning)
+	newPod := createPod("new-pod", "test-namespace", "0", "1.2.3.4", map[string]string{}, NonHostNetwork, corev1.PodRunning)
+	f.podLister = append(f.podLister, oldPod, newPod)
+	f.kubeobjects = append(f.kubeobjects, oldPod, newPod)
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	f.newPodController(stopCh)
+
+	dp.EXPECT().AddToLists(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	dp.EXPECT().AddToSets(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	dp.EXPECT().RemoveFromSets(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	dp.EXPECT().ApplyDataPlane().Return(nil).AnyTimes()
+
+	addPod(t, f, oldPod)
+	namespace, name, ok := f.podController.GetPodByIP("1.2.3.4")
+	assert.True(t, ok)
+	assert.Equal(t, "test-namespace", namespace)
+	assert.Equal(t, "old-pod", name)
+
+	_, _, ok = f.podController.GetPodByIP("1.2.3.5")
+	assert.False(t, ok)
+
+	// the new pod reuses the IP before the old pod is deleted
+	addPod(t, f, newPod)
+	deletePod(t, f, oldPod, DeletedFinalStateknownObject)
+	_, name, ok = f.podController.GetPodByIP("1.2.3.4")
+	assert.True(t, ok)
+	assert.Equal(t, "new-pod", name)
+
+	deletePod(t, f, newPod, DeletedFinalStateknownObject)
+	_, _, ok = f.podController.GetPodByIP("1.2.3.4")
+	assert.False(t, ok)
+	assert.Empty(t, f.podController.podKeyByIP)
+}
+
 func TestHasValidPodIP(t *testing.T) {
 	podObj := &corev1.Pod{
 		Status: corev1.PodStatus{
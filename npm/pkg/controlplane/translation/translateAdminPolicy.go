@@ -0,0 +1,317 @@
+package translation
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Azure/azure-container-networking/npm/pkg/apis/policy/v1alpha1"
+	"github.com/Azure/azure-container-networking/npm/pkg/dataplane/ipsets"
+	"github.com/Azure/azure-container-networking/npm/pkg/dataplane/policies"
+	"github.com/Azure/azure-container-networking/npm/util"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var (
+	// ErrUnsupportedAdminPolicy is returned when an AdminNetworkPolicy or BaselineAdminNetworkPolicy is translated in windows.
+	ErrUnsupportedAdminPolicy = errors.New("unsupported admin network policies on windows")
+	// ErrUnsupportedNodesPeer is returned when an admin policy has a nodes peer.
+	ErrUnsupportedNodesPeer = errors.New("unsupported nodes peer in admin network policy")
+	// ErrUnsupportedSubject is returned when an admin policy subject can't be matched by one set of ipsets,
+	// e.g. a namespace selector with an In operator and several values.
+	ErrUnsupportedSubject = errors.New("unsupported subject in admin network policy")
+	errEmptySubject       = errors.New("admin network policy subject has no namespaces or pods")
+)
+
+const (
+	adminPolicyKeyPrefix    = "AdminNetworkPolicy"
+	baselinePolicyKeyPrefix = "BaselineAdminNetworkPolicy"
+	adminCIDRSetNameFormat  = "%s-%s-%d-%d%s"
+	anpSetNamePrefix        = "anp"
+	banpSetNamePrefix       = "banp"
+)
+
+// AdminPolicyKey returns the policy key of an AdminNetworkPolicy.
+// It can't clash with the key of a NetworkPolicy since namespace names are lowercase.
+func AdminPolicyKey(name string) string {
+	return fmt.Sprintf("%s/%s", adminPolicyKeyPrefix, name)
+}
+
+// BaselineAdminPolicyKey returns the policy key of a BaselineAdminNetworkPolicy.
+func BaselineAdminPolicyKey(name string) string {
+	return fmt.Sprintf("%s/%s", baselinePolicyKeyPrefix, name)
+}
+
+// adminRule is an ingress or egress rule of an AdminNetworkPolicy or BaselineAdminNetworkPolicy.
+type adminRule struct {
+	target    policies.Verdict
+	direction policies.Direction
+	peers     []v1alpha1.AdminNetworkPolicyEgressPeer
+	ports     *[]v1alpha1.AdminNetworkPolicyPort
+}
+
+// TranslateAdminPolicy translates an AdminNetworkPolicy to an NPMNetworkPolicy of the AdminTier.
+// The ACLs keep the order of the rules since the first matching rule decides.
+func TranslateAdminPolicy(anp *v1alpha1.AdminNetworkPolicy) (*policies.NPMNetworkPolicy, error) {
+	rules := make([]adminRule, 0, len(anp.Spec.Ingress)+len(anp.Spec.Egress))
+	for _, rule := range anp.Spec.Ingress {
+		rules = append(rules, adminRule{adminTarget(rule.Action), policies.Ingress, ingressPeers(rule.From), rule.Ports})
+	}
+	for _, rule := range anp.Spec.Egress {
+		rules = append(rules, adminRule{adminTarget(rule.Action), policies.Egress, rule.To, rule.Ports})
+	}
+
+	npmNetPol := &policies.NPMNetworkPolicy{
+		PolicyKey: AdminPolicyKey(anp.Name),
+		Tier:      policies.AdminTier,
+		Priority:  anp.Spec.Priority,
+	}
+	if err := translateAdminPolicy(npmNetPol, anpSetNamePrefix, anp.Name, &anp.Spec.Subject, rules); err != nil {
+		return nil, err
+	}
+	return npmNetPol, nil
+}
+
+// TranslateBaselineAdminPolicy translates a BaselineAdminNetworkPolicy to an NPMNetworkPolicy of the BaselineTier.
+// The ACLs keep the order of the rules since the first matching rule decides.
+func TranslateBaselineAdminPolicy(banp *v1alpha1.BaselineAdminNetworkPolicy) (*policies.NPMNetworkPolicy, error) {
+	rules := make([]adminRule, 0, len(banp.Spec.Ingress)+len(banp.Spec.Egress))
+	for _, rule := range banp.Spec.Ingress {
+		rules = append(rules, adminRule{baselineTarget(rule.Action), policies.Ingress, ingressPeers(rule.From), rule.Ports})
+	}
+	for _, rule := range banp.Spec.Egress {
+		rules = append(rules, adminRule{baselineTarget(rule.Action), policies.Egress, rule.To, rule.Ports})
+	}
+
+	npmNetPol := &policies.NPMNetworkPolicy{
+		PolicyKey: BaselineAdminPolicyKey(banp.Name),
+		Tier:      policies.BaselineTier,
+	}
+	if err := translateAdminPolicy(npmNetPol, banpSetNamePrefix, banp.Name, &banp.Spec.Subject, rules); err != nil {
+		return nil, err
+	}
+	return npmNetPol, nil
+}
+
+func adminTarget(action v1alpha1.AdminNetworkPolicyRuleAction) policies.Verdict {
+	switch action {
+	case v1alpha1.AdminNetworkPolicyRuleActionAllow:
+		return policies.Allowed
+	case v1alpha1.AdminNetworkPolicyRuleActionPass:
+		return policies.Passed
+	default:
+		return policies.Dropped
+	}
+}
+
+func baselineTarget(action v1alpha1.BaselineAdminNetworkPolicyRuleAction) policies.Verdict {
+	if action == v1alpha1.BaselineAdminNetworkPolicyRuleActionAllow {
+		return policies.Allowed
+	}
+	return policies.Dropped
+}
+
+// ingressPeers converts ingress peers to egress peers, which have a superset of the fields.
+func ingressPeers(from []v1alpha1.AdminNetworkPolicyIngressPeer) []v1alpha1.AdminNetworkPolicyEgressPeer {
+	peers := make([]v1alpha1.AdminNetworkPolicyEgressPeer, 0, len(from))
+	for _, peer := range from {
+		peers = append(peers, v1alpha1.AdminNetworkPolicyEgressPeer{Namespaces: peer.Namespaces, Pods: peer.Pods})
+	}
+	return peers
+}
+
+func translateAdminPolicy(npmNetPol *policies.NPMNetworkPolicy, setNamePrefix, name string, subject *v1alpha1.AdminNetworkPolicySubject, rules []adminRule) error {
+	if util.IsWindowsDP() {
+		return ErrUnsupportedAdminPolicy
+	}
+
+	psResult, err := adminSubject(npmNetPol.PolicyKey, subject)
+	if err != nil {
+		return err
+	}
+	npmNetPol.PodSelectorIPSets = psResult.psSets
+	npmNetPol.ChildPodSelectorIPSets = psResult.childPSSets
+	npmNetPol.PodSelectorList = psResult.psList
+
+	for ruleIndex, rule := range rules {
+		matchType := policies.SrcMatch
+		if rule.direction == policies.Egress {
+			matchType = policies.DstMatch
+		}
+
+		// each peer is a different set of SetInfos, and the rule matches if any of them match
+		var peerSetInfos [][]policies.SetInfo
+		for peerIndex, peer := range rule.peers {
+			setInfos, err := adminPeer(npmNetPol, setNamePrefix, name, rule.direction, matchType, ruleIndex, peerIndex, &peer)
+			if err != nil {
+				return err
+			}
+			peerSetInfos = append(peerSetInfos, setInfos...)
+		}
+
+		if err := adminPeerAndPortRule(npmNetPol, rule, peerSetInfos); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// adminSubject translates the subject like the podSelector of a NetworkPolicy, except that the namespaces are selected by labels.
+func adminSubject(policyKey string, subject *v1alpha1.AdminNetworkPolicySubject) (*podSelectorResult, error) {
+	var nsSelector *metav1.LabelSelector
+	psResult := &podSelectorResult{}
+	switch {
+	case subject.Namespaces != nil:
+		nsSelector = subject.Namespaces
+	case subject.Pods != nil:
+		var err error
+		psResult, err = podSelector(policyKey, policies.EitherMatch, &subject.Pods.PodSelector)
+		if err != nil {
+			return nil, err
+		}
+		nsSelector = &subject.Pods.NamespaceSelector
+	default:
+		return nil, errEmptySubject
+	}
+
+	flattenNSSelector, err := flattenNameSpaceSelector(nsSelector)
+	if err != nil {
+		return nil, err
+	}
+	if len(flattenNSSelector) != 1 {
+		// the pod selector list can't match one of several namespace selectors
+		return nil, ErrUnsupportedSubject
+	}
+	nsSelectorIPSets, nsSelectorList := nameSpaceSelector(policies.EitherMatch, &flattenNSSelector[0])
+	psResult.psSets = append(psResult.psSets, nsSelectorIPSets...)
+	psResult.psList = append(psResult.psList, nsSelectorList...)
+	return psResult, nil
+}
+
+// adminPeer translates a peer to the sets of SetInfos that match it, and adds the ipsets to the RuleIPSets.
+func adminPeer(npmNetPol *policies.NPMNetworkPolicy, setNamePrefix, name string, direction policies.Direction, matchType policies.MatchType,
+	ruleIndex, peerIndex int, peer *v1alpha1.AdminNetworkPolicyEgressPeer,
+) ([][]policies.SetInfo, error) {
+	if peer.Nodes != nil {
+		return nil, ErrUnsupportedNodesPeer
+	}
+
+	if len(peer.Networks) > 0 {
+		cidrIPSet, err := adminCIDRIPSet(setNamePrefix, name, direction, ruleIndex, peerIndex, peer.Networks)
+		if err != nil {
+			return nil, err
+		}
+		npmNetPol.RuleIPSets = append(npmNetPol.RuleIPSets, cidrIPSet)
+		setInfo := policies.NewSetInfo(cidrIPSet.Metadata.Name, ipsets.CIDRBlocks, included, matchType)
+		return [][]policies.SetInfo{{setInfo}}, nil
+	}
+
+	var nsSelector *metav1.LabelSelector
+	var psList []policies.SetInfo
+	switch {
+	case peer.Namespaces != nil:
+		nsSelector = peer.Namespaces
+	case peer.Pods != nil:
+		psResult, err := podSelector(npmNetPol.PolicyKey, matchType, &peer.Pods.PodSelector)
+		if err != nil {
+			return nil, err
+		}
+		npmNetPol.RuleIPSets = append(npmNetPol.RuleIPSets, psResult.psSets...)
+		npmNetPol.RuleIPSets = append(npmNetPol.RuleIPSets, psResult.childPSSets...)
+		psList = psResult.psList
+		nsSelector = &peer.Pods.NamespaceSelector
+	default:
+		return nil, nil
+	}
+
+	// Before translating NamespaceSelector, flattenNameSpaceSelector function call should be called
+	// to handle multiple values in matchExpressions spec.
+	flattenNSSelector, err := flattenNameSpaceSelector(nsSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	setInfos := make([][]policies.SetInfo, 0, len(flattenNSSelector))
+	for i := range flattenNSSelector {
+		nsSelectorIPSets, nsSelectorList := nameSpaceSelector(matchType, &flattenNSSelector[i])
+		npmNetPol.RuleIPSets = append(npmNetPol.RuleIPSets, nsSelectorIPSets...)
+		setInfos = append(setInfos, append(nsSelectorList, psList...))
+	}
+	return setInfos, nil
+}
+
+// adminCIDRIPSet returns the ipset with the networks of a peer.
+// The policy name is prefixed so that the ipsets of an AdminNetworkPolicy and the BaselineAdminNetworkPolicy with the same name differ.
+func adminCIDRIPSet(setNamePrefix, name string, direction policies.Direction, ruleIndex, peerIndex int, networks []v1alpha1.CIDR) (*ipsets.TranslatedIPSet, error) {
+	members := make([]string, 0, len(networks))
+	for _, network := range networks {
+		cidr := string(network)
+		if !util.IsIPV4(cidr) {
+			return nil, ErrUnsupportedIPAddress
+		}
+		if cidr == "0.0.0.0/0" {
+			// Ipset doesn't allow 0.0.0.0/0 to be added.
+			members = append(members, "0.0.0.0/1", "128.0.0.0/1")
+			continue
+		}
+		members = append(members, cidr)
+	}
+
+	setName := fmt.Sprintf(adminCIDRSetNameFormat, setNamePrefix, name, ruleIndex, peerIndex, direction)
+	return ipsets.NewTranslatedIPSet(setName, ipsets.CIDRBlocks, members...), nil
+}
+
+// adminPeerAndPortRule adds an ACL for each combination of peer and port of the rule.
+func adminPeerAndPortRule(npmNetPol *policies.NPMNetworkPolicy, rule adminRule, peerSetInfos [][]policies.SetInfo) error {
+	var ports []v1alpha1.AdminNetworkPolicyPort
+	if rule.ports != nil {
+		ports = *rule.ports
+	}
+
+	for _, setInfos := range peerSetInfos {
+		if len(ports) == 0 {
+			acl := policies.NewACLPolicy(rule.target, rule.direction)
+			acl.AddSetInfo(setInfos)
+			npmNetPol.ACLs = append(npmNetPol.ACLs, acl)
+			continue
+		}
+
+		for i := range ports {
+			acl := policies.NewACLPolicy(rule.target, rule.direction)
+			acl.AddSetInfo(setInfos)
+			if err := adminPortRule(npmNetPol, acl, &ports[i]); err != nil {
+				return err
+			}
+			npmNetPol.ACLs = append(npmNetPol.ACLs, acl)
+		}
+	}
+	return nil
+}
+
+// adminPortRule restricts the ACL to the port. A port always applies to the destination.
+func adminPortRule(npmNetPol *policies.NPMNetworkPolicy, acl *policies.ACLPolicy, port *v1alpha1.AdminNetworkPolicyPort) error {
+	switch {
+	case port.PortNumber != nil:
+		acl.Protocol = adminProtocol(string(port.PortNumber.Protocol))
+		acl.DstPorts = policies.Ports{Port: port.PortNumber.Port, EndPort: port.PortNumber.Port}
+	case port.PortRange != nil:
+		acl.Protocol = adminProtocol(string(port.PortRange.Protocol))
+		acl.DstPorts = policies.Ports{Port: port.PortRange.Start, EndPort: port.PortRange.End}
+	case port.NamedPort != nil:
+		if util.IsWindowsDP() {
+			return ErrUnsupportedNamedPort
+		}
+		// the named port ipset holds the protocol of the port
+		namedPortIPSet := ipsets.NewTranslatedIPSet(*port.NamedPort, ipsets.NamedPorts)
+		npmNetPol.RuleIPSets = append(npmNetPol.RuleIPSets, namedPortIPSet)
+		acl.AddSetInfo([]policies.SetInfo{policies.NewSetInfo(*port.NamedPort, ipsets.NamedPorts, included, policies.DstDstMatch)})
+		acl.Protocol = policies.UnspecifiedProtocol
+	}
+	return nil
+}
+
+func adminProtocol(protocol string) policies.Protocol {
+	if protocol == "" {
+		return policies.TCP
+	}
+	return policies.Protocol(protocol)
+}
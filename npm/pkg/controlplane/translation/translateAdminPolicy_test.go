@@ -0,0 +1,298 @@
+package translation
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-container-networking/npm/pkg/apis/policy/v1alpha1"
+	"github.com/Azure/azure-container-networking/npm/pkg/dataplane/ipsets"
+	"github.com/Azure/azure-container-networking/npm/pkg/dataplane/policies"
+	"github.com/Azure/azure-container-networking/npm/util"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestAdminPolicyKeys(t *testing.T) {
+	require.Equal(t, "AdminNetworkPolicy/deny-kube-system", AdminPolicyKey("deny-kube-system"))
+	require.Equal(t, "BaselineAdminNetworkPolicy/default", BaselineAdminPolicyKey(v1alpha1.BaselineAdminNetworkPolicyName))
+}
+
+func TestTranslateAdminPolicy(t *testing.T) {
+	namedPort := "dns"
+	tests := []struct {
+		name      string
+		anp       *v1alpha1.AdminNetworkPolicy
+		npmNetPol *policies.NPMNetworkPolicy
+		wantErr   bool
+	}{
+		{
+			name: "namespaces subject with pass, deny and allow rules",
+			anp: &v1alpha1.AdminNetworkPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "tenants"},
+				Spec: v1alpha1.AdminNetworkPolicySpec{
+					Priority: 10,
+					Subject: v1alpha1.AdminNetworkPolicySubject{
+						Namespaces: &metav1.LabelSelector{MatchLabels: map[string]string{"tenant": "a"}},
+					},
+					Ingress: []v1alpha1.AdminNetworkPolicyIngressRule{
+						{
+							Action: v1alpha1.AdminNetworkPolicyRuleActionPass,
+							From: []v1alpha1.AdminNetworkPolicyIngressPeer{
+								{Namespaces: &metav1.LabelSelector{MatchLabels: map[string]string{"tenant": "a"}}},
+							},
+						},
+						{
+							Action: v1alpha1.AdminNetworkPolicyRuleActionDeny,
+							From: []v1alpha1.AdminNetworkPolicyIngressPeer{
+								{Namespaces: &metav1.LabelSelector{}},
+							},
+						},
+					},
+					Egress: []v1alpha1.AdminNetworkPolicyEgressRule{
+						{
+							Action: v1alpha1.AdminNetworkPolicyRuleActionAllow,
+							To: []v1alpha1.AdminNetworkPolicyEgressPeer{
+								{
+									Pods: &v1alpha1.NamespacedPod{
+										NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"kubernetes.io/metadata.name": "kube-system"}},
+										PodSelector:       metav1.LabelSelector{MatchLabels: map[string]string{"k8s-app": "kube-dns"}},
+									},
+								},
+							},
+							Ports: &[]v1alpha1.AdminNetworkPolicyPort{
+								{PortNumber: &v1alpha1.Port{Protocol: v1.ProtocolUDP, Port: 53}},
+								{PortRange: &v1alpha1.PortRange{Start: 8000, End: 8100}},
+							},
+						},
+					},
+				},
+			},
+			npmNetPol: &policies.NPMNetworkPolicy{
+				PolicyKey: "AdminNetworkPolicy/tenants",
+				Tier:      policies.AdminTier,
+				Priority:  10,
+				PodSelectorIPSets: []*ipsets.TranslatedIPSet{
+					ipsets.NewTranslatedIPSet("tenant:a", ipsets.KeyValueLabelOfNamespace),
+				},
+				PodSelectorList: []policies.SetInfo{
+					policies.NewSetInfo("tenant:a", ipsets.KeyValueLabelOfNamespace, included, policies.EitherMatch),
+				},
+				RuleIPSets: []*ipsets.TranslatedIPSet{
+					ipsets.NewTranslatedIPSet("tenant:a", ipsets.KeyValueLabelOfNamespace),
+					ipsets.NewTranslatedIPSet(util.KubeAllNamespacesFlag, ipsets.KeyLabelOfNamespace),
+					ipsets.NewTranslatedIPSet("k8s-app:kube-dns", ipsets.KeyValueLabelOfPod),
+					ipsets.NewTranslatedIPSet("kubernetes.io/metadata.name:kube-system", ipsets.KeyValueLabelOfNamespace),
+				},
+				ACLs: []*policies.ACLPolicy{
+					{
+						Target:    policies.Passed,
+						Direction: policies.Ingress,
+						SrcList: []policies.SetInfo{
+							policies.NewSetInfo("tenant:a", ipsets.KeyValueLabelOfNamespace, included, policies.SrcMatch),
+						},
+					},
+					{
+						Target:    policies.Dropped,
+						Direction: policies.Ingress,
+						SrcList: []policies.SetInfo{
+							policies.NewSetInfo(util.KubeAllNamespacesFlag, ipsets.KeyLabelOfNamespace, included, policies.SrcMatch),
+						},
+					},
+					{
+						Target:    policies.Allowed,
+						Direction: policies.Egress,
+						DstList: []policies.SetInfo{
+							policies.NewSetInfo("kubernetes.io/metadata.name:kube-system", ipsets.KeyValueLabelOfNamespace, included, policies.DstMatch),
+							policies.NewSetInfo("k8s-app:kube-dns", ipsets.KeyValueLabelOfPod, included, policies.DstMatch),
+						},
+						DstPorts: policies.Ports{Port: 53, EndPort: 53},
+						Protocol: policies.UDP,
+					},
+					{
+						Target:    policies.Allowed,
+						Direction: policies.Egress,
+						DstList: []policies.SetInfo{
+							policies.NewSetInfo("kubernetes.io/metadata.name:kube-system", ipsets.KeyValueLabelOfNamespace, included, policies.DstMatch),
+							policies.NewSetInfo("k8s-app:kube-dns", ipsets.KeyValueLabelOfPod, included, policies.DstMatch),
+						},
+						DstPorts: policies.Ports{Port: 8000, EndPort: 8100},
+						Protocol: policies.TCP,
+					},
+				},
+			},
+		},
+		{
+			name: "pods subject with networks and named port",
+			anp: &v1alpha1.AdminNetworkPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "egress"},
+				Spec: v1alpha1.AdminNetworkPolicySpec{
+					Priority: 5,
+					Subject: v1alpha1.AdminNetworkPolicySubject{
+						Pods: &v1alpha1.NamespacedPod{
+							PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+						},
+					},
+					Egress: []v1alpha1.AdminNetworkPolicyEgressRule{
+						{
+							Action: v1alpha1.AdminNetworkPolicyRuleActionDeny,
+							To: []v1alpha1.AdminNetworkPolicyEgressPeer{
+								{Networks: []v1alpha1.CIDR{"0.0.0.0/0", "10.0.0.0/8"}},
+							},
+							Ports: &[]v1alpha1.AdminNetworkPolicyPort{
+								{NamedPort: &namedPort},
+							},
+						},
+					},
+				},
+			},
+			npmNetPol: &policies.NPMNetworkPolicy{
+				PolicyKey: "AdminNetworkPolicy/egress",
+				Tier:      policies.AdminTier,
+				Priority:  5,
+				PodSelectorIPSets: []*ipsets.TranslatedIPSet{
+					ipsets.NewTranslatedIPSet("app:web", ipsets.KeyValueLabelOfPod),
+					ipsets.NewTranslatedIPSet(util.KubeAllNamespacesFlag, ipsets.KeyLabelOfNamespace),
+				},
+				ChildPodSelectorIPSets: []*ipsets.TranslatedIPSet{},
+				PodSelectorList: []policies.SetInfo{
+					policies.NewSetInfo("app:web", ipsets.KeyValueLabelOfPod, included, policies.EitherMatch),
+					policies.NewSetInfo(util.KubeAllNamespacesFlag, ipsets.KeyLabelOfNamespace, included, policies.EitherMatch),
+				},
+				RuleIPSets: []*ipsets.TranslatedIPSet{
+					ipsets.NewTranslatedIPSet("anp-egress-0-0OUT", ipsets.CIDRBlocks, "0.0.0.0/1", "128.0.0.0/1", "10.0.0.0/8"),
+					ipsets.NewTranslatedIPSet("dns", ipsets.NamedPorts),
+				},
+				ACLs: []*policies.ACLPolicy{
+					{
+						Target:    policies.Dropped,
+						Direction: policies.Egress,
+						DstList: []policies.SetInfo{
+							policies.NewSetInfo("anp-egress-0-0OUT", ipsets.CIDRBlocks, included, policies.DstMatch),
+							policies.NewSetInfo("dns", ipsets.NamedPorts, included, policies.DstDstMatch),
+						},
+						Protocol: policies.UnspecifiedProtocol,
+					},
+				},
+			},
+		},
+		{
+			name: "subject with several namespace selectors",
+			anp: &v1alpha1.AdminNetworkPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "in"},
+				Spec: v1alpha1.AdminNetworkPolicySpec{
+					Subject: v1alpha1.AdminNetworkPolicySubject{
+						Namespaces: &metav1.LabelSelector{
+							MatchExpressions: []metav1.LabelSelectorRequirement{
+								{Key: "tenant", Operator: metav1.LabelSelectorOpIn, Values: []string{"a", "b"}},
+							},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "nodes peer",
+			anp: &v1alpha1.AdminNetworkPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "nodes"},
+				Spec: v1alpha1.AdminNetworkPolicySpec{
+					Subject: v1alpha1.AdminNetworkPolicySubject{Namespaces: &metav1.LabelSelector{}},
+					Egress: []v1alpha1.AdminNetworkPolicyEgressRule{
+						{
+							Action: v1alpha1.AdminNetworkPolicyRuleActionDeny,
+							To:     []v1alpha1.AdminNetworkPolicyEgressPeer{{Nodes: &metav1.LabelSelector{}}},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "IPv6 network",
+			anp: &v1alpha1.AdminNetworkPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "ipv6"},
+				Spec: v1alpha1.AdminNetworkPolicySpec{
+					Subject: v1alpha1.AdminNetworkPolicySubject{Namespaces: &metav1.LabelSelector{}},
+					Egress: []v1alpha1.AdminNetworkPolicyEgressRule{
+						{
+							Action: v1alpha1.AdminNetworkPolicyRuleActionDeny,
+							To:     []v1alpha1.AdminNetworkPolicyEgressPeer{{Networks: []v1alpha1.CIDR{"fd00::/8"}}},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			npmNetPol, err := TranslateAdminPolicy(tt.anp)
+			if tt.wantErr || util.IsWindowsDP() {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.npmNetPol, npmNetPol)
+		})
+	}
+}
+
+func TestTranslateBaselineAdminPolicy(t *testing.T) {
+	banp := &v1alpha1.BaselineAdminNetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: v1alpha1.BaselineAdminNetworkPolicyName},
+		Spec: v1alpha1.BaselineAdminNetworkPolicySpec{
+			Subject: v1alpha1.AdminNetworkPolicySubject{Namespaces: &metav1.LabelSelector{}},
+			Ingress: []v1alpha1.BaselineAdminNetworkPolicyIngressRule{
+				{
+					Action: v1alpha1.BaselineAdminNetworkPolicyRuleActionAllow,
+					From: []v1alpha1.AdminNetworkPolicyIngressPeer{
+						{Namespaces: &metav1.LabelSelector{MatchLabels: map[string]string{"kubernetes.io/metadata.name": "monitoring"}}},
+					},
+				},
+				{
+					Action: v1alpha1.BaselineAdminNetworkPolicyRuleActionDeny,
+					From:   []v1alpha1.AdminNetworkPolicyIngressPeer{{Namespaces: &metav1.LabelSelector{}}},
+				},
+			},
+		},
+	}
+
+	npmNetPol, err := TranslateBaselineAdminPolicy(banp)
+	if util.IsWindowsDP() {
+		require.Error(t, err)
+		return
+	}
+	require.NoError(t, err)
+	require.Equal(t, &policies.NPMNetworkPolicy{
+		PolicyKey: "BaselineAdminNetworkPolicy/default",
+		Tier:      policies.BaselineTier,
+		PodSelectorIPSets: []*ipsets.TranslatedIPSet{
+			ipsets.NewTranslatedIPSet(util.KubeAllNamespacesFlag, ipsets.KeyLabelOfNamespace),
+		},
+		PodSelectorList: []policies.SetInfo{
+			policies.NewSetInfo(util.KubeAllNamespacesFlag, ipsets.KeyLabelOfNamespace, included, policies.EitherMatch),
+		},
+		RuleIPSets: []*ipsets.TranslatedIPSet{
+			ipsets.NewTranslatedIPSet("kubernetes.io/metadata.name:monitoring", ipsets.KeyValueLabelOfNamespace),
+			ipsets.NewTranslatedIPSet(util.KubeAllNamespacesFlag, ipsets.KeyLabelOfNamespace),
+		},
+		ACLs: []*policies.ACLPolicy{
+			{
+				Target:    policies.Allowed,
+				Direction: policies.Ingress,
+				SrcList: []policies.SetInfo{
+					policies.NewSetInfo("kubernetes.io/metadata.name:monitoring", ipsets.KeyValueLabelOfNamespace, included, policies.SrcMatch),
+				},
+			},
+			{
+				Target:    policies.Dropped,
+				Direction: policies.Ingress,
+				SrcList: []policies.SetInfo{
+					policies.NewSetInfo(util.KubeAllNamespacesFlag, ipsets.KeyLabelOfNamespace, included, policies.SrcMatch),
+				},
+			},
+		},
+	}, npmNetPol)
+}
@@ -28,6 +28,8 @@ var (
 	ErrUnsupportedExceptCIDR = errors.New("unsupported Except CIDR block translation features used on windows")
 	// ErrUnsupportedSCTP is returned when SCTP protocol is used in windows.
 	ErrUnsupportedSCTP = errors.New("unsupported SCTP protocol used on windows")
+	// ErrUnsupportedAuditOnly is returned when an audit-only NetworkPolicy is translated in windows.
+	ErrUnsupportedAuditOnly = errors.New("unsupported audit-only network policy on windows")
 	// ErrInvalidMatchExpressionValues ensures proper matchExpression label values since k8s doesn't perform this check.
 	ErrInvalidMatchExpressionValues = errors.New(
 		"matchExpression label values must be an empty string or consist of alphanumeric characters, '-', '_' or '.', and must start and end with an alphanumeric character",
@@ -36,6 +38,11 @@ var (
 	ErrUnsupportedIPAddress = errors.New("unsupported IP address")
 )
 
+// AuditOnlyAnnotation makes NPM count the traffic that a NetworkPolicy would deny instead of enforcing the policy.
+// It is only honored when its value is "true". The traffic is counted after every enforced NetworkPolicy,
+// so traffic allowed by an enforced NetworkPolicy is never counted.
+const AuditOnlyAnnotation = "npm.azure.com/audit-only"
+
 type podSelectorResult struct {
 	psSets      []*ipsets.TranslatedIPSet
 	childPSSets []*ipsets.TranslatedIPSet
@@ -610,6 +617,13 @@ func TranslatePolicy(npObj *networkingv1.NetworkPolicy) (*policies.NPMNetworkPol
 		}
 	}
 
+	if IsAuditOnly(npObj) {
+		if util.IsWindowsDP() {
+			return nil, ErrUnsupportedAuditOnly
+		}
+		npmNetPol.AuditOnly = true
+	}
+
 	// ad-hoc validation to reduce code changes (modifying function signatures and returning errors in all the correct places)
 	if util.IsWindowsDP() {
 		for _, acl := range npmNetPol.ACLs {
@@ -620,3 +634,8 @@ func TranslatePolicy(npObj *networkingv1.NetworkPolicy) (*policies.NPMNetworkPol
 	}
 	return npmNetPol, nil
 }
+
+// IsAuditOnly returns true if the NetworkPolicy has the AuditOnlyAnnotation.
+func IsAuditOnly(npObj *networkingv1.NetworkPolicy) bool {
+	return npObj.Annotations[AuditOnlyAnnotation] == "true"
+}
@@ -2919,3 +2919,30 @@ func TestEgressPolicy(t *testing.T) {
 		})
 	}
 }
+
+func TestTranslateAuditOnlyPolicy(t *testing.T) {
+	npObj := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "deny-all",
+			Namespace:   defaultNS,
+			Annotations: map[string]string{AuditOnlyAnnotation: "true"},
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+		},
+	}
+
+	npmNetPol, err := TranslatePolicy(npObj)
+	if util.IsWindowsDP() {
+		require.ErrorIs(t, err, ErrUnsupportedAuditOnly)
+		return
+	}
+	require.NoError(t, err)
+	require.True(t, npmNetPol.AuditOnly)
+	require.Equal(t, []*policies.ACLPolicy{defaultDropACL(policies.Ingress)}, npmNetPol.ACLs)
+
+	npObj.Annotations[AuditOnlyAnnotation] = "false"
+	npmNetPol, err = TranslatePolicy(npObj)
+	require.NoError(t, err)
+	require.False(t, npmNetPol.AuditOnly)
+}
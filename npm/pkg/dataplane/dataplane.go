@@ -43,6 +43,9 @@ type Config struct {
 	NetPolInBackground bool
 	MaxPendingNetPols  int
 	NetPolInterval     time.Duration
+	// EnableNFTables applies IPSets and NetPols as a single nftables table instead of with ipset and iptables.
+	// It only affects Linux.
+	EnableNFTables bool
 	*ipsets.IPSetManagerCfg
 	*policies.PolicyManagerCfg
 }
@@ -90,6 +93,10 @@ func NewDataPlane(nodeName string, ioShim *common.IOShim, cfg *Config, stopChann
 		stopChannel: stopChannel,
 	}
 
+	if cfg.EnableNFTables {
+		dp.useNFTables()
+	}
+
 	// do not let Linux apply in background
 	dp.applyInBackground = cfg.ApplyInBackground && util.IsWindowsDP()
 	if dp.applyInBackground {
@@ -109,9 +116,9 @@ func NewDataPlane(nodeName string, ioShim *common.IOShim, cfg *Config, stopChann
 		return nil, err
 	}
 
-	// Prevent netpol in background unless we're in Linux and using nftables.
+	// Prevent netpol in background unless we're in Linux and using nftables (through iptables-nft or natively).
 	// This step must be performed after bootupDataplane() because it calls util.DetectIptablesVersion(), which sets the proper value for util.Iptables
-	dp.netPolInBackground = cfg.NetPolInBackground && !util.IsWindowsDP() && (strings.Contains(util.Iptables, "nft") || dp.debug)
+	dp.netPolInBackground = cfg.NetPolInBackground && !util.IsWindowsDP() && (strings.Contains(util.Iptables, "nft") || cfg.EnableNFTables || dp.debug)
 	if dp.netPolInBackground {
 		msg := fmt.Sprintf("[DataPlane] dataplane configured to add netpols in background every %v or every %d calls to AddPolicy()", dp.NetPolInterval, dp.MaxPendingNetPols)
 		metrics.SendLog(util.DaemonDataplaneID, msg, true)
@@ -393,6 +400,10 @@ func (dp *DataPlane) applyDataPlaneNow(context string) error {
 func (dp *DataPlane) AddPolicy(policy *policies.NPMNetworkPolicy) error {
 	klog.Infof("[DataPlane] Add Policy called for %s", policy.PolicyKey)
 
+	if err := dp.validatePolicy(policy); err != nil {
+		return err
+	}
+
 	if !dp.netPolInBackground {
 		return dp.addPolicies([]*policies.NPMNetworkPolicy{policy})
 	}
@@ -414,6 +425,15 @@ func (dp *DataPlane) AddPolicy(policy *policies.NPMNetworkPolicy) error {
 	return nil
 }
 
+// validatePolicy fails for a policy which the dataplane can never apply, before any IPSet references are added
+// or the policy is queued.
+func (dp *DataPlane) validatePolicy(policy *policies.NPMNetworkPolicy) error {
+	if dp.EnableNFTables && policy.AuditOnly {
+		return fmt.Errorf("[DataPlane] policy %s: %w", policy.PolicyKey, policies.ErrUnsupportedNFTablesAudit)
+	}
+	return nil
+}
+
 // addPoliciesWithRetry tries adding all policies. If this fails, it tries adding policies one by one.
 // The caller must lock netPolQueue.
 func (dp *DataPlane) addPoliciesWithRetry(context string) {
@@ -610,6 +630,12 @@ func (dp *DataPlane) RemovePolicy(policyKey string) error {
 // onto dataplane accordingly
 func (dp *DataPlane) UpdatePolicy(policy *policies.NPMNetworkPolicy) error {
 	klog.Infof("[DataPlane] Update Policy called for %s", policy.PolicyKey)
+
+	// keep the existing policy if the updated one can never be applied
+	if err := dp.validatePolicy(policy); err != nil {
+		return err
+	}
+
 	ok := dp.policyMgr.PolicyExists(policy.PolicyKey)
 	if !ok {
 		klog.Infof("[DataPlane] Policy %s is not found.", policy.PolicyKey)
@@ -641,6 +667,11 @@ func (dp *DataPlane) GetAllPolicies() []string {
 	return nil
 }
 
+// GetPolicyKeyByHash returns the key of the applied policy whose key has the hash (see util.Hash).
+func (dp *DataPlane) GetPolicyKeyByHash(hash string) (string, bool) {
+	return dp.policyMgr.GetPolicyKeyByHash(hash)
+}
+
 func (dp *DataPlane) createIPSetsAndReferences(sets []*ipsets.TranslatedIPSet, netpolName string, referenceType ipsets.ReferenceType) error {
 	// Create IPSets first along with reference updates
 	npmErrorString := npmerrors.AddSelectorReference
@@ -1,9 +1,11 @@
 package dataplane
 
 import (
+	"github.com/Azure/azure-container-networking/npm/pkg/dataplane/nftables"
 	"github.com/Azure/azure-container-networking/npm/pkg/dataplane/policies"
 	"github.com/Azure/azure-container-networking/npm/util"
 	npmerrors "github.com/Azure/azure-container-networking/npm/util/errors"
+	"k8s.io/klog"
 )
 
 func (dp *DataPlane) getEndpointsToApplyPolicies(_ []*policies.NPMNetworkPolicy) (map[string]string, error) {
@@ -20,8 +22,18 @@ func (dp *DataPlane) updatePod(pod *updateNPMPod) error {
 	return nil
 }
 
+// useNFTables makes the IPSetManager and PolicyManager share a single nftables table.
+func (dp *DataPlane) useNFTables() {
+	klog.Infof("[DataPlane] using nftables table %s %s instead of iptables and ipset", nftables.Family, nftables.TableName)
+	ruleset := nftables.NewRuleset(dp.ioShim, dp.PlaceAzureChainFirst)
+	dp.ipsetMgr.UseNFTables(ruleset)
+	dp.policyMgr.UseNFTables(ruleset)
+}
+
 func (dp *DataPlane) bootupDataPlane() error {
-	util.DetectIptablesVersion(dp.ioShim)
+	if !dp.EnableNFTables {
+		util.DetectIptablesVersion(dp.ioShim)
+	}
 
 	// It is important to keep order to clean-up ACLs before ipsets. Otherwise we won't be able to delete ipsets referenced by ACLs
 	if err := dp.policyMgr.Bootup(nil); err != nil {
@@ -175,3 +175,47 @@ func TestNetPolInBackgroundFailureToAddFirstTime(t *testing.T) {
 
 	require.Equal(t, 1, dp.netPolQueue.len(), "expected one netpol to still be in the queue after it fails when adding one at a time")
 }
+
+func TestNFTablesDataPlane(t *testing.T) {
+	metrics.ReinitializeAll()
+
+	nftCfg := *netpolInBackgroundCfg
+	nftCfg.EnableNFTables = true
+
+	// bootup removes what NPM left in iptables and ipset, then ipsets and policies are each applied in one transaction.
+	// The sets of the new table are empty already, so resetting them doesn't run nft.
+	nftCall := testutils.TestCmd{Cmd: []string{"nft", "-f", "-"}}
+	calls := []testutils.TestCmd{nftCall}
+	for _, iptables := range []string{util.IptablesNft, util.IptablesLegacy} {
+		calls = append(calls,
+			testutils.TestCmd{Cmd: []string{iptables, "-w", "60", "-D", "FORWARD", "-j", "AZURE-NPM"}, ExitCode: 2},
+			testutils.TestCmd{Cmd: []string{iptables, "-w", "60", "-D", "FORWARD", "-j", "AZURE-NPM", "-m", "conntrack", "--ctstate", "NEW"}, ExitCode: 2},
+			testutils.TestCmd{Cmd: []string{iptables, "-w", "60", "-t", "filter", "-n", "-L"}, PipedToCommand: true},
+			testutils.TestCmd{Cmd: []string{"grep", "Chain AZURE-NPM"}, ExitCode: 1},
+		)
+	}
+	calls = append(calls, ipsets.GetResetTestCalls()...)
+	calls = append(calls, nftCall, nftCall)
+	ioshim := common.NewMockIOShim(calls)
+	defer ioshim.VerifyCalls(t, calls)
+	dp, err := NewDataPlane("testnode", ioshim, &nftCfg, nil)
+	require.NoError(t, err)
+
+	dp.RunPeriodicTasks()
+
+	// audit-only policies are rejected before touching the dataplane
+	auditPolicy := testPolicyobj
+	auditPolicy.PolicyKey = "testns/audit"
+	auditPolicy.AuditOnly = true
+	require.ErrorIs(t, dp.AddPolicy(&auditPolicy), policies.ErrUnsupportedNFTablesAudit)
+	require.ErrorIs(t, dp.UpdatePolicy(&auditPolicy), policies.ErrUnsupportedNFTablesAudit)
+
+	require.NoError(t, dp.AddPolicy(&testPolicyobj))
+
+	time.Sleep(100 * time.Millisecond)
+
+	_, ok := dp.policyMgr.GetPolicy(testPolicyobj.PolicyKey)
+	require.True(t, ok)
+	// iptables isn't used
+	linuxPromVals{0, 0, 0, 0, 0}.assert(t)
+}
@@ -91,6 +91,11 @@ func (dp *DataPlane) getNetworkInfo() error {
 	return fmt.Errorf("failed to get network info after %d retries with err %w", maxNoNetRetryCount, err)
 }
 
+// useNFTables does nothing since nftables only exists in Linux.
+func (dp *DataPlane) useNFTables() {
+	klog.Infof("[DataPlane] ignoring EnableNFTables in Windows")
+}
+
 func (dp *DataPlane) bootupDataPlane() error {
 	// initialize the DP so the podendpoints will get updated.
 	if err := dp.initializeDataPlane(); err != nil {
@@ -0,0 +1,562 @@
+package debug
+
+import (
+	"fmt"
+	"net/netip"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Azure/azure-container-networking/npm/pkg/controlplane/translation"
+	"github.com/Azure/azure-container-networking/npm/pkg/dataplane/ipsets"
+	"github.com/Azure/azure-container-networking/npm/pkg/dataplane/policies"
+	"github.com/Azure/azure-container-networking/npm/util"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog"
+)
+
+// Verdicts in a ConnectivityMatrix.
+const (
+	Allowed = "."
+	Denied  = "X"
+	// PartiallyDenied is for a CIDR with some denied addresses
+	PartiallyDenied = "~"
+)
+
+var (
+	ErrNoPorts          = fmt.Errorf("no ports in the NetworkPolicies or pods")
+	ErrInvalidPort      = fmt.Errorf("invalid port")
+	ErrPodCIDRExhausted = fmt.Errorf("not enough addresses in the pod CIDR")
+	// ErrUnsupportedCIDR is for IPv6 CIDRs since NPM only supports IPv4
+	ErrUnsupportedCIDR = fmt.Errorf("unsupported CIDR")
+)
+
+// Port is a destination port of a protocol.
+type Port struct {
+	Protocol policies.Protocol
+	Port     int32
+}
+
+func (p Port) String() string {
+	return fmt.Sprintf("%s/%d", p.Protocol, p.Port)
+}
+
+// ParsePort parses a port of the form TCP/80. The protocol is TCP, UDP, or SCTP.
+func ParsePort(s string) (Port, error) {
+	protocol, port, ok := strings.Cut(s, "/")
+	if !ok {
+		return Port{}, fmt.Errorf("%s should be of the form TCP/80: %w", s, ErrInvalidPort)
+	}
+	p := Port{Protocol: policies.Protocol(strings.ToUpper(protocol))}
+	if p.Protocol != policies.TCP && p.Protocol != policies.UDP && p.Protocol != policies.SCTP {
+		return Port{}, fmt.Errorf("%s has unsupported protocol %s: %w", s, protocol, ErrInvalidPort)
+	}
+	number, err := strconv.ParseInt(port, Base, 32)
+	if err != nil || number < 1 || number > 65535 {
+		return Port{}, fmt.Errorf("%s has invalid port number %s: %w", s, port, ErrInvalidPort)
+	}
+	p.Port = int32(number)
+	return p, nil
+}
+
+// MatrixOptions configure ComputeConnectivityMatrix.
+type MatrixOptions struct {
+	// CIDRs are extra destinations, e.g. outside of the cluster.
+	CIDRs []netip.Prefix
+	// Ports to check. By default, the ports of the NetworkPolicies and the named ports of the pods.
+	Ports []Port
+	// PodCIDR has the addresses given to the pods without a status.podIP.
+	PodCIDR netip.Prefix
+}
+
+// ConnectivityMatrix has the verdict from each source pod to each destination pod or CIDR on each port.
+type ConnectivityMatrix struct {
+	Ports []Port
+	// Sources are pod keys (namespace/name)
+	Sources []string
+	// Destinations are pod keys followed by CIDRs
+	Destinations []string
+	// Verdicts[i][j][k] is the verdict from Sources[i] to Destinations[j] on Ports[k]
+	Verdicts [][][]string
+}
+
+// ComputeConnectivityMatrix translates the NetworkPolicies like NPM v2 and evaluates them like the Linux iptables dataplane.
+// A Namespace is assumed for each namespace without a manifest. Host network pods are ignored like in NPM.
+func ComputeConnectivityMatrix(manifests *Manifests, opts *MatrixOptions) (*ConnectivityMatrix, error) {
+	for _, cidr := range opts.CIDRs {
+		if !cidr.Addr().Is4() {
+			return nil, fmt.Errorf("CIDR %s must be IPv4: %w", cidr, ErrUnsupportedCIDR)
+		}
+	}
+
+	model, err := newConnectivityModel(manifests, opts.PodCIDR)
+	if err != nil {
+		return nil, err
+	}
+
+	ports := opts.Ports
+	if len(ports) == 0 {
+		ports = model.ports()
+		if len(ports) == 0 {
+			return nil, ErrNoPorts
+		}
+	}
+
+	matrix := &ConnectivityMatrix{Ports: ports}
+	for _, pod := range model.pods {
+		matrix.Sources = append(matrix.Sources, pod.key)
+		matrix.Destinations = append(matrix.Destinations, pod.key)
+	}
+	for _, cidr := range opts.CIDRs {
+		matrix.Destinations = append(matrix.Destinations, cidr.String())
+	}
+
+	// the ranges only depend on the sets, so they're the same from every source
+	cidrRanges := make([][]netip.Prefix, len(opts.CIDRs))
+	for i, cidr := range opts.CIDRs {
+		cidrRanges[i] = model.uniformRanges(cidr.Masked())
+	}
+
+	matrix.Verdicts = make([][][]string, len(model.pods))
+	for i, src := range model.pods {
+		matrix.Verdicts[i] = make([][]string, 0, len(matrix.Destinations))
+		for _, dst := range model.pods {
+			verdicts := make([]string, len(ports))
+			for k, port := range ports {
+				verdicts[k] = Denied
+				if model.isAllowed(src.ip, dst.ip, port) {
+					verdicts[k] = Allowed
+				}
+			}
+			matrix.Verdicts[i] = append(matrix.Verdicts[i], verdicts)
+		}
+		for _, ranges := range cidrRanges {
+			verdicts := make([]string, len(ports))
+			for k, port := range ports {
+				verdicts[k] = model.cidrVerdict(src.ip, ranges, port)
+			}
+			matrix.Verdicts[i] = append(matrix.Verdicts[i], verdicts)
+		}
+	}
+	return matrix, nil
+}
+
+// Table returns the matrix as a table with a column for each destination and a row for each source.
+// Each cell has a verdict for each port, in the order of the ports in the top left cell.
+func (matrix *ConnectivityMatrix) Table() string {
+	firstWidth := 0
+	for _, port := range matrix.Ports {
+		firstWidth = max(firstWidth, len(port.String()))
+	}
+	for _, src := range matrix.Sources {
+		firstWidth = max(firstWidth, len(src))
+	}
+	cellWidth := 2*len(matrix.Ports) - 1
+	widths := make([]int, len(matrix.Destinations))
+	for j, dst := range matrix.Destinations {
+		widths[j] = max(cellWidth, len(dst))
+	}
+
+	var sb strings.Builder
+	separator := "+" + strings.Repeat("-", firstWidth+2)
+	for _, width := range widths {
+		separator += "+" + strings.Repeat("-", width+2)
+	}
+	separator += "+\n"
+
+	sb.WriteString(separator)
+	for k, port := range matrix.Ports {
+		fmt.Fprintf(&sb, "| %-*s ", firstWidth, port.String())
+		for j, dst := range matrix.Destinations {
+			header := ""
+			if k == 0 {
+				header = dst
+			}
+			left := (widths[j] - len(header)) / 2
+			fmt.Fprintf(&sb, "| %s%-*s ", strings.Repeat(" ", left), widths[j]-left, header)
+		}
+		sb.WriteString("|\n")
+	}
+	sb.WriteString(separator)
+	for i, src := range matrix.Sources {
+		fmt.Fprintf(&sb, "| %-*s ", firstWidth, src)
+		for j := range matrix.Destinations {
+			fmt.Fprintf(&sb, "| %-*s ", widths[j], strings.Join(matrix.Verdicts[i][j], " "))
+		}
+		sb.WriteString("|\n")
+		sb.WriteString(separator)
+	}
+	return sb.String()
+}
+
+type modelPod struct {
+	key   string
+	ip    netip.Addr
+	ports []corev1.ContainerPort
+}
+
+// modelSet is an IPSet with its members.
+type modelSet struct {
+	// nets has the entries of a hash:net set. The value is true for nomatch entries
+	nets map[netip.Prefix]bool
+	// namedPorts has the entries of a hash:ip,port set, e.g. 10.0.0.1,TCP:80
+	namedPorts map[string]struct{}
+	// members has the prefixed names of the members of a list:set set
+	members map[string]struct{}
+}
+
+// connectivityModel is an in-memory model of the IPSets and policies which NPM programs.
+type connectivityModel struct {
+	pods     []*modelPod
+	sets     map[string]*modelSet
+	policies []*policies.NPMNetworkPolicy
+}
+
+func newConnectivityModel(manifests *Manifests, podCIDR netip.Prefix) (*connectivityModel, error) {
+	model := &connectivityModel{sets: make(map[string]*modelSet)}
+	if err := model.addPods(manifests.Pods, podCIDR); err != nil {
+		return nil, err
+	}
+	model.addNamespaces(manifests)
+
+	for _, netPol := range manifests.NetworkPolicies {
+		npmNetPol, err := translation.TranslatePolicy(netPol)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to translate NetworkPolicy %s/%s", netPol.Namespace, netPol.Name)
+		}
+		policies.NormalizePolicy(npmNetPol)
+		if err := policies.ValidatePolicy(npmNetPol); err != nil {
+			return nil, errors.Wrapf(err, "failed to validate NetworkPolicy %s/%s", netPol.Namespace, netPol.Name)
+		}
+		if err := model.addTranslatedSets(npmNetPol.AllPodSelectorIPSets()); err != nil {
+			return nil, err
+		}
+		if err := model.addTranslatedSets(npmNetPol.RuleIPSets); err != nil {
+			return nil, err
+		}
+		model.policies = append(model.policies, npmNetPol)
+	}
+	return model, nil
+}
+
+func (model *connectivityModel) set(metadata *ipsets.IPSetMetadata) *modelSet {
+	name := metadata.GetPrefixName()
+	set, ok := model.sets[name]
+	if !ok {
+		set = &modelSet{
+			nets:       make(map[netip.Prefix]bool),
+			namedPorts: make(map[string]struct{}),
+			members:    make(map[string]struct{}),
+		}
+		model.sets[name] = set
+	}
+	return set
+}
+
+func (model *connectivityModel) addToList(list, member *ipsets.IPSetMetadata) {
+	model.set(member)
+	model.set(list).members[member.GetPrefixName()] = struct{}{}
+}
+
+// addPods adds the pod IPs to sets like the pod controller.
+func (model *connectivityModel) addPods(pods []*corev1.Pod, podCIDR netip.Prefix) error {
+	used := make(map[netip.Addr]struct{})
+	for _, pod := range pods {
+		if pod.Status.PodIP == "" {
+			continue
+		}
+		ip, err := netip.ParseAddr(pod.Status.PodIP)
+		if err != nil {
+			return errors.Wrapf(err, "failed to parse the IP of pod %s/%s", pod.Namespace, pod.Name)
+		}
+		used[ip] = struct{}{}
+	}
+
+	next := podCIDR.Masked().Addr().Next()
+	for _, pod := range pods {
+		if pod.Spec.HostNetwork {
+			continue
+		}
+		key := pod.Namespace + "/" + pod.Name
+		ip, err := netip.ParseAddr(pod.Status.PodIP)
+		if err != nil {
+			if !podCIDR.Addr().Is4() {
+				return fmt.Errorf("pod CIDR %s must be IPv4 to assign an IP to pod %s: %w", podCIDR, key, ErrUnsupportedCIDR)
+			}
+			for _, ok := used[next]; ok; _, ok = used[next] {
+				next = next.Next()
+			}
+			if !podCIDR.Contains(next) {
+				return fmt.Errorf("failed to assign an IP to pod %s in %s: %w", key, podCIDR, ErrPodCIDRExhausted)
+			}
+			ip = next
+			used[ip] = struct{}{}
+			klog.Infof("assigned IP %s to pod %s", ip, key)
+		}
+
+		podIP := netip.PrefixFrom(ip, ip.BitLen())
+		model.set(ipsets.NewIPSetMetadata(pod.Namespace, ipsets.Namespace)).nets[podIP] = false
+		for labelKey, labelVal := range pod.Labels {
+			model.set(ipsets.NewIPSetMetadata(labelKey, ipsets.KeyLabelOfPod)).nets[podIP] = false
+			model.set(ipsets.NewIPSetMetadata(util.GetIpSetFromLabelKV(labelKey, labelVal), ipsets.KeyValueLabelOfPod)).nets[podIP] = false
+		}
+
+		var ports []corev1.ContainerPort
+		for i := range pod.Spec.Containers {
+			for _, port := range pod.Spec.Containers[i].Ports {
+				if port.Protocol == "" {
+					port.Protocol = corev1.ProtocolTCP
+				}
+				ports = append(ports, port)
+				if port.Name != "" {
+					entry := namedPortEntry(ip, policies.Protocol(port.Protocol), port.ContainerPort)
+					model.set(ipsets.NewIPSetMetadata(port.Name, ipsets.NamedPorts)).namedPorts[entry] = struct{}{}
+				}
+			}
+		}
+		model.pods = append(model.pods, &modelPod{key: key, ip: ip, ports: ports})
+	}
+	sort.Slice(model.pods, func(i, j int) bool {
+		return model.pods[i].key < model.pods[j].key
+	})
+	return nil
+}
+
+// addNamespaces adds the namespace sets to lists like the namespace controller.
+// The API server labels each namespace with its name.
+func (model *connectivityModel) addNamespaces(manifests *Manifests) {
+	namespaceLabels := make(map[string]map[string]string)
+	for _, pod := range manifests.Pods {
+		namespaceLabels[pod.Namespace] = map[string]string{}
+	}
+	for _, netPol := range manifests.NetworkPolicies {
+		namespaceLabels[netPol.Namespace] = map[string]string{}
+	}
+	for _, ns := range manifests.Namespaces {
+		labels := make(map[string]string, len(ns.Labels)+1)
+		for labelKey, labelVal := range ns.Labels {
+			labels[labelKey] = labelVal
+		}
+		namespaceLabels[ns.Name] = labels
+	}
+
+	allNamespaces := ipsets.NewIPSetMetadata(util.KubeAllNamespacesFlag, ipsets.KeyLabelOfNamespace)
+	for ns, labels := range namespaceLabels {
+		if _, ok := labels[corev1.LabelMetadataName]; !ok {
+			labels[corev1.LabelMetadataName] = ns
+		}
+		nsSet := ipsets.NewIPSetMetadata(ns, ipsets.Namespace)
+		model.addToList(allNamespaces, nsSet)
+		for labelKey, labelVal := range labels {
+			model.addToList(ipsets.NewIPSetMetadata(labelKey, ipsets.KeyLabelOfNamespace), nsSet)
+			model.addToList(ipsets.NewIPSetMetadata(util.GetIpSetFromLabelKV(labelKey, labelVal), ipsets.KeyValueLabelOfNamespace), nsSet)
+		}
+	}
+}
+
+// addTranslatedSets adds the members from translation: CIDRs for CIDRBlocks sets and sets for NestedLabelOfPod sets.
+func (model *connectivityModel) addTranslatedSets(translatedSets []*ipsets.TranslatedIPSet) error {
+	for _, translatedSet := range translatedSets {
+		set := model.set(translatedSet.Metadata)
+		for _, member := range translatedSet.Members {
+			switch translatedSet.Metadata.Type {
+			case ipsets.CIDRBlocks:
+				cidr, nomatch := strings.CutSuffix(member, " "+util.IpsetNomatch)
+				prefix, err := netip.ParsePrefix(cidr)
+				if err != nil {
+					return errors.Wrapf(err, "failed to parse CIDR in set %s", translatedSet.Metadata.GetPrefixName())
+				}
+				set.nets[prefix.Masked()] = nomatch
+			case ipsets.NestedLabelOfPod:
+				model.addToList(translatedSet.Metadata, ipsets.NewIPSetMetadata(member, ipsets.KeyValueLabelOfPod))
+			}
+		}
+	}
+	return nil
+}
+
+// ports returns the ports of the ACLs and the named ports of the pods, sorted by protocol and port.
+func (model *connectivityModel) ports() []Port {
+	portSet := make(map[Port]struct{})
+	for _, npmNetPol := range model.policies {
+		for _, acl := range npmNetPol.ACLs {
+			if acl.DstPorts.Port == 0 || acl.Protocol == policies.UnspecifiedProtocol {
+				continue
+			}
+			portSet[Port{Protocol: acl.Protocol, Port: acl.DstPorts.Port}] = struct{}{}
+			portSet[Port{Protocol: acl.Protocol, Port: acl.DstPorts.EndPort}] = struct{}{}
+		}
+	}
+	for _, pod := range model.pods {
+		for _, port := range pod.ports {
+			if port.Name != "" {
+				portSet[Port{Protocol: policies.Protocol(port.Protocol), Port: port.ContainerPort}] = struct{}{}
+			}
+		}
+	}
+
+	ports := make([]Port, 0, len(portSet))
+	for port := range portSet {
+		ports = append(ports, port)
+	}
+	sort.Slice(ports, func(i, j int) bool {
+		if ports[i].Protocol != ports[j].Protocol {
+			return ports[i].Protocol < ports[j].Protocol
+		}
+		return ports[i].Port < ports[j].Port
+	})
+	return ports
+}
+
+// cidrVerdict evaluates each of the uniformRanges of a CIDR.
+func (model *connectivityModel) cidrVerdict(src netip.Addr, ranges []netip.Prefix, port Port) string {
+	numAllowed := 0
+	for _, r := range ranges {
+		if model.isAllowed(src, r.Addr(), port) {
+			numAllowed++
+		}
+	}
+	switch numAllowed {
+	case len(ranges):
+		return Allowed
+	case 0:
+		return Denied
+	default:
+		return PartiallyDenied
+	}
+}
+
+// uniformRanges splits the CIDR until no set entry is more specific than a range.
+func (model *connectivityModel) uniformRanges(cidr netip.Prefix) []netip.Prefix {
+	for _, set := range model.sets {
+		for entry := range set.nets {
+			if entry.Bits() > cidr.Bits() && cidr.Contains(entry.Addr()) {
+				lower := netip.PrefixFrom(cidr.Addr(), cidr.Bits()+1)
+				upper := netip.PrefixFrom(lastAddr(lower).Next(), cidr.Bits()+1)
+				return append(model.uniformRanges(lower), model.uniformRanges(upper)...)
+			}
+		}
+	}
+	return []netip.Prefix{cidr}
+}
+
+func lastAddr(prefix netip.Prefix) netip.Addr {
+	b := prefix.Addr().As4()
+	for i := prefix.Bits(); i < 32; i++ {
+		b[i/8] |= 1 << (7 - i%8)
+	}
+	return netip.AddrFrom4(b)
+}
+
+// isAllowed returns whether the AZURE-NPM-INGRESS and AZURE-NPM-EGRESS chains allow the packet.
+func (model *connectivityModel) isAllowed(src, dst netip.Addr, port Port) bool {
+	return model.isAllowedInDirection(policies.Ingress, src, dst, port) && model.isAllowedInDirection(policies.Egress, src, dst, port)
+}
+
+// isAllowedInDirection evaluates the policies which select the destination for ingress, or the source for egress.
+// Like the drop mark, a Dropped ACL only denies the packet if no Allowed ACL of another policy matches it.
+// Audit-only policies never allow or deny packets.
+func (model *connectivityModel) isAllowedInDirection(direction policies.Direction, src, dst netip.Addr, port Port) bool {
+	selectorMatch := policies.DstMatch
+	if direction == policies.Egress {
+		selectorMatch = policies.SrcMatch
+	}
+
+	dropped := false
+	for _, npmNetPol := range model.policies {
+		if npmNetPol.AuditOnly {
+			continue
+		}
+		selected := true
+		for _, setInfo := range npmNetPol.PodSelectorList {
+			setInfo.MatchType = selectorMatch
+			if !model.matchesSet(setInfo, src, dst, port) {
+				selected = false
+				break
+			}
+		}
+		if !selected {
+			continue
+		}
+
+		for _, acl := range npmNetPol.ACLs {
+			if (acl.Direction != direction && acl.Direction != policies.Both) || !model.matchesACL(acl, src, dst, port) {
+				continue
+			}
+			if acl.Target == policies.Allowed {
+				return true
+			}
+			if acl.Target == policies.Dropped {
+				dropped = true
+			}
+		}
+	}
+	return !dropped
+}
+
+func (model *connectivityModel) matchesACL(acl *policies.ACLPolicy, src, dst netip.Addr, port Port) bool {
+	if acl.Protocol != policies.UnspecifiedProtocol && acl.Protocol != port.Protocol {
+		return false
+	}
+	if acl.DstPorts.Port != 0 && (port.Port < acl.DstPorts.Port || port.Port > acl.DstPorts.EndPort) {
+		return false
+	}
+	for _, setInfo := range append(append([]policies.SetInfo{}, acl.SrcList...), acl.DstList...) {
+		if !model.matchesSet(setInfo, src, dst, port) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesSet evaluates an iptables set match, e.g. -m set ! --match-set azure-npm-123 src
+func (model *connectivityModel) matchesSet(setInfo policies.SetInfo, src, dst netip.Addr, port Port) bool {
+	name := setInfo.IPSet.GetPrefixName()
+	var contains bool
+	switch setInfo.MatchType {
+	case policies.SrcMatch:
+		contains = model.containsAddr(name, src)
+	case policies.DstMatch:
+		contains = model.containsAddr(name, dst)
+	case policies.DstDstMatch:
+		contains = model.containsNamedPort(name, namedPortEntry(dst, port.Protocol, port.Port))
+	}
+	return contains == setInfo.Included
+}
+
+// containsAddr follows hash:net sets, where the most specific entry decides, and list:set sets.
+func (model *connectivityModel) containsAddr(name string, addr netip.Addr) bool {
+	set, ok := model.sets[name]
+	if !ok {
+		return false
+	}
+	for member := range set.members {
+		if model.containsAddr(member, addr) {
+			return true
+		}
+	}
+
+	bestBits := -1
+	contains := false
+	for entry, nomatch := range set.nets {
+		if entry.Contains(addr) && entry.Bits() > bestBits {
+			bestBits = entry.Bits()
+			contains = !nomatch
+		}
+	}
+	return contains
+}
+
+func (model *connectivityModel) containsNamedPort(name, entry string) bool {
+	set, ok := model.sets[name]
+	if !ok {
+		return false
+	}
+	_, contains := set.namedPorts[entry]
+	return contains
+}
+
+func namedPortEntry(ip netip.Addr, protocol policies.Protocol, port int32) string {
+	return fmt.Sprintf("%s,%s:%d", ip, protocol, port)
+}
@@ -0,0 +1,222 @@
+package debug
+
+import (
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-container-networking/npm/pkg/controlplane/translation"
+	"github.com/Azure/azure-container-networking/npm/pkg/dataplane/policies"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	netpolFile     = "../testdata/netpol.yaml"
+	netpolPodsFile = "../testdata/netpol-pods.yaml"
+)
+
+var testPodCIDR = netip.MustParsePrefix("10.224.0.0/16")
+
+// expectedTable returns the table in the comment at the top of netpol.yaml
+func expectedTable(t *testing.T) string {
+	b, err := os.ReadFile(netpolFile)
+	require.NoError(t, err)
+	var sb strings.Builder
+	header := true
+	for _, line := range strings.Split(string(b), "\n") {
+		if !strings.HasPrefix(line, "# +") && !strings.HasPrefix(line, "# |") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "# ")
+		// the table in the file upper-cases the destinations in the header, while the matrix keeps the keys of the pods
+		if header && strings.HasPrefix(line, "|") {
+			port := strings.Index(line[1:], "|") + 1
+			line = line[:port] + strings.ToLower(line[port:])
+			header = false
+		}
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+func TestConnectivityMatrixTable(t *testing.T) {
+	manifests, err := LoadManifests(netpolFile, netpolPodsFile)
+	require.NoError(t, err)
+	require.Len(t, manifests.NetworkPolicies, 1)
+	require.Len(t, manifests.Namespaces, 3)
+	require.Len(t, manifests.Pods, 9)
+
+	ports := make([]Port, 0, 4)
+	for _, s := range []string{"TCP/80", "TCP/81", "UDP/80", "UDP/81"} {
+		port, err := ParsePort(s)
+		require.NoError(t, err)
+		ports = append(ports, port)
+	}
+	matrix, err := ComputeConnectivityMatrix(manifests, &MatrixOptions{Ports: ports, PodCIDR: testPodCIDR})
+	require.NoError(t, err)
+	require.Equal(t, expectedTable(t), matrix.Table())
+}
+
+func TestConnectivityMatrixDefaultPorts(t *testing.T) {
+	manifests, err := LoadManifests(netpolFile, netpolPodsFile)
+	require.NoError(t, err)
+	matrix, err := ComputeConnectivityMatrix(manifests, &MatrixOptions{PodCIDR: testPodCIDR})
+	require.NoError(t, err)
+	expected := []Port{
+		{policies.TCP, 53},
+		{policies.TCP, 80},
+		{policies.TCP, 81},
+		{policies.UDP, 53},
+		{policies.UDP, 80},
+		{policies.UDP, 81},
+	}
+	require.Equal(t, expected, matrix.Ports)
+
+	_, err = ComputeConnectivityMatrix(&Manifests{}, &MatrixOptions{PodCIDR: testPodCIDR})
+	require.ErrorIs(t, err, ErrNoPorts)
+}
+
+func TestConnectivityMatrixCIDRsAndNamedPorts(t *testing.T) {
+	tcp := corev1.ProtocolTCP
+	namedPort := intstr.FromString("http")
+	pods := []*corev1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "x", Name: "client", Labels: map[string]string{"app": "client"}},
+			Status:     corev1.PodStatus{PodIP: "10.224.0.1"},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "x", Name: "server", Labels: map[string]string{"app": "server"}},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Ports: []corev1.ContainerPort{{Name: "http", ContainerPort: 8080}}}},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "x", Name: "host"},
+			Spec:       corev1.PodSpec{HostNetwork: true},
+		},
+	}
+	auditOnly := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "x",
+			Name:        "audit-deny-all",
+			Annotations: map[string]string{translation.AuditOnlyAnnotation: "true"},
+		},
+		Spec: networkingv1.NetworkPolicySpec{PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress}},
+	}
+	netPols := []*networkingv1.NetworkPolicy{
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "x", Name: "client-egress"},
+			Spec: networkingv1.NetworkPolicySpec{
+				PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "client"}},
+				Egress: []networkingv1.NetworkPolicyEgressRule{
+					{To: []networkingv1.NetworkPolicyPeer{{IPBlock: &networkingv1.IPBlock{CIDR: "10.0.0.0/16", Except: []string{"10.0.1.0/24"}}}}},
+					{To: []networkingv1.NetworkPolicyPeer{{PodSelector: &metav1.LabelSelector{}}}},
+				},
+				PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeEgress},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "x", Name: "server-ingress"},
+			Spec: networkingv1.NetworkPolicySpec{
+				PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "server"}},
+				Ingress: []networkingv1.NetworkPolicyIngressRule{
+					{Ports: []networkingv1.NetworkPolicyPort{{Protocol: &tcp, Port: &namedPort}}},
+				},
+				PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+			},
+		},
+		auditOnly,
+	}
+	manifests := &Manifests{NetworkPolicies: netPols, Pods: pods}
+	opts := &MatrixOptions{
+		CIDRs: []netip.Prefix{
+			netip.MustParsePrefix("10.0.0.0/24"),
+			netip.MustParsePrefix("10.0.1.0/24"),
+			netip.MustParsePrefix("10.0.0.0/16"),
+			netip.MustParsePrefix("8.8.8.8/32"),
+		},
+		Ports:   []Port{{policies.TCP, 8080}, {policies.TCP, 9090}},
+		PodCIDR: testPodCIDR,
+	}
+	matrix, err := ComputeConnectivityMatrix(manifests, opts)
+	require.NoError(t, err)
+	require.Equal(t, []string{"x/client", "x/server"}, matrix.Sources)
+	require.Equal(t, []string{"x/client", "x/server", "10.0.0.0/24", "10.0.1.0/24", "10.0.0.0/16", "8.8.8.8/32"}, matrix.Destinations)
+	expected := [][][]string{
+		{
+			// the client can only reach pods and 10.0.0.0/16 except 10.0.1.0/24, and the server only allows its named port
+			{Allowed, Allowed},
+			{Allowed, Denied},
+			{Allowed, Allowed},
+			{Denied, Denied},
+			{PartiallyDenied, PartiallyDenied},
+			{Denied, Denied},
+		},
+		{
+			{Allowed, Allowed},
+			{Allowed, Denied},
+			{Allowed, Allowed},
+			{Allowed, Allowed},
+			{Allowed, Allowed},
+			{Allowed, Allowed},
+		},
+	}
+	require.Equal(t, expected, matrix.Verdicts)
+
+	// the audit-only policy would deny everything that the other policies don't allow if it were enforced
+	auditOnly.Annotations = nil
+	matrix, err = ComputeConnectivityMatrix(manifests, opts)
+	require.NoError(t, err)
+	require.Equal(t, []string{Allowed, Denied}, matrix.Verdicts[0][1])
+	for _, portVerdicts := range matrix.Verdicts[1] {
+		require.Equal(t, []string{Denied, Denied}, portVerdicts)
+	}
+}
+
+func TestParsePort(t *testing.T) {
+	port, err := ParsePort("udp/53")
+	require.NoError(t, err)
+	require.Equal(t, Port{policies.UDP, 53}, port)
+	require.Equal(t, "UDP/53", port.String())
+
+	for _, s := range []string{"53", "ICMP/53", "TCP/0", "TCP/65536", "TCP/http"} {
+		_, err := ParsePort(s)
+		require.ErrorIs(t, err, ErrInvalidPort, s)
+	}
+}
+
+func TestLoadManifestsFromDirectory(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "nested"), 0o755))
+	deploymentAndPolicy := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: ignored
+---
+apiVersion: networking.k8s.io/v1
+kind: NetworkPolicy
+metadata:
+  name: deny-all
+spec:
+  podSelector: {}
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "nested", "policy.yml"), []byte(deploymentAndPolicy), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("not a manifest"), 0o600))
+
+	manifests, err := LoadManifests(dir)
+	require.NoError(t, err)
+	require.Len(t, manifests.NetworkPolicies, 1)
+	require.Equal(t, "default", manifests.NetworkPolicies[0].Namespace)
+	require.Empty(t, manifests.Namespaces)
+	require.Empty(t, manifests.Pods)
+
+	_, err = LoadManifests(filepath.Join(dir, "missing"))
+	require.Error(t, err)
+}
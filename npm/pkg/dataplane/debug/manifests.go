@@ -0,0 +1,105 @@
+package debug
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	yamlutil "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/klog"
+)
+
+// Manifests are the objects which decide the connectivity between pods.
+type Manifests struct {
+	NetworkPolicies []*networkingv1.NetworkPolicy
+	Namespaces      []*corev1.Namespace
+	Pods            []*corev1.Pod
+}
+
+// LoadManifests reads the NetworkPolicies, Namespaces, and Pods from YAML or JSON files.
+// Each path is a file or a directory, which is read recursively. Other kinds of objects are ignored.
+func LoadManifests(paths ...string) (*Manifests, error) {
+	manifests := &Manifests{}
+	for _, path := range paths {
+		err := filepath.WalkDir(path, func(file string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if entry.IsDir() || !isManifestFile(file) {
+				return nil
+			}
+			return manifests.addFile(file)
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to load manifests from %s", path)
+		}
+	}
+	return manifests, nil
+}
+
+func isManifestFile(file string) bool {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".yaml", ".yml", ".json":
+		return true
+	default:
+		return false
+	}
+}
+
+func (manifests *Manifests) addFile(file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", file, err)
+	}
+	defer f.Close()
+
+	reader := yamlutil.NewYAMLReader(bufio.NewReader(f))
+	for {
+		doc, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+
+		obj, gvk, err := scheme.Codecs.UniversalDeserializer().Decode(doc, nil, nil)
+		if err != nil {
+			if runtime.IsNotRegisteredError(err) || runtime.IsMissingKind(err) {
+				klog.Infof("ignoring object in %s: %s", file, err.Error())
+				continue
+			}
+			return fmt.Errorf("failed to decode %s: %w", file, err)
+		}
+
+		switch o := obj.(type) {
+		case *networkingv1.NetworkPolicy:
+			if o.Namespace == "" {
+				o.Namespace = corev1.NamespaceDefault
+			}
+			manifests.NetworkPolicies = append(manifests.NetworkPolicies, o)
+		case *corev1.Namespace:
+			manifests.Namespaces = append(manifests.Namespaces, o)
+		case *corev1.Pod:
+			if o.Namespace == "" {
+				o.Namespace = corev1.NamespaceDefault
+			}
+			manifests.Pods = append(manifests.Pods, o)
+		default:
+			klog.Infof("ignoring %s in %s", gvk.Kind, file)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+// Package flowlog collects the connections denied by the NPM v2 dataplane in Linux.
+// The dataplane logs them with NFLOG (see policies.PolicyManagerCfg.FlowLogging), and the Collector
+// decodes them into Records, counts them in Prometheus, and streams them as JSON lines over HTTP.
+package flowlog
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-container-networking/npm/util"
+	"k8s.io/klog"
+)
+
+const (
+	Ingress = "ingress"
+	Egress  = "egress"
+
+	// subscribers miss the Records which arrive while their buffer is full
+	subscriberBufferSize = 100
+)
+
+// Record is a connection denied by a policy.
+// The pod fields are empty if the IP doesn't belong to a Pod in the cache, e.g. for a Pod on another node or an external IP.
+type Record struct {
+	Time         time.Time `json:"time"`
+	Direction    string    `json:"direction"`
+	Policy       string    `json:"policy"`
+	Protocol     string    `json:"protocol"`
+	SrcIP        string    `json:"srcIP"`
+	SrcPort      int       `json:"srcPort,omitempty"`
+	SrcNamespace string    `json:"srcNamespace,omitempty"`
+	SrcPod       string    `json:"srcPod,omitempty"`
+	DstIP        string    `json:"dstIP"`
+	DstPort      int       `json:"dstPort,omitempty"`
+	DstNamespace string    `json:"dstNamespace,omitempty"`
+	DstPod       string    `json:"dstPod,omitempty"`
+}
+
+// PodCache finds Pods by IP, e.g. the v2 PodController.
+type PodCache interface {
+	GetPodByIP(podIP string) (namespace, name string, ok bool)
+}
+
+// PolicyCache finds the applied policies by the hash of their key, e.g. the v2 DataPlane.
+type PolicyCache interface {
+	GetPolicyKeyByHash(hash string) (string, bool)
+}
+
+// Collector turns the logged packets into Records.
+type Collector struct {
+	pods     PodCache
+	policies PolicyCache
+
+	sync.Mutex
+	subscribers map[chan *Record]struct{}
+}
+
+func NewCollector(pods PodCache, policies PolicyCache) *Collector {
+	return &Collector{
+		pods:        pods,
+		policies:    policies,
+		subscribers: make(map[chan *Record]struct{}),
+	}
+}
+
+// newRecord describes the packet logged with the prefix, which is the name of the policy chain which denied it.
+// It returns false if the prefix isn't a policy chain.
+func (c *Collector) newRecord(prefix string, packet *packetInfo, t time.Time) (*Record, bool) {
+	var direction, hash string
+	switch {
+	case strings.HasPrefix(prefix, util.IptablesAzureIngressPolicyChainPrefix+"-"):
+		direction = Ingress
+		hash = strings.TrimPrefix(prefix, util.IptablesAzureIngressPolicyChainPrefix+"-")
+	case strings.HasPrefix(prefix, util.IptablesAzureEgressPolicyChainPrefix+"-"):
+		direction = Egress
+		hash = strings.TrimPrefix(prefix, util.IptablesAzureEgressPolicyChainPrefix+"-")
+	default:
+		return nil, false
+	}
+
+	policyKey, ok := c.policies.GetPolicyKeyByHash(hash)
+	if !ok {
+		// the policy was removed since the packet was logged
+		policyKey = prefix
+	}
+
+	record := &Record{
+		Time:      t,
+		Direction: direction,
+		Policy:    policyKey,
+		Protocol:  packet.protocol,
+		SrcIP:     packet.srcIP.String(),
+		SrcPort:   packet.srcPort,
+		DstIP:     packet.dstIP.String(),
+		DstPort:   packet.dstPort,
+	}
+	record.SrcNamespace, record.SrcPod, _ = c.pods.GetPodByIP(record.SrcIP)
+	record.DstNamespace, record.DstPod, _ = c.pods.GetPodByIP(record.DstIP)
+	return record, true
+}
+
+// publish sends the Record to all subscribers without blocking.
+func (c *Collector) publish(record *Record) {
+	c.Lock()
+	defer c.Unlock()
+	for subscriber := range c.subscribers {
+		select {
+		case subscriber <- record:
+		default:
+		}
+	}
+}
+
+func (c *Collector) subscribe() chan *Record {
+	c.Lock()
+	defer c.Unlock()
+	subscriber := make(chan *Record, subscriberBufferSize)
+	c.subscribers[subscriber] = struct{}{}
+	return subscriber
+}
+
+func (c *Collector) unsubscribe(subscriber chan *Record) {
+	c.Lock()
+	defer c.Unlock()
+	delete(c.subscribers, subscriber)
+}
+
+func (c *Collector) numSubscribers() int {
+	c.Lock()
+	defer c.Unlock()
+	return len(c.subscribers)
+}
+
+// ServeHTTP streams the Records as JSON lines until the client disconnects.
+func (c *Collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	subscriber := c.subscribe()
+	defer c.unsubscribe(subscriber)
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	flusher, canFlush := w.(http.Flusher)
+	if canFlush {
+		flusher.Flush()
+	}
+
+	encoder := json.NewEncoder(w)
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case record := <-subscriber:
+			if err := encoder.Encode(record); err != nil {
+				klog.Infof("stopped streaming flow logs to %s: %s", r.RemoteAddr, err.Error())
+				return
+			}
+			if canFlush {
+				flusher.Flush()
+			}
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package flowlog
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-container-networking/npm/util"
+	"github.com/stretchr/testify/require"
+)
+
+const testPolicyKey = "x/deny-all"
+
+type fakePodCache map[string][2]string
+
+func (pods fakePodCache) GetPodByIP(podIP string) (namespace, name string, ok bool) {
+	pod, ok := pods[podIP]
+	return pod[0], pod[1], ok
+}
+
+type fakePolicyCache map[string]string
+
+func (policies fakePolicyCache) GetPolicyKeyByHash(hash string) (string, bool) {
+	policyKey, ok := policies[hash]
+	return policyKey, ok
+}
+
+func newTestCollector() *Collector {
+	return NewCollector(
+		fakePodCache{"10.0.0.1": {"x", "a"}, "10.0.0.2": {"y", "b"}},
+		fakePolicyCache{util.Hash(testPolicyKey): testPolicyKey},
+	)
+}
+
+// testPacket returns an IPv4 header without options, followed by the ports.
+func testPacket(protocol byte, src, dst string, srcPort, dstPort byte) []byte {
+	b := make([]byte, 24)
+	b[0] = 0x45
+	b[ipv4ProtocolOffset] = protocol
+	copy(b[ipv4SrcOffset:], net.ParseIP(src).To4())
+	copy(b[ipv4DstOffset:], net.ParseIP(dst).To4())
+	b[21] = srcPort
+	b[23] = dstPort
+	return b
+}
+
+func TestDecodePacket(t *testing.T) {
+	packet, err := decodePacket(testPacket(protocolTCP, "10.0.0.1", "10.0.0.2", 100, 80))
+	require.NoError(t, err)
+	require.Equal(t, &packetInfo{
+		protocol: "TCP",
+		srcIP:    net.ParseIP("10.0.0.1").To4(),
+		dstIP:    net.ParseIP("10.0.0.2").To4(),
+		srcPort:  100,
+		dstPort:  80,
+	}, packet)
+
+	packet, err = decodePacket(testPacket(protocolICMP, "10.0.0.1", "10.0.0.2", 8, 0))
+	require.NoError(t, err)
+	require.Equal(t, "ICMP", packet.protocol)
+	require.Zero(t, packet.dstPort)
+
+	// IPv6
+	b := testPacket(protocolTCP, "10.0.0.1", "10.0.0.2", 100, 80)
+	b[0] = 0x60
+	_, err = decodePacket(b)
+	require.ErrorIs(t, err, errUnsupportedPacket)
+
+	// no ports
+	_, err = decodePacket(testPacket(protocolUDP, "10.0.0.1", "10.0.0.2", 100, 80)[:21])
+	require.ErrorIs(t, err, errUnsupportedPacket)
+}
+
+func TestNewRecord(t *testing.T) {
+	c := newTestCollector()
+	packet, err := decodePacket(testPacket(protocolTCP, "10.0.0.1", "10.0.0.2", 100, 80))
+	require.NoError(t, err)
+	now := time.Now()
+
+	record, ok := c.newRecord("AZURE-NPM-INGRESS-"+util.Hash(testPolicyKey), packet, now)
+	require.True(t, ok)
+	require.Equal(t, &Record{
+		Time:         now,
+		Direction:    Ingress,
+		Policy:       testPolicyKey,
+		Protocol:     "TCP",
+		SrcIP:        "10.0.0.1",
+		SrcPort:      100,
+		SrcNamespace: "x",
+		SrcPod:       "a",
+		DstIP:        "10.0.0.2",
+		DstPort:      80,
+		DstNamespace: "y",
+		DstPod:       "b",
+	}, record)
+
+	// unknown policy
+	record, ok = c.newRecord("AZURE-NPM-EGRESS-123", packet, now)
+	require.True(t, ok)
+	require.Equal(t, Egress, record.Direction)
+	require.Equal(t, "AZURE-NPM-EGRESS-123", record.Policy)
+
+	_, ok = c.newRecord("AZURE-NPM-ACCEPT", packet, now)
+	require.False(t, ok)
+}
+
+func TestServeHTTP(t *testing.T) {
+	c := newTestCollector()
+	server := httptest.NewServer(c)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL) //nolint:noctx // test
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Eventually(t, func() bool { return c.numSubscribers() == 1 }, time.Second, 10*time.Millisecond)
+
+	expected := &Record{Direction: Ingress, Policy: testPolicyKey, Protocol: "TCP", SrcIP: "10.0.0.1", DstIP: "10.0.0.2", DstPort: 80}
+	c.publish(expected)
+
+	line, err := bufio.NewReader(resp.Body).ReadBytes('\n')
+	require.NoError(t, err)
+	actual := &Record{}
+	require.NoError(t, json.Unmarshal(line, actual))
+	require.Equal(t, expected, actual)
+
+	// the subscriber is removed once the client disconnects
+	resp.Body.Close()
+	server.CloseClientConnections()
+	require.Eventually(t, func() bool { return c.numSubscribers() == 0 }, time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,176 @@
+package flowlog
+
+// This file contains code for receiving the packets that the dataplane logs to its NFLOG group.
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/Azure/azure-container-networking/npm/metrics"
+	"github.com/Azure/azure-container-networking/npm/util"
+	"github.com/vishvananda/netlink/nl"
+	"golang.org/x/sys/unix"
+	"k8s.io/klog"
+)
+
+// from include/uapi/linux/netfilter/nfnetlink.h and include/uapi/linux/netfilter/nfnetlink_log.h
+const (
+	nfnlSubsysULOG   = 4
+	nfulnlMsgPacket  = 0
+	nfulnlMsgConfig  = 1
+	nfulaCfgCmd      = 1
+	nfulaCfgMode     = 2
+	nfulaPayload     = 9
+	nfulaPrefix      = 10
+	nfulnlCfgCmdBind = 1
+	nfulnlCopyPacket = 2
+	// struct nfulnl_msg_config_mode is a __be32 copy_range, a __u8 copy_mode, and a __u8 pad
+	sizeofConfigMode = 6
+
+	// enough for an IPv4 header with options and the ports
+	copyRange = 128
+)
+
+var (
+	errNoAck          = errors.New("no ack from netlink")
+	errInvalidMessage = errors.New("invalid NFLOG message")
+)
+
+// Run receives the logged packets until stopCh is closed.
+// It returns an error if it can't bind to the NFLOG group or the socket fails.
+func (c *Collector) Run(stopCh <-chan struct{}) error {
+	socket, err := nl.Subscribe(unix.NETLINK_NETFILTER)
+	if err != nil {
+		return fmt.Errorf("failed to open netfilter netlink socket: %w", err)
+	}
+	defer socket.Close()
+
+	// wake up every second to check stopCh
+	if err := socket.SetReceiveTimeout(&unix.Timeval{Sec: 1}); err != nil {
+		return fmt.Errorf("failed to set receive timeout: %w", err)
+	}
+
+	group := util.IptablesAzureNFLogGroup
+	if err := sendConfig(socket, group, nl.NewRtAttr(nfulaCfgCmd, []byte{nfulnlCfgCmdBind})); err != nil {
+		return fmt.Errorf("failed to bind to NFLOG group %d: %w", group, err)
+	}
+	mode := make([]byte, sizeofConfigMode)
+	binary.BigEndian.PutUint32(mode, copyRange)
+	mode[4] = nfulnlCopyPacket
+	if err := sendConfig(socket, group, nl.NewRtAttr(nfulaCfgMode, mode)); err != nil {
+		return fmt.Errorf("failed to set copy mode for NFLOG group %d: %w", group, err)
+	}
+
+	klog.Infof("collecting flow logs from NFLOG group %d", group)
+	for {
+		select {
+		case <-stopCh:
+			return nil
+		default:
+		}
+
+		msgs, _, err := socket.Receive()
+		if err != nil {
+			switch {
+			case errors.Is(err, unix.EAGAIN), errors.Is(err, unix.EINTR):
+				continue
+			case errors.Is(err, unix.ENOBUFS):
+				klog.Warningf("missed flow logs since the socket buffer is full")
+				continue
+			default:
+				return fmt.Errorf("failed to receive flow logs: %w", err)
+			}
+		}
+
+		now := time.Now()
+		for _, msg := range msgs {
+			c.handleMessage(msg, now)
+		}
+	}
+}
+
+// sendConfig sends the attribute in a config message for the group and waits for the ack.
+func sendConfig(socket *nl.NetlinkSocket, group uint16, attr *nl.RtAttr) error {
+	req := nl.NewNetlinkRequest(nfnlSubsysULOG<<8|nfulnlMsgConfig, unix.NLM_F_ACK)
+	req.AddData(&nl.Nfgenmsg{
+		NfgenFamily: unix.AF_UNSPEC,
+		Version:     nl.NFNETLINK_V0,
+		ResId:       nl.Swap16(group),
+	})
+	req.AddData(attr)
+	if err := socket.Send(req); err != nil {
+		return fmt.Errorf("failed to send: %w", err)
+	}
+
+	msgs, _, err := socket.Receive()
+	if err != nil {
+		return fmt.Errorf("failed to receive ack: %w", err)
+	}
+	for _, msg := range msgs {
+		if msg.Header.Type != unix.NLMSG_ERROR || len(msg.Data) < 4 {
+			continue
+		}
+		if errno := int32(nl.NativeEndian().Uint32(msg.Data[0:4])); errno != 0 {
+			return unix.Errno(-errno)
+		}
+		return nil
+	}
+	return errNoAck
+}
+
+// handleMessage turns a logged packet into a Record, counts it, and sends it to the subscribers.
+func (c *Collector) handleMessage(msg syscall.NetlinkMessage, t time.Time) {
+	prefix, payload, ok, err := parseMessage(msg)
+	if err != nil {
+		klog.Warningf("failed to parse flow log: %s", err.Error())
+		return
+	}
+	if !ok {
+		return
+	}
+
+	packet, err := decodePacket(payload)
+	if err != nil {
+		klog.Warningf("failed to decode flow log with prefix %s: %s", prefix, err.Error())
+		return
+	}
+
+	record, ok := c.newRecord(prefix, packet, t)
+	if !ok {
+		klog.Warningf("ignoring flow log with unexpected prefix %s", prefix)
+		return
+	}
+	metrics.IncDeniedFlows(record.Policy, record.Direction)
+	c.publish(record)
+}
+
+// parseMessage returns the prefix and payload of an NFLOG packet message. It returns false for other messages.
+func parseMessage(msg syscall.NetlinkMessage) (prefix string, payload []byte, ok bool, err error) {
+	if msg.Header.Type != nfnlSubsysULOG<<8|nfulnlMsgPacket {
+		return "", nil, false, nil
+	}
+	if len(msg.Data) < nl.SizeofNfgenmsg {
+		return "", nil, false, fmt.Errorf("message is too short: %w", errInvalidMessage)
+	}
+
+	attrs, err := nl.ParseRouteAttr(msg.Data[nl.SizeofNfgenmsg:])
+	if err != nil {
+		return "", nil, false, fmt.Errorf("failed to parse attributes: %w", err)
+	}
+	for _, attr := range attrs {
+		switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+		case nfulaPrefix:
+			prefix = strings.TrimRight(string(attr.Value), "\x00")
+		case nfulaPayload:
+			payload = attr.Value
+		}
+	}
+	if payload == nil {
+		return "", nil, false, fmt.Errorf("message has no payload: %w", errInvalidMessage)
+	}
+	return prefix, payload, true, nil
+}
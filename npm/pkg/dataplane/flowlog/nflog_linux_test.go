@@ -0,0 +1,73 @@
+package flowlog
+
+import (
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-container-networking/npm/metrics"
+	"github.com/Azure/azure-container-networking/npm/util"
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink/nl"
+)
+
+func testMessage(msgType uint16, attrs ...*nl.RtAttr) syscall.NetlinkMessage {
+	data := (&nl.Nfgenmsg{Version: nl.NFNETLINK_V0, ResId: nl.Swap16(util.IptablesAzureNFLogGroup)}).Serialize()
+	for _, attr := range attrs {
+		data = append(data, attr.Serialize()...)
+	}
+	msg := syscall.NetlinkMessage{Data: data}
+	msg.Header.Type = msgType
+	return msg
+}
+
+func TestParseMessage(t *testing.T) {
+	payload := testPacket(protocolTCP, "10.0.0.1", "10.0.0.2", 100, 80)
+	msg := testMessage(nfnlSubsysULOG<<8|nfulnlMsgPacket,
+		nl.NewRtAttr(nfulaPrefix, nl.ZeroTerminated("AZURE-NPM-INGRESS-123")),
+		nl.NewRtAttr(nfulaPayload, payload),
+	)
+	prefix, actualPayload, ok, err := parseMessage(msg)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, "AZURE-NPM-INGRESS-123", prefix)
+	require.Equal(t, payload, actualPayload)
+
+	// config message
+	_, _, ok, err = parseMessage(testMessage(nfnlSubsysULOG<<8 | nfulnlMsgConfig))
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	// no payload
+	_, _, _, err = parseMessage(testMessage(nfnlSubsysULOG<<8|nfulnlMsgPacket, nl.NewRtAttr(nfulaPrefix, nl.ZeroTerminated("AZURE-NPM-INGRESS-123"))))
+	require.ErrorIs(t, err, errInvalidMessage)
+}
+
+func TestHandleMessage(t *testing.T) {
+	metrics.ReinitializeAll()
+	c := newTestCollector()
+	subscriber := c.subscribe()
+
+	msg := testMessage(nfnlSubsysULOG<<8|nfulnlMsgPacket,
+		nl.NewRtAttr(nfulaPrefix, nl.ZeroTerminated("AZURE-NPM-EGRESS-"+util.Hash(testPolicyKey))),
+		nl.NewRtAttr(nfulaPayload, testPacket(protocolUDP, "10.0.0.2", "10.0.0.3", 100, 53)),
+	)
+	c.handleMessage(msg, time.Now())
+
+	record := <-subscriber
+	require.Equal(t, Egress, record.Direction)
+	require.Equal(t, testPolicyKey, record.Policy)
+	require.Equal(t, "y", record.SrcNamespace)
+	require.Equal(t, "b", record.SrcPod)
+	require.Equal(t, "10.0.0.3", record.DstIP)
+	require.Empty(t, record.DstPod)
+	require.Equal(t, 53, record.DstPort)
+
+	count, err := metrics.TotalDeniedFlows(testPolicyKey, Egress)
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+
+	// undecodable packets are ignored
+	c.handleMessage(testMessage(nfnlSubsysULOG<<8|nfulnlMsgPacket, nl.NewRtAttr(nfulaPayload, []byte{0x60})), time.Now())
+	require.Empty(t, subscriber)
+}
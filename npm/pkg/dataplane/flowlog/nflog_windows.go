@@ -0,0 +1,10 @@
+package flowlog
+
+import "errors"
+
+var errUnsupportedPlatform = errors.New("flow logs are only supported in Linux")
+
+// Run is unsupported in Windows.
+func (c *Collector) Run(_ <-chan struct{}) error {
+	return errUnsupportedPlatform
+}
@@ -0,0 +1,77 @@
+package flowlog
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+)
+
+const (
+	ipv4Version          = 4
+	minIPv4HeaderLength  = 20
+	ipv4ProtocolOffset   = 9
+	ipv4SrcOffset        = 12
+	ipv4DstOffset        = 16
+	ipv4HeaderWordLength = 4
+	portsLength          = 4
+
+	protocolICMP = 1
+	protocolTCP  = 6
+	protocolUDP  = 17
+	protocolSCTP = 132
+)
+
+var (
+	errUnsupportedPacket = errors.New("unsupported packet")
+
+	protocolNames = map[byte]string{
+		protocolICMP: "ICMP",
+		protocolTCP:  "TCP",
+		protocolUDP:  "UDP",
+		protocolSCTP: "SCTP",
+	}
+)
+
+// packetInfo is the part of a logged packet which identifies the connection.
+type packetInfo struct {
+	protocol string
+	srcIP    net.IP
+	dstIP    net.IP
+	// the ports are 0 for protocols without ports
+	srcPort int
+	dstPort int
+}
+
+// decodePacket reads the IPv4 header and the ports of TCP, UDP, and SCTP packets.
+func decodePacket(b []byte) (*packetInfo, error) {
+	if len(b) < minIPv4HeaderLength || b[0]>>4 != ipv4Version {
+		return nil, fmt.Errorf("expected an IPv4 packet: %w", errUnsupportedPacket)
+	}
+	headerLength := int(b[0]&0x0f) * ipv4HeaderWordLength
+	if headerLength < minIPv4HeaderLength || len(b) < headerLength {
+		return nil, fmt.Errorf("invalid IPv4 header length %d: %w", headerLength, errUnsupportedPacket)
+	}
+
+	packet := &packetInfo{
+		srcIP: net.IP(append([]byte{}, b[ipv4SrcOffset:ipv4SrcOffset+net.IPv4len]...)),
+		dstIP: net.IP(append([]byte{}, b[ipv4DstOffset:ipv4DstOffset+net.IPv4len]...)),
+	}
+
+	protocol := b[ipv4ProtocolOffset]
+	name, ok := protocolNames[protocol]
+	if !ok {
+		name = strconv.Itoa(int(protocol))
+	}
+	packet.protocol = name
+
+	if protocol == protocolTCP || protocol == protocolUDP || protocol == protocolSCTP {
+		if len(b) < headerLength+portsLength {
+			return nil, fmt.Errorf("%s packet is too short for ports: %w", name, errUnsupportedPacket)
+		}
+		packet.srcPort = int(binary.BigEndian.Uint16(b[headerLength:]))
+		packet.dstPort = int(binary.BigEndian.Uint16(b[headerLength+2:]))
+	}
+	return packet, nil
+}
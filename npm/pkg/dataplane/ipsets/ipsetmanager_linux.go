@@ -97,6 +97,14 @@ var (
 		If a flush fails, we could update the num entries for that set, but that would be a lot of overhead.
 */
 func (iMgr *IPSetManager) resetIPSets() error {
+	if iMgr.nftRuleset != nil {
+		return iMgr.resetNFTablesSets()
+	}
+	return iMgr.resetKernelIPSets()
+}
+
+// resetKernelIPSets flushes and destroys the NPM ipsets in the kernel, as described for resetIPSets.
+func (iMgr *IPSetManager) resetKernelIPSets() error {
 	if success := iMgr.resetWithoutRestore(); success {
 		return nil
 	}
@@ -405,6 +413,10 @@ example where:
 		-X set4
 */
 func (iMgr *IPSetManager) applyIPSets() error {
+	if iMgr.nftRuleset != nil {
+		return iMgr.applyNFTablesSets()
+	}
+
 	creator := iMgr.fileCreatorForApply(maxTryCount)
 	restoreError := creator.RunCommandWithFile(ipsetCommand, ipsetRestoreFlag)
 	if restoreError != nil {
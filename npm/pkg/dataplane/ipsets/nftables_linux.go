@@ -0,0 +1,271 @@
+package ipsets
+
+// This file contains code for the nftables implementation of applying ipsets.
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/Azure/azure-container-networking/npm/metrics"
+	"github.com/Azure/azure-container-networking/npm/pkg/dataplane/nftables"
+	"github.com/Azure/azure-container-networking/npm/util"
+	npmerrors "github.com/Azure/azure-container-networking/npm/util/errors"
+	"k8s.io/klog"
+)
+
+const (
+	nomatchSuffix = " nomatch"
+	// ipset defaults to tcp for members without a protocol
+	defaultNamedPortProtocol = "tcp"
+)
+
+// UseNFTables makes the IPSetManager apply its sets as named sets of the nftables ruleset instead of with ipset.
+func (iMgr *IPSetManager) UseNFTables(ruleset *nftables.Ruleset) {
+	iMgr.nftRuleset = ruleset
+}
+
+// resetNFTablesSets empties the sets of the ruleset, and destroys the ipsets NPM may have left when it ran with iptables
+// before. The ipsets are destroyed after the iptables chains referencing them, which PolicyManager bootup removes.
+// Failing to destroy them is only reported, since ipset may not be installed.
+func (iMgr *IPSetManager) resetNFTablesSets() error {
+	if err := iMgr.nftRuleset.ResetSets(); err != nil {
+		return npmerrors.SimpleErrorWrapper("failed to reset nftables sets", err)
+	}
+	if err := iMgr.resetKernelIPSets(); err != nil {
+		metrics.SendErrorLogAndMetric(util.IpsmID, "failed to clean up ipsets. err: %v", err)
+	}
+	return nil
+}
+
+/*
+applyNFTablesSets replaces the dirty sets of the ruleset in one nft transaction.
+
+nftables sets can't hold other sets, so a list is a set with the members of its member sets.
+Lists in the kernel with a dirty member are replaced too.
+*/
+func (iMgr *IPSetManager) applyNFTablesSets() error {
+	setsToAddOrUpdate := iMgr.dirtyCache.setsToAddOrUpdate()
+	toUpdate := make([]*nftables.Set, 0, len(setsToAddOrUpdate))
+	for prefixedName := range setsToAddOrUpdate {
+		toUpdate = append(toUpdate, iMgr.nftSet(iMgr.setMap[prefixedName]))
+	}
+
+	for prefixedName, set := range iMgr.setMap {
+		if _, ok := setsToAddOrUpdate[prefixedName]; ok || set.Kind != ListSet || !iMgr.nftRuleset.HasSet(set.HashedName) {
+			continue
+		}
+		for memberName := range set.MemberIPSets {
+			if _, ok := setsToAddOrUpdate[memberName]; ok {
+				toUpdate = append(toUpdate, iMgr.nftSet(set))
+				break
+			}
+		}
+	}
+
+	setsToDelete := iMgr.dirtyCache.setsToDelete()
+	toDelete := make([]string, 0, len(setsToDelete))
+	for prefixedName := range setsToDelete {
+		toDelete = append(toDelete, util.GetHashedName(prefixedName))
+	}
+
+	klog.Infof("[IPSetManager] applying %d nftables sets and deleting %d", len(toUpdate), len(toDelete))
+	if err := iMgr.nftRuleset.ApplySets(toUpdate, toDelete); err != nil {
+		return npmerrors.SimpleErrorWrapper("failed to apply nftables sets", err)
+	}
+	return nil
+}
+
+// nftSet returns the nftables set with the members of the set.
+func (iMgr *IPSetManager) nftSet(set *IPSet) *nftables.Set {
+	nftSet := &nftables.Set{
+		Name: set.HashedName,
+		Type: nftables.IPSetType,
+	}
+
+	var members []string
+	if set.Kind == ListSet {
+		for _, member := range set.MemberIPSets {
+			for ip := range member.IPPodKey {
+				members = append(members, ip)
+			}
+			nftSet.Interval = nftSet.Interval || member.Type == CIDRBlocks
+		}
+	} else {
+		for ip := range set.IPPodKey {
+			members = append(members, ip)
+		}
+		nftSet.Interval = set.Type == CIDRBlocks
+	}
+
+	switch {
+	case set.Type == NamedPorts:
+		nftSet.Type = nftables.IPPortSetType
+		for _, member := range members {
+			element, err := namedPortElement(member)
+			if err != nil {
+				metrics.SendErrorLogAndMetric(util.IpsmID, "error: skipping member of nftables set %s: %s", set.Name, err.Error())
+				continue
+			}
+			nftSet.Elements = append(nftSet.Elements, element)
+		}
+	case nftSet.Interval:
+		nftSet.Elements = cidrElements(members)
+	default:
+		nftSet.Elements = members
+	}
+	return nftSet
+}
+
+// namedPortElement converts an ipset member like 10.0.0.1,TCP:8080 to the element 10.0.0.1 . tcp . 8080.
+func namedPortElement(member string) (string, error) {
+	ipAndPort := strings.SplitN(member, ",", 2)
+	if len(ipAndPort) != 2 {
+		return "", fmt.Errorf("named port member %s has no port", member)
+	}
+	protocol, port := defaultNamedPortProtocol, ipAndPort[1]
+	if protocolAndPort := strings.SplitN(port, ":", 2); len(protocolAndPort) == 2 {
+		protocol, port = strings.ToLower(protocolAndPort[0]), protocolAndPort[1]
+	}
+	return fmt.Sprintf("%s . %s . %s", ipAndPort[0], protocol, port), nil
+}
+
+type ipRange struct {
+	first, last uint32
+}
+
+type cidrMember struct {
+	ipRange
+	prefixLength int
+	nomatch      bool
+}
+
+/*
+cidrElements converts the members of a hash:net ipset to the CIDRs of an interval set.
+
+ipset matches the most specific CIDR of a set, so an address in a nomatch CIDR is only matched if it is also in a
+more specific CIDR. Applying the CIDRs from least to most specific, adding the others and removing the nomatch ones,
+gives the same addresses.
+
+example:
+
+	members: 10.0.0.0/16, 10.0.0.0/24 nomatch
+	elements: 10.0.1.0/24, 10.0.2.0/23, 10.0.4.0/22, 10.0.8.0/21, 10.0.16.0/20, 10.0.32.0/19, 10.0.64.0/18, 10.0.128.0/17
+*/
+func cidrElements(members []string) []string {
+	cidrs := make([]cidrMember, 0, len(members))
+	for _, member := range members {
+		cidr, err := parseCIDRMember(member)
+		if err != nil {
+			metrics.SendErrorLogAndMetric(util.IpsmID, "error: skipping CIDR member of nftables set: %s", err.Error())
+			continue
+		}
+		cidrs = append(cidrs, cidr)
+	}
+	// the members of a list come in no particular order, so CIDRs as specific as each other are sorted by address, and
+	// the nomatch one first when the members of a list have the same CIDR, since the list matches if any member does
+	sort.Slice(cidrs, func(i, j int) bool {
+		if cidrs[i].prefixLength != cidrs[j].prefixLength {
+			return cidrs[i].prefixLength < cidrs[j].prefixLength
+		}
+		if cidrs[i].first != cidrs[j].first {
+			return cidrs[i].first < cidrs[j].first
+		}
+		return cidrs[i].nomatch && !cidrs[j].nomatch
+	})
+
+	var ranges []ipRange
+	for _, cidr := range cidrs {
+		ranges = subtractRange(ranges, cidr.ipRange)
+		if !cidr.nomatch {
+			ranges = append(ranges, cidr.ipRange)
+		}
+	}
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].first < ranges[j].first })
+
+	var elements []string
+	for _, r := range mergeRanges(ranges) {
+		elements = append(elements, rangeToCIDRs(r)...)
+	}
+	return elements
+}
+
+func parseCIDRMember(member string) (cidrMember, error) {
+	cidr := cidrMember{}
+	if strings.HasSuffix(member, nomatchSuffix) {
+		cidr.nomatch = true
+		member = strings.TrimSuffix(member, nomatchSuffix)
+	}
+	if !strings.Contains(member, "/") {
+		member += "/32"
+	}
+	_, ipNet, err := net.ParseCIDR(member)
+	if err != nil || ipNet.IP.To4() == nil {
+		return cidr, fmt.Errorf("invalid IPv4 CIDR %s", member)
+	}
+	cidr.prefixLength, _ = ipNet.Mask.Size()
+	cidr.first = binary.BigEndian.Uint32(ipNet.IP.To4())
+	cidr.last = cidr.first | ^binary.BigEndian.Uint32(ipNet.Mask)
+	return cidr, nil
+}
+
+// subtractRange returns the ranges without the addresses in r.
+func subtractRange(ranges []ipRange, r ipRange) []ipRange {
+	result := make([]ipRange, 0, len(ranges)+1)
+	for _, other := range ranges {
+		if other.last < r.first || other.first > r.last {
+			result = append(result, other)
+			continue
+		}
+		if other.first < r.first {
+			result = append(result, ipRange{other.first, r.first - 1})
+		}
+		if other.last > r.last {
+			result = append(result, ipRange{r.last + 1, other.last})
+		}
+	}
+	return result
+}
+
+// mergeRanges merges sorted ranges that overlap or are adjacent.
+func mergeRanges(ranges []ipRange) []ipRange {
+	merged := make([]ipRange, 0, len(ranges))
+	for _, r := range ranges {
+		if n := len(merged); n > 0 && merged[n-1].last != ^uint32(0) && r.first <= merged[n-1].last+1 {
+			if r.last > merged[n-1].last {
+				merged[n-1].last = r.last
+			}
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+// rangeToCIDRs returns the fewest CIDRs covering exactly the range.
+func rangeToCIDRs(r ipRange) []string {
+	var cidrs []string
+	first := uint64(r.first)
+	last := uint64(r.last)
+	for first <= last {
+		// the largest block aligned on first that doesn't go past last
+		size := 32
+		if first > 0 {
+			size = bits.TrailingZeros64(first)
+			if size > 32 {
+				size = 32
+			}
+		}
+		for size > 0 && first+(uint64(1)<<size)-1 > last {
+			size--
+		}
+		ip := make(net.IP, net.IPv4len)
+		binary.BigEndian.PutUint32(ip, uint32(first))
+		cidrs = append(cidrs, fmt.Sprintf("%s/%d", ip.String(), 32-size))
+		first += uint64(1) << size
+	}
+	return cidrs
+}
@@ -0,0 +1,119 @@
+package ipsets
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Azure/azure-container-networking/common"
+	"github.com/Azure/azure-container-networking/npm/pkg/dataplane/nftables"
+	"github.com/Azure/azure-container-networking/npm/util"
+	testutils "github.com/Azure/azure-container-networking/test/utils"
+	"github.com/stretchr/testify/require"
+)
+
+var fakeNFTCommand = testutils.TestCmd{Cmd: []string{"nft", "-f", "-"}}
+
+func TestApplyNFTablesSets(t *testing.T) {
+	calls := []testutils.TestCmd{fakeNFTCommand, fakeNFTCommand, fakeNFTCommand}
+	ioshim := common.NewMockIOShim(calls)
+	defer ioshim.VerifyCalls(t, calls)
+	ruleset := nftables.NewRuleset(ioshim, util.PlaceAzureChainFirst)
+	iMgr := NewIPSetManager(applyAlwaysCfg, ioshim)
+	iMgr.UseNFTables(ruleset)
+
+	require.NoError(t, iMgr.AddToSets([]*IPSetMetadata{TestKeyPodSet.Metadata}, "10.0.0.1", "a"))
+	require.NoError(t, iMgr.AddToSets([]*IPSetMetadata{TestNamedportSet.Metadata}, "10.0.0.1,UDP:53", "a"))
+	require.NoError(t, iMgr.AddToSets([]*IPSetMetadata{TestCIDRSet.Metadata}, "10.0.0.0/16", ""))
+	require.NoError(t, iMgr.AddToLists([]*IPSetMetadata{TestNestedLabelList.Metadata}, []*IPSetMetadata{TestKeyPodSet.Metadata}))
+	require.NoError(t, iMgr.ApplyIPSets())
+
+	rendered := ruleset.Render()
+	require.Contains(t, rendered, fmt.Sprintf("\tset %s {\n\t\ttype ipv4_addr\n\t\telements = { 10.0.0.1 }\n\t}\n", TestKeyPodSet.HashedName))
+	require.Contains(t, rendered, fmt.Sprintf("\tset %s {\n\t\ttype ipv4_addr . inet_proto . inet_service\n\t\telements = { 10.0.0.1 . udp . 53 }\n\t}\n", TestNamedportSet.HashedName))
+	require.Contains(t, rendered, fmt.Sprintf("\tset %s {\n\t\ttype ipv4_addr\n\t\tflags interval\n\t\tauto-merge\n\t\telements = { 10.0.0.0/16 }\n\t}\n", TestCIDRSet.HashedName))
+	require.Contains(t, rendered, fmt.Sprintf("\tset %s {\n\t\ttype ipv4_addr\n\t\telements = { 10.0.0.1 }\n\t}\n", TestNestedLabelList.HashedName))
+
+	// the list is replaced with its updated member
+	require.NoError(t, iMgr.AddToSets([]*IPSetMetadata{TestKeyPodSet.Metadata}, "10.0.0.2", "b"))
+	require.NoError(t, iMgr.ApplyIPSets())
+	require.Contains(t, ruleset.Render(), fmt.Sprintf("\tset %s {\n\t\ttype ipv4_addr\n\t\telements = { 10.0.0.1, 10.0.0.2 }\n\t}\n", TestNestedLabelList.HashedName))
+
+	iMgr.DeleteIPSet(TestCIDRSet.PrefixName, util.ForceDelete)
+	require.NoError(t, iMgr.ApplyIPSets())
+	require.False(t, ruleset.HasSet(TestCIDRSet.HashedName))
+}
+
+func TestResetNFTablesSets(t *testing.T) {
+	// the ipsets NPM left when it ran with iptables are destroyed after the nftables sets are emptied
+	calls := append([]testutils.TestCmd{fakeNFTCommand}, GetResetTestCalls()...)
+	ioshim := common.NewMockIOShim(calls)
+	defer ioshim.VerifyCalls(t, calls)
+	iMgr := NewIPSetManager(applyAlwaysCfg, ioshim)
+	iMgr.UseNFTables(nftables.NewRuleset(ioshim, util.PlaceAzureChainFirst))
+	require.NoError(t, iMgr.ResetIPSets())
+
+}
+
+func TestNamedPortElement(t *testing.T) {
+	element, err := namedPortElement("10.0.0.1,TCP:8080")
+	require.NoError(t, err)
+	require.Equal(t, "10.0.0.1 . tcp . 8080", element)
+
+	element, err = namedPortElement("10.0.0.1,8080")
+	require.NoError(t, err)
+	require.Equal(t, "10.0.0.1 . tcp . 8080", element)
+
+	_, err = namedPortElement("10.0.0.1")
+	require.Error(t, err)
+}
+
+func TestCIDRElements(t *testing.T) {
+	tests := []struct {
+		name     string
+		members  []string
+		expected []string
+	}{
+		{
+			name:     "nomatch inside CIDR",
+			members:  []string{"10.0.0.0/24 nomatch", "10.0.0.0/16"},
+			expected: []string{"10.0.1.0/24", "10.0.2.0/23", "10.0.4.0/22", "10.0.8.0/21", "10.0.16.0/20", "10.0.32.0/19", "10.0.64.0/18", "10.0.128.0/17"},
+		},
+		{
+			name:     "CIDR inside nomatch",
+			members:  []string{"10.0.0.0/16", "10.0.0.0/15 nomatch"},
+			expected: []string{"10.0.0.0/16"},
+		},
+		{
+			name:     "same CIDR as nomatch and not",
+			members:  []string{"10.0.0.0/24", "10.0.0.0/24 nomatch"},
+			expected: []string{"10.0.0.0/24"},
+		},
+		{
+			name:     "same CIDR as not and nomatch",
+			members:  []string{"10.0.0.0/24 nomatch", "10.0.0.0/24"},
+			expected: []string{"10.0.0.0/24"},
+		},
+		{
+			name:     "adjacent CIDRs and single IP",
+			members:  []string{"10.0.0.0/25", "10.0.0.128/25", "10.1.0.1"},
+			expected: []string{"10.0.0.0/24", "10.1.0.1/32"},
+		},
+		{
+			name:     "whole address space",
+			members:  []string{"0.0.0.0/0", "10.0.0.0/8"},
+			expected: []string{"0.0.0.0/0"},
+		},
+		{
+			name:     "invalid member",
+			members:  []string{"abc", "10.0.0.0/8"},
+			expected: []string{"10.0.0.0/8"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, cidrElements(tt.members))
+		})
+	}
+}
@@ -0,0 +1,369 @@
+// Package nftables renders the NPM dataplane as a single nftables table.
+//
+// The table mirrors the iptables dataplane: the same base chains, marks and policy chains, with nftables named sets
+// in place of ipsets. Instead of jumping to every policy chain with a match on the pod selector ipsets, the ingress and
+// egress chains look up the pod IP in a verdict map, which jumps to a chain per pod with the jumps to the policy chains
+// selecting the pod.
+//
+// The table is replaced in one nft transaction the first time it's applied. After that, only the elements of the sets
+// and maps and the chains which changed are updated, in one nft transaction too, so the kernel never holds a partial
+// ruleset.
+//
+// NPM filters the forwarded traffic in a base chain of its own, right before or right after the filter priority of the
+// iptables FORWARD chain, instead of with a jump from FORWARD. Unlike a jump, a base chain doesn't skip the packets
+// which an earlier base chain accepts: accepting a packet only ends the base chain it is in, while dropping it is final.
+// When NPM is placed after the Kubernetes chains, the packets KUBE-FORWARD accepts, e.g. those kube-proxy marked to
+// masquerade, still go through NPM, and when NPM is placed first, the packets NPM accepts still go through FORWARD.
+// Only new connections go through NPM, so the established connections KUBE-FORWARD accepts aren't filtered again.
+package nftables
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Azure/azure-container-networking/common"
+	"github.com/Azure/azure-container-networking/npm/util"
+)
+
+const (
+	// Family and TableName identify the NPM table.
+	Family    = "inet"
+	TableName = "azure-npm"
+
+	forwardChain          = "forward"
+	ingressPodsMap        = "AZURE-NPM-INGRESS-PODS"
+	egressPodsMap         = "AZURE-NPM-EGRESS-PODS"
+	ingressPodChainPrefix = "AZURE-NPM-INGRESS-POD"
+	egressPodChainPrefix  = "AZURE-NPM-EGRESS-POD"
+
+	// priorities of the forward chain relative to the filter priority (0) of the iptables FORWARD chain. The forward
+	// chain is not skipped by the verdicts of FORWARD, see the package doc.
+	placeFirstPriority = "-1"
+	placeAfterPriority = "1"
+
+	// nftables truncates longer comments
+	maxCommentLength = 127
+)
+
+// SetType is the nftables type of the elements of a set.
+type SetType string
+
+const (
+	// IPSetType holds IPv4 addresses (and CIDRs in interval sets).
+	IPSetType SetType = "ipv4_addr"
+	// IPPortSetType holds IPv4 address, protocol and port concatenations, for named ports.
+	IPPortSetType SetType = "ipv4_addr . inet_proto . inet_service"
+)
+
+// Set is a named set of the table.
+type Set struct {
+	Name string
+	Type SetType
+	// Interval sets hold CIDRs and can't be used to select pods.
+	Interval bool
+	Elements []string
+}
+
+// Selector matches the IPs in (or not in) a set.
+type Selector struct {
+	Set      string
+	Included bool
+}
+
+// PolicyChain is the ingress or egress chain of a network policy.
+type PolicyChain struct {
+	Name string
+	// Comment describes the jump to the chain.
+	Comment string
+	// Selectors select the pods the chain applies to. Without any, the chain applies to all traffic.
+	Selectors []Selector
+	Rules     []string
+}
+
+// Policy holds the chains of a network policy. Either chain may be nil.
+type Policy struct {
+	Key     string
+	Ingress *PolicyChain
+	Egress  *PolicyChain
+}
+
+// Ruleset is the desired state of the NPM table, shared by the IPSetManager and the PolicyManager.
+type Ruleset struct {
+	sync.Mutex
+	ioShim     *common.IOShim
+	placeFirst bool
+	sets       map[string]*Set
+	policies   map[string]*Policy
+	// applied is the table in the kernel, nil until the table is replaced or when it may differ from the kernel.
+	applied *table
+}
+
+func NewRuleset(ioShim *common.IOShim, placeFirst bool) *Ruleset {
+	return &Ruleset{
+		ioShim:     ioShim,
+		placeFirst: placeFirst,
+		sets:       make(map[string]*Set),
+		policies:   make(map[string]*Policy),
+	}
+}
+
+// Render returns the nft script replacing the NPM table.
+func (r *Ruleset) Render() string {
+	r.Lock()
+	defer r.Unlock()
+	return r.table().replaceScript()
+}
+
+// table returns the NPM table with the sets and policies of the ruleset.
+func (r *Ruleset) table() *table {
+	t := &table{}
+	for _, name := range sortedKeys(r.sets) {
+		t.objects = append(t.objects, r.sets[name].object())
+	}
+
+	ingress, egress := r.policyChains()
+	// chains are declared before the chains and maps jumping to them
+	t.addChain(util.IptablesAzureAcceptChain, "accept")
+	r.addDirection(t, egress, egressPodChainPrefix, egressPodsMap)
+	t.addChain(util.IptablesAzureEgressChain, r.baseChainRules(egress, "saddr", egressPodsMap,
+		fmt.Sprintf("%s drop comment %s", matchMark(util.IptablesAzureEgressDropMarkHex), quote("DROP-ON-EGRESS-DROP-MARK-"+util.IptablesAzureEgressDropMarkHex)),
+		fmt.Sprintf("%s jump %s comment %s", matchMark(util.IptablesAzureIngressAllowMarkHex), util.IptablesAzureAcceptChain,
+			quote("ACCEPT-ON-INGRESS-ALLOW-MARK-"+util.IptablesAzureIngressAllowMarkHex)),
+	)...)
+	t.addChain(util.IptablesAzureIngressAllowMarkChain,
+		fmt.Sprintf("%s comment %s", SetMark(util.IptablesAzureIngressAllowMarkHex), quote("SET-INGRESS-ALLOW-MARK-"+util.IptablesAzureIngressAllowMarkHex)),
+		"jump "+util.IptablesAzureEgressChain,
+	)
+	r.addDirection(t, ingress, ingressPodChainPrefix, ingressPodsMap)
+	t.addChain(util.IptablesAzureIngressChain, r.baseChainRules(ingress, "daddr", ingressPodsMap,
+		fmt.Sprintf("%s drop comment %s", matchMark(util.IptablesAzureIngressDropMarkHex), quote("DROP-ON-INGRESS-DROP-MARK-"+util.IptablesAzureIngressDropMarkHex)),
+	)...)
+
+	// to leave NPM deactivated without policies, don't specify any rules for the AZURE-NPM chain
+	var azureRules []string
+	if len(r.policies) > 0 {
+		azureRules = []string{
+			"jump " + util.IptablesAzureIngressChain,
+			"jump " + util.IptablesAzureEgressChain,
+			"jump " + util.IptablesAzureAcceptChain,
+		}
+	}
+	t.addChain(util.IptablesAzureChain, azureRules...)
+
+	priority := placeAfterPriority
+	if r.placeFirst {
+		priority = placeFirstPriority
+	}
+	t.objects = append(t.objects, &object{
+		kind:  chainKind,
+		name:  forwardChain,
+		spec:  []string{fmt.Sprintf("type filter hook forward priority %s; policy accept;", priority)},
+		rules: []string{"ct state new jump " + util.IptablesAzureChain},
+	})
+	return t
+}
+
+// policyChains returns the ingress and egress chains of all policies, sorted by name.
+func (r *Ruleset) policyChains() (ingress, egress []*PolicyChain) {
+	for _, key := range sortedKeys(r.policies) {
+		policy := r.policies[key]
+		if policy.Ingress != nil {
+			ingress = append(ingress, policy.Ingress)
+		}
+		if policy.Egress != nil {
+			egress = append(egress, policy.Egress)
+		}
+	}
+	sort.Slice(ingress, func(i, j int) bool { return ingress[i].Name < ingress[j].Name })
+	sort.Slice(egress, func(i, j int) bool { return egress[i].Name < egress[j].Name })
+	return ingress, egress
+}
+
+// addDirection adds the policy chains of a direction, the chains per pod and the verdict map to them.
+func (r *Ruleset) addDirection(t *table, chains []*PolicyChain, podChainPrefix, podsMap string) {
+	for _, chain := range chains {
+		t.addChain(chain.Name, chain.Rules...)
+	}
+
+	podChains := r.podChains(chains)
+	ips := sortedKeys(podChains)
+	elements := make([]string, 0, len(ips))
+	for _, ip := range ips {
+		podChain := podChainName(podChainPrefix, ip)
+		rules := make([]string, 0, len(podChains[ip]))
+		for _, chain := range podChains[ip] {
+			rules = append(rules, fmt.Sprintf("jump %s comment %s", chain.Name, quote(chain.Comment)))
+		}
+		t.addChain(podChain, rules...)
+		elements = append(elements, ip+mapKeySeparator+"jump "+podChain)
+	}
+
+	t.objects = append(t.objects, &object{
+		kind:     mapKind,
+		name:     podsMap,
+		spec:     []string{fmt.Sprintf("type %s : verdict", IPSetType)},
+		elements: elements,
+	})
+}
+
+// podChains returns the policy chains to jump to for each selected pod IP.
+func (r *Ruleset) podChains(chains []*PolicyChain) map[string][]*PolicyChain {
+	podChains := make(map[string][]*PolicyChain)
+	for _, chain := range chains {
+		ips, ok := r.selectedIPs(chain.Selectors)
+		if !ok {
+			continue
+		}
+		for _, ip := range ips {
+			podChains[ip] = append(podChains[ip], chain)
+		}
+	}
+	return podChains
+}
+
+// baseChainRules returns the rules of the ingress or egress chain: the jumps to the policy chains whose pods can't be
+// looked up, the lookup of the pod verdict map, then the rules acting on the marks.
+func (r *Ruleset) baseChainRules(chains []*PolicyChain, addr, podsMap string, markRules ...string) []string {
+	rules := make([]string, 0, len(markRules)+1)
+	for _, chain := range chains {
+		if _, ok := r.selectedIPs(chain.Selectors); ok {
+			continue
+		}
+		rule := make([]string, 0, len(chain.Selectors)+1)
+		for _, selector := range chain.Selectors {
+			rule = append(rule, MatchSet("ip "+addr, selector.Set, selector.Included))
+		}
+		rule = append(rule, fmt.Sprintf("jump %s comment %s", chain.Name, quote(chain.Comment)))
+		rules = append(rules, strings.Join(rule, " "))
+	}
+	rules = append(rules, fmt.Sprintf("ip %s vmap @%s", addr, podsMap))
+	return append(rules, markRules...)
+}
+
+// selectedIPs returns the IPs in all included sets of the selectors and in none of the excluded sets.
+// It returns false if the IPs can't be listed: without an included set, or with an interval set.
+// Missing sets are taken to be empty.
+func (r *Ruleset) selectedIPs(selectors []Selector) ([]string, bool) {
+	var included []*Set
+	for _, selector := range selectors {
+		set, ok := r.sets[selector.Set]
+		if ok && set.Interval {
+			return nil, false
+		}
+		if selector.Included {
+			if !ok {
+				set = &Set{Name: selector.Set}
+			}
+			included = append(included, set)
+		}
+	}
+	if len(included) == 0 {
+		return nil, false
+	}
+
+	// the first included set has every selected IP
+	ips := make([]string, 0, len(included[0].Elements))
+	for _, ip := range included[0].Elements {
+		if r.isSelected(ip, selectors) {
+			ips = append(ips, ip)
+		}
+	}
+	return ips, true
+}
+
+func (r *Ruleset) isSelected(ip string, selectors []Selector) bool {
+	for _, selector := range selectors {
+		set, ok := r.sets[selector.Set]
+		if (ok && set.hasElement(ip)) != selector.Included {
+			return false
+		}
+	}
+	return true
+}
+
+func (set *Set) hasElement(element string) bool {
+	for _, e := range set.Elements {
+		if e == element {
+			return true
+		}
+	}
+	return false
+}
+
+func (set *Set) object() *object {
+	o := &object{kind: setKind, name: set.Name, spec: []string{fmt.Sprintf("type %s", set.Type)}}
+	if set.Interval {
+		o.spec = append(o.spec, "flags interval", "auto-merge")
+	}
+	o.elements = append([]string(nil), set.Elements...)
+	sort.Strings(o.elements)
+	return o
+}
+
+// MatchSet returns the match of the expression (e.g. "ip saddr") on the elements of a set.
+func MatchSet(expression, set string, included bool) string {
+	if included {
+		return fmt.Sprintf("%s @%s", expression, set)
+	}
+	return fmt.Sprintf("%s != @%s", expression, set)
+}
+
+// SetMark returns the statement setting an iptables mark of the form value/mask.
+func SetMark(mark string) string {
+	return "meta mark set meta mark | " + markValue(mark)
+}
+
+func matchMark(mark string) string {
+	value := markValue(mark)
+	return fmt.Sprintf("meta mark & %s == %s", value, value)
+}
+
+// markValue returns the value of an iptables mark of the form value/mask.
+func markValue(mark string) string {
+	return strings.SplitN(mark, "/", 2)[0]
+}
+
+// Comment returns the comment statement for a rule.
+func Comment(comment string) string {
+	return "comment " + quote(comment)
+}
+
+func quote(comment string) string {
+	comment = strings.ReplaceAll(comment, `"`, "")
+	if len(comment) > maxCommentLength {
+		comment = comment[:maxCommentLength]
+	}
+	return `"` + comment + `"`
+}
+
+func podChainName(prefix, ip string) string {
+	return fmt.Sprintf("%s-%s", prefix, util.Hash(ip))
+}
+
+func writeElements(w *writer, elements []string) {
+	if len(elements) == 0 {
+		return
+	}
+	w.line(2, "elements = { %s }", strings.Join(elements, ", "))
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+type writer struct {
+	strings.Builder
+}
+
+func (w *writer) line(indent int, format string, args ...interface{}) {
+	w.WriteString(strings.Repeat("\t", indent))
+	fmt.Fprintf(w, format, args...)
+	w.WriteString("\n")
+}
@@ -0,0 +1,132 @@
+package nftables
+
+import (
+	"strings"
+
+	npmerrors "github.com/Azure/azure-container-networking/npm/util/errors"
+	"github.com/Azure/azure-container-networking/npm/util/ioutil"
+	"k8s.io/klog"
+)
+
+const (
+	nftCommand   = "nft"
+	nftFileFlag  = "-f"
+	nftStdinFile = "-"
+
+	maxTryCount = 2
+	// e.g. /dev/stdin:12:9-24: Error: Could not process rule: No such file or directory
+	lineErrorPattern = "/dev/stdin:(\\d+):"
+)
+
+// Reset replaces the NPM table with one without sets and policies, whatever the table in the kernel holds.
+func (r *Ruleset) Reset() error {
+	r.Lock()
+	defer r.Unlock()
+	r.sets = make(map[string]*Set)
+	r.policies = make(map[string]*Policy)
+	r.applied = nil
+	return r.apply()
+}
+
+// ResetSets removes all sets from the NPM table. The policies must not reference any set.
+func (r *Ruleset) ResetSets() error {
+	r.Lock()
+	defer r.Unlock()
+	r.sets = make(map[string]*Set)
+	return r.apply()
+}
+
+// ApplySets adds or replaces the sets in toUpdate and removes the sets in toDelete.
+// On failure, the ruleset is left as it was.
+func (r *Ruleset) ApplySets(toUpdate []*Set, toDelete []string) error {
+	r.Lock()
+	defer r.Unlock()
+
+	original := make(map[string]*Set, len(r.sets))
+	for name, set := range r.sets {
+		original[name] = set
+	}
+	for _, set := range toUpdate {
+		r.sets[set.Name] = set
+	}
+	for _, name := range toDelete {
+		delete(r.sets, name)
+	}
+
+	if err := r.apply(); err != nil {
+		r.sets = original
+		return err
+	}
+	return nil
+}
+
+// HasSet returns whether the set is in the ruleset.
+func (r *Ruleset) HasSet(name string) bool {
+	r.Lock()
+	defer r.Unlock()
+	_, ok := r.sets[name]
+	return ok
+}
+
+// ApplyPolicies adds or replaces the policies in toAdd and removes the policies with the keys in toRemove.
+// On failure, the ruleset is left as it was.
+func (r *Ruleset) ApplyPolicies(toAdd []*Policy, toRemove []string) error {
+	r.Lock()
+	defer r.Unlock()
+
+	original := make(map[string]*Policy, len(r.policies))
+	for key, policy := range r.policies {
+		original[key] = policy
+	}
+	for _, policy := range toAdd {
+		r.policies[policy.Key] = policy
+	}
+	for _, key := range toRemove {
+		delete(r.policies, key)
+	}
+
+	if err := r.apply(); err != nil {
+		r.policies = original
+		return err
+	}
+	return nil
+}
+
+// apply updates the NPM table in the kernel to the ruleset. The caller must lock the ruleset.
+// The sets, maps and chains which changed since the table was last applied are updated in place. The whole table is
+// replaced the first time, when a declaration changed, or when the update fails, e.g. because the table was changed
+// outside NPM.
+func (r *Ruleset) apply() error {
+	desired := r.table()
+	if r.applied != nil {
+		script, ok := desired.updateScript(r.applied)
+		if ok {
+			if script == "" {
+				return nil
+			}
+			klog.Infof("[nftables] updating table %s %s with %d sets and %d policies", Family, TableName, len(r.sets), len(r.policies))
+			err := r.runScript(script, 1)
+			if err == nil {
+				r.applied = desired
+				return nil
+			}
+			klog.Warningf("[nftables] failed to update table %s %s, replacing it. err: %s", Family, TableName, err.Error())
+		}
+		r.applied = nil
+	}
+
+	klog.Infof("[nftables] replacing table %s %s with %d sets and %d policies", Family, TableName, len(r.sets), len(r.policies))
+	if err := r.runScript(desired.replaceScript(), maxTryCount); err != nil {
+		return npmerrors.SimpleErrorWrapper("failed to replace nftables table", err)
+	}
+	r.applied = desired
+	return nil
+}
+
+func (r *Ruleset) runScript(script string, tryCount int) error {
+	creator := ioutil.NewFileCreator(r.ioShim, tryCount, lineErrorPattern)
+	for _, line := range strings.Split(strings.TrimSuffix(script, "\n"), "\n") {
+		creator.AddLine("", nil, line)
+	}
+	return creator.RunCommandWithFile(nftCommand, nftFileFlag, nftStdinFile) //nolint:wrapcheck // the callers add the context
+}
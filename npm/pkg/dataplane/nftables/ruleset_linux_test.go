@@ -0,0 +1,67 @@
+package nftables
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-container-networking/common"
+	"github.com/Azure/azure-container-networking/npm/util"
+	testutils "github.com/Azure/azure-container-networking/test/utils"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	fakeNFTCommand        = testutils.TestCmd{Cmd: []string{"nft", "-f", "-"}}
+	fakeNFTFailureCommand = testutils.TestCmd{Cmd: []string{"nft", "-f", "-"}, Stdout: "/dev/stdin:5:9-20: Error: syntax error", ExitCode: 1}
+)
+
+func TestApplySetsAndPolicies(t *testing.T) {
+	calls := []testutils.TestCmd{fakeNFTCommand, fakeNFTCommand, fakeNFTCommand, fakeNFTCommand}
+	ioshim := common.NewMockIOShim(calls)
+	defer ioshim.VerifyCalls(t, calls)
+	r := NewRuleset(ioshim, util.PlaceAzureChainFirst)
+
+	require.NoError(t, r.Reset())
+	require.NoError(t, r.ApplySets([]*Set{{Name: "azure-npm-1", Type: IPSetType}}, nil))
+	require.True(t, r.HasSet("azure-npm-1"))
+	require.NoError(t, r.ApplyPolicies([]*Policy{{Key: "x/a"}}, nil))
+	require.Len(t, r.policies, 1)
+	require.NoError(t, r.ApplySets(nil, []string{"azure-npm-1"}))
+	require.False(t, r.HasSet("azure-npm-1"))
+}
+
+func TestApplyFailureKeepsRuleset(t *testing.T) {
+	calls := []testutils.TestCmd{fakeNFTFailureCommand, fakeNFTFailureCommand, fakeNFTFailureCommand, fakeNFTFailureCommand}
+	ioshim := common.NewMockIOShim(calls)
+	defer ioshim.VerifyCalls(t, calls)
+	r := NewRuleset(ioshim, util.PlaceAzureChainFirst)
+	r.sets["azure-npm-1"] = &Set{Name: "azure-npm-1", Type: IPSetType}
+
+	require.Error(t, r.ApplySets([]*Set{{Name: "azure-npm-2", Type: IPSetType}}, []string{"azure-npm-1"}))
+	require.True(t, r.HasSet("azure-npm-1"))
+	require.False(t, r.HasSet("azure-npm-2"))
+
+	require.Error(t, r.ApplyPolicies([]*Policy{{Key: "x/a"}}, nil))
+	require.Empty(t, r.policies)
+}
+
+func TestApplyUpdatesTable(t *testing.T) {
+	calls := []testutils.TestCmd{
+		// the table is replaced on bootup
+		fakeNFTCommand,
+		// the update fails, e.g. because the table was changed outside NPM, so the table is replaced instead
+		fakeNFTFailureCommand,
+		fakeNFTCommand,
+		// then updated again
+		fakeNFTCommand,
+	}
+	ioshim := common.NewMockIOShim(calls)
+	defer ioshim.VerifyCalls(t, calls)
+	r := NewRuleset(ioshim, util.PlaceAzureChainFirst)
+
+	require.NoError(t, r.Reset())
+	// nothing changed, so nft isn't run
+	require.NoError(t, r.ApplySets(nil, []string{"azure-npm-missing"}))
+	require.NoError(t, r.ApplySets([]*Set{{Name: "azure-npm-1", Type: IPSetType}}, nil))
+	require.NotNil(t, r.applied)
+	require.NoError(t, r.ApplySets([]*Set{{Name: "azure-npm-2", Type: IPSetType}}, nil))
+}
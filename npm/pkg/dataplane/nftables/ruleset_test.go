@@ -0,0 +1,130 @@
+package nftables
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-container-networking/npm/util"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update the rendered rulesets in testdata")
+
+// requireGolden checks the rendered ruleset against testdata/<name>.nft, which -update rewrites.
+func requireGolden(t *testing.T, name, rendered string) {
+	t.Helper()
+	file := filepath.Join("testdata", name+".nft")
+	if *update {
+		require.NoError(t, os.WriteFile(file, []byte(rendered), 0o600))
+	}
+	expected, err := os.ReadFile(file)
+	require.NoError(t, err)
+	require.Equal(t, string(expected), rendered)
+}
+
+func testRuleset() *Ruleset {
+	r := NewRuleset(nil, util.PlaceAzureChainFirst)
+	r.sets["azure-npm-1"] = &Set{Name: "azure-npm-1", Type: IPSetType, Elements: []string{"10.0.0.1", "10.0.0.2", "10.0.0.3"}}
+	r.sets["azure-npm-2"] = &Set{Name: "azure-npm-2", Type: IPSetType, Elements: []string{"10.0.0.2"}}
+	r.sets["azure-npm-3"] = &Set{Name: "azure-npm-3", Type: IPSetType, Interval: true, Elements: []string{"10.0.0.0/24"}}
+	return r
+}
+
+func TestRenderWithoutPolicies(t *testing.T) {
+	rendered := testRuleset().Render()
+	requireGolden(t, "without-policies", rendered)
+	// NPM is deactivated
+	require.Contains(t, rendered, "\tchain AZURE-NPM {\n\t}\n")
+	require.Contains(t, rendered, "type filter hook forward priority -1; policy accept;")
+	require.Contains(t, rendered, "\tset azure-npm-3 {\n\t\ttype ipv4_addr\n\t\tflags interval\n\t\tauto-merge\n\t\telements = { 10.0.0.0/24 }\n\t}\n")
+	require.True(t, strings.HasPrefix(rendered, "add table inet azure-npm\ndelete table inet azure-npm\ntable inet azure-npm {\n"))
+
+	r := NewRuleset(nil, util.PlaceAzureChainAfterKubeServices)
+	rendered = r.Render()
+	requireGolden(t, "after-kube-services", rendered)
+	require.Contains(t, rendered, "type filter hook forward priority 1; policy accept;")
+}
+
+func TestRenderPodVerdictMaps(t *testing.T) {
+	r := testRuleset()
+	r.policies["x/a"] = &Policy{
+		Key: "x/a",
+		Ingress: &PolicyChain{
+			Name:      "AZURE-NPM-INGRESS-111",
+			Comment:   "INGRESS-POLICY-x/a",
+			Selectors: []Selector{{Set: "azure-npm-1", Included: true}, {Set: "azure-npm-2", Included: false}},
+			Rules:     []string{"jump AZURE-NPM-INGRESS-ALLOW-MARK"},
+		},
+	}
+	r.policies["x/b"] = &Policy{
+		Key: "x/b",
+		Ingress: &PolicyChain{
+			Name:      "AZURE-NPM-INGRESS-222",
+			Comment:   "INGRESS-POLICY-x/b",
+			Selectors: []Selector{{Set: "azure-npm-1", Included: true}},
+		},
+		Egress: &PolicyChain{
+			Name:      "AZURE-NPM-EGRESS-222",
+			Comment:   "EGRESS-POLICY-x/b",
+			Selectors: []Selector{{Set: "azure-npm-missing", Included: true}},
+		},
+	}
+	rendered := r.Render()
+	requireGolden(t, "pod-verdict-maps", rendered)
+
+	// 10.0.0.2 is excluded from x/a
+	podChain := func(ip string) string { return fmt.Sprintf("AZURE-NPM-INGRESS-POD-%s", util.Hash(ip)) }
+	require.Contains(t, rendered, fmt.Sprintf("\tchain %s {\n\t\tjump AZURE-NPM-INGRESS-111 comment \"INGRESS-POLICY-x/a\"\n\t\tjump AZURE-NPM-INGRESS-222 comment \"INGRESS-POLICY-x/b\"\n\t}\n", podChain("10.0.0.1")))
+	require.Contains(t, rendered, fmt.Sprintf("\tchain %s {\n\t\tjump AZURE-NPM-INGRESS-222 comment \"INGRESS-POLICY-x/b\"\n\t}\n", podChain("10.0.0.2")))
+	require.Contains(t, rendered, fmt.Sprintf("elements = { 10.0.0.1 : jump %s, 10.0.0.2 : jump %s, 10.0.0.3 : jump %s }", podChain("10.0.0.1"), podChain("10.0.0.2"), podChain("10.0.0.3")))
+	// a missing set selects no pod
+	require.Contains(t, rendered, "\tmap AZURE-NPM-EGRESS-PODS {\n\t\ttype ipv4_addr : verdict\n\t}\n")
+	// NPM is activated
+	require.Contains(t, rendered, "\tchain AZURE-NPM {\n\t\tjump AZURE-NPM-INGRESS\n\t\tjump AZURE-NPM-EGRESS\n\t\tjump AZURE-NPM-ACCEPT\n\t}\n")
+}
+
+func TestRenderPoliciesWithoutPodLookup(t *testing.T) {
+	r := testRuleset()
+	r.policies["x/a"] = &Policy{
+		Key: "x/a",
+		Ingress: &PolicyChain{
+			Name:      "AZURE-NPM-INGRESS-111",
+			Comment:   "INGRESS-POLICY-x/a",
+			Selectors: []Selector{{Set: "azure-npm-3", Included: true}},
+		},
+		Egress: &PolicyChain{
+			Name:    "AZURE-NPM-EGRESS-111",
+			Comment: "EGRESS-POLICY-x/a",
+		},
+	}
+	rendered := r.Render()
+	requireGolden(t, "without-pod-lookup", rendered)
+
+	// the pods of interval sets or of policies without selectors aren't looked up
+	require.Contains(t, rendered, "\tchain AZURE-NPM-INGRESS {\n\t\tip daddr @azure-npm-3 jump AZURE-NPM-INGRESS-111 comment \"INGRESS-POLICY-x/a\"\n\t\tip daddr vmap @AZURE-NPM-INGRESS-PODS\n")
+	require.Contains(t, rendered, "\tchain AZURE-NPM-EGRESS {\n\t\tjump AZURE-NPM-EGRESS-111 comment \"EGRESS-POLICY-x/a\"\n\t\tip saddr vmap @AZURE-NPM-EGRESS-PODS\n")
+}
+
+func TestForwardChainPriority(t *testing.T) {
+	// the iptables FORWARD chain has the filter priority 0. As the forward chain is a base chain of its own, the
+	// packets an earlier base chain accepts still go through the later one, only new connections go through NPM.
+	first, err := strconv.Atoi(placeFirstPriority)
+	require.NoError(t, err)
+	require.Negative(t, first)
+	after, err := strconv.Atoi(placeAfterPriority)
+	require.NoError(t, err)
+	require.Positive(t, after)
+
+	rendered := NewRuleset(nil, util.PlaceAzureChainAfterKubeServices).Render()
+	require.Contains(t, rendered, "\tchain forward {\n\t\ttype filter hook forward priority 1; policy accept;\n\t\tct state new jump AZURE-NPM\n\t}\n")
+}
+
+func TestQuote(t *testing.T) {
+	require.Equal(t, `"ALLOW-ALL"`, quote(`ALLOW-"ALL"`))
+	require.Len(t, quote(strings.Repeat("a", 200)), maxCommentLength+2)
+}
@@ -0,0 +1,233 @@
+package nftables
+
+import (
+	"fmt"
+	"strings"
+)
+
+type objectKind string
+
+const (
+	setKind   objectKind = "set"
+	mapKind   objectKind = "map"
+	chainKind objectKind = "chain"
+
+	mapKeySeparator = " : "
+)
+
+// object is a set, verdict map or chain of the NPM table.
+type object struct {
+	kind objectKind
+	name string
+	// spec holds the statements declaring the object, e.g. its type and flags.
+	spec []string
+	// elements of a set or map, sorted.
+	elements []string
+	// rules of a chain.
+	rules []string
+}
+
+// table is the rendered NPM table, with its objects in the order they are declared.
+type table struct {
+	objects []*object
+}
+
+func (t *table) addChain(name string, rules ...string) {
+	t.objects = append(t.objects, &object{kind: chainKind, name: name, rules: rules})
+}
+
+// replaceScript returns the nft script replacing the NPM table with t.
+func (t *table) replaceScript() string {
+	w := &writer{}
+	// deleting a table that doesn't exist fails, so add it first
+	w.line(0, "add table %s %s", Family, TableName)
+	w.line(0, "delete table %s %s", Family, TableName)
+	w.line(0, "table %s %s {", Family, TableName)
+	for _, o := range t.objects {
+		w.line(1, "%s %s {", o.kind, o.name)
+		for _, statement := range o.spec {
+			w.line(2, "%s", statement)
+		}
+		for _, rule := range o.rules {
+			w.line(2, "%s", rule)
+		}
+		writeElements(w, o.elements)
+		w.line(1, "}")
+	}
+	w.line(0, "}")
+	return w.String()
+}
+
+/*
+updateScript returns the nft script changing the NPM table from applied to t. It returns false if the table has to
+be replaced instead, when the declaration of an object changed.
+
+The commands are ordered so that every object exists before it is referenced and is no longer referenced when it is
+deleted:
+ 1. the new sets and maps are added and the elements of the sets are updated
+ 2. the new chains are added
+ 3. the rules of the new and changed chains are replaced, and the chains to delete are flushed
+ 4. the elements of the maps are updated
+ 5. the chains, then the sets and maps to delete are deleted
+
+Interval sets are flushed and refilled when they change, since auto-merge may have merged the elements to delete.
+*/
+func (t *table) updateScript(applied *table) (string, bool) {
+	previous := applied.byName()
+	current := t.byName()
+
+	var sets, chains, rules, maps, deletions []string
+	for _, o := range t.objects {
+		prev, ok := previous[o.name]
+		if ok && (prev.kind != o.kind || !equalStrings(prev.spec, o.spec)) {
+			return "", false
+		}
+
+		switch o.kind {
+		case setKind:
+			if !ok {
+				sets = append(sets, command("add", o.kind, o.name, o.declaration()))
+				prev = &object{}
+			}
+			sets = append(sets, o.updateElements(prev)...)
+		case mapKind:
+			if !ok {
+				sets = append(sets, command("add", o.kind, o.name, o.declaration()))
+				prev = &object{}
+			}
+			maps = append(maps, o.updateElements(prev)...)
+		case chainKind:
+			switch {
+			case !ok:
+				chains = append(chains, command("add", o.kind, o.name, o.declaration()))
+			case equalStrings(prev.rules, o.rules):
+				continue
+			default:
+				rules = append(rules, command("flush", o.kind, o.name, ""))
+			}
+			for _, rule := range o.rules {
+				rules = append(rules, command("add", "rule", o.name, rule))
+			}
+		}
+	}
+
+	var setDeletions []string
+	for _, o := range applied.objects {
+		if _, ok := current[o.name]; ok {
+			continue
+		}
+		if o.kind == chainKind {
+			rules = append(rules, command("flush", chainKind, o.name, ""))
+			deletions = append(deletions, command("delete", chainKind, o.name, ""))
+		} else {
+			setDeletions = append(setDeletions, command("delete", o.kind, o.name, ""))
+		}
+	}
+
+	var script strings.Builder
+	for _, section := range [][]string{sets, chains, rules, maps, deletions, setDeletions} {
+		for _, line := range section {
+			script.WriteString(line)
+			script.WriteString("\n")
+		}
+	}
+	return script.String(), true
+}
+
+// updateElements returns the commands changing the elements of the set or map from those of prev.
+func (o *object) updateElements(prev *object) []string {
+	if equalStrings(prev.elements, o.elements) {
+		return nil
+	}
+	if o.kind == setKind && o.isInterval() && len(prev.elements) > 0 {
+		return append([]string{command("flush", setKind, o.name, "")}, elementsCommand("add", o.name, o.elements)...)
+	}
+
+	// elements are deleted by key, so a map element whose verdict changed is deleted, then added again
+	kept := make(map[string]bool, len(o.elements))
+	for _, e := range o.elements {
+		kept[e] = true
+	}
+	var toDelete []string
+	for _, e := range prev.elements {
+		if !kept[e] {
+			toDelete = append(toDelete, o.key(e))
+		}
+	}
+	existing := make(map[string]bool, len(prev.elements))
+	for _, e := range prev.elements {
+		existing[e] = true
+	}
+	var toAdd []string
+	for _, e := range o.elements {
+		if !existing[e] {
+			toAdd = append(toAdd, e)
+		}
+	}
+	return append(elementsCommand("delete", o.name, toDelete), elementsCommand("add", o.name, toAdd)...)
+}
+
+// declaration returns the statements declaring the object in an nft command, empty for a regular chain.
+func (o *object) declaration() string {
+	if len(o.spec) == 0 {
+		return ""
+	}
+	statements := make([]string, 0, len(o.spec))
+	for _, statement := range o.spec {
+		statements = append(statements, strings.TrimSuffix(statement, ";"))
+	}
+	return "{ " + strings.Join(statements, "; ") + "; }"
+}
+
+func (o *object) isInterval() bool {
+	for _, statement := range o.spec {
+		if statement == "flags interval" {
+			return true
+		}
+	}
+	return false
+}
+
+// key returns the key of an element, which is the element itself for a set.
+func (o *object) key(element string) string {
+	if o.kind == mapKind {
+		return strings.SplitN(element, mapKeySeparator, 2)[0]
+	}
+	return element
+}
+
+func (t *table) byName() map[string]*object {
+	objects := make(map[string]*object, len(t.objects))
+	for _, o := range t.objects {
+		objects[o.name] = o
+	}
+	return objects
+}
+
+// command returns an nft command on an object of the NPM table, e.g. "add chain inet azure-npm AZURE-NPM".
+func command(verb string, kind objectKind, name, args string) string {
+	c := fmt.Sprintf("%s %s %s %s %s", verb, kind, Family, TableName, name)
+	if args != "" {
+		c += " " + args
+	}
+	return c
+}
+
+func elementsCommand(verb, name string, elements []string) []string {
+	if len(elements) == 0 {
+		return nil
+	}
+	return []string{command(verb, "element", name, "{ "+strings.Join(elements, ", ")+" }")}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
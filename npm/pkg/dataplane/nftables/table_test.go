@@ -0,0 +1,86 @@
+package nftables
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-container-networking/npm/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateScript(t *testing.T) {
+	r := testRuleset()
+	applied := r.table()
+
+	script, ok := r.table().updateScript(applied)
+	require.True(t, ok)
+	require.Empty(t, script)
+
+	r.sets["azure-npm-1"] = &Set{Name: "azure-npm-1", Type: IPSetType, Elements: []string{"10.0.0.3", "10.0.0.4"}}
+	r.sets["azure-npm-3"] = &Set{Name: "azure-npm-3", Type: IPSetType, Interval: true, Elements: []string{"10.0.1.0/24"}}
+	delete(r.sets, "azure-npm-2")
+	r.sets["azure-npm-4"] = &Set{Name: "azure-npm-4", Type: IPSetType, Interval: true, Elements: []string{"10.1.0.0/16"}}
+	r.policies["x/a"] = &Policy{
+		Key: "x/a",
+		Ingress: &PolicyChain{
+			Name:      "AZURE-NPM-INGRESS-111",
+			Comment:   "INGRESS-POLICY-x/a",
+			Selectors: []Selector{{Set: "azure-npm-1", Included: true}},
+		},
+	}
+	script, ok = r.table().updateScript(applied)
+	require.True(t, ok)
+
+	podChain := func(ip string) string { return fmt.Sprintf("AZURE-NPM-INGRESS-POD-%s", util.Hash(ip)) }
+	require.Equal(t, []string{
+		// the elements of hash sets are updated, interval sets are refilled
+		"delete element inet azure-npm azure-npm-1 { 10.0.0.1, 10.0.0.2 }",
+		"add element inet azure-npm azure-npm-1 { 10.0.0.4 }",
+		"flush set inet azure-npm azure-npm-3",
+		"add element inet azure-npm azure-npm-3 { 10.0.1.0/24 }",
+		"add set inet azure-npm azure-npm-4 { type ipv4_addr; flags interval; auto-merge; }",
+		"add element inet azure-npm azure-npm-4 { 10.1.0.0/16 }",
+		"add chain inet azure-npm AZURE-NPM-INGRESS-111",
+		"add chain inet azure-npm " + podChain("10.0.0.3"),
+		"add chain inet azure-npm " + podChain("10.0.0.4"),
+		"add rule inet azure-npm " + podChain("10.0.0.3") + " jump AZURE-NPM-INGRESS-111 comment \"INGRESS-POLICY-x/a\"",
+		"add rule inet azure-npm " + podChain("10.0.0.4") + " jump AZURE-NPM-INGRESS-111 comment \"INGRESS-POLICY-x/a\"",
+		"flush chain inet azure-npm AZURE-NPM",
+		"add rule inet azure-npm AZURE-NPM jump AZURE-NPM-INGRESS",
+		"add rule inet azure-npm AZURE-NPM jump AZURE-NPM-EGRESS",
+		"add rule inet azure-npm AZURE-NPM jump AZURE-NPM-ACCEPT",
+		fmt.Sprintf("add element inet azure-npm AZURE-NPM-INGRESS-PODS { 10.0.0.3 : jump %s, 10.0.0.4 : jump %s }", podChain("10.0.0.3"), podChain("10.0.0.4")),
+		"delete set inet azure-npm azure-npm-2",
+	}, strings.Split(strings.TrimSuffix(script, "\n"), "\n"))
+
+	// removing the policy removes its chains once nothing jumps to them
+	applied = r.table()
+	delete(r.policies, "x/a")
+	script, ok = r.table().updateScript(applied)
+	require.True(t, ok)
+	require.Equal(t, []string{
+		"flush chain inet azure-npm AZURE-NPM",
+		"flush chain inet azure-npm AZURE-NPM-INGRESS-111",
+		"flush chain inet azure-npm " + podChain("10.0.0.3"),
+		"flush chain inet azure-npm " + podChain("10.0.0.4"),
+		"delete element inet azure-npm AZURE-NPM-INGRESS-PODS { 10.0.0.3, 10.0.0.4 }",
+		"delete chain inet azure-npm AZURE-NPM-INGRESS-111",
+		"delete chain inet azure-npm " + podChain("10.0.0.3"),
+		"delete chain inet azure-npm " + podChain("10.0.0.4"),
+	}, strings.Split(strings.TrimSuffix(script, "\n"), "\n"))
+}
+
+func TestUpdateScriptReplacesChangedDeclarations(t *testing.T) {
+	r := testRuleset()
+	applied := r.table()
+
+	r.sets["azure-npm-1"] = &Set{Name: "azure-npm-1", Type: IPSetType, Interval: true}
+	_, ok := r.table().updateScript(applied)
+	require.False(t, ok)
+
+	r = testRuleset()
+	r.placeFirst = false
+	_, ok = r.table().updateScript(applied)
+	require.False(t, ok)
+}
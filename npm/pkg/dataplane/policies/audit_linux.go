@@ -0,0 +1,101 @@
+package policies
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Azure/azure-container-networking/npm/metrics"
+	"github.com/Azure/azure-container-networking/npm/util"
+	npmerrors "github.com/Azure/azure-container-networking/npm/util/errors"
+	"k8s.io/klog"
+)
+
+// the comments of the rules of audit-only policies which count the traffic the policy would have dropped start with this prefix
+const auditCommentPrefix = "AUDIT-"
+
+var (
+	listChainWithCountersArgs = []string{
+		util.IptablesWaitFlag, util.IptablesDefaultWaitTime, util.IptablesTableFlag, util.IptablesFilterTable,
+		util.IptablesNumericFlag, util.IptablesVerboseFlag, util.IptablesExactFlag, util.IptablesListFlag,
+	}
+	auditCommentMatch = fmt.Sprintf("/* %s", auditCommentPrefix)
+)
+
+// reportAuditCounts reports the number of packets that each audit-only policy would have denied since it was last applied.
+// The caller must hold the reconcileManager lock.
+func (pMgr *PolicyManager) reportAuditCounts() {
+	pMgr.policyMap.RLock()
+	auditPolicies := make([]*NPMNetworkPolicy, 0)
+	for _, policy := range pMgr.policyMap.cache {
+		if policy.AuditOnly {
+			auditPolicies = append(auditPolicies, policy)
+		}
+	}
+	pMgr.policyMap.RUnlock()
+
+	for _, policy := range auditPolicies {
+		count, err := pMgr.auditCount(policy)
+		if err != nil {
+			msg := fmt.Sprintf("failed to count denied packets of audit-only NetPol %s: %s", policy.PolicyKey, err.Error())
+			metrics.SendErrorLogAndMetric(util.IptmID, "error: %s", msg)
+			continue
+		}
+
+		if count > pMgr.auditCounts[policy.PolicyKey] {
+			klog.Infof("audit-only NetPol %s would have denied %d packets since it was last applied", policy.PolicyKey, count)
+		}
+		pMgr.auditCounts[policy.PolicyKey] = count
+		metrics.SetAuditDeniedPackets(policy.PolicyKey, count)
+	}
+}
+
+// forgetAuditCounts stops reporting the policy. The caller must hold the reconcileManager lock.
+func (pMgr *PolicyManager) forgetAuditCounts(policy *NPMNetworkPolicy) {
+	if !policy.AuditOnly {
+		return
+	}
+	delete(pMgr.auditCounts, policy.PolicyKey)
+	metrics.DeleteAuditDeniedPackets(policy.PolicyKey)
+}
+
+// auditCount sums the packet counters of the AUDIT rules in the policy chain(s).
+func (pMgr *PolicyManager) auditCount(policy *NPMNetworkPolicy) (int, error) {
+	count := 0
+	for _, chain := range chainNames([]*NPMNetworkPolicy{policy}) {
+		args := make([]string, 0, len(listChainWithCountersArgs)+1)
+		args = append(args, listChainWithCountersArgs...)
+		args = append(args, chain)
+		output, err := pMgr.ioShim.Exec.Command(util.Iptables, args...).CombinedOutput()
+		if err != nil {
+			return 0, npmerrors.SimpleErrorWrapper(fmt.Sprintf("failed to list chain %s. output: [%s]", chain, strings.TrimSuffix(string(output), "\n")), err)
+		}
+		chainCount, err := auditCountFromListOutput(string(output))
+		if err != nil {
+			return 0, err
+		}
+		count += chainCount
+	}
+	return count, nil
+}
+
+// auditCountFromListOutput sums the packet counters of the AUDIT rules in the output of "iptables -nvxL <chain>", e.g.
+//
+//	Chain AZURE-NPM-INGRESS-123 (1 references)
+//	    pkts      bytes target     prot opt in     out     source               destination
+//	       3      180 RETURN     all  --  *      *       0.0.0.0/0            0.0.0.0/0            /* AUDIT-DROP-ALL */
+func auditCountFromListOutput(output string) (int, error) {
+	count := 0
+	for _, line := range strings.Split(output, "\n") {
+		if !strings.Contains(line, auditCommentMatch) {
+			continue
+		}
+		fields := strings.Fields(line)
+		packets, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return 0, npmerrors.SimpleErrorWrapper(fmt.Sprintf("unexpected packet counter in line [%s]", line), err)
+		}
+		count += packets
+	}
+	return count, nil
+}
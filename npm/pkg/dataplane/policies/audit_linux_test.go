@@ -0,0 +1,150 @@
+package policies
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-container-networking/common"
+	"github.com/Azure/azure-container-networking/npm/metrics"
+	"github.com/Azure/azure-container-networking/npm/pkg/dataplane/ipsets"
+	dptestutils "github.com/Azure/azure-container-networking/npm/pkg/dataplane/testutils"
+	testutils "github.com/Azure/azure-container-networking/test/utils"
+	"github.com/stretchr/testify/require"
+)
+
+var auditNetPol = &NPMNetworkPolicy{
+	Namespace:   "x",
+	PolicyKey:   "x/audit",
+	ACLPolicyID: "azure-acl-x-audit",
+	PodSelectorIPSets: []*ipsets.TranslatedIPSet{
+		{Metadata: ipsets.TestKeyPodSet.Metadata},
+	},
+	PodSelectorList: []SetInfo{
+		{
+			IPSet:     ipsets.TestKeyPodSet.Metadata,
+			Included:  true,
+			MatchType: EitherMatch,
+		},
+	},
+	ACLs:      []*ACLPolicy{ingressDeniedACL, ingressAllowedACL},
+	AuditOnly: true,
+}
+
+func TestCreatorForAuditOnlyPolicies(t *testing.T) {
+	ioshim := common.NewMockIOShim(nil)
+	defer ioshim.VerifyCalls(t, nil)
+	pMgr := NewPolicyManager(ioshim, ipsetConfig)
+
+	policies := []*NPMNetworkPolicy{auditNetPol}
+	creator := pMgr.creatorForNewNetworkPolicies(chainNames(policies), policies)
+	actualLines := strings.Split(creator.ToString(), "\n")
+	chain := auditNetPol.ingressChainName()
+	expectedLines := []string{
+		"*filter",
+		fmt.Sprintf(":%s - -", chain),
+		"-F AZURE-NPM",
+		"-A AZURE-NPM -j AZURE-NPM-INGRESS",
+		"-A AZURE-NPM -j AZURE-NPM-EGRESS",
+		"-A AZURE-NPM -j AZURE-NPM-ACCEPT",
+		// the policy never marks or accepts traffic
+		fmt.Sprintf(
+			"-A %s -j RETURN -p TCP --dport 222:333 -m set --match-set %s src -m set ! --match-set %s dst -m comment --comment AUDIT-%s",
+			chain, ipsets.TestCIDRSet.HashedName, ipsets.TestKeyPodSet.HashedName, ingressDropComment,
+		),
+		fmt.Sprintf("-A %s -j RETURN -m set --match-set %s src -m comment --comment %s", chain, ipsets.TestCIDRSet.HashedName, ingressAllowComment),
+		fmt.Sprintf(
+			"-I AZURE-NPM-INGRESS 1 -j %s -m set --match-set %s dst -m comment --comment INGRESS-POLICY-x/audit-TO-podlabel-test-keyPod-set-IN-ns-x",
+			chain, ipsets.TestKeyPodSet.HashedName,
+		),
+		"COMMIT",
+		"",
+	}
+	dptestutils.AssertEqualLines(t, expectedLines, actualLines)
+}
+
+func TestJumpOrderForAuditOnlyPolicies(t *testing.T) {
+	ioshim := common.NewMockIOShim(nil)
+	defer ioshim.VerifyCalls(t, nil)
+	pMgr := NewPolicyManager(ioshim, ipsetConfig)
+	pMgr.policyMap.cache[auditNetPol.PolicyKey] = auditNetPol
+
+	// a newer enforcing policy still comes before the audit-only policy
+	order := pMgr.newJumpOrder(forIngress)
+	require.Equal(t, 1, order.lineNumber(ingressNetPol))
+
+	// a newer audit-only policy comes after the enforcing policies
+	pMgr.policyMap.cache[ingressNetPol.PolicyKey] = ingressNetPol
+	newerAuditNetPol := *auditNetPol
+	newerAuditNetPol.PolicyKey = "x/newer-audit"
+	order = pMgr.newJumpOrder(forIngress)
+	require.Equal(t, 2, order.lineNumber(&newerAuditNetPol))
+
+	// policies added together keep their order within their group
+	newerIngressNetPol := *ingressNetPol
+	newerIngressNetPol.PolicyKey = "x/newer-ingress"
+	require.Equal(t, 1, order.lineNumber(&newerIngressNetPol))
+	newestAuditNetPol := *auditNetPol
+	newestAuditNetPol.PolicyKey = "x/newest-audit"
+	require.Equal(t, 4, order.lineNumber(&newestAuditNetPol))
+}
+
+func TestReportAuditCounts(t *testing.T) {
+	metrics.ReinitializeAll()
+	chain := auditNetPol.ingressChainName()
+	listCommand := []string{"iptables", "-w", "60", "-t", "filter", "-n", "-v", "-x", "-L", chain}
+	listOutput := func(dropped, allowed int) string {
+		return fmt.Sprintf(`Chain %s (1 references)
+    pkts      bytes target     prot opt in     out     source               destination
+%8d      180 RETURN     tcp  --  *      *       0.0.0.0/0            0.0.0.0/0            /* AUDIT-%s */
+%8d      120 RETURN     all  --  *      *       0.0.0.0/0            0.0.0.0/0            /* %s */
+`, chain, dropped, ingressDropComment, allowed, ingressAllowComment)
+	}
+	calls := []testutils.TestCmd{
+		{Cmd: listCommand, Stdout: listOutput(3, 7)},
+		{Cmd: listCommand, Stdout: listOutput(5, 7)},
+		{Cmd: listCommand, ExitCode: 1},
+	}
+	ioshim := common.NewMockIOShim(calls)
+	defer ioshim.VerifyCalls(t, calls)
+	pMgr := NewPolicyManager(ioshim, ipsetConfig)
+	pMgr.policyMap.cache[auditNetPol.PolicyKey] = auditNetPol
+	pMgr.policyMap.cache[ingressNetPol.PolicyKey] = ingressNetPol
+
+	pMgr.reportAuditCounts()
+	count, err := metrics.GetAuditDeniedPackets(auditNetPol.PolicyKey)
+	require.NoError(t, err)
+	require.Equal(t, 3, count)
+
+	pMgr.reportAuditCounts()
+	count, err = metrics.GetAuditDeniedPackets(auditNetPol.PolicyKey)
+	require.NoError(t, err)
+	require.Equal(t, 5, count)
+
+	// a failure keeps the last count
+	pMgr.reportAuditCounts()
+	count, err = metrics.GetAuditDeniedPackets(auditNetPol.PolicyKey)
+	require.NoError(t, err)
+	require.Equal(t, 5, count)
+
+	pMgr.forgetAuditCounts(auditNetPol)
+	require.NotContains(t, pMgr.auditCounts, auditNetPol.PolicyKey)
+	count, err = metrics.GetAuditDeniedPackets(auditNetPol.PolicyKey)
+	require.NoError(t, err)
+	require.Equal(t, 0, count)
+}
+
+func TestAuditCountFromListOutput(t *testing.T) {
+	output := `Chain AZURE-NPM-INGRESS-123 (1 references)
+    pkts      bytes target     prot opt in     out     source               destination
+       3      180 RETURN     all  --  *      *       0.0.0.0/0            0.0.0.0/0            /* AUDIT-DROP-ALL */
+      10      600 RETURN     all  --  *      *       0.0.0.0/0            0.0.0.0/0            /* ALLOW-ALL */
+       4      240 RETURN     tcp  --  *      *       0.0.0.0/0            0.0.0.0/0            /* AUDIT-DROP-ON-TCP */
+`
+	count, err := auditCountFromListOutput(output)
+	require.NoError(t, err)
+	require.Equal(t, 7, count)
+
+	_, err = auditCountFromListOutput("  abc 180 RETURN all -- * * 0.0.0.0/0 0.0.0.0/0 /* AUDIT-DROP-ALL */")
+	require.Error(t, err)
+}
@@ -153,6 +153,10 @@ func isBaseChain(chain string) bool {
 		for _, chain := range iptablesAzureChains {
 			iptablesAzureChainsMap[chain] = struct{}{}
 		}
+		// never clean up the flow log chains since the base chains may jump to them
+		for _, chain := range flowLogChains {
+			iptablesAzureChainsMap[chain] = struct{}{}
+		}
 	}
 	_, exist := iptablesAzureChainsMap[chain]
 	return exist
@@ -179,6 +183,10 @@ TODO: could use one grep call instead of separate calls for getting jump line nu
   - would use a grep pattern like so: <line num...AZURE-NPM>|<Chain AZURE-NPM>
 */
 func (pMgr *PolicyManager) bootup(_ []string) error {
+	if pMgr.nftRuleset != nil {
+		return pMgr.bootupNFTables()
+	}
+
 	klog.Infof("booting up iptables Azure chains")
 
 	// Stop reconciling so we don't contend for iptables, and so we don't update the staleChains at the same time as reconcile()
@@ -192,67 +200,8 @@ func (pMgr *PolicyManager) bootup(_ []string) error {
 		util.IptablesSave = util.IptablesSaveLegacy
 		util.IptablesRestore = util.IptablesRestoreLegacy
 
-		// 0. delete the deprecated jump to deprecated AZURE-NPM in legacy iptables
-		deprecatedErrCode, deprecatedErr := pMgr.ignoreErrorsAndRunIPTablesCommand(removeDeprecatedJumpIgnoredErrors, util.IptablesDeletionFlag, deprecatedJumpFromForwardToAzureChainArgs...)
-		if deprecatedErrCode == 0 {
-			klog.Infof("deleted deprecated jump rule from FORWARD chain to AZURE-NPM chain")
-		} else if deprecatedErr != nil {
-			metrics.SendErrorLogAndMetric(util.IptmID,
-				"failed to delete deprecated jump rule from FORWARD chain to AZURE-NPM chain for unexpected reason with exit code %d and error: %s",
-				deprecatedErrCode, deprecatedErr.Error())
-		}
-
-		// 0. delete the deprecated jump to current AZURE-NPM in legacy iptables
-		deprecatedErrCode, deprecatedErr = pMgr.ignoreErrorsAndRunIPTablesCommand(removeDeprecatedJumpIgnoredErrors, util.IptablesDeletionFlag, jumpFromForwardToAzureChainArgs...)
-		if deprecatedErrCode == 0 {
-			klog.Infof("deleted deprecated jump rule from FORWARD chain to AZURE-NPM chain")
-		} else if deprecatedErr != nil {
-			metrics.SendErrorLogAndMetric(util.IptmID,
-				"failed to delete deprecated jump rule from FORWARD chain to AZURE-NPM chain for unexpected reason with exit code %d and error: %s",
-				deprecatedErrCode, deprecatedErr.Error())
-		}
-
-		// clean up current chains in legacy iptables
-		currentChains, err := ioutil.AllCurrentAzureChains(pMgr.ioShim.Exec, util.IptablesDefaultWaitTime)
-		if err != nil {
-			return npmerrors.SimpleErrorWrapper("failed to get current chains for bootup", err)
-		}
-
-		// We have only one chance to clean existing legacy iptables chains.
-		// So flush all the chains and then destroy them
-		var aggregateError error
-		for chain := range currentChains {
-			errCode, err := pMgr.runIPTablesCommand(util.IptablesFlushFlag, chain)
-			if err != nil && errCode != doesNotExistErrorCode {
-				// add to staleChains if it's not one of the iptablesAzureChains
-				pMgr.staleChains.add(chain)
-				currentErrString := fmt.Sprintf("failed to flush chain %s with err [%v]", chain, err)
-				if aggregateError == nil {
-					aggregateError = npmerrors.SimpleError(currentErrString)
-				} else {
-					aggregateError = npmerrors.SimpleErrorWrapper(fmt.Sprintf("%s and had previous error", currentErrString), aggregateError)
-				}
-			}
-		}
-
-		for chain := range currentChains {
-			errCode, err := pMgr.runIPTablesCommand(util.IptablesDestroyFlag, chain)
-			if err != nil && errCode != doesNotExistErrorCode {
-				// add to staleChains if it's not one of the iptablesAzureChains
-				pMgr.staleChains.add(chain)
-				currentErrString := fmt.Sprintf("failed to delete chain %s with err [%v]", chain, err)
-				if aggregateError == nil {
-					aggregateError = npmerrors.SimpleError(currentErrString)
-				} else {
-					aggregateError = npmerrors.SimpleErrorWrapper(fmt.Sprintf("%s and had previous error", currentErrString), aggregateError)
-				}
-			}
-		}
-
-		if aggregateError != nil {
-			metrics.SendErrorLogAndMetric(util.IptmID,
-				"failed to flush and delete stale chain in legacy iptables with error: %s",
-				aggregateError.Error())
+		if err := pMgr.removeAzureChains(); err != nil {
+			return err
 		}
 
 		util.Iptables = util.IptablesNft
@@ -294,10 +243,84 @@ func (pMgr *PolicyManager) bootup(_ []string) error {
 	return nil
 }
 
+// removeAzureChains deletes the jumps to AZURE-NPM and flushes and deletes the Azure chains of util.Iptables, for an
+// iptables NPM no longer uses.
+func (pMgr *PolicyManager) removeAzureChains() error {
+	// delete the deprecated jump to deprecated AZURE-NPM
+	deprecatedErrCode, deprecatedErr := pMgr.ignoreErrorsAndRunIPTablesCommand(removeDeprecatedJumpIgnoredErrors, util.IptablesDeletionFlag, deprecatedJumpFromForwardToAzureChainArgs...)
+	if deprecatedErrCode == 0 {
+		klog.Infof("deleted deprecated jump rule from FORWARD chain to AZURE-NPM chain")
+	} else if deprecatedErr != nil {
+		metrics.SendErrorLogAndMetric(util.IptmID,
+			"failed to delete deprecated jump rule from FORWARD chain to AZURE-NPM chain for unexpected reason with exit code %d and error: %s",
+			deprecatedErrCode, deprecatedErr.Error())
+	}
+
+	// delete the deprecated jump to current AZURE-NPM
+	deprecatedErrCode, deprecatedErr = pMgr.ignoreErrorsAndRunIPTablesCommand(removeDeprecatedJumpIgnoredErrors, util.IptablesDeletionFlag, jumpFromForwardToAzureChainArgs...)
+	if deprecatedErrCode == 0 {
+		klog.Infof("deleted deprecated jump rule from FORWARD chain to AZURE-NPM chain")
+	} else if deprecatedErr != nil {
+		metrics.SendErrorLogAndMetric(util.IptmID,
+			"failed to delete deprecated jump rule from FORWARD chain to AZURE-NPM chain for unexpected reason with exit code %d and error: %s",
+			deprecatedErrCode, deprecatedErr.Error())
+	}
+
+	// clean up current chains
+	currentChains, err := ioutil.AllCurrentAzureChains(pMgr.ioShim.Exec, util.IptablesDefaultWaitTime)
+	if err != nil {
+		return npmerrors.SimpleErrorWrapper("failed to get current chains for bootup", err)
+	}
+
+	// We have only one chance to clean these chains.
+	// So flush all the chains and then destroy them
+	var aggregateError error
+	for chain := range currentChains {
+		errCode, err := pMgr.runIPTablesCommand(util.IptablesFlushFlag, chain)
+		if err != nil && errCode != doesNotExistErrorCode {
+			// add to staleChains if it's not one of the iptablesAzureChains
+			pMgr.staleChains.add(chain)
+			currentErrString := fmt.Sprintf("failed to flush chain %s with err [%v]", chain, err)
+			if aggregateError == nil {
+				aggregateError = npmerrors.SimpleError(currentErrString)
+			} else {
+				aggregateError = npmerrors.SimpleErrorWrapper(fmt.Sprintf("%s and had previous error", currentErrString), aggregateError)
+			}
+		}
+	}
+
+	for chain := range currentChains {
+		errCode, err := pMgr.runIPTablesCommand(util.IptablesDestroyFlag, chain)
+		if err != nil && errCode != doesNotExistErrorCode {
+			// add to staleChains if it's not one of the iptablesAzureChains
+			pMgr.staleChains.add(chain)
+			currentErrString := fmt.Sprintf("failed to delete chain %s with err [%v]", chain, err)
+			if aggregateError == nil {
+				aggregateError = npmerrors.SimpleError(currentErrString)
+			} else {
+				aggregateError = npmerrors.SimpleErrorWrapper(fmt.Sprintf("%s and had previous error", currentErrString), aggregateError)
+			}
+		}
+	}
+
+	if aggregateError != nil {
+		metrics.SendErrorLogAndMetric(util.IptmID,
+			"failed to flush and delete stale chain in %s with error: %s", util.Iptables,
+			aggregateError.Error())
+	}
+	return nil
+}
+
 // reconcile does the following:
 // - creates the jump rule from FORWARD chain to AZURE-NPM chain (if it does not exist) and makes sure it's after the jumps to KUBE-FORWARD & KUBE-SERVICES chains (if they exist).
+// - reports the packets that audit-only policies would have denied.
 // - cleans up stale policy chains. It can be forced to stop this process if reconcileManager.forceLock() is called.
 func (pMgr *PolicyManager) reconcile() {
+	if pMgr.nftRuleset != nil {
+		// the nftables table is replaced as a whole, so there are no stale chains or jumps to reposition
+		return
+	}
+
 	if err := pMgr.positionAzureChainJumpRule(); err != nil {
 		msg := fmt.Sprintf("failed to reconcile jump rule to Azure-NPM due to %s", err.Error())
 		metrics.SendErrorLogAndMetric(util.IptmID, "error: %s", msg)
@@ -306,6 +329,8 @@ func (pMgr *PolicyManager) reconcile() {
 
 	pMgr.reconcileManager.Lock()
 	defer pMgr.reconcileManager.Unlock()
+	pMgr.reportAuditCounts()
+
 	staleChains := pMgr.staleChains.emptyAndGetAll()
 
 	if len(staleChains) == 0 {
@@ -390,8 +415,12 @@ func (pMgr *PolicyManager) ignoreErrorsAndRunIPTablesCommand(ignored []*exitErro
 // Writes the restore file for bootup, and marks the following as stale: deprecated chains and old v2 policy chains.
 // This is a separate function to help with UTs.
 func (pMgr *PolicyManager) creatorForBootup(currentChains map[string]struct{}) *ioutil.FileCreator {
-	chainsToCreate := make([]string, 0, len(iptablesAzureChains))
-	for _, chain := range iptablesAzureChains {
+	baseChains := iptablesAzureChains
+	if pMgr.FlowLogging {
+		baseChains = append(append([]string{}, iptablesAzureChains...), flowLogChains...)
+	}
+	chainsToCreate := make([]string, 0, len(baseChains))
+	for _, chain := range baseChains {
 		_, exists := currentChains[chain]
 		if !exists {
 			chainsToCreate = append(chainsToCreate, chain)
@@ -409,6 +438,9 @@ func (pMgr *PolicyManager) creatorForBootup(currentChains map[string]struct{}) *
 	}
 
 	// add AZURE-NPM-INGRESS chain rules
+	if pMgr.FlowLogging {
+		creator.AddLine("", nil, jumpToFlowLogChainSpecs(util.IptablesAzureIngressChain, util.IptablesAzureIngressFlowLogChain, util.IptablesAzureIngressDropMarkHex)...)
+	}
 	ingressDropSpecs := []string{util.IptablesAppendFlag, util.IptablesAzureIngressChain, util.IptablesJumpFlag, util.IptablesDrop}
 	ingressDropSpecs = append(ingressDropSpecs, onMarkSpecs(util.IptablesAzureIngressDropMarkHex)...)
 	ingressDropSpecs = append(ingressDropSpecs, commentSpecs(fmt.Sprintf("DROP-ON-INGRESS-DROP-MARK-%s", util.IptablesAzureIngressDropMarkHex))...)
@@ -422,6 +454,9 @@ func (pMgr *PolicyManager) creatorForBootup(currentChains map[string]struct{}) *
 	creator.AddLine("", nil, util.IptablesAppendFlag, util.IptablesAzureIngressAllowMarkChain, util.IptablesJumpFlag, util.IptablesAzureEgressChain)
 
 	// add AZURE-NPM-EGRESS chain rules
+	if pMgr.FlowLogging {
+		creator.AddLine("", nil, jumpToFlowLogChainSpecs(util.IptablesAzureEgressChain, util.IptablesAzureEgressFlowLogChain, util.IptablesAzureEgressDropMarkHex)...)
+	}
 	egressDropSpecs := []string{util.IptablesAppendFlag, util.IptablesAzureEgressChain, util.IptablesJumpFlag, util.IptablesDrop}
 	egressDropSpecs = append(egressDropSpecs, onMarkSpecs(util.IptablesAzureEgressDropMarkHex)...)
 	egressDropSpecs = append(egressDropSpecs, commentSpecs(fmt.Sprintf("DROP-ON-EGRESS-DROP-MARK-%s", util.IptablesAzureEgressDropMarkHex))...)
@@ -0,0 +1,119 @@
+package policies
+
+// This file contains code for logging the connections denied by policies with NFLOG.
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/Azure/azure-container-networking/npm/util"
+	"github.com/Azure/azure-container-networking/npm/util/ioutil"
+	"k8s.io/klog"
+)
+
+// the comments of the NFLOG rules start with this prefix
+const flowLogCommentPrefix = "FLOWLOG-"
+
+// flowLogChains log the connections denied by NamespaceTier policies.
+// AZURE-NPM-INGRESS and AZURE-NPM-EGRESS jump to them right before dropping on the drop mark,
+// so traffic which another policy allows is never logged.
+var flowLogChains = []string{util.IptablesAzureIngressFlowLogChain, util.IptablesAzureEgressFlowLogChain}
+
+// nflogSpecs log the packet at a limited rate. The prefix is the policy chain, which identifies the policy and direction.
+func nflogSpecs(policyChain string) []string {
+	return []string{
+		util.IptablesJumpFlag,
+		util.IptablesNFLogTarget,
+		util.IptablesNFLogGroupFlag,
+		strconv.Itoa(int(util.IptablesAzureNFLogGroup)),
+		util.IptablesNFLogPrefixFlag,
+		policyChain,
+		util.IptablesModuleFlag,
+		util.IptablesLimitModuleFlag,
+		util.IptablesLimitFlag,
+		util.IptablesAzureFlowLogRate,
+	}
+}
+
+// jumpToFlowLogChainSpecs are the specs of the rule in AZURE-NPM-INGRESS or AZURE-NPM-EGRESS which jumps to the flow log chain on the drop mark.
+func jumpToFlowLogChainSpecs(baseChain, flowLogChain, dropMark string) []string {
+	specs := []string{util.IptablesAppendFlag, baseChain, util.IptablesJumpFlag, flowLogChain}
+	specs = append(specs, onMarkSpecs(dropMark)...)
+	return append(specs, commentSpecs(fmt.Sprintf("%sON-DROP-MARK-%s", flowLogCommentPrefix, dropMark))...)
+}
+
+// nflogBeforeDropSpecs are the specs of the rule which logs the traffic an AdminTier or BaselineTier policy drops.
+// These policies drop right away instead of setting the drop mark, so they log in their own chain right before the DROP rule.
+func nflogBeforeDropSpecs(policyChain string, aclPolicy *ACLPolicy, comment string) []string {
+	specs := []string{util.IptablesAppendFlag, policyChain}
+	specs = append(specs, nflogSpecs(policyChain)...)
+	return append(specs, iptablesRuleSpecs(aclPolicy, flowLogCommentPrefix+comment)...)
+}
+
+// writeFlowLogRules adds a rule to the flow log chains for each added NamespaceTier policy which drops traffic.
+// The rule matches the pods the policy selects, since every such policy denies the traffic that reaches the flow log chain.
+// The rules of other policies are left as they are, and deleteFlowLogRules deletes the rules of a removed policy.
+func writeFlowLogRules(creator *ioutil.FileCreator, addedPolicies []*NPMNetworkPolicy) {
+	for _, policy := range addedPolicies {
+		for _, direction := range []UniqueDirection{forIngress, forEgress} {
+			if flowLogChain, specs, ok := flowLogRuleSpecs(policy, direction); ok {
+				line := []string{util.IptablesAppendFlag, flowLogChain}
+				creator.AddLine("", nil, append(line, specs...)...)
+			}
+		}
+	}
+}
+
+// deleteFlowLogRules deletes the rules of the policy in the flow log chains.
+func (pMgr *PolicyManager) deleteFlowLogRules(policy *NPMNetworkPolicy) error {
+	for _, direction := range []UniqueDirection{forIngress, forEgress} {
+		flowLogChain, specs, ok := flowLogRuleSpecs(policy, direction)
+		if !ok {
+			continue
+		}
+		errCode, err := pMgr.runIPTablesCommand(util.IptablesDeletionFlag, append([]string{flowLogChain}, specs...)...)
+		if err != nil && errCode != doesNotExistErrorCode && errCode != couldntLoadTargetErrorCode {
+			errorString := fmt.Sprintf("failed to delete the rule in %s chain for policy %s with exit code %d", flowLogChain, policy.PolicyKey, errCode)
+			klog.Errorf("%s. err: %s", errorString, err.Error())
+			return fmt.Errorf("%s. err: %w", errorString, err)
+		}
+	}
+	return nil
+}
+
+// flowLogRuleSpecs returns the flow log chain and the specs of the policy's rule in it for the direction,
+// or false if the policy has no such rule.
+func flowLogRuleSpecs(policy *NPMNetworkPolicy, direction UniqueDirection) (flowLogChain string, specs []string, ok bool) {
+	if policy.Tier != NamespaceTier || policy.AuditOnly {
+		return "", nil, false
+	}
+	dropsIngress, dropsEgress := policy.dropsIngressAndEgress()
+	if direction == forIngress {
+		if !dropsIngress {
+			return "", nil, false
+		}
+		specs = nflogSpecs(policy.ingressChainName())
+		specs = append(specs, matchSetSpecsForNetworkPolicy(policy, DstMatch)...)
+		specs = append(specs, commentSpecs(flowLogCommentPrefix+policy.commentForJumpToIngress())...)
+		return util.IptablesAzureIngressFlowLogChain, specs, true
+	}
+	if !dropsEgress {
+		return "", nil, false
+	}
+	specs = nflogSpecs(policy.egressChainName())
+	specs = append(specs, matchSetSpecsForNetworkPolicy(policy, SrcMatch)...)
+	specs = append(specs, commentSpecs(flowLogCommentPrefix+policy.commentForJumpToEgress())...)
+	return util.IptablesAzureEgressFlowLogChain, specs, true
+}
+
+// returns two booleans indicating whether the network policy drops ingress and egress traffic respectively
+func (networkPolicy *NPMNetworkPolicy) dropsIngressAndEgress() (dropsIngress, dropsEgress bool) {
+	for _, aclPolicy := range networkPolicy.ACLs {
+		if aclPolicy.Target != Dropped {
+			continue
+		}
+		dropsIngress = dropsIngress || aclPolicy.hasIngress()
+		dropsEgress = dropsEgress || aclPolicy.hasEgress()
+	}
+	return
+}
@@ -0,0 +1,184 @@
+package policies
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-container-networking/common"
+	"github.com/Azure/azure-container-networking/npm/pkg/dataplane/ipsets"
+	dptestutils "github.com/Azure/azure-container-networking/npm/pkg/dataplane/testutils"
+	"github.com/Azure/azure-container-networking/npm/util"
+	testutils "github.com/Azure/azure-container-networking/test/utils"
+	"github.com/stretchr/testify/require"
+)
+
+var flowLogConfig = &PolicyManagerCfg{
+	PolicyMode:           IPSetPolicyMode,
+	PlaceAzureChainFirst: util.PlaceAzureChainFirst,
+	FlowLogging:          true,
+}
+
+func nflogRule(chain string) string {
+	return fmt.Sprintf("-j NFLOG --nflog-group 1023 --nflog-prefix %s -m limit --limit 10/second", chain)
+}
+
+func TestCreatorForFlowLogs(t *testing.T) {
+	ioshim := common.NewMockIOShim(nil)
+	defer ioshim.VerifyCalls(t, nil)
+	pMgr := NewPolicyManager(ioshim, flowLogConfig)
+
+	baselinePolicy := &NPMNetworkPolicy{
+		PolicyKey: "BaselineAdminNetworkPolicy/default",
+		Tier:      BaselineTier,
+		ACLs:      []*ACLPolicy{ingressDeniedACL},
+	}
+	adminPolicy := &NPMNetworkPolicy{
+		PolicyKey: "AdminNetworkPolicy/deny",
+		Tier:      AdminTier,
+		Priority:  10,
+		ACLs:      []*ACLPolicy{ingressDeniedACL},
+	}
+	// egressNetPol only allows traffic and auditNetPol never drops traffic, so neither is logged
+	policies := []*NPMNetworkPolicy{bothDirectionsNetPol, egressNetPol, auditNetPol, baselinePolicy, adminPolicy}
+	creator := pMgr.creatorForNewNetworkPolicies(chainNames(policies), policies)
+	actualLines := strings.Split(creator.ToString(), "\n")
+	baselineChain := baselinePolicy.ingressChainName()
+	adminChain := adminPolicy.ingressChainName()
+	auditChain := auditNetPol.ingressChainName()
+	expectedLines := []string{
+		"*filter",
+		fmt.Sprintf(":%s - -", bothDirectionsNetPolIngressChain),
+		fmt.Sprintf(":%s - -", bothDirectionsNetPolEgressChain),
+		fmt.Sprintf(":%s - -", egressNetPolChain),
+		fmt.Sprintf(":%s - -", auditChain),
+		fmt.Sprintf(":%s - -", baselineChain),
+		fmt.Sprintf(":%s - -", adminChain),
+		"-F AZURE-NPM",
+		"-A AZURE-NPM -j AZURE-NPM-INGRESS",
+		"-A AZURE-NPM -j AZURE-NPM-EGRESS",
+		"-A AZURE-NPM -j AZURE-NPM-ACCEPT",
+		// the NamespaceTier policy chains are unchanged
+		fmt.Sprintf("-A %s %s", bothDirectionsNetPolIngressChain, ingressDropRule),
+		fmt.Sprintf("-A %s %s", bothDirectionsNetPolIngressChain, ingressAllowRule),
+		fmt.Sprintf("-A %s %s", bothDirectionsNetPolEgressChain, egressDropRule),
+		fmt.Sprintf("-A %s %s", bothDirectionsNetPolEgressChain, egressAllowRule),
+		fmt.Sprintf("-I AZURE-NPM-INGRESS 1 %s", ingressEgressNetPolIngressJump),
+		fmt.Sprintf("-I AZURE-NPM-EGRESS 1 %s", ingressEgressNetPolEgressJump),
+		fmt.Sprintf("-A %s %s", egressNetPolChain, egressAllowRule),
+		fmt.Sprintf("-I AZURE-NPM-EGRESS 2 %s", egressNetPolJump),
+		fmt.Sprintf(
+			"-A %s -j RETURN -p TCP --dport 222:333 -m set --match-set %s src -m set ! --match-set %s dst -m comment --comment AUDIT-%s",
+			auditChain, ipsets.TestCIDRSet.HashedName, ipsets.TestKeyPodSet.HashedName, ingressDropComment,
+		),
+		fmt.Sprintf("-A %s -j RETURN -m set --match-set %s src -m comment --comment %s", auditChain, ipsets.TestCIDRSet.HashedName, ingressAllowComment),
+		fmt.Sprintf(
+			"-I AZURE-NPM-INGRESS 2 -j %s -m set --match-set %s dst -m comment --comment INGRESS-POLICY-x/audit-TO-podlabel-test-keyPod-set-IN-ns-x",
+			auditChain, ipsets.TestKeyPodSet.HashedName,
+		),
+		// the baseline policy logs right before dropping
+		fmt.Sprintf(
+			"-A %s %s -p TCP --dport 222:333 -m set --match-set %s src -m set ! --match-set %s dst -m comment --comment FLOWLOG-%s",
+			baselineChain, nflogRule(baselineChain), ipsets.TestCIDRSet.HashedName, ipsets.TestKeyPodSet.HashedName, ingressDropComment,
+		),
+		fmt.Sprintf("-A %s %s", baselineChain, strings.Replace(ingressDropRule, "-j MARK --set-mark "+util.IptablesAzureIngressDropMarkHex, "-j DROP", 1)),
+		// the baseline policy goes after the jump to the flow log chain and the rule dropping on the drop mark
+		fmt.Sprintf("-I AZURE-NPM-INGRESS 5 -j %s -m comment --comment INGRESS-POLICY-BaselineAdminNetworkPolicy/default-TO-all", baselineChain),
+		// the admin policy logs right before dropping too
+		fmt.Sprintf("-A %s -j RETURN -m mark --mark 0x100/0x100 -m comment --comment RETURN-ON-PASS-MARK-0x100/0x100", adminChain),
+		fmt.Sprintf(
+			"-A %s %s -p TCP --dport 222:333 -m set --match-set %s src -m set ! --match-set %s dst -m comment --comment FLOWLOG-%s",
+			adminChain, nflogRule(adminChain), ipsets.TestCIDRSet.HashedName, ipsets.TestKeyPodSet.HashedName, ingressDropComment,
+		),
+		fmt.Sprintf("-A %s %s", adminChain, strings.Replace(ingressDropRule, "-j MARK --set-mark "+util.IptablesAzureIngressDropMarkHex, "-j DROP", 1)),
+		fmt.Sprintf("-I AZURE-NPM-INGRESS 1 -j %s -m comment --comment INGRESS-POLICY-AdminNetworkPolicy/deny-TO-all", adminChain),
+		// the NamespaceTier policy logs in the flow log chains, without rewriting the rules of other policies
+		fmt.Sprintf(
+			"-A AZURE-NPM-INGRESS-FLOWLOG %s -m set --match-set %s dst -m comment --comment FLOWLOG-%s",
+			nflogRule(bothDirectionsNetPolIngressChain), ipsets.TestKeyPodSet.HashedName, bothDirectionsNetPolIngressJumpComment,
+		),
+		fmt.Sprintf(
+			"-A AZURE-NPM-EGRESS-FLOWLOG %s -m set --match-set %s src -m comment --comment FLOWLOG-%s",
+			nflogRule(bothDirectionsNetPolEgressChain), ipsets.TestKeyPodSet.HashedName, bothDirectionsNetPolEgressJumpComment,
+		),
+		"COMMIT",
+		"",
+	}
+	dptestutils.AssertEqualLines(t, expectedLines, actualLines)
+}
+
+func TestRemovePolicyWithFlowLogs(t *testing.T) {
+	deleteFlowLogRule := func(flowLogChain, policyChain, match, jumpComment string) []string {
+		rule := fmt.Sprintf("iptables -w 60 -D %s %s %s -m comment --comment FLOWLOG-%s", flowLogChain, nflogRule(policyChain), match, jumpComment)
+		return strings.Fields(rule)
+	}
+	calls := GetRemovePolicyTestCalls(bothDirectionsNetPol)
+	restoreCall := calls[len(calls)-1]
+	calls = append(calls[:len(calls)-1],
+		// only the rules of the removed policy are deleted
+		testutils.TestCmd{Cmd: deleteFlowLogRule(
+			"AZURE-NPM-INGRESS-FLOWLOG", bothDirectionsNetPolIngressChain,
+			fmt.Sprintf("-m set --match-set %s dst", ipsets.TestKeyPodSet.HashedName), bothDirectionsNetPolIngressJumpComment,
+		)},
+		// a rule which doesn't exist is fine
+		testutils.TestCmd{Cmd: deleteFlowLogRule(
+			"AZURE-NPM-EGRESS-FLOWLOG", bothDirectionsNetPolEgressChain,
+			fmt.Sprintf("-m set --match-set %s src", ipsets.TestKeyPodSet.HashedName), bothDirectionsNetPolEgressJumpComment,
+		), ExitCode: 1},
+		restoreCall,
+	)
+	ioshim := common.NewMockIOShim(calls)
+	defer ioshim.VerifyCalls(t, calls)
+	pMgr := NewPolicyManager(ioshim, flowLogConfig)
+	pMgr.policyMap.cache[bothDirectionsNetPol.PolicyKey] = bothDirectionsNetPol
+	pMgr.policyMap.cache[ingressNetPol.PolicyKey] = ingressNetPol
+
+	require.NoError(t, pMgr.removePolicy(bothDirectionsNetPol, nil))
+
+	creator := pMgr.creatorForRemovingPolicies(chainNames([]*NPMNetworkPolicy{bothDirectionsNetPol}))
+	require.NotContains(t, creator.ToString(), "FLOWLOG")
+}
+
+func TestCreatorForBootupWithFlowLogs(t *testing.T) {
+	pMgr := NewPolicyManager(common.NewMockIOShim(nil), flowLogConfig)
+	creator := pMgr.creatorForBootup(stringsToMap([]string{}))
+	actualLines := strings.Split(creator.ToString(), "\n")
+	expectedLines := []string{
+		"*filter",
+		":AZURE-NPM - -",
+		":AZURE-NPM-INGRESS - -",
+		":AZURE-NPM-INGRESS-ALLOW-MARK - -",
+		":AZURE-NPM-EGRESS - -",
+		":AZURE-NPM-ACCEPT - -",
+		":AZURE-NPM-INGRESS-FLOWLOG - -",
+		":AZURE-NPM-EGRESS-FLOWLOG - -",
+		"-A AZURE-NPM-INGRESS -j AZURE-NPM-INGRESS-FLOWLOG -m mark --mark 0x400/0x400 -m comment --comment FLOWLOG-ON-DROP-MARK-0x400/0x400",
+		"-A AZURE-NPM-INGRESS -j DROP -m mark --mark 0x400/0x400 -m comment --comment DROP-ON-INGRESS-DROP-MARK-0x400/0x400",
+		"-A AZURE-NPM-INGRESS-ALLOW-MARK -j MARK --set-mark 0x200/0x200 -m comment --comment SET-INGRESS-ALLOW-MARK-0x200/0x200",
+		"-A AZURE-NPM-INGRESS-ALLOW-MARK -j AZURE-NPM-EGRESS",
+		"-A AZURE-NPM-EGRESS -j AZURE-NPM-EGRESS-FLOWLOG -m mark --mark 0x800/0x800 -m comment --comment FLOWLOG-ON-DROP-MARK-0x800/0x800",
+		"-A AZURE-NPM-EGRESS -j DROP -m mark --mark 0x800/0x800 -m comment --comment DROP-ON-EGRESS-DROP-MARK-0x800/0x800",
+		"-A AZURE-NPM-EGRESS -j AZURE-NPM-ACCEPT -m mark --mark 0x200/0x200 -m comment --comment ACCEPT-ON-INGRESS-ALLOW-MARK-0x200/0x200",
+		"-A AZURE-NPM-ACCEPT -j ACCEPT",
+		"COMMIT",
+		"",
+	}
+	dptestutils.AssertEqualLines(t, expectedLines, actualLines)
+
+	// the flow log chains are never stale
+	creator = pMgr.creatorForBootup(stringsToMap([]string{"AZURE-NPM-INGRESS-FLOWLOG", "AZURE-NPM-EGRESS-FLOWLOG"}))
+	require.Contains(t, creator.ToString(), "-F AZURE-NPM-INGRESS-FLOWLOG\n")
+	assertStaleChainsContain(t, pMgr.staleChains)
+}
+
+func TestGetPolicyKeyByHash(t *testing.T) {
+	pMgr := NewPolicyManager(common.NewMockIOShim(nil), flowLogConfig)
+	pMgr.policyMap.cache[bothDirectionsNetPol.PolicyKey] = bothDirectionsNetPol
+
+	policyKey, ok := pMgr.GetPolicyKeyByHash(util.Hash(bothDirectionsNetPol.PolicyKey))
+	require.True(t, ok)
+	require.Equal(t, bothDirectionsNetPol.PolicyKey, policyKey)
+
+	_, ok = pMgr.GetPolicyKeyByHash(util.Hash(ingressNetPol.PolicyKey))
+	require.False(t, ok)
+}
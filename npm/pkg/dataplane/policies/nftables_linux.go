@@ -0,0 +1,170 @@
+package policies
+
+// This file contains code for the nftables implementation of adding/removing policies.
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Azure/azure-container-networking/npm/metrics"
+	"github.com/Azure/azure-container-networking/npm/pkg/dataplane/nftables"
+	"github.com/Azure/azure-container-networking/npm/util"
+	npmerrors "github.com/Azure/azure-container-networking/npm/util/errors"
+	"k8s.io/klog"
+)
+
+const (
+	nftSrcMatch         = "ip saddr"
+	nftDstMatch         = "ip daddr"
+	nftNamedPortMatch   = "ip daddr . meta l4proto . th dport"
+	nftProtocolMatch    = "meta l4proto"
+	nftDstPortMatch     = "th dport"
+	nftJumpStatement    = "jump"
+	nftPortRangeDivider = "-"
+)
+
+var errUnsupportedNFTablesTier = errors.New("only NetworkPolicies are supported with nftables")
+
+var nftMatchTypeStrings = map[MatchType]string{
+	SrcMatch:    nftSrcMatch,
+	DstMatch:    nftDstMatch,
+	DstDstMatch: nftNamedPortMatch,
+}
+
+// UseNFTables makes the PolicyManager apply policies as chains of the nftables ruleset instead of with iptables.
+func (pMgr *PolicyManager) UseNFTables(ruleset *nftables.Ruleset) {
+	pMgr.nftRuleset = ruleset
+}
+
+// bootupNFTables replaces the NPM table with one holding only the base chains, which leaves NPM deactivated.
+// The jumps to AZURE-NPM and the Azure chains NPM may have left in iptables when it ran with it before are removed,
+// from both iptables-nft and the legacy iptables, as they would keep filtering traffic with stale policies.
+// Failing to remove them is only reported, since the iptables binaries may not be installed.
+func (pMgr *PolicyManager) bootupNFTables() error {
+	klog.Infof("booting up nftables table %s %s", nftables.Family, nftables.TableName)
+	if err := pMgr.nftRuleset.Reset(); err != nil {
+		return npmerrors.SimpleErrorWrapper("failed to reset nftables table for bootup", err)
+	}
+
+	pMgr.reconcileManager.forceLock()
+	defer pMgr.reconcileManager.forceUnlock()
+
+	iptables, iptablesSave, iptablesRestore := util.Iptables, util.IptablesSave, util.IptablesRestore
+	defer func() {
+		util.Iptables, util.IptablesSave, util.IptablesRestore = iptables, iptablesSave, iptablesRestore
+	}()
+	for _, binaries := range [][3]string{
+		{util.IptablesNft, util.IptablesSaveNft, util.IptablesRestoreNft},
+		{util.IptablesLegacy, util.IptablesSaveLegacy, util.IptablesRestoreLegacy},
+	} {
+		util.Iptables, util.IptablesSave, util.IptablesRestore = binaries[0], binaries[1], binaries[2]
+		klog.Infof("cleaning up Azure chains in %s", util.Iptables)
+		if err := pMgr.removeAzureChains(); err != nil {
+			metrics.SendErrorLogAndMetric(util.IptmID, "failed to clean up Azure chains in %s with error: %s", util.Iptables, err.Error())
+		}
+	}
+	return nil
+}
+
+func (pMgr *PolicyManager) addNFTablesPolicies(networkPolicies []*NPMNetworkPolicy) error {
+	toAdd := make([]*nftables.Policy, 0, len(networkPolicies))
+	for _, networkPolicy := range networkPolicies {
+		if networkPolicy.Tier != NamespaceTier {
+			return fmt.Errorf("policy %s has tier %s: %w", networkPolicy.PolicyKey, networkPolicy.Tier, errUnsupportedNFTablesTier)
+		}
+		if networkPolicy.AuditOnly {
+			return fmt.Errorf("policy %s: %w", networkPolicy.PolicyKey, ErrUnsupportedNFTablesAudit)
+		}
+		toAdd = append(toAdd, nftPolicy(networkPolicy))
+	}
+	if err := pMgr.nftRuleset.ApplyPolicies(toAdd, nil); err != nil {
+		return fmt.Errorf("failed to apply nftables ruleset with updated policies. err: %w", err)
+	}
+	return nil
+}
+
+func (pMgr *PolicyManager) removeNFTablesPolicy(networkPolicy *NPMNetworkPolicy) error {
+	if err := pMgr.nftRuleset.ApplyPolicies(nil, []string{networkPolicy.PolicyKey}); err != nil {
+		return fmt.Errorf("failed to apply nftables ruleset without policy. err: %w", err)
+	}
+	return nil
+}
+
+// nftPolicy returns the chains of the network policy, with the same rules as the iptables chains.
+func nftPolicy(networkPolicy *NPMNetworkPolicy) *nftables.Policy {
+	policy := &nftables.Policy{Key: networkPolicy.PolicyKey}
+	selectors := make([]nftables.Selector, 0, len(networkPolicy.PodSelectorList))
+	for _, setInfo := range networkPolicy.PodSelectorList {
+		selectors = append(selectors, nftables.Selector{Set: setInfo.IPSet.GetHashedName(), Included: setInfo.Included})
+	}
+
+	hasIngress, hasEgress := networkPolicy.hasIngressAndEgress()
+	if hasIngress {
+		policy.Ingress = &nftables.PolicyChain{
+			Name:      networkPolicy.ingressChainName(),
+			Comment:   networkPolicy.commentForJumpToIngress(),
+			Selectors: selectors,
+		}
+	}
+	if hasEgress {
+		policy.Egress = &nftables.PolicyChain{
+			Name:      networkPolicy.egressChainName(),
+			Comment:   networkPolicy.commentForJumpToEgress(),
+			Selectors: selectors,
+		}
+	}
+
+	for _, aclPolicy := range networkPolicy.ACLs {
+		var chain *nftables.PolicyChain
+		var verdict string
+		if aclPolicy.hasIngress() {
+			chain = policy.Ingress
+			if aclPolicy.Target == Allowed {
+				verdict = nftJumpStatement + " " + util.IptablesAzureIngressAllowMarkChain
+			} else {
+				verdict = nftables.SetMark(util.IptablesAzureIngressDropMarkHex)
+			}
+		} else {
+			chain = policy.Egress
+			if aclPolicy.Target == Allowed {
+				verdict = nftJumpStatement + " " + util.IptablesAzureAcceptChain
+			} else {
+				verdict = nftables.SetMark(util.IptablesAzureEgressDropMarkHex)
+			}
+		}
+		chain.Rules = append(chain.Rules, nftRule(aclPolicy, verdict))
+	}
+	return policy
+}
+
+func nftRule(aclPolicy *ACLPolicy, verdict string) string {
+	rule := make([]string, 0)
+	if aclPolicy.Protocol != UnspecifiedProtocol {
+		rule = append(rule, nftProtocolMatch, strings.ToLower(string(aclPolicy.Protocol)))
+	}
+	if aclPolicy.DstPorts.Port != 0 || aclPolicy.DstPorts.EndPort != 0 {
+		rule = append(rule, nftDstPortMatch, aclPolicy.DstPorts.toNFTablesString())
+	}
+	rule = append(rule, nftMatchSets(aclPolicy.SrcList)...)
+	rule = append(rule, nftMatchSets(aclPolicy.DstList)...)
+	rule = append(rule, verdict, nftables.Comment(aclPolicy.comment()))
+	return strings.Join(rule, " ")
+}
+
+func nftMatchSets(setInfoList []SetInfo) []string {
+	matches := make([]string, 0, len(setInfoList))
+	for _, setInfo := range setInfoList {
+		matches = append(matches, nftables.MatchSet(nftMatchTypeStrings[setInfo.MatchType], setInfo.IPSet.GetHashedName(), setInfo.Included))
+	}
+	return matches
+}
+
+func (portRange *Ports) toNFTablesString() string {
+	start := strconv.Itoa(int(portRange.Port))
+	if portRange.Port >= portRange.EndPort {
+		return start
+	}
+	return start + nftPortRangeDivider + strconv.Itoa(int(portRange.EndPort))
+}
@@ -0,0 +1,153 @@
+package policies
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Azure/azure-container-networking/common"
+	"github.com/Azure/azure-container-networking/npm/pkg/dataplane/ipsets"
+	"github.com/Azure/azure-container-networking/npm/pkg/dataplane/nftables"
+	"github.com/Azure/azure-container-networking/npm/util"
+	testutils "github.com/Azure/azure-container-networking/test/utils"
+	"github.com/stretchr/testify/require"
+)
+
+var fakeNFTCommand = testutils.TestCmd{Cmd: []string{"nft", "-f", "-"}}
+
+// nftBootupCalls resets the table, then finds no Azure chains in iptables-nft nor in iptables-legacy.
+func nftBootupCalls() []testutils.TestCmd {
+	calls := []testutils.TestCmd{fakeNFTCommand}
+	for _, iptables := range []string{util.IptablesNft, util.IptablesLegacy} {
+		calls = append(calls,
+			testutils.TestCmd{Cmd: []string{iptables, "-w", "60", "-D", "FORWARD", "-j", "AZURE-NPM"}, ExitCode: 2},
+			testutils.TestCmd{Cmd: []string{iptables, "-w", "60", "-D", "FORWARD", "-j", "AZURE-NPM", "-m", "conntrack", "--ctstate", "NEW"}, ExitCode: 2},
+			testutils.TestCmd{Cmd: []string{iptables, "-w", "60", "-t", "filter", "-n", "-L"}, PipedToCommand: true},
+			testutils.TestCmd{Cmd: []string{"grep", "Chain AZURE-NPM"}, ExitCode: 1},
+		)
+	}
+	return calls
+}
+
+func TestNFTablesRules(t *testing.T) {
+	tests := []struct {
+		name     string
+		acl      *ACLPolicy
+		verdict  string
+		expected string
+	}{
+		{
+			name:    "ingress drop with port range",
+			acl:     ingressDeniedACL,
+			verdict: nftables.SetMark(util.IptablesAzureIngressDropMarkHex),
+			expected: fmt.Sprintf(
+				"meta l4proto tcp th dport 222-333 ip saddr @%s ip daddr != @%s meta mark set meta mark | 0x400 comment \"%s\"",
+				ipsets.TestCIDRSet.HashedName,
+				ipsets.TestKeyPodSet.HashedName,
+				ingressDropComment,
+			),
+		},
+		{
+			name:     "ingress allow",
+			acl:      ingressAllowedACL,
+			verdict:  "jump AZURE-NPM-INGRESS-ALLOW-MARK",
+			expected: fmt.Sprintf("ip saddr @%s jump AZURE-NPM-INGRESS-ALLOW-MARK comment \"%s\"", ipsets.TestCIDRSet.HashedName, ingressAllowComment),
+		},
+		{
+			name:    "egress drop with single port",
+			acl:     egressDeniedACL,
+			verdict: nftables.SetMark(util.IptablesAzureEgressDropMarkHex),
+			expected: fmt.Sprintf(
+				"meta l4proto udp th dport 144 ip daddr @%s meta mark set meta mark | 0x800 comment \"%s\"",
+				ipsets.TestCIDRSet.HashedName,
+				egressDropComment,
+			),
+		},
+		{
+			name: "egress allow to named port",
+			acl: &ACLPolicy{
+				DstList:   []SetInfo{{ipsets.TestNamedportSet.Metadata, true, DstDstMatch}},
+				Target:    Allowed,
+				Direction: Egress,
+				Protocol:  UnspecifiedProtocol,
+			},
+			verdict:  "jump AZURE-NPM-ACCEPT",
+			expected: fmt.Sprintf("ip daddr . meta l4proto . th dport @%s jump AZURE-NPM-ACCEPT comment \"%s\"", ipsets.TestNamedportSet.HashedName, egressAllowComment),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, nftRule(tt.acl, tt.verdict))
+		})
+	}
+}
+
+func TestNFTablesPolicy(t *testing.T) {
+	policy := nftPolicy(bothDirectionsNetPol)
+	require.Equal(t, bothDirectionsNetPol.PolicyKey, policy.Key)
+
+	require.Equal(t, bothDirectionsNetPolIngressChain, policy.Ingress.Name)
+	require.Equal(t, bothDirectionsNetPolIngressJumpComment, policy.Ingress.Comment)
+	require.Equal(t, []nftables.Selector{{Set: ipsets.TestKeyPodSet.HashedName, Included: true}}, policy.Ingress.Selectors)
+	require.Equal(t, []string{
+		nftRule(ingressDeniedACL, nftables.SetMark(util.IptablesAzureIngressDropMarkHex)),
+		nftRule(ingressAllowedACL, "jump AZURE-NPM-INGRESS-ALLOW-MARK"),
+	}, policy.Ingress.Rules)
+
+	require.Equal(t, bothDirectionsNetPolEgressChain, policy.Egress.Name)
+	require.Equal(t, bothDirectionsNetPolEgressJumpComment, policy.Egress.Comment)
+	require.Equal(t, []string{
+		nftRule(egressDeniedACL, nftables.SetMark(util.IptablesAzureEgressDropMarkHex)),
+		nftRule(egressAllowedACL, "jump AZURE-NPM-ACCEPT"),
+	}, policy.Egress.Rules)
+
+	policy = nftPolicy(egressNetPol)
+	require.Nil(t, policy.Ingress)
+	require.Empty(t, policy.Egress.Selectors)
+}
+
+func TestNFTablesBootupRemovesIPTablesChains(t *testing.T) {
+	calls := []testutils.TestCmd{
+		fakeNFTCommand,
+		// NPM ran with iptables-nft before
+		{Cmd: []string{"iptables-nft", "-w", "60", "-D", "FORWARD", "-j", "AZURE-NPM"}, ExitCode: 2},
+		{Cmd: []string{"iptables-nft", "-w", "60", "-D", "FORWARD", "-j", "AZURE-NPM", "-m", "conntrack", "--ctstate", "NEW"}},
+		{Cmd: []string{"iptables-nft", "-w", "60", "-t", "filter", "-n", "-L"}, PipedToCommand: true},
+		{Cmd: []string{"grep", "Chain AZURE-NPM"}, Stdout: "Chain AZURE-NPM (1 references)\n"},
+		{Cmd: []string{"iptables-nft", "-w", "60", "-F", "AZURE-NPM"}},
+		{Cmd: []string{"iptables-nft", "-w", "60", "-X", "AZURE-NPM"}},
+		// nor with the legacy iptables, whose commands fail
+		{Cmd: []string{"iptables", "-w", "60", "-D", "FORWARD", "-j", "AZURE-NPM"}, ExitCode: 3},
+		{Cmd: []string{"iptables", "-w", "60", "-D", "FORWARD", "-j", "AZURE-NPM", "-m", "conntrack", "--ctstate", "NEW"}, ExitCode: 3},
+		{Cmd: []string{"iptables", "-w", "60", "-t", "filter", "-n", "-L"}, PipedToCommand: true},
+		{Cmd: []string{"grep", "Chain AZURE-NPM"}, ExitCode: 2},
+	}
+	ioshim := common.NewMockIOShim(calls)
+	defer ioshim.VerifyCalls(t, calls)
+	pMgr := NewPolicyManager(ioshim, ipsetConfig)
+	pMgr.UseNFTables(nftables.NewRuleset(ioshim, util.PlaceAzureChainFirst))
+
+	iptables := util.Iptables
+	require.NoError(t, pMgr.Bootup(nil))
+	require.Equal(t, iptables, util.Iptables)
+}
+
+func TestNFTablesAddAndRemovePolicies(t *testing.T) {
+	calls := append(nftBootupCalls(), fakeNFTCommand, fakeNFTCommand)
+	ioshim := common.NewMockIOShim(calls)
+	defer ioshim.VerifyCalls(t, calls)
+	ruleset := nftables.NewRuleset(ioshim, util.PlaceAzureChainFirst)
+	pMgr := NewPolicyManager(ioshim, ipsetConfig)
+	pMgr.UseNFTables(ruleset)
+
+	require.NoError(t, pMgr.Bootup(nil))
+	require.NoError(t, pMgr.AddPolicies(allTestNetworkPolicies, nil))
+	rendered := ruleset.Render()
+	require.Contains(t, rendered, fmt.Sprintf("\tchain %s {\n", bothDirectionsNetPolIngressChain))
+	require.Contains(t, rendered, fmt.Sprintf("\tchain %s {\n", ingressNetPolChain))
+	require.Contains(t, rendered, fmt.Sprintf("\t\tjump %s comment \"%s\"\n", egressNetPolChain, egressNetPolJumpComment))
+
+	require.NoError(t, pMgr.RemovePolicy(egressNetPol.PolicyKey))
+	require.NotContains(t, ruleset.Render(), egressNetPolChain)
+}
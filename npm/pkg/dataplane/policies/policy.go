@@ -29,6 +29,12 @@ type NPMNetworkPolicy struct {
 	// and not from pod selector IPSets, including children of a NestedLabelOfPod ipset
 	RuleIPSets []*ipsets.TranslatedIPSet
 	ACLs       []*ACLPolicy
+	// Tier is NamespaceTier for NetworkPolicies, or AdminTier or BaselineTier for cluster-scoped admin policies (Linux only)
+	Tier Tier
+	// Priority orders the policies of the AdminTier. A lower Priority is evaluated first.
+	Priority int32
+	// AuditOnly policies never allow or drop traffic. The traffic they would have dropped is only counted (Linux only).
+	AuditOnly bool
 	// podIP is key and endpoint ID as value
 	// Will be populated by dataplane and policy manager
 	PodEndpoints map[string]string
@@ -68,6 +74,21 @@ func (netPol *NPMNetworkPolicy) numACLRulesProducedInKernel() int {
 	if hasEgress {
 		numRules++
 	}
+
+	if netPol.Tier == AdminTier {
+		// a Pass rule needs a second rule to return on the pass mark, and each chain starts with a return on the pass mark
+		for _, aclPolicy := range netPol.ACLs {
+			if aclPolicy.Target == Passed {
+				numRules++
+			}
+		}
+		if hasIngress {
+			numRules++
+		}
+		if hasEgress {
+			numRules++
+		}
+	}
 	return numRules
 }
 
@@ -132,10 +153,26 @@ func NormalizePolicy(networkPolicy *NPMNetworkPolicy) {
 }
 
 func ValidatePolicy(networkPolicy *NPMNetworkPolicy) error {
+	if !networkPolicy.hasKnownTier() {
+		return npmerrors.SimpleError(fmt.Sprintf("NetPol %s has unknown tier [%s]", networkPolicy.PolicyKey, networkPolicy.Tier))
+	}
+	if util.IsWindowsDP() && networkPolicy.Tier != NamespaceTier {
+		return npmerrors.SimpleError(fmt.Sprintf("NetPol %s has unsupported tier [%s] on Windows", networkPolicy.PolicyKey, networkPolicy.Tier))
+	}
+	if networkPolicy.AuditOnly && util.IsWindowsDP() {
+		return npmerrors.SimpleError(fmt.Sprintf("NetPol %s can't be audit-only on Windows", networkPolicy.PolicyKey))
+	}
+	if networkPolicy.AuditOnly && networkPolicy.Tier != NamespaceTier {
+		return npmerrors.SimpleError(fmt.Sprintf("NetPol %s can't be audit-only in tier [%s]", networkPolicy.PolicyKey, networkPolicy.Tier))
+	}
+
 	for _, aclPolicy := range networkPolicy.ACLs {
 		if !aclPolicy.hasKnownTarget() {
 			return npmerrors.SimpleError(fmt.Sprintf("ACL policy for NetPol %s has unknown target [%s]", networkPolicy.PolicyKey, aclPolicy.Target))
 		}
+		if aclPolicy.Target == Passed && networkPolicy.Tier != AdminTier {
+			return npmerrors.SimpleError(fmt.Sprintf("ACL policy for NetPol %s has target [%s] outside of tier [%s]", networkPolicy.PolicyKey, Passed, AdminTier))
+		}
 		if !aclPolicy.hasKnownDirection() {
 			return npmerrors.SimpleError(fmt.Sprintf("ACL policy for NetPol %s has unknown direction [%s]", networkPolicy.PolicyKey, aclPolicy.Direction))
 		}
@@ -173,6 +210,10 @@ func ValidatePolicy(networkPolicy *NPMNetworkPolicy) error {
 	return nil
 }
 
+func (netPol *NPMNetworkPolicy) hasKnownTier() bool {
+	return netPol.Tier == NamespaceTier || netPol.Tier == AdminTier || netPol.Tier == BaselineTier
+}
+
 func NewACLPolicy(target Verdict, direction Direction) *ACLPolicy {
 	acl := &ACLPolicy{
 		Target:    target,
@@ -224,7 +265,7 @@ func (aclPolicy *ACLPolicy) hasKnownProtocol() bool {
 }
 
 func (aclPolicy *ACLPolicy) hasKnownTarget() bool {
-	return aclPolicy.Target == Allowed || aclPolicy.Target == Dropped
+	return aclPolicy.Target == Allowed || aclPolicy.Target == Dropped || aclPolicy.Target == Passed
 }
 
 func (aclPolicy *ACLPolicy) satisifiesPortAndProtocolConstraints() bool {
@@ -330,6 +371,21 @@ const (
 	Allowed Verdict = "ALLOW"
 	// Dropped is denying a flow
 	Dropped Verdict = "DROP"
+	// Passed skips the remaining policies of the AdminTier. Only valid in the AdminTier.
+	Passed Verdict = "PASS"
+)
+
+// Tier decides when a policy is evaluated relative to policies of other tiers.
+type Tier string
+
+const (
+	// NamespaceTier is for NetworkPolicies
+	NamespaceTier Tier = ""
+	// AdminTier is for AdminNetworkPolicies, which are evaluated before NetworkPolicies.
+	// The first Allowed, Dropped, or Passed ACL of the tier decides.
+	AdminTier Tier = "ADMIN"
+	// BaselineTier is for the BaselineAdminNetworkPolicy, which is only evaluated for pods that no NetworkPolicy isolates.
+	BaselineTier Tier = "BASELINE"
 )
 
 // Protocol can be TCP, UDP, SCTP, or unspecified since they are currently supported in networkpolicy.
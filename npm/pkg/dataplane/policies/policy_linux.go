@@ -72,6 +72,10 @@ func (networkPolicy *NPMNetworkPolicy) commentForJump(direction UniqueDirection)
 	if len(networkPolicy.PodSelectorList) > 0 {
 		podSelectorComment = commentForInfos(networkPolicy.PodSelectorList)
 	}
+	if networkPolicy.Namespace == "" {
+		// cluster-scoped admin policies
+		return fmt.Sprintf("%s-POLICY-%s-%s-%s", prefix, networkPolicy.PolicyKey, toFrom, podSelectorComment)
+	}
 	return fmt.Sprintf("%s-POLICY-%s-%s-%s-IN-ns-%s", prefix, networkPolicy.PolicyKey, toFrom, podSelectorComment, networkPolicy.Namespace)
 }
 
@@ -128,9 +132,12 @@ func (aclPolicy *ACLPolicy) comment() string {
 	}
 
 	builder := strings.Builder{}
-	if aclPolicy.Target == Allowed {
+	switch aclPolicy.Target {
+	case Allowed:
 		builder.WriteString("ALLOW")
-	} else {
+	case Passed:
+		builder.WriteString("PASS")
+	default:
 		builder.WriteString("DROP")
 	}
 
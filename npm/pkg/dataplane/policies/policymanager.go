@@ -1,16 +1,21 @@
 package policies
 
 import (
+	"errors"
 	"fmt"
 	"sync"
 
 	"github.com/Azure/azure-container-networking/common"
 	"github.com/Azure/azure-container-networking/npm/metrics"
+	"github.com/Azure/azure-container-networking/npm/pkg/dataplane/nftables"
 	"github.com/Azure/azure-container-networking/npm/util"
 	npmerrors "github.com/Azure/azure-container-networking/npm/util/errors"
 	"k8s.io/klog"
 )
 
+// ErrUnsupportedNFTablesAudit is returned for audit-only policies when policies are applied with nftables.
+var ErrUnsupportedNFTablesAudit = errors.New("audit-only NetworkPolicies are not supported with nftables")
+
 // PolicyManagerMode will be used in windows to decide if
 // SetPolicies should be used or not
 type PolicyManagerMode string
@@ -26,6 +31,8 @@ const (
 	// it represents the number of rules unrelated to policies
 	// it's technically 3 off when there are no policies since we flush the AZURE-NPM chain then
 	numLinuxBaseACLRules = 11
+	// the jumps to the flow log chains
+	numLinuxFlowLogBaseACLRules = 2
 )
 
 type PolicyManagerCfg struct {
@@ -35,6 +42,8 @@ type PolicyManagerCfg struct {
 	PolicyMode PolicyManagerMode
 	// PlaceAzureChainFirst only affects Linux
 	PlaceAzureChainFirst bool
+	// FlowLogging only affects Linux with iptables. It logs the connections denied by policies to an NFLOG group.
+	FlowLogging bool
 	// MaxBatchedACLsPerPod is the maximum number of ACLs that can be added to a Pod at once in Windows.
 	// The zero value is valid.
 	// A NetworkPolicy's ACLs are always in the same batch, and there will be at least one NetworkPolicy per batch.
@@ -62,6 +71,11 @@ type PolicyManager struct {
 	ioShim           *common.IOShim
 	staleChains      *staleChains
 	reconcileManager *reconcileManager
+	// nftRuleset is set when policies are applied as nftables chains instead of with iptables (Linux only)
+	nftRuleset *nftables.Ruleset
+	// auditCounts is the last reported count of packets denied by each audit-only policy (Linux only).
+	// It is guarded by the reconcileManager lock.
+	auditCounts map[string]int
 	*PolicyManagerCfg
 }
 
@@ -72,6 +86,7 @@ func NewPolicyManager(ioShim *common.IOShim, cfg *PolicyManagerCfg) *PolicyManag
 		},
 		ioShim:      ioShim,
 		staleChains: newStaleChains(),
+		auditCounts: make(map[string]int),
 		reconcileManager: &reconcileManager{
 			releaseLockSignal: make(chan struct{}, 1),
 		},
@@ -97,6 +112,9 @@ func (pMgr *PolicyManager) Bootup(epIDs []string) error {
 	if !util.IsWindowsDP() {
 		// update Prometheus metrics on success
 		metrics.IncNumACLRulesBy(numLinuxBaseACLRules)
+		if pMgr.FlowLogging {
+			metrics.IncNumACLRulesBy(numLinuxFlowLogBaseACLRules)
+		}
 	}
 
 	if util.IsWindowsDP() && pMgr.NodeIP == "" {
@@ -126,6 +144,20 @@ func (pMgr *PolicyManager) GetPolicy(policyKey string) (*NPMNetworkPolicy, bool)
 	return policy, ok
 }
 
+// GetPolicyKeyByHash returns the key of the policy whose key has the hash (see util.Hash).
+// In Linux, the hash identifies the policy in its chain names.
+func (pMgr *PolicyManager) GetPolicyKeyByHash(hash string) (string, bool) {
+	pMgr.policyMap.RLock()
+	defer pMgr.policyMap.RUnlock()
+
+	for policyKey := range pMgr.policyMap.cache {
+		if util.Hash(policyKey) == hash {
+			return policyKey, true
+		}
+	}
+	return "", false
+}
+
 func (pMgr *PolicyManager) AddPolicies(policies []*NPMNetworkPolicy, endpointList map[string]string) error {
 	nonEmptyPolicies := make([]*NPMNetworkPolicy, 0, len(policies))
 	for _, policy := range policies {
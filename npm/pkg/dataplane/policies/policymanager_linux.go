@@ -42,6 +42,10 @@ Known errors that we should retry on:
 */
 
 func (pMgr *PolicyManager) addPolicies(networkPolicies []*NPMNetworkPolicy, _ map[string]string) error {
+	if pMgr.nftRuleset != nil {
+		return pMgr.addNFTablesPolicies(networkPolicies)
+	}
+
 	// 1. Add rules for the network policies and activate NPM (if necessary).
 	chainsToCreate := chainNames(networkPolicies)
 	creator := pMgr.creatorForNewNetworkPolicies(chainsToCreate, networkPolicies)
@@ -66,6 +70,10 @@ func (pMgr *PolicyManager) addPolicies(networkPolicies []*NPMNetworkPolicy, _ ma
 }
 
 func (pMgr *PolicyManager) removePolicy(networkPolicy *NPMNetworkPolicy, _ map[string]string) error {
+	if pMgr.nftRuleset != nil {
+		return pMgr.removeNFTablesPolicy(networkPolicy)
+	}
+
 	chainsToDelete := chainNames([]*NPMNetworkPolicy{networkPolicy})
 	creator := pMgr.creatorForRemovingPolicies(chainsToDelete)
 
@@ -79,6 +87,12 @@ func (pMgr *PolicyManager) removePolicy(networkPolicy *NPMNetworkPolicy, _ map[s
 	if deleteErr != nil {
 		return fmt.Errorf("failed to delete jumps to policy chains. err: %w", deleteErr)
 	}
+	// Likewise, stop logging the connections denied by the policy.
+	if pMgr.FlowLogging {
+		if deleteErr := pMgr.deleteFlowLogRules(networkPolicy); deleteErr != nil {
+			return fmt.Errorf("failed to delete flow log rules. err: %w", deleteErr)
+		}
+	}
 
 	// 2. Flush the policy chains and deactivate NPM (if necessary).
 	timer := metrics.StartNewTimer()
@@ -93,6 +107,7 @@ func (pMgr *PolicyManager) removePolicy(networkPolicy *NPMNetworkPolicy, _ map[s
 	for _, chain := range chainsToDelete {
 		pMgr.staleChains.add(chain)
 	}
+	pMgr.forgetAuditCounts(networkPolicy)
 	return nil
 }
 
@@ -116,6 +131,7 @@ func (pMgr *PolicyManager) creatorForRemovingPolicies(allChainNames []string) *i
 	for _, chainName := range allChainNames {
 		creator.AddLine("", nil, util.IptablesFlushFlag, chainName)
 	}
+
 	creator.AddLine("", nil, util.IptablesRestoreCommit)
 	return creator
 }
@@ -221,57 +237,187 @@ func (pMgr *PolicyManager) creatorForNewNetworkPolicies(policyChains []string, n
 	}
 
 	// 2. Add all rules for the network policies
-	ingressJumpLineNumber := 1
-	egressJumpLineNumber := 1
+	ingressJumps := pMgr.newJumpOrder(forIngress)
+	egressJumps := pMgr.newJumpOrder(forEgress)
 	for _, networkPolicy := range networkPolicies {
 		// 2.1 add all rules for the policy chain(s)
-		writeNetworkPolicyRules(creator, networkPolicy)
+		writeNetworkPolicyRules(creator, networkPolicy, pMgr.FlowLogging)
 
 		// 2.2 add jump rule(s) to the policy chain(s)
 		hasIngress, hasEgress := networkPolicy.hasIngressAndEgress()
 		if hasIngress {
-			ingressJumpSpecs := insertSpecs(util.IptablesAzureIngressChain, ingressJumpLineNumber, ingressJumpSpecs(networkPolicy))
+			ingressJumpSpecs := insertSpecs(util.IptablesAzureIngressChain, ingressJumps.lineNumber(networkPolicy), ingressJumpSpecs(networkPolicy))
 			creator.AddLine("", nil, ingressJumpSpecs...) // TODO error handler
-			ingressJumpLineNumber++
 		}
 		if hasEgress {
-			egressJumpSpecs := insertSpecs(util.IptablesAzureEgressChain, egressJumpLineNumber, egressJumpSpecs(networkPolicy))
+			egressJumpSpecs := insertSpecs(util.IptablesAzureEgressChain, egressJumps.lineNumber(networkPolicy), egressJumpSpecs(networkPolicy))
 			creator.AddLine("", nil, egressJumpSpecs...) // TODO error handler
-			egressJumpLineNumber++
 		}
 	}
+
+	// 3. Log the connections denied by the policies
+	if pMgr.FlowLogging {
+		writeFlowLogRules(creator, networkPolicies)
+	}
 	creator.AddLine("", nil, util.IptablesRestoreCommit)
 	return creator
 }
 
+// jumpOrder finds where to insert a jump to a policy chain in AZURE-NPM-INGRESS or AZURE-NPM-EGRESS.
+// The chain has, in order:
+// 1. jumps to AdminTier policy chains, by priority
+// 2. jumps to enforcing NamespaceTier policy chains, newest first
+// 3. jumps to audit-only NamespaceTier policy chains, newest first
+// 4. the jump to the flow log chain (only with flow logging) and the rule dropping on the drop mark
+// 5. the jump to the BaselineTier policy chain
+// 6. the rule accepting on the ingress allow mark (AZURE-NPM-EGRESS only)
+//
+// Audit-only policy chains come after the enforcing ones so that their AUDIT rules only count the traffic
+// that no enforcing policy allowed, regardless of the order the policies were applied in.
+type jumpOrder struct {
+	adminPolicies []*NPMNetworkPolicy
+	// numNamespacePolicies counts both the enforcing and the audit-only NamespaceTier policies
+	numNamespacePolicies      int
+	numAuditPolicies          int
+	numAddedNamespacePolicies int
+	numAddedAuditPolicies     int
+	numDropRules              int
+}
+
+// newJumpOrder returns the jumpOrder for the policies in the kernel. The caller must lock the policyMap.
+func (pMgr *PolicyManager) newJumpOrder(direction UniqueDirection) *jumpOrder {
+	order := &jumpOrder{numDropRules: 1}
+	if pMgr.FlowLogging {
+		order.numDropRules++
+	}
+	for _, policy := range pMgr.policyMap.cache {
+		hasIngress, hasEgress := policy.hasIngressAndEgress()
+		if (direction == forIngress && !hasIngress) || (direction == forEgress && !hasEgress) {
+			continue
+		}
+		switch policy.Tier {
+		case AdminTier:
+			order.adminPolicies = append(order.adminPolicies, policy)
+		case NamespaceTier:
+			order.numNamespacePolicies++
+			if policy.AuditOnly {
+				order.numAuditPolicies++
+			}
+		}
+	}
+	return order
+}
+
+// lineNumber returns the line number to insert the jump to the policy chain at, and accounts for the new jump.
+func (order *jumpOrder) lineNumber(networkPolicy *NPMNetworkPolicy) int {
+	switch networkPolicy.Tier {
+	case AdminTier:
+		lineNumber := 1
+		for _, policy := range order.adminPolicies {
+			if policy.Priority < networkPolicy.Priority || (policy.Priority == networkPolicy.Priority && policy.PolicyKey < networkPolicy.PolicyKey) {
+				lineNumber++
+			}
+		}
+		order.adminPolicies = append(order.adminPolicies, networkPolicy)
+		return lineNumber
+	case BaselineTier:
+		// right after the rule dropping on the drop mark
+		return len(order.adminPolicies) + order.numNamespacePolicies + order.numDropRules + 1
+	default:
+		if networkPolicy.AuditOnly {
+			// right after the jumps to the enforcing NamespaceTier policy chains
+			lineNumber := len(order.adminPolicies) + order.numNamespacePolicies - order.numAuditPolicies + order.numAddedAuditPolicies + 1
+			order.numAddedAuditPolicies++
+			order.numAuditPolicies++
+			order.numNamespacePolicies++
+			return lineNumber
+		}
+		lineNumber := len(order.adminPolicies) + order.numAddedNamespacePolicies + 1
+		order.numAddedNamespacePolicies++
+		order.numNamespacePolicies++
+		return lineNumber
+	}
+}
+
 // write rules for the policy chain(s)
-func writeNetworkPolicyRules(creator *ioutil.FileCreator, networkPolicy *NPMNetworkPolicy) {
+func writeNetworkPolicyRules(creator *ioutil.FileCreator, networkPolicy *NPMNetworkPolicy, flowLogging bool) {
+	if networkPolicy.Tier == AdminTier {
+		// skip the chain if a higher priority policy passed the traffic
+		hasIngress, hasEgress := networkPolicy.hasIngressAndEgress()
+		if hasIngress {
+			creator.AddLine("", nil, returnOnPassMarkSpecs(networkPolicy.ingressChainName(), util.IptablesAzureIngressPassMarkHex)...)
+		}
+		if hasEgress {
+			creator.AddLine("", nil, returnOnPassMarkSpecs(networkPolicy.egressChainName(), util.IptablesAzureEgressPassMarkHex)...)
+		}
+	}
+
 	for _, aclPolicy := range networkPolicy.ACLs {
 		var chainName string
 		var actionSpecs []string
+		var passMark string
 		if aclPolicy.hasIngress() {
 			chainName = networkPolicy.ingressChainName()
-			if aclPolicy.Target == Allowed {
+			passMark = util.IptablesAzureIngressPassMarkHex
+			switch {
+			case aclPolicy.Target == Allowed:
 				actionSpecs = []string{util.IptablesJumpFlag, util.IptablesAzureIngressAllowMarkChain}
-			} else {
+			case aclPolicy.Target == Passed:
+				actionSpecs = setMarkSpecs(passMark)
+			case networkPolicy.Tier == NamespaceTier:
 				actionSpecs = setMarkSpecs(util.IptablesAzureIngressDropMarkHex)
+			default:
+				// admin policies drop right away since no other policy can allow the traffic
+				actionSpecs = []string{util.IptablesJumpFlag, util.IptablesDrop}
 			}
 		} else {
 			chainName = networkPolicy.egressChainName()
-			if aclPolicy.Target == Allowed {
+			passMark = util.IptablesAzureEgressPassMarkHex
+			switch {
+			case aclPolicy.Target == Allowed:
 				actionSpecs = []string{util.IptablesJumpFlag, util.IptablesAzureAcceptChain}
-			} else {
+			case aclPolicy.Target == Passed:
+				actionSpecs = setMarkSpecs(passMark)
+			case networkPolicy.Tier == NamespaceTier:
 				actionSpecs = setMarkSpecs(util.IptablesAzureEgressDropMarkHex)
+			default:
+				// admin policies drop right away since no other policy can allow the traffic
+				actionSpecs = []string{util.IptablesJumpFlag, util.IptablesDrop}
 			}
 		}
+		comment := aclPolicy.comment()
+		if networkPolicy.AuditOnly {
+			// audit-only policies never decide, so the traffic continues to the next policy chain.
+			// The packet counters of the AUDIT rules count the traffic the policy would have dropped.
+			actionSpecs = []string{util.IptablesJumpFlag, util.IptablesReturn}
+			if aclPolicy.Target == Dropped {
+				comment = auditCommentPrefix + comment
+			}
+		}
+		if flowLogging && aclPolicy.Target == Dropped && networkPolicy.Tier != NamespaceTier && !networkPolicy.AuditOnly {
+			// AdminTier and BaselineTier policies drop right away, so log here.
+			// See writeFlowLogRules for NamespaceTier policies.
+			creator.AddLine("", nil, nflogBeforeDropSpecs(chainName, aclPolicy, comment)...)
+		}
 		line := []string{"-A", chainName}
 		line = append(line, actionSpecs...)
-		line = append(line, iptablesRuleSpecs(aclPolicy)...)
+		line = append(line, iptablesRuleSpecs(aclPolicy, comment)...)
 		creator.AddLine("", nil, line...) // TODO add error handler
+
+		if aclPolicy.Target == Passed {
+			// the remaining rules of the chain are skipped too
+			creator.AddLine("", nil, returnOnPassMarkSpecs(chainName, passMark)...)
+		}
 	}
 }
 
-func iptablesRuleSpecs(aclPolicy *ACLPolicy) []string {
+func returnOnPassMarkSpecs(chainName, passMark string) []string {
+	specs := []string{util.IptablesAppendFlag, chainName, util.IptablesJumpFlag, util.IptablesReturn}
+	specs = append(specs, onMarkSpecs(passMark)...)
+	return append(specs, commentSpecs(fmt.Sprintf("RETURN-ON-PASS-MARK-%s", passMark))...)
+}
+
+func iptablesRuleSpecs(aclPolicy *ACLPolicy, comment string) []string {
 	specs := make([]string, 0)
 	if aclPolicy.Protocol != UnspecifiedProtocol {
 		specs = append(specs, util.IptablesProtFlag, string(aclPolicy.Protocol))
@@ -279,7 +425,7 @@ func iptablesRuleSpecs(aclPolicy *ACLPolicy) []string {
 	specs = append(specs, dstPortSpecs(aclPolicy.DstPorts)...)
 	specs = append(specs, matchSetSpecsFromSetInfo(aclPolicy.SrcList)...)
 	specs = append(specs, matchSetSpecsFromSetInfo(aclPolicy.DstList)...)
-	specs = append(specs, commentSpecs(aclPolicy.comment())...)
+	specs = append(specs, commentSpecs(comment)...)
 	return specs
 }
 
@@ -282,6 +282,88 @@ func TestCreatorForAddPolicies(t *testing.T) {
 	dptestutils.AssertEqualLines(t, expectedLines, actualLines)
 }
 
+func TestCreatorForAdminPolicies(t *testing.T) {
+	ioshim := common.NewMockIOShim(nil)
+	defer ioshim.VerifyCalls(t, nil)
+	pMgr := NewPolicyManager(ioshim, ipsetConfig)
+
+	passedACL := &ACLPolicy{
+		SrcList:   []SetInfo{{ipsets.TestCIDRSet.Metadata, true, SrcMatch}},
+		Target:    Passed,
+		Direction: Ingress,
+		Protocol:  UnspecifiedProtocol,
+	}
+	lowPriorityPolicy := &NPMNetworkPolicy{
+		PolicyKey: "AdminNetworkPolicy/low",
+		Tier:      AdminTier,
+		Priority:  20,
+		ACLs:      []*ACLPolicy{ingressDeniedACL},
+	}
+	highPriorityPolicy := &NPMNetworkPolicy{
+		PolicyKey: "AdminNetworkPolicy/high",
+		Tier:      AdminTier,
+		Priority:  10,
+		PodSelectorIPSets: []*ipsets.TranslatedIPSet{
+			{Metadata: ipsets.TestNSSet.Metadata},
+		},
+		PodSelectorList: []SetInfo{{ipsets.TestNSSet.Metadata, true, EitherMatch}},
+		ACLs:            []*ACLPolicy{passedACL, ingressAllowedACL},
+	}
+	baselinePolicy := &NPMNetworkPolicy{
+		PolicyKey: "BaselineAdminNetworkPolicy/default",
+		Tier:      BaselineTier,
+		ACLs:      []*ACLPolicy{ingressDeniedACL, egressDeniedACL},
+	}
+
+	policies := []*NPMNetworkPolicy{lowPriorityPolicy, ingressNetPol, highPriorityPolicy, baselinePolicy}
+	creator := pMgr.creatorForNewNetworkPolicies(chainNames(policies), policies)
+	actualLines := strings.Split(creator.ToString(), "\n")
+	returnOnIngressPassMark := "-j RETURN -m mark --mark 0x100/0x100 -m comment --comment RETURN-ON-PASS-MARK-0x100/0x100"
+	expectedLines := []string{
+		"*filter",
+		// all chains
+		fmt.Sprintf(":%s - -", lowPriorityPolicy.ingressChainName()),
+		fmt.Sprintf(":%s - -", ingressNetPolChain),
+		fmt.Sprintf(":%s - -", highPriorityPolicy.ingressChainName()),
+		fmt.Sprintf(":%s - -", baselinePolicy.ingressChainName()),
+		fmt.Sprintf(":%s - -", baselinePolicy.egressChainName()),
+		"-F AZURE-NPM",
+		// activation rules for AZURE-NPM chain
+		"-A AZURE-NPM -j AZURE-NPM-INGRESS",
+		"-A AZURE-NPM -j AZURE-NPM-EGRESS",
+		"-A AZURE-NPM -j AZURE-NPM-ACCEPT",
+		// low priority admin policy drops right away
+		fmt.Sprintf("-A %s %s", lowPriorityPolicy.ingressChainName(), returnOnIngressPassMark),
+		fmt.Sprintf("-A %s %s", lowPriorityPolicy.ingressChainName(), strings.Replace(ingressDropRule, "-j MARK --set-mark "+util.IptablesAzureIngressDropMarkHex, "-j DROP", 1)),
+		fmt.Sprintf("-I AZURE-NPM-INGRESS 1 -j %s -m comment --comment INGRESS-POLICY-AdminNetworkPolicy/low-TO-all", lowPriorityPolicy.ingressChainName()),
+		// network policy goes after the admin policies
+		fmt.Sprintf("-A %s %s", ingressNetPolChain, ingressDropRule),
+		fmt.Sprintf("-I AZURE-NPM-INGRESS 2 %s", ingressNetPolJump),
+		// high priority admin policy goes before the low priority one
+		fmt.Sprintf("-A %s %s", highPriorityPolicy.ingressChainName(), returnOnIngressPassMark),
+		fmt.Sprintf(
+			"-A %s -j MARK --set-mark 0x100/0x100 -m set --match-set %s src -m comment --comment PASS-FROM-cidr-test-cidr-set",
+			highPriorityPolicy.ingressChainName(),
+			ipsets.TestCIDRSet.HashedName,
+		),
+		fmt.Sprintf("-A %s %s", highPriorityPolicy.ingressChainName(), returnOnIngressPassMark),
+		fmt.Sprintf("-A %s %s", highPriorityPolicy.ingressChainName(), ingressAllowRule),
+		fmt.Sprintf(
+			"-I AZURE-NPM-INGRESS 1 -j %s -m set --match-set %s dst -m comment --comment INGRESS-POLICY-AdminNetworkPolicy/high-TO-ns-test-ns-set",
+			highPriorityPolicy.ingressChainName(),
+			ipsets.TestNSSet.HashedName,
+		),
+		// baseline policy goes after the rule dropping on the drop mark
+		fmt.Sprintf("-A %s %s", baselinePolicy.ingressChainName(), strings.Replace(ingressDropRule, "-j MARK --set-mark "+util.IptablesAzureIngressDropMarkHex, "-j DROP", 1)),
+		fmt.Sprintf("-A %s %s", baselinePolicy.egressChainName(), strings.Replace(egressDropRule, "-j MARK --set-mark "+util.IptablesAzureEgressDropMarkHex, "-j DROP", 1)),
+		fmt.Sprintf("-I AZURE-NPM-INGRESS 5 -j %s -m comment --comment INGRESS-POLICY-BaselineAdminNetworkPolicy/default-TO-all", baselinePolicy.ingressChainName()),
+		fmt.Sprintf("-I AZURE-NPM-EGRESS 2 -j %s -m comment --comment EGRESS-POLICY-BaselineAdminNetworkPolicy/default-FROM-all", baselinePolicy.egressChainName()),
+		"COMMIT",
+		"",
+	}
+	dptestutils.AssertEqualLines(t, expectedLines, actualLines)
+}
+
 func TestCreatorForRemovePolicies(t *testing.T) {
 	calls := []testutils.TestCmd{fakeIPTablesRestoreCommand}
 	ioshim := common.NewMockIOShim(calls)
@@ -208,9 +208,11 @@ func TestRemoveNonexistentPolicy(t *testing.T) {
 
 func TestNormalizeAndValidatePolicy(t *testing.T) {
 	tests := []struct {
-		name    string
-		acl     *ACLPolicy
-		wantErr bool
+		name      string
+		tier      Tier
+		auditOnly bool
+		acl       *ACLPolicy
+		wantErr   bool
 	}{
 		{
 			name: "valid policy",
@@ -229,6 +231,53 @@ func TestNormalizeAndValidatePolicy(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "pass outside of admin tier",
+			acl: &ACLPolicy{
+				Target:    Passed,
+				Direction: Ingress,
+			},
+			wantErr: true,
+		},
+		{
+			name: "pass in admin tier",
+			tier: AdminTier,
+			acl: &ACLPolicy{
+				Target:    Passed,
+				Direction: Ingress,
+			},
+			// admin tiers are unsupported on windows
+			wantErr: util.IsWindowsDP(),
+		},
+		{
+			name: "unknown tier",
+			tier: "invalid",
+			acl: &ACLPolicy{
+				Target:    Dropped,
+				Direction: Ingress,
+			},
+			wantErr: true,
+		},
+		{
+			name:      "audit-only policy",
+			auditOnly: true,
+			acl: &ACLPolicy{
+				Target:    Dropped,
+				Direction: Ingress,
+			},
+			// audit-only policies are unsupported on windows
+			wantErr: util.IsWindowsDP(),
+		},
+		{
+			name:      "audit-only policy in admin tier",
+			tier:      AdminTier,
+			auditOnly: true,
+			acl: &ACLPolicy{
+				Target:    Dropped,
+				Direction: Ingress,
+			},
+			wantErr: true,
+		},
 		// TODO add other invalid cases
 	}
 	for _, tt := range tests {
@@ -239,6 +288,8 @@ func TestNormalizeAndValidatePolicy(t *testing.T) {
 				PolicyKey:   "x/test-netpol",
 				ACLPolicyID: "azure-acl-x-test-netpol",
 				ACLs:        []*ACLPolicy{tt.acl},
+				Tier:        tt.tier,
+				AuditOnly:   tt.auditOnly,
 			}
 			NormalizePolicy(netPol)
 			err := ValidatePolicy(netPol)
@@ -7,6 +7,7 @@ import (
 	controllersv2 "github.com/Azure/azure-container-networking/npm/pkg/controlplane/controllers/v2"
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/dynamic/dynamicinformer"
 	"k8s.io/client-go/informers"
 	coreinformers "k8s.io/client-go/informers/core/v1"
 	networkinginformers "k8s.io/client-go/informers/networking/v1"
@@ -34,6 +35,8 @@ type K8SControllersV2 struct {
 	NamespaceControllerV2 *controllersv2.NamespaceController     //nolint:structcheck // false lint error
 	NpmNamespaceCacheV2   *controllersv2.NpmNamespaceCache       //nolint:structcheck // false lint error
 	NetPolControllerV2    *controllersv2.NetworkPolicyController //nolint:structcheck // false lint error
+	// AdminNetPolControllerV2 is nil unless admin network policies are enabled.
+	AdminNetPolControllerV2 *controllersv2.AdminNetworkPolicyController //nolint:structcheck // false lint error
 }
 
 // Informers are the informers for the k8s controllers
@@ -42,6 +45,11 @@ type Informers struct {
 	PodInformer     coreinformers.PodInformer                 //nolint:structcheck // false lint error
 	NsInformer      coreinformers.NamespaceInformer           //nolint:structcheck // false lint error
 	NpInformer      networkinginformers.NetworkPolicyInformer //nolint:structcheck // false lint error
+	// DynamicInformerFactory watches the AdminNetworkPolicy and BaselineAdminNetworkPolicy CRDs.
+	// It is nil unless admin network policies are enabled.
+	DynamicInformerFactory dynamicinformer.DynamicSharedInformerFactory //nolint:structcheck // false lint error
+	AnpInformer            informers.GenericInformer                    //nolint:structcheck // false lint error
+	BanpInformer           informers.GenericInformer                    //nolint:structcheck // false lint error
 }
 
 // AzureConfig captures the Azure specific configurations and fields
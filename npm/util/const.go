@@ -93,12 +93,19 @@ const (
 	IptablesFilterTable        string = "filter"
 	IptablesCommentModuleFlag  string = "comment"
 	IptablesCommentFlag        string = "--comment"
+	IptablesNFLogTarget        string = "NFLOG"
+	IptablesNFLogGroupFlag     string = "--nflog-group"
+	IptablesNFLogPrefixFlag    string = "--nflog-prefix"
+	IptablesLimitModuleFlag    string = "limit"
+	IptablesLimitFlag          string = "--limit"
 	IptablesAddCommentFlag
 
 	IptablesTableFlag       string = "-t"
 	IptablesListFlag        string = "-L"
 	IptablesNumericFlag     string = "-n"
 	IptablesLineNumbersFlag string = "--line-numbers"
+	IptablesVerboseFlag     string = "-v"
+	IptablesExactFlag       string = "-x"
 
 	IptablesKubeServicesChain          string = "KUBE-SERVICES"
 	IptablesForwardChain               string = "FORWARD"
@@ -121,6 +128,9 @@ const (
 	// NPM v2 Chains
 	IptablesAzureIngressPolicyChainPrefix string = "AZURE-NPM-INGRESS"
 	IptablesAzureEgressPolicyChainPrefix  string = "AZURE-NPM-EGRESS"
+	// NPM v2 Chains for flow logs
+	IptablesAzureIngressFlowLogChain string = "AZURE-NPM-INGRESS-FLOWLOG"
+	IptablesAzureEgressFlowLogChain  string = "AZURE-NPM-EGRESS-FLOWLOG"
 
 	// Below chain exists only in NPM before v1.2.6
 	IptablesAzureTargetSetsChain string = "AZURE-NPM-TARGET-SETS"
@@ -146,6 +156,14 @@ const (
 	IptablesAzureIngressAllowMarkHex string = "0x200/0x200"
 	IptablesAzureIngressDropMarkHex  string = "0x400/0x400"
 	IptablesAzureEgressDropMarkHex   string = "0x800/0x800"
+	// the pass marks (8th and 12th bit) skip the remaining AdminNetworkPolicy chains.
+	// v1 uses the 12th bit too, but v1 and v2 never program the same node.
+	IptablesAzureIngressPassMarkHex string = "0x100/0x100"
+	IptablesAzureEgressPassMarkHex  string = "0x1000/0x1000"
+
+	// NPM v2 logs the connections denied by policies to this NFLOG group, at this rate per policy and direction.
+	IptablesAzureNFLogGroup  uint16 = 1023
+	IptablesAzureFlowLogRate string = "10/second"
 
 	// marks in NPM v1
 	IptablesAzureIngressMarkHex string = "0x2000"
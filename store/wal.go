@@ -0,0 +1,518 @@
+// Copyright 2026 Microsoft. All rights reserved.
+// MIT License
+
+package store
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-container-networking/log"
+	"github.com/Azure/azure-container-networking/platform"
+	"github.com/Azure/azure-container-networking/processlock"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	// WALExtension - Extension added to the snapshot file name for the write-ahead log.
+	WALExtension = ".wal"
+
+	// DefaultWALCompactionSize - size in bytes after which the write-ahead log is folded into the snapshot.
+	DefaultWALCompactionSize = 4 * 1024 * 1024
+	// DefaultWALCompactionRecords - number of records after which the write-ahead log is folded into the snapshot.
+	DefaultWALCompactionRecords = 1024
+
+	// walHeaderSize is the size of the record header: payload length followed by the payload checksum.
+	walHeaderSize = 8
+	// walMaxRecordSize bounds a single record so a corrupt length field can't trigger a huge allocation.
+	walMaxRecordSize = 64 * 1024 * 1024
+)
+
+var (
+	walCRCTable = crc32.MakeTable(crc32.Castagnoli)
+
+	// ErrCorruptRecord is returned when a write-ahead log record fails validation.
+	ErrCorruptRecord = errors.New("corrupt write-ahead log record")
+)
+
+// walRecord is a single per-key update in the write-ahead log.
+type walRecord struct {
+	Key   string          `json:"key"`
+	Value json.RawMessage `json:"value"`
+}
+
+// walFileStore is an implementation of KeyValueStore backed by a JSON snapshot and an
+// append-only write-ahead log of checksummed per-key updates.
+//
+// Every Write appends a single record to the log and syncs it to disk, so an update is
+// either fully applied or not at all. On load the snapshot is read and the log is replayed
+// on top of it; a torn or corrupt tail left behind by a crash is truncated away. Flush
+// folds the log into the snapshot, and so does a Write once the log reaches the compaction
+// size or record count. The snapshot uses the same format as jsonFileStore, so an existing
+// state file is imported as-is on first start, and FoldWALFile brings the snapshot up to
+// date before switching back to jsonFileStore.
+type walFileStore struct {
+	fileName          string
+	walFileName       string
+	compactionSize    int64
+	compactionRecords int
+	data              map[string]*json.RawMessage
+	walSize           int64
+	walRecords        int
+	inSync            bool
+	processLock    processlock.Interface
+	sync.Mutex
+	logger *zap.Logger
+}
+
+// NewWALFileStore creates a new walFileStore object, accessed as a KeyValueStore.
+// fileName is the snapshot file; the write-ahead log is kept next to it with WALExtension appended.
+func NewWALFileStore(fileName string, lockclient processlock.Interface, logger *zap.Logger) (KeyValueStore, error) {
+	if fileName == "" {
+		return &walFileStore{}, errors.New("need to pass in a snapshot file path")
+	}
+	kvs := &walFileStore{
+		fileName:          fileName,
+		walFileName:       fileName + WALExtension,
+		compactionSize:    DefaultWALCompactionSize,
+		compactionRecords: DefaultWALCompactionRecords,
+		processLock:       lockclient,
+		data:              make(map[string]*json.RawMessage),
+		logger:            logger,
+	}
+
+	return kvs, nil
+}
+
+// FoldWALFile folds the write-ahead log left next to the snapshot file by a walFileStore into the
+// snapshot and removes the log, so that jsonFileStore sees every update after the WAL store is
+// turned off. It does nothing if there is no log. The caller must hold the store lock.
+func FoldWALFile(fileName string, logger *zap.Logger) error {
+	walFileName := fileName + WALExtension
+	if _, err := os.Stat(walFileName); err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return errors.Wrap(err, "failed to stat write-ahead log")
+	}
+
+	kvs := &walFileStore{
+		fileName:    fileName,
+		walFileName: walFileName,
+		data:        make(map[string]*json.RawMessage),
+		logger:      logger,
+	}
+	if _, err := kvs.load(); err != nil {
+		return err
+	}
+	if kvs.walSize > 0 {
+		if err := kvs.compact(); err != nil {
+			return err
+		}
+	}
+	if err := os.Remove(walFileName); err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "failed to remove write-ahead log")
+	}
+
+	return nil
+}
+
+func (kvs *walFileStore) Exists() bool {
+	if _, err := os.Stat(kvs.fileName); err == nil {
+		return true
+	}
+	if _, err := os.Stat(kvs.walFileName); err == nil {
+		return true
+	}
+	return false
+}
+
+// Read restores the value for the given key from persistent store.
+func (kvs *walFileStore) Read(key string, value interface{}) error {
+	kvs.Mutex.Lock()
+	defer kvs.Mutex.Unlock()
+
+	if !kvs.inSync {
+		found, err := kvs.load()
+		if err != nil {
+			return err
+		}
+		if !found {
+			return ErrKeyNotFound
+		}
+	}
+
+	raw, ok := kvs.data[key]
+	if !ok {
+		return ErrKeyNotFound
+	}
+
+	return json.Unmarshal(*raw, value)
+}
+
+// Write atomically saves the given key value pair to persistent store.
+func (kvs *walFileStore) Write(key string, value interface{}) error {
+	kvs.Mutex.Lock()
+	defer kvs.Mutex.Unlock()
+
+	// Load existing state first so that a later compaction doesn't drop keys we haven't read yet.
+	if !kvs.inSync {
+		if _, err := kvs.load(); err != nil {
+			return err
+		}
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	if err := kvs.append(walRecord{Key: key, Value: raw}); err != nil {
+		return err
+	}
+
+	rawMsg := json.RawMessage(raw)
+	kvs.data[key] = &rawMsg
+
+	if kvs.walSize >= kvs.compactionSize || kvs.walRecords >= kvs.compactionRecords {
+		return kvs.compact()
+	}
+
+	return nil
+}
+
+// Flush folds the write-ahead log into the snapshot.
+func (kvs *walFileStore) Flush() error {
+	kvs.Mutex.Lock()
+	defer kvs.Mutex.Unlock()
+
+	if !kvs.inSync {
+		if _, err := kvs.load(); err != nil {
+			return err
+		}
+	}
+
+	return kvs.compact()
+}
+
+// load reads the snapshot and replays the write-ahead log on top of it.
+// It reports whether any persisted state was found.
+func (kvs *walFileStore) load() (bool, error) {
+	data := make(map[string]*json.RawMessage)
+
+	snapshotFound := true
+	b, err := os.ReadFile(kvs.fileName)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return false, errors.Wrap(err, "failed to read snapshot")
+		}
+		snapshotFound = false
+	}
+
+	if len(b) != 0 {
+		if err := json.Unmarshal(b, &data); err != nil {
+			return false, errors.Wrap(err, "failed to decode snapshot")
+		}
+	}
+
+	walFound, err := kvs.replay(data)
+	if err != nil {
+		return false, err
+	}
+
+	kvs.data = data
+	kvs.inSync = true
+
+	if snapshotFound && len(b) == 0 && !walFound {
+		// Like jsonFileStore, start over from an empty store so that the next write replaces the empty file.
+		kvs.logf("Snapshot file %s was empty, starting with an empty store", kvs.fileName)
+		return false, nil
+	}
+
+	return snapshotFound || walFound, nil
+}
+
+// replay applies every valid record in the write-ahead log to data, truncating the log at
+// the first torn or corrupt record. It reports whether a log file was found.
+func (kvs *walFileStore) replay(data map[string]*json.RawMessage) (bool, error) {
+	f, err := os.OpenFile(kvs.walFileName, os.O_RDWR, 0o664)
+	if err != nil {
+		if os.IsNotExist(err) {
+			kvs.walSize = 0
+			kvs.walRecords = 0
+			return false, nil
+		}
+		return false, errors.Wrap(err, "failed to open write-ahead log")
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	var offset int64
+	records := 0
+	for {
+		rec, n, err := readWALRecord(r)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			kvs.logf("Recovering write-ahead log %s: truncating at offset %d: %v", kvs.walFileName, offset, err)
+			if err := f.Truncate(offset); err != nil {
+				return true, errors.Wrap(err, "failed to truncate write-ahead log")
+			}
+			if err := f.Sync(); err != nil {
+				return true, errors.Wrap(err, "failed to sync write-ahead log")
+			}
+			break
+		}
+
+		value := rec.Value
+		data[rec.Key] = &value
+		offset += n
+		records++
+	}
+
+	kvs.walSize = offset
+	kvs.walRecords = records
+
+	return true, nil
+}
+
+// append writes a record to the end of the write-ahead log and syncs it to disk.
+func (kvs *walFileStore) append(rec walRecord) error {
+	buf, err := encodeWALRecord(rec)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(kvs.walFileName, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o664)
+	if err != nil {
+		return errors.Wrap(err, "failed to open write-ahead log")
+	}
+	defer f.Close()
+
+	if _, err := f.Write(buf); err != nil {
+		return errors.Wrap(err, "failed to append to write-ahead log")
+	}
+
+	if err := f.Sync(); err != nil {
+		return errors.Wrap(err, "failed to sync write-ahead log")
+	}
+
+	kvs.walSize += int64(len(buf))
+	kvs.walRecords++
+
+	return nil
+}
+
+// compact atomically replaces the snapshot with the in-memory state and empties the log.
+// A crash between the two steps is harmless since replaying the log onto the new snapshot
+// yields the same state.
+func (kvs *walFileStore) compact() error {
+	buf, err := json.MarshalIndent(&kvs.data, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	dir, file := filepath.Split(kvs.fileName)
+	if dir == "" {
+		dir = "."
+	}
+
+	f, err := os.CreateTemp(dir, file)
+	if err != nil {
+		return errors.Wrap(err, "cannot create temp file")
+	}
+
+	tmpFileName := f.Name()
+
+	defer func() {
+		if err != nil {
+			_ = os.Remove(tmpFileName)
+			f.Close()
+		}
+	}()
+
+	if _, err = f.Write(buf); err != nil {
+		return errors.Wrap(err, "temp file write failed")
+	}
+
+	if err = f.Sync(); err != nil {
+		return errors.Wrap(err, "temp file sync failed")
+	}
+
+	if err = f.Close(); err != nil {
+		return errors.Wrap(err, "temp file close failed")
+	}
+
+	if err = platform.ReplaceFile(tmpFileName, kvs.fileName); err != nil {
+		return errors.Wrap(err, "rename temp file to snapshot file failed")
+	}
+
+	if err = os.Truncate(kvs.walFileName, 0); err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "failed to truncate write-ahead log")
+	}
+
+	kvs.walSize = 0
+	kvs.walRecords = 0
+
+	return nil
+}
+
+func (kvs *walFileStore) lockUtil(status chan error) {
+	err := kvs.processLock.Lock()
+	status <- err
+}
+
+// Lock locks the store for exclusive access.
+func (kvs *walFileStore) Lock(timeout time.Duration) error {
+	kvs.Mutex.Lock()
+	defer kvs.Mutex.Unlock()
+
+	afterTime := time.After(timeout)
+	status := make(chan error)
+
+	kvs.logf("Acquiring process lock")
+
+	go kvs.lockUtil(status)
+
+	var err error
+	select {
+	case <-afterTime:
+		return ErrTimeoutLockingStore
+	case err = <-status:
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "processLock acquire error")
+	}
+
+	// Another process may have written to the store while we were not holding the lock.
+	kvs.inSync = false
+
+	kvs.logf("Acquired process lock with timeout value of %v", timeout)
+
+	return nil
+}
+
+// Unlock unlocks the store.
+func (kvs *walFileStore) Unlock() error {
+	kvs.Mutex.Lock()
+	defer kvs.Mutex.Unlock()
+
+	err := kvs.processLock.Unlock()
+	if err != nil {
+		return errors.Wrap(err, "unlock error")
+	}
+
+	kvs.logf("Released process lock")
+
+	return nil
+}
+
+// GetModificationTime returns the modification time of the persistent store.
+func (kvs *walFileStore) GetModificationTime() (time.Time, error) {
+	kvs.Mutex.Lock()
+	defer kvs.Mutex.Unlock()
+
+	var modTime time.Time
+	var statErr error
+	for _, name := range []string{kvs.fileName, kvs.walFileName} {
+		info, err := os.Stat(name)
+		if err != nil {
+			statErr = err
+			continue
+		}
+		if info.ModTime().After(modTime) {
+			modTime = info.ModTime()
+		}
+	}
+
+	if modTime.IsZero() {
+		kvs.logf("os.stat() for file %v failed: %v", kvs.fileName, statErr)
+		return time.Time{}.UTC(), statErr
+	}
+
+	return modTime.UTC(), nil
+}
+
+func (kvs *walFileStore) Remove() {
+	kvs.Mutex.Lock()
+	defer kvs.Mutex.Unlock()
+
+	for _, name := range []string{kvs.fileName, kvs.walFileName} {
+		if err := os.Remove(name); err != nil && !os.IsNotExist(err) {
+			log.Errorf("could not remove file %s. Error: %v", name, err)
+		}
+	}
+
+	kvs.data = make(map[string]*json.RawMessage)
+	kvs.walSize = 0
+	kvs.walRecords = 0
+	kvs.inSync = false
+}
+
+func (kvs *walFileStore) logf(format string, args ...interface{}) {
+	if kvs.logger != nil {
+		kvs.logger.Sugar().Infof(format, args...)
+	} else {
+		log.Printf(format, args...)
+	}
+}
+
+// encodeWALRecord frames a record as: payload length (uint32 LE), CRC32-C of payload (uint32 LE), payload.
+func encodeWALRecord(rec walRecord) ([]byte, error) {
+	payload, err := json.Marshal(rec)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode write-ahead log record")
+	}
+
+	buf := make([]byte, walHeaderSize+len(payload))
+	binary.LittleEndian.PutUint32(buf[0:4], uint32(len(payload)))
+	binary.LittleEndian.PutUint32(buf[4:8], crc32.Checksum(payload, walCRCTable))
+	copy(buf[walHeaderSize:], payload)
+
+	return buf, nil
+}
+
+// readWALRecord reads the next record and returns it along with its encoded size.
+// io.EOF is returned only at a clean record boundary.
+func readWALRecord(r io.Reader) (walRecord, int64, error) {
+	var rec walRecord
+
+	header := make([]byte, walHeaderSize)
+	if n, err := io.ReadFull(r, header); err != nil {
+		if errors.Is(err, io.EOF) && n == 0 {
+			return rec, 0, io.EOF
+		}
+		return rec, 0, errors.Wrap(ErrCorruptRecord, "short header")
+	}
+
+	length := binary.LittleEndian.Uint32(header[0:4])
+	checksum := binary.LittleEndian.Uint32(header[4:8])
+	if length == 0 || length > walMaxRecordSize {
+		return rec, 0, errors.Wrapf(ErrCorruptRecord, "invalid length %d", length)
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return rec, 0, errors.Wrap(ErrCorruptRecord, "short payload")
+	}
+
+	if crc32.Checksum(payload, walCRCTable) != checksum {
+		return rec, 0, errors.Wrap(ErrCorruptRecord, "checksum mismatch")
+	}
+
+	if err := json.Unmarshal(payload, &rec); err != nil {
+		return rec, 0, errors.Wrap(ErrCorruptRecord, err.Error())
+	}
+
+	return rec, int64(walHeaderSize) + int64(length), nil
+}
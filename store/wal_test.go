@@ -0,0 +1,220 @@
+// Copyright 2026 Microsoft. All rights reserved.
+// MIT License
+
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Azure/azure-container-networking/processlock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestWALStore(t *testing.T) (*walFileStore, string) {
+	t.Helper()
+	fileName := filepath.Join(t.TempDir(), "azure-vnet.json")
+	kvs, err := NewWALFileStore(fileName, processlock.NewMockFileLock(false), nil)
+	require.NoError(t, err)
+	return kvs.(*walFileStore), fileName
+}
+
+func reopenWALStore(t *testing.T, fileName string) KeyValueStore {
+	t.Helper()
+	kvs, err := NewWALFileStore(fileName, processlock.NewMockFileLock(false), nil)
+	require.NoError(t, err)
+	return kvs
+}
+
+func TestWALStoreWriteAndReadBack(t *testing.T) {
+	kvs, fileName := newTestWALStore(t)
+
+	require.False(t, kvs.Exists())
+	require.ErrorIs(t, kvs.Read(testKey1, &testType1{}), ErrKeyNotFound)
+
+	require.NoError(t, kvs.Write(testKey1, &testType1{"test", 42}))
+	require.NoError(t, kvs.Write(testKey2, &testType1{"any", 14}))
+	require.NoError(t, kvs.Write(testKey1, &testType1{"updated", 43}))
+	require.True(t, kvs.Exists())
+
+	// Nothing is in the snapshot yet, everything lives in the log.
+	_, err := os.Stat(fileName)
+	require.True(t, os.IsNotExist(err))
+
+	var v testType1
+	other := reopenWALStore(t, fileName)
+	require.NoError(t, other.Read(testKey1, &v))
+	require.Equal(t, testType1{"updated", 43}, v)
+	require.NoError(t, other.Read(testKey2, &v))
+	require.Equal(t, testType1{"any", 14}, v)
+}
+
+func TestWALStoreFlushCompactsLog(t *testing.T) {
+	kvs, fileName := newTestWALStore(t)
+
+	require.NoError(t, kvs.Write(testKey1, &testType1{"test", 42}))
+	require.NoError(t, kvs.Flush())
+
+	info, err := os.Stat(fileName + WALExtension)
+	require.NoError(t, err)
+	require.Zero(t, info.Size())
+
+	// The snapshot must stay readable by the plain JSON store.
+	var v testType1
+	jsonStore, err := NewJsonFileStore(fileName, processlock.NewMockFileLock(false), nil)
+	require.NoError(t, err)
+	require.NoError(t, jsonStore.Read(testKey1, &v))
+	require.Equal(t, testType1{"test", 42}, v)
+}
+
+func TestWALStoreCompactsAutomatically(t *testing.T) {
+	kvs, fileName := newTestWALStore(t)
+	kvs.compactionSize = 1
+
+	require.NoError(t, kvs.Write(testKey1, &testType1{"test", 42}))
+	require.Zero(t, kvs.walSize)
+
+	var v testType1
+	require.NoError(t, reopenWALStore(t, fileName).Read(testKey1, &v))
+	require.Equal(t, testType1{"test", 42}, v)
+}
+
+func TestWALStoreImportsExistingJSONFile(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "azure-vnet.json")
+	require.NoError(t, os.WriteFile(fileName, []byte(`{"key1":{"Field1":"test","Field2":42}}`), 0o600))
+
+	kvs := reopenWALStore(t, fileName)
+	require.NoError(t, kvs.Write(testKey2, &testType1{"any", 14}))
+
+	var v testType1
+	other := reopenWALStore(t, fileName)
+	require.NoError(t, other.Read(testKey1, &v))
+	require.Equal(t, testType1{"test", 42}, v)
+	require.NoError(t, other.Read(testKey2, &v))
+	require.Equal(t, testType1{"any", 14}, v)
+}
+
+func TestWALStoreRecoversFromTornWrite(t *testing.T) {
+	tests := []struct {
+		name    string
+		corrupt func([]byte) []byte
+	}{
+		{
+			name:    "truncated payload",
+			corrupt: func(b []byte) []byte { return b[:len(b)-3] },
+		},
+		{
+			name:    "truncated header",
+			corrupt: func(b []byte) []byte { return b[:walHeaderSize-2] },
+		},
+		{
+			name: "checksum mismatch",
+			corrupt: func(b []byte) []byte {
+				b[len(b)-2] ^= 0xff
+				return b
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			kvs, fileName := newTestWALStore(t)
+			require.NoError(t, kvs.Write(testKey1, &testType1{"test", 42}))
+			goodSize := kvs.walSize
+
+			// Simulate a crash in the middle of appending the second record.
+			rec, err := encodeWALRecord(walRecord{Key: testKey2, Value: []byte(`{"Field1":"lost","Field2":1}`)})
+			require.NoError(t, err)
+			f, err := os.OpenFile(fileName+WALExtension, os.O_WRONLY|os.O_APPEND, 0o600)
+			require.NoError(t, err)
+			_, err = f.Write(tt.corrupt(rec))
+			require.NoError(t, err)
+			require.NoError(t, f.Close())
+
+			var v testType1
+			other := reopenWALStore(t, fileName)
+			require.NoError(t, other.Read(testKey1, &v))
+			require.Equal(t, testType1{"test", 42}, v)
+			require.ErrorIs(t, other.Read(testKey2, &v), ErrKeyNotFound)
+
+			info, err := os.Stat(fileName + WALExtension)
+			require.NoError(t, err)
+			require.Equal(t, goodSize, info.Size())
+
+			// The log is usable again after recovery.
+			require.NoError(t, other.Write(testKey2, &testType1{"any", 14}))
+			require.NoError(t, reopenWALStore(t, fileName).Read(testKey2, &v))
+			require.Equal(t, testType1{"any", 14}, v)
+		})
+	}
+}
+
+func TestWALStoreCompactsAfterRecordCount(t *testing.T) {
+	kvs, _ := newTestWALStore(t)
+	kvs.compactionRecords = 2
+
+	// Neither unlocking nor reloading the store compacts the log.
+	require.NoError(t, kvs.Lock(DefaultLockTimeoutLinux))
+	require.NoError(t, kvs.Write(testKey1, &testType1{"test", 42}))
+	require.NoError(t, kvs.Unlock())
+	require.NoError(t, kvs.Lock(DefaultLockTimeoutLinux))
+	require.NoError(t, kvs.Read(testKey1, &testType1{}))
+	require.Equal(t, 1, kvs.walRecords)
+	require.NotZero(t, kvs.walSize)
+
+	require.NoError(t, kvs.Write(testKey2, &testType1{"any", 14}))
+	require.NoError(t, kvs.Unlock())
+	require.Zero(t, kvs.walRecords)
+	require.Zero(t, kvs.walSize)
+}
+
+func TestWALStoreEmptySnapshot(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "azure-vnet.json")
+	require.NoError(t, os.WriteFile(fileName, nil, 0o600))
+
+	// A truncated snapshot without a log is an empty store which accepts writes.
+	kvs := reopenWALStore(t, fileName)
+	require.ErrorIs(t, kvs.Read(testKey1, &testType1{}), ErrKeyNotFound)
+	require.NoError(t, kvs.Write(testKey1, &testType1{"test", 42}))
+	require.NoError(t, kvs.Flush())
+
+	var v testType1
+	require.NoError(t, reopenWALStore(t, fileName).Read(testKey1, &v))
+	require.Equal(t, testType1{"test", 42}, v)
+}
+
+func TestFoldWALFile(t *testing.T) {
+	kvs, fileName := newTestWALStore(t)
+	require.NoError(t, kvs.Write(testKey1, &testType1{"test", 42}))
+	require.NoError(t, kvs.Flush())
+	require.NoError(t, kvs.Write(testKey2, &testType1{"any", 14}))
+
+	// Switching back to the plain JSON store doesn't lose the update in the log.
+	require.NoError(t, FoldWALFile(fileName, nil))
+	_, err := os.Stat(fileName + WALExtension)
+	require.True(t, os.IsNotExist(err))
+
+	var v testType1
+	jsonStore, err := NewJsonFileStore(fileName, processlock.NewMockFileLock(false), nil)
+	require.NoError(t, err)
+	require.NoError(t, jsonStore.Read(testKey1, &v))
+	require.Equal(t, testType1{"test", 42}, v)
+	require.NoError(t, jsonStore.Read(testKey2, &v))
+	require.Equal(t, testType1{"any", 14}, v)
+
+	// There is nothing to do without a log.
+	require.NoError(t, FoldWALFile(fileName, nil))
+}
+
+func TestWALStoreRemove(t *testing.T) {
+	kvs, fileName := newTestWALStore(t)
+	require.NoError(t, kvs.Write(testKey1, &testType1{"test", 42}))
+	require.NoError(t, kvs.Flush())
+	require.NoError(t, kvs.Write(testKey2, &testType1{"any", 14}))
+
+	kvs.Remove()
+	require.False(t, kvs.Exists())
+	require.ErrorIs(t, reopenWALStore(t, fileName).Read(testKey1, &testType1{}), ErrKeyNotFound)
+}